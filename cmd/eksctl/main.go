@@ -26,6 +26,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ctl/update"
 	"github.com/weaveworks/eksctl/pkg/ctl/upgrade"
 	"github.com/weaveworks/eksctl/pkg/ctl/utils"
+	"github.com/weaveworks/eksctl/pkg/metrics"
 )
 
 func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
@@ -91,15 +92,32 @@ func main() {
 
 	dumpLogsValue := rootCmd.PersistentFlags().BoolP("dumpLogs", "d", false, "dump logs to disk on failure if set to true")
 
+	metricsAddr := rootCmd.PersistentFlags().String("metrics-addr", "", "serve Prometheus metrics on /metrics at the given address (e.g. :9090) while the command runs; disabled by default")
+
 	logBuffer := new(bytes.Buffer)
 
+	var metricsServer *metrics.Server
 	cobra.OnInitialize(func() {
 		initLogger(*loggerLevel, *colorValue, logBuffer, *dumpLogsValue)
+
+		if *metricsAddr != "" {
+			var err error
+			if metricsServer, err = metrics.Serve(*metricsAddr); err != nil {
+				logger.Critical(err.Error())
+				os.Exit(1)
+			}
+		}
 	})
 
 	rootCmd.SetUsageFunc(flagGrouping.Usage)
 
-	if err := rootCmd.Execute(); err != nil {
+	err = rootCmd.Execute()
+	if metricsServer != nil {
+		if closeErr := metricsServer.Close(); closeErr != nil {
+			logger.Debug("error closing the metrics server: %v", closeErr)
+		}
+	}
+	if err != nil {
 
 		if *dumpLogsValue {
 			if dumpErr := dumpLogsToDisk(logBuffer, err.Error()); dumpErr != nil {
@@ -195,10 +195,11 @@ func (a *Manager) getRecommendedPolicies(addon *api.Addon) (api.InlineDocument,
 	// API isn't case sensitive
 	switch addon.CanonicalName() {
 	case vpcCNIName:
+		partition := api.Partition(a.clusterConfig.Metadata.Region)
 		if a.clusterConfig.IPv6Enabled() {
-			return makeIPv6VPCCNIPolicyDocument(), nil, nil
+			return makeIPv6VPCCNIPolicyDocument(partition), nil, nil
 		}
-		return nil, []string{fmt.Sprintf("arn:%s:iam::aws:policy/%s", api.Partition(a.clusterConfig.Metadata.Region), api.IAMPolicyAmazonEKSCNIPolicy)}, nil
+		return nil, []string{fmt.Sprintf("arn:%s:iam::aws:policy/%s", partition, api.IAMPolicyAmazonEKSCNIPolicy)}, nil
 	case ebsCSIDriverName:
 		return nil, nil, &api.WellKnownPolicies{
 			EBSCSIController: true,
@@ -267,7 +268,7 @@ func (a *Manager) createStack(resourceSet builder.ResourceSetReader, addon *api.
 	return <-errChan
 }
 
-func makeIPv6VPCCNIPolicyDocument() map[string]interface{} {
+func makeIPv6VPCCNIPolicyDocument(partition string) map[string]interface{} {
 	return map[string]interface{}{
 		"Version": "2012-10-17",
 		"Statement": []map[string]interface{}{
@@ -287,7 +288,7 @@ func makeIPv6VPCCNIPolicyDocument() map[string]interface{} {
 				"Action": []string{
 					"ec2:CreateTags",
 				},
-				"Resource": "arn:aws:ec2:*:*:network-interface/*",
+				"Resource": fmt.Sprintf("arn:%s:ec2:*:*:network-interface/*", partition),
 			},
 		},
 	}
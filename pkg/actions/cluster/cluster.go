@@ -13,11 +13,33 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
 )
 
 type Cluster interface {
-	Upgrade(ctx context.Context, dryRun bool) error
-	Delete(ctx context.Context, waitInterval time.Duration, wait, force, disableNodegroupEviction bool, parallel int) error
+	Upgrade(ctx context.Context, dryRun bool, planHash string) error
+	Delete(ctx context.Context, options DeleteOptions) error
+}
+
+// DeleteOptions contains options to configure the deletion of a cluster
+type DeleteOptions struct {
+	// WaitInterval is the interval at which the deletion of nodegroups without a stack is polled
+	WaitInterval time.Duration
+	// Wait for the deletion of all resources to finish
+	Wait bool
+	// Force the deletion to continue when errors occur
+	Force bool
+	// DisableNodegroupEviction drains nodes using delete, even if eviction is supported
+	DisableNodegroupEviction bool
+	// ContinueAfterDeprecatedStacks continues deleting the remaining shared resources after deleting deprecated stacks
+	ContinueAfterDeprecatedStacks bool
+	// SkipOrphanedNetworkCleanup skips deleting the network resources left behind in the VPC of the cluster
+	SkipOrphanedNetworkCleanup bool
+	// Parallel is the number of nodes to drain in parallel
+	Parallel int
+	// CheckNodeReachability warns about the nodes whose security groups do not allow the control plane to reach
+	// their kubelet before draining them
+	CheckNodeReachability bool
 }
 
 func New(cfg *api.ClusterConfig, ctl *eks.ClusterProvider) (Cluster, error) {
@@ -50,3 +72,14 @@ func New(cfg *api.ClusterConfig, ctl *eks.ClusterProvider) (Cluster, error) {
 
 	return NewUnownedCluster(cfg, ctl, stackManager), nil
 }
+
+// checkOperability checks whether Kubernetes API operations can be performed on the cluster, logging why not along
+// with how to resolve it, and the missing RBAC permissions
+func checkOperability(ctx context.Context, cfg *api.ClusterConfig, ctl *eks.ClusterProvider, newClientSet func() (kubernetes.Interface, error)) bool {
+	report := ctl.CheckOperability(ctx, cfg, newClientSet)
+	logger.Info("%s", report)
+	for _, warning := range report.Warnings {
+		logger.Warning("%s", warning)
+	}
+	return report.Operable()
+}
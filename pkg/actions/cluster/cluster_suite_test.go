@@ -3,11 +3,63 @@ package cluster_test
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 )
 
 func TestCluster(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Cluster Suite")
 }
+
+// newOperableClientSet returns a fake clientset that allows all SelfSubjectAccessReviews, so that the cluster is
+// considered operable
+func newOperableClientSet() *fake.Clientset {
+	clientSet := fake.NewSimpleClientset()
+	clientSet.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	return clientSet
+}
+
+// newUnauthorizedClientSet returns a clientset whose identity is not granted any of the permissions needed to drain
+// nodegroups
+func newUnauthorizedClientSet() *fake.Clientset {
+	clientSet := fake.NewSimpleClientset()
+	clientSet.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = false
+		return true, review, nil
+	})
+	return clientSet
+}
+
+// isOrphanedNetworkResourcesFilter returns true for the filters used to find the network resources that the cluster
+// left behind in its VPC
+func isOrphanedNetworkResourcesFilter(filters []ec2types.Filter) bool {
+	return len(filters) >= 2 && aws.ToString(filters[0].Name) == "vpc-id" &&
+		aws.ToString(filters[1].Name) == "tag-key" && filters[1].Values[0] == "kubernetes.io/cluster/my-cluster"
+}
+
+// mockOrphanedNetworkResources mocks the detached network interfaces and security groups that the cluster left behind
+// in its VPC
+func mockOrphanedNetworkResources(p *mockprovider.MockProvider, networkInterfaces []ec2types.NetworkInterface, securityGroups []ec2types.SecurityGroup) {
+	p.MockEC2().On("DescribeNetworkInterfaces", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeNetworkInterfacesInput) bool {
+		return isOrphanedNetworkResourcesFilter(input.Filters)
+	})).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: networkInterfaces}, nil)
+	p.MockEC2().On("DescribeSecurityGroups", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+		return isOrphanedNetworkResourcesFilter(input.Filters)
+	})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: securityGroups}, nil)
+}
@@ -3,9 +3,12 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
@@ -20,24 +23,37 @@ import (
 
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/awsapi"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/elb"
 	ssh "github.com/weaveworks/eksctl/pkg/ssh/client"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+	"github.com/weaveworks/eksctl/pkg/vpc"
 
 	"github.com/kris-nova/logger"
 )
 
+// kubeletPort is the port the control plane reaches the kubelet of the nodes on, e.g. to evict pods
+const kubeletPort = 10250
+
 type NodeGroupDrainer interface {
 	Drain(input *nodegroup.DrainInput) error
 }
 type vpcCniDeleter func(clusterName string, ctl *eks.ClusterProvider, clientSet kubernetes.Interface)
 
-func deleteSharedResources(ctx context.Context, cfg *api.ClusterConfig, ctl *eks.ClusterProvider, stackManager manager.StackManager, clusterOperable bool, clientSet kubernetes.Interface) error {
+// newFargateENIRetryPolicy returns the policy used to wait for Fargate network interfaces to be deleted
+var newFargateENIRetryPolicy = func(timeout time.Duration) retry.Policy {
+	backoff := retry.NewTimingOutExponentialBackoff(timeout)
+	return &backoff
+}
+
+func deleteSharedResources(ctx context.Context, cfg *api.ClusterConfig, ctl *eks.ClusterProvider, stackManager manager.StackManager, clusterOperable, continueAfterDeprecatedStacks bool, clientSet kubernetes.Interface) error {
 	if clusterOperable {
-		if err := deleteFargateProfiles(cfg.Metadata, ctl, stackManager); err != nil {
+		if err := deleteFargateProfiles(ctx, cfg.Metadata, ctl, stackManager); err != nil {
 			return err
 		}
 	}
@@ -46,7 +62,10 @@ func deleteSharedResources(ctx context.Context, cfg *api.ClusterConfig, ctl *eks
 		if err != nil {
 			return err
 		}
-		return nil
+		if !continueAfterDeprecatedStacks {
+			return nil
+		}
+		logger.Info("continuing with deletion of remaining shared resources")
 	}
 
 	ssh.DeleteKeys(ctx, ctl.Provider.EC2(), cfg.Metadata.Name)
@@ -68,6 +87,23 @@ func deleteSharedResources(ctx context.Context, cfg *api.ClusterConfig, ctl *eks
 	return nil
 }
 
+// cleanupNetworkResources deletes the dangling network interfaces of the security groups of the cluster and, unless
+// skipOrphanedNetworkCleanup is set, the network interfaces and security groups left behind in the VPC of the cluster,
+// e.g. by controllers running in it, once the nodegroups are deleted and their network interfaces are detached
+func cleanupNetworkResources(ctx context.Context, ec2API awsapi.EC2, cfg *api.ClusterConfig, skipOrphanedNetworkCleanup bool) error {
+	if err := vpc.CleanupNetworkInterfaces(ctx, ec2API, cfg); err != nil {
+		return err
+	}
+	if skipOrphanedNetworkCleanup {
+		return nil
+	}
+	logger.Info("cleaning up detached network interfaces and orphaned security groups of cluster %q in %q", cfg.Metadata.Name, cfg.VPC.ID)
+	if err := vpc.CleanupOrphanedNetworkResources(ctx, ec2API, cfg.Metadata.Name, cfg.VPC.ID); err != nil {
+		logger.Warning("failed to clean up orphaned network resources: %v", err)
+	}
+	return nil
+}
+
 func handleErrors(errs []error, subject string) error {
 	logger.Info("%d error(s) occurred while deleting %s", len(errs), subject)
 	for _, err := range errs {
@@ -76,7 +112,7 @@ func handleErrors(errs []error, subject string) error {
 	return fmt.Errorf("failed to delete %s", subject)
 }
 
-func deleteFargateProfiles(clusterMeta *api.ClusterMeta, ctl *eks.ClusterProvider, stackManager manager.StackManager) error {
+func deleteFargateProfiles(ctx context.Context, clusterMeta *api.ClusterMeta, ctl *eks.ClusterProvider, stackManager manager.StackManager) error {
 	manager := fargate.NewFromProvider(
 		clusterMeta.Name,
 		ctl.Provider,
@@ -111,6 +147,14 @@ func deleteFargateProfiles(clusterMeta *api.ClusterMeta, ctl *eks.ClusterProvide
 	}
 	logger.Info("deleted %v Fargate profile(s)", len(profileNames))
 
+	if len(profileNames) > 0 {
+		// deleting the profiles deletes the pods running on Fargate, e.g. coredns, but their network interfaces
+		// are released later and would make the deletion of the VPC fail
+		if err := waitForFargateNetworkInterfacesDeletion(ctx, ctl); err != nil {
+			return err
+		}
+	}
+
 	stack, err := stackManager.GetFargateStack()
 	if err != nil {
 		return err
@@ -125,6 +169,18 @@ func deleteFargateProfiles(clusterMeta *api.ClusterMeta, ctl *eks.ClusterProvide
 	return nil
 }
 
+func waitForFargateNetworkInterfacesDeletion(ctx context.Context, ctl *eks.ClusterProvider) error {
+	if ctl.Status.ClusterInfo == nil || ctl.Status.ClusterInfo.Cluster == nil || ctl.Status.ClusterInfo.Cluster.ResourcesVpcConfig == nil {
+		logger.Debug("VPC of the cluster is unknown, not waiting for Fargate network interfaces to be deleted")
+		return nil
+	}
+	vpcID := aws.StringValue(ctl.Status.ClusterInfo.Cluster.ResourcesVpcConfig.VpcId)
+	if vpcID == "" {
+		return nil
+	}
+	return vpc.WaitForFargateNetworkInterfacesDeletion(ctx, ctl.Provider.EC2(), vpcID, newFargateENIRetryPolicy(ctl.Provider.WaitTimeout()))
+}
+
 func deleteDeprecatedStacks(stackManager manager.StackManager) (bool, error) {
 	tasks, err := stackManager.DeleteTasksForDeprecatedStacks()
 	if err != nil {
@@ -165,9 +221,10 @@ func checkForUndeletedStacks(stackManager manager.StackManager) error {
 	return nil
 }
 
-func drainAllNodeGroups(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface, allStacks []manager.NodeGroupStack,
-	disableEviction bool, parallel int, nodeGroupDrainer NodeGroupDrainer, vpcCniDeleter vpcCniDeleter) error {
+func drainAllNodeGroups(ctx context.Context, cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface, allStacks []manager.NodeGroupStack,
+	options DeleteOptions, nodeGroupDrainer NodeGroupDrainer, vpcCniDeleter vpcCniDeleter) error {
 	if len(allStacks) == 0 {
+		logger.Info("no nodegroups to drain in cluster %q", cfg.Metadata.Name)
 		return nil
 	}
 
@@ -180,11 +237,19 @@ func drainAllNodeGroups(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, client
 
 	logger.Info("will drain %d unmanaged nodegroup(s) in cluster %q", len(cfg.NodeGroups), cfg.Metadata.Name)
 
+	if options.CheckNodeReachability && len(cfg.NodeGroups) > 0 {
+		var nodeGroupNames []string
+		for _, ng := range cfg.NodeGroups {
+			nodeGroupNames = append(nodeGroupNames, ng.Name)
+		}
+		warnAboutUnreachableNodes(ctx, ctl, cfg.Metadata.Name, nodeGroupNames)
+	}
+
 	drainInput := &nodegroup.DrainInput{
 		NodeGroups:      cmdutils.ToKubeNodeGroups(cfg),
 		MaxGracePeriod:  ctl.Provider.WaitTimeout(),
-		DisableEviction: disableEviction,
-		Parallel:        parallel,
+		DisableEviction: options.DisableNodegroupEviction,
+		Parallel:        options.Parallel,
 	}
 	if err := nodeGroupDrainer.Drain(drainInput); err != nil {
 		return err
@@ -194,6 +259,127 @@ func drainAllNodeGroups(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, client
 	return nil
 }
 
+// warnAboutUnreachableNodes warns about the nodes of nodeGroupNames whose security groups do not allow the control plane
+// to reach their kubelet, e.g. because the rules were modified out-of-band, as draining them would stall. It is a best
+// effort check that only logs the errors it encounters
+func warnAboutUnreachableNodes(ctx context.Context, ctl *eks.ClusterProvider, clusterName string, nodeGroupNames []string) {
+	controlPlaneSecurityGroups := getControlPlaneSecurityGroups(ctl)
+	if len(controlPlaneSecurityGroups) == 0 {
+		logger.Debug("security groups of the control plane are unknown, not checking whether it can reach the nodes")
+		return
+	}
+	unreachableNodes, err := findUnreachableNodes(ctx, ctl.Provider.EC2(), clusterName, nodeGroupNames, controlPlaneSecurityGroups)
+	if err != nil {
+		logger.Debug("failed to check whether the control plane can reach the nodes: %v", err)
+		return
+	}
+	if len(unreachableNodes) > 0 {
+		logger.Warning("the security groups of node(s) %s do not allow the control plane (security groups %s) to reach the kubelet on port %d, "+
+			"draining them will stall until the missing ingress rules are restored", strings.Join(unreachableNodes, ", "), strings.Join(controlPlaneSecurityGroups, ", "), kubeletPort)
+	}
+}
+
+func getControlPlaneSecurityGroups(ctl *eks.ClusterProvider) []string {
+	if ctl.Status == nil || ctl.Status.ClusterInfo == nil || ctl.Status.ClusterInfo.Cluster == nil || ctl.Status.ClusterInfo.Cluster.ResourcesVpcConfig == nil {
+		return nil
+	}
+	vpcConfig := ctl.Status.ClusterInfo.Cluster.ResourcesVpcConfig
+	var securityGroups []string
+	if clusterSecurityGroup := aws.StringValue(vpcConfig.ClusterSecurityGroupId); clusterSecurityGroup != "" {
+		securityGroups = append(securityGroups, clusterSecurityGroup)
+	}
+	return append(securityGroups, aws.StringValueSlice(vpcConfig.SecurityGroupIds)...)
+}
+
+// findUnreachableNodes returns the IDs of the running instances of nodeGroupNames that have no security group allowing
+// ingress to the kubelet port from controlPlaneSecurityGroups
+func findUnreachableNodes(ctx context.Context, ec2API awsapi.EC2, clusterName string, nodeGroupNames, controlPlaneSecurityGroups []string) ([]string, error) {
+	instanceSecurityGroups := map[string][]string{}
+	securityGroups := sets.NewString()
+	paginator := ec2.NewDescribeInstancesPaginator(ec2API, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []string{"kubernetes.io/cluster/" + clusterName},
+			},
+			{
+				Name:   aws.String("tag:" + api.NodeGroupNameTag),
+				Values: nodeGroupNames,
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{string(ec2types.InstanceStateNameRunning)},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error describing the instances of nodegroups %v: %w", nodeGroupNames, err)
+		}
+		for _, reservation := range output.Reservations {
+			for _, instance := range reservation.Instances {
+				var groupIDs []string
+				for _, sg := range instance.SecurityGroups {
+					groupIDs = append(groupIDs, aws.StringValue(sg.GroupId))
+				}
+				instanceSecurityGroups[aws.StringValue(instance.InstanceId)] = groupIDs
+				securityGroups.Insert(groupIDs...)
+			}
+		}
+	}
+	if securityGroups.Len() == 0 {
+		return nil, nil
+	}
+
+	output, err := ec2API.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: securityGroups.List(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing security groups %v: %w", securityGroups.List(), err)
+	}
+	allowingSecurityGroups := sets.NewString()
+	for _, sg := range output.SecurityGroups {
+		if allowsKubeletIngress(sg.IpPermissions, sets.NewString(controlPlaneSecurityGroups...)) {
+			allowingSecurityGroups.Insert(aws.StringValue(sg.GroupId))
+		}
+	}
+
+	var unreachableNodes []string
+	for instanceID, groupIDs := range instanceSecurityGroups {
+		if !allowingSecurityGroups.HasAny(groupIDs...) {
+			unreachableNodes = append(unreachableNodes, instanceID)
+		}
+	}
+	sort.Strings(unreachableNodes)
+	return unreachableNodes, nil
+}
+
+// allowsKubeletIngress reports whether permissions allow ingress to the kubelet port from controlPlaneSecurityGroups.
+// Rules allowing CIDR ranges are assumed to cover the network interfaces of the control plane
+func allowsKubeletIngress(permissions []ec2types.IpPermission, controlPlaneSecurityGroups sets.String) bool {
+	for _, permission := range permissions {
+		switch aws.StringValue(permission.IpProtocol) {
+		case "-1":
+		case "tcp", "6":
+			if permission.FromPort == nil || permission.ToPort == nil || *permission.FromPort > kubeletPort || *permission.ToPort < kubeletPort {
+				continue
+			}
+		default:
+			continue
+		}
+		if len(permission.IpRanges) > 0 || len(permission.Ipv6Ranges) > 0 {
+			return true
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			if controlPlaneSecurityGroups.Has(aws.StringValue(pair.GroupId)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Attempts to delete the vpc-cni, and fails silently if an error occurs. This is an attempt
 // to prevent a race condition in the vpc-cni #1849
 func attemptVpcCniDeletion(clusterName string, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) {
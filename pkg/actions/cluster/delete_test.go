@@ -1,6 +1,15 @@
 package cluster_test
 
 import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/kris-nova/logger"
+
 	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 
@@ -68,7 +77,7 @@ var _ = Describe("DrainAllNodeGroups", func() {
 					vpcCniDeleterCalled++
 				}
 
-				err := cluster.DrainAllNodeGroups(cfg, ctl, fakeClientSet, nodeGroupStacks, false, 1, mockedDrainer, vpcCniDeleter)
+				err := cluster.DrainAllNodeGroups(context.Background(), cfg, ctl, fakeClientSet, nodeGroupStacks, cluster.DeleteOptions{Parallel: 1}, mockedDrainer, vpcCniDeleter)
 				Expect(err).NotTo(HaveOccurred())
 				mockedDrainer.AssertNumberOfCalls(GinkgoT(), "Drain", 1)
 				Expect(vpcCniDeleterCalled).To(Equal(1))
@@ -97,7 +106,7 @@ var _ = Describe("DrainAllNodeGroups", func() {
 					vpcCniDeleterCalled++
 				}
 
-				err := cluster.DrainAllNodeGroups(cfg, ctl, fakeClientSet, nodeGroupStacks, true, 1, mockedDrainer, vpcCniDeleter)
+				err := cluster.DrainAllNodeGroups(context.Background(), cfg, ctl, fakeClientSet, nodeGroupStacks, cluster.DeleteOptions{DisableNodegroupEviction: true, Parallel: 1}, mockedDrainer, vpcCniDeleter)
 				Expect(err).NotTo(HaveOccurred())
 				mockedDrainer.AssertNumberOfCalls(GinkgoT(), "Drain", 1)
 				Expect(vpcCniDeleterCalled).To(Equal(1))
@@ -125,11 +134,153 @@ var _ = Describe("DrainAllNodeGroups", func() {
 					vpcCniDeleterCalled++
 				}
 
-				err := cluster.DrainAllNodeGroups(cfg, ctl, fakeClientSet, nodeGroupStacks, false, 1, mockedDrainer, vpcCniDeleter)
+				err := cluster.DrainAllNodeGroups(context.Background(), cfg, ctl, fakeClientSet, nodeGroupStacks, cluster.DeleteOptions{Parallel: 1}, mockedDrainer, vpcCniDeleter)
 				Expect(err).NotTo(HaveOccurred())
 				mockedDrainer.AssertNotCalled(GinkgoT(), "Drain")
 				Expect(vpcCniDeleterCalled).To(Equal(0))
 			})
 		})
+
+		When("the security groups of the nodes do not allow the control plane to reach the kubelet", func() {
+			var (
+				output               *bytes.Buffer
+				originalWriter       = logger.Writer
+				nodeGroupStacks      []manager.NodeGroupStack
+				mockedDrainer        *drainerMock
+				nodeSecurityGroupIPs []ec2types.IpPermission
+			)
+
+			BeforeEach(func() {
+				output = &bytes.Buffer{}
+				logger.Writer = output
+				ctl.Status.ClusterInfo = &eks.ClusterInfo{
+					Cluster: &awseks.Cluster{
+						ResourcesVpcConfig: &awseks.VpcConfigResponse{
+							ClusterSecurityGroupId: aws.String("sg-cluster"),
+						},
+					},
+				}
+				nodeGroupStacks = []manager.NodeGroupStack{{NodeGroupName: "ng-1", Type: api.NodeGroupTypeUnmanaged}}
+				mockedDrainer = &drainerMock{}
+				mockedDrainer.On("Drain", mock.Anything).Return(nil)
+
+				p.MockEC2().On("DescribeInstances", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeInstancesInput) bool {
+					return len(input.Filters) == 3 && aws.ToString(input.Filters[1].Name) == "tag:"+api.NodeGroupNameTag &&
+						input.Filters[1].Values[0] == "ng-1"
+				})).Return(&ec2.DescribeInstancesOutput{
+					Reservations: []ec2types.Reservation{
+						{
+							Instances: []ec2types.Instance{
+								{
+									InstanceId: aws.String("i-1234"),
+									SecurityGroups: []ec2types.GroupIdentifier{
+										{GroupId: aws.String("sg-node")},
+									},
+								},
+							},
+						},
+					},
+				}, nil)
+				p.MockEC2().On("DescribeSecurityGroups", mock.Anything, &ec2.DescribeSecurityGroupsInput{
+					GroupIds: []string{"sg-node"},
+				}).Return(func(_ context.Context, _ *ec2.DescribeSecurityGroupsInput, _ ...func(*ec2.Options)) *ec2.DescribeSecurityGroupsOutput {
+					return &ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []ec2types.SecurityGroup{
+							{
+								GroupId:       aws.String("sg-node"),
+								IpPermissions: nodeSecurityGroupIPs,
+							},
+						},
+					}
+				}, nil)
+			})
+
+			AfterEach(func() {
+				logger.Writer = originalWriter
+			})
+
+			It("warns that draining will stall", func() {
+				nodeSecurityGroupIPs = []ec2types.IpPermission{
+					{
+						IpProtocol: aws.String("tcp"),
+						FromPort:   aws.Int32(22),
+						ToPort:     aws.Int32(22),
+						UserIdGroupPairs: []ec2types.UserIdGroupPair{
+							{GroupId: aws.String("sg-cluster")},
+						},
+					},
+				}
+				err := cluster.DrainAllNodeGroups(context.Background(), cfg, ctl, fakeClientSet, nodeGroupStacks, cluster.DeleteOptions{Parallel: 1, CheckNodeReachability: true}, mockedDrainer, func(string, *eks.ClusterProvider, kubernetes.Interface) {})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output.String()).To(ContainSubstring("the security groups of node(s) i-1234 do not allow the control plane (security groups sg-cluster) to reach the kubelet on port 10250"))
+				mockedDrainer.AssertNumberOfCalls(GinkgoT(), "Drain", 1)
+			})
+
+			It("does not warn when the control plane can reach the kubelet", func() {
+				nodeSecurityGroupIPs = []ec2types.IpPermission{
+					{
+						IpProtocol: aws.String("tcp"),
+						FromPort:   aws.Int32(1025),
+						ToPort:     aws.Int32(65535),
+						UserIdGroupPairs: []ec2types.UserIdGroupPair{
+							{GroupId: aws.String("sg-cluster")},
+						},
+					},
+				}
+				err := cluster.DrainAllNodeGroups(context.Background(), cfg, ctl, fakeClientSet, nodeGroupStacks, cluster.DeleteOptions{Parallel: 1, CheckNodeReachability: true}, mockedDrainer, func(string, *eks.ClusterProvider, kubernetes.Interface) {})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output.String()).NotTo(ContainSubstring("do not allow the control plane"))
+			})
+
+			It("does not check the security groups of the nodes unless requested", func() {
+				err := cluster.DrainAllNodeGroups(context.Background(), cfg, ctl, fakeClientSet, nodeGroupStacks, cluster.DeleteOptions{Parallel: 1}, mockedDrainer, func(string, *eks.ClusterProvider, kubernetes.Interface) {})
+				Expect(err).NotTo(HaveOccurred())
+				p.MockEC2().AssertNotCalled(GinkgoT(), "DescribeInstances", mock.Anything, mock.Anything)
+				p.MockEC2().AssertNotCalled(GinkgoT(), "DescribeSecurityGroups", mock.Anything, mock.Anything)
+				mockedDrainer.AssertNumberOfCalls(GinkgoT(), "Drain", 1)
+			})
+		})
+	})
+})
+
+var _ = Describe("CleanupNetworkResources", func() {
+	var (
+		p   *mockprovider.MockProvider
+		cfg *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "my-cluster"
+		cfg.VPC.ID = "vpc-1234"
+
+		p.MockEC2().On("DescribeNetworkInterfaces", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeNetworkInterfacesInput) bool {
+			return len(input.Filters) == 2 && aws.ToString(input.Filters[1].Name) == "status"
+		})).Return(&ec2.DescribeNetworkInterfacesOutput{}, nil)
+		mockOrphanedNetworkResources(p, []ec2types.NetworkInterface{
+			{NetworkInterfaceId: aws.String("eni-orphaned")},
+		}, []ec2types.SecurityGroup{
+			{GroupId: aws.String("sg-orphaned"), GroupName: aws.String("orphaned")},
+		})
+		p.MockEC2().On("DeleteNetworkInterface", mock.Anything, mock.Anything).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+		p.MockEC2().On("DeleteSecurityGroup", mock.Anything, mock.Anything).Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+	})
+
+	It("deletes the detached network interfaces and orphaned security groups of the cluster", func() {
+		Expect(cluster.CleanupNetworkResources(context.Background(), p.EC2(), cfg, false)).To(Succeed())
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteNetworkInterface", mock.Anything, &ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String("eni-orphaned"),
+		})
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteSecurityGroup", mock.Anything, &ec2.DeleteSecurityGroupInput{
+			GroupId: aws.String("sg-orphaned"),
+		})
+	})
+
+	It("only deletes the dangling network interfaces of the cluster's security groups when skipOrphanedNetworkCleanup is set", func() {
+		Expect(cluster.CleanupNetworkResources(context.Background(), p.EC2(), cfg, true)).To(Succeed())
+		p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeNetworkInterfaces", 1)
+		p.MockEC2().AssertNotCalled(GinkgoT(), "DeleteNetworkInterface", mock.Anything, mock.Anything)
+		p.MockEC2().AssertNotCalled(GinkgoT(), "DeleteSecurityGroup", mock.Anything, mock.Anything)
 	})
 })
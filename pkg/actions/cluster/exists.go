@@ -0,0 +1,80 @@
+package cluster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// Behaviours of `eksctl create cluster` when the cluster it creates already exists
+const (
+	// IfExistsFail fails the creation of the cluster
+	IfExistsFail = "fail"
+	// IfExistsSkip skips the creation of a cluster that is healthy and was created from the same config
+	IfExistsSkip = "skip"
+	// IfExistsReconcile creates the nodegroups and addons of the config that the cluster is missing
+	IfExistsReconcile = "reconcile"
+)
+
+// IfExistsValues returns the supported values of --if-exists
+func IfExistsValues() []string {
+	return []string{IfExistsFail, IfExistsSkip, IfExistsReconcile}
+}
+
+// ExistingClusterState describes how an existing cluster relates to the config it is being created from
+type ExistingClusterState int
+
+const (
+	// ClusterStackNotFound means that eksctl has not created the cluster
+	ClusterStackNotFound ExistingClusterState = iota
+	// ClusterMatchesConfig means that the cluster was created from the same config
+	ClusterMatchesConfig
+	// ClusterDivergesFromConfig means that the cluster was created from a different config, or by a version of eksctl
+	// that did not record it
+	ClusterDivergesFromConfig
+)
+
+// ConfigHash identifies cfg, as loaded before `eksctl create cluster` fills in the resources it creates, so that
+// running the command again with the same config can be recognised
+func ConfigHash(cfg *api.ClusterConfig) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("serialising cluster config: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CheckExistingCluster compares the cluster stack, if eksctl has created one, with configHash, the hash of the
+// config the cluster is being created from. It returns an error if the stack is not in a healthy state
+func CheckExistingCluster(stackManager manager.StackManager, configHash string) (ExistingClusterState, error) {
+	stack, err := stackManager.DescribeClusterStack()
+	if err != nil {
+		return ClusterStackNotFound, err
+	}
+	if stack == nil {
+		return ClusterStackNotFound, nil
+	}
+	if !isHealthyClusterStack(stack) {
+		return ClusterStackNotFound, fmt.Errorf("cluster stack %q already exists and is in state %s", *stack.StackName, *stack.StackStatus)
+	}
+	if manager.GetClusterConfigHashTag(stack.Tags) != configHash {
+		return ClusterDivergesFromConfig, nil
+	}
+	return ClusterMatchesConfig, nil
+}
+
+func isHealthyClusterStack(stack *manager.Stack) bool {
+	switch *stack.StackStatus {
+	case cloudformation.StackStatusCreateComplete, cloudformation.StackStatusUpdateComplete, cloudformation.StackStatusUpdateRollbackComplete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package cluster_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/actions/cluster"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+)
+
+var _ = Describe("CheckExistingCluster", func() {
+	var (
+		cfg              *api.ClusterConfig
+		configHash       string
+		fakeStackManager *fakes.FakeStackManager
+	)
+
+	clusterStack := func(status, hash string) *cfn.Stack {
+		stack := &cfn.Stack{
+			StackName:   aws.String("eksctl-my-cluster-cluster"),
+			StackStatus: aws.String(status),
+			Tags: []*cfn.Tag{
+				{Key: aws.String(api.ClusterNameTag), Value: aws.String("my-cluster")},
+			},
+		}
+		if hash != "" {
+			stack.Tags = append(stack.Tags, &cfn.Tag{Key: aws.String(api.ClusterConfigHashTag), Value: aws.String(hash)})
+		}
+		return stack
+	}
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "my-cluster"
+		cfg.Metadata.Region = "us-west-2"
+
+		var err error
+		configHash, err = cluster.ConfigHash(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeStackManager = new(fakes.FakeStackManager)
+	})
+
+	It("hashes the same config to the same value", func() {
+		otherCfg := api.NewClusterConfig()
+		otherCfg.Metadata.Name = "my-cluster"
+		otherCfg.Metadata.Region = "us-west-2"
+		Expect(cluster.ConfigHash(otherCfg)).To(Equal(configHash))
+
+		otherCfg.Metadata.Region = "eu-west-1"
+		Expect(cluster.ConfigHash(otherCfg)).NotTo(Equal(configHash))
+	})
+
+	It("reports clusters that eksctl has not created", func() {
+		fakeStackManager.DescribeClusterStackReturns(nil, nil)
+
+		state, err := cluster.CheckExistingCluster(fakeStackManager, configHash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(cluster.ClusterStackNotFound))
+	})
+
+	It("reports clusters created from the same config", func() {
+		fakeStackManager.DescribeClusterStackReturns(clusterStack(cfn.StackStatusCreateComplete, configHash), nil)
+
+		state, err := cluster.CheckExistingCluster(fakeStackManager, configHash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(cluster.ClusterMatchesConfig))
+	})
+
+	It("reports clusters created from a diverging config", func() {
+		cfg.Metadata.Version = "1.27"
+		divergingHash, err := cluster.ConfigHash(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		fakeStackManager.DescribeClusterStackReturns(clusterStack(cfn.StackStatusUpdateComplete, configHash), nil)
+
+		state, err := cluster.CheckExistingCluster(fakeStackManager, divergingHash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(cluster.ClusterDivergesFromConfig))
+	})
+
+	It("reports clusters whose config hash was not recorded as diverging", func() {
+		fakeStackManager.DescribeClusterStackReturns(clusterStack(cfn.StackStatusCreateComplete, ""), nil)
+
+		state, err := cluster.CheckExistingCluster(fakeStackManager, configHash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal(cluster.ClusterDivergesFromConfig))
+	})
+
+	It("fails when the cluster stack is not healthy", func() {
+		fakeStackManager.DescribeClusterStackReturns(clusterStack(cfn.StackStatusRollbackComplete, configHash), nil)
+
+		_, err := cluster.CheckExistingCluster(fakeStackManager, configHash)
+		Expect(err).To(MatchError(`cluster stack "eksctl-my-cluster-cluster" already exists and is in state ROLLBACK_COMPLETE`))
+	})
+
+	It("returns errors describing the cluster stack", func() {
+		fakeStackManager.DescribeClusterStackReturns(nil, errors.New("throttled"))
+
+		_, err := cluster.CheckExistingCluster(fakeStackManager, configHash)
+		Expect(err).To(MatchError("throttled"))
+	})
+})
@@ -1,13 +1,17 @@
 package cluster
 
 import (
+	"time"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 )
 
 var (
-	DrainAllNodeGroups = drainAllNodeGroups
+	DrainAllNodeGroups      = drainAllNodeGroups
+	CleanupNetworkResources = cleanupNetworkResources
 )
 
 func (c *UnownedCluster) SetNewClientSet(newClientSet func() (kubernetes.Interface, error)) {
@@ -33,3 +37,11 @@ func SetProviderConstructor(f ProviderConstructor) {
 func SetStackManagerConstructor(f StackManagerConstructor) {
 	newStackCollection = f
 }
+
+func SetFargateENIRetryPolicy(f func(timeout time.Duration) retry.Policy) (restore func()) {
+	previous := newFargateENIRetryPolicy
+	newFargateENIRetryPolicy = f
+	return func() {
+		newFargateENIRetryPolicy = previous
+	}
+}
@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 
@@ -27,6 +28,14 @@ type Description struct {
 	Owned  api.EKSCTLCreated
 }
 
+// Summary describes a cluster along with when it was created and last updated, and by which version of eksctl
+type Summary struct {
+	*awseks.Cluster
+	CreationTime    *time.Time
+	LastUpdatedTime *time.Time
+	EksctlVersion   string
+}
+
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fakes/fake_aws_provider.go . ProviderConstructor
 type ProviderConstructor func(ctx context.Context, spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) (*eks.ClusterProvider, error)
@@ -83,6 +92,31 @@ func GetClusters(ctx context.Context, provider api.ClusterProvider, listAllRegio
 	return clusters, nil
 }
 
+// GetClusterSummary describes the cluster and, if it was created by eksctl, its stack. For other clusters, the
+// creation time reported by EKS is used
+func GetClusterSummary(ctx context.Context, ctl *eks.ClusterProvider, cfg *api.ClusterConfig) (*Summary, error) {
+	cluster, err := ctl.GetCluster(ctx, cfg.Metadata.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Cluster:      cluster,
+		CreationTime: cluster.CreatedAt,
+	}
+
+	stack, err := newStackCollection(ctl.Provider, cfg).DescribeClusterStack()
+	if err != nil {
+		return nil, fmt.Errorf("describing stack of cluster %q: %w", cfg.Metadata.Name, err)
+	}
+	if stack != nil {
+		summary.CreationTime = stack.CreationTime
+		summary.LastUpdatedTime = stack.LastUpdatedTime
+		summary.EksctlVersion = manager.GetEksctlVersionTag(stack.Tags)
+	}
+	return summary, nil
+}
+
 func listClusters(provider api.ClusterProvider, chunkSize int64) ([]Description, error) {
 	var allClusters []Description
 
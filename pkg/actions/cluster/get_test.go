@@ -3,11 +3,13 @@ package cluster_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/weaveworks/eksctl/pkg/actions/cluster"
 	"github.com/weaveworks/eksctl/pkg/actions/cluster/fakes"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	mgrfakes "github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
@@ -277,4 +280,68 @@ var _ = Describe("Get", func() {
 			})
 		})
 	})
+
+	Describe("GetClusterSummary", func() {
+		var (
+			stackManager    *mgrfakes.FakeStackManager
+			cfg             *api.ClusterConfig
+			createdAt       = time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+			stackCreatedAt  = time.Date(2022, 3, 1, 9, 45, 0, 0, time.UTC)
+			lastUpdatedTime = time.Date(2022, 3, 15, 12, 30, 0, 0, time.UTC)
+		)
+
+		BeforeEach(func() {
+			stackManager = new(mgrfakes.FakeStackManager)
+			stackCollectionProvider.Returns(stackManager)
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Name = "my-cluster"
+
+			intialProvider.MockEKS().On("DescribeCluster", &awseks.DescribeClusterInput{
+				Name: aws.String("my-cluster"),
+			}).Return(&awseks.DescribeClusterOutput{
+				Cluster: &awseks.Cluster{
+					Name:      aws.String("my-cluster"),
+					Status:    aws.String(awseks.ClusterStatusActive),
+					CreatedAt: aws.Time(createdAt),
+				},
+			}, nil)
+		})
+
+		It("returns the timestamps and eksctl version of the cluster stack", func() {
+			stackManager.DescribeClusterStackReturns(&cloudformation.Stack{
+				CreationTime:    aws.Time(stackCreatedAt),
+				LastUpdatedTime: aws.Time(lastUpdatedTime),
+				Tags: []*cloudformation.Tag{
+					{
+						Key:   aws.String(api.EksctlVersionTag),
+						Value: aws.String("0.90.0"),
+					},
+				},
+			}, nil)
+
+			summary, err := cluster.GetClusterSummary(context.Background(), &eks.ClusterProvider{Provider: intialProvider}, cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*summary.Name).To(Equal("my-cluster"))
+			Expect(summary.CreationTime).To(Equal(aws.Time(stackCreatedAt)))
+			Expect(summary.LastUpdatedTime).To(Equal(aws.Time(lastUpdatedTime)))
+			Expect(summary.EksctlVersion).To(Equal("0.90.0"))
+		})
+
+		It("returns the creation time reported by EKS for a cluster without a stack", func() {
+			stackManager.DescribeClusterStackReturns(nil, nil)
+
+			summary, err := cluster.GetClusterSummary(context.Background(), &eks.ClusterProvider{Provider: intialProvider}, cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary.CreationTime).To(Equal(aws.Time(createdAt)))
+			Expect(summary.LastUpdatedTime).To(BeNil())
+			Expect(summary.EksctlVersion).To(BeEmpty())
+		})
+
+		It("errors when the cluster stack cannot be described", func() {
+			stackManager.DescribeClusterStackReturns(nil, fmt.Errorf("foo"))
+
+			_, err := cluster.GetClusterSummary(context.Background(), &eks.ClusterProvider{Provider: intialProvider}, cfg)
+			Expect(err).To(MatchError(`describing stack of cluster "my-cluster": foo`))
+		})
+	})
 })
@@ -2,7 +2,7 @@ package cluster
 
 import (
 	"context"
-	"time"
+	"os"
 
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
@@ -41,17 +41,35 @@ func NewOwnedCluster(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clusterSt
 	}
 }
 
-func (c *OwnedCluster) Upgrade(ctx context.Context, dryRun bool) error {
+func (c *OwnedCluster) Upgrade(ctx context.Context, dryRun bool, planHash string) error {
 	if err := vpc.UseFromClusterStack(ctx, c.ctl.Provider, c.clusterStack, c.cfg); err != nil {
 		return errors.Wrapf(err, "getting VPC configuration for cluster %q", c.cfg.Metadata.Name)
 	}
 
-	versionUpdateRequired, err := upgrade(c.cfg, c.ctl, dryRun)
+	currentVersion := c.ctl.ControlPlaneVersion()
+	versionUpdateRequired, err := requiresVersionUpgrade(c.cfg.Metadata, currentVersion)
 	if err != nil {
 		return err
 	}
 
-	stackUpdateRequired, err := c.stackManager.AppendNewClusterStackResource(ctx, dryRun)
+	stackTemplates, err := c.stackManager.RenderClusterStackTemplates(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "rendering the stack of cluster %q", c.cfg.Metadata.Name)
+	}
+	stackDiff, err := manager.DiffTemplateResources(stackTemplates.Current, stackTemplates.New)
+	if err != nil {
+		return errors.Wrapf(err, "comparing the stack of cluster %q", c.cfg.Metadata.Name)
+	}
+	plan := newUpgradePlan(c.cfg.Metadata.Name, currentVersion, c.cfg.Metadata.Version, c.stackManager.MakeClusterStackName(), stackDiff)
+	if err := reviewUpgradePlan(plan, dryRun, planHash, os.Stdout); err != nil {
+		return err
+	}
+
+	if err := upgrade(c.cfg, c.ctl, currentVersion, versionUpdateRequired, dryRun); err != nil {
+		return err
+	}
+
+	stackUpdateRequired, err := c.stackManager.AppendNewClusterStackResource(stackTemplates, dryRun)
 	if err != nil {
 		return err
 	}
@@ -61,20 +79,21 @@ func (c *OwnedCluster) Upgrade(ctx context.Context, dryRun bool) error {
 		logger.Critical("failed checking nodegroups", err.Error())
 	}
 
+	if !dryRun {
+		checkOperability(ctx, c.cfg, c.ctl, c.newClientSet)
+	}
+
 	cmdutils.LogPlanModeWarning(dryRun && (stackUpdateRequired || versionUpdateRequired))
 	return nil
 }
 
-func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force, disableNodegroupEviction bool, parallel int) error {
+func (c *OwnedCluster) Delete(ctx context.Context, options DeleteOptions) error {
 	var (
 		clientSet kubernetes.Interface
 		oidc      *iamoidc.OpenIDConnectManager
 	)
 
-	clusterOperable, err := c.ctl.CanOperate(c.cfg)
-	if err != nil {
-		logger.Debug("failed to check if cluster is operable: %v", err)
-	}
+	clusterOperable := checkOperability(ctx, c.cfg, c.ctl, c.newClientSet)
 
 	// moving this here was fine because inside `NewTasksToDeleteClusterWithNodeGroups` we did it anyway.
 	allStacks, err := c.stackManager.ListNodeGroupStacks()
@@ -87,7 +106,7 @@ func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force,
 		var err error
 		clientSet, err = c.newClientSet()
 		if err != nil {
-			if force {
+			if options.Force {
 				logger.Warning("error occurred during deletion: %v", err)
 			} else {
 				return err
@@ -97,7 +116,7 @@ func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force,
 		oidc, err = c.ctl.NewOpenIDConnectManager(c.cfg)
 		if err != nil {
 			if _, ok := err.(*eks.UnsupportedOIDCError); !ok {
-				if force {
+				if options.Force {
 					logger.Warning("error occurred during deletion: %v", err)
 				} else {
 					return err
@@ -107,8 +126,8 @@ func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force,
 		}
 
 		nodeGroupManager := c.newNodeGroupManager(c.cfg, c.ctl, clientSet)
-		if err := drainAllNodeGroups(c.cfg, c.ctl, clientSet, allStacks, disableNodegroupEviction, parallel, nodeGroupManager, attemptVpcCniDeletion); err != nil {
-			if !force {
+		if err := drainAllNodeGroups(ctx, c.cfg, c.ctl, clientSet, allStacks, options, nodeGroupManager, attemptVpcCniDeletion); err != nil {
+			if !options.Force {
 				return err
 			}
 
@@ -116,9 +135,9 @@ func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force,
 		}
 	}
 
-	if err := deleteSharedResources(ctx, c.cfg, c.ctl, c.stackManager, clusterOperable, clientSet); err != nil {
+	if err := deleteSharedResources(ctx, c.cfg, c.ctl, c.stackManager, clusterOperable, options.ContinueAfterDeprecatedStacks, clientSet); err != nil {
 		if err != nil {
-			if force {
+			if options.Force {
 				logger.Warning("error occurred during deletion: %v", err)
 			} else {
 				return err
@@ -127,14 +146,14 @@ func (c *OwnedCluster) Delete(ctx context.Context, _ time.Duration, wait, force,
 	}
 
 	deleteOIDCProvider := clusterOperable && oidcSupported
-	tasks, err := c.stackManager.NewTasksToDeleteClusterWithNodeGroups(ctx, c.clusterStack, allStacks, deleteOIDCProvider, oidc, kubernetes.NewCachedClientSet(clientSet), wait, func(errs chan error, _ string) error {
+	tasks, err := c.stackManager.NewTasksToDeleteClusterWithNodeGroups(ctx, c.clusterStack, allStacks, deleteOIDCProvider, oidc, kubernetes.NewCachedClientSet(clientSet), options.Wait, func(errs chan error, _ string) error {
 		logger.Info("trying to cleanup dangling network interfaces")
 		if err := c.ctl.LoadClusterVPC(ctx, c.cfg, c.stackManager); err != nil {
 			return errors.Wrapf(err, "getting VPC configuration for cluster %q", c.cfg.Metadata.Name)
 		}
 
 		go func() {
-			errs <- vpc.CleanupNetworkInterfaces(ctx, c.ctl.Provider.EC2(), c.cfg)
+			errs <- cleanupNetworkResources(ctx, c.ctl.Provider.EC2(), c.cfg, options.SkipOrphanedNetworkCleanup)
 			close(errs)
 		}()
 		return nil
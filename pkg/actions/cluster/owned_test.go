@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
@@ -24,6 +25,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
 	"github.com/weaveworks/eksctl/pkg/testutils"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 	"github.com/weaveworks/eksctl/pkg/utils/strings"
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 )
@@ -114,7 +116,7 @@ var _ = Describe("Delete", func() {
 				return fakeClientSet, nil
 			})
 
-			err := c.Delete(context.Background(), time.Microsecond, false, false, false, 1)
+			err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 			Expect(ranDeleteDeprecatedTasks).To(BeTrue())
@@ -170,7 +172,7 @@ var _ = Describe("Delete", func() {
 				}, nil)
 
 				c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
-				fakeClientSet = fake.NewSimpleClientset()
+				fakeClientSet = newOperableClientSet()
 
 				c.SetNewClientSet(func() (kubernetes.Interface, error) {
 					return fakeClientSet, nil
@@ -188,7 +190,7 @@ var _ = Describe("Delete", func() {
 					return mockedDrainer
 				})
 
-				err := c.Delete(context.Background(), time.Microsecond, false, true, false, 1)
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Force: true, Parallel: 1})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 				Expect(ranDeleteDeprecatedTasks).To(BeFalse())
@@ -235,7 +237,7 @@ var _ = Describe("Delete", func() {
 				}, nil)
 
 				c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
-				fakeClientSet = fake.NewSimpleClientset()
+				fakeClientSet = newOperableClientSet()
 
 				c.SetNewClientSet(func() (kubernetes.Interface, error) {
 					return fakeClientSet, nil
@@ -261,7 +263,7 @@ var _ = Describe("Delete", func() {
 					return mockedDrainer
 				})
 
-				err := c.Delete(context.Background(), time.Microsecond, false, false, false, 1)
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
 				Expect(err).To(MatchError(errorMessage))
 				Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(0))
 				Expect(ranDeleteDeprecatedTasks).To(BeFalse())
@@ -272,6 +274,109 @@ var _ = Describe("Delete", func() {
 		})
 	})
 
+	Context("when the cluster only runs on Fargate", func() {
+		var restoreFargateENIRetryPolicy func()
+
+		BeforeEach(func() {
+			ctl.Status = &eks.ProviderStatus{
+				ClusterInfo: &eks.ClusterInfo{
+					Cluster: testutils.NewFakeCluster(clusterName, awseks.ClusterStatusActive),
+				},
+			}
+			restoreFargateENIRetryPolicy = cluster.SetFargateENIRetryPolicy(func(time.Duration) retry.Policy {
+				return &retry.ConstantBackoff{MaxRetries: 2, Time: 1, TimeUnit: time.Millisecond}
+			})
+		})
+
+		AfterEach(func() {
+			restoreFargateENIRetryPolicy()
+		})
+
+		It("deletes the Fargate profiles and waits for their network interfaces before deleting the cluster stack", func() {
+			p.MockEKS().On("ListFargateProfiles", &awseks.ListFargateProfilesInput{
+				ClusterName: strings.Pointer(clusterName),
+			}).Once().Return(&awseks.ListFargateProfilesOutput{FargateProfileNames: aws.StringSlice([]string{"fp-default"})}, nil)
+
+			p.MockEKS().On("DeleteFargateProfile", &awseks.DeleteFargateProfileInput{
+				ClusterName:        aws.String(clusterName),
+				FargateProfileName: aws.String("fp-default"),
+			}).Once().Return(&awseks.DeleteFargateProfileOutput{}, nil)
+
+			p.MockEKS().On("ListFargateProfiles", &awseks.ListFargateProfilesInput{
+				ClusterName: strings.Pointer(clusterName),
+			}).Once().Return(&awseks.ListFargateProfilesOutput{}, nil)
+
+			// the network interface of the coredns pod is released after the Fargate profile is deleted
+			p.MockEC2().On("DescribeNetworkInterfaces", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeNetworkInterfacesInput) bool {
+				return input.Filters[0].Values[0] == "vpc-1234"
+			})).Once().Return(&ec2.DescribeNetworkInterfacesOutput{
+				NetworkInterfaces: []ec2types.NetworkInterface{{NetworkInterfaceId: aws.String("eni-coredns")}},
+			}, nil)
+			fargateENIsDeleted := false
+			p.MockEC2().On("DescribeNetworkInterfaces", mock.Anything, mock.Anything).Once().Return(&ec2.DescribeNetworkInterfacesOutput{}, nil).Run(func(mock.Arguments) {
+				fargateENIsDeleted = true
+			})
+
+			fakeStackManager.DeleteTasksForDeprecatedStacksReturns(&tasks.TaskTree{}, nil)
+
+			p.MockEC2().On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{}, nil)
+
+			p.MockEC2().On("DescribeSecurityGroups", mock.Anything, mock.Anything).Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+
+			fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsReturns(&tasks.TaskTree{
+				Tasks: []tasks.Task{&tasks.GenericTask{Doer: func() error {
+					Expect(fargateENIsDeleted).To(BeTrue())
+					ranDeleteClusterTasks = true
+					return nil
+				}}},
+			}, nil)
+
+			c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
+			fakeClientSet = newOperableClientSet()
+			c.SetNewClientSet(func() (kubernetes.Interface, error) {
+				return fakeClientSet, nil
+			})
+
+			mockedDrainer := &drainerMockOwned{}
+			c.SetNewNodeGroupManager(func(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) cluster.NodeGroupDrainer {
+				return mockedDrainer
+			})
+
+			err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
+			Expect(err).NotTo(HaveOccurred())
+			mockedDrainer.AssertNotCalled(GinkgoT(), "Drain", mock.Anything)
+			p.MockEKS().AssertNumberOfCalls(GinkgoT(), "DeleteFargateProfile", 1)
+			p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeNetworkInterfaces", 2)
+			Expect(ranDeleteClusterTasks).To(BeTrue())
+		})
+
+		It("fails if the network interfaces of Fargate pods are not deleted", func() {
+			p.MockEKS().On("ListFargateProfiles", &awseks.ListFargateProfilesInput{
+				ClusterName: strings.Pointer(clusterName),
+			}).Once().Return(&awseks.ListFargateProfilesOutput{FargateProfileNames: aws.StringSlice([]string{"fp-default"})}, nil)
+
+			p.MockEKS().On("DeleteFargateProfile", mock.Anything).Once().Return(&awseks.DeleteFargateProfileOutput{}, nil)
+
+			p.MockEKS().On("ListFargateProfiles", &awseks.ListFargateProfilesInput{
+				ClusterName: strings.Pointer(clusterName),
+			}).Once().Return(&awseks.ListFargateProfilesOutput{}, nil)
+
+			p.MockEC2().On("DescribeNetworkInterfaces", mock.Anything, mock.Anything).Return(&ec2.DescribeNetworkInterfacesOutput{
+				NetworkInterfaces: []ec2types.NetworkInterface{{NetworkInterfaceId: aws.String("eni-coredns")}},
+			}, nil)
+
+			c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
+			fakeClientSet = newOperableClientSet()
+			c.SetNewClientSet(func() (kubernetes.Interface, error) {
+				return fakeClientSet, nil
+			})
+
+			err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
+			Expect(err).To(MatchError(`timed out waiting for Fargate network interfaces in "vpc-1234" to be deleted: eni-coredns`))
+			Expect(fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("when the cluster is inoperable", func() {
 		It("deletes the cluster without trying to query kubernetes", func() {
 			//mocks are in order of being called
@@ -302,12 +407,80 @@ var _ = Describe("Delete", func() {
 
 			c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
 
-			err := c.Delete(context.Background(), time.Microsecond, false, false, false, 1)
+			err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 			Expect(ranDeleteDeprecatedTasks).To(BeTrue())
 			Expect(fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsCallCount()).To(Equal(1))
 			Expect(ranDeleteClusterTasks).To(BeTrue())
+			p.MockEC2().AssertNotCalled(GinkgoT(), "DescribeKeyPairs", mock.Anything, mock.Anything)
+		})
+
+		When("continueAfterDeprecatedStacks is set to true", func() {
+			It("deletes the remaining shared resources after deleting deprecated stacks", func() {
+				p.MockEKS().On("DescribeCluster", mock.Anything).Return(&awseks.DescribeClusterOutput{
+					Cluster: testutils.NewFakeCluster(clusterName, awseks.ClusterStatusFailed),
+				}, nil)
+
+				fakeStackManager.DeleteTasksForDeprecatedStacksReturns(&tasks.TaskTree{
+					Tasks: []tasks.Task{&tasks.GenericTask{Doer: func() error {
+						ranDeleteDeprecatedTasks = true
+						return nil
+					}}},
+				}, nil)
+
+				p.MockEC2().On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{}, nil)
+
+				fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsReturns(&tasks.TaskTree{
+					Tasks: []tasks.Task{&tasks.GenericTask{Doer: func() error {
+						ranDeleteClusterTasks = true
+						return nil
+					}}},
+				}, nil)
+
+				c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
+
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, ContinueAfterDeprecatedStacks: true, Parallel: 1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ranDeleteDeprecatedTasks).To(BeTrue())
+				p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeKeyPairs", 1)
+				Expect(ranDeleteClusterTasks).To(BeTrue())
+			})
+		})
+
+		When("RBAC does not allow draining nodegroups", func() {
+			It("still drains the nodegroups", func() {
+				ctl.Status = &eks.ProviderStatus{
+					ClusterInfo: &eks.ClusterInfo{
+						Cluster: &awseks.Cluster{
+							Status:  aws.String(awseks.ClusterStatusActive),
+							Version: aws.String("1.21"),
+						},
+					},
+				}
+				p.MockEKS().On("DescribeCluster", mock.Anything).Return(&awseks.DescribeClusterOutput{
+					Cluster: testutils.NewFakeCluster(clusterName, awseks.ClusterStatusActive),
+				}, nil)
+
+				fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1"}}, nil)
+
+				c := cluster.NewOwnedCluster(cfg, ctl, nil, fakeStackManager)
+				fakeClientSet = newUnauthorizedClientSet()
+				c.SetNewClientSet(func() (kubernetes.Interface, error) {
+					return fakeClientSet, nil
+				})
+
+				mockedDrainer := &drainerMockOwned{}
+				mockedDrainer.On("Drain", mock.Anything).Return(errors.New("cannot evict pods"))
+				c.SetNewNodeGroupManager(func(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) cluster.NodeGroupDrainer {
+					return mockedDrainer
+				})
+
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
+				Expect(err).To(MatchError("cannot evict pods"))
+				mockedDrainer.AssertNumberOfCalls(GinkgoT(), "Drain", 1)
+				Expect(fakeStackManager.NewTasksToDeleteClusterWithNodeGroupsCallCount()).To(Equal(0))
+			})
 		})
 	})
 })
@@ -3,8 +3,10 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
@@ -44,27 +46,43 @@ func NewUnownedCluster(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, stackMa
 	}
 }
 
-func (c *UnownedCluster) Upgrade(_ context.Context, dryRun bool) error {
-	versionUpdateRequired, err := upgrade(c.cfg, c.ctl, dryRun)
+func (c *UnownedCluster) Upgrade(ctx context.Context, dryRun bool, planHash string) error {
+	currentVersion := c.ctl.ControlPlaneVersion()
+	versionUpdateRequired, err := requiresVersionUpgrade(c.cfg.Metadata, currentVersion)
 	if err != nil {
 		return err
 	}
 
+	plan := newUpgradePlan(c.cfg.Metadata.Name, currentVersion, c.cfg.Metadata.Version, "", nil)
+	if err := reviewUpgradePlan(plan, dryRun, planHash, os.Stdout); err != nil {
+		return err
+	}
+
+	if err := upgrade(c.cfg, c.ctl, currentVersion, versionUpdateRequired, dryRun); err != nil {
+		return err
+	}
+
+	if !dryRun {
+		checkOperability(ctx, c.cfg, c.ctl, c.newClientSet)
+	}
+
 	// if no version update is required, don't log asking them to rerun with --approve
 	cmdutils.LogPlanModeWarning(dryRun && versionUpdateRequired)
 	return nil
 }
 
-func (c *UnownedCluster) Delete(ctx context.Context, waitInterval time.Duration, wait, force, disableNodegroupEviction bool, parallel int) error {
+func (c *UnownedCluster) Delete(ctx context.Context, options DeleteOptions) error {
 	clusterName := c.cfg.Metadata.Name
 
-	if err := c.checkClusterExists(clusterName); err != nil {
+	cluster, err := c.checkClusterExists(clusterName)
+	if err != nil {
 		return err
 	}
 
-	clusterOperable, err := c.ctl.CanOperate(c.cfg)
-	if err != nil {
-		logger.Debug("failed to check if cluster is operable: %v", err)
+	c.warnEndpointAccessMismatch(cluster)
+	clusterOperable := checkOperability(ctx, c.cfg, c.ctl, c.newClientSet)
+	if !clusterOperable {
+		logger.Warning("nodegroups will not be drained before they are deleted")
 	}
 
 	allStacks, err := c.stackManager.ListNodeGroupStacks()
@@ -80,8 +98,8 @@ func (c *UnownedCluster) Delete(ctx context.Context, waitInterval time.Duration,
 		}
 
 		nodeGroupManager := c.newNodeGroupManager(c.cfg, c.ctl, clientSet)
-		if err := drainAllNodeGroups(c.cfg, c.ctl, clientSet, allStacks, disableNodegroupEviction, parallel, nodeGroupManager, attemptVpcCniDeletion); err != nil {
-			if !force {
+		if err := drainAllNodeGroups(ctx, c.cfg, c.ctl, clientSet, allStacks, options, nodeGroupManager, attemptVpcCniDeletion); err != nil {
+			if !options.Force {
 				return err
 			}
 
@@ -89,9 +107,9 @@ func (c *UnownedCluster) Delete(ctx context.Context, waitInterval time.Duration,
 		}
 	}
 
-	if err := deleteSharedResources(ctx, c.cfg, c.ctl, c.stackManager, clusterOperable, clientSet); err != nil {
+	if err := deleteSharedResources(ctx, c.cfg, c.ctl, c.stackManager, clusterOperable, options.ContinueAfterDeprecatedStacks, clientSet); err != nil {
 		if err != nil {
-			if force {
+			if options.Force {
 				logger.Warning("error occurred during deletion: %v", err)
 			} else {
 				return err
@@ -105,13 +123,13 @@ func (c *UnownedCluster) Delete(ctx context.Context, waitInterval time.Duration,
 
 	// we have to wait for nodegroups to delete before deleting the cluster
 	// so the `wait` value is ignored here
-	if err := c.deleteAndWaitForNodegroupsDeletion(waitInterval, allStacks); err != nil {
+	if err := c.deleteAndWaitForNodegroupsDeletion(options.WaitInterval, allStacks); err != nil {
 		return err
 	}
 
-	if err := c.deleteIAMAndOIDC(ctx, wait, clusterOperable, clientSet); err != nil {
+	if err := c.deleteIAMAndOIDC(ctx, options.Wait, clusterOperable, clientSet); err != nil {
 		if err != nil {
-			if force {
+			if options.Force {
 				logger.Warning("error occurred during deletion: %v", err)
 			} else {
 				return err
@@ -119,7 +137,7 @@ func (c *UnownedCluster) Delete(ctx context.Context, waitInterval time.Duration,
 		}
 	}
 
-	if err := c.deleteCluster(wait); err != nil {
+	if err := c.deleteCluster(options.Wait); err != nil {
 		return err
 	}
 
@@ -147,17 +165,33 @@ func (c *UnownedCluster) deleteFargateRoleIfExists() error {
 	return nil
 }
 
-func (c *UnownedCluster) checkClusterExists(clusterName string) error {
-	_, err := c.ctl.Provider.EKS().DescribeCluster(&awseks.DescribeClusterInput{
+func (c *UnownedCluster) checkClusterExists(clusterName string) (*awseks.Cluster, error) {
+	output, err := c.ctl.Provider.EKS().DescribeCluster(&awseks.DescribeClusterInput{
 		Name: &c.cfg.Metadata.Name,
 	})
 	if err != nil {
 		if isNotFound(err) {
-			return errors.Errorf("cluster %q not found", clusterName)
+			return nil, errors.Errorf("cluster %q not found", clusterName)
 		}
-		return errors.Wrapf(err, "error describing cluster %q", clusterName)
+		return nil, errors.Wrapf(err, "error describing cluster %q", clusterName)
+	}
+	return output.Cluster, nil
+}
+
+// warnEndpointAccessMismatch warns when the endpoint access of the cluster does not match the config
+func (c *UnownedCluster) warnEndpointAccessMismatch(cluster *awseks.Cluster) {
+	if cluster == nil || cluster.ResourcesVpcConfig == nil || c.cfg.VPC == nil || c.cfg.VPC.ClusterEndpoints == nil {
+		return
+	}
+	publicAccess := aws.BoolValue(cluster.ResourcesVpcConfig.EndpointPublicAccess)
+	privateAccess := aws.BoolValue(cluster.ResourcesVpcConfig.EndpointPrivateAccess)
+
+	endpoints := c.cfg.VPC.ClusterEndpoints
+	if (endpoints.PublicAccess != nil && *endpoints.PublicAccess != publicAccess) ||
+		(endpoints.PrivateAccess != nil && *endpoints.PrivateAccess != privateAccess) {
+		logger.Warning("the endpoint access of cluster %q (publicAccess=%v, privateAccess=%v) does not match the config (publicAccess=%v, privateAccess=%v)",
+			c.cfg.Metadata.Name, publicAccess, privateAccess, aws.BoolValue(endpoints.PublicAccess), aws.BoolValue(endpoints.PrivateAccess))
 	}
-	return nil
 }
 
 func (c *UnownedCluster) deleteIAMAndOIDC(ctx context.Context, wait bool, clusterOperable bool, clientSet kubernetes.Interface) error {
@@ -262,7 +296,7 @@ func (c *UnownedCluster) deleteAndWaitForNodegroupsDeletion(waitInterval time.Du
 	}
 
 	if len(allStacks) == 0 && len(nodeGroups.Nodegroups) == 0 {
-		logger.Warning("no nodegroups found for %s", clusterName)
+		logger.Info("no nodegroups to delete in cluster %q", clusterName)
 		return nil
 	}
 
@@ -272,17 +306,15 @@ func (c *UnownedCluster) deleteAndWaitForNodegroupsDeletion(waitInterval time.Du
 		return err
 	}
 
-	for _, n := range nodeGroups.Nodegroups {
-		isUnowned := func() bool {
-			for _, stack := range allStacks {
-				if stack.NodeGroupName == *n {
-					return false
-				}
-			}
-			return true
+	ownedManagedNodeGroups := map[string]bool{}
+	for _, stack := range allStacks {
+		if stack.Type == api.NodeGroupTypeManaged {
+			ownedManagedNodeGroups[stack.NodeGroupName] = true
 		}
+	}
 
-		if isUnowned() {
+	for _, n := range nodeGroups.Nodegroups {
+		if !ownedManagedNodeGroups[*n] {
 			// if a managed ng does not have a stack, we queue if for deletion via api
 			tasks.Append(c.stackManager.NewTaskToDeleteUnownedNodeGroup(clusterName, *n, eksAPI, c.waitForUnownedNgsDeletion(waitInterval)))
 		}
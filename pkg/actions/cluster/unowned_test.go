@@ -2,6 +2,8 @@ package cluster_test
 
 import (
 	"context"
+	"net"
+	"net/url"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -13,7 +15,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/weaveworks/eksctl/pkg/actions/cluster"
 	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
@@ -123,7 +127,10 @@ var _ = Describe("Delete", func() {
 				Nodegroups: aws.StringSlice([]string{"ng-1", "ng-2"}),
 			}, nil)
 
-			fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1"}}, nil)
+			fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{
+				{NodeGroupName: "ng-1", Type: api.NodeGroupTypeManaged},
+				{NodeGroupName: "ng-3", Type: api.NodeGroupTypeUnmanaged},
+			}, nil)
 
 			var deleteCallCount int
 			fakeStackManager.NewTasksToDeleteNodeGroupsReturns(&tasks.TaskTree{
@@ -152,10 +159,13 @@ var _ = Describe("Delete", func() {
 				return fakeClientSet, nil
 			})
 
-			err := c.Delete(context.Background(), time.Microsecond, false, false, false, 1)
+			err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(deleteCallCount).To(Equal(1))
 			Expect(unownedDeleteCallCount).To(Equal(1))
+			Expect(fakeStackManager.NewTaskToDeleteUnownedNodeGroupCallCount()).To(Equal(1))
+			_, unownedNodeGroupName, _, _ := fakeStackManager.NewTaskToDeleteUnownedNodeGroupArgsForCall(0)
+			Expect(unownedNodeGroupName).To(Equal("ng-2"))
 			Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 			Expect(ranDeleteDeprecatedTasks).To(BeTrue())
 			Expect(fakeStackManager.DeleteStackBySpecCallCount()).To(Equal(1))
@@ -211,7 +221,7 @@ var _ = Describe("Delete", func() {
 					Nodegroups: aws.StringSlice([]string{"ng-1", "ng-2"}),
 				}, nil)
 
-				fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1"}}, nil)
+				fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1", Type: api.NodeGroupTypeManaged}}, nil)
 
 				var deleteCallCount int
 				fakeStackManager.NewTasksToDeleteNodeGroupsReturns(&tasks.TaskTree{
@@ -236,7 +246,7 @@ var _ = Describe("Delete", func() {
 					},
 				}
 				c := cluster.NewUnownedCluster(cfg, ctl, fakeStackManager)
-				fakeClientSet := fake.NewSimpleClientset()
+				fakeClientSet := newOperableClientSet()
 
 				c.SetNewClientSet(func() (kubernetes.Interface, error) {
 					return fakeClientSet, nil
@@ -254,7 +264,7 @@ var _ = Describe("Delete", func() {
 					return mockedDrainer
 				})
 
-				err := c.Delete(context.Background(), time.Microsecond, false, true, false, 1)
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Force: true, Parallel: 1})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deleteCallCount).To(Equal(0))
 				Expect(unownedDeleteCallCount).To(Equal(0))
@@ -314,7 +324,7 @@ var _ = Describe("Delete", func() {
 					Nodegroups: aws.StringSlice([]string{"ng-1", "ng-2"}),
 				}, nil)
 
-				fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1"}}, nil)
+				fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1", Type: api.NodeGroupTypeManaged}}, nil)
 
 				var deleteCallCount int
 				fakeStackManager.NewTasksToDeleteNodeGroupsReturns(&tasks.TaskTree{
@@ -331,7 +341,7 @@ var _ = Describe("Delete", func() {
 
 				p.MockEKS().On("DeleteCluster", mock.Anything).Return(&awseks.DeleteClusterOutput{}, nil)
 				c := cluster.NewUnownedCluster(cfg, ctl, fakeStackManager)
-				fakeClientSet := fake.NewSimpleClientset()
+				fakeClientSet := newOperableClientSet()
 
 				c.SetNewClientSet(func() (kubernetes.Interface, error) {
 					return fakeClientSet, nil
@@ -357,7 +367,7 @@ var _ = Describe("Delete", func() {
 					return mockedDrainer
 				})
 
-				err := c.Delete(context.Background(), time.Microsecond, false, false, false, 1)
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
 				Expect(err).To(MatchError(errorMessage))
 				Expect(deleteCallCount).To(Equal(0))
 				Expect(unownedDeleteCallCount).To(Equal(0))
@@ -369,6 +379,91 @@ var _ = Describe("Delete", func() {
 		})
 	})
 
+	Context("when the cluster only allows private access to its endpoint", func() {
+		var (
+			c                *cluster.UnownedCluster
+			fakeClientSet    *fake.Clientset
+			drainerRequested bool
+		)
+
+		BeforeEach(func() {
+			fakeCluster := testutils.NewFakeCluster(clusterName, awseks.ClusterStatusActive)
+			fakeCluster.Endpoint = aws.String("https://private.eks.amazonaws.com")
+			fakeCluster.ResourcesVpcConfig.EndpointPublicAccess = aws.Bool(false)
+			fakeCluster.ResourcesVpcConfig.EndpointPrivateAccess = aws.Bool(true)
+			p.MockEKS().On("DescribeCluster", mock.MatchedBy(func(input *awseks.DescribeClusterInput) bool {
+				return *input.Name == clusterName
+			})).Return(&awseks.DescribeClusterOutput{
+				Cluster: fakeCluster,
+			}, nil)
+
+			fakeStackManager.DeleteTasksForDeprecatedStacksReturns(&tasks.TaskTree{}, nil)
+			p.MockEC2().On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{}, nil)
+			p.MockEC2().On("DescribeSecurityGroups", mock.Anything, mock.Anything).Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+			p.MockEKS().On("ListNodegroups", mock.Anything).Return(&awseks.ListNodegroupsOutput{}, nil)
+			fakeStackManager.ListNodeGroupStacksReturns(nil, nil)
+			fakeStackManager.NewTasksToDeleteNodeGroupsReturns(&tasks.TaskTree{}, nil)
+			fakeStackManager.NewTaskToDeleteUnownedNodeGroupReturns(&tasks.TaskTree{})
+			fakeStackManager.NewTaskToDeleteAddonIAMReturns(&tasks.TaskTree{}, nil)
+			p.MockEKS().On("DeleteCluster", mock.Anything).Return(&awseks.DeleteClusterOutput{}, nil)
+
+			ctl.Status = &eks.ProviderStatus{
+				ClusterInfo: &eks.ClusterInfo{
+					Cluster: fakeCluster,
+				},
+			}
+			c = cluster.NewUnownedCluster(cfg, ctl, fakeStackManager)
+			drainerRequested = false
+			c.SetNewClientSet(func() (kubernetes.Interface, error) {
+				return fakeClientSet, nil
+			})
+			c.SetNewNodeGroupManager(func(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) cluster.NodeGroupDrainer {
+				drainerRequested = true
+				mockedDrainer := &drainerMockUnowned{}
+				mockedDrainer.On("Drain", mock.Anything).Return(nil)
+				return mockedDrainer
+			})
+		})
+
+		When("the endpoint is unreachable from the current network", func() {
+			It("skips draining and deletes the cluster", func() {
+				fakeClientSet = fake.NewSimpleClientset()
+				fakeClientSet.PrependReactor("create", "selfsubjectaccessreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, &url.Error{Op: "Post", URL: "https://private.eks.amazonaws.com", Err: &net.DNSError{Err: "no such host", Name: "private.eks.amazonaws.com", IsNotFound: true}}
+				})
+
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(drainerRequested).To(BeFalse())
+				Expect(p.MockEKS().AssertNumberOfCalls(GinkgoT(), "DeleteCluster", 1)).To(BeTrue())
+			})
+		})
+
+		When("the endpoint is reachable from the current network", func() {
+			BeforeEach(func() {
+				p.MockEKS().On("DeleteAddon", mock.Anything).Return(&awseks.DeleteAddonOutput{}, nil)
+				p.MockEKS().On("ListFargateProfiles", mock.Anything).Return(&awseks.ListFargateProfilesOutput{}, nil)
+				p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cloudformation.DescribeStacksOutput{}, nil)
+			})
+
+			It("drains the nodegroups", func() {
+				fakeClientSet = newOperableClientSet()
+
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Force: true, Parallel: 1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(drainerRequested).To(BeTrue())
+			})
+
+			It("drains the nodegroups even if RBAC does not grant all the permissions to drain them", func() {
+				fakeClientSet = newUnauthorizedClientSet()
+
+				err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Force: true, Parallel: 1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(drainerRequested).To(BeTrue())
+			})
+		})
+	})
+
 	Context("when the cluster is inoperable", func() {
 		It("deletes the cluster without trying to query kubernetes", func() {
 			//mocks are in order of being called
@@ -394,7 +489,7 @@ var _ = Describe("Delete", func() {
 				Nodegroups: aws.StringSlice([]string{"ng-1", "ng-2"}),
 			}, nil)
 
-			fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1"}}, nil)
+			fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{NodeGroupName: "ng-1", Type: api.NodeGroupTypeManaged}}, nil)
 
 			var deleteCallCount int
 			fakeStackManager.NewTasksToDeleteNodeGroupsReturns(&tasks.TaskTree{
@@ -421,7 +516,7 @@ var _ = Describe("Delete", func() {
 			p.MockEKS().On("DeleteCluster", mock.Anything).Return(&awseks.DeleteClusterOutput{}, nil)
 
 			c := cluster.NewUnownedCluster(cfg, ctl, fakeStackManager)
-			err := c.Delete(context.Background(), time.Microsecond, false, false, false, 1)
+			err := c.Delete(context.Background(), cluster.DeleteOptions{WaitInterval: time.Microsecond, Parallel: 1})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStackManager.DeleteTasksForDeprecatedStacksCallCount()).To(Equal(1))
 			Expect(deleteCallCount).To(Equal(1))
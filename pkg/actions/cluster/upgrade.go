@@ -13,16 +13,10 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
-func upgrade(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, dryRun bool) (bool, error) {
-	currentVersion := ctl.ControlPlaneVersion()
-	versionUpdateRequired, err := requiresVersionUpgrade(cfg.Metadata, currentVersion)
-	if err != nil {
-		return false, err
-	}
-
+func upgrade(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, currentVersion string, versionUpdateRequired, dryRun bool) error {
 	printer := printers.NewJSONPrinter()
 	if err := printer.LogObj(logger.Debug, "cfg.json = \\\n%s\n", cfg); err != nil {
-		return false, err
+		return err
 	}
 
 	if versionUpdateRequired {
@@ -30,7 +24,7 @@ func upgrade(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, dryRun bool) (boo
 		cmdutils.LogIntendedAction(dryRun, "upgrade cluster %q control plane from current version %q to %q", cfg.Metadata.Name, currentVersion, cfg.Metadata.Version)
 		if !dryRun {
 			if err := ctl.UpdateClusterVersionBlocking(cfg); err != nil {
-				return false, err
+				return err
 			}
 			logger.Success("cluster %q control plane has been upgraded to version %q", cfg.Metadata.Name, cfg.Metadata.Version)
 			logger.Info(msgNodeGroupsAndAddons)
@@ -38,7 +32,7 @@ func upgrade(cfg *api.ClusterConfig, ctl *eks.ClusterProvider, dryRun bool) (boo
 	} else {
 		logger.Info("no cluster version update required")
 	}
-	return versionUpdateRequired, nil
+	return nil
 }
 
 func requiresVersionUpgrade(clusterMeta *api.ClusterMeta, currentEKSVersion string) (bool, error) {
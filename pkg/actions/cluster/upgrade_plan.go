@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kris-nova/logger"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// UpgradePlan describes the changes `eksctl upgrade cluster` makes to a cluster
+type UpgradePlan struct {
+	ClusterName    string
+	CurrentVersion string
+	TargetVersion  string
+	// StackName is the name of the cluster stack, it is empty if the cluster was not created by eksctl
+	StackName string
+	// StackDiff holds the resources of the cluster stack that differ from the template rendered for TargetVersion
+	StackDiff *manager.TemplateDiff
+	// AddonUpdates are the default addons that need to be updated after the control plane
+	AddonUpdates []string
+}
+
+func newUpgradePlan(clusterName, currentVersion, targetVersion, stackName string, stackDiff *manager.TemplateDiff) *UpgradePlan {
+	return &UpgradePlan{
+		ClusterName:    clusterName,
+		CurrentVersion: currentVersion,
+		TargetVersion:  targetVersion,
+		StackName:      stackName,
+		StackDiff:      stackDiff,
+		AddonUpdates:   defaultaddons.AddonsToUpdateForVersion(currentVersion, targetVersion),
+	}
+}
+
+// Render writes a summary of the plan, the output only depends on the plan so that it can be compared across runs
+func (p *UpgradePlan) Render(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "upgrade plan for cluster %q\n", p.ClusterName)
+	if p.CurrentVersion == p.TargetVersion {
+		fmt.Fprintf(&b, "control plane version: %s (no change)\n", p.CurrentVersion)
+	} else {
+		fmt.Fprintf(&b, "control plane version: %s -> %s\n", p.CurrentVersion, p.TargetVersion)
+	}
+
+	switch {
+	case p.StackDiff == nil:
+		b.WriteString("cluster stack: not managed by eksctl\n")
+	case p.StackDiff.IsEmpty():
+		fmt.Fprintf(&b, "cluster stack %q: no changes\n", p.StackName)
+	default:
+		fmt.Fprintf(&b, "cluster stack %q:\n", p.StackName)
+		writeChanges := func(prefix string, changes []manager.ResourceChange) {
+			for _, c := range changes {
+				fmt.Fprintf(&b, "  %s %s (%s)\n", prefix, c.LogicalID, c.Type)
+			}
+		}
+		writeChanges("+", p.StackDiff.Added)
+		writeChanges("~", p.StackDiff.Changed)
+		writeChanges("-", p.StackDiff.Removed)
+		if len(p.StackDiff.Changed) > 0 || len(p.StackDiff.Removed) > 0 {
+			b.WriteString("  note: only added resources (+) are applied to the stack\n")
+		}
+	}
+
+	if len(p.AddonUpdates) == 0 {
+		b.WriteString("addons to update: none\n")
+	} else {
+		fmt.Fprintf(&b, "addons to update: %s\n", strings.Join(p.AddonUpdates, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Hash identifies the plan, so that it can be applied only if it is the one that was reviewed
+func (p *UpgradePlan) Hash() (string, error) {
+	var b bytes.Buffer
+	if err := p.Render(&b); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b.Bytes())
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// reviewUpgradePlan writes the plan to out in plan mode, otherwise it ensures the plan has not diverged from the one
+// identified by planHash, if set
+func reviewUpgradePlan(plan *UpgradePlan, dryRun bool, planHash string, out io.Writer) error {
+	hash, err := plan.Hash()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		if err := plan.Render(out); err != nil {
+			return err
+		}
+		logger.Info("to apply this plan, re-run with --approve --plan-hash=%s", hash)
+		return nil
+	}
+
+	if planHash != "" && planHash != hash {
+		return fmt.Errorf("the upgrade plan of cluster %q has changed since it was reviewed (plan hash %q, expected %q); re-run without --approve to review the new plan", plan.ClusterName, hash, planHash)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+var _ = Describe("upgrade plan", func() {
+	var plan *UpgradePlan
+
+	BeforeEach(func() {
+		plan = newUpgradePlan("my-cluster", "1.21", "1.22", "eksctl-my-cluster-cluster", &manager.TemplateDiff{
+			Added: []manager.ResourceChange{
+				{LogicalID: "IngressDefaultClusterToNodeSG", Type: "AWS::EC2::SecurityGroupIngress"},
+			},
+			Changed: []manager.ResourceChange{
+				{LogicalID: "ControlPlane", Type: "AWS::EKS::Cluster"},
+			},
+		})
+	})
+
+	It("renders the changes of the plan", func() {
+		var out bytes.Buffer
+		Expect(plan.Render(&out)).To(Succeed())
+
+		golden, err := os.ReadFile("testdata/upgrade_plan.golden")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(string(golden)))
+	})
+
+	It("renders clusters that were not created by eksctl", func() {
+		plan = newUpgradePlan("my-cluster", "1.22", "1.22", "", nil)
+		var out bytes.Buffer
+		Expect(plan.Render(&out)).To(Succeed())
+		Expect(out.String()).To(Equal(`upgrade plan for cluster "my-cluster"
+control plane version: 1.22 (no change)
+cluster stack: not managed by eksctl
+addons to update: none
+`))
+	})
+
+	It("prints the plan and its hash in plan mode", func() {
+		var out bytes.Buffer
+		Expect(reviewUpgradePlan(plan, true, "", &out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("control plane version: 1.21 -> 1.22"))
+	})
+
+	It("applies the plan that was reviewed", func() {
+		hash, err := plan.Hash()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(HaveLen(12))
+
+		var out bytes.Buffer
+		Expect(reviewUpgradePlan(plan, false, hash, &out)).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("refuses to apply a plan that has diverged from the one reviewed", func() {
+		hash, err := plan.Hash()
+		Expect(err).NotTo(HaveOccurred())
+
+		plan.StackDiff.Added = append(plan.StackDiff.Added, manager.ResourceChange{LogicalID: "PolicyELBPermissions", Type: "AWS::IAM::Policy"})
+		err = reviewUpgradePlan(plan, false, hash, &bytes.Buffer{})
+		Expect(err).To(MatchError(ContainSubstring(`the upgrade plan of cluster "my-cluster" has changed since it was reviewed`)))
+	})
+})
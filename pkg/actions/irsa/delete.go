@@ -1,18 +1,55 @@
 package irsa
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
 )
 
+// maxParallelDeletions is the maximum number of iamserviceaccounts that are deleted at the same time
+const maxParallelDeletions = 10
+
+// DeleteOptions selects the iamserviceaccounts to delete in bulk, instead of naming each of them
+type DeleteOptions struct {
+	// Namespace limits the iamserviceaccounts to those in a namespace, all namespaces are used when empty
+	Namespace string
+	// All selects all iamserviceaccounts of Namespace
+	All bool
+	// Selector is a label selector matched against the labels of the ServiceAccounts
+	Selector string
+}
+
+// IsBulk returns true if the options select iamserviceaccounts in bulk
+func (o DeleteOptions) IsBulk() bool {
+	return o.All || o.Selector != ""
+}
+
+func (o DeleteOptions) describe() string {
+	var criteria []string
+	if o.Namespace != "" {
+		criteria = append(criteria, fmt.Sprintf("namespace %q", o.Namespace))
+	}
+	if o.Selector != "" {
+		criteria = append(criteria, fmt.Sprintf("selector %q", o.Selector))
+	}
+	return strings.Join(criteria, " and ")
+}
+
 func (m *Manager) Delete(serviceAccounts []string, plan, wait bool) error {
 	taskTree, err := m.stackManager.NewTasksToDeleteIAMServiceAccounts(serviceAccounts, kubernetes.NewCachedClientSet(m.clientSet), wait)
 	if err != nil {
 		return err
 	}
 	taskTree.PlanMode = plan
+	taskTree.MaxParallel = maxParallelDeletions
 
 	logger.Info(taskTree.Describe())
 	if errs := taskTree.DoAllSync(); len(errs) > 0 {
@@ -26,3 +63,57 @@ func (m *Manager) Delete(serviceAccounts []string, plan, wait bool) error {
 	logPlanModeWarning(plan && taskTree.Len() > 0)
 	return nil
 }
+
+// DeleteMatching deletes the iamserviceaccounts selected by options, after printing the list of iamserviceaccounts
+// that are deleted
+func (m *Manager) DeleteMatching(ctx context.Context, options DeleteOptions, plan, wait bool) error {
+	serviceAccounts, err := m.FindServiceAccountsToDelete(ctx, options)
+	if err != nil {
+		return err
+	}
+	if len(serviceAccounts) == 0 {
+		logger.Info("no iamserviceaccounts match %s", options.describe())
+		return nil
+	}
+
+	logger.Info("%d iamserviceaccount(s) match %s and will be deleted: %s", len(serviceAccounts), options.describe(), strings.Join(serviceAccounts, ", "))
+	return m.Delete(serviceAccounts, plan, wait)
+}
+
+// FindServiceAccountsToDelete returns the names of the iamserviceaccounts selected by options, in the form
+// namespace/name. The iamserviceaccounts are found using the tags of their stacks, and when a selector is set, only
+// those whose ServiceAccount has matching labels are returned
+func (m *Manager) FindServiceAccountsToDelete(ctx context.Context, options DeleteOptions) ([]string, error) {
+	remoteServiceAccounts, err := m.stackManager.GetIAMServiceAccounts()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting iamserviceaccounts")
+	}
+
+	if options.Namespace != "" {
+		remoteServiceAccounts = filterByNamespace(remoteServiceAccounts, options.Namespace)
+	}
+
+	var matching sets.String
+	if options.Selector != "" {
+		serviceAccounts, err := m.clientSet.CoreV1().ServiceAccounts(options.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: options.Selector,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing serviceaccounts matching %q", options.Selector)
+		}
+		matching = sets.NewString()
+		for _, sa := range serviceAccounts.Items {
+			matching.Insert(sa.Namespace + "/" + sa.Name)
+		}
+	}
+
+	var names []string
+	for _, sa := range remoteServiceAccounts {
+		name := sa.NameString()
+		if matching == nil || matching.Has(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
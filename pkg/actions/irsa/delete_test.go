@@ -0,0 +1,143 @@
+package irsa_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/kris-nova/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/actions/irsa"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+var _ = Describe("Delete", func() {
+
+	var (
+		irsaManager      *irsa.Manager
+		fakeStackManager *fakes.FakeStackManager
+		taskTree         *tasks.TaskTree
+		output           *bytes.Buffer
+		loggerWriter     io.Writer
+	)
+
+	newServiceAccount := func(namespace, name string, labels map[string]string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+		}
+	}
+
+	newIAMServiceAccount := func(namespace, name string) *api.ClusterIAMServiceAccount {
+		return &api.ClusterIAMServiceAccount{
+			ClusterIAMMeta: api.ClusterIAMMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeStackManager = new(fakes.FakeStackManager)
+		fakeStackManager.GetIAMServiceAccountsReturns([]*api.ClusterIAMServiceAccount{
+			newIAMServiceAccount("team-a", "sa-2"),
+			newIAMServiceAccount("team-a", "sa-1"),
+			newIAMServiceAccount("team-b", "sa-3"),
+		}, nil)
+		taskTree = &tasks.TaskTree{Parallel: true}
+		taskTree.Append(&tasks.TaskWithoutParams{Info: "delete IAM role for serviceaccount"})
+		fakeStackManager.NewTasksToDeleteIAMServiceAccountsReturns(taskTree, nil)
+
+		clientSet := fake.NewSimpleClientset(
+			newServiceAccount("team-a", "sa-1", map[string]string{"app": "legacy"}),
+			newServiceAccount("team-a", "sa-2", map[string]string{"app": "web"}),
+			newServiceAccount("team-b", "sa-3", map[string]string{"app": "legacy"}),
+			newServiceAccount("team-b", "not-irsa", map[string]string{"app": "legacy"}),
+		)
+		irsaManager = irsa.New("my-cluster", fakeStackManager, nil, clientSet)
+
+		output = &bytes.Buffer{}
+		loggerWriter = logger.Writer
+		logger.Writer = output
+	})
+
+	AfterEach(func() {
+		logger.Writer = loggerWriter
+	})
+
+	When("all iamserviceaccounts of a namespace are selected", func() {
+		It("returns the iamserviceaccounts of the namespace", func() {
+			serviceAccounts, err := irsaManager.FindServiceAccountsToDelete(context.Background(), irsa.DeleteOptions{
+				Namespace: "team-a",
+				All:       true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceAccounts).To(Equal([]string{"team-a/sa-1", "team-a/sa-2"}))
+		})
+	})
+
+	When("a label selector is set", func() {
+		It("returns the iamserviceaccounts whose serviceaccount matches it in all namespaces", func() {
+			serviceAccounts, err := irsaManager.FindServiceAccountsToDelete(context.Background(), irsa.DeleteOptions{
+				Selector: "app=legacy",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceAccounts).To(Equal([]string{"team-a/sa-1", "team-b/sa-3"}))
+		})
+
+		It("only returns those of the namespace when it is set", func() {
+			serviceAccounts, err := irsaManager.FindServiceAccountsToDelete(context.Background(), irsa.DeleteOptions{
+				Namespace: "team-b",
+				Selector:  "app=legacy",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceAccounts).To(Equal([]string{"team-b/sa-3"}))
+		})
+
+		It("returns nothing when no serviceaccount matches it", func() {
+			serviceAccounts, err := irsaManager.FindServiceAccountsToDelete(context.Background(), irsa.DeleteOptions{
+				Selector: "app=unknown",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceAccounts).To(BeEmpty())
+		})
+	})
+
+	When("deleting the matching iamserviceaccounts", func() {
+		It("prints the plan and deletes them with a bounded number of parallel tasks", func() {
+			err := irsaManager.DeleteMatching(context.Background(), irsa.DeleteOptions{
+				Namespace: "team-a",
+				All:       true,
+			}, true, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.String()).To(ContainSubstring(`2 iamserviceaccount(s) match namespace "team-a" and will be deleted: team-a/sa-1, team-a/sa-2`))
+			Expect(output.String()).To(ContainSubstring("no changes were applied, run again with '--approve' to apply the changes"))
+
+			Expect(fakeStackManager.NewTasksToDeleteIAMServiceAccountsCallCount()).To(Equal(1))
+			serviceAccounts, _, wait := fakeStackManager.NewTasksToDeleteIAMServiceAccountsArgsForCall(0)
+			Expect(serviceAccounts).To(Equal([]string{"team-a/sa-1", "team-a/sa-2"}))
+			Expect(wait).To(BeFalse())
+			Expect(taskTree.MaxParallel).To(Equal(10))
+		})
+
+		It("does not delete anything when no iamserviceaccount matches", func() {
+			err := irsaManager.DeleteMatching(context.Background(), irsa.DeleteOptions{
+				Namespace: "team-c",
+				All:       true,
+			}, false, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.String()).To(ContainSubstring(`no iamserviceaccounts match namespace "team-c"`))
+			Expect(fakeStackManager.NewTasksToDeleteIAMServiceAccountsCallCount()).To(Equal(0))
+		})
+	})
+})
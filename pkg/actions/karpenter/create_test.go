@@ -337,6 +337,26 @@ var _ = Describe("Create", func() {
 				Expect(accounts[0].AttachPolicyARNs).To(ConsistOf(policyARN))
 			})
 		})
+		When("the cluster is in the aws-us-gov partition", func() {
+			BeforeEach(func() {
+				cfg.Status.ARN = "arn:aws-us-gov:iam::123456789012:user/test"
+			})
+			It("uses the partition of the cluster in the ARNs of the IAM resources", func() {
+				fakeKarpenterInstaller.InstallReturns(nil)
+				install := &karpenteractions.Installer{
+					StackManager:       fakeStackManager,
+					CTL:                ctl,
+					Config:             cfg,
+					KarpenterInstaller: fakeKarpenterInstaller,
+					ClientSet:          fakeClientSet,
+				}
+				Expect(install.Create(context.Background())).To(Succeed())
+				accounts, _, _ := fakeStackManager.NewTasksToCreateIAMServiceAccountsArgsForCall(0)
+				Expect(accounts).NotTo(BeEmpty())
+				policyARN := fmt.Sprintf("arn:aws-us-gov:iam::123456789012:policy/eksctl-%s-%s", builder.KarpenterManagedPolicy, cfg.Metadata.Name)
+				Expect(accounts[0].AttachPolicyARNs).To(ConsistOf(policyARN))
+			})
+		})
 		When("defaultInstanceProfile is not set", func() {
 			BeforeEach(func() {
 				cfg.Karpenter.DefaultInstanceProfile = nil
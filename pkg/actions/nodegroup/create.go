@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/blang/semver"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
@@ -13,7 +15,9 @@ import (
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/iam"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
@@ -30,9 +34,27 @@ type CreateOpts struct {
 	InstallNvidiaDevicePlugin bool
 	DryRun                    bool
 	SkipOutdatedAddonsCheck   bool
+	SkipVersionSkewCheck      bool
+	SkipUserDataValidation    bool
+	StrictDeprecations        bool
 	ConfigFileProvided        bool
+	// WaitForInstanceProfile waits for the existing instance profiles of unmanaged nodegroups to be retrievable via
+	// IAM before creating their stacks
+	WaitForInstanceProfile bool
+	// AMIMaxAge is the maximum age of the AMIs resolved for the nodegroups; if it is zero, deprecated AMIs are only
+	// warned about
+	AMIMaxAge time.Duration
 }
 
+const (
+	// maxVersionSkew is the number of minor versions nodes may lag behind the control plane
+	maxVersionSkew = 2
+	// maxVersionSkewFrom1_28 is the number of minor versions nodes may lag behind a 1.28+ control plane
+	maxVersionSkewFrom1_28 = 3
+	// instanceProfileWaitTimeout bounds the wait for an instance profile to propagate
+	instanceProfileWaitTimeout = 2 * time.Minute
+)
+
 // Create creates a new nodegroup with the given options.
 func (m *Manager) Create(ctx context.Context, options CreateOpts, nodegroupFilter filter.NodegroupFilter) error {
 	cfg := m.cfg
@@ -44,6 +66,11 @@ func (m *Manager) Create(ctx context.Context, options CreateOpts, nodegroupFilte
 		return err
 	}
 
+	// the version of the nodegroups is only known once it has been resolved from the control plane
+	if err := api.ValidateAMIFamilyDeprecations(cfg, options.StrictDeprecations); err != nil {
+		return err
+	}
+
 	if err := m.checkARMSupport(ctl, m.clientSet, cfg, options.SkipOutdatedAddonsCheck); err != nil {
 		return err
 	}
@@ -71,7 +98,7 @@ func (m *Manager) Create(ctx context.Context, options CreateOpts, nodegroupFilte
 	}
 
 	if !options.DryRun {
-		if err := m.init.Normalize(ctx, nodePools, cfg.Metadata); err != nil {
+		if err := m.init.Normalize(ctx, nodePools, cfg.Metadata, options.AMIMaxAge); err != nil {
 			return err
 		}
 	}
@@ -97,6 +124,23 @@ func (m *Manager) Create(ctx context.Context, options CreateOpts, nodegroupFilte
 
 	logFiltered := cmdutils.ApplyFilter(cfg, nodegroupFilter)
 	logFiltered()
+
+	if err := m.init.CheckMaxPods(ctx, cfg); err != nil {
+		return err
+	}
+
+	if !options.SkipVersionSkewCheck {
+		if err := checkVersionSkew(ctl.ControlPlaneVersion(), cfg); err != nil {
+			return err
+		}
+	}
+
+	if !options.SkipUserDataValidation {
+		if err := validateUserData(cfg); err != nil {
+			return err
+		}
+	}
+
 	logMsg := func(resource string, count int) {
 		logger.Info("will create a CloudFormation stack for each of %d %s in cluster %q", count, resource, meta.Name)
 	}
@@ -119,6 +163,16 @@ func (m *Manager) Create(ctx context.Context, options CreateOpts, nodegroupFilte
 		return cmdutils.PrintNodeGroupDryRunConfig(clusterConfigCopy, os.Stdout)
 	}
 
+	if err := m.init.EnsureServiceLinkedRoles(ctx, cfg); err != nil {
+		return err
+	}
+
+	if options.WaitForInstanceProfile {
+		if err := m.waitForInstanceProfiles(ctx); err != nil {
+			return err
+		}
+	}
+
 	if err := m.nodeCreationTasks(ctx, isOwnedCluster); err != nil {
 		return err
 	}
@@ -156,9 +210,18 @@ func (m *Manager) nodeCreationTasks(ctx context.Context, isOwnedCluster bool) er
 		logger.Debug("cluster has withOIDC enabled but is not using IRSA for CNI, will add CNI policy to node role")
 	}
 
+	if !awsNodeUsesIRSA {
+		for _, ng := range cfg.AllNodeGroups() {
+			if ng.IAM != nil && ng.IAM.AttachCNIPolicy == api.AttachCNIPolicyNever {
+				return fmt.Errorf("nodegroup %q has iam.attachCNIPolicy set to %s, but the aws-node service account does not use IRSA; "+
+					"create an IAM service account for aws-node with the AmazonEKS_CNI_Policy first", ng.Name, api.AttachCNIPolicyNever)
+			}
+		}
+	}
+
 	var vpcImporter vpc.Importer
 	if isOwnedCluster {
-		vpcImporter = vpc.NewStackConfigImporter(m.stackManager.MakeClusterStackName())
+		vpcImporter = vpc.NewStackConfigImporter(m.stackManager.MakeClusterStackName(), cfg.VPC)
 	} else {
 		vpcImporter = vpc.NewSpecConfigImporter(*m.ctl.Status.ClusterInfo.Cluster.ResourcesVpcConfig.ClusterSecurityGroupId, cfg.VPC)
 	}
@@ -179,6 +242,22 @@ func (m *Manager) nodeCreationTasks(ctx context.Context, isOwnedCluster bool) er
 	return m.init.DoAllNodegroupStackTasks(taskTree, meta.Region, meta.Name)
 }
 
+// waitForInstanceProfiles waits for the instance profiles set by iam.instanceProfileARN to be retrievable, so that the
+// Auto Scaling groups of the nodegroups do not fail to launch instances with an invalid IAM instance profile. The
+// instance profiles created by the nodegroup stacks are created before their Auto Scaling group by CloudFormation
+func (m *Manager) waitForInstanceProfiles(ctx context.Context) error {
+	for _, ng := range m.cfg.NodeGroups {
+		if ng.IAM == nil || ng.IAM.InstanceProfileARN == "" {
+			continue
+		}
+		logger.Info("waiting for instance profile %q of nodegroup %q to be retrievable", ng.IAM.InstanceProfileARN, ng.Name)
+		if err := iam.WaitForInstanceProfile(ctx, m.ctl.Provider.IAM(), ng.IAM.InstanceProfileARN, instanceProfileWaitTimeout); err != nil {
+			return errors.Wrapf(err, "nodegroup %q", ng.Name)
+		}
+	}
+	return nil
+}
+
 func (m *Manager) postNodeCreationTasks(clientSet kubernetes.Interface, options CreateOpts) error {
 	tasks := m.ctl.ClusterTasksForNodeGroups(m.cfg, options.InstallNeuronDevicePlugin, options.InstallNvidiaDevicePlugin)
 	logger.Info(tasks.Describe())
@@ -213,9 +292,20 @@ func (m *Manager) postNodeCreationTasks(clientSet kubernetes.Interface, options
 	}
 
 	logger.Success("created %d managed nodegroup(s) in cluster %q", len(m.cfg.ManagedNodeGroups), m.cfg.Metadata.Name)
+	logSSHUsers(m.cfg)
 	return nil
 }
 
+// logSSHUsers logs the user to SSH onto the nodes as for each nodegroup allowing SSH access
+func logSSHUsers(cfg *api.ClusterConfig) {
+	for _, np := range cmdutils.ToNodePools(cfg) {
+		ng := np.BaseNodeGroup()
+		if ng.SSH != nil && api.IsEnabled(ng.SSH.Allow) && ng.SSH.User != "" {
+			logger.Info("nodes of nodegroup %q can be accessed over SSH as user %q", ng.Name, ng.SSH.User)
+		}
+	}
+}
+
 func checkVersion(ctl *eks.ClusterProvider, meta *api.ClusterMeta) error {
 	switch meta.Version {
 	case "auto":
@@ -250,6 +340,61 @@ func checkVersion(ctl *eks.ClusterProvider, meta *api.ClusterMeta) error {
 	return nil
 }
 
+// checkVersionSkew validates that the version of each new nodegroup is supported by the control plane, i.e.
+// it is not newer than the control plane and lags behind it by no more than the Kubernetes version skew policy allows
+func checkVersionSkew(controlPlaneVersion string, cfg *api.ClusterConfig) error {
+	cpVersion, err := semver.ParseTolerant(controlPlaneVersion)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse control plane version %q", controlPlaneVersion)
+	}
+
+	maxSkew := maxVersionSkew
+	if cpVersion.Major == 1 && cpVersion.Minor >= 28 {
+		maxSkew = maxVersionSkewFrom1_28
+	}
+
+	check := func(ngName, ngVersion string) error {
+		v, err := semver.ParseTolerant(ngVersion)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse version %q of nodegroup %q", ngVersion, ngName)
+		}
+		const hint = "to ignore this check and proceed with the nodegroup creation, please run again with --skip-version-skew-check=true"
+		switch {
+		case v.Major != cpVersion.Major || v.Minor > cpVersion.Minor:
+			return fmt.Errorf("version %s of nodegroup %q is newer than control plane version %s; %s", ngVersion, ngName, controlPlaneVersion, hint)
+		case cpVersion.Minor-v.Minor > uint64(maxSkew):
+			return fmt.Errorf("version %s of nodegroup %q is more than %d minor versions older than control plane version %s; %s", ngVersion, ngName, maxSkew, controlPlaneVersion, hint)
+		}
+		return nil
+	}
+
+	for _, ng := range cfg.NodeGroups {
+		if err := check(ng.Name, cfg.Metadata.Version); err != nil {
+			return err
+		}
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		ngVersion := cfg.Metadata.Version
+		if ng.ReleaseVersion != "" {
+			ngVersion = strings.Split(ng.ReleaseVersion, "-")[0]
+		}
+		if err := check(ng.Name, ngVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateUserData validates the bootstrap commands and the size of the user data of each new nodegroup
+func validateUserData(cfg *api.ClusterConfig) error {
+	for _, np := range cmdutils.ToNodePools(cfg) {
+		if err := nodebootstrap.ValidateUserData(cfg, np); err != nil {
+			return fmt.Errorf("%w; to ignore this check and proceed with the nodegroup creation, please run again with --skip-userdata-validation=true", err)
+		}
+	}
+	return nil
+}
+
 func (m *Manager) checkARMSupport(ctl *eks.ClusterProvider, clientSet kubernetes.Interface, cfg *api.ClusterConfig, skipOutdatedAddonsCheck bool) error {
 	kubeProvider := m.kubeProvider
 	rawClient, err := kubeProvider.NewRawClient(cfg)
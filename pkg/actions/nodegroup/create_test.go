@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -21,16 +22,21 @@ import (
 )
 
 type ngEntry struct {
-	version       string
-	opts          nodegroup.CreateOpts
-	mockCalls     func(*fakes.FakeKubeProvider, *fakes.FakeNodeGroupInitialiser, *utilFakes.FakeNodegroupFilter)
-	expectedCalls func(*fakes.FakeKubeProvider, *fakes.FakeNodeGroupInitialiser, *utilFakes.FakeNodegroupFilter)
-	expErr        error
+	version             string
+	controlPlaneVersion string
+	updateClusterConfig func(*api.ClusterConfig)
+	opts                nodegroup.CreateOpts
+	mockCalls           func(*fakes.FakeKubeProvider, *fakes.FakeNodeGroupInitialiser, *utilFakes.FakeNodegroupFilter)
+	expectedCalls       func(*fakes.FakeKubeProvider, *fakes.FakeNodeGroupInitialiser, *utilFakes.FakeNodegroupFilter)
+	expErr              error
 }
 
 var _ = DescribeTable("Create", func(t ngEntry) {
 	cfg := newClusterConfig()
 	cfg.Metadata.Version = t.version
+	if t.updateClusterConfig != nil {
+		t.updateClusterConfig(cfg)
+	}
 
 	p := mockprovider.NewMockProvider()
 	ctl := &eks.ClusterProvider{
@@ -41,6 +47,9 @@ var _ = DescribeTable("Create", func(t ngEntry) {
 			},
 		},
 	}
+	if t.controlPlaneVersion != "" {
+		ctl.Status.ClusterInfo.Cluster.Version = aws.String(t.controlPlaneVersion)
+	}
 	m := nodegroup.New(cfg, ctl, nil)
 
 	k := &fakes.FakeKubeProvider{}
@@ -148,6 +157,105 @@ var _ = DescribeTable("Create", func(t ngEntry) {
 		expErr: errors.New("err"),
 	}),
 
+	Entry("fails when it cannot check the max pods of the nodegroups", ngEntry{
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			init.CheckMaxPodsReturns(errors.New("err"))
+		},
+		expectedCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			Expect(init.ValidateLegacySubnetsForNodeGroupsCallCount()).To(Equal(1))
+			Expect(f.SetOnlyLocalCallCount()).To(Equal(1))
+			Expect(init.CheckMaxPodsCallCount()).To(Equal(1))
+		},
+		expErr: errors.New("err"),
+	}),
+
+	Entry("fails when the nodegroup version is newer than the control plane version", ngEntry{
+		version:             api.Version1_22,
+		controlPlaneVersion: api.Version1_21,
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+		},
+		expErr: errors.New(`version 1.22 of nodegroup "my-ng" is newer than control plane version 1.21`),
+	}),
+
+	Entry("fails when the nodegroup version is too old for the control plane version", ngEntry{
+		version:             api.Version1_19,
+		controlPlaneVersion: api.Version1_22,
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+		},
+		expErr: errors.New(`version 1.19 of nodegroup "my-ng" is more than 2 minor versions older than control plane version 1.22`),
+	}),
+
+	Entry("does not fail when the nodegroup version is too old but the version skew check is skipped", ngEntry{
+		version:             api.Version1_19,
+		controlPlaneVersion: api.Version1_22,
+		opts: nodegroup.CreateOpts{
+			DryRun:               true,
+			SkipVersionSkewCheck: true,
+		},
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+		},
+	}),
+
+	Entry("does not fail when the nodegroup version is within the supported version skew", ngEntry{
+		version:             api.Version1_20,
+		controlPlaneVersion: api.Version1_22,
+		opts: nodegroup.CreateOpts{
+			DryRun: true,
+		},
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+		},
+		expectedCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			Expect(f.SetOnlyLocalCallCount()).To(Equal(1))
+			Expect(init.DoAllNodegroupStackTasksCallCount()).To(Equal(0))
+		},
+	}),
+
+	Entry("fails when the bootstrap commands of a nodegroup are invalid", ngEntry{
+		version:             api.Version1_22,
+		controlPlaneVersion: api.Version1_22,
+		updateClusterConfig: func(cfg *api.ClusterConfig) {
+			cfg.NodeGroups[0].PreBootstrapCommands = []string{"if [ -f /etc/ready ]; then echo ready"}
+		},
+		opts: nodegroup.CreateOpts{
+			DryRun: true,
+		},
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+		},
+		expErr: errors.New(`invalid preBootstrapCommands[0] of nodegroup "my-ng": line 1, column 1: if statement must end with "fi"; to ignore this check and proceed with the nodegroup creation, please run again with --skip-userdata-validation=true`),
+	}),
+
+	Entry("does not fail when the bootstrap commands of a nodegroup are invalid but the user data validation is skipped", ngEntry{
+		version:             api.Version1_22,
+		controlPlaneVersion: api.Version1_22,
+		updateClusterConfig: func(cfg *api.ClusterConfig) {
+			cfg.NodeGroups[0].PreBootstrapCommands = []string{"if [ -f /etc/ready ]; then echo ready"}
+		},
+		opts: nodegroup.CreateOpts{
+			DryRun:                 true,
+			SkipUserDataValidation: true,
+		},
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+		},
+	}),
+
+	Entry("fails when the required service-linked roles cannot be created", ngEntry{
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			init.EnsureServiceLinkedRolesReturns(errors.New("err"))
+		},
+		expectedCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			Expect(init.EnsureServiceLinkedRolesCallCount()).To(Equal(1))
+			Expect(init.DoesAWSNodeUseIRSACallCount()).To(Equal(0))
+			Expect(init.DoAllNodegroupStackTasksCallCount()).To(Equal(0))
+		},
+		expErr: errors.New("err"),
+	}),
+
 	Entry("fails to evaluate whether aws-node uses IRSA", ngEntry{
 		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
 			init.DoesAWSNodeUseIRSAReturns(true, errors.New("err"))
@@ -165,6 +273,21 @@ var _ = DescribeTable("Create", func(t ngEntry) {
 		expErr: errors.New("err"),
 	}),
 
+	Entry("fails when a nodegroup never attaches the CNI policy but aws-node does not use IRSA", ngEntry{
+		updateClusterConfig: func(c *api.ClusterConfig) {
+			c.ManagedNodeGroups[0].IAM = &api.NodeGroupIAM{AttachCNIPolicy: api.AttachCNIPolicyNever}
+		},
+		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			f.MatchReturns(true)
+			init.DoesAWSNodeUseIRSAReturns(false, nil)
+		},
+		expectedCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
+			Expect(init.DoesAWSNodeUseIRSACallCount()).To(Equal(1))
+			Expect(init.DoAllNodegroupStackTasksCallCount()).To(Equal(0))
+		},
+		expErr: errors.New(`nodegroup "my-ng" has iam.attachCNIPolicy set to never, but the aws-node service account does not use IRSA`),
+	}),
+
 	Entry("stack manager fails to do ng tasks", ngEntry{
 		mockCalls: func(k *fakes.FakeKubeProvider, init *fakes.FakeNodeGroupInitialiser, f *utilFakes.FakeNodegroupFilter) {
 			k.NewRawClientReturns(&kubernetes.RawClient{}, nil)
@@ -286,12 +409,14 @@ func newClusterConfig() *api.ClusterConfig {
 		PrivateCluster: &api.PrivateCluster{},
 		NodeGroups: []*api.NodeGroup{{
 			NodeGroupBase: &api.NodeGroupBase{
-				Name: "my-ng",
+				Name:      "my-ng",
+				AMIFamily: api.NodeImageFamilyAmazonLinux2,
 			}},
 		},
 		ManagedNodeGroups: []*api.ManagedNodeGroup{{
 			NodeGroupBase: &api.NodeGroupBase{
-				Name: "my-ng",
+				Name:      "my-ng",
+				AMIFamily: api.NodeImageFamilyAmazonLinux2,
 			}},
 		},
 	}
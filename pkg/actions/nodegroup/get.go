@@ -41,10 +41,13 @@ type Summary struct {
 	InstanceType         string
 	ImageID              string
 	CreationTime         time.Time
+	LastUpdatedTime      *time.Time
+	EksctlVersion        string
 	NodeInstanceRoleARN  string
 	AutoScalingGroupName string
 	Version              string
 	NodeGroupType        api.NodeGroupType `json:"Type"`
+	SSHUser              string            `json:"SSHUser,omitempty"`
 }
 
 func (m *Manager) GetAll(ctx context.Context) ([]*Summary, error) {
@@ -62,7 +65,12 @@ func (m *Manager) GetAll(ctx context.Context) ([]*Summary, error) {
 }
 
 func (m *Manager) Get(ctx context.Context, name string) (*Summary, error) {
-	summary, err := m.getUnmanagedSummary(ctx, name)
+	stack, err := m.stackManager.DescribeNodeGroupStack(name)
+	if err != nil {
+		return nil, fmt.Errorf("getting nodegroup stack summaries: %w", err)
+	}
+
+	summary, err := m.unmanagedStackToSummary(ctx, stack)
 	if err != nil {
 		return nil, fmt.Errorf("getting nodegroup stack summaries: %w", err)
 	}
@@ -71,7 +79,12 @@ func (m *Manager) Get(ctx context.Context, name string) (*Summary, error) {
 		return summary, nil
 	}
 
-	return m.getManagedSummary(ctx, name)
+	summary, err = m.getManagedSummary(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	setStackHistory(summary, stack)
+	return summary, nil
 }
 
 func (m *Manager) getManagedSummaries(ctx context.Context) ([]*Summary, error) {
@@ -95,6 +108,7 @@ func (m *Manager) getManagedSummaries(ctx context.Context) ([]*Summary, error) {
 			return nil, err
 		}
 		summary.StackName = aws.StringValue(stack.StackName)
+		setStackHistory(summary, stack)
 		summaries = append(summaries, summary)
 	}
 
@@ -122,15 +136,6 @@ func (m *Manager) getUnmanagedSummaries(ctx context.Context) ([]*Summary, error)
 	return summaries, nil
 }
 
-func (m *Manager) getUnmanagedSummary(ctx context.Context, name string) (*Summary, error) {
-	stack, err := m.stackManager.DescribeNodeGroupStack(name)
-	if err != nil {
-		return nil, err
-	}
-
-	return m.unmanagedStackToSummary(ctx, stack)
-}
-
 func (m *Manager) unmanagedStackToSummary(ctx context.Context, s *manager.Stack) (*Summary, error) {
 	nodeGroupType, err := manager.GetNodeGroupType(s.Tags)
 	if err != nil {
@@ -241,6 +246,9 @@ func (m *Manager) mapStackToNodeGroupSummary(stack *manager.Stack, ngPaths *node
 		InstanceType:    gjson.Get(template, ngPaths.InstanceType).String(),
 		ImageID:         gjson.Get(template, imageIDPath).String(),
 		CreationTime:    *stack.CreationTime,
+		LastUpdatedTime: stack.LastUpdatedTime,
+		EksctlVersion:   manager.GetEksctlVersionTag(stack.Tags),
+		SSHUser:         manager.GetSSHUserTag(stack.Tags),
 	}
 
 	nodeGroupType, err := manager.GetNodeGroupType(stack.Tags)
@@ -268,6 +276,19 @@ func (m *Manager) mapStackToNodeGroupSummary(stack *manager.Stack, ngPaths *node
 	return summary, nil
 }
 
+// setStackHistory sets the creation and update times of a managed nodegroup from its stack, if it has one, along with
+// the version of eksctl that last created or updated the stack and the SSH user. Otherwise, the times reported by EKS
+// are kept
+func setStackHistory(summary *Summary, stack *manager.Stack) {
+	if stack == nil || stack.CreationTime == nil {
+		return
+	}
+	summary.CreationTime = *stack.CreationTime
+	summary.LastUpdatedTime = stack.LastUpdatedTime
+	summary.EksctlVersion = manager.GetEksctlVersionTag(stack.Tags)
+	summary.SSHUser = manager.GetSSHUserTag(stack.Tags)
+}
+
 func getClusterNameTag(s *manager.Stack) string {
 	for _, tag := range s.Tags {
 		if *tag.Key == api.ClusterNameTag || *tag.Key == api.OldClusterNameTag {
@@ -314,6 +335,7 @@ func (m *Manager) getManagedSummary(ctx context.Context, nodeGroupName string) (
 		InstanceType:         m.getInstanceTypes(ctx, ng),
 		ImageID:              imageID,
 		CreationTime:         *ng.CreatedAt,
+		LastUpdatedTime:      ng.ModifiedAt,
 		NodeInstanceRoleARN:  *ng.NodeRole,
 		AutoScalingGroupName: strings.Join(asgs, ","),
 		Version:              getOptionalValue(ng.Version),
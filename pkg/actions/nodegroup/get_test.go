@@ -131,6 +131,29 @@ var _ = Describe("Get", func() {
 						NodeGroupType:        api.NodeGroupTypeManaged,
 					}))
 				})
+
+				It("returns the timestamps and eksctl version of the CF Stack", func() {
+					stackCreationTime := t.Add(-time.Minute)
+					stackLastUpdatedTime := t.Add(time.Hour)
+					fakeStackManager.DescribeNodeGroupStackReturns(&cloudformation.Stack{
+						StackName:       aws.String(stackName),
+						CreationTime:    aws.Time(stackCreationTime),
+						LastUpdatedTime: aws.Time(stackLastUpdatedTime),
+						Tags: []*cloudformation.Tag{
+							{
+								Key:   aws.String(api.EksctlVersionTag),
+								Value: aws.String("0.90.0"),
+							},
+						},
+					}, nil)
+
+					summaries, err := m.GetAll(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(summaries).To(HaveLen(1))
+					Expect(summaries[0].CreationTime).To(Equal(stackCreationTime))
+					Expect(summaries[0].LastUpdatedTime).To(Equal(aws.Time(stackLastUpdatedTime)))
+					Expect(summaries[0].EksctlVersion).To(Equal("0.90.0"))
+				})
 			})
 
 			When("a nodegroup is not associated to a CF Stack", func() {
@@ -0,0 +1,148 @@
+package nodegroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kris-nova/logger"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// PurgeLaunchTemplates deletes the launch templates tagged with the names of nodeGroupNames that are left behind once
+// the nodegroups are deleted. Launch templates still used by an Auto Scaling group of the cluster are kept, and in plan
+// mode the launch templates are only listed
+func (m *Manager) PurgeLaunchTemplates(ctx context.Context, nodeGroupNames []string, plan bool) error {
+	if len(nodeGroupNames) == 0 {
+		return nil
+	}
+
+	launchTemplates, err := m.findNodeGroupLaunchTemplates(ctx, nodeGroupNames)
+	if err != nil {
+		return err
+	}
+	if len(launchTemplates) == 0 {
+		logger.Info("no launch templates to purge for nodegroups %v", nodeGroupNames)
+		return nil
+	}
+
+	// in plan mode the Auto Scaling groups of the nodegroups have not been deleted yet
+	var deletedNodeGroups []string
+	if plan {
+		deletedNodeGroups = nodeGroupNames
+	}
+	inUse, err := m.launchTemplatesInUse(ctx, deletedNodeGroups)
+	if err != nil {
+		return err
+	}
+
+	for _, lt := range launchTemplates {
+		id, name := aws.StringValue(lt.LaunchTemplateId), aws.StringValue(lt.LaunchTemplateName)
+		if inUse[id] || inUse[name] {
+			logger.Info("keeping launch template %q (%s) as it is still used by an Auto Scaling group", name, id)
+			continue
+		}
+		if plan {
+			logger.Info("(plan) would delete launch template %q (%s)", name, id)
+			continue
+		}
+		if _, err := m.ctl.Provider.EC2().DeleteLaunchTemplate(ctx, &ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: lt.LaunchTemplateId,
+		}); err != nil {
+			return fmt.Errorf("deleting launch template %q (%s): %w", name, id, err)
+		}
+		logger.Info("deleted launch template %q (%s)", name, id)
+	}
+	return nil
+}
+
+// findNodeGroupLaunchTemplates returns the launch templates tagged with the name of the cluster and of one of
+// nodeGroupNames
+func (m *Manager) findNodeGroupLaunchTemplates(ctx context.Context, nodeGroupNames []string) ([]ec2types.LaunchTemplate, error) {
+	paginator := ec2.NewDescribeLaunchTemplatesPaginator(m.ctl.Provider.EC2(), &ec2.DescribeLaunchTemplatesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("tag:" + api.ClusterNameTag),
+				Values: []string{m.cfg.Metadata.Name},
+			},
+			{
+				Name:   aws.String("tag:" + api.NodeGroupNameTag),
+				Values: nodeGroupNames,
+			},
+		},
+	})
+
+	var launchTemplates []ec2types.LaunchTemplate
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing launch templates of nodegroups %v: %w", nodeGroupNames, err)
+		}
+		launchTemplates = append(launchTemplates, output.LaunchTemplates...)
+	}
+	return launchTemplates, nil
+}
+
+// launchTemplatesInUse returns the IDs and names of the launch templates used by the Auto Scaling groups of the
+// cluster, other than those of ignoredNodeGroups
+func (m *Manager) launchTemplatesInUse(ctx context.Context, ignoredNodeGroups []string) (map[string]bool, error) {
+	paginator := autoscaling.NewDescribeAutoScalingGroupsPaginator(m.ctl.Provider.ASG(), &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: []asgtypes.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: []string{"kubernetes.io/cluster/" + m.cfg.Metadata.Name},
+			},
+		},
+	})
+
+	inUse := map[string]bool{}
+	addSpec := func(spec *asgtypes.LaunchTemplateSpecification) {
+		if spec == nil {
+			return
+		}
+		if spec.LaunchTemplateId != nil {
+			inUse[*spec.LaunchTemplateId] = true
+		}
+		if spec.LaunchTemplateName != nil {
+			inUse[*spec.LaunchTemplateName] = true
+		}
+	}
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing Auto Scaling groups of cluster %q: %w", m.cfg.Metadata.Name, err)
+		}
+		for _, asg := range output.AutoScalingGroups {
+			if belongsToNodeGroup(asg, ignoredNodeGroups) {
+				continue
+			}
+			addSpec(asg.LaunchTemplate)
+			if asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil {
+				addSpec(asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification)
+				for _, override := range asg.MixedInstancesPolicy.LaunchTemplate.Overrides {
+					addSpec(override.LaunchTemplateSpecification)
+				}
+			}
+		}
+	}
+	return inUse, nil
+}
+
+func belongsToNodeGroup(asg asgtypes.AutoScalingGroup, nodeGroupNames []string) bool {
+	for _, tag := range asg.Tags {
+		if aws.StringValue(tag.Key) != api.NodeGroupNameTag {
+			continue
+		}
+		for _, name := range nodeGroupNames {
+			if aws.StringValue(tag.Value) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package nodegroup_test
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("PurgeLaunchTemplates", func() {
+	var (
+		p   *mockprovider.MockProvider
+		m   *nodegroup.Manager
+		cfg *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "my-cluster"
+		m = nodegroup.New(cfg, &eks.ClusterProvider{Provider: p}, nil)
+
+		p.MockEC2().On("DescribeLaunchTemplates", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeLaunchTemplatesInput) bool {
+			Expect(input.Filters).To(ConsistOf(
+				ec2types.Filter{Name: aws.String("tag:" + api.ClusterNameTag), Values: []string{"my-cluster"}},
+				ec2types.Filter{Name: aws.String("tag:" + api.NodeGroupNameTag), Values: []string{"ng-1", "ng-2"}},
+			))
+			return true
+		})).Return(&ec2.DescribeLaunchTemplatesOutput{
+			LaunchTemplates: []ec2types.LaunchTemplate{
+				{LaunchTemplateId: aws.String("lt-1"), LaunchTemplateName: aws.String("eksctl-my-cluster-nodegroup-ng-1")},
+				{LaunchTemplateId: aws.String("lt-2"), LaunchTemplateName: aws.String("eksctl-my-cluster-nodegroup-ng-2")},
+			},
+		}, nil)
+		p.MockEC2().On("DeleteLaunchTemplate", mock.Anything, mock.Anything).Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+	})
+
+	mockAutoScalingGroups := func(asgs ...asgtypes.AutoScalingGroup) {
+		p.MockASG().On("DescribeAutoScalingGroups", mock.Anything, mock.MatchedBy(func(input *autoscaling.DescribeAutoScalingGroupsInput) bool {
+			Expect(input.Filters).To(ConsistOf(asgtypes.Filter{
+				Name:   aws.String("tag-key"),
+				Values: []string{"kubernetes.io/cluster/my-cluster"},
+			}))
+			return true
+		})).Return(&autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: asgs}, nil)
+	}
+
+	deletedLaunchTemplates := func() []string {
+		var ids []string
+		for _, call := range p.MockEC2().Calls {
+			if call.Method == "DeleteLaunchTemplate" {
+				ids = append(ids, *call.Arguments.Get(1).(*ec2.DeleteLaunchTemplateInput).LaunchTemplateId)
+			}
+		}
+		return ids
+	}
+
+	It("deletes the launch templates of the nodegroups that are not used by an Auto Scaling group", func() {
+		mockAutoScalingGroups()
+		Expect(m.PurgeLaunchTemplates(context.Background(), []string{"ng-1", "ng-2"}, false)).To(Succeed())
+		Expect(deletedLaunchTemplates()).To(ConsistOf("lt-1", "lt-2"))
+	})
+
+	It("keeps the launch templates still used by an Auto Scaling group", func() {
+		mockAutoScalingGroups(
+			asgtypes.AutoScalingGroup{
+				LaunchTemplate: &asgtypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-1")},
+			},
+		)
+		Expect(m.PurgeLaunchTemplates(context.Background(), []string{"ng-1", "ng-2"}, false)).To(Succeed())
+		Expect(deletedLaunchTemplates()).To(ConsistOf("lt-2"))
+	})
+
+	It("keeps the launch templates used by the mixed instances policy of an Auto Scaling group", func() {
+		mockAutoScalingGroups(
+			asgtypes.AutoScalingGroup{
+				MixedInstancesPolicy: &asgtypes.MixedInstancesPolicy{
+					LaunchTemplate: &asgtypes.LaunchTemplate{
+						LaunchTemplateSpecification: &asgtypes.LaunchTemplateSpecification{
+							LaunchTemplateName: aws.String("eksctl-my-cluster-nodegroup-ng-2"),
+						},
+					},
+				},
+			},
+		)
+		Expect(m.PurgeLaunchTemplates(context.Background(), []string{"ng-1", "ng-2"}, false)).To(Succeed())
+		Expect(deletedLaunchTemplates()).To(ConsistOf("lt-1"))
+	})
+
+	When("in plan mode", func() {
+		It("does not delete any launch template, nor count the Auto Scaling groups of the nodegroups as using them", func() {
+			mockAutoScalingGroups(
+				asgtypes.AutoScalingGroup{
+					LaunchTemplate: &asgtypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-1")},
+					Tags: []asgtypes.TagDescription{
+						{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng-1")},
+					},
+				},
+			)
+			Expect(m.PurgeLaunchTemplates(context.Background(), []string{"ng-1", "ng-2"}, true)).To(Succeed())
+			Expect(deletedLaunchTemplates()).To(BeEmpty())
+		})
+	})
+})
@@ -3,10 +3,12 @@ package nodegroup
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/kris-nova/logger"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 
@@ -50,6 +52,11 @@ func (m *Manager) Scale(ctx context.Context, ng *api.NodeGroupBase) error {
 }
 
 func (m *Manager) scaleUnmanagedNodeGroup(ctx context.Context, ng *api.NodeGroupBase, stackInfo manager.StackInfo) error {
+	if hasScalingParameters(stackInfo.Stack) {
+		return m.scaleUnmanagedNodeGroupStack(ctx, ng, stackInfo)
+	}
+	logger.Info("the stack of nodegroup %q was created by an older version of eksctl and does not expose its scaling configuration as parameters; updating the Auto Scaling group directly", ng.Name)
+
 	asgName := ""
 	for _, resource := range stackInfo.Resources {
 		if *resource.LogicalResourceId == "NodeGroup" {
@@ -87,6 +94,48 @@ func (m *Manager) scaleUnmanagedNodeGroup(ctx context.Context, ng *api.NodeGroup
 	return nil
 }
 
+// scaleUnmanagedNodeGroupStack scales the nodegroup by updating the scaling parameters of its stack, keeping the
+// template and the values of the other parameters as they are. The current desired capacity of an autoscaled
+// nodegroup is kept unless a new one is set
+func (m *Manager) scaleUnmanagedNodeGroupStack(ctx context.Context, ng *api.NodeGroupBase, stackInfo manager.StackInfo) error {
+	parameters := map[string]string{}
+	if ng.MaxSize != nil {
+		parameters[builder.NodeGroupMaxSizeParameter] = strconv.Itoa(*ng.MaxSize)
+	}
+
+	if ng.MinSize != nil {
+		parameters[builder.NodeGroupMinSizeParameter] = strconv.Itoa(*ng.MinSize)
+	}
+
+	if ng.DesiredCapacity != nil {
+		parameters[builder.NodeGroupDesiredCapacityParameter] = strconv.Itoa(*ng.DesiredCapacity)
+	}
+
+	if err := m.stackManager.UpdateNodeGroupStack(ctx, manager.UpdateNodeGroupStackOptions{
+		NodeGroupName:           ng.Name,
+		Stack:                   stackInfo.Stack,
+		TemplateData:            manager.PreviousTemplate{},
+		Parameters:              parameters,
+		Wait:                    true,
+		PreserveDesiredCapacity: true,
+	}); err != nil {
+		return err
+	}
+	logger.Info("nodegroup successfully scaled")
+
+	return nil
+}
+
+// hasScalingParameters returns true if the nodegroup stack references its scaling configuration through
+// parameters rather than embedding it in the template
+func hasScalingParameters(stack *manager.Stack) bool {
+	keys := map[string]bool{}
+	for _, p := range stack.Parameters {
+		keys[*p.ParameterKey] = true
+	}
+	return keys[builder.NodeGroupMinSizeParameter] && keys[builder.NodeGroupMaxSizeParameter] && keys[builder.NodeGroupDesiredCapacityParameter]
+}
+
 func (m *Manager) scaleManagedNodeGroup(ng *api.NodeGroupBase) error {
 	scalingConfig := &eks.NodegroupScalingConfig{}
 
@@ -117,6 +117,75 @@ var _ = Describe("Scale", func() {
 	})
 
 	Describe("Unmanaged Nodegroup", func() {
+		When("the stack exposes the scaling configuration as parameters", func() {
+			BeforeEach(func() {
+				nodegroups := make(map[string]manager.StackInfo)
+				nodegroups["my-ng"] = manager.StackInfo{
+					Stack: &manager.Stack{
+						StackName: aws.String("eksctl-my-cluster-nodegroup-my-ng"),
+						Tags: []*cloudformation.Tag{
+							{
+								Key:   aws.String(api.NodeGroupNameTag),
+								Value: aws.String("my-ng"),
+							},
+							{
+								Key:   aws.String(api.NodeGroupTypeTag),
+								Value: aws.String(string(api.NodeGroupTypeUnmanaged)),
+							},
+						},
+						Parameters: []*cloudformation.Parameter{
+							{
+								ParameterKey:   aws.String("MinSize"),
+								ParameterValue: aws.String("2"),
+							},
+							{
+								ParameterKey:   aws.String("MaxSize"),
+								ParameterValue: aws.String("4"),
+							},
+							{
+								ParameterKey:   aws.String("DesiredCapacity"),
+								ParameterValue: aws.String("2"),
+							},
+						},
+					},
+					Resources: []*cloudformation.StackResource{
+						{
+							PhysicalResourceId: aws.String("asg-name"),
+							LogicalResourceId:  aws.String("NodeGroup"),
+						},
+					},
+				}
+				fakeStackManager.DescribeNodeGroupStacksAndResourcesReturns(nodegroups, nil)
+			})
+
+			It("scales the nodegroup by updating the stack parameters", func() {
+				err := m.Scale(context.Background(), ng)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+				_, options := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+				Expect(*options.Stack.StackName).To(Equal("eksctl-my-cluster-nodegroup-my-ng"))
+				Expect(options.TemplateData).To(Equal(manager.PreviousTemplate{}))
+				Expect(options.Parameters).To(Equal(map[string]string{
+					"MinSize":         "1",
+					"DesiredCapacity": "3",
+				}))
+				Expect(options.PreserveDesiredCapacity).To(BeTrue())
+				Expect(p.MockASG().AssertNotCalled(GinkgoT(), "UpdateAutoScalingGroup", mock.Anything, mock.Anything)).To(BeTrue())
+			})
+
+			When("the stack update fails", func() {
+				BeforeEach(func() {
+					fakeStackManager.UpdateNodeGroupStackReturns(fmt.Errorf("foo"))
+				})
+
+				It("returns an error", func() {
+					err := m.Scale(context.Background(), ng)
+					Expect(err).To(MatchError(fmt.Sprintf("failed to scale nodegroup for cluster %q, error: foo", clusterName)))
+				})
+			})
+		})
+
 		When("the ASG exists", func() {
 			BeforeEach(func() {
 				nodegroups := make(map[string]manager.StackInfo)
@@ -150,9 +219,11 @@ var _ = Describe("Scale", func() {
 
 			})
 
-			It("scales the nodegroup", func() {
+			It("scales the nodegroup by updating the ASG directly", func() {
 				err := m.Scale(context.Background(), ng)
 				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(BeZero())
+				Expect(p.MockASG().AssertNumberOfCalls(GinkgoT(), "UpdateAutoScalingGroup", 1)).To(BeTrue())
 			})
 		})
 
@@ -47,6 +47,14 @@ type UpgradeOptions struct {
 	Wait bool
 	// Stack to upgrade
 	Stack *manager.NodeGroupStack
+	// Roll replaces the existing instances of an unmanaged nodegroup using the
+	// AutoScalingGroup's rolling update policy after the AMI has been updated
+	Roll bool
+	// DryRun prints the AMI changes for an unmanaged nodegroup without applying them
+	DryRun bool
+	// PreserveDesiredCapacity keeps the current desired capacity of an autoscaled nodegroup when updating its
+	// stack, so that the capacity set by e.g. cluster-autoscaler is not reset
+	PreserveDesiredCapacity bool
 }
 
 func (m *Manager) Upgrade(ctx context.Context, options UpgradeOptions) error {
@@ -62,6 +70,11 @@ func (m *Manager) Upgrade(ctx context.Context, options UpgradeOptions) error {
 		}
 	}
 
+	if hasStack != nil && hasStack.Type == api.NodeGroupTypeUnmanaged {
+		options.Stack = hasStack
+		return m.upgradeUnmanaged(ctx, options)
+	}
+
 	nodegroupOutput, err := m.ctl.Provider.EKS().DescribeNodegroup(&eks.DescribeNodegroupInput{
 		ClusterName:   &m.cfg.Metadata.Name,
 		NodegroupName: &options.NodegroupName,
@@ -69,7 +82,7 @@ func (m *Manager) Upgrade(ctx context.Context, options UpgradeOptions) error {
 
 	if err != nil {
 		if managed.IsNotFound(err) {
-			return fmt.Errorf("upgrade is only supported for managed nodegroups; could not find one with name %q", options.NodegroupName)
+			return fmt.Errorf("could not find a managed or unmanaged nodegroup with name %q", options.NodegroupName)
 		}
 		return err
 	}
@@ -198,7 +211,16 @@ func (m *Manager) upgradeUsingStack(ctx context.Context, options UpgradeOptions,
 			return err
 		}
 
-		if err := m.stackManager.UpdateNodeGroupStack(options.NodegroupName, string(bytes), true); err != nil {
+		updateOptions := manager.UpdateNodeGroupStackOptions{
+			NodeGroupName:           options.NodegroupName,
+			TemplateData:            manager.TemplateBody(bytes),
+			Wait:                    true,
+			PreserveDesiredCapacity: options.PreserveDesiredCapacity,
+		}
+		if options.Stack != nil {
+			updateOptions.Stack = options.Stack.Stack
+		}
+		if err := m.stackManager.UpdateNodeGroupStack(ctx, updateOptions); err != nil {
 			return errors.Wrap(err, "error updating nodegroup stack")
 		}
 		return nil
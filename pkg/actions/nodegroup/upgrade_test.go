@@ -1,14 +1,22 @@
 package nodegroup_test
 
 import (
+	"bytes"
 	"context"
+	"io"
 
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/kris-nova/logger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
@@ -175,16 +183,16 @@ var _ = Describe("Upgrade", func() {
 					Expect(fakeStackManager.GetManagedNodeGroupTemplateArgsForCall(0).NodeGroupName).To(Equal(ngName))
 					Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(2))
 					By("upgrading the ForceUpdateEnabled setting first")
-					ng, template, wait := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
-					Expect(ng).To(Equal(ngName))
-					Expect(template).To(Equal(al2ForceFalseTemplate))
-					Expect(wait).To(BeTrue())
+					_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+					Expect(updateOptions.NodeGroupName).To(Equal(ngName))
+					Expect(string(updateOptions.TemplateData.(manager.TemplateBody))).To(Equal(al2ForceFalseTemplate))
+					Expect(updateOptions.Wait).To(BeTrue())
 
 					By("upgrading the ReleaseVersion setting next")
-					ng, template, wait = fakeStackManager.UpdateNodeGroupStackArgsForCall(1)
-					Expect(ng).To(Equal(ngName))
-					Expect(template).To(Equal(al2FullyUpdatedTemplate))
-					Expect(wait).To(BeTrue())
+					_, updateOptions = fakeStackManager.UpdateNodeGroupStackArgsForCall(1)
+					Expect(updateOptions.NodeGroupName).To(Equal(ngName))
+					Expect(string(updateOptions.TemplateData.(manager.TemplateBody))).To(Equal(al2FullyUpdatedTemplate))
+					Expect(updateOptions.Wait).To(BeTrue())
 				})
 			})
 		})
@@ -236,10 +244,10 @@ var _ = Describe("Upgrade", func() {
 					Expect(fakeStackManager.GetManagedNodeGroupTemplateArgsForCall(0).NodeGroupName).To(Equal(ngName))
 					Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
 					By("upgrading the ReleaseVersion and not updating the ForceUpdateEnabled setting")
-					ng, template, wait := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
-					Expect(ng).To(Equal(ngName))
-					Expect(template).To(Equal(al2FullyUpdatedTemplate))
-					Expect(wait).To(BeTrue())
+					_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+					Expect(updateOptions.NodeGroupName).To(Equal(ngName))
+					Expect(string(updateOptions.TemplateData.(manager.TemplateBody))).To(Equal(al2FullyUpdatedTemplate))
+					Expect(updateOptions.Wait).To(BeTrue())
 				})
 			})
 		})
@@ -292,18 +300,274 @@ var _ = Describe("Upgrade", func() {
 
 					By("upgrading the ForceUpdateEnabled setting first")
 					Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(2))
-					ng, template, wait := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
-					Expect(ng).To(Equal(ngName))
-					Expect(template).To(Equal(brForceFalseTemplate))
-					Expect(wait).To(BeTrue())
+					_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+					Expect(updateOptions.NodeGroupName).To(Equal(ngName))
+					Expect(string(updateOptions.TemplateData.(manager.TemplateBody))).To(Equal(brForceFalseTemplate))
+					Expect(updateOptions.Wait).To(BeTrue())
 
 					By("upgrading the Version next")
-					ng, template, wait = fakeStackManager.UpdateNodeGroupStackArgsForCall(1)
-					Expect(ng).To(Equal(ngName))
-					Expect(template).To(Equal(brFulllyUpdatedTemplate))
-					Expect(wait).To(BeTrue())
+					_, updateOptions = fakeStackManager.UpdateNodeGroupStackArgsForCall(1)
+					Expect(updateOptions.NodeGroupName).To(Equal(ngName))
+					Expect(string(updateOptions.TemplateData.(manager.TemplateBody))).To(Equal(brFulllyUpdatedTemplate))
+					Expect(updateOptions.Wait).To(BeTrue())
 				})
 			})
 		})
 	})
+
+	When("the nodegroup is unmanaged", func() {
+		const (
+			launchTemplateData = `"NodeGroupLaunchTemplate":{"Properties":{"LaunchTemplateData":{"ImageId":"ami-old","InstanceType":"m5.large"}}}`
+			latestVersion      = `{"Fn::GetAtt":["NodeGroupLaunchTemplate","LatestVersionNumber"]}`
+			unmanagedTemplate  = `{"Resources":{` + launchTemplateData + `,"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":` + latestVersion + `}},"UpdatePolicy":{"AutoScalingRollingUpdate":{"SuspendProcesses":["AZRebalance"]}}}}}`
+		)
+
+		var (
+			unmanagedStack *manager.Stack
+			output         *bytes.Buffer
+			loggerWriter   io.Writer
+		)
+
+		mockLaunchTemplateVersions := func(versions ...int64) {
+			for _, version := range versions {
+				p.MockEC2().On("DescribeLaunchTemplates", mock.Anything, &ec2.DescribeLaunchTemplatesInput{
+					LaunchTemplateNames: []string{"eksctl-my-cluster-nodegroup-my-nodegroup"},
+				}).Return(&ec2.DescribeLaunchTemplatesOutput{
+					LaunchTemplates: []ec2types.LaunchTemplate{{LatestVersionNumber: awsv2.Int64(version)}},
+				}, nil).Once()
+			}
+		}
+
+		updatedTemplate := func() (string, bool) {
+			_, options := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+			templateBody, ok := options.TemplateData.(manager.TemplateBody)
+			Expect(ok).To(BeTrue())
+			return string(templateBody), options.Wait
+		}
+
+		BeforeEach(func() {
+			fakeStackManager.MakeChangeSetNameReturns("eksctl-update-nodegroup")
+			unmanagedStack = &manager.Stack{
+				StackName: aws.String("eksctl-my-cluster-nodegroup-my-nodegroup"),
+				Tags: []*cloudformation.Tag{
+					{
+						Key:   aws.String(api.NodeGroupAMIFamilyTag),
+						Value: aws.String(api.NodeImageFamilyAmazonLinux2),
+					},
+				},
+			}
+			fakeStackManager.ListNodeGroupStacksReturns([]manager.NodeGroupStack{{
+				NodeGroupName: ngName,
+				Type:          api.NodeGroupTypeUnmanaged,
+				Stack:         unmanagedStack,
+			}}, nil)
+			fakeStackManager.GetStackTemplateReturns(unmanagedTemplate, nil)
+			fakeStackManager.GetUnmanagedNodeGroupAutoScalingGroupNameReturns("my-asg", nil)
+
+			p.MockEC2().On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+				InstanceTypes: []ec2types.InstanceType{ec2types.InstanceTypeM5Large},
+			}).Return(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []ec2types.InstanceTypeInfo{
+					{
+						InstanceType: ec2types.InstanceTypeM5Large,
+						ProcessorInfo: &ec2types.ProcessorInfo{
+							SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+						},
+					},
+				},
+			}, nil)
+
+			p.MockSSM().On("GetParameter", mock.Anything, &ssm.GetParameterInput{
+				Name: aws.String("/aws/service/eks/optimized-ami/1.21/amazon-linux-2/recommended/image_id"),
+			}).Return(&ssm.GetParameterOutput{
+				Parameter: &ssmtypes.Parameter{
+					Value: aws.String("ami-new"),
+				},
+			}, nil)
+
+			p.MockEC2().On("DescribeImages", mock.Anything, &ec2.DescribeImagesInput{
+				ImageIds: []string{"ami-old", "ami-new"},
+			}).Return(&ec2.DescribeImagesOutput{
+				Images: []ec2types.Image{
+					{ImageId: aws.String("ami-old"), Name: aws.String("amazon-eks-node-1.21-v20220101")},
+					{ImageId: aws.String("ami-new"), Name: aws.String("amazon-eks-node-1.21-v20220201")},
+				},
+			}, nil)
+
+			mockLaunchTemplateVersions(3, 4)
+			p.MockASG().On("UpdateAutoScalingGroup", mock.Anything, mock.Anything).Return(&autoscaling.UpdateAutoScalingGroupOutput{}, nil)
+
+			output = &bytes.Buffer{}
+			loggerWriter = logger.Writer
+			logger.Writer = output
+		})
+
+		AfterEach(func() {
+			logger.Writer = loggerWriter
+		})
+
+		It("updates the AMI without replacing the existing instances", func() {
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			Expect(fakeStackManager.GetStackTemplateArgsForCall(0)).To(Equal("eksctl-my-cluster-nodegroup-my-nodegroup"))
+			Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+			template, wait := updatedTemplate()
+			_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+			Expect(updateOptions.NodeGroupName).To(Equal(ngName))
+			Expect(updateOptions.Stack).To(Equal(unmanagedStack))
+			Expect(updateOptions.Parameters).To(BeNil())
+			Expect(wait).To(BeTrue())
+			By("only updating the launch template in the stack, and keeping the rolling update policy")
+			Expect(template).To(Equal(`{"Resources":{"NodeGroupLaunchTemplate":{"Properties":{"LaunchTemplateData":{"ImageId":"ami-new","InstanceType":"m5.large"}}},"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":"3"}},"UpdatePolicy":{"AutoScalingRollingUpdate":{"SuspendProcesses":["AZRebalance"]}}}}}`))
+
+			By("switching the AutoScalingGroup to the new launch template version")
+			p.MockASG().AssertCalled(GinkgoT(), "UpdateAutoScalingGroup", mock.Anything, &autoscaling.UpdateAutoScalingGroupInput{
+				AutoScalingGroupName: awsv2.String("my-asg"),
+				LaunchTemplate: &autoscalingtypes.LaunchTemplateSpecification{
+					LaunchTemplateName: awsv2.String("eksctl-my-cluster-nodegroup-my-nodegroup"),
+					Version:            awsv2.String("4"),
+				},
+			})
+			p.MockEKS().AssertNotCalled(GinkgoT(), "DescribeNodegroup", mock.Anything)
+		})
+
+		It("switches the launch template of the mixed instances policy to the new version", func() {
+			fakeStackManager.GetStackTemplateReturns(`{"Resources":{`+launchTemplateData+`,"NodeGroup":{"Properties":{"MixedInstancesPolicy":{"LaunchTemplate":{"LaunchTemplateSpecification":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":`+latestVersion+`}}}},"UpdatePolicy":{"AutoScalingRollingUpdate":{}}}}}`, nil)
+
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			template, _ := updatedTemplate()
+			Expect(template).To(ContainSubstring(`"LaunchTemplateSpecification":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":"3"}`))
+			p.MockASG().AssertCalled(GinkgoT(), "UpdateAutoScalingGroup", mock.Anything, &autoscaling.UpdateAutoScalingGroupInput{
+				AutoScalingGroupName: awsv2.String("my-asg"),
+				MixedInstancesPolicy: &autoscalingtypes.MixedInstancesPolicy{
+					LaunchTemplate: &autoscalingtypes.LaunchTemplate{
+						LaunchTemplateSpecification: &autoscalingtypes.LaunchTemplateSpecification{
+							LaunchTemplateName: awsv2.String("eksctl-my-cluster-nodegroup-my-nodegroup"),
+							Version:            awsv2.String("4"),
+						},
+					},
+				},
+			})
+		})
+
+		It("keeps the launch template version pinned by a previous upgrade", func() {
+			fakeStackManager.GetStackTemplateReturns(`{"Resources":{`+launchTemplateData+`,"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":"2"}},"UpdatePolicy":{"AutoScalingRollingUpdate":{}}}}}`, nil)
+
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			template, _ := updatedTemplate()
+			Expect(template).To(ContainSubstring(`"Version":"2"`))
+			p.MockASG().AssertCalled(GinkgoT(), "UpdateAutoScalingGroup", mock.Anything, &autoscaling.UpdateAutoScalingGroupInput{
+				AutoScalingGroupName: awsv2.String("my-asg"),
+				LaunchTemplate: &autoscalingtypes.LaunchTemplateSpecification{
+					LaunchTemplateName: awsv2.String("eksctl-my-cluster-nodegroup-my-nodegroup"),
+					Version:            awsv2.String("3"),
+				},
+			})
+		})
+
+		It("keeps the rolling update policy when roll is set", func() {
+			options.Roll = true
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+			template, wait := updatedTemplate()
+			Expect(wait).To(BeFalse())
+			Expect(template).To(Equal(`{"Resources":{"NodeGroupLaunchTemplate":{"Properties":{"LaunchTemplateData":{"ImageId":"ami-new","InstanceType":"m5.large"}}},"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":{"Fn::GetAtt":["NodeGroupLaunchTemplate","LatestVersionNumber"]}}},"UpdatePolicy":{"AutoScalingRollingUpdate":{"SuspendProcesses":["AZRebalance"]}}}}}`))
+			p.MockASG().AssertNotCalled(GinkgoT(), "UpdateAutoScalingGroup", mock.Anything, mock.Anything)
+		})
+
+		It("makes the AutoScalingGroup follow the latest launch template version again when roll is set", func() {
+			fakeStackManager.GetStackTemplateReturns(`{"Resources":{`+launchTemplateData+`,"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":"2"}},"UpdatePolicy":{"AutoScalingRollingUpdate":{}}}}}`, nil)
+			options.Roll = true
+
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			template, _ := updatedTemplate()
+			Expect(template).To(ContainSubstring(`"Version":` + latestVersion))
+		})
+
+		It("does not add a rolling update policy when roll is set", func() {
+			fakeStackManager.GetStackTemplateReturns(`{"Resources":{`+launchTemplateData+`,"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":`+latestVersion+`}}}}}`, nil)
+			options.Roll = true
+
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			template, _ := updatedTemplate()
+			Expect(template).NotTo(ContainSubstring("UpdatePolicy"))
+			Expect(output.String()).To(ContainSubstring(`nodegroup "my-nodegroup" has no rolling update policy, existing instances will not be replaced`))
+		})
+
+		It("does not update the stack in dry-run mode", func() {
+			options.DryRun = true
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(0))
+			p.MockASG().AssertNotCalled(GinkgoT(), "UpdateAutoScalingGroup", mock.Anything, mock.Anything)
+		})
+
+		Context("when CloudFormation resolves the AMI from an SSM parameter", func() {
+			BeforeEach(func() {
+				fakeStackManager.GetStackTemplateReturns(`{"Parameters":{"ImageId":{"Type":"AWS::SSM::Parameter::Value<AWS::EC2::Image::Id>","Default":"/aws/service/eks/optimized-ami/1.20/amazon-linux-2/recommended/image_id"}},"Resources":{"NodeGroupLaunchTemplate":{"Properties":{"LaunchTemplateData":{"ImageId":{"Ref":"ImageId"},"InstanceType":"m5.large"}}},"NodeGroup":{"Properties":{"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":`+latestVersion+`}},"UpdatePolicy":{"AutoScalingRollingUpdate":{}}}}}`, nil)
+				unmanagedStack.Parameters = []*cloudformation.Parameter{
+					{
+						ParameterKey:   aws.String("ImageId"),
+						ParameterValue: aws.String("/aws/service/eks/optimized-ami/1.20/amazon-linux-2/recommended/image_id"),
+						ResolvedValue:  aws.String("ami-old"),
+					},
+				}
+			})
+
+			It("updates the SSM parameter of the AMI to the new Kubernetes version", func() {
+				Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+				Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(1))
+				_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+				Expect(updateOptions.Parameters).To(Equal(map[string]string{
+					"ImageId": "/aws/service/eks/optimized-ami/1.21/amazon-linux-2/recommended/image_id",
+				}))
+				template, _ := updatedTemplate()
+				Expect(template).To(ContainSubstring(`"ImageId":{"Ref":"ImageId"}`))
+				Expect(output.String()).To(ContainSubstring("will be upgraded from AMI ami-old (amazon-eks-node-1.21-v20220101) to ami-new (amazon-eks-node-1.21-v20220201)"))
+			})
+
+			It("does not update the stack when the resolved AMI is the latest", func() {
+				unmanagedStack.Parameters[0].ResolvedValue = aws.String("ami-new")
+
+				Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+				Expect(fakeStackManager.UpdateNodeGroupStackCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the nodegroup sets a maximum instance lifetime", func() {
+			BeforeEach(func() {
+				fakeStackManager.GetStackTemplateReturns(`{"Resources":{`+launchTemplateData+`,"NodeGroup":{"Properties":{"MaxInstanceLifetime":604800,"LaunchTemplate":{"LaunchTemplateName":"eksctl-my-cluster-nodegroup-my-nodegroup","Version":`+latestVersion+`}},"UpdatePolicy":{"AutoScalingRollingUpdate":{}}}}}`, nil)
+			})
+
+			It("warns that the rolling update conflicts with it", func() {
+				options.Roll = true
+				options.DryRun = true
+				Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+				Expect(output.String()).To(ContainSubstring(`nodegroup "my-nodegroup" sets both maxInstanceLifetime (604800 seconds) and --roll`))
+			})
+
+			It("does not warn without a rolling update", func() {
+				Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+				Expect(output.String()).NotTo(ContainSubstring("maxInstanceLifetime"))
+			})
+		})
+
+		It("preserves the desired capacity of the nodegroup when updating its stack", func() {
+			options.PreserveDesiredCapacity = true
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+			Expect(updateOptions.PreserveDesiredCapacity).To(BeTrue())
+		})
+
+		It("does not preserve the desired capacity when preserving it is disabled", func() {
+			options.PreserveDesiredCapacity = false
+			Expect(m.Upgrade(context.Background(), options)).To(Succeed())
+			_, updateOptions := fakeStackManager.UpdateNodeGroupStackArgsForCall(0)
+			Expect(updateOptions.PreserveDesiredCapacity).To(BeFalse())
+		})
+
+		It("does not support release-version", func() {
+			options.KubernetesVersion = ""
+			options.ReleaseVersion = "1.21-20220201"
+			Expect(m.Upgrade(context.Background(), options)).To(MatchError("launch-template-version and release-version are only supported for managed nodegroups"))
+		})
+	})
 })
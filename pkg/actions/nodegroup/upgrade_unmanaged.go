@@ -0,0 +1,272 @@
+package nodegroup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/weaveworks/eksctl/pkg/ami"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+const (
+	instanceTypePath        = "Resources.NodeGroupLaunchTemplate.Properties.LaunchTemplateData.InstanceType"
+	rollingUpdatePolicyPath = "Resources.NodeGroup.UpdatePolicy.AutoScalingRollingUpdate"
+	maxInstanceLifetimePath = "Resources.NodeGroup.Properties.MaxInstanceLifetime"
+
+	launchTemplateVersionPathInASG                  = "Resources.NodeGroup.Properties.LaunchTemplate.Version"
+	launchTemplateVersionPathInMixedInstancesPolicy = "Resources.NodeGroup.Properties.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification.Version"
+)
+
+// upgradeUnmanaged upgrades an unmanaged nodegroup to the latest AMI for its image family and the specified
+// Kubernetes version, or the control plane version if the version isn't specified
+func (m *Manager) upgradeUnmanaged(ctx context.Context, options UpgradeOptions) error {
+	if options.LaunchTemplateVersion != "" || options.ReleaseVersion != "" {
+		return errors.New("launch-template-version and release-version are only supported for managed nodegroups")
+	}
+
+	stack := options.Stack.Stack
+	template, err := m.stackManager.GetStackTemplate(*stack.StackName)
+	if err != nil {
+		return errors.Wrap(err, "error fetching nodegroup template")
+	}
+
+	// the AMI is either set in the template, or resolved by CloudFormation from an SSM parameter when the
+	// nodegroup uses amiResolutionMode: CloudFormation
+	imageIDFromParameter := gjson.Get(template, imageIDPath+".Ref").String() == builder.NodeGroupImageIDParameter
+	var currentAMI string
+	if imageIDFromParameter {
+		currentAMI = resolvedParameterValue(stack, builder.NodeGroupImageIDParameter)
+	} else if imageID := gjson.Get(template, imageIDPath); imageID.Type == gjson.String {
+		currentAMI = imageID.String()
+	}
+	if currentAMI == "" {
+		return fmt.Errorf("unexpected error: failed to find AMI of launch template in nodegroup stack %q", *stack.StackName)
+	}
+	instanceType := gjson.Get(template, instanceTypePath).String()
+
+	kubernetesVersion := options.KubernetesVersion
+	if kubernetesVersion == "" {
+		kubernetesVersion = m.ctl.ControlPlaneVersion()
+	}
+
+	imageFamily := api.DefaultNodeImageFamily
+	if family := manager.GetNodeGroupAMIFamily(stack.Tags); family != "" {
+		imageFamily = family
+	} else {
+		logger.Warning("nodegroup %q has no %q tag, assuming image family %s", options.NodegroupName, api.NodeGroupAMIFamilyTag, imageFamily)
+	}
+
+	resolver := ami.NewMultiResolver(
+		ami.NewSSMResolver(m.ctl.Provider.SSM(), m.ctl.Provider.EC2()),
+		ami.NewAutoResolver(m.ctl.Provider.EC2()),
+	)
+	latestAMI, err := resolver.Resolve(ctx, m.ctl.Provider.Region(), kubernetesVersion, instanceType, imageFamily)
+	if err != nil {
+		return errors.Wrap(err, "unable to determine AMI to use")
+	}
+
+	if latestAMI == currentAMI {
+		logger.Info("nodegroup %q is already up-to-date", options.NodegroupName)
+		return nil
+	}
+
+	imageNames, err := m.describeImageNames(ctx, currentAMI, latestAMI)
+	if err != nil {
+		return err
+	}
+	logger.Info("nodegroup %q will be upgraded from AMI %s (%s) to %s (%s)", options.NodegroupName,
+		currentAMI, imageNames[currentAMI], latestAMI, imageNames[latestAMI])
+
+	if options.Roll {
+		warnMaxInstanceLifetime(options.NodegroupName, template)
+	}
+
+	if options.DryRun {
+		logger.Info("no changes were applied, run again without --dry-run to upgrade the nodegroup")
+		return nil
+	}
+
+	var parameters map[string]string
+	if imageIDFromParameter {
+		// CloudFormation resolves the latest AMI of the new Kubernetes version from its SSM parameter
+		parameterName, err := ami.MakeSSMParameterNameForInstanceType(ctx, m.ctl.Provider.EC2(), kubernetesVersion, instanceType, imageFamily, "")
+		if err != nil {
+			return errors.Wrap(err, "unable to determine the SSM parameter of the AMI")
+		}
+		parameters = map[string]string{builder.NodeGroupImageIDParameter: parameterName}
+	} else {
+		template, err = sjson.Set(template, imageIDPath, latestAMI)
+		if err != nil {
+			return errors.Wrap(err, "unexpected error updating nodegroup template")
+		}
+	}
+
+	versionPath := launchTemplateVersionPath(template)
+	if versionPath == "" {
+		return fmt.Errorf("unexpected error: failed to find the launch template of the AutoScalingGroup in nodegroup stack %q", *stack.StackName)
+	}
+
+	if options.Roll {
+		if !gjson.Get(template, rollingUpdatePolicyPath).Exists() {
+			logger.Warning("nodegroup %q has no rolling update policy, existing instances will not be replaced", options.NodegroupName)
+		}
+		// the AutoScalingGroup follows the latest version of the launch template again, which makes
+		// CloudFormation replace the instances according to the rolling update policy
+		template, err = sjson.Set(template, versionPath, map[string]interface{}{
+			"Fn::GetAtt": []string{"NodeGroupLaunchTemplate", "LatestVersionNumber"},
+		})
+		if err != nil {
+			return errors.Wrap(err, "unexpected error updating nodegroup template")
+		}
+		logger.Info("existing instances will be replaced using the AutoScalingGroup's rolling update policy")
+	} else if !gjson.Get(template, versionPath).IsObject() {
+		// the AutoScalingGroup is already pinned to a version by a previous upgrade
+		logger.Debug("launch template version of nodegroup %q is already pinned", options.NodegroupName)
+	} else {
+		// changing the launch template version of the AutoScalingGroup in the stack would make CloudFormation
+		// replace the instances, so the stack only updates the launch template, and the AutoScalingGroup is
+		// switched to the new version outside of CloudFormation
+		currentVersion, err := m.latestLaunchTemplateVersion(ctx, *stack.StackName)
+		if err != nil {
+			return err
+		}
+		template, err = sjson.Set(template, versionPath, currentVersion)
+		if err != nil {
+			return errors.Wrap(err, "unexpected error updating nodegroup template")
+		}
+	}
+
+	logger.Info("upgrading nodegroup AMI")
+	// the new launch template version is only known once the stack update completes
+	if err := m.stackManager.UpdateNodeGroupStack(ctx, manager.UpdateNodeGroupStackOptions{
+		NodeGroupName:           options.NodegroupName,
+		Stack:                   stack,
+		TemplateData:            manager.TemplateBody(template),
+		Parameters:              parameters,
+		Wait:                    options.Wait || !options.Roll,
+		PreserveDesiredCapacity: options.PreserveDesiredCapacity,
+	}); err != nil {
+		return errors.Wrap(err, "error updating nodegroup stack")
+	}
+
+	if !options.Roll {
+		if err := m.useLatestLaunchTemplateVersion(ctx, stack, versionPath); err != nil {
+			return err
+		}
+		logger.Info("existing instances will not be replaced, use --roll to replace them")
+	}
+	logger.Info("nodegroup successfully upgraded")
+	return nil
+}
+
+// resolvedParameterValue returns the value CloudFormation resolved for the SSM parameter type parameter of the stack
+func resolvedParameterValue(stack *manager.Stack, key string) string {
+	for _, p := range stack.Parameters {
+		if p.ParameterKey != nil && *p.ParameterKey == key && p.ResolvedValue != nil {
+			return *p.ResolvedValue
+		}
+	}
+	return ""
+}
+
+// launchTemplateVersionPath returns the path of the launch template version of the AutoScalingGroup in the template,
+// or an empty string if it has none
+func launchTemplateVersionPath(template string) string {
+	for _, path := range []string{launchTemplateVersionPathInASG, launchTemplateVersionPathInMixedInstancesPolicy} {
+		if gjson.Get(template, path).Exists() {
+			return path
+		}
+	}
+	return ""
+}
+
+// latestLaunchTemplateVersion returns the latest version of the launch template of the nodegroup, which is named
+// after its stack
+func (m *Manager) latestLaunchTemplateVersion(ctx context.Context, launchTemplateName string) (string, error) {
+	output, err := m.ctl.Provider.EC2().DescribeLaunchTemplates(ctx, &ec2.DescribeLaunchTemplatesInput{
+		LaunchTemplateNames: []string{launchTemplateName},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "error describing launch template %q", launchTemplateName)
+	}
+	if len(output.LaunchTemplates) != 1 || output.LaunchTemplates[0].LatestVersionNumber == nil {
+		return "", fmt.Errorf("unexpected error: failed to find launch template %q", launchTemplateName)
+	}
+	return strconv.FormatInt(*output.LaunchTemplates[0].LatestVersionNumber, 10), nil
+}
+
+// useLatestLaunchTemplateVersion makes the AutoScalingGroup of the nodegroup launch new instances using the latest
+// version of its launch template, without replacing the existing instances
+func (m *Manager) useLatestLaunchTemplateVersion(ctx context.Context, stack *manager.Stack, versionPath string) error {
+	launchTemplateName := *stack.StackName
+	version, err := m.latestLaunchTemplateVersion(ctx, launchTemplateName)
+	if err != nil {
+		return err
+	}
+	asgName, err := m.stackManager.GetUnmanagedNodeGroupAutoScalingGroupName(stack)
+	if err != nil {
+		return errors.Wrapf(err, "getting the AutoScalingGroup of stack %q", launchTemplateName)
+	}
+
+	launchTemplate := &autoscalingtypes.LaunchTemplateSpecification{
+		LaunchTemplateName: aws.String(launchTemplateName),
+		Version:            aws.String(version),
+	}
+	input := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(asgName),
+	}
+	if versionPath == launchTemplateVersionPathInMixedInstancesPolicy {
+		input.MixedInstancesPolicy = &autoscalingtypes.MixedInstancesPolicy{
+			LaunchTemplate: &autoscalingtypes.LaunchTemplate{
+				LaunchTemplateSpecification: launchTemplate,
+			},
+		}
+	} else {
+		input.LaunchTemplate = launchTemplate
+	}
+	if _, err := m.ctl.Provider.ASG().UpdateAutoScalingGroup(ctx, input); err != nil {
+		return errors.Wrapf(err, "error updating the launch template version of AutoScalingGroup %q", asgName)
+	}
+	logger.Info("new instances of AutoScalingGroup %q will use version %s of launch template %q", asgName, version, launchTemplateName)
+	return nil
+}
+
+// warnMaxInstanceLifetime warns if the nodegroup sets a maximum instance lifetime, as the AutoScalingGroup keeps
+// replacing the instances reaching it while the rolling update replaces instances in batches
+func warnMaxInstanceLifetime(nodeGroupName, template string) {
+	maxInstanceLifetime := gjson.Get(template, maxInstanceLifetimePath)
+	if !maxInstanceLifetime.Exists() {
+		return
+	}
+	logger.Warning("nodegroup %q sets both maxInstanceLifetime (%d seconds) and --roll; instances reaching their maximum lifetime will be replaced independently of the batches of the rolling update", nodeGroupName, maxInstanceLifetime.Int())
+}
+
+// describeImageNames returns the names of the specified images, keyed by their IDs
+func (m *Manager) describeImageNames(ctx context.Context, imageIDs ...string) (map[string]string, error) {
+	output, err := m.ctl.Provider.EC2().DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds: imageIDs,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error describing images")
+	}
+
+	names := make(map[string]string, len(output.Images))
+	for _, image := range output.Images {
+		if image.ImageId != nil && image.Name != nil {
+			names[*image.ImageId] = *image.Name
+		}
+	}
+	return names, nil
+}
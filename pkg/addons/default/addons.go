@@ -1,6 +1,9 @@
 package defaultaddons
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
@@ -48,6 +51,34 @@ func DoAddonsSupportMultiArch(eksAPI eksiface.EKSAPI, rawClient kubernetes.RawCl
 	return coreDNSUpToDate, nil
 }
 
+var coreDNSImageTagPattern = regexp.MustCompile(`/eks/coredns:([^"]+)"`)
+
+// AddonsToUpdateForVersion returns the default addons whose version eksctl bumps when upgrading the control plane
+// from currentVersion to targetVersion, e.g. 1.21 to 1.22. kube-proxy follows the version of the control plane,
+// while coredns is only updated if the target version ships a different image
+func AddonsToUpdateForVersion(currentVersion, targetVersion string) []string {
+	if currentVersion == targetVersion {
+		return nil
+	}
+	addons := []string{KubeProxy}
+	if coreDNSImageTag(currentVersion) != coreDNSImageTag(targetVersion) || coreDNSImageTag(targetVersion) == "" {
+		addons = append(addons, CoreDNS)
+	}
+	return addons
+}
+
+func coreDNSImageTag(version string) string {
+	manifest, err := coreDNSDir.ReadFile(fmt.Sprintf("assets/%s-%s.json", CoreDNS, version))
+	if err != nil {
+		return ""
+	}
+	match := coreDNSImageTagPattern.FindSubmatch(manifest)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
 // LoadAsset return embedded manifest as a runtime.Object
 func newList(data []byte) (*metav1.List, error) {
 	list, err := kubernetes.NewList(data)
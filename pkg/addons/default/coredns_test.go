@@ -98,6 +98,16 @@ var _ = Describe("default addons - coredns", func() {
 			})
 		})
 	})
+
+	Context("AddonsToUpdateForVersion", func() {
+		It("bumps kube-proxy and coredns when the coredns image changes", func() {
+			Expect(da.AddonsToUpdateForVersion("1.21", "1.22")).To(Equal([]string{da.KubeProxy, da.CoreDNS}))
+		})
+
+		It("bumps nothing when the version does not change", func() {
+			Expect(da.AddonsToUpdateForVersion("1.22", "1.22")).To(BeEmpty())
+		})
+	})
 })
 
 func createCoreDNSFromTestSample(rawClient *testutils.FakeRawClient, ct *testutils.CollectionTracker, kubernetesVersion string) {
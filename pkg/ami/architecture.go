@@ -0,0 +1,39 @@
+package ami
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/weaveworks/eksctl/pkg/awsapi"
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
+)
+
+// InstanceTypesByArchitecture groups instanceTypes by their CPU architecture, as described by EC2
+func InstanceTypesByArchitecture(ctx context.Context, ec2API awsapi.EC2, instanceTypes []string) (map[string][]string, error) {
+	info, err := instancetypes.InfoList(ctx, ec2API, instanceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't retrieve instance type description for %v: %w", instanceTypes, err)
+	}
+
+	byArchitecture := map[string][]string{}
+	for i, it := range info {
+		byArchitecture[it.Architecture] = append(byArchitecture[it.Architecture], instanceTypes[i])
+	}
+	return byArchitecture, nil
+}
+
+// Architecture returns the CPU architecture of instanceTypes, or an *ErrMixedArchitectures naming the instance types
+// of each architecture if they do not all share the same architecture
+func Architecture(ctx context.Context, ec2API awsapi.EC2, instanceTypes []string) (string, error) {
+	byArchitecture, err := InstanceTypesByArchitecture(ctx, ec2API, instanceTypes)
+	if err != nil {
+		return "", err
+	}
+	if len(byArchitecture) > 1 {
+		return "", &ErrMixedArchitectures{instanceTypesByArchitecture: byArchitecture}
+	}
+	for architecture := range byArchitecture {
+		return architecture, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,65 @@
+package ami_test
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	. "github.com/weaveworks/eksctl/pkg/ami"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Instance type architectures", func() {
+	var p *mockprovider.MockProvider
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		p.MockEC2().On("DescribeInstanceTypes", mock.Anything, mock.Anything).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []ec2types.InstanceTypeInfo{
+				{
+					InstanceType: "m5.large",
+					ProcessorInfo: &ec2types.ProcessorInfo{
+						SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+					},
+				},
+				{
+					InstanceType: "m5a.large",
+					ProcessorInfo: &ec2types.ProcessorInfo{
+						SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+					},
+				},
+				{
+					InstanceType: "m6g.large",
+					ProcessorInfo: &ec2types.ProcessorInfo{
+						SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeArm64},
+					},
+				},
+			},
+		}, nil)
+	})
+
+	It("groups the instance types by architecture", func() {
+		byArchitecture, err := InstanceTypesByArchitecture(context.Background(), p.MockEC2(), []string{"m5.large", "m6g.large", "m5a.large"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(byArchitecture).To(Equal(map[string][]string{
+			"x86_64": {"m5.large", "m5a.large"},
+			"arm64":  {"m6g.large"},
+		}))
+	})
+
+	It("returns the architecture shared by the instance types", func() {
+		architecture, err := Architecture(context.Background(), p.MockEC2(), []string{"m5.large", "m5a.large"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(architecture).To(Equal("x86_64"))
+	})
+
+	It("names the instance types of each architecture when they do not share one", func() {
+		_, err := Architecture(context.Background(), p.MockEC2(), []string{"m5.large", "m6g.large", "m5a.large"})
+		Expect(err).To(BeAssignableToTypeOf(&ErrMixedArchitectures{}))
+		Expect(err).To(MatchError("instance types of different CPU architectures cannot share an AMI: arm64 (m6g.large), x86_64 (m5.large, m5a.large); set an AMI explicitly or split the instance types into separate nodegroups"))
+	})
+})
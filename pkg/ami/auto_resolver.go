@@ -9,6 +9,7 @@ import (
 	"github.com/kris-nova/logger"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
 	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
 )
 
@@ -28,6 +29,11 @@ func MakeImageSearchPatterns(version string) map[string]map[int]string {
 			ImageClassGPU:     fmt.Sprintf("amazon-eks-gpu-node-%s-*", version),
 			ImageClassARM:     fmt.Sprintf("amazon-eks-arm64-node-%s-*", version),
 		},
+		api.NodeImageFamilyAmazonLinux2023: {
+			ImageClassGeneral: fmt.Sprintf("amazon-eks-node-al2023-x86_64-standard-%s-v*", version),
+			ImageClassGPU:     fmt.Sprintf("amazon-eks-node-al2023-x86_64-nvidia-*%s-v*", version),
+			ImageClassARM:     fmt.Sprintf("amazon-eks-node-al2023-arm64-standard-%s-v*", version),
+		},
 		api.NodeImageFamilyUbuntu2004: {
 			ImageClassGeneral: fmt.Sprintf("ubuntu-eks/k8s_%s/images/*20.04-amd64*", version),
 			ImageClassARM:     fmt.Sprintf("ubuntu-eks/k8s_%s/images/*20.04-arm64*", version),
@@ -47,6 +53,12 @@ func MakeImageSearchPatterns(version string) map[string]map[int]string {
 		api.NodeImageFamilyWindowsServer20H2CoreContainer: {
 			ImageClassGeneral: fmt.Sprintf("Windows_Server-20H2-English-Core-EKS_Optimized-%v-*", version),
 		},
+		api.NodeImageFamilyWindowsServer2022CoreContainer: {
+			ImageClassGeneral: fmt.Sprintf("Windows_Server-2022-English-Core-EKS_Optimized-%v-*", version),
+		},
+		api.NodeImageFamilyWindowsServer2022FullContainer: {
+			ImageClassGeneral: fmt.Sprintf("Windows_Server-2022-English-Full-EKS_Optimized-%v-*", version),
+		},
 	}
 }
 
@@ -55,7 +67,7 @@ func OwnerAccountID(imageFamily, region string) (string, error) {
 	switch imageFamily {
 	case api.NodeImageFamilyUbuntu2004, api.NodeImageFamilyUbuntu1804:
 		return ownerIDUbuntuFamily, nil
-	case api.NodeImageFamilyAmazonLinux2:
+	case api.NodeImageFamilyAmazonLinux2, api.NodeImageFamilyAmazonLinux2023:
 		return api.EKSResourceAccountID(region), nil
 	default:
 		if api.IsWindowsImage(imageFamily) {
@@ -74,7 +86,21 @@ type AutoResolver struct {
 // Resolve will return an AMI to use based on the default AMI for
 // each region
 func (r *AutoResolver) Resolve(ctx context.Context, region, version, instanceType, imageFamily string) (string, error) {
-	logger.Debug("resolving AMI using AutoResolver for region %s, instanceType %s and imageFamily %s", region, instanceType, imageFamily)
+	architecture := instancetypes.ArchitectureX86_64
+	if instanceutils.IsARMInstanceType(instanceType) {
+		architecture = instancetypes.ArchitectureARM64
+	}
+	return r.ResolveForArchitecture(ctx, region, version, instanceType, imageFamily, architecture)
+}
+
+// ResolveForArchitecture returns the default AMI for the region for the CPU architecture, regardless of the
+// architecture of instanceType, which is only used to find whether a GPU AMI is needed
+func (r *AutoResolver) ResolveForArchitecture(ctx context.Context, region, version, instanceType, imageFamily, architecture string) (string, error) {
+	logger.Debug("resolving AMI using AutoResolver for region %s, instanceType %s, imageFamily %s and architecture %s", region, instanceType, imageFamily, architecture)
+
+	if err := api.ValidateAMIFamilyVersion(imageFamily, version); err != nil {
+		return "", err
+	}
 
 	imageClasses := MakeImageSearchPatterns(version)[imageFamily]
 	namePattern := imageClasses[ImageClassGeneral]
@@ -87,7 +113,7 @@ func (r *AutoResolver) Resolve(ctx context.Context, region, version, instanceTyp
 		}
 	}
 
-	if instanceutils.IsARMInstanceType(instanceType) {
+	if architecture == instancetypes.ArchitectureARM64 {
 		var ok bool
 		namePattern, ok = imageClasses[ImageClassARM]
 		if !ok {
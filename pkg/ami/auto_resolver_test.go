@@ -64,6 +64,16 @@ var _ = Describe("AMI Auto Resolution", func() {
 			})
 		})
 
+		Context("with an AMI family that is no longer published for the EKS version", func() {
+			It("should return an error without querying EC2", func() {
+				p = mockprovider.NewMockProvider()
+				resolver := NewAutoResolver(p.MockEC2())
+				_, err := resolver.Resolve(context.Background(), "eu-west-1", "1.22", "t3.medium", api.NodeImageFamilyWindowsServer2004CoreContainer)
+				Expect(err).To(MatchError("WindowsServer2004CoreContainer AMIs are not published for EKS version 1.22 and above, use WindowsServer2019CoreContainer instead"))
+				Expect(p.MockEC2().AssertNotCalled(GinkgoT(), "DescribeImages", mock.Anything, mock.Anything)).To(BeTrue())
+			})
+		})
+
 		Context("with a valid region and N instance type", func() {
 			BeforeEach(func() {
 				region = "eu-west-1"
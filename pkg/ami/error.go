@@ -2,6 +2,8 @@ package ami
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // ErrFailedResolution is an error type that represents
@@ -47,3 +49,24 @@ func NewErrNotFound(ami string) *ErrNotFound {
 func (e *ErrNotFound) Error() string {
 	return fmt.Sprintf("unable to find AMI %s", e.ami)
 }
+
+// ErrMixedArchitectures is an error type that represents instance types of
+// different CPU architectures, which cannot share an AMI
+type ErrMixedArchitectures struct {
+	instanceTypesByArchitecture map[string][]string
+}
+
+// Error return the error message
+func (e *ErrMixedArchitectures) Error() string {
+	var architectures []string
+	for architecture := range e.instanceTypesByArchitecture {
+		architectures = append(architectures, architecture)
+	}
+	sort.Strings(architectures)
+
+	var groups []string
+	for _, architecture := range architectures {
+		groups = append(groups, fmt.Sprintf("%s (%s)", architecture, strings.Join(e.instanceTypesByArchitecture[architecture], ", ")))
+	}
+	return fmt.Sprintf("instance types of different CPU architectures cannot share an AMI: %s; set an AMI explicitly or split the instance types into separate nodegroups", strings.Join(groups, ", "))
+}
@@ -18,8 +18,25 @@ type MultiResolver struct {
 // and instance type. It will invoke a specific resolver
 // to do the actual determining of AMI.
 func (r *MultiResolver) Resolve(ctx context.Context, region, version, instanceType, imageFamily string) (string, error) {
+	return r.resolve(region, version, instanceType, imageFamily, func(resolver Resolver) (string, error) {
+		return resolver.Resolve(ctx, region, version, instanceType, imageFamily)
+	})
+}
+
+// ResolveForArchitecture resolves an AMI for the CPU architecture using the delegate resolvers, returning the first
+// AMI found
+func (r *MultiResolver) ResolveForArchitecture(ctx context.Context, region, version, instanceType, imageFamily, architecture string) (string, error) {
+	return r.resolve(region, version, instanceType, imageFamily, func(resolver Resolver) (string, error) {
+		if architectureResolver, ok := resolver.(ArchitectureResolver); ok {
+			return architectureResolver.ResolveForArchitecture(ctx, region, version, instanceType, imageFamily, architecture)
+		}
+		return resolver.Resolve(ctx, region, version, instanceType, imageFamily)
+	})
+}
+
+func (r *MultiResolver) resolve(region, version, instanceType, imageFamily string, resolve func(Resolver) (string, error)) (string, error) {
 	for _, resolver := range r.delegates {
-		ami, err := resolver.Resolve(ctx, region, version, instanceType, imageFamily)
+		ami, err := resolve(resolver)
 		if err != nil {
 			if _, ok := err.(*UnsupportedQueryError); ok {
 				logger.Debug(err.Error())
@@ -41,6 +58,13 @@ type Resolver interface {
 	Resolve(ctx context.Context, region, version, instanceType, imageFamily string) (string, error)
 }
 
+// ArchitectureResolver is a Resolver that can resolve the AMI for an explicit CPU architecture, e.g. when the
+// architecture is determined from all the instance types of a nodegroup rather than from a single one
+type ArchitectureResolver interface {
+	Resolver
+	ResolveForArchitecture(ctx context.Context, region, version, instanceType, imageFamily, architecture string) (string, error)
+}
+
 // NewMultiResolver creates and returns a MultiResolver with the specified delegates
 func NewMultiResolver(delegates ...Resolver) *MultiResolver {
 	return &MultiResolver{
@@ -53,9 +77,10 @@ func NewAutoResolver(api awsapi.EC2) Resolver {
 	return &AutoResolver{api: api}
 }
 
-// NewSSMResolver creates a new AutoResolver.
-func NewSSMResolver(api awsapi.SSM) Resolver {
-	return &SSMResolver{ssmAPI: api}
+// NewSSMResolver creates a new SSMResolver. The EC2 API is used to look up the capabilities of instance types; when it
+// is nil they are inferred from the instance type family
+func NewSSMResolver(ssmAPI awsapi.SSM, ec2API awsapi.EC2) Resolver {
+	return &SSMResolver{ssmAPI: ssmAPI, ec2API: ec2API}
 }
 
 // UnsupportedQueryError represents an unsupported AMI query error
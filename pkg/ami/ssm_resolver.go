@@ -3,8 +3,8 @@ package ami
 import (
 	"context"
 	"fmt"
-
-	"github.com/pkg/errors"
+	"regexp"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go/aws"
@@ -13,47 +13,153 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
 	"github.com/weaveworks/eksctl/pkg/utils"
-	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
 )
 
 // SSMResolver resolves the AMI to the defaults for the region
 // by querying AWS SSM get parameter API
 type SSMResolver struct {
 	ssmAPI awsapi.SSM
+	ec2API awsapi.EC2
+}
+
+// amiReleaseVersionPattern matches the release versions of the EKS-optimized Amazon Linux AMIs, e.g.
+// amazon-eks-node-1.27-v20231201, amazon-eks-gpu-node-1.27-v20231201 or
+// amazon-eks-node-al2023-x86_64-standard-1.29-v20240213, capturing their EKS version
+var amiReleaseVersionPattern = regexp.MustCompile(`^amazon-eks-[a-z0-9-]*node-(?:[a-z0-9_]+-)*(\d+\.\d+)-v\d{8}$`)
+
+// bottlerocketReleaseVersionPattern matches the versions of Bottlerocket releases, e.g. 1.19.2, optionally followed by
+// the commit of the release, e.g. 1.19.2-29cc92cc
+var bottlerocketReleaseVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(?:-[0-9a-f]{8})?$`)
+
+// ResolvedAMI is an AMI resolved from SSM Parameter Store, along with the metadata of the parameter it was read from
+type ResolvedAMI struct {
+	ImageID string
+	// ParameterVersion is the version of the SSM parameter, which is incremented each time the AMI is updated
+	ParameterVersion int64
+	// LastModifiedDate is the date the SSM parameter was last updated, i.e. when the AMI was published
+	LastModifiedDate *time.Time
 }
 
 // Resolve will return an AMI to use based on the default AMI for
 // each region
 func (r *SSMResolver) Resolve(ctx context.Context, region, version, instanceType, imageFamily string) (string, error) {
-	logger.Debug("resolving AMI using SSM Parameter resolver for region %s, instanceType %s and imageFamily %s", region, instanceType, imageFamily)
+	resolved, err := r.ResolveWithMetadata(ctx, region, version, instanceType, imageFamily)
+	if err != nil {
+		return "", err
+	}
+	return resolved.ImageID, nil
+}
+
+// ResolveWithMetadata returns the default AMI for the region along with the version and last modified date of its SSM
+// parameter, which tell how old the AMI is
+func (r *SSMResolver) ResolveWithMetadata(ctx context.Context, region, version, instanceType, imageFamily string) (*ResolvedAMI, error) {
+	return r.resolve(ctx, region, version, instanceType, imageFamily, "", "")
+}
 
-	parameterName, err := MakeSSMParameterName(version, instanceType, imageFamily)
+// ResolveWithReleaseVersion returns the AMI of a specific release of the EKS-optimized AMIs, e.g.
+// amazon-eks-node-1.27-v20231201, or of Bottlerocket, e.g. 1.19.2, so that the same AMI can be used across
+// environments or an older release can be rolled back to. It resolves the latest AMI when releaseVersion is empty
+func (r *SSMResolver) ResolveWithReleaseVersion(ctx context.Context, region, version, instanceType, imageFamily, releaseVersion string) (string, error) {
+	resolved, err := r.resolve(ctx, region, version, instanceType, imageFamily, releaseVersion, "")
 	if err != nil {
 		return "", err
 	}
+	return resolved.ImageID, nil
+}
+
+// ResolveForArchitecture returns the default AMI for the region for the CPU architecture, regardless of the
+// architecture of instanceType, which is only used to find whether an accelerated AMI is needed
+func (r *SSMResolver) ResolveForArchitecture(ctx context.Context, region, version, instanceType, imageFamily, architecture string) (string, error) {
+	resolved, err := r.resolve(ctx, region, version, instanceType, imageFamily, "", architecture)
+	if err != nil {
+		return "", err
+	}
+	return resolved.ImageID, nil
+}
+
+func (r *SSMResolver) resolve(ctx context.Context, region, version, instanceType, imageFamily, releaseVersion, architecture string) (*ResolvedAMI, error) {
+	logger.Debug("resolving AMI using SSM Parameter resolver for region %s, instanceType %s, imageFamily %s, release version %q and architecture %q", region, instanceType, imageFamily, releaseVersion, architecture)
+
+	parameterName, err := makeSSMParameterNameForInstanceType(ctx, r.ec2API, version, instanceType, imageFamily, releaseVersion, architecture)
+	if err != nil {
+		return nil, err
+	}
 
 	output, err := r.ssmAPI.GetParameter(ctx, &ssm.GetParameterInput{
 		Name: aws.String(parameterName),
 	})
 	if err != nil {
-		return "", fmt.Errorf("error getting AMI from SSM Parameter Store: %w. please verify that AMI Family is supported", err)
+		return nil, fmt.Errorf("error getting AMI from SSM Parameter Store: %w. please verify that AMI Family is supported", err)
 	}
 
 	if output == nil || output.Parameter == nil || *output.Parameter.Value == "" {
-		return "", NewErrFailedResolution(region, version, instanceType, imageFamily)
+		return nil, NewErrFailedResolution(region, version, instanceType, imageFamily)
 	}
 
-	return *output.Parameter.Value, nil
+	return &ResolvedAMI{
+		ImageID:          *output.Parameter.Value,
+		ParameterVersion: output.Parameter.Version,
+		LastModifiedDate: output.Parameter.LastModifiedDate,
+	}, nil
 }
 
 // MakeSSMParameterName creates an SSM parameter name
 func MakeSSMParameterName(version, instanceType, imageFamily string) (string, error) {
+	return MakeSSMParameterNameForReleaseVersion(version, instanceType, imageFamily, "")
+}
+
+// MakeSSMParameterNameForReleaseVersion creates the name of the SSM parameter of a release of the EKS-optimized AMIs,
+// or of the latest AMI when releaseVersion is empty. Release versions are only published for Amazon Linux and
+// Bottlerocket AMIs. The capabilities of the instance type are inferred from its family, without querying EC2
+func MakeSSMParameterNameForReleaseVersion(version, instanceType, imageFamily, releaseVersion string) (string, error) {
+	return makeSSMParameterName(version, instancetypes.StaticInfo(instanceType), imageFamily, releaseVersion)
+}
+
+// MakeSSMParameterNameForInstanceType creates the name of the SSM parameter holding the AMI the resolver would use
+// for the instance type, querying EC2 for its capabilities. CloudFormation templates reference the same parameter
+// when AMIs are resolved by CloudFormation
+func MakeSSMParameterNameForInstanceType(ctx context.Context, ec2API awsapi.EC2, version, instanceType, imageFamily, releaseVersion string) (string, error) {
+	return makeSSMParameterNameForInstanceType(ctx, ec2API, version, instanceType, imageFamily, releaseVersion, "")
+}
+
+// makeSSMParameterNameForInstanceType creates the name of the SSM parameter for the instance type, using architecture
+// instead of the architecture of the instance type when it is set
+func makeSSMParameterNameForInstanceType(ctx context.Context, ec2API awsapi.EC2, version, instanceType, imageFamily, releaseVersion, architecture string) (string, error) {
+	instanceTypeInfo, err := instancetypes.Info(ctx, ec2API, instanceType)
+	if err != nil {
+		return "", fmt.Errorf("couldn't retrieve instance type description for %s: %w", instanceType, err)
+	}
+	if architecture != "" {
+		instanceTypeInfo.Architecture = architecture
+	}
+	return makeSSMParameterName(version, instanceTypeInfo, imageFamily, releaseVersion)
+}
+
+func makeSSMParameterName(version string, instanceType instancetypes.Capabilities, imageFamily, releaseVersion string) (string, error) {
 	const fieldName = "image_id"
 
+	release := "recommended"
+	if imageFamily == api.NodeImageFamilyBottlerocket {
+		release = "latest"
+	}
+	if releaseVersion != "" {
+		if err := validateReleaseVersion(version, imageFamily, releaseVersion); err != nil {
+			return "", err
+		}
+		release = releaseVersion
+	}
+
+	if err := api.ValidateAMIFamilyVersion(imageFamily, version); err != nil {
+		return "", err
+	}
+
 	switch imageFamily {
 	case api.NodeImageFamilyAmazonLinux2:
-		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/%s/recommended/%s", version, imageType(imageFamily, instanceType, version), fieldName), nil
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/%s/%s/%s", version, imageType(imageFamily, instanceType, version), release, fieldName), nil
+	case api.NodeImageFamilyAmazonLinux2023:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/%s/%s/%s/%s/%s", version, utils.ToKebabCase(imageFamily), instanceType.Architecture, imageType(imageFamily, instanceType, version), release, fieldName), nil
 	case api.NodeImageFamilyWindowsServer2019CoreContainer:
 		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-2019-English-Core-EKS_Optimized-%s/%s", version, fieldName), nil
 	case api.NodeImageFamilyWindowsServer2019FullContainer:
@@ -61,17 +167,13 @@ func MakeSSMParameterName(version, instanceType, imageFamily string) (string, er
 	case api.NodeImageFamilyWindowsServer2004CoreContainer:
 		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-2004-English-Core-EKS_Optimized-%s/%s", version, fieldName), nil
 	case api.NodeImageFamilyWindowsServer20H2CoreContainer:
-		const minVersion = api.Version1_21
-		supportsWindows20H2, err := utils.IsMinVersion(minVersion, version)
-		if err != nil {
-			return "", err
-		}
-		if !supportsWindows20H2 {
-			return "", errors.Errorf("Windows Server 20H2 Core requires EKS version %s and above", minVersion)
-		}
 		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-20H2-English-Core-EKS_Optimized-%s/%s", version, fieldName), nil
+	case api.NodeImageFamilyWindowsServer2022CoreContainer:
+		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-2022-English-Core-EKS_Optimized-%s/%s", version, fieldName), nil
+	case api.NodeImageFamilyWindowsServer2022FullContainer:
+		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-2022-English-Full-EKS_Optimized-%s/%s", version, fieldName), nil
 	case api.NodeImageFamilyBottlerocket:
-		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/%s/latest/%s", imageType(imageFamily, instanceType, version), instanceEC2ArchName(instanceType), fieldName), nil
+		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/%s/%s/%s", imageType(imageFamily, instanceType, version), instanceType.Architecture, release, fieldName), nil
 	case api.NodeImageFamilyUbuntu2004, api.NodeImageFamilyUbuntu1804:
 		return "", &UnsupportedQueryError{msg: fmt.Sprintf("SSM Parameter lookups for %s AMIs is not supported yet", imageFamily)}
 	default:
@@ -79,6 +181,27 @@ func MakeSSMParameterName(version, instanceType, imageFamily string) (string, er
 	}
 }
 
+func validateReleaseVersion(version, imageFamily, releaseVersion string) error {
+	switch imageFamily {
+	case api.NodeImageFamilyAmazonLinux2, api.NodeImageFamilyAmazonLinux2023:
+	case api.NodeImageFamilyBottlerocket:
+		if !bottlerocketReleaseVersionPattern.MatchString(releaseVersion) {
+			return fmt.Errorf("invalid AMI release version %q: expected the version of a Bottlerocket release, e.g. 1.19.2", releaseVersion)
+		}
+		return nil
+	default:
+		return fmt.Errorf("AMI release versions can only be resolved for the %s, %s and %s image families, not %s", api.NodeImageFamilyAmazonLinux2, api.NodeImageFamilyAmazonLinux2023, api.NodeImageFamilyBottlerocket, imageFamily)
+	}
+	match := amiReleaseVersionPattern.FindStringSubmatch(releaseVersion)
+	if match == nil {
+		return fmt.Errorf("invalid AMI release version %q: expected the release version of an EKS-optimized AMI, e.g. amazon-eks-node-%s-v20231201", releaseVersion, version)
+	}
+	if match[1] != version {
+		return fmt.Errorf("AMI release version %q is for EKS version %s, not %s", releaseVersion, match[1], version)
+	}
+	return nil
+}
+
 // MakeManagedSSMParameterName creates an SSM parameter name for a managed nodegroup
 func MakeManagedSSMParameterName(version, amiType string) (string, error) {
 	switch amiType {
@@ -92,28 +215,28 @@ func MakeManagedSSMParameterName(version, amiType string) (string, error) {
 	return "", nil
 }
 
-// instanceEC2ArchName returns the name of the architecture as used by EC2
-// resources.
-func instanceEC2ArchName(instanceType string) string {
-	if instanceutils.IsARMInstanceType(instanceType) {
-		return "arm64"
-	}
-	return "x86_64"
-}
-
-func imageType(imageFamily, instanceType, version string) string {
+func imageType(imageFamily string, instanceType instancetypes.Capabilities, version string) string {
 	family := utils.ToKebabCase(imageFamily)
 	switch imageFamily {
+	case api.NodeImageFamilyAmazonLinux2023:
+		// AL2023 AMIs are published for each architecture in variants for accelerated instance types
+		if instanceType.IsNvidia() {
+			return "nvidia"
+		}
+		if instanceType.IsInferentia() {
+			return "neuron"
+		}
+		return "standard"
 	case api.NodeImageFamilyBottlerocket:
-		if instanceutils.IsNvidiaInstanceType(instanceType) {
+		if instanceType.IsNvidia() {
 			return fmt.Sprintf("%s-%s", version, "nvidia")
 		}
 		return version
 	default:
-		if instanceutils.IsGPUInstanceType(instanceType) {
+		if instanceType.IsGPU() {
 			return family + "-gpu"
 		}
-		if instanceutils.IsARMInstanceType(instanceType) {
+		if instanceType.IsARM() {
 			return family + "-arm64"
 		}
 		return family
@@ -2,12 +2,16 @@ package ami_test
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 
 	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
 
@@ -48,7 +52,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 
 						p = mockprovider.NewMockProvider()
 						addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.12/amazon-linux-2/recommended/image_id", expectedAmi)
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 					})
 
@@ -71,7 +75,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 						p = mockprovider.NewMockProvider()
 						addMockFailedGetParameter(p, "/aws/service/eks/optimized-ami/1.12/amazon-linux-2/recommended/image_id")
 
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 					})
 
@@ -100,7 +104,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 
 						p = mockprovider.NewMockProvider()
 						addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.12/amazon-linux-2-gpu/recommended/image_id", expectedAmi)
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 					})
 
@@ -133,7 +137,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 						imageFamily = "WindowsServer2019FullContainer"
 						addMockGetParameter(p, "/aws/service/ami-windows-latest/Windows_Server-2019-English-Full-EKS_Optimized-1.14/image_id", expectedAmi)
 
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 
 						Expect(err).NotTo(HaveOccurred())
@@ -145,7 +149,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 						imageFamily = "WindowsServer2019CoreContainer"
 						addMockGetParameter(p, "/aws/service/ami-windows-latest/Windows_Server-2019-English-Core-EKS_Optimized-1.15/image_id", expectedAmi)
 
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, "1.15", instanceType, imageFamily)
 
 						Expect(err).NotTo(HaveOccurred())
@@ -164,7 +168,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 					It("should return a valid AMI", func() {
 						addMockGetParameter(p, "/aws/service/ami-windows-latest/Windows_Server-20H2-English-Core-EKS_Optimized-1.21/image_id", expectedAmi)
 
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, "1.21", instanceType, "WindowsServer20H2CoreContainer")
 
 						Expect(err).NotTo(HaveOccurred())
@@ -173,13 +177,309 @@ var _ = Describe("AMI Auto Resolution", func() {
 					})
 
 					It("should return an error for EKS versions below 1.21", func() {
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						_, err := resolver.Resolve(context.Background(), region, "1.20", instanceType, "WindowsServer20H2CoreContainer")
 						Expect(err).To(HaveOccurred())
 						Expect(err).To(MatchError(ContainSubstring("Windows Server 20H2 Core requires EKS version 1.21 and above")))
 					})
+
+					It("should return an error for EKS versions its AMIs are no longer published for", func() {
+						resolver := NewSSMResolver(p.MockSSM(), nil)
+						_, err := resolver.Resolve(context.Background(), region, "1.23", instanceType, "WindowsServer20H2CoreContainer")
+						Expect(err).To(MatchError("WindowsServer20H2CoreContainer AMIs are not published for EKS version 1.23 and above, use WindowsServer2019CoreContainer instead"))
+						Expect(p.MockSSM().AssertNotCalled(GinkgoT(), "GetParameter", mock.Anything, mock.Anything)).To(BeTrue())
+					})
+				})
+
+				Context("Windows Server 2022", func() {
+					var p *mockprovider.MockProvider
+
+					BeforeEach(func() {
+						p = mockprovider.NewMockProvider()
+					})
+
+					It("should return a valid Full image for 1.23", func() {
+						addMockGetParameter(p, "/aws/service/ami-windows-latest/Windows_Server-2022-English-Full-EKS_Optimized-1.23/image_id", expectedAmi)
+
+						resolver := NewSSMResolver(p.MockSSM(), nil)
+						resolvedAmi, err = resolver.Resolve(context.Background(), region, "1.23", instanceType, "WindowsServer2022FullContainer")
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+						Expect(p.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 1)).To(BeTrue())
+					})
+
+					It("should return a valid Core image for 1.24", func() {
+						addMockGetParameter(p, "/aws/service/ami-windows-latest/Windows_Server-2022-English-Core-EKS_Optimized-1.24/image_id", expectedAmi)
+
+						resolver := NewSSMResolver(p.MockSSM(), nil)
+						resolvedAmi, err = resolver.Resolve(context.Background(), region, "1.24", instanceType, "WindowsServer2022CoreContainer")
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+						Expect(p.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 1)).To(BeTrue())
+					})
+
+					It("should return an error for EKS versions below 1.23", func() {
+						resolver := NewSSMResolver(p.MockSSM(), nil)
+						_, err := resolver.Resolve(context.Background(), region, "1.22", instanceType, "WindowsServer2022CoreContainer")
+						Expect(err).To(MatchError("Windows Server 2022 Core requires EKS version 1.23 and above"))
+
+						_, err = resolver.Resolve(context.Background(), region, "1.22", instanceType, "WindowsServer2022FullContainer")
+						Expect(err).To(MatchError("Windows Server 2022 Full requires EKS version 1.23 and above"))
+						Expect(p.MockSSM().AssertNotCalled(GinkgoT(), "GetParameter", mock.Anything, mock.Anything)).To(BeTrue())
+					})
+				})
+
+			})
+
+			Context("and AmazonLinux2023 image family", func() {
+				type al2023Entry struct {
+					instanceType  string
+					parameterName string
+				}
+
+				BeforeEach(func() {
+					p = mockprovider.NewMockProvider()
+					imageFamily = "AmazonLinux2023"
+					version = "1.23"
+				})
+
+				DescribeTable("should resolve the AMI of the variant for the instance type", func(e al2023Entry) {
+					addMockGetParameter(p, e.parameterName, expectedAmi)
+					resolver := NewSSMResolver(p.MockSSM(), nil)
+					resolvedAmi, err = resolver.Resolve(context.Background(), region, version, e.instanceType, imageFamily)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+					Expect(p.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 1)).To(BeTrue())
+				},
+					Entry("x86_64", al2023Entry{
+						instanceType:  "t3.medium",
+						parameterName: "/aws/service/eks/optimized-ami/1.23/amazon-linux-2023/x86_64/standard/recommended/image_id",
+					}),
+					Entry("arm64", al2023Entry{
+						instanceType:  "m6g.large",
+						parameterName: "/aws/service/eks/optimized-ami/1.23/amazon-linux-2023/arm64/standard/recommended/image_id",
+					}),
+					Entry("nvidia gpu", al2023Entry{
+						instanceType:  "g4dn.xlarge",
+						parameterName: "/aws/service/eks/optimized-ami/1.23/amazon-linux-2023/x86_64/nvidia/recommended/image_id",
+					}),
+					Entry("inferentia", al2023Entry{
+						instanceType:  "inf1.xlarge",
+						parameterName: "/aws/service/eks/optimized-ami/1.23/amazon-linux-2023/x86_64/neuron/recommended/image_id",
+					}),
+				)
+
+				It("should return an error if the AMI is not available", func() {
+					addMockFailedGetParameter(p, "/aws/service/eks/optimized-ami/1.23/amazon-linux-2023/x86_64/standard/recommended/image_id")
+					resolver := NewSSMResolver(p.MockSSM(), nil)
+					resolvedAmi, err = resolver.Resolve(context.Background(), region, version, "t3.medium", imageFamily)
+
+					Expect(err).To(HaveOccurred())
+					Expect(resolvedAmi).To(BeEmpty())
+				})
+
+				It("should return an error for EKS versions below 1.23", func() {
+					resolver := NewSSMResolver(p.MockSSM(), nil)
+					_, err := resolver.Resolve(context.Background(), region, "1.22", "t3.medium", imageFamily)
+					Expect(err).To(MatchError("Amazon Linux 2023 requires EKS version 1.23 and above"))
+					Expect(p.MockSSM().AssertNotCalled(GinkgoT(), "GetParameter", mock.Anything, mock.Anything)).To(BeTrue())
+				})
+			})
+
+			Context("with a release version", func() {
+				type releaseVersionEntry struct {
+					version        string
+					instanceType   string
+					imageFamily    string
+					releaseVersion string
+					parameterName  string
+					expectedErr    string
+				}
+
+				BeforeEach(func() {
+					p = mockprovider.NewMockProvider()
+				})
+
+				DescribeTable("should resolve the AMI of the release", func(e releaseVersionEntry) {
+					if e.parameterName != "" {
+						addMockGetParameter(p, e.parameterName, expectedAmi)
+					}
+					resolver := NewSSMResolver(p.MockSSM(), nil).(*SSMResolver)
+					resolvedAmi, err = resolver.ResolveWithReleaseVersion(context.Background(), region, e.version, e.instanceType, e.imageFamily, e.releaseVersion)
+
+					if e.expectedErr != "" {
+						Expect(err).To(MatchError(e.expectedErr))
+						Expect(p.MockSSM().AssertNotCalled(GinkgoT(), "GetParameter", mock.Anything, mock.Anything)).To(BeTrue())
+						return
+					}
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+					Expect(p.MockSSM().AssertNumberOfCalls(GinkgoT(), "GetParameter", 1)).To(BeTrue())
+				},
+					Entry("recommended when the release version is empty", releaseVersionEntry{
+						version:       "1.27",
+						instanceType:  "t3.medium",
+						imageFamily:   "AmazonLinux2",
+						parameterName: "/aws/service/eks/optimized-ami/1.27/amazon-linux-2/recommended/image_id",
+					}),
+					Entry("pinned AmazonLinux2 release", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "t3.medium",
+						imageFamily:    "AmazonLinux2",
+						releaseVersion: "amazon-eks-node-1.27-v20231201",
+						parameterName:  "/aws/service/eks/optimized-ami/1.27/amazon-linux-2/amazon-eks-node-1.27-v20231201/image_id",
+					}),
+					Entry("pinned AmazonLinux2 GPU release", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "g4dn.xlarge",
+						imageFamily:    "AmazonLinux2",
+						releaseVersion: "amazon-eks-gpu-node-1.27-v20231201",
+						parameterName:  "/aws/service/eks/optimized-ami/1.27/amazon-linux-2-gpu/amazon-eks-gpu-node-1.27-v20231201/image_id",
+					}),
+					Entry("pinned AmazonLinux2023 release", releaseVersionEntry{
+						version:        "1.29",
+						instanceType:   "t3.medium",
+						imageFamily:    "AmazonLinux2023",
+						releaseVersion: "amazon-eks-node-al2023-x86_64-standard-1.29-v20240213",
+						parameterName:  "/aws/service/eks/optimized-ami/1.29/amazon-linux-2023/x86_64/standard/amazon-eks-node-al2023-x86_64-standard-1.29-v20240213/image_id",
+					}),
+					Entry("malformed release version", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "t3.medium",
+						imageFamily:    "AmazonLinux2",
+						releaseVersion: "v20231201",
+						expectedErr:    `invalid AMI release version "v20231201": expected the release version of an EKS-optimized AMI, e.g. amazon-eks-node-1.27-v20231201`,
+					}),
+					Entry("release version of another EKS version", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "t3.medium",
+						imageFamily:    "AmazonLinux2",
+						releaseVersion: "amazon-eks-node-1.26-v20231201",
+						expectedErr:    `AMI release version "amazon-eks-node-1.26-v20231201" is for EKS version 1.26, not 1.27`,
+					}),
+					Entry("latest Bottlerocket release when the release version is empty", releaseVersionEntry{
+						version:       "1.27",
+						instanceType:  "t3.medium",
+						imageFamily:   "Bottlerocket",
+						parameterName: "/aws/service/bottlerocket/aws-k8s-1.27/x86_64/latest/image_id",
+					}),
+					Entry("pinned Bottlerocket release", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "t3.medium",
+						imageFamily:    "Bottlerocket",
+						releaseVersion: "1.19.2",
+						parameterName:  "/aws/service/bottlerocket/aws-k8s-1.27/x86_64/1.19.2/image_id",
+					}),
+					Entry("pinned Bottlerocket release with its commit", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "g4dn.xlarge",
+						imageFamily:    "Bottlerocket",
+						releaseVersion: "1.19.2-29cc92cc",
+						parameterName:  "/aws/service/bottlerocket/aws-k8s-1.27-nvidia/x86_64/1.19.2-29cc92cc/image_id",
+					}),
+					Entry("malformed Bottlerocket release version", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "t3.medium",
+						imageFamily:    "Bottlerocket",
+						releaseVersion: "amazon-eks-node-1.27-v20231201",
+						expectedErr:    `invalid AMI release version "amazon-eks-node-1.27-v20231201": expected the version of a Bottlerocket release, e.g. 1.19.2`,
+					}),
+					Entry("image family without release versions", releaseVersionEntry{
+						version:        "1.27",
+						instanceType:   "t3.medium",
+						imageFamily:    "WindowsServer2019CoreContainer",
+						releaseVersion: "amazon-eks-node-1.27-v20231201",
+						expectedErr:    "AMI release versions can only be resolved for the AmazonLinux2, AmazonLinux2023 and Bottlerocket image families, not WindowsServer2019CoreContainer",
+					}),
+				)
+			})
+
+			Context("with metadata", func() {
+				BeforeEach(func() {
+					p = mockprovider.NewMockProvider()
+				})
+
+				It("should return the version and last modified date of the SSM parameter", func() {
+					lastModifiedDate := time.Date(2023, time.December, 1, 10, 30, 0, 0, time.UTC)
+					parameterName := "/aws/service/eks/optimized-ami/1.27/amazon-linux-2/recommended/image_id"
+					p.MockSSM().On("GetParameter", mock.Anything, mock.MatchedBy(func(input *ssm.GetParameterInput) bool {
+						return *input.Name == parameterName
+					})).Return(&ssm.GetParameterOutput{
+						Parameter: &ssmtypes.Parameter{
+							Name:             aws.String(parameterName),
+							Type:             ssmtypes.ParameterTypeString,
+							Value:            aws.String(expectedAmi),
+							Version:          42,
+							LastModifiedDate: aws.Time(lastModifiedDate),
+						},
+					}, nil)
+
+					resolver := NewSSMResolver(p.MockSSM(), nil).(*SSMResolver)
+					resolved, err := resolver.ResolveWithMetadata(context.Background(), region, "1.27", "t3.medium", "AmazonLinux2")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolved).To(Equal(&ResolvedAMI{
+						ImageID:          expectedAmi,
+						ParameterVersion: 42,
+						LastModifiedDate: aws.Time(lastModifiedDate),
+					}))
 				})
 
+				It("should fail if the AMI is not available", func() {
+					addMockFailedGetParameter(p, "/aws/service/eks/optimized-ami/1.27/amazon-linux-2/recommended/image_id")
+
+					resolver := NewSSMResolver(p.MockSSM(), nil).(*SSMResolver)
+					resolved, err := resolver.ResolveWithMetadata(context.Background(), region, "1.27", "t3.medium", "AmazonLinux2")
+					Expect(err).To(HaveOccurred())
+					Expect(resolved).To(BeNil())
+				})
+			})
+
+			Context("with an EC2 API", func() {
+				BeforeEach(func() {
+					p = mockprovider.NewMockProvider()
+					p.MockEC2().On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+						InstanceTypes: []ec2types.InstanceType{"m7g.large"},
+					}).Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []ec2types.InstanceTypeInfo{
+							{
+								InstanceType: "m7g.large",
+								ProcessorInfo: &ec2types.ProcessorInfo{
+									SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeArm64},
+								},
+							},
+						},
+					}, nil)
+				})
+
+				It("should use the capabilities of the instance type described by EC2", func() {
+					addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.27/amazon-linux-2-arm64/recommended/image_id", expectedAmi)
+
+					resolver := NewSSMResolver(p.MockSSM(), p.MockEC2())
+					for i := 0; i < 2; i++ {
+						resolvedAmi, err = resolver.Resolve(context.Background(), region, "1.27", "m7g.large", "AmazonLinux2")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(resolvedAmi).To(Equal(expectedAmi))
+					}
+					Expect(p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeInstanceTypes", 1)).To(BeTrue())
+				})
+
+				It("should make the SSM parameter name from the capabilities of the instance type described by EC2", func() {
+					parameterName, err := MakeSSMParameterNameForInstanceType(context.Background(), p.MockEC2(), "1.27", "m7g.large", "AmazonLinux2", "")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(parameterName).To(Equal("/aws/service/eks/optimized-ami/1.27/amazon-linux-2-arm64/recommended/image_id"))
+				})
+
+				It("should use the explicit architecture over the architecture of the instance type", func() {
+					addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.27/amazon-linux-2/recommended/image_id", expectedAmi)
+
+					resolver := NewSSMResolver(p.MockSSM(), p.MockEC2()).(ArchitectureResolver)
+					resolvedAmi, err = resolver.ResolveForArchitecture(context.Background(), region, "1.27", "m7g.large", "AmazonLinux2", "x86_64")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(Equal(expectedAmi))
+				})
 			})
 
 			Context("and Ubuntu family", func() {
@@ -189,7 +489,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 				})
 
 				It("should return an error", func() {
-					resolver := NewSSMResolver(p.MockSSM())
+					resolver := NewSSMResolver(p.MockSSM(), nil)
 					resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 
 					Expect(err).To(HaveOccurred())
@@ -207,7 +507,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 					BeforeEach(func() {
 						p = mockprovider.NewMockProvider()
 						addMockGetParameter(p, "/aws/service/bottlerocket/aws-k8s-1.15/x86_64/latest/image_id", expectedAmi)
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 					})
 
@@ -229,7 +529,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 						p = mockprovider.NewMockProvider()
 						addMockFailedGetParameter(p, "/aws/service/bottlerocket/aws-k8s-1.15/x86_64/latest/image_id")
 
-						resolver := NewSSMResolver(p.MockSSM())
+						resolver := NewSSMResolver(p.MockSSM(), nil)
 						resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 					})
 
@@ -256,7 +556,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 						BeforeEach(func() {
 							p = mockprovider.NewMockProvider()
 							addMockGetParameter(p, "/aws/service/bottlerocket/aws-k8s-1.15/arm64/latest/image_id", expectedAmi)
-							resolver := NewSSMResolver(p.MockSSM())
+							resolver := NewSSMResolver(p.MockSSM(), nil)
 							resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 						})
 
@@ -278,7 +578,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 							p = mockprovider.NewMockProvider()
 							addMockFailedGetParameter(p, "/aws/service/bottlerocket/aws-k8s-1.15/arm64/latest/image_id")
 
-							resolver := NewSSMResolver(p.MockSSM())
+							resolver := NewSSMResolver(p.MockSSM(), nil)
 							resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 						})
 
@@ -308,7 +608,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 						BeforeEach(func() {
 							p = mockprovider.NewMockProvider()
 							addMockGetParameter(p, "/aws/service/bottlerocket/aws-k8s-1.21-nvidia/x86_64/latest/image_id", expectedAmi)
-							resolver := NewSSMResolver(p.MockSSM())
+							resolver := NewSSMResolver(p.MockSSM(), nil)
 							resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 						})
 
@@ -328,7 +628,7 @@ var _ = Describe("AMI Auto Resolution", func() {
 							p = mockprovider.NewMockProvider()
 							addMockFailedGetParameter(p, "/aws/service/bottlerocket/aws-k8s-1.21-nvidia/x86_64/latest/image_id")
 
-							resolver := NewSSMResolver(p.MockSSM())
+							resolver := NewSSMResolver(p.MockSSM(), nil)
 							resolvedAmi, err = resolver.Resolve(context.Background(), region, version, instanceType, imageFamily)
 						})
 
@@ -0,0 +1,72 @@
+package ami
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/awsapi"
+)
+
+// VerifyAMI describes the resolved image amiID and logs its creation date and deprecation time. If maxAge is
+// positive, it returns an error when the image is already deprecated or was created more than maxAge ago, otherwise
+// it only warns about deprecated images
+func VerifyAMI(ctx context.Context, ec2API awsapi.EC2, amiID string, maxAge time.Duration) error {
+	output, err := ec2API.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds: []string{amiID},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to find image %q", amiID)
+	}
+	if len(output.Images) < 1 {
+		return NewErrNotFound(amiID)
+	}
+	image := output.Images[0]
+
+	creationDate, err := parseImageTime(image.CreationDate)
+	if err != nil {
+		return errors.Wrapf(err, "parsing creation date of image %q", amiID)
+	}
+	deprecationTime, err := parseImageTime(image.DeprecationTime)
+	if err != nil {
+		return errors.Wrapf(err, "parsing deprecation time of image %q", amiID)
+	}
+
+	now := time.Now()
+	if deprecationTime.IsZero() {
+		logger.Info("image %q was created on %s", amiID, formatImageTime(creationDate))
+	} else {
+		logger.Info("image %q was created on %s and is deprecated as of %s", amiID, formatImageTime(creationDate), formatImageTime(deprecationTime))
+	}
+
+	if !deprecationTime.IsZero() && !deprecationTime.After(now) {
+		msg := fmt.Sprintf("image %q was deprecated on %s", amiID, formatImageTime(deprecationTime))
+		if maxAge > 0 {
+			return errors.New(msg)
+		}
+		logger.Warning(msg)
+	}
+	if maxAge > 0 && !creationDate.IsZero() && now.Sub(creationDate) > maxAge {
+		return fmt.Errorf("image %q was created on %s, more than %s ago", amiID, formatImageTime(creationDate), maxAge)
+	}
+	return nil
+}
+
+// parseImageTime parses the timestamps EC2 reports for images, returning the zero time if value is not set
+func parseImageTime(value *string) (time.Time, error) {
+	if value == nil || *value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, *value)
+}
+
+func formatImageTime(t time.Time) string {
+	if t.IsZero() {
+		return "an unknown date"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,77 @@
+package ami_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	. "github.com/weaveworks/eksctl/pkg/ami"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("VerifyAMI", func() {
+	const amiID = "ami-0123456789abcdef0"
+
+	var p *mockprovider.MockProvider
+
+	mockImage := func(creationDate time.Time, deprecationTime *time.Time) {
+		image := ec2types.Image{
+			ImageId:      aws.String(amiID),
+			CreationDate: aws.String(creationDate.UTC().Format("2006-01-02T15:04:05.000Z")),
+		}
+		if deprecationTime != nil {
+			image.DeprecationTime = aws.String(deprecationTime.UTC().Format("2006-01-02T15:04:05.000Z"))
+		}
+		p.MockEC2().On("DescribeImages", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeImagesInput) bool {
+			return len(input.ImageIds) == 1 && input.ImageIds[0] == amiID
+		})).Return(&ec2.DescribeImagesOutput{
+			Images: []ec2types.Image{image},
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+	})
+
+	It("accepts an image that is recent enough and not deprecated", func() {
+		deprecationTime := time.Now().AddDate(1, 0, 0)
+		mockImage(time.Now().AddDate(0, 0, -10), &deprecationTime)
+
+		Expect(VerifyAMI(context.Background(), p.MockEC2(), amiID, 30*24*time.Hour)).To(Succeed())
+	})
+
+	It("rejects an image older than the maximum age", func() {
+		creationDate := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockImage(creationDate, nil)
+
+		err := VerifyAMI(context.Background(), p.MockEC2(), amiID, 720*time.Hour)
+		Expect(err).To(MatchError(`image "ami-0123456789abcdef0" was created on 2020-01-02T03:04:05Z, more than 720h0m0s ago`))
+	})
+
+	It("rejects a deprecated image when a maximum age is set", func() {
+		deprecationTime := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockImage(time.Now().AddDate(0, 0, -1), &deprecationTime)
+
+		err := VerifyAMI(context.Background(), p.MockEC2(), amiID, 720*time.Hour)
+		Expect(err).To(MatchError(`image "ami-0123456789abcdef0" was deprecated on 2021-01-02T03:04:05Z`))
+	})
+
+	It("only warns about old or deprecated images when no maximum age is set", func() {
+		deprecationTime := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockImage(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), &deprecationTime)
+
+		Expect(VerifyAMI(context.Background(), p.MockEC2(), amiID, 0)).To(Succeed())
+	})
+
+	It("errors when the image does not exist", func() {
+		p.MockEC2().On("DescribeImages", mock.Anything, mock.Anything).Return(&ec2.DescribeImagesOutput{}, nil)
+
+		Expect(VerifyAMI(context.Background(), p.MockEC2(), amiID, 0)).To(MatchError(NewErrNotFound(amiID).Error()))
+	})
+})
@@ -0,0 +1,115 @@
+package v1alpha5
+
+import (
+	"fmt"
+
+	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/utils"
+)
+
+// amiFamilyDeprecation holds the EKS versions from which the AMIs of a family are deprecated and no longer published
+type amiFamilyDeprecation struct {
+	deprecatedIn string
+	// removedIn is empty when the AMIs are deprecated but no version has been announced for their removal yet
+	removedIn   string
+	replacement string
+}
+
+// amiFamilyDeprecations holds the AMI families that are being phased out, along with the families replacing them
+var amiFamilyDeprecations = map[string]amiFamilyDeprecation{
+	NodeImageFamilyUbuntu1804: {
+		deprecatedIn: Version1_22,
+		replacement:  NodeImageFamilyUbuntu2004,
+	},
+	NodeImageFamilyWindowsServer2004CoreContainer: {
+		deprecatedIn: Version1_21,
+		removedIn:    Version1_22,
+		replacement:  NodeImageFamilyWindowsServer2019CoreContainer,
+	},
+	NodeImageFamilyWindowsServer20H2CoreContainer: {
+		deprecatedIn: Version1_22,
+		removedIn:    Version1_23,
+		replacement:  NodeImageFamilyWindowsServer2019CoreContainer,
+	},
+}
+
+// AMIFamilyDeprecation returns a message naming the replacement of amiFamily if it is deprecated for the EKS version,
+// and an error if its AMIs are no longer published for the EKS version
+func AMIFamilyDeprecation(amiFamily, version string) (string, error) {
+	d, ok := amiFamilyDeprecations[amiFamily]
+	if !ok {
+		return "", nil
+	}
+
+	if d.removedIn != "" {
+		removed, err := utils.IsMinVersion(d.removedIn, version)
+		if err != nil {
+			return "", err
+		}
+		if removed {
+			return "", fmt.Errorf("%s AMIs are not published for EKS version %s and above, use %s instead", amiFamily, d.removedIn, d.replacement)
+		}
+	}
+
+	deprecated, err := utils.IsMinVersion(d.deprecatedIn, version)
+	if err != nil {
+		return "", err
+	}
+	if !deprecated {
+		return "", nil
+	}
+	if d.removedIn == "" {
+		return fmt.Sprintf("%s AMIs are deprecated from EKS version %s and will not be published for a future version, use %s instead", amiFamily, d.deprecatedIn, d.replacement), nil
+	}
+	return fmt.Sprintf("%s AMIs are deprecated from EKS version %s and will not be published for EKS version %s and above, use %s instead", amiFamily, d.deprecatedIn, d.removedIn, d.replacement), nil
+}
+
+// ValidateAMIFamilyDeprecations warns about nodegroups using an AMI family that is deprecated for the EKS version of the
+// cluster, or rejects them if strict is set. Nodegroups using a family whose AMIs are no longer published are always
+// rejected. It is skipped when the version is not known yet
+func ValidateAMIFamilyDeprecations(cfg *ClusterConfig, strict bool) error {
+	version := cfg.Metadata.Version
+	if !isResolvedVersion(version) {
+		return nil
+	}
+
+	validateAMIFamily := func(ng *NodeGroupBase, path string) error {
+		if ng.AMIFamily == "" {
+			return nil
+		}
+		msg, err := AMIFamilyDeprecation(ng.AMIFamily, version)
+		if err != nil {
+			return fmt.Errorf("%s.amiFamily: %w", path, err)
+		}
+		if msg == "" {
+			return nil
+		}
+		if strict {
+			return fmt.Errorf("%s.amiFamily: %s; to proceed with a deprecated AMI family, please run again without --strict-deprecations", path, msg)
+		}
+		logger.Warning("%s.amiFamily: %s", path, msg)
+		return nil
+	}
+
+	for i, ng := range cfg.NodeGroups {
+		if err := validateAMIFamily(ng.NodeGroupBase, fmt.Sprintf("nodeGroups[%d]", i)); err != nil {
+			return err
+		}
+	}
+	for i, ng := range cfg.ManagedNodeGroups {
+		if err := validateAMIFamily(ng.NodeGroupBase, fmt.Sprintf("managedNodeGroups[%d]", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isResolvedVersion returns false for an empty version and for the versions that are resolved later on, e.g. auto
+func isResolvedVersion(version string) bool {
+	switch version {
+	case "", "auto", "default", "latest":
+		return false
+	}
+	return true
+}
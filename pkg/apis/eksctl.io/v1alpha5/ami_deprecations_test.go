@@ -0,0 +1,127 @@
+package v1alpha5_test
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/kris-nova/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("AMI family deprecations", func() {
+	type deprecationEntry struct {
+		amiFamily string
+		version   string
+
+		expectedMsg string
+		expectedErr string
+	}
+
+	DescribeTable("AMIFamilyDeprecation", func(e deprecationEntry) {
+		msg, err := api.AMIFamilyDeprecation(e.amiFamily, e.version)
+		if e.expectedErr != "" {
+			Expect(err).To(MatchError(e.expectedErr))
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg).To(Equal(e.expectedMsg))
+	},
+		Entry("Windows Server 2004 on 1.20", deprecationEntry{
+			amiFamily: api.NodeImageFamilyWindowsServer2004CoreContainer,
+			version:   api.Version1_20,
+		}),
+		Entry("Windows Server 2019 on 1.20", deprecationEntry{
+			amiFamily: api.NodeImageFamilyWindowsServer2019CoreContainer,
+			version:   api.Version1_20,
+		}),
+		Entry("Windows Server 2004 on 1.21", deprecationEntry{
+			amiFamily:   api.NodeImageFamilyWindowsServer2004CoreContainer,
+			version:     api.Version1_21,
+			expectedMsg: "WindowsServer2004CoreContainer AMIs are deprecated from EKS version 1.21 and will not be published for EKS version 1.22 and above, use WindowsServer2019CoreContainer instead",
+		}),
+		Entry("Amazon Linux 2 on 1.21", deprecationEntry{
+			amiFamily: api.NodeImageFamilyAmazonLinux2,
+			version:   api.Version1_21,
+		}),
+		Entry("Ubuntu 18.04 on 1.22", deprecationEntry{
+			amiFamily:   api.NodeImageFamilyUbuntu1804,
+			version:     api.Version1_22,
+			expectedMsg: "Ubuntu1804 AMIs are deprecated from EKS version 1.22 and will not be published for a future version, use Ubuntu2004 instead",
+		}),
+		Entry("Ubuntu 20.04 on 1.22", deprecationEntry{
+			amiFamily: api.NodeImageFamilyUbuntu2004,
+			version:   api.Version1_22,
+		}),
+		Entry("Windows Server 20H2 on 1.23", deprecationEntry{
+			amiFamily:   api.NodeImageFamilyWindowsServer20H2CoreContainer,
+			version:     api.Version1_23,
+			expectedErr: "WindowsServer20H2CoreContainer AMIs are not published for EKS version 1.23 and above, use WindowsServer2019CoreContainer instead",
+		}),
+		Entry("Bottlerocket on 1.23", deprecationEntry{
+			amiFamily: api.NodeImageFamilyBottlerocket,
+			version:   api.Version1_23,
+		}),
+	)
+
+	Describe("ValidateAMIFamilyDeprecations", func() {
+		var (
+			cfg          *api.ClusterConfig
+			output       *bytes.Buffer
+			loggerWriter io.Writer
+		)
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Version = api.Version1_22
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ubuntu"
+			ng.AMIFamily = api.NodeImageFamilyUbuntu1804
+			mng := api.NewManagedNodeGroup()
+			mng.Name = "al2"
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{mng}
+
+			output = &bytes.Buffer{}
+			loggerWriter = logger.Writer
+			logger.Writer = output
+		})
+
+		AfterEach(func() {
+			logger.Writer = loggerWriter
+		})
+
+		It("warns about nodegroups using a deprecated AMI family", func() {
+			Expect(api.ValidateAMIFamilyDeprecations(cfg, false)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring("nodeGroups[0].amiFamily: Ubuntu1804 AMIs are deprecated from EKS version 1.22"))
+			Expect(output.String()).NotTo(ContainSubstring("managedNodeGroups[0]"))
+		})
+
+		It("rejects nodegroups using a deprecated AMI family in strict mode", func() {
+			err := api.ValidateAMIFamilyDeprecations(cfg, true)
+			Expect(err).To(MatchError(ContainSubstring("nodeGroups[0].amiFamily: Ubuntu1804 AMIs are deprecated from EKS version 1.22 and will not be published for a future version, use Ubuntu2004 instead")))
+			Expect(output.String()).To(BeEmpty())
+		})
+
+		It("rejects nodegroups using an AMI family that is no longer published", func() {
+			cfg.ManagedNodeGroups[0].AMIFamily = api.NodeImageFamilyWindowsServer2004CoreContainer
+			err := api.ValidateAMIFamilyDeprecations(cfg, false)
+			Expect(err).To(MatchError("managedNodeGroups[0].amiFamily: WindowsServer2004CoreContainer AMIs are not published for EKS version 1.22 and above, use WindowsServer2019CoreContainer instead"))
+		})
+
+		It("is skipped when the version is resolved from the control plane", func() {
+			cfg.Metadata.Version = "auto"
+			Expect(api.ValidateAMIFamilyDeprecations(cfg, true)).To(Succeed())
+		})
+
+		It("rejects AMI families that are no longer published when validating the cluster config", func() {
+			cfg.Metadata.Version = api.Version1_23
+			cfg.NodeGroups[0].AMIFamily = api.NodeImageFamilyWindowsServer20H2CoreContainer
+			err := api.ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError("nodeGroups[0].amiFamily: WindowsServer20H2CoreContainer AMIs are not published for EKS version 1.23 and above, use WindowsServer2019CoreContainer instead"))
+		})
+	})
+})
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/kris-nova/logger"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/aws-sdk-go/aws"
+
+	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
 )
 
 const (
@@ -62,6 +65,27 @@ func SetClusterConfigDefaults(cfg *ClusterConfig) {
 	if cfg.Karpenter != nil && cfg.Karpenter.CreateServiceAccount == nil {
 		cfg.Karpenter.CreateServiceAccount = Disabled()
 	}
+
+	setPrivateDNSNameOptionsDefaults(cfg)
+}
+
+// setPrivateDNSNameOptionsDefaults sets the privateDNSNameOptions of the cluster on the nodegroups that do not set
+// their own, except for those using an existing launch template, which sets the hostname type itself; nothing is set
+// unless the cluster sets a hostname type
+func setPrivateDNSNameOptionsDefaults(cfg *ClusterConfig) {
+	if cfg.PrivateDNSNameOptions == nil || cfg.PrivateDNSNameOptions.HostnameType == "" {
+		return
+	}
+	for _, ng := range cfg.NodeGroups {
+		if ng.PrivateDNSNameOptions == nil && ng.LaunchTemplate == nil {
+			ng.PrivateDNSNameOptions = cfg.PrivateDNSNameOptions.DeepCopy()
+		}
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		if ng.PrivateDNSNameOptions == nil && ng.LaunchTemplate == nil {
+			ng.PrivateDNSNameOptions = cfg.PrivateDNSNameOptions.DeepCopy()
+		}
+	}
 }
 
 // IAMServiceAccountsWithImplicitServiceAccounts adds implicitly created
@@ -98,7 +122,7 @@ func vpccniAddonSpecified(cfg *ClusterConfig) bool {
 // SetNodeGroupDefaults will set defaults for a given nodegroup
 func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 	setNodeGroupBaseDefaults(ng.NodeGroupBase, meta)
-	if ng.InstanceType == "" {
+	if ng.InstanceType == "" && ng.LaunchTemplate == nil {
 		if HasMixedInstances(ng) || !ng.InstanceSelector.IsZero() {
 			ng.InstanceType = "mixed"
 		} else {
@@ -109,8 +133,9 @@ func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 	if ng.AMIFamily == "" {
 		ng.AMIFamily = DefaultNodeImageFamily
 	}
+	setSSHUserDefault(ng.NodeGroupBase)
 
-	setVolumeDefaults(ng.NodeGroupBase, nil)
+	setVolumeDefaults(ng.NodeGroupBase, ng.LaunchTemplate)
 	setDefaultsForAdditionalVolumes(ng.NodeGroupBase)
 
 	if ng.SecurityGroups.WithLocal == nil {
@@ -121,6 +146,7 @@ func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 	}
 
 	setContainerRuntimeDefault(ng)
+	setCPUCreditsDefault(ng)
 }
 
 // SetManagedNodeGroupDefaults sets default values for a ManagedNodeGroup
@@ -129,6 +155,7 @@ func SetManagedNodeGroupDefaults(ng *ManagedNodeGroup, meta *ClusterMeta) {
 	if ng.AMIFamily == "" {
 		ng.AMIFamily = NodeImageFamilyAmazonLinux2
 	}
+	setSSHUserDefault(ng.NodeGroupBase)
 	if ng.LaunchTemplate == nil && ng.InstanceType == "" && len(ng.InstanceTypes) == 0 && ng.InstanceSelector.IsZero() {
 		ng.InstanceType = DefaultNodeType
 	}
@@ -143,6 +170,23 @@ func SetManagedNodeGroupDefaults(ng *ManagedNodeGroup, meta *ClusterMeta) {
 	setDefaultsForAdditionalVolumes(ng.NodeGroupBase)
 }
 
+// setCPUCreditsDefault launches burstable instances in standard mode unless specified otherwise, as unlimited mode,
+// the default for some instance types, incurs additional charges
+func setCPUCreditsDefault(ng *NodeGroup) {
+	instanceTypes := ng.InstanceTypeList()
+	// the instance types selected by instanceRequirements are not known
+	if ng.CPUCredits != nil || len(instanceTypes) == 0 {
+		return
+	}
+	for _, instanceType := range instanceTypes {
+		if !instanceutils.IsBurstableInstanceType(instanceType) {
+			return
+		}
+	}
+	logger.Info("setting cpuCredits of nodegroup %q to %q as it uses burstable instance types", ng.Name, CPUCreditsStandard)
+	ng.CPUCredits = aws.String(CPUCreditsStandard)
+}
+
 func setNodeGroupBaseDefaults(ng *NodeGroupBase, meta *ClusterMeta) {
 	if ng.ScalingConfig == nil {
 		ng.ScalingConfig = &ScalingConfig{}
@@ -230,6 +274,12 @@ func setDefaultsForAdditionalVolumes(ng *NodeGroupBase) {
 
 func setContainerRuntimeDefault(ng *NodeGroup) {
 	if ng.ContainerRuntime == nil {
+		if ng.AMIFamily == NodeImageFamilyAmazonLinux2023 {
+			// AL2023 AMIs only ship containerd
+			containerd := ContainerRuntimeContainerD
+			ng.ContainerRuntime = &containerd
+			return
+		}
 		ng.ContainerRuntime = &DefaultContainerRuntime
 	}
 }
@@ -275,6 +325,9 @@ func setSSHDefaults(sshConfig *NodeGroupSSH) {
 		sshConfig.PublicKeyName,
 		sshConfig.PublicKeyPath,
 		sshConfig.PublicKey)
+	if len(sshConfig.PublicKeyNames) > 0 {
+		numSSHFlagsEnabled++
+	}
 
 	if numSSHFlagsEnabled == 0 {
 		if IsEnabled(sshConfig.Allow) {
@@ -289,6 +342,26 @@ func setSSHDefaults(sshConfig *NodeGroupSSH) {
 
 }
 
+// setSSHUserDefault sets the SSH user of nodegroups allowing SSH access to the default user of their AMI family
+func setSSHUserDefault(ng *NodeGroupBase) {
+	if IsEnabled(ng.SSH.Allow) && ng.SSH.User == "" {
+		ng.SSH.User = DefaultSSHUser(ng.AMIFamily)
+	}
+}
+
+// DefaultSSHUser returns the user created by the images of the AMI family for SSH access
+func DefaultSSHUser(amiFamily string) string {
+	switch {
+	case IsWindowsImage(amiFamily):
+		return "Administrator"
+	case amiFamily == NodeImageFamilyUbuntu2004, amiFamily == NodeImageFamilyUbuntu1804:
+		return "ubuntu"
+	default:
+		// Amazon Linux, and the admin container of Bottlerocket
+		return "ec2-user"
+	}
+}
+
 func setDefaultNodeLabels(labels map[string]string, clusterName, nodeGroupName string) {
 	labels[ClusterNameLabel] = clusterName
 	labels[NodeGroupNameLabel] = nodeGroupName
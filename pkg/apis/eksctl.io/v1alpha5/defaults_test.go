@@ -3,6 +3,7 @@ package v1alpha5
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -126,6 +127,57 @@ var _ = Describe("ClusterConfig validation", func() {
 
 			Expect(*testNodeGroup.SSH.Allow).To(BeFalse())
 			Expect(*testNodeGroup.SSH.PublicKeyPath).To(BeIdenticalTo(testKeyPath))
+			Expect(testNodeGroup.SSH.User).To(BeEmpty())
+		})
+
+		It("Providing several SSH key names enables SSH when SSH.Allow not set", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					SSH: &NodeGroupSSH{
+						PublicKeyNames: []string{"primary", "escrow"},
+					},
+				},
+			}
+
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+
+			Expect(*testNodeGroup.SSH.Allow).To(BeTrue())
+			Expect(testNodeGroup.SSH.PublicKeyPath).To(BeNil())
+		})
+
+		DescribeTable("sets the SSH user to the default user of the AMI family",
+			func(amiFamily, expectedUser string) {
+				testNodeGroup := NodeGroup{
+					NodeGroupBase: &NodeGroupBase{
+						AMIFamily: amiFamily,
+						SSH: &NodeGroupSSH{
+							Allow: Enabled(),
+						},
+					},
+				}
+
+				SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+				Expect(testNodeGroup.SSH.User).To(Equal(expectedUser))
+			},
+			Entry("AmazonLinux2", NodeImageFamilyAmazonLinux2, "ec2-user"),
+			Entry("AmazonLinux2023", NodeImageFamilyAmazonLinux2023, "ec2-user"),
+			Entry("Ubuntu2004", NodeImageFamilyUbuntu2004, "ubuntu"),
+			Entry("Bottlerocket", NodeImageFamilyBottlerocket, "ec2-user"),
+			Entry("WindowsServer2019CoreContainer", NodeImageFamilyWindowsServer2019CoreContainer, "Administrator"),
+		)
+
+		It("keeps the SSH user set on the nodegroup", func() {
+			testNodeGroup := ManagedNodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					SSH: &NodeGroupSSH{
+						Allow: Enabled(),
+						User:  "break-glass",
+					},
+				},
+			}
+
+			SetManagedNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(testNodeGroup.SSH.User).To(Equal("break-glass"))
 		})
 	})
 
@@ -232,6 +284,69 @@ var _ = Describe("ClusterConfig validation", func() {
 			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
 			Expect(*testNodeGroup.ContainerRuntime).To(Equal(DefaultContainerRuntime))
 		})
+
+		It("defaults to containerd for AmazonLinux2023", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					AMIFamily: NodeImageFamilyAmazonLinux2023,
+				},
+			}
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(*testNodeGroup.ContainerRuntime).To(Equal(ContainerRuntimeContainerD))
+		})
+	})
+
+	Context("CPU credits settings", func() {
+		It("defaults to standard for burstable instance types", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					InstanceType: "t3.medium",
+				},
+			}
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(testNodeGroup.CPUCredits).To(Equal(aws.String(CPUCreditsStandard)))
+		})
+
+		It("defaults to standard when all instance types of the instances distribution are burstable", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{},
+				InstancesDistribution: &NodeGroupInstancesDistribution{
+					InstanceTypes: []string{"t3.large", "t3a.large", "t4g.large"},
+				},
+			}
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(testNodeGroup.CPUCredits).To(Equal(aws.String(CPUCreditsStandard)))
+		})
+
+		It("is not set when some instance types are not burstable", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{},
+				InstancesDistribution: &NodeGroupInstancesDistribution{
+					InstanceTypes: []string{"t3.large", "m5.large"},
+				},
+			}
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(testNodeGroup.CPUCredits).To(BeNil())
+		})
+
+		It("is not set for the default instance type", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{},
+			}
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(testNodeGroup.CPUCredits).To(BeNil())
+		})
+
+		It("keeps the value that was set", func() {
+			testNodeGroup := NodeGroup{
+				NodeGroupBase: &NodeGroupBase{
+					InstanceType: "t3.medium",
+				},
+				CPUCredits: aws.String(CPUCreditsUnlimited),
+			}
+			SetNodeGroupDefaults(&testNodeGroup, &ClusterMeta{})
+			Expect(testNodeGroup.CPUCredits).To(Equal(aws.String(CPUCreditsUnlimited)))
+		})
 	})
 
 	Describe("Cluster Managed Shared Node Security Group settings", func() {
@@ -266,6 +381,51 @@ var _ = Describe("ClusterConfig validation", func() {
 
 	})
 
+	Describe("Cluster private DNS name options", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.PrivateDNSNameOptions = &PrivateDNSNameOptions{HostnameType: HostnameTypeResourceName}
+		})
+
+		It("sets the cluster hostname type on the nodegroups that do not set their own", func() {
+			ng := cfg.NewNodeGroup()
+			mng := &ManagedNodeGroup{NodeGroupBase: &NodeGroupBase{Name: "mng"}}
+			cfg.ManagedNodeGroups = []*ManagedNodeGroup{mng}
+			SetClusterConfigDefaults(cfg)
+			Expect(ng.PrivateDNSNameOptions.HostnameType).To(Equal(HostnameTypeResourceName))
+			Expect(mng.PrivateDNSNameOptions.HostnameType).To(Equal(HostnameTypeResourceName))
+		})
+
+		It("does not override the hostname type of a nodegroup", func() {
+			ng := cfg.NewNodeGroup()
+			ng.PrivateDNSNameOptions = &PrivateDNSNameOptions{HostnameType: HostnameTypeIPName}
+			SetClusterConfigDefaults(cfg)
+			Expect(ng.PrivateDNSNameOptions.HostnameType).To(Equal(HostnameTypeIPName))
+		})
+
+		It("does not set the hostname type of nodegroups using an existing launch template", func() {
+			ng := cfg.NewNodeGroup()
+			ng.LaunchTemplate = &LaunchTemplate{ID: "lt-1234"}
+			SetClusterConfigDefaults(cfg)
+			Expect(ng.PrivateDNSNameOptions).To(BeNil())
+		})
+
+		DescribeTable("does not set the hostname type of nodegroups when the cluster does not set one", func(options *PrivateDNSNameOptions) {
+			cfg.PrivateDNSNameOptions = options
+			ng := cfg.NewNodeGroup()
+			mng := &ManagedNodeGroup{NodeGroupBase: &NodeGroupBase{Name: "mng"}}
+			cfg.ManagedNodeGroups = []*ManagedNodeGroup{mng}
+			SetClusterConfigDefaults(cfg)
+			Expect(ng.PrivateDNSNameOptions).To(BeNil())
+			Expect(mng.PrivateDNSNameOptions).To(BeNil())
+		},
+			Entry("privateDNSNameOptions is not set", nil),
+			Entry("privateDNSNameOptions is empty", &PrivateDNSNameOptions{}),
+		)
+	})
+
 	Describe("ClusterConfig", func() {
 		var cfg *ClusterConfig
 
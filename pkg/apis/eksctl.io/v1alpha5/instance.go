@@ -12,7 +12,7 @@ func SelectInstanceType(np NodePool) string {
 	switch ng := np.(type) {
 	case *NodeGroup:
 		if ng.InstancesDistribution != nil {
-			instanceTypes = ng.InstancesDistribution.InstanceTypes
+			instanceTypes = ng.InstancesDistribution.GetInstanceTypes()
 		}
 	case *ManagedNodeGroup:
 		instanceTypes = ng.InstanceTypes
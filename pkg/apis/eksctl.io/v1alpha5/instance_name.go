@@ -0,0 +1,98 @@
+package v1alpha5
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// MaxInstanceNameLength is the maximum length of an EC2 tag value, which limits the length of the Name tag of instances
+const MaxInstanceNameLength = 255
+
+// instanceNameCharset matches the characters allowed in EC2 tag values
+var instanceNameCharset = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// InstanceNameTemplateData holds the values available to instanceNameTemplate
+type InstanceNameTemplateData struct {
+	ClusterName   string
+	NodeGroupName string
+	Region        string
+}
+
+// instanceNameTemplateFuncs is the set of functions available to instanceNameTemplate in addition to the
+// comparison and logical text/template builtins
+var instanceNameTemplateFuncs = withDisallowedBuiltins(template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": func(from, to, s string) string { return strings.ReplaceAll(s, from, to) },
+	"trunc": func(n int, s string) string {
+		if n >= 0 && len(s) > n {
+			return s[:n]
+		}
+		return s
+	},
+})
+
+// withDisallowedBuiltins overrides the text/template builtins that can call arbitrary functions or format values
+// freely, so instanceNameTemplate only has access to the fields and functions it documents
+func withDisallowedBuiltins(funcs template.FuncMap) template.FuncMap {
+	for _, name := range []string{"call", "html", "index", "js", "print", "printf", "println", "slice", "urlquery"} {
+		name := name
+		funcs[name] = func(...interface{}) (string, error) {
+			return "", fmt.Errorf("function %q is not allowed", name)
+		}
+	}
+	return funcs
+}
+
+// InstanceNameTag returns the value of the Name tag of the nodegroup's instances. It renders instanceNameTemplate
+// when set; otherwise instanceName defaults to `<cluster>-<nodegroup>-Node` and is prefixed with instancePrefix
+func (n *NodeGroupBase) InstanceNameTag(meta *ClusterMeta) (string, error) {
+	name := n.defaultInstanceName(meta)
+	if n.InstanceNameTemplate != "" {
+		var err error
+		if name, err = renderInstanceNameTemplate(n.InstanceNameTemplate, InstanceNameTemplateData{
+			ClusterName:   meta.Name,
+			NodeGroupName: n.Name,
+			Region:        meta.Region,
+		}); err != nil {
+			return "", err
+		}
+		if !instanceNameCharset.MatchString(name) {
+			return "", fmt.Errorf("instance name %q rendered from instanceNameTemplate can only contain letters, numbers, spaces and the characters _.:/=+-@", name)
+		}
+	}
+
+	if len(name) > MaxInstanceNameLength {
+		return "", fmt.Errorf("instance name %q is %d characters long, which exceeds the maximum of %d", name, len(name), MaxInstanceNameLength)
+	}
+	return name, nil
+}
+
+func (n *NodeGroupBase) defaultInstanceName(meta *ClusterMeta) string {
+	var nameParts []string
+	if n.InstancePrefix != "" {
+		nameParts = append(nameParts, n.InstancePrefix, "-")
+	}
+	// this overrides the default naming convention
+	if n.InstanceName != "" {
+		nameParts = append(nameParts, n.InstanceName)
+	} else {
+		nameParts = append(nameParts, fmt.Sprintf("%s-%s-Node", meta.Name, n.Name))
+	}
+	return strings.Join(nameParts, "")
+}
+
+func renderInstanceNameTemplate(text string, data InstanceNameTemplateData) (string, error) {
+	tmpl, err := template.New("instanceNameTemplate").Funcs(instanceNameTemplateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing instanceNameTemplate: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering instanceNameTemplate: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,85 @@
+package v1alpha5
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+type instanceNameEntry struct {
+	ng           *NodeGroupBase
+	expectedName string
+	expectedErr  string
+}
+
+var _ = Describe("Instance name", func() {
+	DescribeTable("InstanceNameTag", func(e instanceNameEntry) {
+		meta := &ClusterMeta{Name: "cluster", Region: "us-west-2"}
+		name, err := e.ng.InstanceNameTag(meta)
+		if e.expectedErr != "" {
+			Expect(err).To(MatchError(ContainSubstring(e.expectedErr)))
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal(e.expectedName))
+	},
+		Entry("defaults to <cluster>-<nodegroup>-Node", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng"},
+			expectedName: "cluster-ng-Node",
+		}),
+		Entry("prefixes the default name with instancePrefix", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng", InstancePrefix: "prefix"},
+			expectedName: "prefix-cluster-ng-Node",
+		}),
+		Entry("prefixes instanceName with instancePrefix", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng", InstancePrefix: "prefix", InstanceName: "name"},
+			expectedName: "prefix-name",
+		}),
+		Entry("renders instanceNameTemplate", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng", InstanceNameTemplate: "{{.ClusterName}}-{{.NodeGroupName}}-{{.Region}}"},
+			expectedName: "cluster-ng-us-west-2",
+		}),
+		Entry("renders instanceNameTemplate with functions", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng", InstanceNameTemplate: `{{upper .ClusterName}}-{{replace "-" "" .Region}}-{{trunc 1 .NodeGroupName}}`},
+			expectedName: "CLUSTER-uswest2-n",
+		}),
+		Entry("fails to parse an invalid template", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstanceNameTemplate: "{{.ClusterName"},
+			expectedErr: "parsing instanceNameTemplate",
+		}),
+		Entry("fails to parse a template using a function that is not available", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstanceNameTemplate: `{{env "HOME"}}`},
+			expectedErr: `function "env" not defined`,
+		}),
+		Entry("fails to render a template referencing an unknown field", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstanceNameTemplate: "{{.AccountID}}"},
+			expectedErr: "rendering instanceNameTemplate",
+		}),
+		Entry("fails when the rendered name exceeds the maximum length", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstanceNameTemplate: strings.Repeat("a", MaxInstanceNameLength) + "{{.Region}}"},
+			expectedErr: "which exceeds the maximum of 255",
+		}),
+		Entry("fails when the default name exceeds the maximum length", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstancePrefix: strings.Repeat("a", MaxInstanceNameLength)},
+			expectedErr: "which exceeds the maximum of 255",
+		}),
+		Entry("fails when the rendered name contains invalid characters", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstanceNameTemplate: "{{.ClusterName}}|{{.NodeGroupName}}"},
+			expectedErr: "can only contain letters, numbers, spaces and the characters",
+		}),
+		Entry("does not restrict the characters of instanceName", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng", InstanceName: "team|name"},
+			expectedName: "team|name",
+		}),
+		Entry("fails to render a template calling a builtin that is not allowed", instanceNameEntry{
+			ng:          &NodeGroupBase{Name: "ng", InstanceNameTemplate: `{{printf "%s-node" .ClusterName}}`},
+			expectedErr: `function "printf" is not allowed`,
+		}),
+		Entry("renders a template using comparison builtins", instanceNameEntry{
+			ng:           &NodeGroupBase{Name: "ng", InstanceNameTemplate: `{{if eq .Region "us-west-2"}}oregon{{else}}{{.Region}}{{end}}-{{.NodeGroupName}}`},
+			expectedName: "oregon-ng",
+		}),
+	)
+})
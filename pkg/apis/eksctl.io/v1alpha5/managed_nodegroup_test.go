@@ -137,7 +137,8 @@ var _ = Describe("Managed Nodegroup Validation", func() {
 		err := ValidateManagedNodeGroup(0, mng)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("cannot set instanceType, ami, ssh.allow, ssh.enableSSM, ssh.sourceSecurityGroupIds, securityGroups, " +
-			"volumeSize, instanceName, instancePrefix, maxPodsPerNode, disableIMDSv1, disablePodIMDS, preBootstrapCommands, overrideBootstrapCommand, placement in managedNodeGroup when a launch template is supplied"))
+			"volumeSize, instanceName, instancePrefix, instanceNameTemplate, maxPodsPerNode, disableIMDSv1, disablePodIMDS, preBootstrapCommands, overrideBootstrapCommand, placement, " +
+			"launchTemplateTagSpecifications, capacityReservation, disableLaunchTemplateMetadataOptions, privateDNSNameOptions in managedNodeGroup when a launch template is supplied"))
 	},
 		Entry("instanceType", &NodeGroupBase{
 			InstanceType: "m5.xlarge",
@@ -164,6 +165,9 @@ var _ = Describe("Managed Nodegroup Validation", func() {
 				AttachIDs: []string{"sg-custom"},
 			},
 		}),
+		Entry("disableLaunchTemplateMetadataOptions", &NodeGroupBase{
+			DisableLaunchTemplateMetadataOptions: Enabled(),
+		}),
 	)
 
 	type updateConfigEntry struct {
@@ -0,0 +1,77 @@
+package v1alpha5
+
+import (
+	"errors"
+	"reflect"
+)
+
+// MergeNodeGroupDefaults deep-merges nodeGroupDefaults into every nodegroup and managedNodeGroupDefaults into
+// every managed nodegroup, with the values set on a nodegroup taking precedence over the defaults.
+// The defaults are removed from the ClusterConfig once they have been merged
+func MergeNodeGroupDefaults(clusterConfig *ClusterConfig) error {
+	if defaults := clusterConfig.NodeGroupDefaults; defaults != nil {
+		if defaults.NodeGroupBase != nil && defaults.Name != "" {
+			return errors.New("nodeGroupDefaults.name cannot be set")
+		}
+		for _, ng := range clusterConfig.NodeGroups {
+			mergeDefaults(reflect.ValueOf(ng).Elem(), reflect.ValueOf(defaults.DeepCopy()).Elem())
+		}
+		clusterConfig.NodeGroupDefaults = nil
+	}
+
+	if defaults := clusterConfig.ManagedNodeGroupDefaults; defaults != nil {
+		if defaults.NodeGroupBase != nil && defaults.Name != "" {
+			return errors.New("managedNodeGroupDefaults.name cannot be set")
+		}
+		for _, ng := range clusterConfig.ManagedNodeGroups {
+			mergeDefaults(reflect.ValueOf(ng).Elem(), reflect.ValueOf(defaults.DeepCopy()).Elem())
+		}
+		clusterConfig.ManagedNodeGroupDefaults = nil
+	}
+
+	return nil
+}
+
+// mergeDefaults sets every unset field of dst to the value of the same field in defaults, recursing into
+// nested structs and merging the keys of maps. Slices and scalar values set on dst are kept as they are;
+// note that a non-pointer field set to its zero value is indistinguishable from an unset one
+func mergeDefaults(dst, defaults reflect.Value) {
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Type().Field(i).PkgPath != "" {
+				// unexported field
+				continue
+			}
+			mergeDefaults(dst.Field(i), defaults.Field(i))
+		}
+	case reflect.Ptr:
+		if defaults.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(defaults)
+			return
+		}
+		if dst.Elem().Kind() == reflect.Struct {
+			mergeDefaults(dst.Elem(), defaults.Elem())
+		}
+	case reflect.Map:
+		if defaults.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(defaults)
+			return
+		}
+		for _, key := range defaults.MapKeys() {
+			if !dst.MapIndex(key).IsValid() {
+				dst.SetMapIndex(key, defaults.MapIndex(key))
+			}
+		}
+	default:
+		if dst.IsZero() {
+			dst.Set(defaults)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package v1alpha5
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+type mergeDefaultsEntry struct {
+	defaults *NodeGroupBase
+	ng       *NodeGroupBase
+	expected *NodeGroupBase
+}
+
+var _ = Describe("Nodegroup defaults", func() {
+	DescribeTable("merging nodeGroupDefaults", func(e mergeDefaultsEntry) {
+		clusterConfig := NewClusterConfig()
+		clusterConfig.NodeGroupDefaults = &NodeGroup{NodeGroupBase: e.defaults}
+		clusterConfig.NodeGroups = []*NodeGroup{{NodeGroupBase: e.ng}}
+
+		Expect(MergeNodeGroupDefaults(clusterConfig)).To(Succeed())
+		Expect(clusterConfig.NodeGroupDefaults).To(BeNil())
+		Expect(clusterConfig.NodeGroups[0].NodeGroupBase).To(Equal(e.expected))
+	},
+		Entry("scalar fields are set when unset on the nodegroup", mergeDefaultsEntry{
+			defaults: &NodeGroupBase{InstanceType: "m5.large", AMIFamily: NodeImageFamilyAmazonLinux2},
+			ng:       &NodeGroupBase{Name: "ng", InstanceType: "m5.xlarge"},
+			expected: &NodeGroupBase{Name: "ng", InstanceType: "m5.xlarge", AMIFamily: NodeImageFamilyAmazonLinux2},
+		}),
+		Entry("pointer fields are set when nil on the nodegroup", mergeDefaultsEntry{
+			defaults: &NodeGroupBase{
+				ScalingConfig: &ScalingConfig{MinSize: aws.Int(1), MaxSize: aws.Int(5)},
+				VolumeSize:    aws.Int(100),
+			},
+			ng: &NodeGroupBase{
+				Name:          "ng",
+				ScalingConfig: &ScalingConfig{MinSize: aws.Int(2)},
+			},
+			expected: &NodeGroupBase{
+				Name:          "ng",
+				ScalingConfig: &ScalingConfig{MinSize: aws.Int(2), MaxSize: aws.Int(5)},
+				VolumeSize:    aws.Int(100),
+			},
+		}),
+		Entry("nested structs are merged", mergeDefaultsEntry{
+			defaults: &NodeGroupBase{
+				SSH: &NodeGroupSSH{Allow: aws.Bool(true), PublicKeyName: aws.String("default-key")},
+				IAM: &NodeGroupIAM{
+					WithAddonPolicies: NodeGroupIAMAddonPolicies{ImageBuilder: aws.Bool(true), AutoScaler: aws.Bool(false)},
+				},
+			},
+			ng: &NodeGroupBase{
+				Name: "ng",
+				SSH:  &NodeGroupSSH{PublicKeyName: aws.String("my-key")},
+				IAM: &NodeGroupIAM{
+					WithAddonPolicies: NodeGroupIAMAddonPolicies{AutoScaler: aws.Bool(true)},
+				},
+			},
+			expected: &NodeGroupBase{
+				Name: "ng",
+				SSH:  &NodeGroupSSH{Allow: aws.Bool(true), PublicKeyName: aws.String("my-key")},
+				IAM: &NodeGroupIAM{
+					WithAddonPolicies: NodeGroupIAMAddonPolicies{ImageBuilder: aws.Bool(true), AutoScaler: aws.Bool(true)},
+				},
+			},
+		}),
+		Entry("maps are merged with the nodegroup's keys taking precedence", mergeDefaultsEntry{
+			defaults: &NodeGroupBase{Labels: map[string]string{"team": "platform", "role": "default"}},
+			ng:       &NodeGroupBase{Name: "ng", Labels: map[string]string{"role": "builders"}},
+			expected: &NodeGroupBase{Name: "ng", Labels: map[string]string{"team": "platform", "role": "builders"}},
+		}),
+		Entry("slices set on the nodegroup are not merged", mergeDefaultsEntry{
+			defaults: &NodeGroupBase{AvailabilityZones: []string{"us-west-2a", "us-west-2b"}, PreBootstrapCommands: []string{"echo default"}},
+			ng:       &NodeGroupBase{Name: "ng", AvailabilityZones: []string{"us-west-2c"}},
+			expected: &NodeGroupBase{Name: "ng", AvailabilityZones: []string{"us-west-2c"}, PreBootstrapCommands: []string{"echo default"}},
+		}),
+		Entry("a nodegroup without a base gets all the defaults", mergeDefaultsEntry{
+			defaults: &NodeGroupBase{InstanceType: "m5.large", VolumeSize: aws.Int(100)},
+			ng:       nil,
+			expected: &NodeGroupBase{InstanceType: "m5.large", VolumeSize: aws.Int(100)},
+		}),
+	)
+
+	It("merges managedNodeGroupDefaults into every managed nodegroup without sharing values", func() {
+		clusterConfig := NewClusterConfig()
+		clusterConfig.ManagedNodeGroupDefaults = &ManagedNodeGroup{
+			NodeGroupBase: &NodeGroupBase{ScalingConfig: &ScalingConfig{MinSize: aws.Int(1)}},
+			InstanceTypes: []string{"m5.large", "m5a.large"},
+			Spot:          true,
+		}
+		clusterConfig.ManagedNodeGroups = []*ManagedNodeGroup{
+			{NodeGroupBase: &NodeGroupBase{Name: "mng-1"}},
+			{NodeGroupBase: &NodeGroupBase{Name: "mng-2"}, InstanceTypes: []string{"c5.large"}},
+		}
+
+		Expect(MergeNodeGroupDefaults(clusterConfig)).To(Succeed())
+		Expect(clusterConfig.ManagedNodeGroupDefaults).To(BeNil())
+
+		mng1, mng2 := clusterConfig.ManagedNodeGroups[0], clusterConfig.ManagedNodeGroups[1]
+		Expect(mng1.InstanceTypes).To(Equal([]string{"m5.large", "m5a.large"}))
+		Expect(mng2.InstanceTypes).To(Equal([]string{"c5.large"}))
+		Expect(mng1.Spot).To(BeTrue())
+		Expect(mng2.Spot).To(BeTrue())
+		Expect(*mng1.MinSize).To(Equal(1))
+		Expect(mng1.MinSize).NotTo(BeIdenticalTo(mng2.MinSize))
+	})
+
+	It("does not allow setting the name of the defaults", func() {
+		clusterConfig := NewClusterConfig()
+		clusterConfig.NodeGroupDefaults = &NodeGroup{NodeGroupBase: &NodeGroupBase{Name: "ng"}}
+		Expect(MergeNodeGroupDefaults(clusterConfig)).To(MatchError("nodeGroupDefaults.name cannot be set"))
+	})
+})
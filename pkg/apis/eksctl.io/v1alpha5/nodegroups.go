@@ -8,7 +8,7 @@ func HasInstanceType(nodeGroup *NodeGroup, hasType func(string) bool) bool {
 		return true
 	}
 	if nodeGroup.InstancesDistribution != nil {
-		for _, instanceType := range nodeGroup.InstancesDistribution.InstanceTypes {
+		for _, instanceType := range nodeGroup.InstancesDistribution.GetInstanceTypes() {
 			if hasType(instanceType) {
 				return true
 			}
@@ -171,20 +171,92 @@ const (
 	PartitionUSGov = "aws-us-gov"
 )
 
+// Values for `CPUCredits`
+const (
+	// CPUCreditsStandard configures burstable instances to accrue and spend CPU credits without exceeding them
+	CPUCreditsStandard = "standard"
+	// CPUCreditsUnlimited allows burstable instances to burst beyond their CPU credits, which incurs additional charges
+	CPUCreditsUnlimited = "unlimited"
+)
+
+// Keys of `LaunchTemplateTagSpecifications`
+const (
+	// LaunchTemplateResourceTypeInstance tags the instances launched by the launch template
+	LaunchTemplateResourceTypeInstance = "instance"
+	// LaunchTemplateResourceTypeVolume tags the EBS volumes of the instances
+	LaunchTemplateResourceTypeVolume = "volume"
+	// LaunchTemplateResourceTypeNetworkInterface tags the network interfaces of the instances
+	LaunchTemplateResourceTypeNetworkInterface = "network-interface"
+)
+
+// Values for `PlacementTenancy`
+const (
+	// PlacementTenancyDefault runs instances on shared hardware (default)
+	PlacementTenancyDefault = "default"
+	// PlacementTenancyDedicated runs instances on single-tenant hardware
+	PlacementTenancyDedicated = "dedicated"
+	// PlacementTenancyHost runs instances on Dedicated Hosts
+	PlacementTenancyHost = "host"
+)
+
+// Values for `ShutdownBehavior`
+const (
+	// ShutdownBehaviorStop stops the instances that are shut down (default)
+	ShutdownBehaviorStop = "stop"
+	// ShutdownBehaviorTerminate terminates the instances that are shut down, which removes them from the ASG
+	ShutdownBehaviorTerminate = "terminate"
+)
+
+// Values for `HostnameType`
+const (
+	// HostnameTypeIPName names the instances after their private IPv4 address (default)
+	HostnameTypeIPName = "ip-name"
+	// HostnameTypeResourceName names the instances after their instance ID
+	HostnameTypeResourceName = "resource-name"
+)
+
+// Values for `CapacityReservationPreference`
+const (
+	CapacityReservationPreferenceOpen = "open"
+	CapacityReservationPreferenceNone = "none"
+)
+
 // Values for `NodeAMIFamily`
 // All valid values of supported families should go in this block
 const (
 	// DefaultNodeImageFamily (default)
-	DefaultNodeImageFamily      = NodeImageFamilyAmazonLinux2
-	NodeImageFamilyAmazonLinux2 = "AmazonLinux2"
-	NodeImageFamilyUbuntu2004   = "Ubuntu2004"
-	NodeImageFamilyUbuntu1804   = "Ubuntu1804"
-	NodeImageFamilyBottlerocket = "Bottlerocket"
+	DefaultNodeImageFamily         = NodeImageFamilyAmazonLinux2
+	NodeImageFamilyAmazonLinux2    = "AmazonLinux2"
+	NodeImageFamilyAmazonLinux2023 = "AmazonLinux2023"
+	NodeImageFamilyUbuntu2004      = "Ubuntu2004"
+	NodeImageFamilyUbuntu1804      = "Ubuntu1804"
+	NodeImageFamilyBottlerocket    = "Bottlerocket"
 
 	NodeImageFamilyWindowsServer2019CoreContainer = "WindowsServer2019CoreContainer"
 	NodeImageFamilyWindowsServer2019FullContainer = "WindowsServer2019FullContainer"
 	NodeImageFamilyWindowsServer2004CoreContainer = "WindowsServer2004CoreContainer"
 	NodeImageFamilyWindowsServer20H2CoreContainer = "WindowsServer20H2CoreContainer"
+	NodeImageFamilyWindowsServer2022CoreContainer = "WindowsServer2022CoreContainer"
+	NodeImageFamilyWindowsServer2022FullContainer = "WindowsServer2022FullContainer"
+)
+
+// Values for `AMIResolutionMode`
+const (
+	// AMIResolutionModeEksctl resolves the AMI when eksctl renders the template, which references the AMI ID (default)
+	AMIResolutionModeEksctl = "eksctl"
+	// AMIResolutionModeCloudFormation makes CloudFormation resolve the AMI from the SSM parameter of the EKS-optimized
+	// AMI each time the stack is updated
+	AMIResolutionModeCloudFormation = "cloudformation"
+)
+
+// Values for `AttachCNIPolicy`
+const (
+	// AttachCNIPolicyAuto attaches the AmazonEKS_CNI_Policy unless the VPC CNI uses IRSA (default)
+	AttachCNIPolicyAuto = "auto"
+	// AttachCNIPolicyAlways attaches the AmazonEKS_CNI_Policy
+	AttachCNIPolicyAlways = "always"
+	// AttachCNIPolicyNever does not attach the AmazonEKS_CNI_Policy, the VPC CNI must use IRSA
+	AttachCNIPolicyNever = "never"
 )
 
 // Container runtime values.
@@ -193,6 +265,13 @@ const (
 	ContainerRuntimeDockerD    = "dockerd"
 )
 
+// Warm pool states.
+const (
+	WarmPoolStateStopped    = "Stopped"
+	WarmPoolStateRunning    = "Running"
+	WarmPoolStateHibernated = "Hibernated"
+)
+
 const (
 	// DefaultNodeType is the default instance type to use for nodes
 	DefaultNodeType = "m5.large"
@@ -212,6 +291,9 @@ const (
 	// ClusterNameTag defines the tag of the cluster name
 	ClusterNameTag = "alpha.eksctl.io/cluster-name"
 
+	// ClusterConfigHashTag defines the hash of the config a cluster was created from
+	ClusterConfigHashTag = "alpha.eksctl.io/cluster-config-hash"
+
 	// OldClusterNameTag defines the tag of the cluster name
 	OldClusterNameTag = "eksctl.cluster.k8s.io/v1alpha1/cluster-name"
 
@@ -221,6 +303,12 @@ const (
 	// NodeGroupTypeTag defines the nodegroup type as managed or unmanaged
 	NodeGroupTypeTag = "alpha.eksctl.io/nodegroup-type"
 
+	// NodeGroupAMIFamilyTag defines the AMI family of an unmanaged nodegroup
+	NodeGroupAMIFamilyTag = "alpha.eksctl.io/nodegroup-ami-family"
+
+	// NodeGroupSSHUserTag defines the user to SSH onto the nodes of a nodegroup as
+	NodeGroupSSHUserTag = "alpha.eksctl.io/nodegroup-ssh-user"
+
 	// OldNodeGroupNameTag defines the tag of the nodegroup name
 	OldNodeGroupNameTag = "eksctl.io/v1alpha2/nodegroup-name"
 
@@ -247,6 +335,9 @@ const (
 
 	EKSNodeGroupNameLabel = "eks.amazonaws.com/nodegroup"
 
+	// ClusterAutoscalerTagPrefix is the prefix of the ASG tags used by the cluster autoscaler
+	ClusterAutoscalerTagPrefix = "k8s.io/cluster-autoscaler/"
+
 	// SpotAllocationStrategyLowestPrice defines the ASG spot allocation strategy of lowest-price
 	SpotAllocationStrategyLowestPrice = "lowest-price"
 
@@ -262,6 +353,24 @@ const (
 	// https://docs.aws.amazon.com/autoscaling/ec2/userguide/asg-purchase-options.html#asg-spot-strategy
 	SpotAllocationStrategyCapacityOptimizedPrioritized = "capacity-optimized-prioritized"
 
+	// SpotAllocationStrategyPriceCapacityOptimized defines the ASG spot allocation strategy of price-capacity-optimized
+	SpotAllocationStrategyPriceCapacityOptimized = "price-capacity-optimized"
+
+	// OnDemandAllocationStrategyLowestPrice defines the ASG on-demand allocation strategy of lowest-price
+	OnDemandAllocationStrategyLowestPrice = "lowest-price"
+
+	// OnDemandAllocationStrategyPrioritized defines the ASG on-demand allocation strategy of prioritized, which
+	// launches on-demand instances of the instance types in the order they are listed in
+	OnDemandAllocationStrategyPrioritized = "prioritized"
+
+	// CapacityReservationPreferenceReservationsFirst defines the ASG capacity reservation preference that launches on-demand
+	// instances into Capacity Reservations while they have available capacity, and outside of them otherwise
+	CapacityReservationPreferenceReservationsFirst = "capacity-reservations-first"
+
+	// CapacityReservationPreferenceReservationsOnly defines the ASG capacity reservation preference that only launches
+	// instances into Capacity Reservations
+	CapacityReservationPreferenceReservationsOnly = "capacity-reservations-only"
+
 	// eksResourceAccountStandard defines the AWS EKS account ID that provides node resources in default regions
 	// for standard AWS partition
 	eksResourceAccountStandard = "602401143452"
@@ -500,6 +609,7 @@ func SupportedNodeVolumeTypes() []string {
 func supportedAMIFamilies() []string {
 	return []string{
 		NodeImageFamilyAmazonLinux2,
+		NodeImageFamilyAmazonLinux2023,
 		NodeImageFamilyUbuntu2004,
 		NodeImageFamilyUbuntu1804,
 		NodeImageFamilyBottlerocket,
@@ -507,6 +617,8 @@ func supportedAMIFamilies() []string {
 		NodeImageFamilyWindowsServer2019FullContainer,
 		NodeImageFamilyWindowsServer2004CoreContainer,
 		NodeImageFamilyWindowsServer20H2CoreContainer,
+		NodeImageFamilyWindowsServer2022CoreContainer,
+		NodeImageFamilyWindowsServer2022FullContainer,
 	}
 }
 
@@ -516,6 +628,23 @@ func supportedSpotAllocationStrategies() []string {
 		SpotAllocationStrategyLowestPrice,
 		SpotAllocationStrategyCapacityOptimized,
 		SpotAllocationStrategyCapacityOptimizedPrioritized,
+		SpotAllocationStrategyPriceCapacityOptimized,
+	}
+}
+
+// supportedOnDemandAllocationStrategies are the on-demand allocation strategies supported by ASG
+func supportedOnDemandAllocationStrategies() []string {
+	return []string{
+		OnDemandAllocationStrategyLowestPrice,
+		OnDemandAllocationStrategyPrioritized,
+	}
+}
+
+// supportedCapacityReservationPreferences are the capacity reservation preferences of ASG that eksctl supports
+func supportedCapacityReservationPreferences() []string {
+	return []string{
+		CapacityReservationPreferenceReservationsFirst,
+		CapacityReservationPreferenceReservationsOnly,
 	}
 }
 
@@ -529,6 +658,26 @@ func isSpotAllocationStrategySupported(allocationStrategy string) bool {
 	return false
 }
 
+// isOnDemandAllocationStrategySupported returns true if the on-demand allocation strategy is supported for ASG
+func isOnDemandAllocationStrategySupported(allocationStrategy string) bool {
+	for _, strategy := range supportedOnDemandAllocationStrategies() {
+		if strategy == allocationStrategy {
+			return true
+		}
+	}
+	return false
+}
+
+// isCapacityReservationPreferenceSupported returns true if the capacity reservation preference is supported for ASG
+func isCapacityReservationPreferenceSupported(preference string) bool {
+	for _, p := range supportedCapacityReservationPreferences() {
+		if p == preference {
+			return true
+		}
+	}
+	return false
+}
+
 // EKSResourceAccountID provides worker node resources(ami/ecr image) in different aws account
 // for different aws partitions & opt-in regions.
 func EKSResourceAccountID(region string) string {
@@ -580,6 +729,9 @@ type KubernetesNetworkConfig struct {
 	IPFamily string `json:"ipFamily,omitempty"`
 	// ServiceIPv4CIDR is the CIDR range from where `ClusterIP`s are assigned
 	ServiceIPv4CIDR string `json:"serviceIPv4CIDR,omitempty"`
+	// ServiceIPv6CIDR is the CIDR range EKS assigns `ClusterIP`s from in IPv6 clusters, it is only known once the
+	// cluster has been created
+	ServiceIPv6CIDR string `json:"-"`
 }
 
 func (k *KubernetesNetworkConfig) IPv6Enabled() bool {
@@ -718,6 +870,11 @@ type ClusterConfig struct {
 	// +optional
 	PrivateCluster *PrivateCluster `json:"privateCluster,omitempty"`
 
+	// PrivateDNSNameOptions configures the hostname of the instances of all
+	// nodegroups that do not set their own `privateDNSNameOptions`
+	// +optional
+	PrivateDNSNameOptions *PrivateDNSNameOptions `json:"privateDNSNameOptions,omitempty"`
+
 	// NodeGroups For information and examples see [nodegroups](/usage/managing-nodegroups)
 	// +optional
 	NodeGroups []*NodeGroup `json:"nodeGroups,omitempty"`
@@ -727,12 +884,45 @@ type ClusterConfig struct {
 	// +optional
 	ManagedNodeGroups []*ManagedNodeGroup `json:"managedNodeGroups,omitempty"`
 
+	// NodeGroupDefaults are deep-merged into every nodegroup in `nodeGroups`,
+	// values set on a nodegroup take precedence
+	// +optional
+	NodeGroupDefaults *NodeGroup `json:"nodeGroupDefaults,omitempty"`
+
+	// ManagedNodeGroupDefaults are deep-merged into every managed nodegroup in `managedNodeGroups`,
+	// values set on a managed nodegroup take precedence
+	// +optional
+	ManagedNodeGroupDefaults *ManagedNodeGroup `json:"managedNodeGroupDefaults,omitempty"`
+
 	// +optional
 	FargateProfiles []*FargateProfile `json:"fargateProfiles,omitempty"`
 
 	// +optional
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
 
+	// AvailabilityZoneIDs lists the IDs of the availability zones of the cluster (e.g. `use1-az1`),
+	// instead of their names in `availabilityZones`. Unlike zone names, which map to different
+	// zones in each account, zone IDs identify the same zones in all accounts
+	// +optional
+	AvailabilityZoneIDs []string `json:"availabilityZoneIDs,omitempty"`
+
+	// AvailabilityZoneCount is the number of availability zones that are selected for the cluster when
+	// availabilityZones is not set, it defaults to 3, or 2 in us-east-1
+	// +optional
+	AvailabilityZoneCount *int `json:"availabilityZoneCount,omitempty"`
+
+	// DeniedAvailabilityZoneIDs lists the IDs of zones (e.g. `use1-az3`) that are never
+	// selected when choosing the availability zones of the cluster, in addition to the
+	// zones eksctl already avoids
+	// +optional
+	DeniedAvailabilityZoneIDs []string `json:"deniedAvailabilityZoneIDs,omitempty"`
+
+	// LocalZones lists the Local Zones (e.g. `us-west-2-lax-1a`) and Wavelength Zones in which
+	// subnets are created along with those of `availabilityZones`. Nodegroups are placed in
+	// these zones by listing them in their `availabilityZones`
+	// +optional
+	LocalZones []string `json:"localZones,omitempty"`
+
 	// See [CloudWatch support](/usage/cloudwatch-cluster-logging/)
 	// +optional
 	CloudWatch *ClusterCloudWatch `json:"cloudWatch,omitempty"`
@@ -831,8 +1021,11 @@ func NewClusterIAM() *ClusterIAM {
 	}
 }
 
-// AppendAvailabilityZone appends a new AZ to the set
+// AppendAvailabilityZone appends a new AZ to the set, unless it is one of the Local Zones of the cluster
 func (c *ClusterConfig) AppendAvailabilityZone(newAZ string) {
+	if c.IsLocalZone(newAZ) {
+		return
+	}
 	for _, az := range c.AvailabilityZones {
 		if az == newAZ {
 			return
@@ -841,15 +1034,39 @@ func (c *ClusterConfig) AppendAvailabilityZone(newAZ string) {
 	c.AvailabilityZones = append(c.AvailabilityZones, newAZ)
 }
 
+// IsLocalZone returns true if zone is one of the Local Zones or Wavelength Zones of the cluster
+func (c *ClusterConfig) IsLocalZone(zone string) bool {
+	for _, localZone := range c.LocalZones {
+		if localZone == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// AllZones returns the availability zones of the cluster followed by its Local Zones and Wavelength Zones
+func (c *ClusterConfig) AllZones() []string {
+	return append(append([]string{}, c.AvailabilityZones...), c.LocalZones...)
+}
+
 func (c *ClusterConfig) IPv6Enabled() bool {
 	return c.KubernetesNetworkConfig != nil && c.KubernetesNetworkConfig.IPv6Enabled()
 }
 
+// IsSharedNodeSecurityGroupDisabled returns true if the shared node security group is not created for the cluster
+func (c *ClusterConfig) IsSharedNodeSecurityGroupDisabled() bool {
+	return c.VPC != nil && c.VPC.SharedNodeSecurityGroup == SharedNodeSecurityGroupDisabled
+}
+
 // SetClusterStatus populates ClusterStatus using *eks.Cluster.
 func (c *ClusterConfig) SetClusterStatus(cluster *eks.Cluster) error {
-	if networkConfig := cluster.KubernetesNetworkConfig; networkConfig != nil && networkConfig.ServiceIpv4Cidr != nil {
-		c.Status.KubernetesNetworkConfig = &KubernetesNetworkConfig{
-			ServiceIPv4CIDR: *networkConfig.ServiceIpv4Cidr,
+	if networkConfig := cluster.KubernetesNetworkConfig; networkConfig != nil && (networkConfig.ServiceIpv4Cidr != nil || networkConfig.ServiceIpv6Cidr != nil) {
+		c.Status.KubernetesNetworkConfig = &KubernetesNetworkConfig{}
+		if networkConfig.ServiceIpv4Cidr != nil {
+			c.Status.KubernetesNetworkConfig.ServiceIPv4CIDR = *networkConfig.ServiceIpv4Cidr
+		}
+		if networkConfig.ServiceIpv6Cidr != nil {
+			c.Status.KubernetesNetworkConfig.ServiceIPv6CIDR = *networkConfig.ServiceIpv6Cidr
 		}
 	}
 	data, err := base64.StdEncoding.DecodeString(*cluster.CertificateAuthority.Data)
@@ -960,10 +1177,15 @@ type NodeGroup struct {
 	//+optional
 	InstancesDistribution *NodeGroupInstancesDistribution `json:"instancesDistribution,omitempty"`
 
+	// Spot launches the instances of the nodegroup as spot instances, for nodegroups without `instancesDistribution`
+	// +optional
+	Spot bool `json:"spot,omitempty"`
+
 	// +optional
 	ASGMetricsCollection []MetricsCollection `json:"asgMetricsCollection,omitempty"`
 
-	// CPUCredits configures [T3 Unlimited](https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/burstable-performance-instances-unlimited-mode.html), valid only for T-type instances
+	// CPUCredits configures [T3 Unlimited](https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/burstable-performance-instances-unlimited-mode.html), valid only for T-type instances.
+	// When unset, `standard` is used if all instance types of the nodegroup are T-type instances
 	// +optional
 	CPUCredits *string `json:"cpuCredits,omitempty"`
 
@@ -1005,9 +1227,43 @@ type NodeGroup struct {
 	// +optional
 	DisableASGTagPropagation *bool `json:"disableASGTagPropagation,omitempty"`
 
+	// DisableDefaultInstanceTags lists the keys of tags that eksctl sets on the AutoScalingGroup, e.g. `Name`, that
+	// are not propagated to the instances of the nodegroup. The tags are still set on the AutoScalingGroup. The
+	// `kubernetes.io/cluster/<name>` tag cannot be disabled, as the cloud provider requires it
+	// +optional
+	DisableDefaultInstanceTags []string `json:"disableDefaultInstanceTags,omitempty"`
+
 	// MaxInstanceLifetime defines the maximum amount of time in seconds an instance stays alive.
 	// +optional
 	MaxInstanceLifetime *int `json:"maxInstanceLifetime,omitempty"`
+
+	// ASGContext is a reserved field that sets the context of the AutoScalingGroup.
+	// +optional
+	ASGContext *string `json:"asgContext,omitempty"`
+
+	// WarmPool configures a [warm
+	// pool](https://docs.aws.amazon.com/autoscaling/ec2/userguide/ec2-auto-scaling-warm-pools.html)
+	// of pre-initialized instances for the AutoScalingGroup
+	// +optional
+	WarmPool *WarmPool `json:"warmPool,omitempty"`
+
+	// AMIResolutionMode sets whether eksctl or CloudFormation resolves the AMI of the nodegroup, valid variants are
+	// `AMIResolutionMode` constants. With `cloudformation`, the launch template references the SSM parameter of the
+	// EKS-optimized AMI, so that updating the stack picks up the latest AMI
+	// +optional
+	AMIResolutionMode string `json:"amiResolutionMode,omitempty"`
+
+	// AMIReleaseVersion pins the AMI resolved for the nodegroup to a release, instead of the latest one. For
+	// AmazonLinux2 and AmazonLinux2023, it is the release version of the EKS-optimized AMI, e.g.
+	// `amazon-eks-node-1.27-v20231201`, and for Bottlerocket the version of the release, e.g. `1.19.2`
+	// +optional
+	AMIReleaseVersion string `json:"amiReleaseVersion,omitempty"`
+
+	// LaunchTemplate specifies an existing launch template, e.g. one managed outside of eksctl, to use for the
+	// AutoScalingGroup of the nodegroup instead of the launch template that eksctl generates. The launch template
+	// sets the AMI, instance type, user data and instance profile of the nodes
+	// +optional
+	LaunchTemplate *LaunchTemplate `json:"launchTemplate,omitempty"`
 }
 
 // GetContainerRuntime returns the container runtime.
@@ -1020,7 +1276,7 @@ func (n *NodeGroup) GetContainerRuntime() string {
 
 func (n *NodeGroup) InstanceTypeList() []string {
 	if HasMixedInstances(n) {
-		return n.InstancesDistribution.InstanceTypes
+		return n.InstancesDistribution.GetInstanceTypes()
 	}
 	return []string{n.InstanceType}
 }
@@ -1088,6 +1344,15 @@ type (
 		// Defaults to `true`
 		// +optional
 		WithLocal *bool `json:"withLocal"`
+		// RestrictControlPlaneRules narrows the rules between the control plane and the local security group
+		// of the nodegroup to the kubelet port and HTTPS, instead of all ports from 1025
+		// Not supported for managed nodegroups
+		// +optional
+		RestrictControlPlaneRules *bool `json:"restrictControlPlaneRules,omitempty"`
+		// AdditionalControlPlanePorts lists the ports, other than the kubelet port and HTTPS, that the control plane
+		// can reach the nodes on when RestrictControlPlaneRules is enabled, e.g. for webhooks on nonstandard ports
+		// +optional
+		AdditionalControlPlanePorts []int `json:"additionalControlPlanePorts,omitempty"`
 	}
 	// NodeGroupIAM holds all IAM attributes of a NodeGroup
 	NodeGroupIAM struct {
@@ -1107,6 +1372,10 @@ type (
 		InstanceRolePermissionsBoundary string `json:"instanceRolePermissionsBoundary,omitempty"`
 		// +optional
 		WithAddonPolicies NodeGroupIAMAddonPolicies `json:"withAddonPolicies,omitempty"`
+		// AttachCNIPolicy sets whether the AmazonEKS_CNI_Policy is attached to the instance role, valid variants are
+		// `AttachCNIPolicy` constants. `never` is meant for clusters where the VPC CNI uses IRSA
+		// +optional
+		AttachCNIPolicy string `json:"attachCNIPolicy,omitempty"`
 	}
 	// NodeGroupIAMAddonPolicies holds all IAM addon policies
 	NodeGroupIAMAddonPolicies struct {
@@ -1149,17 +1418,28 @@ type (
 
 	// NodeGroupSSH holds all the ssh access configuration to a NodeGroup
 	NodeGroupSSH struct {
-		// +optional If Allow is true the SSH configuration provided is used, otherwise it is ignored. Only one of
-		// PublicKeyPath, PublicKey and PublicKeyName can be configured
+		// +optional If Allow is true the SSH configuration provided is used, otherwise it is ignored. Exactly one of
+		// PublicKeyPath, PublicKey, PublicKeyName and PublicKeyNames can be configured
 		Allow *bool `json:"allow"`
-		// +optional The path to the SSH public key to be added to the nodes SSH keychain. If Allow is true this value
-		// defaults to "~/.ssh/id_rsa.pub", otherwise the value is ignored.
+		// +optional The path to the SSH public key to be imported as a new EC2 key pair and added to the nodes SSH
+		// keychain. If Allow is true this value defaults to "~/.ssh/id_rsa.pub", otherwise the value is ignored.
 		PublicKeyPath *string `json:"publicKeyPath,omitempty"`
-		// +optional Public key to be added to the nodes SSH keychain. If Allow is false this value is ignored.
+		// +optional Public key to be imported as a new EC2 key pair and added to the nodes SSH keychain. If Allow is
+		// false this value is ignored.
 		PublicKey *string `json:"publicKey,omitempty"`
-		// +optional Public key name in EC2 to be added to the nodes SSH keychain. If Allow is false this value
-		// is ignored.
+		// +optional Name of an existing EC2 key pair to be added to the nodes SSH keychain. If Allow is false this
+		// value is ignored.
 		PublicKeyName *string `json:"publicKeyName,omitempty"`
+		// +optional Names of existing EC2 key pairs to be added to the nodes SSH keychain. The first key pair is set
+		// in the launch template, and the public keys of the others are added to the `authorized_keys` of the SSH
+		// user by the user data, which is only supported for Amazon Linux and Ubuntu nodegroups. If Allow is false
+		// this value is ignored.
+		PublicKeyNames []string `json:"publicKeyNames,omitempty"`
+		// AdditionalPublicKeys holds the public keys of the key pairs following the first one in PublicKeyNames
+		AdditionalPublicKeys []string `json:"-"`
+		// +optional User is the user to SSH onto the nodes as. It is informational only, and defaults to the
+		// default user of the AMI family.
+		User string `json:"user,omitempty"`
 		// +optional
 		SourceSecurityGroupIDs []string `json:"sourceSecurityGroupIds,omitempty"`
 		// Enables the ability to [SSH onto nodes using SSM](/introduction#ssh-access)
@@ -1170,8 +1450,19 @@ type (
 	// NodeGroupInstancesDistribution holds the configuration for [spot
 	// instances](/usage/spot-instances/)
 	NodeGroupInstancesDistribution struct {
-		// +required
+		// Either `instanceTypes` or `instanceTypeOverrides` must be set
+		// +optional
 		InstanceTypes []string `json:"instanceTypes,omitempty"`
+		// InstanceTypeOverrides can be set instead of `instanceTypes` to specify the weighted capacity of each
+		// instance type. With the `capacity-optimized-prioritized` spot allocation strategy, their order is the
+		// priority of the instance types
+		// +optional
+		InstanceTypeOverrides []InstanceTypeOverride `json:"instanceTypeOverrides,omitempty"`
+		// InstanceRequirements can be set instead of `instanceTypes` to select the instance types by
+		// their attributes, see [attribute-based instance type
+		// selection](https://docs.aws.amazon.com/autoscaling/ec2/userguide/create-asg-instance-type-requirements.html)
+		// +optional
+		InstanceRequirements *InstanceRequirements `json:"instanceRequirements,omitempty"`
 		// Defaults to `on demand price`
 		// +optional
 		MaxPrice *float64 `json:"maxPrice,omitempty"`
@@ -1182,10 +1473,18 @@ type (
 		// Defaults to `100`
 		// +optional
 		OnDemandPercentageAboveBaseCapacity *int `json:"onDemandPercentageAboveBaseCapacity,omitempty"`
+		// Valid variants are: `"lowest-price"` and `"prioritized"`. With `"prioritized"`, on-demand capacity is
+		// launched from the instance types in the order they are listed in.
+		// Defaults to the allocation strategy of Auto Scaling
+		// +optional
+		OnDemandAllocationStrategy *string `json:"onDemandAllocationStrategy,omitempty"`
 		// Range [1-20]
 		// Defaults to `2`
 		// +optional
 		SpotInstancePools *int `json:"spotInstancePools,omitempty"`
+		// Valid variants are: `"lowest-price"`, `"capacity-optimized"`, `"capacity-optimized-prioritized"` and
+		// `"price-capacity-optimized"`. With `"capacity-optimized-prioritized"`, the order of the instance types is
+		// their priority.
 		// +optional
 		SpotAllocationStrategy *string `json:"spotAllocationStrategy,omitempty"`
 		// Enable [capacity
@@ -1193,6 +1492,66 @@ type (
 		// for spot instances
 		// +optional
 		CapacityRebalance bool `json:"capacityRebalance"`
+		// CapacityReservation makes the on-demand instances of the nodegroup launch into Capacity Reservations,
+		// spot instances are not affected
+		// +optional
+		CapacityReservation *InstancesDistributionCapacityReservation `json:"capacityReservation,omitempty"`
+	}
+
+	// InstancesDistributionCapacityReservation holds the Capacity Reservations preferences of the on-demand instances
+	// of a mixed instances nodegroup
+	InstancesDistributionCapacityReservation struct {
+		// Valid variants are `"capacity-reservations-first"`, to launch on-demand instances into Capacity
+		// Reservations while they have available capacity, and `"capacity-reservations-only"`, to only launch
+		// instances into Capacity Reservations
+		// +required
+		CapacityReservationPreference string `json:"capacityReservationPreference"`
+		// CapacityReservationIDs are the Capacity Reservations to launch instances into, instead of any open
+		// Capacity Reservation with matching attributes
+		// +optional
+		CapacityReservationIDs []string `json:"capacityReservationIDs,omitempty"`
+		// CapacityReservationResourceGroupARNs are the Capacity Reservation groups to launch instances into,
+		// instead of any open Capacity Reservation with matching attributes
+		// +optional
+		CapacityReservationResourceGroupARNs []string `json:"capacityReservationResourceGroupARNs,omitempty"`
+	}
+
+	// InstanceTypeOverride is an instance type of a mixed instances nodegroup
+	InstanceTypeOverride struct {
+		// +required
+		InstanceType string `json:"instanceType"`
+		// WeightedCapacity is the number of capacity units an instance of this type counts for in the
+		// desired capacity of the nodegroup. Range [1-999]
+		// +optional
+		WeightedCapacity *int `json:"weightedCapacity,omitempty"`
+	}
+
+	// InstanceRequirements holds the attributes of the instance types of a mixed instances nodegroup
+	InstanceRequirements struct {
+		// VCPUCount is the range of the number of vCPUs
+		// +required
+		VCPUCount *InstanceRequirementsRange `json:"vCPUCount"`
+		// MemoryMiB is the range of the memory size in MiB
+		// +required
+		MemoryMiB *InstanceRequirementsRange `json:"memoryMiB"`
+		// CPUManufacturers restricts the instance types to those with CPUs
+		// from these manufacturers, valid variants are `intel`, `amd` and
+		// `amazon-web-services`. The CPU architecture must match that of the AMI
+		// +optional
+		CPUManufacturers []string `json:"cpuManufacturers,omitempty"`
+		// ExcludedInstanceTypes are instance types, which may contain `*`
+		// wildcards, to exclude
+		// +optional
+		ExcludedInstanceTypes []string `json:"excludedInstanceTypes,omitempty"`
+	}
+
+	// InstanceRequirementsRange is a range of an attribute of InstanceRequirements
+	InstanceRequirementsRange struct {
+		// +required
+		Min *int `json:"min"`
+		// Defaults to no maximum
+		// +optional
+		Max *int `json:"max,omitempty"`
 	}
 
 	// NodeGroupBottlerocket holds the configuration for Bottlerocket based
@@ -1218,6 +1577,28 @@ type (
 		// +optional
 		MaxUnavailablePercentage *int `json:"maxUnavailablePercentage,omitempty"`
 	}
+
+	// WarmPool holds the configuration of the warm pool of a nodegroup
+	WarmPool struct {
+		// MinSize is the minimum number of instances kept in the warm pool.
+		// Defaults to `0`
+		// +optional
+		MinSize *int `json:"minSize,omitempty"`
+		// MaxPreparedCapacity is the maximum number of instances that are
+		// either running in the nodegroup or kept in the warm pool.
+		// Defaults to the `maxSize` of the nodegroup
+		// +optional
+		MaxPreparedCapacity *int `json:"maxPreparedCapacity,omitempty"`
+		// PoolState is the state of the instances in the warm pool, one of
+		// `Stopped`, `Running` or `Hibernated`.
+		// Defaults to `Stopped`
+		// +optional
+		PoolState string `json:"poolState,omitempty"`
+		// ReuseOnScaleIn returns instances to the warm pool on scale in,
+		// instead of terminating them
+		// +optional
+		ReuseOnScaleIn *bool `json:"reuseOnScaleIn,omitempty"`
+	}
 )
 
 // MetricsCollection used by the scaling config,
@@ -1272,6 +1653,10 @@ type VolumeMapping struct {
 	VolumeThroughput *int `json:"volumeThroughput,omitempty"`
 	// +optional
 	SnapshotID *string `json:"snapshotID,omitempty"`
+	// Rate in MiB/s at which the volume is initialized from `snapshotID`.
+	// Valid range is `100`-`300`
+	// +optional
+	VolumeInitializationRate *int `json:"volumeInitializationRate,omitempty"`
 }
 
 // NodeGroupBase represents the base nodegroup config for self-managed and managed nodegroups
@@ -1288,14 +1673,27 @@ type NodeGroupBase struct {
 	// AZs](/usage/autoscaling/#zone-aware-auto-scaling)
 	// +optional
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+	// Limit nodes to the AZs with these IDs (e.g. `use1-az1`), which identify
+	// the same zones in all accounts, unlike the names in `availabilityZones`
+	// +optional
+	AvailabilityZoneIDs []string `json:"availabilityZoneIDs,omitempty"`
 	// Limit nodes to specific subnets
 	// +optional
 	Subnets []string `json:"subnets,omitempty"`
+	// ARN of the [AWS Outpost](https://docs.aws.amazon.com/eks/latest/userguide/eks-outposts.html)
+	// to launch the nodes on. Only the subnets that are on the Outpost are used for the nodegroup
+	// +optional
+	OutpostARN string `json:"outpostARN,omitempty"`
 
 	// +optional
 	InstancePrefix string `json:"instancePrefix,omitempty"`
 	// +optional
 	InstanceName string `json:"instanceName,omitempty"`
+	// Template for the Name tag of instances, rendered with Go's text/template.
+	// Available fields are `.ClusterName`, `.NodeGroupName` and `.Region`, and available functions are
+	// `lower`, `upper`, `replace` and `trunc`. Cannot be set together with `instanceName` or `instancePrefix`
+	// +optional
+	InstanceNameTemplate string `json:"instanceNameTemplate,omitempty"`
 
 	// +optional
 	*ScalingConfig
@@ -1314,6 +1712,12 @@ type NodeGroupBase struct {
 	// for nodegroup
 	// +optional
 	PrivateNetworking bool `json:"privateNetworking"`
+	// AssociatePublicIPAddress assigns a public IP address to the primary
+	// network interface of the nodes, for public subnets that do not
+	// auto-assign public IP addresses (unmanaged nodegroups only).
+	// Cannot be enabled along with `privateNetworking`
+	// +optional
+	AssociatePublicIPAddress *bool `json:"associatePublicIPAddress,omitempty"`
 	// Applied to the Autoscaling Group and to the EC2 instances (unmanaged),
 	// Applied to the EKS Nodegroup resource and to the EC2 instances (managed)
 	// +optional
@@ -1378,6 +1782,18 @@ type NodeGroupBase struct {
 	// +optional
 	DisablePodIMDS *bool `json:"disablePodIMDS,omitempty"`
 
+	// HTTPPutResponseHopLimit is the number of network hops allowed for the responses of the metadata service,
+	// between 1 and 64. Defaults to `2`, or `1` when `disablePodIMDS` is enabled
+	// +optional
+	HTTPPutResponseHopLimit *int `json:"httpPutResponseHopLimit,omitempty"`
+
+	// DisableLaunchTemplateMetadataOptions omits the metadata options from the launch template, leaving the EC2
+	// defaults, for legacy AMIs that do not support them. Cannot be used with `disableIMDSv1`, `disablePodIMDS` or
+	// `httpPutResponseHopLimit`
+	// Defaults to `false`
+	// +optional
+	DisableLaunchTemplateMetadataOptions *bool `json:"disableLaunchTemplateMetadataOptions,omitempty"`
+
 	// Placement specifies the placement group in which nodes should
 	// be spawned
 	// +optional
@@ -1402,11 +1818,86 @@ type NodeGroupBase struct {
 	// Enable EC2 detailed monitoring
 	// +optional
 	EnableDetailedMonitoring *bool `json:"enableDetailedMonitoring,omitempty"`
+
+	// DisableRollback stops CloudFormation from rolling back the nodegroup stack
+	// if it fails to create, so that the failed resources can be inspected
+	// Defaults to `false`
+	// +optional
+	DisableRollback *bool `json:"disableRollback,omitempty"`
+
+	// LaunchTemplateTagSpecifications adds tags to the resources created from the
+	// launch template, keyed by resource type (`instance`, `volume` or
+	// `network-interface`). They are merged with the tags set on all of these
+	// resources, taking precedence over them
+	// +optional
+	LaunchTemplateTagSpecifications map[string]map[string]string `json:"launchTemplateTagSpecifications,omitempty"`
+
+	// CapacityReservation configures the On-Demand Capacity Reservations that
+	// the instances of the nodegroup are launched into
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+
+	// ShutdownBehavior of the instances when they are shut down from the OS,
+	// valid variants are `ShutdownBehavior` constants. Only supported for
+	// unmanaged nodegroups
+	// +optional
+	ShutdownBehavior string `json:"shutdownBehavior,omitempty"`
+
+	// PrivateDNSNameOptions configures the hostname of the instances,
+	// defaults to `privateDNSNameOptions` of the cluster
+	// +optional
+	PrivateDNSNameOptions *PrivateDNSNameOptions `json:"privateDNSNameOptions,omitempty"`
+}
+
+// PrivateDNSNameOptions configures the private DNS hostname of instances
+type PrivateDNSNameOptions struct {
+	// HostnameType of the instances, valid variants are `HostnameType` constants
+	// +optional
+	HostnameType string `json:"hostnameType,omitempty"`
 }
 
 // Placement specifies placement group information
 type Placement struct {
+	// +optional
 	GroupName string `json:"groupName,omitempty"`
+
+	// Tenancy of the instances, valid variants are `PlacementTenancy` constants
+	// +optional
+	Tenancy string `json:"tenancy,omitempty"`
+
+	// HostResourceGroupARN is the ARN of the host resource group in which to
+	// launch the instances, it requires `host` tenancy
+	// +optional
+	HostResourceGroupARN string `json:"hostResourceGroupArn,omitempty"`
+
+	// PartitionNumber is the number of the partition the instances are
+	// launched into, it requires a placement group with the `partition` strategy
+	// +optional
+	PartitionNumber *int `json:"partitionNumber,omitempty"`
+}
+
+// CapacityReservation specifies either a preference for using Capacity
+// Reservations or the Capacity Reservation to target
+type CapacityReservation struct {
+	// CapacityReservationPreference is either `open`, to launch instances into
+	// any open Capacity Reservation with matching attributes, or `none`
+	// +optional
+	CapacityReservationPreference *string `json:"capacityReservationPreference,omitempty"`
+
+	// CapacityReservationTarget targets a Capacity Reservation or a Capacity
+	// Reservation group
+	// +optional
+	CapacityReservationTarget *CapacityReservationTarget `json:"capacityReservationTarget,omitempty"`
+}
+
+// CapacityReservationTarget specifies a Capacity Reservation or the ARN of
+// a Capacity Reservation group, but not both
+type CapacityReservationTarget struct {
+	// +optional
+	CapacityReservationID *string `json:"capacityReservationID,omitempty"`
+
+	// +optional
+	CapacityReservationResourceGroupARN *string `json:"capacityReservationResourceGroupARN,omitempty"`
 }
 
 // ListOptions returns metav1.ListOptions with label selector for the nodegroup
@@ -1435,14 +1926,17 @@ func (n *NodeGroupBase) GetAMIFamily() string {
 }
 
 type LaunchTemplate struct {
-	// Launch template ID
-	// +required
+	// Launch template ID, required for managed nodegroups, while unmanaged nodegroups
+	// can specify the launch template by name instead
 	ID string `json:"id,omitempty"`
+	// Launch template name, only supported for unmanaged nodegroups
+	// +optional
+	Name string `json:"name,omitempty"`
 	// Launch template version
-	// Defaults to the default launch template version
+	// Defaults to the default launch template version for managed nodegroups,
+	// and is required for unmanaged nodegroups
 	// TODO support $Default, $Latest
 	Version *string `json:"version,omitempty"`
-	// TODO support Name?
 }
 
 // NodeGroupTaint represents a Kubernetes taint
@@ -1537,7 +2031,20 @@ func (in *InlineDocument) DeepCopy() *InlineDocument {
 
 // HasMixedInstances checks if a nodegroup has mixed instances option declared
 func HasMixedInstances(ng *NodeGroup) bool {
-	return ng.InstancesDistribution != nil && len(ng.InstancesDistribution.InstanceTypes) > 0
+	return ng.InstancesDistribution != nil && (len(ng.InstancesDistribution.GetInstanceTypes()) > 0 || ng.InstancesDistribution.InstanceRequirements != nil)
+}
+
+// GetInstanceTypes returns the instance types of the distribution, from InstanceTypeOverrides if set. It returns no
+// instance types when InstanceRequirements is set, as they are only known to EC2 Auto Scaling
+func (d *NodeGroupInstancesDistribution) GetInstanceTypes() []string {
+	if len(d.InstanceTypeOverrides) == 0 {
+		return d.InstanceTypes
+	}
+	instanceTypes := make([]string, len(d.InstanceTypeOverrides))
+	for i, override := range d.InstanceTypeOverrides {
+		instanceTypes[i] = override.InstanceType
+	}
+	return instanceTypes
 }
 
 // IsAMI returns true if the argument is an AMI ID
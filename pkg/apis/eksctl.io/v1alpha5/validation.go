@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -33,6 +34,9 @@ const (
 	MinGP3Iops    = DefaultNodeVolumeGP3IOPS
 	MaxGP3Iops    = 16000
 	OneDay        = 86400
+
+	MinVolumeInitializationRate = 100
+	MaxVolumeInitializationRate = 300
 )
 
 var (
@@ -64,6 +68,17 @@ func setNonEmpty(field string) error {
 	return fmt.Errorf("%s must be set and non-empty", field)
 }
 
+// ValidateProviderConfig validates the global parameters for interactions with AWS APIs
+func ValidateProviderConfig(p *ProviderConfig) error {
+	if p.CloudFormationRoleARN != "" {
+		parsed, err := arn.Parse(p.CloudFormationRoleARN)
+		if err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+			return fmt.Errorf("invalid CloudFormation role ARN %q, it must be the ARN of an IAM role", p.CloudFormationRoleARN)
+		}
+	}
+	return nil
+}
+
 // ValidateClusterConfig checks compatible fields of a given ClusterConfig
 func ValidateClusterConfig(cfg *ClusterConfig) error {
 	if IsDisabled(cfg.IAM.WithOIDC) && len(cfg.IAM.ServiceAccounts) > 0 {
@@ -88,6 +103,10 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		return err
 	}
 
+	if err := validatePrivateDNSNameOptions(cfg.PrivateDNSNameOptions, "privateDNSNameOptions"); err != nil {
+		return err
+	}
+
 	// names must be unique across both managed and unmanaged nodegroups
 	ngNames := nameSet{}
 	validateNg := func(ng *NodeGroupBase, path string) error {
@@ -100,6 +119,12 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		if cfg.PrivateCluster.Enabled && !ng.PrivateNetworking {
 			return fmt.Errorf("%s.privateNetworking must be enabled for a fully-private cluster", path)
 		}
+		if ng.InstanceNameTemplate != "" && (ng.InstanceName != "" || ng.InstancePrefix != "") {
+			return fmt.Errorf("%[1]s.instanceNameTemplate cannot be set together with %[1]s.instanceName or %[1]s.instancePrefix", path)
+		}
+		if _, err := ng.InstanceNameTag(cfg.Metadata); err != nil {
+			return fmt.Errorf("invalid instance name for %s: %w", path, err)
+		}
 		return nil
 	}
 
@@ -107,6 +132,10 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		return err
 	}
 
+	if err := validateVersionGates(cfg); err != nil {
+		return err
+	}
+
 	for i, ng := range cfg.NodeGroups {
 		path := fmt.Sprintf("nodeGroups[%d]", i)
 		if err := validateNg(ng.NodeGroupBase, path); err != nil {
@@ -132,6 +161,22 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		return err
 	}
 
+	if err := validateAvailabilityZones(cfg.AvailabilityZoneIDs); err != nil {
+		return err
+	}
+
+	if err := validateZoneNamesAndIDs(cfg.AvailabilityZones, cfg.AvailabilityZoneIDs, ""); err != nil {
+		return err
+	}
+
+	if err := validateAvailabilityZoneCount(cfg); err != nil {
+		return err
+	}
+
+	if err := validateLocalZones(cfg); err != nil {
+		return err
+	}
+
 	if err := cfg.ValidateVPCConfig(); err != nil {
 		return err
 	}
@@ -147,6 +192,18 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 	return nil
 }
 
+func validatePrivateDNSNameOptions(options *PrivateDNSNameOptions, path string) error {
+	if options == nil {
+		return nil
+	}
+	switch options.HostnameType {
+	case "", HostnameTypeIPName, HostnameTypeResourceName:
+		return nil
+	default:
+		return fmt.Errorf("invalid value %q for %s.hostnameType; must be one of %s or %s", options.HostnameType, path, HostnameTypeIPName, HostnameTypeResourceName)
+	}
+}
+
 func validateKarpenterConfig(cfg *ClusterConfig) error {
 	if cfg.Karpenter == nil {
 		return nil
@@ -263,6 +320,93 @@ func (c *ClusterConfig) ValidateVPCConfig() error {
 		return errors.New("vpc.manageSharedNodeSecurityGroupRules must be enabled when using ekstcl-managed security groups")
 	}
 
+	if c.IsSharedNodeSecurityGroupDisabled() {
+		if err := c.validateSharedNodeSecurityGroupDisabled(); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateSubnetTags(c.VPC); err != nil {
+		return err
+	}
+
+	if IsDisabled(c.VPC.ManageControlPlaneSecurityGroupRules) {
+		if c.VPC.SecurityGroup == "" {
+			return errors.New("vpc.manageControlPlaneSecurityGroupRules can only be disabled when the control plane security group is not created by eksctl (vpc.securityGroup)")
+		}
+		logger.Warning("eksctl will not add rules to the control plane security group %q for unmanaged nodegroups; unless it allows egress to the nodes on ports 443 and 1025-65535 and ingress from the nodes on port 443, nodes will not be able to join the cluster",
+			c.VPC.SecurityGroup)
+	}
+
+	return nil
+}
+
+// Limits of EC2 tags, see https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/Using_Tags.html#tag-restrictions
+const (
+	maxTagsPerResource = 50
+	maxTagKeyLength    = 128
+	maxTagValueLength  = 256
+)
+
+// ValidateSubnetTags checks that the tags of the subnets are valid EC2 tags, and that the subnets of each topology do
+// not get more tags than EC2 allows along with the Name and ELB role tags that eksctl adds
+func ValidateSubnetTags(vpc *ClusterVPC) error {
+	// eksctl adds a Name tag and an ELB role tag to the subnets it creates
+	const maxSubnetTags = maxTagsPerResource - 2
+
+	if err := validateTags(vpc.SubnetTags, "vpc.subnetTags"); err != nil {
+		return err
+	}
+	if err := validateTags(vpc.PublicSubnetTags, "vpc.publicSubnetTags"); err != nil {
+		return err
+	}
+	if err := validateTags(vpc.PrivateSubnetTags, "vpc.privateSubnetTags"); err != nil {
+		return err
+	}
+
+	for _, topology := range SubnetTopologies() {
+		tags := vpc.TagsOfSubnets(topology)
+		if _, ok := tags["Name"]; ok {
+			return fmt.Errorf("the Name tag of the %s subnets is set by eksctl and cannot be set in vpc.subnetTags or vpc.%sSubnetTags",
+				strings.ToLower(string(topology)), strings.ToLower(string(topology)))
+		}
+		if count := len(tags); count > maxSubnetTags {
+			return fmt.Errorf("%s subnets cannot have more than %d tags along with the tags of vpc.subnetTags, got %d",
+				strings.ToLower(string(topology)), maxSubnetTags, count)
+		}
+	}
+	return nil
+}
+
+func validateTags(tags map[string]string, path string) error {
+	for key, value := range tags {
+		switch {
+		case key == "":
+			return fmt.Errorf("%s cannot contain an empty key", path)
+		case len(key) > maxTagKeyLength:
+			return fmt.Errorf("%s: key %q is longer than %d characters", path, key, maxTagKeyLength)
+		case strings.HasPrefix(strings.ToLower(key), "aws:"):
+			return fmt.Errorf("%s: key %q cannot start with the reserved prefix \"aws:\"", path, key)
+		case len(value) > maxTagValueLength:
+			return fmt.Errorf("%s: the value of key %q is longer than %d characters", path, key, maxTagValueLength)
+		}
+	}
+	return nil
+}
+
+// validateSharedNodeSecurityGroupDisabled ensures nothing relies on the shared node security group when it is not
+// created. Unmanaged nodegroups must explicitly opt out of it, as their local security group does not allow traffic
+// from the other nodes of the cluster
+func (c *ClusterConfig) validateSharedNodeSecurityGroupDisabled() error {
+	for i, ng := range c.NodeGroups {
+		if ng.SecurityGroups == nil || !IsDisabled(ng.SecurityGroups.WithShared) {
+			return fmt.Errorf("nodeGroups[%d].securityGroups.withShared must be disabled for nodegroup %q as vpc.sharedNodeSecurityGroup is %q; "+
+				"attach security groups that allow traffic between all the nodes of the cluster with securityGroups.attachIDs, or set a security group ID in vpc.sharedNodeSecurityGroup", i, ng.Name, SharedNodeSecurityGroupDisabled)
+		}
+	}
+	if c.PrivateCluster != nil && c.PrivateCluster.Enabled && !c.PrivateCluster.SkipEndpointCreation {
+		return fmt.Errorf("vpc.sharedNodeSecurityGroup cannot be %q for a fully-private cluster, as the VPC endpoints created for it use the shared node security group", SharedNodeSecurityGroupDisabled)
+	}
 	return nil
 }
 
@@ -410,10 +554,10 @@ func (c *ClusterConfig) validateKubernetesNetworkConfig() error {
 			return fmt.Errorf("oidc needs to be enabled if IPv6 is set")
 		}
 
-		if version, err := utils.CompareVersions(c.Metadata.Version, Version1_21); err != nil {
+		if version, err := utils.CompareVersions(c.Metadata.Version, minimumVersionForIPv6); err != nil {
 			return fmt.Errorf("failed to convert %s cluster version to semver: %w", c.Metadata.Version, err)
 		} else if err == nil && version == -1 {
-			return fmt.Errorf("cluster version must be >= %s", Version1_21)
+			return fmt.Errorf("cluster version must be >= %s", minimumVersionForIPv6)
 		}
 	default:
 		return fmt.Errorf("invalid value %q for ipFamily; allowed are %s and %s", c.KubernetesNetworkConfig.IPFamily, IPV4Family, IPV6Family)
@@ -462,6 +606,28 @@ func validateNodeGroupBase(np NodePool, path string) error {
 		return fmt.Errorf("%s.maxPodsPerNode cannot be negative", path)
 	}
 
+	if IsEnabled(ng.AssociatePublicIPAddress) && ng.PrivateNetworking {
+		return fmt.Errorf("%s.associatePublicIPAddress cannot be enabled when %s.privateNetworking is enabled", path, path)
+	}
+
+	if ng.HTTPPutResponseHopLimit != nil && (*ng.HTTPPutResponseHopLimit < 1 || *ng.HTTPPutResponseHopLimit > 64) {
+		return fmt.Errorf("%s.httpPutResponseHopLimit must be between 1 and 64", path)
+	}
+
+	if IsEnabled(ng.DisableLaunchTemplateMetadataOptions) {
+		if IsEnabled(ng.DisableIMDSv1) || IsEnabled(ng.DisablePodIMDS) || ng.HTTPPutResponseHopLimit != nil {
+			return fmt.Errorf("%[1]s.disableLaunchTemplateMetadataOptions cannot be used with %[1]s.disableIMDSv1, %[1]s.disablePodIMDS or %[1]s.httpPutResponseHopLimit, which are set in the metadata options", path)
+		}
+	}
+
+	if ng.IAM != nil {
+		switch ng.IAM.AttachCNIPolicy {
+		case "", AttachCNIPolicyAuto, AttachCNIPolicyAlways, AttachCNIPolicyNever:
+		default:
+			return fmt.Errorf("invalid value %q for %s.iam.attachCNIPolicy; must be one of %s, %s or %s", ng.IAM.AttachCNIPolicy, path, AttachCNIPolicyAuto, AttachCNIPolicyAlways, AttachCNIPolicyNever)
+		}
+	}
+
 	if IsEnabled(ng.DisablePodIMDS) && ng.IAM != nil {
 		fmtFieldConflictErr := func(_ string) error {
 			return fmt.Errorf("%s.disablePodIMDS and %s.iam.withAddonPolicies cannot be set at the same time", path, path)
@@ -475,14 +641,48 @@ func validateNodeGroupBase(np NodePool, path string) error {
 		return fmt.Errorf("only one of %[1]s.subnets or %[1]s.availabilityZones should be set", path)
 	}
 
+	if len(ng.AvailabilityZoneIDs) > 0 && len(ng.Subnets) > 0 {
+		return fmt.Errorf("only one of %[1]s.subnets or %[1]s.availabilityZoneIDs should be set", path)
+	}
+
+	if err := validateZoneNamesAndIDs(ng.AvailabilityZones, ng.AvailabilityZoneIDs, path+"."); err != nil {
+		return err
+	}
+
+	if ng.OutpostARN != "" {
+		if parsed, err := arn.Parse(ng.OutpostARN); err != nil || parsed.Service != "outposts" {
+			return fmt.Errorf("%s.outpostARN %q is not a valid Outpost ARN", path, ng.OutpostARN)
+		}
+	}
+
 	if ng.Placement != nil {
-		if ng.Placement.GroupName == "" {
-			return fmt.Errorf("%s.placement.groupName must be set and non-empty", path)
+		if err := validatePlacement(ng.Placement, path); err != nil {
+			return err
+		}
+	}
+
+	if err := validateLaunchTemplateTagSpecifications(ng, path); err != nil {
+		return err
+	}
+
+	if ng.CapacityReservation != nil {
+		if err := validateCapacityReservation(ng.CapacityReservation, path); err != nil {
+			return err
 		}
 	}
 
+	switch ng.ShutdownBehavior {
+	case "", ShutdownBehaviorStop, ShutdownBehaviorTerminate:
+	default:
+		return fmt.Errorf("invalid value %q for %s.shutdownBehavior; must be one of %s or %s", ng.ShutdownBehavior, path, ShutdownBehaviorStop, ShutdownBehaviorTerminate)
+	}
+
+	if err := validatePrivateDNSNameOptions(ng.PrivateDNSNameOptions, path+".privateDNSNameOptions"); err != nil {
+		return err
+	}
+
 	if IsEnabled(ng.EFAEnabled) {
-		if len(ng.AvailabilityZones) > 1 || len(ng.Subnets) > 1 {
+		if len(ng.AvailabilityZones) > 1 || len(ng.AvailabilityZoneIDs) > 1 || len(ng.Subnets) > 1 {
 			return fmt.Errorf("%s.efaEnabled nodegroups must have only one subnet or one availability zone", path)
 		}
 	}
@@ -500,9 +700,10 @@ func validateNodeGroupBase(np NodePool, path string) error {
 		}
 	}
 
-	// Only AmazonLinux2 and Bottlerocket support NVIDIA GPUs
+	// Only AmazonLinux2, AmazonLinux2023 and Bottlerocket support NVIDIA GPUs
 	if instanceutils.IsNvidiaInstanceType(SelectInstanceType(np)) &&
-		(ng.AMIFamily != NodeImageFamilyAmazonLinux2 && ng.AMIFamily != NodeImageFamilyBottlerocket && ng.AMIFamily != "") {
+		(ng.AMIFamily != NodeImageFamilyAmazonLinux2 && ng.AMIFamily != NodeImageFamilyAmazonLinux2023 &&
+			ng.AMIFamily != NodeImageFamilyBottlerocket && ng.AMIFamily != "") {
 		return errors.Errorf("NVIDIA GPU instance types are not supported for %s", ng.AMIFamily)
 	}
 
@@ -514,29 +715,220 @@ func validateNodeGroupBase(np NodePool, path string) error {
 	return nil
 }
 
+// validateNodeGroupLaunchTemplate validates the existing launch template of an unmanaged nodegroup, and that the
+// nodegroup does not set the fields of the launch template that eksctl would otherwise generate, as they would be ignored
+func validateNodeGroupLaunchTemplate(ng *NodeGroup, path string) error {
+	lt := ng.LaunchTemplate
+	switch {
+	case lt.ID == "" && lt.Name == "":
+		return fmt.Errorf("%[1]s.launchTemplate.id or %[1]s.launchTemplate.name is required if %[1]s.launchTemplate is set", path)
+	case lt.ID != "" && lt.Name != "":
+		return fmt.Errorf("only one of %[1]s.launchTemplate.id and %[1]s.launchTemplate.name can be set", path)
+	case lt.Version == nil:
+		// CloudFormation only resolves the default or latest version of the launch templates it creates
+		return fmt.Errorf("%s.launchTemplate.version is required for unmanaged nodegroups", path)
+	}
+	if versionNumber, err := strconv.ParseInt(*lt.Version, 10, 64); err != nil || versionNumber < 1 {
+		return fmt.Errorf("%s.launchTemplate.version must be a version number >= 1, got %q", path, *lt.Version)
+	}
+
+	var setFields []string
+	for field, isSet := range map[string]bool{
+		"ami":                             ng.AMI != "",
+		"amiReleaseVersion":               ng.AMIReleaseVersion != "",
+		"amiResolutionMode":               ng.AMIResolutionMode != "",
+		"instanceType":                    ng.InstanceType != "",
+		"instancesDistribution":           ng.InstancesDistribution != nil,
+		"spot":                            ng.Spot,
+		"shutdownBehavior":                ng.ShutdownBehavior != "",
+		"privateDNSNameOptions":           ng.PrivateDNSNameOptions != nil,
+		"instanceSelector":                ng.InstanceSelector != nil && !ng.InstanceSelector.IsZero(),
+		"cpuCredits":                      ng.CPUCredits != nil,
+		"preBootstrapCommands":            len(ng.PreBootstrapCommands) > 0,
+		"overrideBootstrapCommand":        ng.OverrideBootstrapCommand != nil,
+		"kubeletExtraConfig":              ng.KubeletExtraConfig != nil,
+		"maxPodsPerNode":                  ng.MaxPodsPerNode != 0,
+		"volumeSize":                      ng.VolumeSize != nil,
+		"additionalVolumes":               len(ng.AdditionalVolumes) > 0,
+		"ssh.allow":                       ng.SSH != nil && IsEnabled(ng.SSH.Allow),
+		"securityGroups.attachIDs":        ng.SecurityGroups != nil && len(ng.SecurityGroups.AttachIDs) > 0,
+		"launchTemplateTagSpecifications": len(ng.LaunchTemplateTagSpecifications) > 0,
+		"placement":                       ng.Placement != nil,
+		"capacityReservation":             ng.CapacityReservation != nil,
+		"associatePublicIPAddress":        ng.AssociatePublicIPAddress != nil,
+		"efaEnabled":                      IsEnabled(ng.EFAEnabled),
+		"disableIMDSv1":                   IsEnabled(ng.DisableIMDSv1),
+		"disablePodIMDS":                  IsEnabled(ng.DisablePodIMDS),
+	} {
+		if isSet {
+			setFields = append(setFields, path+"."+field)
+		}
+	}
+	if len(setFields) > 0 {
+		sort.Strings(setFields)
+		return fmt.Errorf("%s cannot be set when %s.launchTemplate is set, as they are set by the launch template", strings.Join(setFields, ", "), path)
+	}
+	// eksctl authorizes the role of the instance profile to join the cluster instead of creating one
+	if ng.IAM == nil || ng.IAM.InstanceProfileARN == "" {
+		return fmt.Errorf("%[1]s.iam.instanceProfileARN must be set to the instance profile of the launch template when %[1]s.launchTemplate is set", path)
+	}
+	return nil
+}
+
+func validateLaunchTemplateTagSpecifications(ng *NodeGroupBase, path string) error {
+	for resourceType, tags := range ng.LaunchTemplateTagSpecifications {
+		switch resourceType {
+		case LaunchTemplateResourceTypeInstance, LaunchTemplateResourceTypeVolume, LaunchTemplateResourceTypeNetworkInterface:
+		default:
+			return fmt.Errorf("invalid resource type %q in %s.launchTemplateTagSpecifications; must be one of %s, %s or %s", resourceType, path,
+				LaunchTemplateResourceTypeInstance, LaunchTemplateResourceTypeVolume, LaunchTemplateResourceTypeNetworkInterface)
+		}
+		for key := range tags {
+			if strings.HasPrefix(key, "kubernetes.io/cluster/") {
+				return fmt.Errorf("%s.launchTemplateTagSpecifications.%s cannot set tag %q as it is set by eksctl", path, resourceType, key)
+			}
+		}
+	}
+	return nil
+}
+
+func validatePlacement(placement *Placement, path string) error {
+	if placement.GroupName == "" && placement.Tenancy == "" {
+		return fmt.Errorf("%s.placement.groupName must be set and non-empty", path)
+	}
+
+	switch placement.Tenancy {
+	case "", PlacementTenancyDefault, PlacementTenancyDedicated, PlacementTenancyHost:
+	default:
+		return fmt.Errorf("invalid value %q for %s.placement.tenancy; must be one of %s, %s or %s",
+			placement.Tenancy, path, PlacementTenancyDefault, PlacementTenancyDedicated, PlacementTenancyHost)
+	}
+
+	if placement.HostResourceGroupARN != "" {
+		if placement.Tenancy != PlacementTenancyHost {
+			return fmt.Errorf("%[1]s.placement.hostResourceGroupArn requires %[1]s.placement.tenancy to be %[2]s", path, PlacementTenancyHost)
+		}
+		if _, err := arn.Parse(placement.HostResourceGroupARN); err != nil {
+			return fmt.Errorf("%s.placement.hostResourceGroupArn %q is not a valid ARN", path, placement.HostResourceGroupARN)
+		}
+	}
+
+	if placement.PartitionNumber != nil {
+		if placement.GroupName == "" {
+			return fmt.Errorf("%[1]s.placement.partitionNumber requires %[1]s.placement.groupName to be set to a placement group with the partition strategy", path)
+		}
+		if *placement.PartitionNumber < 1 {
+			return fmt.Errorf("%s.placement.partitionNumber must be at least 1", path)
+		}
+	}
+	return nil
+}
+
+func validateCapacityReservation(cr *CapacityReservation, path string) error {
+	if cr.CapacityReservationPreference != nil && cr.CapacityReservationTarget != nil {
+		return fmt.Errorf("only one of %[1]s.capacityReservation.capacityReservationPreference or %[1]s.capacityReservation.capacityReservationTarget should be set", path)
+	}
+
+	if cr.CapacityReservationPreference != nil {
+		switch *cr.CapacityReservationPreference {
+		case CapacityReservationPreferenceOpen, CapacityReservationPreferenceNone:
+		default:
+			return fmt.Errorf("invalid value %q for %s.capacityReservation.capacityReservationPreference; must be one of %s or %s",
+				*cr.CapacityReservationPreference, path, CapacityReservationPreferenceOpen, CapacityReservationPreferenceNone)
+		}
+	}
+
+	if target := cr.CapacityReservationTarget; target != nil {
+		hasID, hasGroupARN := IsSetAndNonEmptyString(target.CapacityReservationID), IsSetAndNonEmptyString(target.CapacityReservationResourceGroupARN)
+		if hasID == hasGroupARN {
+			return fmt.Errorf("exactly one of %[1]s.capacityReservation.capacityReservationTarget.capacityReservationID or %[1]s.capacityReservation.capacityReservationTarget.capacityReservationResourceGroupARN must be set", path)
+		}
+		if hasGroupARN {
+			if _, err := arn.Parse(*target.CapacityReservationResourceGroupARN); err != nil {
+				return fmt.Errorf("%s.capacityReservation.capacityReservationTarget.capacityReservationResourceGroupARN %q is not a valid ARN", path, *target.CapacityReservationResourceGroupARN)
+			}
+		}
+	}
+	return nil
+}
+
+// isSpotOnly returns true if the instances distribution launches no On-Demand instances
+func isSpotOnly(distribution *NodeGroupInstancesDistribution) bool {
+	return distribution != nil &&
+		distribution.OnDemandPercentageAboveBaseCapacity != nil && *distribution.OnDemandPercentageAboveBaseCapacity == 0 &&
+		(distribution.OnDemandBaseCapacity == nil || *distribution.OnDemandBaseCapacity == 0)
+}
+
+// defaultRootVolumeName is the device name of the root volume when volumeName is not set
+const defaultRootVolumeName = "/dev/xvda"
+
 func validateVolumeOpts(ng *NodeGroupBase, path string) error {
-	if ng.VolumeType != nil {
-		if ng.VolumeIOPS != nil && !(*ng.VolumeType == NodeVolumeTypeIO1 || *ng.VolumeType == NodeVolumeTypeGP3) {
+	if err := validateVolumeMapping(&VolumeMapping{
+		VolumeType:       ng.VolumeType,
+		VolumeIOPS:       ng.VolumeIOPS,
+		VolumeThroughput: ng.VolumeThroughput,
+	}, path); err != nil {
+		return err
+	}
+
+	rootVolumeName := defaultRootVolumeName
+	if IsSetAndNonEmptyString(ng.VolumeName) {
+		rootVolumeName = *ng.VolumeName
+	}
+	volumeNames := map[string]string{rootVolumeName: "the root volume"}
+	if ng.AdditionalEncryptedVolume != "" {
+		volumeNames[ng.AdditionalEncryptedVolume] = "the OS volume"
+	}
+
+	for i, volume := range ng.AdditionalVolumes {
+		volumePath := fmt.Sprintf("%s.additionalVolumes[%d]", path, i)
+		if err := validateVolumeMapping(volume, volumePath); err != nil {
+			return err
+		}
+		if IsSetAndNonEmptyString(volume.VolumeName) {
+			if usedBy, ok := volumeNames[*volume.VolumeName]; ok {
+				return fmt.Errorf("%s.volumeName %q is already used by %s", volumePath, *volume.VolumeName, usedBy)
+			}
+			volumeNames[*volume.VolumeName] = volumePath
+		}
+		if volume.VolumeInitializationRate == nil {
+			continue
+		}
+		if !IsSetAndNonEmptyString(volume.SnapshotID) {
+			return fmt.Errorf("%s.volumeInitializationRate can only be set when %s.snapshotID is set", volumePath, volumePath)
+		}
+		if rate := *volume.VolumeInitializationRate; !(rate >= MinVolumeInitializationRate && rate <= MaxVolumeInitializationRate) {
+			return fmt.Errorf("value for %s.volumeInitializationRate must be within range %d-%d", volumePath, MinVolumeInitializationRate, MaxVolumeInitializationRate)
+		}
+	}
+
+	return nil
+}
+
+// validateVolumeMapping checks the IOPS and throughput of a volume against its type, which defaults to gp3
+func validateVolumeMapping(vm *VolumeMapping, path string) error {
+	if vm.VolumeType != nil {
+		if vm.VolumeIOPS != nil && !(*vm.VolumeType == NodeVolumeTypeIO1 || *vm.VolumeType == NodeVolumeTypeGP3) {
 			return fmt.Errorf("%s.volumeIOPS is only supported for %s and %s volume types", path, NodeVolumeTypeIO1, NodeVolumeTypeGP3)
 		}
 
-		if *ng.VolumeType == NodeVolumeTypeIO1 {
-			if ng.VolumeIOPS != nil && !(*ng.VolumeIOPS >= MinIO1Iops && *ng.VolumeIOPS <= MaxIO1Iops) {
+		if *vm.VolumeType == NodeVolumeTypeIO1 {
+			if vm.VolumeIOPS != nil && !(*vm.VolumeIOPS >= MinIO1Iops && *vm.VolumeIOPS <= MaxIO1Iops) {
 				return fmt.Errorf("value for %s.volumeIOPS must be within range %d-%d", path, MinIO1Iops, MaxIO1Iops)
 			}
 		}
 
-		if ng.VolumeThroughput != nil && *ng.VolumeType != NodeVolumeTypeGP3 {
+		if vm.VolumeThroughput != nil && *vm.VolumeType != NodeVolumeTypeGP3 {
 			return fmt.Errorf("%s.volumeThroughput is only supported for %s volume type", path, NodeVolumeTypeGP3)
 		}
 	}
 
-	if ng.VolumeType == nil || *ng.VolumeType == NodeVolumeTypeGP3 {
-		if ng.VolumeIOPS != nil && !(*ng.VolumeIOPS >= MinGP3Iops && *ng.VolumeIOPS <= MaxGP3Iops) {
+	if vm.VolumeType == nil || *vm.VolumeType == NodeVolumeTypeGP3 {
+		if vm.VolumeIOPS != nil && !(*vm.VolumeIOPS >= MinGP3Iops && *vm.VolumeIOPS <= MaxGP3Iops) {
 			return fmt.Errorf("value for %s.volumeIOPS must be within range %d-%d", path, MinGP3Iops, MaxGP3Iops)
 		}
 
-		if ng.VolumeThroughput != nil && !(*ng.VolumeThroughput >= MinThroughput && *ng.VolumeThroughput <= MaxThroughput) {
+		if vm.VolumeThroughput != nil && !(*vm.VolumeThroughput >= MinThroughput && *vm.VolumeThroughput <= MaxThroughput) {
 			return fmt.Errorf("value for %s.volumeThroughput must be within range %d-%d", path, MinThroughput, MaxThroughput)
 		}
 	}
@@ -610,6 +1002,12 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	if ng.LaunchTemplate != nil {
+		if err := validateNodeGroupLaunchTemplate(ng, path); err != nil {
+			return err
+		}
+	}
+
 	if ng.IAM != nil {
 		if err := validateNodeGroupIAM(ng.IAM, ng.IAM.InstanceProfileARN, "instanceProfileARN", path); err != nil {
 			return err
@@ -630,7 +1028,8 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		}
 	}
 
-	if ng.AMI != "" && ng.OverrideBootstrapCommand == nil {
+	// custom AL2023 AMIs are bootstrapped by nodeadm from the NodeConfig in the user data
+	if ng.AMI != "" && ng.OverrideBootstrapCommand == nil && ng.AMIFamily != NodeImageFamilyAmazonLinux2023 {
 		return errors.Errorf("%s.overrideBootstrapCommand is required when using a custom AMI (%s.ami)", path, path)
 	}
 
@@ -638,14 +1037,36 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	warnRestrictedNodeLabels(ng.Labels, ng.Taints, path)
+
 	if err := validateNodeGroupLabels(ng.Labels); err != nil {
 		return err
 	}
 
+	for _, key := range ng.DisableDefaultInstanceTags {
+		if key == "" {
+			return fmt.Errorf("%s.disableDefaultInstanceTags cannot contain an empty key", path)
+		}
+		if strings.HasPrefix(key, "kubernetes.io/cluster/") {
+			return fmt.Errorf("%s.disableDefaultInstanceTags: tag %q cannot be disabled, as the cloud provider requires it on the instances of the cluster", path, key)
+		}
+	}
+
+	if IsEnabled(ng.PropagateASGTags) {
+		for key := range ng.Tags {
+			if strings.HasPrefix(key, ClusterAutoscalerTagPrefix) {
+				return fmt.Errorf("%s.tags: tag %q cannot be set when %s.propagateASGTags is enabled, as tags with the prefix %q are generated from labels and taints", path, key, path, ClusterAutoscalerTagPrefix)
+			}
+		}
+	}
+
 	if ng.SSH != nil {
 		if err := validateNodeGroupSSH(ng.SSH); err != nil {
 			return err
 		}
+		if err := validateSSHPublicKeyNames(ng.NodeGroupBase, path); err != nil {
+			return err
+		}
 	}
 
 	if ng.Bottlerocket != nil && ng.AMIFamily != NodeImageFamilyBottlerocket {
@@ -678,6 +1099,23 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	if ng.AMIFamily == NodeImageFamilyAmazonLinux2023 {
+		// AL2023 nodes are bootstrapped by nodeadm from a NodeConfig rather than by bootstrap.sh
+		fieldNotSupported := func(field string) error {
+			return &unsupportedFieldError{
+				ng:    ng.NodeGroupBase,
+				path:  path,
+				field: field,
+			}
+		}
+		if ng.OverrideBootstrapCommand != nil {
+			return fieldNotSupported("overrideBootstrapCommand")
+		}
+		if IsEnabled(ng.EFAEnabled) {
+			return fieldNotSupported("efaEnabled")
+		}
+	}
+
 	if ng.AMIFamily == NodeImageFamilyBottlerocket && ng.Bottlerocket != nil {
 		err := checkBottlerocketSettings(ng.Bottlerocket.Settings, path)
 		if err != nil {
@@ -689,6 +1127,19 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		return err
 	}
 
+	if ng.Spot && (ng.InstancesDistribution != nil || (ng.InstanceSelector != nil && !ng.InstanceSelector.IsZero())) {
+		return fmt.Errorf("%[1]s.spot cannot be used with %[1]s.instancesDistribution or %[1]s.instanceSelector; set %[1]s.instancesDistribution.onDemandPercentageAboveBaseCapacity to 0 to only launch Spot instances", path)
+	}
+
+	if ng.CapacityReservation != nil && ng.CapacityReservation.CapacityReservationTarget != nil {
+		if isSpotOnly(ng.InstancesDistribution) {
+			return fmt.Errorf("%[1]s.capacityReservation.capacityReservationTarget cannot be used when %[1]s.instancesDistribution only launches Spot instances", path)
+		}
+		if ng.Spot {
+			return fmt.Errorf("%[1]s.capacityReservation.capacityReservationTarget cannot be used with %[1]s.spot", path)
+		}
+	}
+
 	if err := validateCPUCredits(ng); err != nil {
 		return err
 	}
@@ -698,9 +1149,12 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 	}
 
 	if ng.ContainerRuntime != nil {
-		if *ng.ContainerRuntime == ContainerRuntimeContainerD && ng.AMIFamily != NodeImageFamilyAmazonLinux2 {
+		if *ng.ContainerRuntime == ContainerRuntimeContainerD && ng.AMIFamily != NodeImageFamilyAmazonLinux2 && ng.AMIFamily != NodeImageFamilyAmazonLinux2023 {
 			// check if it's dockerd or containerd
-			return fmt.Errorf("%s as runtime is only support for AL2 ami family", ContainerRuntimeContainerD)
+			return fmt.Errorf("%s as runtime is only support for AL2 and AL2023 ami families", ContainerRuntimeContainerD)
+		}
+		if *ng.ContainerRuntime == ContainerRuntimeDockerD && ng.AMIFamily == NodeImageFamilyAmazonLinux2023 {
+			return fmt.Errorf("%s as runtime is not supported for the %s ami family, only %s is", ContainerRuntimeDockerD, NodeImageFamilyAmazonLinux2023, ContainerRuntimeContainerD)
 		}
 		if *ng.ContainerRuntime != ContainerRuntimeDockerD && *ng.ContainerRuntime != ContainerRuntimeContainerD {
 			return fmt.Errorf("only %s and %s are supported for container runtime", ContainerRuntimeContainerD, ContainerRuntimeDockerD)
@@ -713,6 +1167,117 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		}
 	}
 
+	if ng.WarmPool != nil {
+		if err := validateWarmPool(ng, path); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAMIResolutionMode(ng, path); err != nil {
+		return err
+	}
+
+	if err := validateAMIReleaseVersion(ng, path); err != nil {
+		return err
+	}
+
+	if ng.SecurityGroups != nil {
+		if err := validateControlPlaneRules(ng.SecurityGroups, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateControlPlaneRules(sgs *NodeGroupSGs, path string) error {
+	if !IsEnabled(sgs.RestrictControlPlaneRules) {
+		if len(sgs.AdditionalControlPlanePorts) > 0 {
+			return fmt.Errorf("%[1]s.securityGroups.additionalControlPlanePorts can only be set when %[1]s.securityGroups.restrictControlPlaneRules is enabled", path)
+		}
+		return nil
+	}
+	if IsDisabled(sgs.WithLocal) {
+		return fmt.Errorf("%[1]s.securityGroups.restrictControlPlaneRules requires the local security group of the nodegroup, which is disabled by %[1]s.securityGroups.withLocal", path)
+	}
+	seen := map[int]bool{}
+	for _, port := range sgs.AdditionalControlPlanePorts {
+		switch {
+		case port < 1 || port > 65535:
+			return fmt.Errorf("invalid port %d in %s.securityGroups.additionalControlPlanePorts; must be between 1 and 65535", port, path)
+		case port == 10250 || port == 443:
+			return fmt.Errorf("port %d in %s.securityGroups.additionalControlPlanePorts is always allowed", port, path)
+		case seen[port]:
+			return fmt.Errorf("duplicate port %d in %s.securityGroups.additionalControlPlanePorts", port, path)
+		}
+		seen[port] = true
+	}
+	return nil
+}
+
+func validateAMIReleaseVersion(ng *NodeGroup, path string) error {
+	if ng.AMIReleaseVersion == "" {
+		return nil
+	}
+	if ng.AMI != "" && ng.AMI != NodeImageResolverAutoSSM {
+		return fmt.Errorf("%[1]s.amiReleaseVersion cannot be set when %[1]s.ami is %[2]q; %[1]s.ami must be empty or %[3]q", path, ng.AMI, NodeImageResolverAutoSSM)
+	}
+	switch ng.AMIFamily {
+	case "", NodeImageFamilyAmazonLinux2, NodeImageFamilyAmazonLinux2023, NodeImageFamilyBottlerocket:
+		return nil
+	default:
+		return fmt.Errorf("%s.amiReleaseVersion is not supported for the %s AMI family; it can only be set for %s, %s and %s", path, ng.AMIFamily,
+			NodeImageFamilyAmazonLinux2, NodeImageFamilyAmazonLinux2023, NodeImageFamilyBottlerocket)
+	}
+}
+
+func validateAMIResolutionMode(ng *NodeGroup, path string) error {
+	switch ng.AMIResolutionMode {
+	case "", AMIResolutionModeEksctl:
+		return nil
+	case AMIResolutionModeCloudFormation:
+	default:
+		return fmt.Errorf("invalid value %q for %s.amiResolutionMode; must be one of %s or %s", ng.AMIResolutionMode, path,
+			AMIResolutionModeEksctl, AMIResolutionModeCloudFormation)
+	}
+
+	if ng.AMI != "" && ng.AMI != NodeImageResolverAutoSSM {
+		return fmt.Errorf("%[1]s.ami must be empty or %[2]q when %[1]s.amiResolutionMode is %[3]s", path, NodeImageResolverAutoSSM, AMIResolutionModeCloudFormation)
+	}
+	if ng.AMIFamily == NodeImageFamilyUbuntu2004 || ng.AMIFamily == NodeImageFamilyUbuntu1804 {
+		return fmt.Errorf("%s.amiResolutionMode %s is not supported for the %s AMI family, as its AMIs are not published in SSM", path, AMIResolutionModeCloudFormation, ng.AMIFamily)
+	}
+	return nil
+}
+
+func validateWarmPool(ng *NodeGroup, path string) error {
+	warmPool := ng.WarmPool
+	switch warmPool.PoolState {
+	case "", WarmPoolStateStopped, WarmPoolStateRunning, WarmPoolStateHibernated:
+	default:
+		return fmt.Errorf("invalid value %q for %s.warmPool.poolState; must be one of %s, %s or %s", warmPool.PoolState, path,
+			WarmPoolStateStopped, WarmPoolStateRunning, WarmPoolStateHibernated)
+	}
+
+	minSize := 0
+	if warmPool.MinSize != nil {
+		if *warmPool.MinSize < 0 {
+			return fmt.Errorf("%s.warmPool.minSize cannot be negative", path)
+		}
+		minSize = *warmPool.MinSize
+	}
+	if warmPool.MaxPreparedCapacity != nil && *warmPool.MaxPreparedCapacity < minSize {
+		return fmt.Errorf("%s.warmPool.maxPreparedCapacity (%d) cannot be less than %s.warmPool.minSize (%d)", path, *warmPool.MaxPreparedCapacity, path, minSize)
+	}
+
+	// warm pools are not supported by ASGs with a mixed instances policy
+	if ng.InstancesDistribution != nil || (ng.InstanceSelector != nil && !ng.InstanceSelector.IsZero()) {
+		return fmt.Errorf("%s.warmPool cannot be used with instancesDistribution or instanceSelector", path)
+	}
+	// nor by ASGs launching Spot instances
+	if ng.Spot {
+		return fmt.Errorf("%s.warmPool cannot be used with spot", path)
+	}
 	return nil
 }
 
@@ -725,8 +1290,6 @@ func validateNodeGroupLabels(labels map[string]string) error {
 	// - https://github.com/kubernetes/kubernetes/blob/v1.13.2/pkg/kubelet/apis/well_known_labels.go
 	// we cannot import those packages because they break other dependencies
 
-	unknownKubernetesLabels := []string{}
-
 	for label := range labels {
 		labelParts := strings.Split(label, "/")
 
@@ -740,19 +1303,51 @@ func validateNodeGroupLabels(labels map[string]string) error {
 		if errs := validation.IsValidLabelValue(labels[label]); len(errs) > 0 {
 			return fmt.Errorf("label %q has invalid value %q - %v", label, labels[label], errs)
 		}
+	}
+
+	if unknownKubernetesLabels := restrictedNodeLabels(labels); len(unknownKubernetesLabels) > 0 {
+		return fmt.Errorf("unknown 'kubernetes.io' or 'k8s.io' labels were specified: %v", unknownKubernetesLabels)
+	}
+	return nil
+}
+
+// restrictedNodeLabels returns the sorted keys of labels in the 'kubernetes.io' or 'k8s.io' namespaces that the
+// kubelet does not accept via --node-labels
+func restrictedNodeLabels(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for label := range labels {
+		keys = append(keys, label)
+	}
+	return restrictedKeys(keys)
+}
 
-		if len(labelParts) == 2 {
-			namespace := labelParts[0]
-			if isKubernetesLabel(namespace) && !kubeletapis.IsKubeletLabel(label) {
-				unknownKubernetesLabels = append(unknownKubernetesLabels, label)
+// restrictedKeys returns the sorted keys in the 'kubernetes.io' or 'k8s.io' namespaces that the kubelet restricts
+func restrictedKeys(keys []string) []string {
+	var restricted []string
+	for _, key := range keys {
+		if keyParts := strings.Split(key, "/"); len(keyParts) == 2 {
+			if isKubernetesLabel(keyParts[0]) && !kubeletapis.IsKubeletLabel(key) {
+				restricted = append(restricted, key)
 			}
 		}
 	}
+	sort.Strings(restricted)
+	return restricted
+}
 
-	if len(unknownKubernetesLabels) > 0 {
-		return fmt.Errorf("unknown 'kubernetes.io' or 'k8s.io' labels were specified: %v", unknownKubernetesLabels)
+// warnRestrictedNodeLabels warns about the labels and taints of nodegroups whose keys are restricted by the kubelet,
+// as nodes may come up without them
+func warnRestrictedNodeLabels(labels map[string]string, ngTaints []NodeGroupTaint, path string) {
+	for _, label := range restrictedNodeLabels(labels) {
+		logger.Warning("label %q of %s is in a 'kubernetes.io' or 'k8s.io' namespace restricted by the kubelet; nodes may come up without it", label, path)
+	}
+	taintKeys := make([]string, 0, len(ngTaints))
+	for _, t := range ngTaints {
+		taintKeys = append(taintKeys, t.Key)
+	}
+	for _, key := range restrictedKeys(taintKeys) {
+		logger.Warning("taint %q of %s is in a 'kubernetes.io' or 'k8s.io' namespace restricted by the kubelet; nodes may come up without it", key, path)
 	}
-	return nil
 }
 
 func isKubernetesLabel(namespace string) bool {
@@ -863,6 +1458,10 @@ func ValidateManagedNodeGroup(index int, ng *ManagedNodeGroup) error {
 		return err
 	}
 
+	if err := validateSSHPublicKeyNames(ng.NodeGroupBase, path); err != nil {
+		return err
+	}
+
 	if instanceutils.IsNvidiaInstanceType(SelectInstanceType(ng)) && ng.AMIFamily == NodeImageFamilyBottlerocket {
 		logger.Info("Bottlerocket GPU support is for unmanaged nodegroups only. If you're using CLI flags pass --managed=false")
 		return errors.Errorf("NVIDIA GPU instance types are not supported for managed nodegroups with AMIFamily %s", ng.AMIFamily)
@@ -927,6 +1526,27 @@ func ValidateManagedNodeGroup(index int, ng *ManagedNodeGroup) error {
 		return errors.Errorf("securityGroups.withLocal and securityGroups.withShared are not supported for managed nodegroups (%s.securityGroups)", path)
 	}
 
+	if IsEnabled(ng.SecurityGroups.RestrictControlPlaneRules) || len(ng.SecurityGroups.AdditionalControlPlanePorts) > 0 {
+		return errors.Errorf("securityGroups.restrictControlPlaneRules and securityGroups.additionalControlPlanePorts are not supported for managed nodegroups (%s.securityGroups)", path)
+	}
+
+	if ng.OutpostARN != "" {
+		return errors.Errorf("outpostARN is not supported for managed nodegroups (%s.outpostARN)", path)
+	}
+
+	if ng.AssociatePublicIPAddress != nil {
+		return errors.Errorf("associatePublicIPAddress is not supported for managed nodegroups (%s.associatePublicIPAddress)", path)
+	}
+
+	if ng.Spot && ng.CapacityReservation != nil && ng.CapacityReservation.CapacityReservationTarget != nil {
+		return errors.Errorf("capacityReservation.capacityReservationTarget cannot be used with Spot instances (%s.spot)", path)
+	}
+
+	// EKS does not allow the launch templates of managed nodegroups to set the shutdown behavior
+	if ng.ShutdownBehavior != "" {
+		return errors.Errorf("shutdownBehavior is not supported for managed nodegroups (%s.shutdownBehavior)", path)
+	}
+
 	if ng.InstanceType != "" {
 		if len(ng.InstanceTypes) > 0 {
 			return errors.Errorf("only one of instanceType or instanceTypes can be specified (%s)", path)
@@ -956,8 +1576,13 @@ func ValidateManagedNodeGroup(index int, ng *ManagedNodeGroup) error {
 		return err
 	}
 
+	warnRestrictedNodeLabels(ng.Labels, ng.Taints, path)
+
 	switch {
 	case ng.LaunchTemplate != nil:
+		if ng.LaunchTemplate.Name != "" {
+			return errors.Errorf("launchTemplate.name is not supported for managed nodegroups, use launchTemplate.id instead (%s.%s)", path, "launchTemplate.name")
+		}
 		if ng.LaunchTemplate.ID == "" {
 			return errors.Errorf("launchTemplate.id is required if launchTemplate is set (%s.%s)", path, "launchTemplate")
 		}
@@ -975,13 +1600,15 @@ func ValidateManagedNodeGroup(index int, ng *ManagedNodeGroup) error {
 
 		if ng.InstanceType != "" || ng.AMI != "" || IsEnabled(ng.SSH.Allow) || IsEnabled(ng.SSH.EnableSSM) || len(ng.SSH.SourceSecurityGroupIDs) > 0 ||
 			ng.VolumeSize != nil || len(ng.PreBootstrapCommands) > 0 || ng.OverrideBootstrapCommand != nil ||
-			len(ng.SecurityGroups.AttachIDs) > 0 || ng.InstanceName != "" || ng.InstancePrefix != "" || ng.MaxPodsPerNode != 0 ||
-			IsEnabled(ng.DisableIMDSv1) || IsEnabled(ng.DisablePodIMDS) || ng.Placement != nil {
+			len(ng.SecurityGroups.AttachIDs) > 0 || ng.InstanceName != "" || ng.InstancePrefix != "" || ng.InstanceNameTemplate != "" || ng.MaxPodsPerNode != 0 ||
+			IsEnabled(ng.DisableIMDSv1) || IsEnabled(ng.DisablePodIMDS) || ng.Placement != nil || len(ng.LaunchTemplateTagSpecifications) > 0 ||
+			ng.CapacityReservation != nil || IsEnabled(ng.DisableLaunchTemplateMetadataOptions) || ng.PrivateDNSNameOptions != nil {
 
 			incompatibleFields := []string{
 				"instanceType", "ami", "ssh.allow", "ssh.enableSSM", "ssh.sourceSecurityGroupIds", "securityGroups",
-				"volumeSize", "instanceName", "instancePrefix", "maxPodsPerNode", "disableIMDSv1",
-				"disablePodIMDS", "preBootstrapCommands", "overrideBootstrapCommand", "placement",
+				"volumeSize", "instanceName", "instancePrefix", "instanceNameTemplate", "maxPodsPerNode", "disableIMDSv1",
+				"disablePodIMDS", "preBootstrapCommands", "overrideBootstrapCommand", "placement", "launchTemplateTagSpecifications",
+				"capacityReservation", "disableLaunchTemplateMetadataOptions", "privateDNSNameOptions",
 			}
 			return errors.Errorf("cannot set %s in managedNodeGroup when a launch template is supplied", strings.Join(incompatibleFields, ", "))
 		}
@@ -1046,13 +1673,27 @@ func validateInstancesDistribution(ng *NodeGroup) error {
 	}
 
 	distribution := ng.InstancesDistribution
-	if len(distribution.InstanceTypes) == 0 && !hasInstanceSelector {
+	if err := validateInstanceTypeOverrides(distribution, hasInstanceSelector); err != nil {
+		return err
+	}
+
+	if distribution.InstanceRequirements != nil {
+		if len(distribution.InstanceTypes) > 0 || len(distribution.InstanceTypeOverrides) > 0 {
+			return errors.New("instanceRequirements cannot be set at the same time as instanceTypes or instanceTypeOverrides")
+		}
+		if hasInstanceSelector {
+			return errors.New("instanceRequirements cannot be set when using the instance selector feature")
+		}
+		if err := validateInstanceRequirements(distribution.InstanceRequirements); err != nil {
+			return err
+		}
+	} else if len(distribution.GetInstanceTypes()) == 0 && !hasInstanceSelector {
 		return fmt.Errorf("at least two instance types have to be specified for mixed nodegroups")
 	}
 
 	if !hasInstanceSelector {
 		uniqueInstanceTypes := make(map[string]struct{})
-		for _, instanceType := range distribution.InstanceTypes {
+		for _, instanceType := range distribution.GetInstanceTypes() {
 			uniqueInstanceTypes[instanceType] = struct{}{}
 		}
 
@@ -1069,24 +1710,136 @@ func validateInstancesDistribution(ng *NodeGroup) error {
 		return fmt.Errorf("percentageAboveBase should be between 0 and 100")
 	}
 
+	if strategy := distribution.OnDemandAllocationStrategy; strategy != nil && !isOnDemandAllocationStrategySupported(*strategy) {
+		return fmt.Errorf("onDemandAllocationStrategy should be one of: %v", strings.Join(supportedOnDemandAllocationStrategies(), ", "))
+	}
+
+	if err := validateInstancesDistributionCapacityReservation(ng); err != nil {
+		return err
+	}
+
 	if distribution.SpotInstancePools != nil && (*distribution.SpotInstancePools < 1 || *distribution.SpotInstancePools > 20) {
 		return fmt.Errorf("spotInstancePools should be between 1 and 20")
 	}
 
-	if distribution.SpotInstancePools != nil && distribution.SpotAllocationStrategy != nil && *distribution.SpotAllocationStrategy == SpotAllocationStrategyCapacityOptimized {
-		return fmt.Errorf("spotInstancePools cannot be specified when also specifying spotAllocationStrategy: %s", SpotAllocationStrategyCapacityOptimized)
+	if err := ValidateSpotAllocationStrategy(distribution.SpotAllocationStrategy); err != nil {
+		return err
+	}
+
+	// spot instance pools only apply to the lowest-price strategy
+	if distribution.SpotInstancePools != nil && distribution.SpotAllocationStrategy != nil && *distribution.SpotAllocationStrategy != SpotAllocationStrategyLowestPrice {
+		return fmt.Errorf("spotInstancePools cannot be specified when also specifying spotAllocationStrategy: %s", *distribution.SpotAllocationStrategy)
+	}
+
+	return nil
+}
+
+// validateInstancesDistributionCapacityReservation validates the Capacity Reservation preferences of the on-demand
+// instances of a mixed instances nodegroup, which only apply to on-demand instances
+func validateInstancesDistributionCapacityReservation(ng *NodeGroup) error {
+	distribution := ng.InstancesDistribution
+	cr := distribution.CapacityReservation
+	if cr == nil {
+		return nil
+	}
+
+	if !isCapacityReservationPreferenceSupported(cr.CapacityReservationPreference) {
+		return fmt.Errorf("capacityReservation.capacityReservationPreference should be one of: %v", strings.Join(supportedCapacityReservationPreferences(), ", "))
+	}
+	if len(cr.CapacityReservationIDs) > 0 && len(cr.CapacityReservationResourceGroupARNs) > 0 {
+		return errors.New("capacityReservation.capacityReservationIDs and capacityReservation.capacityReservationResourceGroupARNs cannot be set at the same time")
+	}
+	for _, groupARN := range cr.CapacityReservationResourceGroupARNs {
+		if _, err := arn.Parse(groupARN); err != nil {
+			return fmt.Errorf("capacityReservation.capacityReservationResourceGroupARNs contains %q, which is not a valid ARN", groupARN)
+		}
+	}
+	if ng.CapacityReservation != nil {
+		return errors.New("instancesDistribution.capacityReservation cannot be set at the same time as the capacityReservation of the nodegroup")
+	}
+	if isSpotOnly(distribution) {
+		return errors.New("capacityReservation cannot be set when the instances distribution only launches spot instances")
+	}
+
+	if percentage := distribution.OnDemandPercentageAboveBaseCapacity; percentage != nil && *percentage < 100 {
+		if cr.CapacityReservationPreference == CapacityReservationPreferenceReservationsOnly {
+			return fmt.Errorf("capacityReservation.capacityReservationPreference cannot be %q when the instances distribution launches spot instances, use %q or set onDemandPercentageAboveBaseCapacity to 100", CapacityReservationPreferenceReservationsOnly, CapacityReservationPreferenceReservationsFirst)
+		}
+		if distribution.OnDemandBaseCapacity == nil || *distribution.OnDemandBaseCapacity == 0 {
+			return errors.New("onDemandBaseCapacity must be set to the number of on-demand instances to launch into Capacity Reservations when the instances distribution also launches spot instances")
+		}
+	}
+	return nil
+}
+
+// ValidateSpotAllocationStrategy returns an error if strategy is set to a spot allocation strategy that is not
+// supported by ASG
+func ValidateSpotAllocationStrategy(strategy *string) error {
+	if strategy != nil && !isSpotAllocationStrategySupported(*strategy) {
+		return fmt.Errorf("spotAllocationStrategy should be one of: %v", strings.Join(supportedSpotAllocationStrategies(), ", "))
+	}
+	return nil
+}
+
+func validateInstanceRequirements(requirements *InstanceRequirements) error {
+	if err := validateInstanceRequirementsRange(requirements.VCPUCount, "vCPUCount"); err != nil {
+		return err
+	}
+	if err := validateInstanceRequirementsRange(requirements.MemoryMiB, "memoryMiB"); err != nil {
+		return err
+	}
+	for _, manufacturer := range requirements.CPUManufacturers {
+		switch manufacturer {
+		case "intel", "amd", "amazon-web-services":
+		default:
+			return fmt.Errorf("instanceRequirements.cpuManufacturers contains invalid value %q; must be one of intel, amd or amazon-web-services", manufacturer)
+		}
+	}
+	return nil
+}
+
+func validateInstanceRequirementsRange(r *InstanceRequirementsRange, field string) error {
+	path := "instanceRequirements." + field
+	if r == nil || r.Min == nil {
+		return fmt.Errorf("%s.min must be set", path)
 	}
+	if *r.Min < 0 {
+		return fmt.Errorf("%s.min cannot be negative", path)
+	}
+	if r.Max != nil && *r.Max < *r.Min {
+		return fmt.Errorf("%s.max (%d) cannot be less than %s.min (%d)", path, *r.Max, path, *r.Min)
+	}
+	return nil
+}
 
-	if distribution.SpotInstancePools != nil && distribution.SpotAllocationStrategy != nil && (*distribution.SpotAllocationStrategy == SpotAllocationStrategyCapacityOptimized || *distribution.SpotAllocationStrategy == SpotAllocationStrategyCapacityOptimizedPrioritized) {
-		return fmt.Errorf("spotInstancePools cannot be specified when also specifying spotAllocationStrategy: %s", SpotAllocationStrategyCapacityOptimizedPrioritized)
+func validateInstanceTypeOverrides(distribution *NodeGroupInstancesDistribution, hasInstanceSelector bool) error {
+	if len(distribution.InstanceTypeOverrides) == 0 {
+		return nil
+	}
+	if len(distribution.InstanceTypes) > 0 {
+		return errors.New("instanceTypes and instanceTypeOverrides cannot be set at the same time")
+	}
+	if hasInstanceSelector {
+		return errors.New("instanceTypeOverrides cannot be set when using the instance selector feature")
 	}
 
-	if distribution.SpotAllocationStrategy != nil {
-		if !isSpotAllocationStrategySupported(*distribution.SpotAllocationStrategy) {
-			return fmt.Errorf("spotAllocationStrategy should be one of: %v", strings.Join(supportedSpotAllocationStrategies(), ", "))
+	weighted := 0
+	for i, override := range distribution.InstanceTypeOverrides {
+		path := fmt.Sprintf("instanceTypeOverrides[%d]", i)
+		if override.InstanceType == "" {
+			return fmt.Errorf("%s.instanceType must be set", path)
+		}
+		if override.WeightedCapacity != nil {
+			if *override.WeightedCapacity < 1 || *override.WeightedCapacity > 999 {
+				return fmt.Errorf("%s.weightedCapacity should be between 1 and 999", path)
+			}
+			weighted++
 		}
 	}
 
+	if weighted > 0 && weighted != len(distribution.InstanceTypeOverrides) {
+		return errors.New("weightedCapacity must be set for all or none of instanceTypeOverrides")
+	}
 	return nil
 }
 
@@ -1099,11 +1852,11 @@ func validateCPUCredits(ng *NodeGroup) error {
 	}
 
 	if ng.InstancesDistribution != nil {
-		instanceTypes = ng.InstancesDistribution.InstanceTypes
+		instanceTypes = ng.InstancesDistribution.GetInstanceTypes()
 	}
 
 	for _, instanceType := range instanceTypes {
-		if strings.HasPrefix(instanceType, "t") {
+		if instanceutils.IsBurstableInstanceType(instanceType) {
 			isTInstance = true
 		}
 	}
@@ -1112,7 +1865,7 @@ func validateCPUCredits(ng *NodeGroup) error {
 		return fmt.Errorf("cpuCredits option set for nodegroup, but it has no t2/t3 instance types")
 	}
 
-	if strings.ToLower(*ng.CPUCredits) != "unlimited" && strings.ToLower(*ng.CPUCredits) != "standard" {
+	if strings.ToLower(*ng.CPUCredits) != CPUCreditsUnlimited && strings.ToLower(*ng.CPUCredits) != CPUCreditsStandard {
 		return fmt.Errorf("cpuCredits option accepts only one of 'standard' or 'unlimited'")
 	}
 
@@ -1141,6 +1894,18 @@ func validateASGSuspendProcesses(ng *NodeGroup) error {
 	return nil
 }
 
+// validateSSHPublicKeyNames rejects several SSH key pairs for AMI families whose user data cannot authorize the
+// additional public keys
+func validateSSHPublicKeyNames(ng *NodeGroupBase, path string) error {
+	if ng.SSH == nil || len(ng.SSH.PublicKeyNames) <= 1 {
+		return nil
+	}
+	if IsWindowsImage(ng.AMIFamily) || ng.AMIFamily == NodeImageFamilyBottlerocket {
+		return fmt.Errorf("only one SSH key pair can be specified for %s nodegroups, as additional public keys are added to the authorized_keys of Amazon Linux and Ubuntu nodes by their user data (path=%s.ssh.publicKeyNames)", ng.AMIFamily, path)
+	}
+	return nil
+}
+
 func validateNodeGroupSSH(SSH *NodeGroupSSH) error {
 	numSSHFlagsEnabled := countEnabledFields(
 		SSH.PublicKeyPath,
@@ -1150,6 +1915,9 @@ func validateNodeGroupSSH(SSH *NodeGroupSSH) error {
 	if numSSHFlagsEnabled > 1 {
 		return errors.New("only one of publicKeyName, publicKeyPath or publicKey can be specified for SSH per node-group")
 	}
+	if len(SSH.PublicKeyNames) > 0 && numSSHFlagsEnabled > 0 {
+		return errors.New("publicKeyNames cannot be specified along with publicKeyName, publicKeyPath or publicKey for SSH per node-group")
+	}
 	return nil
 }
 
@@ -1201,7 +1969,9 @@ func IsWindowsImage(imageFamily string) bool {
 	case NodeImageFamilyWindowsServer2019CoreContainer,
 		NodeImageFamilyWindowsServer2019FullContainer,
 		NodeImageFamilyWindowsServer2004CoreContainer,
-		NodeImageFamilyWindowsServer20H2CoreContainer:
+		NodeImageFamilyWindowsServer20H2CoreContainer,
+		NodeImageFamilyWindowsServer2022CoreContainer,
+		NodeImageFamilyWindowsServer2022FullContainer:
 		return true
 
 	default:
@@ -1316,6 +2086,65 @@ func validateAvailabilityZones(azList []string) error {
 	}
 }
 
+// zoneIDPattern matches zone IDs such as `use1-az1` or `usw2-lax1-az1`, as opposed to zone names such as `us-east-1a`
+var zoneIDPattern = regexp.MustCompile(`^[a-z]+[0-9]+-([a-z]+[0-9]+-)?az[0-9]+$`)
+
+func validateZoneNamesAndIDs(names, ids []string, pathPrefix string) error {
+	if len(names) > 0 && len(ids) > 0 {
+		return fmt.Errorf("only one of %[1]savailabilityZones or %[1]savailabilityZoneIDs should be set", pathPrefix)
+	}
+	for _, name := range names {
+		if zoneIDPattern.MatchString(name) {
+			return fmt.Errorf("%savailabilityZones must only contain zone names, but %q is a zone ID; zone IDs should be set in %savailabilityZoneIDs", pathPrefix, name, pathPrefix)
+		}
+	}
+	for _, id := range ids {
+		if !zoneIDPattern.MatchString(id) {
+			return fmt.Errorf("%savailabilityZoneIDs must only contain zone IDs (e.g. use1-az1), got %q", pathPrefix, id)
+		}
+	}
+	return nil
+}
+
+// maxAvailabilityZones is the maximum number of zones of a cluster, as the CIDR of a VPC created by eksctl is split
+// into at most 16 subnets, one public and one private subnet per zone
+const maxAvailabilityZones = 8
+
+func validateAvailabilityZoneCount(cfg *ClusterConfig) error {
+	if cfg.AvailabilityZoneCount == nil {
+		return nil
+	}
+	count := *cfg.AvailabilityZoneCount
+	switch {
+	case len(cfg.AvailabilityZones) > 0:
+		return errors.New("availabilityZoneCount cannot be set along with availabilityZones")
+	case len(cfg.AvailabilityZoneIDs) > 0:
+		return errors.New("availabilityZoneCount cannot be set along with availabilityZoneIDs")
+	case count < MinRequiredAvailabilityZones:
+		return fmt.Errorf("availabilityZoneCount must be at least %d, got %d", MinRequiredAvailabilityZones, count)
+	case count+len(cfg.LocalZones) > maxAvailabilityZones:
+		return fmt.Errorf("availabilityZoneCount and the number of localZones cannot add up to more than %d zones, got %d", maxAvailabilityZones, count+len(cfg.LocalZones))
+	}
+	return nil
+}
+
+func validateLocalZones(cfg *ClusterConfig) error {
+	if len(cfg.LocalZones) == 0 {
+		return nil
+	}
+	if cfg.IPv6Enabled() {
+		return errors.New("localZones is not supported for IPv6 clusters")
+	}
+	for _, zone := range cfg.LocalZones {
+		for _, az := range cfg.AvailabilityZones {
+			if zone == az {
+				return fmt.Errorf("zone %q cannot be listed in both availabilityZones and localZones", zone)
+			}
+		}
+	}
+	return nil
+}
+
 func ErrTooFewAvailabilityZones(azs []string) error {
 	return fmt.Errorf("only %d zone(s) specified %v, %d are required (can be non-unique)", len(azs), azs, MinRequiredAvailabilityZones)
 }
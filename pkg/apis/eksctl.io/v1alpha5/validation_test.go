@@ -1,9 +1,12 @@
 package v1alpha5_test
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kris-nova/logger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -14,6 +17,24 @@ import (
 )
 
 var _ = Describe("ClusterConfig validation", func() {
+	DescribeTable("ValidateProviderConfig", func(roleARN, expectedErr string) {
+		err := api.ValidateProviderConfig(&api.ProviderConfig{
+			CloudFormationRoleARN: roleARN,
+		})
+		if expectedErr == "" {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(expectedErr))
+		}
+	},
+		Entry("no CloudFormation role", "", ""),
+		Entry("a valid role ARN", "arn:aws:iam::123456789012:role/cfn-deployer", ""),
+		Entry("a role ARN with a path", "arn:aws-us-gov:iam::123456789012:role/deploy/cfn-deployer", ""),
+		Entry("not an ARN", "cfn-deployer", `invalid CloudFormation role ARN "cfn-deployer", it must be the ARN of an IAM role`),
+		Entry("the ARN of an IAM user", "arn:aws:iam::123456789012:user/deployer", `invalid CloudFormation role ARN "arn:aws:iam::123456789012:user/deployer", it must be the ARN of an IAM role`),
+		Entry("the ARN of another service", "arn:aws:s3:::role/bucket", `invalid CloudFormation role ARN "arn:aws:s3:::role/bucket", it must be the ARN of an IAM role`),
+	)
+
 	Describe("nodeGroups[*].name", func() {
 		var (
 			cfg *api.ClusterConfig
@@ -54,6 +75,37 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Describe("nodeGroups[*].instanceNameTemplate validation", func() {
+		var cfg *api.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Name = "cluster"
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "ng0"
+			ng0.InstanceNameTemplate = "{{.ClusterName}}-{{.NodeGroupName}}-{{.Region}}"
+		})
+
+		It("accepts a valid template", func() {
+			Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects a template that fails to render", func() {
+			cfg.NodeGroups[0].InstanceNameTemplate = "{{.Unknown}}"
+			Expect(api.ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("invalid instance name for nodeGroups[0]: rendering instanceNameTemplate")))
+		})
+
+		It("rejects a template that renders a name that is too long", func() {
+			cfg.NodeGroups[0].InstanceNameTemplate = fmt.Sprintf("%0256d", 0)
+			Expect(api.ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("exceeds the maximum of 255")))
+		})
+
+		It("rejects setting instanceNameTemplate together with instanceName", func() {
+			cfg.NodeGroups[0].InstanceName = "name"
+			Expect(api.ValidateClusterConfig(cfg)).To(MatchError("nodeGroups[0].instanceNameTemplate cannot be set together with nodeGroups[0].instanceName or nodeGroups[0].instancePrefix"))
+		})
+	})
+
 	Describe("nodeGroups[*].name validation", func() {
 		var (
 			cfg *api.ClusterConfig
@@ -103,6 +155,53 @@ var _ = Describe("ClusterConfig validation", func() {
 			err = api.ValidateNodeGroup(0, ng0)
 			Expect(err).To(HaveOccurred())
 		})
+		It("containerd is allowed for AL2023", func() {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.ContainerRuntime = aws.String(api.ContainerRuntimeContainerD)
+			ng0.AMIFamily = api.NodeImageFamilyAmazonLinux2023
+			Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+		})
+		It("dockerd is rejected for AL2023", func() {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.ContainerRuntime = aws.String(api.ContainerRuntimeDockerD)
+			ng0.AMIFamily = api.NodeImageFamilyAmazonLinux2023
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("dockerd as runtime is not supported for the AmazonLinux2023 ami family, only containerd is"))
+		})
+	})
+
+	Describe("nodeGroups[*] with the AmazonLinux2023 AMI family", func() {
+		var ng0 *api.NodeGroup
+
+		BeforeEach(func() {
+			cfg := api.NewClusterConfig()
+			ng0 = cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.AMIFamily = api.NodeImageFamilyAmazonLinux2023
+		})
+
+		It("does not require overrideBootstrapCommand for a custom AMI", func() {
+			ng0.AMI = "ami-1234"
+			Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+		})
+
+		It("rejects overrideBootstrapCommand", func() {
+			ng0.OverrideBootstrapCommand = aws.String("/usr/bin/nodeadm init")
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("overrideBootstrapCommand is not supported for AmazonLinux2023 nodegroups (path=nodeGroups[0].overrideBootstrapCommand)"))
+		})
+
+		It("rejects efaEnabled", func() {
+			ng0.EFAEnabled = api.Enabled()
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("efaEnabled is not supported for AmazonLinux2023 nodegroups (path=nodeGroups[0].efaEnabled)"))
+		})
+
+		It("accepts NVIDIA GPU instance types", func() {
+			ng0.InstanceType = "g4dn.xlarge"
+			Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+		})
 	})
 
 	Describe("nodeGroups[*].ami validation", func() {
@@ -123,6 +222,55 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Describe("EKS version gates", func() {
+		var cfg *api.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Version = api.Version1_20
+		})
+
+		It("should reject an AMI family not supported by the EKS version", func() {
+			ng := cfg.NewNodeGroup()
+			ng.Name = "windows-20h2"
+			ng.AMIFamily = api.NodeImageFamilyWindowsServer20H2CoreContainer
+			err := api.ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError("nodeGroups[0].amiFamily: Windows Server 20H2 Core requires EKS version 1.21 and above"))
+		})
+
+		It("should reject an AMI family not supported by the EKS version in managed nodegroups", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.Name = "windows-20h2"
+			ng.AMIFamily = api.NodeImageFamilyWindowsServer20H2CoreContainer
+			cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{ng}
+			err := api.ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(ContainSubstring("managedNodeGroups[0].amiFamily: Windows Server 20H2 Core requires EKS version 1.21 and above")))
+		})
+
+		It("should accept an AMI family supported by the EKS version", func() {
+			cfg.Metadata.Version = api.Version1_21
+			ng := cfg.NewNodeGroup()
+			ng.Name = "windows-20h2"
+			ng.AMIFamily = api.NodeImageFamilyWindowsServer20H2CoreContainer
+			Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("should accept AMI families without a version gate", func() {
+			ng := cfg.NewNodeGroup()
+			ng.Name = "al2"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("should skip the version gates when the EKS version is not set", func() {
+			cfg.Metadata.Version = ""
+			ng := cfg.NewNodeGroup()
+			ng.Name = "windows-20h2"
+			ng.AMIFamily = api.NodeImageFamilyWindowsServer20H2CoreContainer
+			Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
 	Describe("nodeGroups[*].maxInstanceLifetime validation", func() {
 		It("should reject if value is below a day", func() {
 			cfg := api.NewClusterConfig()
@@ -148,6 +296,638 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Describe("nodeGroups[*].httpPutResponseHopLimit validation", func() {
+		DescribeTable("validates the hop limit", func(hopLimit int, expectedErr string) {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.HTTPPutResponseHopLimit = aws.Int(hopLimit)
+			err := api.ValidateNodeGroup(0, ng0)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(expectedErr))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+			Entry("below the minimum", 0, "nodeGroups[0].httpPutResponseHopLimit must be between 1 and 64"),
+			Entry("above the maximum", 65, "nodeGroups[0].httpPutResponseHopLimit must be between 1 and 64"),
+			Entry("minimum", 1, ""),
+			Entry("maximum", 64, ""),
+		)
+	})
+
+	Describe("nodeGroups[*].disableLaunchTemplateMetadataOptions validation", func() {
+		const conflictErr = "nodeGroups[0].disableLaunchTemplateMetadataOptions cannot be used with nodeGroups[0].disableIMDSv1, " +
+			"nodeGroups[0].disablePodIMDS or nodeGroups[0].httpPutResponseHopLimit, which are set in the metadata options"
+
+		DescribeTable("rejects the fields set in the metadata options", func(updateNodeGroup func(*api.NodeGroup), expectedErr string) {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.DisableLaunchTemplateMetadataOptions = api.Enabled()
+			updateNodeGroup(ng0)
+			err := api.ValidateNodeGroup(0, ng0)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(expectedErr))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+			Entry("alone", func(*api.NodeGroup) {}, ""),
+			Entry("with disableIMDSv1", func(ng *api.NodeGroup) {
+				ng.DisableIMDSv1 = api.Enabled()
+			}, conflictErr),
+			Entry("with disablePodIMDS", func(ng *api.NodeGroup) {
+				ng.DisablePodIMDS = api.Enabled()
+			}, conflictErr),
+			Entry("with httpPutResponseHopLimit", func(ng *api.NodeGroup) {
+				ng.HTTPPutResponseHopLimit = aws.Int(1)
+			}, conflictErr),
+		)
+	})
+
+	Describe("nodeGroups[*].warmPool validation", func() {
+		type warmPoolEntry struct {
+			warmPool              *api.WarmPool
+			instancesDistribution *api.NodeGroupInstancesDistribution
+			spot                  bool
+
+			expectedErr string
+		}
+
+		DescribeTable("validates the warm pool", func(e warmPoolEntry) {
+			cfg := api.NewClusterConfig()
+			ng0 := cfg.NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.WarmPool = e.warmPool
+			ng0.Spot = e.spot
+			if e.instancesDistribution != nil {
+				ng0.InstanceType = "mixed"
+				ng0.InstancesDistribution = e.instancesDistribution
+			}
+			err := api.ValidateNodeGroup(0, ng0)
+			if e.expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(e.expectedErr))
+			}
+		},
+			Entry("valid warm pool", warmPoolEntry{
+				warmPool: &api.WarmPool{
+					MinSize:             aws.Int(1),
+					MaxPreparedCapacity: aws.Int(3),
+					PoolState:           api.WarmPoolStateHibernated,
+					ReuseOnScaleIn:      aws.Bool(true),
+				},
+			}),
+			Entry("empty warm pool", warmPoolEntry{
+				warmPool: &api.WarmPool{},
+			}),
+			Entry("invalid pool state", warmPoolEntry{
+				warmPool: &api.WarmPool{
+					PoolState: "Terminated",
+				},
+				expectedErr: `invalid value "Terminated" for nodeGroups[0].warmPool.poolState; must be one of Stopped, Running or Hibernated`,
+			}),
+			Entry("maxPreparedCapacity below minSize", warmPoolEntry{
+				warmPool: &api.WarmPool{
+					MinSize:             aws.Int(3),
+					MaxPreparedCapacity: aws.Int(2),
+				},
+				expectedErr: "nodeGroups[0].warmPool.maxPreparedCapacity (2) cannot be less than nodeGroups[0].warmPool.minSize (3)",
+			}),
+			Entry("negative minSize", warmPoolEntry{
+				warmPool: &api.WarmPool{
+					MinSize: aws.Int(-1),
+				},
+				expectedErr: "nodeGroups[0].warmPool.minSize cannot be negative",
+			}),
+			Entry("instancesDistribution", warmPoolEntry{
+				warmPool: &api.WarmPool{},
+				instancesDistribution: &api.NodeGroupInstancesDistribution{
+					InstanceTypes: []string{"t3.medium", "t3.large"},
+				},
+				expectedErr: "nodeGroups[0].warmPool cannot be used with instancesDistribution or instanceSelector",
+			}),
+			Entry("spot", warmPoolEntry{
+				warmPool:    &api.WarmPool{},
+				spot:        true,
+				expectedErr: "nodeGroups[0].warmPool cannot be used with spot",
+			}),
+		)
+	})
+
+	Describe("nodeGroups[*].spot validation", func() {
+		var ng0 *api.NodeGroup
+
+		BeforeEach(func() {
+			ng0 = api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.Spot = true
+		})
+
+		It("should allow spot for a single instance type", func() {
+			Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+		})
+
+		It("should reject spot with instancesDistribution", func() {
+			ng0.InstanceType = "mixed"
+			ng0.InstancesDistribution = &api.NodeGroupInstancesDistribution{
+				InstanceTypes: []string{"t3.medium", "t3.large"},
+			}
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].spot cannot be used with nodeGroups[0].instancesDistribution or nodeGroups[0].instanceSelector; set nodeGroups[0].instancesDistribution.onDemandPercentageAboveBaseCapacity to 0 to only launch Spot instances"))
+		})
+
+		It("should reject spot with a capacity reservation target", func() {
+			ng0.CapacityReservation = &api.CapacityReservation{
+				CapacityReservationTarget: &api.CapacityReservationTarget{
+					CapacityReservationID: aws.String("cr-1234"),
+				},
+			}
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].capacityReservation.capacityReservationTarget cannot be used with nodeGroups[0].spot"))
+		})
+	})
+
+	Describe("nodeGroups[*].shutdownBehavior validation", func() {
+		DescribeTable("validates the shutdown behavior", func(shutdownBehavior, expectedErr string) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.ShutdownBehavior = shutdownBehavior
+			err := api.ValidateNodeGroup(0, ng0)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+			Entry("unset", "", ""),
+			Entry("stop", api.ShutdownBehaviorStop, ""),
+			Entry("terminate", api.ShutdownBehaviorTerminate, ""),
+			Entry("an invalid value", "hibernate", `invalid value "hibernate" for nodeGroups[0].shutdownBehavior; must be one of stop or terminate`),
+		)
+
+		It("rejects the shutdown behavior for managed nodegroups", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.Name = "mng"
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.ShutdownBehavior = api.ShutdownBehaviorTerminate
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(MatchError("shutdownBehavior is not supported for managed nodegroups (managedNodeGroups[0].shutdownBehavior)"))
+		})
+	})
+
+	Describe("privateDNSNameOptions validation", func() {
+		DescribeTable("validates the hostname type of nodegroups", func(hostnameType, expectedErr string) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.PrivateDNSNameOptions = &api.PrivateDNSNameOptions{HostnameType: hostnameType}
+			err := api.ValidateNodeGroup(0, ng0)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+			Entry("ip-name", api.HostnameTypeIPName, ""),
+			Entry("resource-name", api.HostnameTypeResourceName, ""),
+			Entry("an invalid value", "instance-id", `invalid value "instance-id" for nodeGroups[0].privateDNSNameOptions.hostnameType; must be one of ip-name or resource-name`),
+		)
+
+		It("validates the hostname type of the cluster", func() {
+			cfg := api.NewClusterConfig()
+			cfg.PrivateDNSNameOptions = &api.PrivateDNSNameOptions{HostnameType: "instance-id"}
+			Expect(api.ValidateClusterConfig(cfg)).To(MatchError(`invalid value "instance-id" for privateDNSNameOptions.hostnameType; must be one of ip-name or resource-name`))
+		})
+	})
+
+	Describe("nodeGroups[*].amiResolutionMode validation", func() {
+		type amiResolutionModeEntry struct {
+			amiResolutionMode string
+			ami               string
+			amiFamily         string
+
+			expectedErr string
+		}
+
+		DescribeTable("validates the AMI resolution mode", func(e amiResolutionModeEntry) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.AMIResolutionMode = e.amiResolutionMode
+			if e.ami != "" {
+				ng0.AMI = e.ami
+				ng0.OverrideBootstrapCommand = aws.String("/etc/eks/bootstrap.sh")
+			}
+			if e.amiFamily != "" {
+				ng0.AMIFamily = e.amiFamily
+			}
+			err := api.ValidateNodeGroup(0, ng0)
+			if e.expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(e.expectedErr))
+			}
+		},
+			Entry("eksctl", amiResolutionModeEntry{
+				amiResolutionMode: api.AMIResolutionModeEksctl,
+				ami:               "ami-123",
+			}),
+			Entry("cloudformation", amiResolutionModeEntry{
+				amiResolutionMode: api.AMIResolutionModeCloudFormation,
+			}),
+			Entry("cloudformation with the SSM resolver", amiResolutionModeEntry{
+				amiResolutionMode: api.AMIResolutionModeCloudFormation,
+				ami:               api.NodeImageResolverAutoSSM,
+			}),
+			Entry("invalid mode", amiResolutionModeEntry{
+				amiResolutionMode: "ssm",
+				expectedErr:       `invalid value "ssm" for nodeGroups[0].amiResolutionMode; must be one of eksctl or cloudformation`,
+			}),
+			Entry("cloudformation with a custom AMI", amiResolutionModeEntry{
+				amiResolutionMode: api.AMIResolutionModeCloudFormation,
+				ami:               "ami-123",
+				expectedErr:       `nodeGroups[0].ami must be empty or "auto-ssm" when nodeGroups[0].amiResolutionMode is cloudformation`,
+			}),
+			Entry("cloudformation with Ubuntu", amiResolutionModeEntry{
+				amiResolutionMode: api.AMIResolutionModeCloudFormation,
+				amiFamily:         api.NodeImageFamilyUbuntu2004,
+				expectedErr:       "nodeGroups[0].amiResolutionMode cloudformation is not supported for the Ubuntu2004 AMI family, as its AMIs are not published in SSM",
+			}),
+		)
+	})
+
+	Describe("nodeGroups[*].amiReleaseVersion validation", func() {
+		type amiReleaseVersionEntry struct {
+			ami       string
+			amiFamily string
+
+			expectedErr string
+		}
+
+		DescribeTable("validates the AMI release version", func(e amiReleaseVersionEntry) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.AMIReleaseVersion = "1.19.2"
+			if e.ami != "" {
+				ng0.AMI = e.ami
+				ng0.OverrideBootstrapCommand = aws.String("/etc/eks/bootstrap.sh")
+			}
+			ng0.AMIFamily = e.amiFamily
+			err := api.ValidateNodeGroup(0, ng0)
+			if e.expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(e.expectedErr))
+			}
+		},
+			Entry("Bottlerocket", amiReleaseVersionEntry{
+				amiFamily: api.NodeImageFamilyBottlerocket,
+			}),
+			Entry("AmazonLinux2 with the SSM resolver", amiReleaseVersionEntry{
+				ami:       api.NodeImageResolverAutoSSM,
+				amiFamily: api.NodeImageFamilyAmazonLinux2,
+			}),
+			Entry("an AMI ID", amiReleaseVersionEntry{
+				ami:         "ami-123",
+				amiFamily:   api.NodeImageFamilyAmazonLinux2,
+				expectedErr: `nodeGroups[0].amiReleaseVersion cannot be set when nodeGroups[0].ami is "ami-123"; nodeGroups[0].ami must be empty or "auto-ssm"`,
+			}),
+			Entry("the auto resolver", amiReleaseVersionEntry{
+				ami:         api.NodeImageResolverAuto,
+				amiFamily:   api.NodeImageFamilyAmazonLinux2,
+				expectedErr: `nodeGroups[0].amiReleaseVersion cannot be set when nodeGroups[0].ami is "auto"; nodeGroups[0].ami must be empty or "auto-ssm"`,
+			}),
+			Entry("Ubuntu", amiReleaseVersionEntry{
+				amiFamily:   api.NodeImageFamilyUbuntu2004,
+				expectedErr: "nodeGroups[0].amiReleaseVersion is not supported for the Ubuntu2004 AMI family; it can only be set for AmazonLinux2, AmazonLinux2023 and Bottlerocket",
+			}),
+		)
+	})
+
+	Describe("nodeGroups[*].securityGroups.restrictControlPlaneRules validation", func() {
+		type controlPlaneRulesEntry struct {
+			restrict        *bool
+			withLocal       *bool
+			additionalPorts []int
+
+			expectedErr string
+		}
+
+		DescribeTable("validates the control plane rules", func(e controlPlaneRulesEntry) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.SecurityGroups.RestrictControlPlaneRules = e.restrict
+			ng0.SecurityGroups.AdditionalControlPlanePorts = e.additionalPorts
+			if e.withLocal != nil {
+				ng0.SecurityGroups.WithLocal = e.withLocal
+				ng0.SecurityGroups.AttachIDs = []string{"sg-123"}
+			}
+			err := api.ValidateNodeGroup(0, ng0)
+			if e.expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(e.expectedErr))
+			}
+		},
+			Entry("restricted rules with additional ports", controlPlaneRulesEntry{
+				restrict:        api.Enabled(),
+				additionalPorts: []int{8443, 9443},
+			}),
+			Entry("additional ports without restricted rules", controlPlaneRulesEntry{
+				additionalPorts: []int{8443},
+				expectedErr:     "nodeGroups[0].securityGroups.additionalControlPlanePorts can only be set when nodeGroups[0].securityGroups.restrictControlPlaneRules is enabled",
+			}),
+			Entry("restricted rules without the local security group", controlPlaneRulesEntry{
+				restrict:    api.Enabled(),
+				withLocal:   api.Disabled(),
+				expectedErr: "nodeGroups[0].securityGroups.restrictControlPlaneRules requires the local security group of the nodegroup, which is disabled by nodeGroups[0].securityGroups.withLocal",
+			}),
+			Entry("an out of range port", controlPlaneRulesEntry{
+				restrict:        api.Enabled(),
+				additionalPorts: []int{70000},
+				expectedErr:     "invalid port 70000 in nodeGroups[0].securityGroups.additionalControlPlanePorts; must be between 1 and 65535",
+			}),
+			Entry("the kubelet port", controlPlaneRulesEntry{
+				restrict:        api.Enabled(),
+				additionalPorts: []int{10250},
+				expectedErr:     "port 10250 in nodeGroups[0].securityGroups.additionalControlPlanePorts is always allowed",
+			}),
+			Entry("a duplicate port", controlPlaneRulesEntry{
+				restrict:        api.Enabled(),
+				additionalPorts: []int{8443, 8443},
+				expectedErr:     "duplicate port 8443 in nodeGroups[0].securityGroups.additionalControlPlanePorts",
+			}),
+		)
+	})
+
+	Describe("nodeGroups[*].tags validation", func() {
+		var ng0 *api.NodeGroup
+
+		BeforeEach(func() {
+			ng0 = api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.Tags = map[string]string{
+				"k8s.io/cluster-autoscaler/node-template/label/team": "a",
+			}
+		})
+
+		It("should reject cluster autoscaler tags when propagateASGTags is enabled", func() {
+			ng0.PropagateASGTags = api.Enabled()
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError(`nodeGroups[0].tags: tag "k8s.io/cluster-autoscaler/node-template/label/team" cannot be set when nodeGroups[0].propagateASGTags is enabled, as tags with the prefix "k8s.io/cluster-autoscaler/" are generated from labels and taints`))
+		})
+
+		It("should allow cluster autoscaler tags when propagateASGTags is not enabled", func() {
+			Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+		})
+	})
+
+	Describe("nodeGroups[*].disableDefaultInstanceTags validation", func() {
+		var ng0 *api.NodeGroup
+
+		BeforeEach(func() {
+			ng0 = api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+		})
+
+		It("should allow disabling the Name tag", func() {
+			ng0.DisableDefaultInstanceTags = []string{"Name"}
+			Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+		})
+
+		It("should reject disabling the cluster ownership tag", func() {
+			ng0.DisableDefaultInstanceTags = []string{"Name", "kubernetes.io/cluster/cluster-1"}
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError(`nodeGroups[0].disableDefaultInstanceTags: tag "kubernetes.io/cluster/cluster-1" cannot be disabled, as the cloud provider requires it on the instances of the cluster`))
+		})
+
+		It("should reject an empty key", func() {
+			ng0.DisableDefaultInstanceTags = []string{""}
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].disableDefaultInstanceTags cannot contain an empty key"))
+		})
+	})
+
+	Describe("nodeGroups[*].launchTemplate validation", func() {
+		var ng0 *api.NodeGroup
+
+		BeforeEach(func() {
+			ng0 = api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.InstanceType = ""
+			ng0.VolumeSize = nil
+			ng0.IAM.InstanceProfileARN = "arn:aws:iam::123456789012:instance-profile/node-profile"
+		})
+
+		DescribeTable("validates the launch template", func(lt *api.LaunchTemplate, expectedErr string) {
+			ng0.LaunchTemplate = lt
+			err := api.ValidateNodeGroup(0, ng0)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+			Entry("a launch template ID", &api.LaunchTemplate{ID: "lt-1234", Version: aws.String("2")}, ""),
+			Entry("a launch template name", &api.LaunchTemplate{Name: "my-launch-template", Version: aws.String("1")}, ""),
+			Entry("neither an ID nor a name", &api.LaunchTemplate{Version: aws.String("1")},
+				"nodeGroups[0].launchTemplate.id or nodeGroups[0].launchTemplate.name is required if nodeGroups[0].launchTemplate is set"),
+			Entry("both an ID and a name", &api.LaunchTemplate{ID: "lt-1234", Name: "my-launch-template", Version: aws.String("1")},
+				"only one of nodeGroups[0].launchTemplate.id and nodeGroups[0].launchTemplate.name can be set"),
+			Entry("no version", &api.LaunchTemplate{ID: "lt-1234"},
+				"nodeGroups[0].launchTemplate.version is required for unmanaged nodegroups"),
+			Entry("a version that is not a number", &api.LaunchTemplate{ID: "lt-1234", Version: aws.String("$Latest")},
+				`nodeGroups[0].launchTemplate.version must be a version number >= 1, got "$Latest"`),
+		)
+
+		It("rejects fields that are set by the launch template", func() {
+			ng0.LaunchTemplate = &api.LaunchTemplate{ID: "lt-1234", Version: aws.String("1")}
+			ng0.InstanceType = "m5.large"
+			ng0.AMI = "ami-1234"
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].ami, nodeGroups[0].instanceType cannot be set when nodeGroups[0].launchTemplate is set, as they are set by the launch template"))
+		})
+
+		It("requires the instance profile of the launch template", func() {
+			ng0.LaunchTemplate = &api.LaunchTemplate{ID: "lt-1234", Version: aws.String("1")}
+			ng0.IAM.InstanceProfileARN = ""
+			Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].iam.instanceProfileARN must be set to the instance profile of the launch template when nodeGroups[0].launchTemplate is set"))
+		})
+
+		It("rejects a launch template name for managed nodegroups", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.Name = "mng"
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.LaunchTemplate = &api.LaunchTemplate{Name: "my-launch-template"}
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(MatchError("launchTemplate.name is not supported for managed nodegroups, use launchTemplate.id instead (managedNodeGroups[0].launchTemplate.name)"))
+		})
+	})
+
+	Describe("nodeGroups[*].launchTemplateTagSpecifications validation", func() {
+		DescribeTable("validates the tags of each resource type", func(tagSpecs map[string]map[string]string, expectedErr string) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.LaunchTemplateTagSpecifications = tagSpecs
+			err := api.ValidateNodeGroup(0, ng0)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+			Entry("tags for all resource types", map[string]map[string]string{
+				"instance":          {"team": "a"},
+				"volume":            {"cost-center": "1234"},
+				"network-interface": {"Name": "eni"},
+			}, ""),
+			Entry("an unknown resource type", map[string]map[string]string{
+				"spot-instances-request": {"team": "a"},
+			}, `invalid resource type "spot-instances-request" in nodeGroups[0].launchTemplateTagSpecifications; must be one of instance, volume or network-interface`),
+			Entry("the cluster tag", map[string]map[string]string{
+				"volume": {"kubernetes.io/cluster/my-cluster": "shared"},
+			}, `nodeGroups[0].launchTemplateTagSpecifications.volume cannot set tag "kubernetes.io/cluster/my-cluster" as it is set by eksctl`),
+		)
+
+		It("rejects launchTemplateTagSpecifications for managed nodegroups with a launch template", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.Name = "mng"
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.LaunchTemplate = &api.LaunchTemplate{ID: "lt-1234"}
+			mng.LaunchTemplateTagSpecifications = map[string]map[string]string{
+				"volume": {"cost-center": "1234"},
+			}
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(MatchError(ContainSubstring("launchTemplateTagSpecifications")))
+		})
+	})
+
+	DescribeTable("nodeGroups[*].placement validation", func(placement *api.Placement, expectedErr string) {
+		ng0 := api.NewClusterConfig().NewNodeGroup()
+		ng0.Name = "node-group"
+		ng0.Placement = placement
+		err := api.ValidateNodeGroup(0, ng0)
+		if expectedErr == "" {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(expectedErr))
+		}
+	},
+		Entry("a placement group", &api.Placement{GroupName: "pg"}, ""),
+		Entry("dedicated tenancy without a placement group", &api.Placement{Tenancy: api.PlacementTenancyDedicated}, ""),
+		Entry("a host resource group", &api.Placement{
+			Tenancy:              api.PlacementTenancyHost,
+			HostResourceGroupARN: "arn:aws:resource-groups:us-west-2:123456789012:group/hosts",
+		}, ""),
+		Entry("a partition", &api.Placement{GroupName: "pg", PartitionNumber: aws.Int(2)}, ""),
+		Entry("neither a placement group nor a tenancy", &api.Placement{}, "nodeGroups[0].placement.groupName must be set and non-empty"),
+		Entry("an invalid tenancy", &api.Placement{Tenancy: "shared"},
+			`invalid value "shared" for nodeGroups[0].placement.tenancy; must be one of default, dedicated or host`),
+		Entry("a host resource group without host tenancy", &api.Placement{
+			Tenancy:              api.PlacementTenancyDedicated,
+			HostResourceGroupARN: "arn:aws:resource-groups:us-west-2:123456789012:group/hosts",
+		}, "nodeGroups[0].placement.hostResourceGroupArn requires nodeGroups[0].placement.tenancy to be host"),
+		Entry("an invalid host resource group ARN", &api.Placement{
+			Tenancy:              api.PlacementTenancyHost,
+			HostResourceGroupARN: "hosts",
+		}, `nodeGroups[0].placement.hostResourceGroupArn "hosts" is not a valid ARN`),
+		Entry("a partition without a placement group", &api.Placement{Tenancy: api.PlacementTenancyDefault, PartitionNumber: aws.Int(1)},
+			"nodeGroups[0].placement.partitionNumber requires nodeGroups[0].placement.groupName to be set to a placement group with the partition strategy"),
+		Entry("an invalid partition", &api.Placement{GroupName: "pg", PartitionNumber: aws.Int(0)}, "nodeGroups[0].placement.partitionNumber must be at least 1"),
+	)
+
+	Describe("nodeGroups[*].capacityReservation validation", func() {
+		type capacityReservationEntry struct {
+			capacityReservation   *api.CapacityReservation
+			instancesDistribution *api.NodeGroupInstancesDistribution
+
+			expectedErr string
+		}
+
+		DescribeTable("validates the Capacity Reservation options", func(e capacityReservationEntry) {
+			ng0 := api.NewClusterConfig().NewNodeGroup()
+			ng0.Name = "node-group"
+			ng0.CapacityReservation = e.capacityReservation
+			if e.instancesDistribution != nil {
+				ng0.InstanceType = "mixed"
+				ng0.InstancesDistribution = e.instancesDistribution
+			}
+			err := api.ValidateNodeGroup(0, ng0)
+			if e.expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(e.expectedErr))
+			}
+		},
+			Entry("a preference", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationPreference: aws.String("open"),
+				},
+			}),
+			Entry("a Capacity Reservation", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationTarget: &api.CapacityReservationTarget{
+						CapacityReservationID: aws.String("cr-1234"),
+					},
+				},
+			}),
+			Entry("a Capacity Reservation group", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationTarget: &api.CapacityReservationTarget{
+						CapacityReservationResourceGroupARN: aws.String("arn:aws:resource-groups:us-west-2:123456789012:group/gpu-reservations"),
+					},
+				},
+			}),
+			Entry("an invalid preference", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationPreference: aws.String("targeted"),
+				},
+				expectedErr: `invalid value "targeted" for nodeGroups[0].capacityReservation.capacityReservationPreference; must be one of open or none`,
+			}),
+			Entry("both a preference and a target", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationPreference: aws.String("open"),
+					CapacityReservationTarget: &api.CapacityReservationTarget{
+						CapacityReservationID: aws.String("cr-1234"),
+					},
+				},
+				expectedErr: "only one of nodeGroups[0].capacityReservation.capacityReservationPreference or nodeGroups[0].capacityReservation.capacityReservationTarget should be set",
+			}),
+			Entry("both a Capacity Reservation and a group", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationTarget: &api.CapacityReservationTarget{
+						CapacityReservationID:               aws.String("cr-1234"),
+						CapacityReservationResourceGroupARN: aws.String("arn:aws:resource-groups:us-west-2:123456789012:group/gpu-reservations"),
+					},
+				},
+				expectedErr: "exactly one of nodeGroups[0].capacityReservation.capacityReservationTarget.capacityReservationID or nodeGroups[0].capacityReservation.capacityReservationTarget.capacityReservationResourceGroupARN must be set",
+			}),
+			Entry("an empty target", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationTarget: &api.CapacityReservationTarget{},
+				},
+				expectedErr: "exactly one of nodeGroups[0].capacityReservation.capacityReservationTarget.capacityReservationID or nodeGroups[0].capacityReservation.capacityReservationTarget.capacityReservationResourceGroupARN must be set",
+			}),
+			Entry("a target with Spot instances only", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationTarget: &api.CapacityReservationTarget{
+						CapacityReservationID: aws.String("cr-1234"),
+					},
+				},
+				instancesDistribution: &api.NodeGroupInstancesDistribution{
+					InstanceTypes:                       []string{"p3.2xlarge", "p3.8xlarge"},
+					OnDemandBaseCapacity:                aws.Int(0),
+					OnDemandPercentageAboveBaseCapacity: aws.Int(0),
+				},
+				expectedErr: "nodeGroups[0].capacityReservation.capacityReservationTarget cannot be used when nodeGroups[0].instancesDistribution only launches Spot instances",
+			}),
+			Entry("a target with On-Demand and Spot instances", capacityReservationEntry{
+				capacityReservation: &api.CapacityReservation{
+					CapacityReservationTarget: &api.CapacityReservationTarget{
+						CapacityReservationID: aws.String("cr-1234"),
+					},
+				},
+				instancesDistribution: &api.NodeGroupInstancesDistribution{
+					InstanceTypes:                       []string{"p3.2xlarge", "p3.8xlarge"},
+					OnDemandBaseCapacity:                aws.Int(1),
+					OnDemandPercentageAboveBaseCapacity: aws.Int(0),
+				},
+			}),
+		)
+	})
+
 	Describe("nodeGroups[*].volumeX", func() {
 		var (
 			cfg *api.ClusterConfig
@@ -226,36 +1006,223 @@ var _ = Describe("ClusterConfig validation", func() {
 				ng0.VolumeThroughput = aws.Int(125)
 			})
 
-			When("VolumeType is gp3", func() {
-				BeforeEach(func() {
-					*ng0.VolumeType = api.NodeVolumeTypeGP3
-				})
+			When("VolumeType is gp3", func() {
+				BeforeEach(func() {
+					*ng0.VolumeType = api.NodeVolumeTypeGP3
+				})
+
+				It("does not fail", func() {
+					Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+				})
+
+				When(fmt.Sprintf("the value of volumeThroughput is < %d", api.MinThroughput), func() {
+					It("returns an error", func() {
+						ng0.VolumeThroughput = aws.Int(api.MinThroughput - 1)
+						Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].volumeThroughput must be within range 125-1000"))
+					})
+				})
+
+				When(fmt.Sprintf("the value of volumeIOPS is > %d", api.MaxThroughput), func() {
+					It("returns an error", func() {
+						ng0.VolumeThroughput = aws.Int(api.MaxThroughput + 1)
+						Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].volumeThroughput must be within range 125-1000"))
+					})
+				})
+			})
+
+			When("VolumeType is one for which Throughput is not supported", func() {
+				It("returns an error", func() {
+					*ng0.VolumeType = api.NodeVolumeTypeGP2
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].volumeThroughput is only supported for gp3 volume type"))
+				})
+			})
+		})
+
+		When("volumeInitializationRate is set on an additional volume", func() {
+			BeforeEach(func() {
+				ng0.AdditionalVolumes = []*api.VolumeMapping{
+					{
+						VolumeSize:               aws.Int(20),
+						VolumeName:               aws.String("/dev/xvdb"),
+						SnapshotID:               aws.String("snap-1234"),
+						VolumeInitializationRate: aws.Int(200),
+					},
+				}
+			})
+
+			It("does not fail", func() {
+				Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+			})
+
+			When("snapshotID is not set", func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[0].SnapshotID = nil
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].additionalVolumes[0].volumeInitializationRate can only be set when nodeGroups[0].additionalVolumes[0].snapshotID is set"))
+				})
+			})
+
+			When(fmt.Sprintf("the value of volumeInitializationRate is < %d", api.MinVolumeInitializationRate), func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[0].VolumeInitializationRate = aws.Int(api.MinVolumeInitializationRate - 1)
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].additionalVolumes[0].volumeInitializationRate must be within range 100-300"))
+				})
+			})
+
+			When(fmt.Sprintf("the value of volumeInitializationRate is > %d", api.MaxVolumeInitializationRate), func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[0].VolumeInitializationRate = aws.Int(api.MaxVolumeInitializationRate + 1)
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].additionalVolumes[0].volumeInitializationRate must be within range 100-300"))
+				})
+			})
+		})
+
+		When("volumeIOPS and volumeThroughput are set on additional volumes", func() {
+			BeforeEach(func() {
+				ng0.AdditionalVolumes = []*api.VolumeMapping{
+					{
+						VolumeSize:       aws.Int(20),
+						VolumeType:       aws.String(api.NodeVolumeTypeGP3),
+						VolumeName:       aws.String("/dev/xvdb"),
+						VolumeIOPS:       aws.Int(3000),
+						VolumeThroughput: aws.Int(125),
+					},
+					{
+						VolumeSize:       aws.Int(100),
+						VolumeType:       aws.String(api.NodeVolumeTypeGP3),
+						VolumeName:       aws.String("/dev/xvdc"),
+						VolumeIOPS:       aws.Int(16000),
+						VolumeThroughput: aws.Int(1000),
+					},
+				}
+			})
+
+			It("does not fail", func() {
+				Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
+			})
+
+			When("VolumeType is one for which Throughput is not supported", func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[1].VolumeType = aws.String(api.NodeVolumeTypeIO1)
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].additionalVolumes[1].volumeThroughput is only supported for gp3 volume type"))
+				})
+			})
+
+			When(fmt.Sprintf("the value of volumeIOPS is < %d", api.MinGP3Iops), func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[0].VolumeIOPS = aws.Int(api.MinGP3Iops - 1)
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].additionalVolumes[0].volumeIOPS must be within range 3000-16000"))
+				})
+			})
+
+			When(fmt.Sprintf("the value of volumeIOPS is > %d", api.MaxGP3Iops), func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[1].VolumeIOPS = aws.Int(api.MaxGP3Iops + 1)
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].additionalVolumes[1].volumeIOPS must be within range 3000-16000"))
+				})
+			})
+
+			When(fmt.Sprintf("the value of volumeThroughput is > %d", api.MaxThroughput), func() {
+				It("returns an error", func() {
+					ng0.AdditionalVolumes[0].VolumeThroughput = aws.Int(api.MaxThroughput + 1)
+					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].additionalVolumes[0].volumeThroughput must be within range 125-1000"))
+				})
+			})
+		})
+
+		When("the device names of additional volumes collide", func() {
+			BeforeEach(func() {
+				ng0.AdditionalVolumes = []*api.VolumeMapping{
+					{
+						VolumeSize: aws.Int(20),
+						VolumeName: aws.String("/dev/xvdb"),
+					},
+					{
+						VolumeSize: aws.Int(100),
+						VolumeName: aws.String("/dev/xvdc"),
+					},
+				}
+			})
+
+			It("returns an error if an additional volume uses the default root device", func() {
+				ng0.AdditionalVolumes[1].VolumeName = aws.String("/dev/xvda")
+				Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError(`nodeGroups[0].additionalVolumes[1].volumeName "/dev/xvda" is already used by the root volume`))
+			})
+
+			It("returns an error if an additional volume uses the device of volumeName", func() {
+				ng0.VolumeSize = aws.Int(80)
+				ng0.VolumeName = aws.String("/dev/xvdb")
+				Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError(`nodeGroups[0].additionalVolumes[0].volumeName "/dev/xvdb" is already used by the root volume`))
+			})
+
+			It("returns an error if two additional volumes use the same device", func() {
+				ng0.AdditionalVolumes[1].VolumeName = aws.String("/dev/xvdb")
+				Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError(`nodeGroups[0].additionalVolumes[1].volumeName "/dev/xvdb" is already used by nodeGroups[0].additionalVolumes[0]`))
+			})
+
+			It("returns an error if an additional volume uses the OS device of Bottlerocket", func() {
+				ng0.VolumeSize = aws.Int(80)
+				ng0.VolumeName = aws.String("/dev/xvdb")
+				ng0.AdditionalEncryptedVolume = "/dev/xvda"
+				ng0.AdditionalVolumes = []*api.VolumeMapping{
+					{
+						VolumeSize: aws.Int(20),
+						VolumeName: aws.String("/dev/xvda"),
+					},
+				}
+				Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError(`nodeGroups[0].additionalVolumes[0].volumeName "/dev/xvda" is already used by the OS volume`))
+			})
+		})
+	})
+
+	Describe("nodeGroups[*].associatePublicIPAddress", func() {
+		It("accepts nodegroups in public subnets", func() {
+			ng := api.NewClusterConfig().NewNodeGroup()
+			ng.AssociatePublicIPAddress = api.Enabled()
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("cannot be enabled along with privateNetworking", func() {
+			ng := api.NewClusterConfig().NewNodeGroup()
+			ng.AssociatePublicIPAddress = api.Enabled()
+			ng.PrivateNetworking = true
+			Expect(api.ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].associatePublicIPAddress cannot be enabled when nodeGroups[0].privateNetworking is enabled"))
+		})
+
+		It("can be disabled along with privateNetworking", func() {
+			ng := api.NewClusterConfig().NewNodeGroup()
+			ng.AssociatePublicIPAddress = api.Disabled()
+			ng.PrivateNetworking = true
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
 
-				It("does not fail", func() {
-					Expect(api.ValidateNodeGroup(0, ng0)).To(Succeed())
-				})
+		It("is not supported for managed nodegroups", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.AssociatePublicIPAddress = api.Enabled()
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(MatchError("associatePublicIPAddress is not supported for managed nodegroups (managedNodeGroups[0].associatePublicIPAddress)"))
+		})
+	})
 
-				When(fmt.Sprintf("the value of volumeThroughput is < %d", api.MinThroughput), func() {
-					It("returns an error", func() {
-						ng0.VolumeThroughput = aws.Int(api.MinThroughput - 1)
-						Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].volumeThroughput must be within range 125-1000"))
-					})
-				})
+	Describe("nodeGroups[*].outpostARN", func() {
+		const outpostARN = "arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"
 
-				When(fmt.Sprintf("the value of volumeIOPS is > %d", api.MaxThroughput), func() {
-					It("returns an error", func() {
-						ng0.VolumeThroughput = aws.Int(api.MaxThroughput + 1)
-						Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("value for nodeGroups[0].volumeThroughput must be within range 125-1000"))
-					})
-				})
-			})
+		It("accepts an Outpost ARN", func() {
+			ng := api.NewClusterConfig().NewNodeGroup()
+			ng.OutpostARN = outpostARN
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+		})
 
-			When("VolumeType is one for which Throughput is not supported", func() {
-				It("returns an error", func() {
-					*ng0.VolumeType = api.NodeVolumeTypeGP2
-					Expect(api.ValidateNodeGroup(0, ng0)).To(MatchError("nodeGroups[0].volumeThroughput is only supported for gp3 volume type"))
-				})
-			})
+		It("rejects an ARN that is not of an Outpost", func() {
+			ng := api.NewClusterConfig().NewNodeGroup()
+			ng.OutpostARN = "arn:aws:ec2:us-west-2:123456789012:subnet/subnet-1234"
+			Expect(api.ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].outpostARN "arn:aws:ec2:us-west-2:123456789012:subnet/subnet-1234" is not a valid Outpost ARN`))
+		})
+
+		It("is not supported for managed nodegroups", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.OutpostARN = outpostARN
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(MatchError("outpostARN is not supported for managed nodegroups (managedNodeGroups[0].outpostARN)"))
 		})
 	})
 
@@ -1024,6 +1991,54 @@ var _ = Describe("ClusterConfig validation", func() {
 			})
 		})
 
+		Context("subnet tags", func() {
+			manyTags := func(n int) map[string]string {
+				tags := map[string]string{}
+				for i := 0; i < n; i++ {
+					tags[fmt.Sprintf("key-%d", i)] = "value"
+				}
+				return tags
+			}
+
+			DescribeTable("validates the tags of the subnets", func(updateVPC func(*api.ClusterVPC), expectedErr string) {
+				updateVPC(cfg.VPC)
+				err = cfg.ValidateVPCConfig()
+				if expectedErr == "" {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(expectedErr))
+				}
+			},
+				Entry("valid tags", func(vpc *api.ClusterVPC) {
+					vpc.SubnetTags = map[string]string{"karpenter.sh/discovery": "cluster-1"}
+					vpc.PublicSubnetTags = map[string]string{"team": "web"}
+					vpc.PrivateSubnetTags = manyTags(47)
+				}, ""),
+				Entry("empty key", func(vpc *api.ClusterVPC) {
+					vpc.SubnetTags = map[string]string{"": "value"}
+				}, "vpc.subnetTags cannot contain an empty key"),
+				Entry("reserved prefix", func(vpc *api.ClusterVPC) {
+					vpc.PrivateSubnetTags = map[string]string{"AWS:team": "web"}
+				}, `vpc.privateSubnetTags: key "AWS:team" cannot start with the reserved prefix "aws:"`),
+				Entry("key too long", func(vpc *api.ClusterVPC) {
+					vpc.PublicSubnetTags = map[string]string{string(bytes.Repeat([]byte("k"), 129)): "value"}
+				}, fmt.Sprintf("vpc.publicSubnetTags: key %q is longer than 128 characters", bytes.Repeat([]byte("k"), 129))),
+				Entry("value too long", func(vpc *api.ClusterVPC) {
+					vpc.SubnetTags = map[string]string{"team": string(bytes.Repeat([]byte("v"), 257))}
+				}, `vpc.subnetTags: the value of key "team" is longer than 256 characters`),
+				Entry("Name tag", func(vpc *api.ClusterVPC) {
+					vpc.PublicSubnetTags = map[string]string{"Name": "public"}
+				}, "the Name tag of the public subnets is set by eksctl and cannot be set in vpc.subnetTags or vpc.publicSubnetTags"),
+				Entry("too many tags along with the tags of all subnets", func(vpc *api.ClusterVPC) {
+					vpc.SubnetTags = manyTags(40)
+					vpc.PrivateSubnetTags = map[string]string{}
+					for i := 0; i < 10; i++ {
+						vpc.PrivateSubnetTags[fmt.Sprintf("private-%d", i)] = "value"
+					}
+				}, "private subnets cannot have more than 48 tags along with the tags of vpc.subnetTags, got 50"),
+			)
+		})
+
 		Context("ipv6 CIDRs", func() {
 			When("IPv6Cidr or IPv6CidrPool is provided and ipv6 is not set", func() {
 				It("returns an error", func() {
@@ -1127,6 +2142,72 @@ var _ = Describe("ClusterConfig validation", func() {
 		})
 	})
 
+	Describe("vpc.sharedNodeSecurityGroup disabled", func() {
+		var cfg *api.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.VPC.SharedNodeSecurityGroup = api.SharedNodeSecurityGroupDisabled
+		})
+
+		It("accepts nodegroups that disable withShared", func() {
+			cfg.NodeGroups = append(cfg.NodeGroups, &api.NodeGroup{NodeGroupBase: &api.NodeGroupBase{
+				Name: "ng",
+				SecurityGroups: &api.NodeGroupSGs{
+					WithShared: api.Disabled(),
+					AttachIDs:  []string{"sg-nodes"},
+				},
+			}})
+			api.SetClusterConfigDefaults(cfg)
+			Expect(cfg.ValidateVPCConfig()).To(Succeed())
+		})
+
+		It("accepts managed nodegroups", func() {
+			cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, &api.ManagedNodeGroup{NodeGroupBase: &api.NodeGroupBase{Name: "mng"}})
+			api.SetClusterConfigDefaults(cfg)
+			Expect(cfg.ValidateVPCConfig()).To(Succeed())
+		})
+
+		DescribeTable("rejects unmanaged nodegroups that do not disable withShared", func(securityGroups *api.NodeGroupSGs) {
+			cfg.NodeGroups = append(cfg.NodeGroups, &api.NodeGroup{NodeGroupBase: &api.NodeGroupBase{
+				Name:           "ng",
+				SecurityGroups: securityGroups,
+			}})
+			api.SetClusterConfigDefaults(cfg)
+			Expect(cfg.ValidateVPCConfig()).To(MatchError(ContainSubstring(`nodeGroups[0].securityGroups.withShared must be disabled for nodegroup "ng" as vpc.sharedNodeSecurityGroup is "disabled"`)))
+		},
+			Entry("without security groups", nil),
+			Entry("without withShared", &api.NodeGroupSGs{}),
+			Entry("with withShared enabled", &api.NodeGroupSGs{WithShared: api.Enabled()}),
+		)
+
+		It("rejects fully-private clusters", func() {
+			cfg.PrivateCluster = &api.PrivateCluster{
+				Enabled: true,
+			}
+			api.SetClusterConfigDefaults(cfg)
+			Expect(cfg.ValidateVPCConfig()).To(MatchError(ContainSubstring("fully-private cluster")))
+		})
+	})
+
+	Describe("vpc.manageControlPlaneSecurityGroupRules disabled", func() {
+		var cfg *api.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.VPC.ManageControlPlaneSecurityGroupRules = api.Disabled()
+		})
+
+		It("allows control plane security groups not created by eksctl", func() {
+			cfg.VPC.SecurityGroup = "sg-123"
+			Expect(cfg.ValidateVPCConfig()).To(Succeed())
+		})
+
+		It("rejects control plane security groups created by eksctl", func() {
+			Expect(cfg.ValidateVPCConfig()).To(MatchError("vpc.manageControlPlaneSecurityGroupRules can only be disabled when the control plane security group is not created by eksctl (vpc.securityGroup)"))
+		})
+	})
+
 	Describe("ValidatePrivateCluster", func() {
 		var (
 			cfg *api.ClusterConfig
@@ -1272,6 +2353,60 @@ var _ = Describe("ClusterConfig validation", func() {
 			Expect(err).To(MatchError("only one of publicKeyName, publicKeyPath or publicKey can be specified for SSH per node-group"))
 		})
 
+		It("fails when key names and a key name are specified", func() {
+			ng.SSH = &api.NodeGroupSSH{
+				Allow:          api.Enabled(),
+				PublicKeyName:  &testKeyName,
+				PublicKeyNames: []string{"primary", "escrow"},
+			}
+			err := api.ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("publicKeyNames cannot be specified along with publicKeyName, publicKeyPath or publicKey for SSH per node-group"))
+		})
+
+		DescribeTable("several key names",
+			func(amiFamily string, expectedErr string) {
+				ng.AMIFamily = amiFamily
+				ng.SSH = &api.NodeGroupSSH{
+					Allow:          api.Enabled(),
+					PublicKeyNames: []string{"primary", "escrow"},
+				}
+				err := api.ValidateNodeGroup(0, ng)
+				if expectedErr == "" {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(expectedErr))
+				}
+			},
+			Entry("are supported for AmazonLinux2", api.NodeImageFamilyAmazonLinux2, ""),
+			Entry("are supported for AmazonLinux2023", api.NodeImageFamilyAmazonLinux2023, ""),
+			Entry("are supported for Ubuntu2004", api.NodeImageFamilyUbuntu2004, ""),
+			Entry("are not supported for Bottlerocket", api.NodeImageFamilyBottlerocket,
+				"only one SSH key pair can be specified for Bottlerocket nodegroups, as additional public keys are added to the authorized_keys of Amazon Linux and Ubuntu nodes by their user data (path=nodeGroups[0].ssh.publicKeyNames)"),
+			Entry("are not supported for Windows", api.NodeImageFamilyWindowsServer2019FullContainer,
+				"only one SSH key pair can be specified for WindowsServer2019FullContainer nodegroups, as additional public keys are added to the authorized_keys of Amazon Linux and Ubuntu nodes by their user data (path=nodeGroups[0].ssh.publicKeyNames)"),
+		)
+
+		DescribeTable("several key names for managed nodegroups",
+			func(amiFamily string, expectedErr string) {
+				mng := api.NewManagedNodeGroup()
+				mng.Name = "mng"
+				mng.AMIFamily = amiFamily
+				mng.SSH = &api.NodeGroupSSH{
+					Allow:          api.Enabled(),
+					PublicKeyNames: []string{"primary", "escrow"},
+				}
+				err := api.ValidateManagedNodeGroup(0, mng)
+				if expectedErr == "" {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(expectedErr))
+				}
+			},
+			Entry("are supported for AmazonLinux2", api.NodeImageFamilyAmazonLinux2, ""),
+			Entry("are not supported for Bottlerocket", api.NodeImageFamilyBottlerocket,
+				"only one SSH key pair can be specified for Bottlerocket nodegroups, as additional public keys are added to the authorized_keys of Amazon Linux and Ubuntu nodes by their user data (path=managedNodeGroups[0].ssh.publicKeyNames)"),
+		)
+
 		Context("Instances distribution", func() {
 			var ng *api.NodeGroup
 			BeforeEach(func() {
@@ -1350,7 +2485,70 @@ var _ = Describe("ClusterConfig validation", func() {
 				ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("unsupported-strategy")
 
 				err := api.ValidateNodeGroup(0, ng)
-				Expect(err).To(MatchError("spotAllocationStrategy should be one of: lowest-price, capacity-optimized, capacity-optimized-prioritized"))
+				Expect(err).To(MatchError("spotAllocationStrategy should be one of: lowest-price, capacity-optimized, capacity-optimized-prioritized, price-capacity-optimized"))
+			})
+
+			It("It fails when the onDemandAllocationStrategy is not a supported strategy", func() {
+				ng.InstancesDistribution.OnDemandAllocationStrategy = strings.Pointer("capacity-optimized")
+
+				err := api.ValidateNodeGroup(0, ng)
+				Expect(err).To(MatchError("onDemandAllocationStrategy should be one of: lowest-price, prioritized"))
+			})
+
+			It("It does not fail when the onDemandAllocationStrategy is prioritized", func() {
+				ng.InstancesDistribution.OnDemandAllocationStrategy = strings.Pointer("prioritized")
+
+				err := api.ValidateNodeGroup(0, ng)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			DescribeTable("capacityReservation", func(update func(*api.NodeGroup), expectedErr string) {
+				ng.InstancesDistribution.CapacityReservation = &api.InstancesDistributionCapacityReservation{
+					CapacityReservationPreference: api.CapacityReservationPreferenceReservationsFirst,
+					CapacityReservationIDs:        []string{"cr-1234"},
+				}
+				if update != nil {
+					update(ng)
+				}
+				err := api.ValidateNodeGroup(0, ng)
+				if expectedErr == "" {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(expectedErr))
+				}
+			},
+				Entry("on-demand base capacity is reserved while spot fills the rest", nil, ""),
+				Entry("all instances are on-demand and only launched into reservations", func(ng *api.NodeGroup) {
+					ng.InstancesDistribution.CapacityReservation.CapacityReservationPreference = api.CapacityReservationPreferenceReservationsOnly
+					ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity = newInt(100)
+				}, ""),
+				Entry("unsupported preference", func(ng *api.NodeGroup) {
+					ng.InstancesDistribution.CapacityReservation.CapacityReservationPreference = "open"
+				}, "capacityReservation.capacityReservationPreference should be one of: capacity-reservations-first, capacity-reservations-only"),
+				Entry("IDs and resource groups", func(ng *api.NodeGroup) {
+					ng.InstancesDistribution.CapacityReservation.CapacityReservationResourceGroupARNs = []string{"arn:aws:resource-groups:us-west-2:123456789012:group/reservations"}
+				}, "capacityReservation.capacityReservationIDs and capacityReservation.capacityReservationResourceGroupARNs cannot be set at the same time"),
+				Entry("capacityReservation of the nodegroup", func(ng *api.NodeGroup) {
+					ng.CapacityReservation = &api.CapacityReservation{CapacityReservationPreference: aws.String(api.CapacityReservationPreferenceOpen)}
+				}, "instancesDistribution.capacityReservation cannot be set at the same time as the capacityReservation of the nodegroup"),
+				Entry("spot only", func(ng *api.NodeGroup) {
+					ng.InstancesDistribution.OnDemandBaseCapacity = newInt(0)
+					ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity = newInt(0)
+				}, "capacityReservation cannot be set when the instances distribution only launches spot instances"),
+				Entry("only reservations with spot instances", func(ng *api.NodeGroup) {
+					ng.InstancesDistribution.CapacityReservation.CapacityReservationPreference = api.CapacityReservationPreferenceReservationsOnly
+				}, `capacityReservation.capacityReservationPreference cannot be "capacity-reservations-only" when the instances distribution launches spot instances, use "capacity-reservations-first" or set onDemandPercentageAboveBaseCapacity to 100`),
+				Entry("no on-demand base capacity with spot instances", func(ng *api.NodeGroup) {
+					ng.InstancesDistribution.OnDemandBaseCapacity = nil
+				}, "onDemandBaseCapacity must be set to the number of on-demand instances to launch into Capacity Reservations when the instances distribution also launches spot instances"),
+			)
+
+			It("It does not fail when the spotAllocationStrategy is price-capacity-optimized", func() {
+				ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("price-capacity-optimized")
+				ng.InstancesDistribution.SpotInstancePools = nil
+
+				err := api.ValidateNodeGroup(0, ng)
+				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("It fails when the spotAllocationStrategy is capacity-optimized and spotInstancePools is specified", func() {
@@ -1369,6 +2567,14 @@ var _ = Describe("ClusterConfig validation", func() {
 				Expect(err).To(MatchError("spotInstancePools cannot be specified when also specifying spotAllocationStrategy: capacity-optimized-prioritized"))
 			})
 
+			It("It fails when the spotAllocationStrategy is price-capacity-optimized and spotInstancePools is specified", func() {
+				ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("price-capacity-optimized")
+				ng.InstancesDistribution.SpotInstancePools = newInt(2)
+
+				err := api.ValidateNodeGroup(0, ng)
+				Expect(err).To(MatchError("spotInstancePools cannot be specified when also specifying spotAllocationStrategy: price-capacity-optimized"))
+			})
+
 			It("It does not fail when the spotAllocationStrategy is lowest-price and spotInstancePools is specified", func() {
 				ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("lowest-price")
 				ng.InstancesDistribution.SpotInstancePools = newInt(2)
@@ -1376,6 +2582,124 @@ var _ = Describe("ClusterConfig validation", func() {
 				err := api.ValidateNodeGroup(0, ng)
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			Context("instanceTypeOverrides", func() {
+				BeforeEach(func() {
+					ng.InstancesDistribution.InstanceTypes = nil
+					ng.InstancesDistribution.SpotInstancePools = nil
+					ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("capacity-optimized-prioritized")
+					ng.InstancesDistribution.InstanceTypeOverrides = []api.InstanceTypeOverride{
+						{InstanceType: "m5.2xlarge", WeightedCapacity: newInt(2)},
+						{InstanceType: "m5.xlarge", WeightedCapacity: newInt(1)},
+					}
+				})
+
+				It("It does not fail with weighted overrides and the capacity-optimized-prioritized strategy", func() {
+					Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+					Expect(ng.InstanceTypeList()).To(Equal([]string{"m5.2xlarge", "m5.xlarge"}))
+				})
+
+				It("It does not fail with overrides without weights and any strategy", func() {
+					ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("lowest-price")
+					for i := range ng.InstancesDistribution.InstanceTypeOverrides {
+						ng.InstancesDistribution.InstanceTypeOverrides[i].WeightedCapacity = nil
+					}
+					Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+				})
+
+				It("It fails when instanceTypes is also set", func() {
+					ng.InstancesDistribution.InstanceTypes = []string{"m5.xlarge"}
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceTypes and instanceTypeOverrides cannot be set at the same time"))
+				})
+
+				It("It fails when an override has no instance type", func() {
+					ng.InstancesDistribution.InstanceTypeOverrides[1].InstanceType = ""
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceTypeOverrides[1].instanceType must be set"))
+				})
+
+				It("It fails when a weightedCapacity is out of range", func() {
+					ng.InstancesDistribution.InstanceTypeOverrides[0].WeightedCapacity = newInt(1000)
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceTypeOverrides[0].weightedCapacity should be between 1 and 999"))
+				})
+
+				It("It fails when weightedCapacity is only set for some overrides", func() {
+					ng.InstancesDistribution.InstanceTypeOverrides[1].WeightedCapacity = nil
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("weightedCapacity must be set for all or none of instanceTypeOverrides"))
+				})
+
+				It("It does not fail with weighted overrides and any strategy", func() {
+					ng.InstancesDistribution.SpotAllocationStrategy = strings.Pointer("lowest-price")
+					Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+
+					ng.InstancesDistribution.SpotAllocationStrategy = nil
+					Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+				})
+			})
+
+			Context("instanceRequirements", func() {
+				BeforeEach(func() {
+					ng.InstancesDistribution.InstanceTypes = nil
+					ng.InstancesDistribution.InstanceRequirements = &api.InstanceRequirements{
+						VCPUCount: &api.InstanceRequirementsRange{
+							Min: newInt(2),
+							Max: newInt(8),
+						},
+						MemoryMiB: &api.InstanceRequirementsRange{
+							Min: newInt(4096),
+						},
+						CPUManufacturers:      []string{"intel", "amd"},
+						ExcludedInstanceTypes: []string{"t2.*"},
+					}
+				})
+
+				It("It does not fail with valid instance requirements", func() {
+					Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+					Expect(api.HasMixedInstances(ng)).To(BeTrue())
+					Expect(ng.InstanceTypeList()).To(BeEmpty())
+				})
+
+				It("It fails when instanceTypes is also set", func() {
+					ng.InstancesDistribution.InstanceTypes = []string{"m5.xlarge", "m5.2xlarge"}
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceRequirements cannot be set at the same time as instanceTypes or instanceTypeOverrides"))
+				})
+
+				It("It fails when instanceTypeOverrides is also set", func() {
+					ng.InstancesDistribution.InstanceTypeOverrides = []api.InstanceTypeOverride{
+						{InstanceType: "m5.xlarge"},
+					}
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceRequirements cannot be set at the same time as instanceTypes or instanceTypeOverrides"))
+				})
+
+				It("It fails when the minimum vCPU count is not set", func() {
+					ng.InstancesDistribution.InstanceRequirements.VCPUCount.Min = nil
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceRequirements.vCPUCount.min must be set"))
+				})
+
+				It("It fails when the memory range is not set", func() {
+					ng.InstancesDistribution.InstanceRequirements.MemoryMiB = nil
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceRequirements.memoryMiB.min must be set"))
+				})
+
+				It("It fails when the maximum is less than the minimum", func() {
+					ng.InstancesDistribution.InstanceRequirements.VCPUCount.Max = newInt(1)
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError("instanceRequirements.vCPUCount.max (1) cannot be less than instanceRequirements.vCPUCount.min (2)"))
+				})
+
+				It("It fails with an unknown CPU manufacturer", func() {
+					ng.InstancesDistribution.InstanceRequirements.CPUManufacturers = []string{"arm"}
+					err := api.ValidateNodeGroup(0, ng)
+					Expect(err).To(MatchError(`instanceRequirements.cpuManufacturers contains invalid value "arm"; must be one of intel, amd or amazon-web-services`))
+				})
+			})
 		})
 	})
 
@@ -1667,7 +2991,7 @@ var _ = Describe("ClusterConfig validation", func() {
 		It("fails when the AMIFamily is not supported", func() {
 			ng.AMIFamily = "SomeTrash"
 			err := api.ValidateNodeGroup(0, ng)
-			Expect(err).To(MatchError("AMI Family SomeTrash is not supported - use one of: AmazonLinux2, Ubuntu2004, Ubuntu1804, Bottlerocket, WindowsServer2019CoreContainer, WindowsServer2019FullContainer, WindowsServer2004CoreContainer, WindowsServer20H2CoreContainer"))
+			Expect(err).To(MatchError("AMI Family SomeTrash is not supported - use one of: AmazonLinux2, AmazonLinux2023, Ubuntu2004, Ubuntu1804, Bottlerocket, WindowsServer2019CoreContainer, WindowsServer2019FullContainer, WindowsServer2004CoreContainer, WindowsServer20H2CoreContainer, WindowsServer2022CoreContainer, WindowsServer2022FullContainer"))
 		})
 	})
 
@@ -1831,6 +3155,87 @@ var _ = Describe("ClusterConfig validation", func() {
 		}),
 	)
 
+	It("names each restricted label of a nodegroup", func() {
+		ng := newNodeGroup()
+		ng.Labels = map[string]string{
+			"node-role.kubernetes.io/worker": "",
+			"team":                           "a",
+			"example.k8s.io/tier":            "gpu",
+		}
+		Expect(api.ValidateNodeGroup(0, ng)).To(MatchError("unknown 'kubernetes.io' or 'k8s.io' labels were specified: [example.k8s.io/tier node-role.kubernetes.io/worker]"))
+	})
+
+	Describe("restricted nodegroup labels and taints", func() {
+		var (
+			output       *bytes.Buffer
+			loggerWriter io.Writer
+			loggerLevel  int
+		)
+
+		BeforeEach(func() {
+			output = &bytes.Buffer{}
+			loggerWriter = logger.Writer
+			logger.Writer = output
+			loggerLevel = logger.Level
+			logger.Level = 3
+		})
+
+		AfterEach(func() {
+			logger.Writer = loggerWriter
+			logger.Level = loggerLevel
+		})
+
+		It("warns about each label restricted by the kubelet", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.Labels = map[string]string{
+				"node-role.kubernetes.io/worker": "",
+				"kubernetes.io/os":               "linux",
+				"team":                           "a",
+			}
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring(`label "node-role.kubernetes.io/worker" of managedNodeGroups[0] is in a 'kubernetes.io' or 'k8s.io' namespace restricted by the kubelet`))
+			Expect(output.String()).NotTo(ContainSubstring(`"kubernetes.io/os"`))
+			Expect(output.String()).NotTo(ContainSubstring(`"team"`))
+		})
+
+		It("warns about each taint of a managed nodegroup restricted by the kubelet", func() {
+			mng := api.NewManagedNodeGroup()
+			mng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			mng.Taints = []api.NodeGroupTaint{
+				{Key: "node-role.kubernetes.io/worker", Effect: "NoSchedule"},
+				{Key: "node.kubernetes.io/gpu", Effect: "NoSchedule"},
+				{Key: "team", Value: "a", Effect: "NoSchedule"},
+			}
+			Expect(api.ValidateManagedNodeGroup(0, mng)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring(`taint "node-role.kubernetes.io/worker" of managedNodeGroups[0] is in a 'kubernetes.io' or 'k8s.io' namespace restricted by the kubelet`))
+			Expect(output.String()).NotTo(ContainSubstring(`"node.kubernetes.io/gpu"`))
+			Expect(output.String()).NotTo(ContainSubstring(`"team"`))
+		})
+
+		It("warns about each taint of an unmanaged nodegroup restricted by the kubelet", func() {
+			ng := newNodeGroup()
+			ng.Taints = []api.NodeGroupTaint{
+				{Key: "example.k8s.io/tier", Value: "gpu", Effect: "NoExecute"},
+				{Key: "team", Value: "a", Effect: "NoSchedule"},
+			}
+			Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+			Expect(output.String()).To(ContainSubstring(`taint "example.k8s.io/tier" of nodeGroups[0] is in a 'kubernetes.io' or 'k8s.io' namespace restricted by the kubelet`))
+			Expect(output.String()).NotTo(ContainSubstring(`"team"`))
+		})
+
+		It("warns about each label of an unmanaged nodegroup restricted by the kubelet", func() {
+			ng := newNodeGroup()
+			ng.Labels = map[string]string{
+				"node-role.kubernetes.io/worker": "",
+				"team":                           "a",
+			}
+			Expect(api.ValidateNodeGroup(0, ng)).To(MatchError("unknown 'kubernetes.io' or 'k8s.io' labels were specified: [node-role.kubernetes.io/worker]"))
+			Expect(output.String()).To(ContainSubstring(`label "node-role.kubernetes.io/worker" of nodeGroups[0] is in a 'kubernetes.io' or 'k8s.io' namespace restricted by the kubelet`))
+			Expect(output.String()).NotTo(ContainSubstring(`"team"`))
+		})
+	})
+
 	Describe("Availability Zones", func() {
 		When("the config file does not specify any AZ", func() {
 			It("skips validation", func() {
@@ -1845,6 +3250,127 @@ var _ = Describe("ClusterConfig validation", func() {
 				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("only 1 zone(s) specified [az-1], 2 are required (can be non-unique)"))
 			})
 		})
+
+		When("the config file contains Local Zones", func() {
+			var cfg *api.ClusterConfig
+
+			BeforeEach(func() {
+				cfg = api.NewClusterConfig()
+				cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+				cfg.LocalZones = []string{"us-west-2-lax-1a"}
+			})
+
+			It("does not return an error", func() {
+				Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+			})
+
+			It("returns an error when a zone is also listed in availabilityZones", func() {
+				cfg.AvailabilityZones = append(cfg.AvailabilityZones, "us-west-2-lax-1a")
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError(`zone "us-west-2-lax-1a" cannot be listed in both availabilityZones and localZones`))
+			})
+		})
+
+		When("the config file sets the number of availability zones", func() {
+			var cfg *api.ClusterConfig
+
+			BeforeEach(func() {
+				cfg = api.NewClusterConfig()
+				cfg.AvailabilityZoneCount = aws.Int(4)
+			})
+
+			It("does not return an error", func() {
+				Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+			})
+
+			It("returns an error when fewer than 2 zones are requested", func() {
+				cfg.AvailabilityZoneCount = aws.Int(1)
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("availabilityZoneCount must be at least 2, got 1"))
+			})
+
+			It("returns an error when the zones would need more than 16 subnets", func() {
+				cfg.AvailabilityZoneCount = aws.Int(7)
+				cfg.LocalZones = []string{"us-west-2-lax-1a", "us-west-2-lax-1b"}
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("availabilityZoneCount and the number of localZones cannot add up to more than 8 zones, got 9"))
+			})
+
+			It("returns an error when the availability zones are also listed", func() {
+				cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("availabilityZoneCount cannot be set along with availabilityZones"))
+			})
+		})
+
+		When("the config file sets the IDs of the availability zones", func() {
+			var cfg *api.ClusterConfig
+
+			BeforeEach(func() {
+				cfg = api.NewClusterConfig()
+				cfg.AvailabilityZoneIDs = []string{"usw2-az1", "usw2-az2"}
+			})
+
+			It("does not return an error", func() {
+				Expect(api.ValidateClusterConfig(cfg)).To(Succeed())
+			})
+
+			It("returns an error when fewer than 2 zones are listed", func() {
+				cfg.AvailabilityZoneIDs = []string{"usw2-az1"}
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("only 1 zone(s) specified [usw2-az1], 2 are required (can be non-unique)"))
+			})
+
+			It("returns an error when the zone names are also listed", func() {
+				cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("only one of availabilityZones or availabilityZoneIDs should be set"))
+			})
+
+			It("returns an error when a zone name is listed among the IDs", func() {
+				cfg.AvailabilityZoneIDs = []string{"usw2-az1", "us-west-2b"}
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError(`availabilityZoneIDs must only contain zone IDs (e.g. use1-az1), got "us-west-2b"`))
+			})
+
+			It("returns an error when a zone ID is listed among the names", func() {
+				cfg.AvailabilityZoneIDs = nil
+				cfg.AvailabilityZones = []string{"us-west-2a", "usw2-az2"}
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError(`availabilityZones must only contain zone names, but "usw2-az2" is a zone ID; zone IDs should be set in availabilityZoneIDs`))
+			})
+
+			It("returns an error when the number of zones is also set", func() {
+				cfg.AvailabilityZoneCount = aws.Int(2)
+				Expect(api.ValidateClusterConfig(cfg)).To(MatchError("availabilityZoneCount cannot be set along with availabilityZoneIDs"))
+			})
+		})
+
+		When("a nodegroup sets the IDs of its availability zones", func() {
+			var ng *api.NodeGroup
+
+			BeforeEach(func() {
+				ng = api.NewNodeGroup()
+				ng.AvailabilityZoneIDs = []string{"usw2-az1"}
+			})
+
+			It("does not return an error", func() {
+				Expect(api.ValidateNodeGroup(0, ng)).To(Succeed())
+			})
+
+			It("returns an error when the zone names are also listed", func() {
+				ng.AvailabilityZones = []string{"us-west-2a"}
+				Expect(api.ValidateNodeGroup(0, ng)).To(MatchError("only one of nodeGroups[0].availabilityZones or nodeGroups[0].availabilityZoneIDs should be set"))
+			})
+
+			It("returns an error when subnets are also listed", func() {
+				ng.Subnets = []string{"subnet-1"}
+				Expect(api.ValidateNodeGroup(0, ng)).To(MatchError("only one of nodeGroups[0].subnets or nodeGroups[0].availabilityZoneIDs should be set"))
+			})
+
+			It("returns an error when names and IDs are mixed", func() {
+				ng.AvailabilityZoneIDs = []string{"usw2-az1", "us-west-2b"}
+				Expect(api.ValidateNodeGroup(0, ng)).To(MatchError(`nodeGroups[0].availabilityZoneIDs must only contain zone IDs (e.g. use1-az1), got "us-west-2b"`))
+			})
+
+			It("returns an error when EFA is enabled with more than one zone", func() {
+				ng.AvailabilityZoneIDs = []string{"usw2-az1", "usw2-az2"}
+				ng.EFAEnabled = aws.Bool(true)
+				Expect(api.ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].efaEnabled nodegroups must have only one subnet or one availability zone"))
+			})
+		})
 	})
 
 	Describe("Validate SecretsEncryption", func() {
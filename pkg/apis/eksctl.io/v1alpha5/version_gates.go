@@ -0,0 +1,92 @@
+package v1alpha5
+
+import (
+	"fmt"
+
+	"github.com/weaveworks/eksctl/pkg/utils"
+)
+
+// versionGate is the minimum EKS version supporting a feature
+type versionGate struct {
+	// feature is the name of the feature used in errors
+	feature    string
+	minVersion string
+}
+
+// check returns an error if version is lower than the minimum version of the gate
+func (g versionGate) check(version string) error {
+	supported, err := utils.IsMinVersion(g.minVersion, version)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fmt.Errorf("%s requires EKS version %s and above", g.feature, g.minVersion)
+	}
+	return nil
+}
+
+// amiFamilyVersionGates holds the AMI families that are not available for all supported EKS versions
+var amiFamilyVersionGates = map[string]versionGate{
+	NodeImageFamilyWindowsServer20H2CoreContainer: {
+		feature:    "Windows Server 20H2 Core",
+		minVersion: Version1_21,
+	},
+	NodeImageFamilyAmazonLinux2023: {
+		feature:    "Amazon Linux 2023",
+		minVersion: Version1_23,
+	},
+	NodeImageFamilyWindowsServer2022CoreContainer: {
+		feature:    "Windows Server 2022 Core",
+		minVersion: Version1_23,
+	},
+	NodeImageFamilyWindowsServer2022FullContainer: {
+		feature:    "Windows Server 2022 Full",
+		minVersion: Version1_23,
+	},
+}
+
+// minimumVersionForIPv6 is the minimum EKS version supporting the IPv6 IP family
+const minimumVersionForIPv6 = Version1_21
+
+// ValidateAMIFamilyVersion returns an error if amiFamily is not supported by the EKS version, either because it is not
+// available yet or because its AMIs are no longer published
+func ValidateAMIFamilyVersion(amiFamily, version string) error {
+	if gate, ok := amiFamilyVersionGates[amiFamily]; ok {
+		if err := gate.check(version); err != nil {
+			return err
+		}
+	}
+	_, err := AMIFamilyDeprecation(amiFamily, version)
+	return err
+}
+
+// validateVersionGates checks that the features used by the nodegroups are supported by the EKS version of the
+// cluster. It is skipped when the version is not known yet
+func validateVersionGates(cfg *ClusterConfig) error {
+	version := cfg.Metadata.Version
+	if !isResolvedVersion(version) {
+		return nil
+	}
+
+	validateAMIFamily := func(ng *NodeGroupBase, path string) error {
+		if ng.AMIFamily == "" {
+			return nil
+		}
+		if err := ValidateAMIFamilyVersion(ng.AMIFamily, version); err != nil {
+			return fmt.Errorf("%s.amiFamily: %w", path, err)
+		}
+		return nil
+	}
+
+	for i, ng := range cfg.NodeGroups {
+		if err := validateAMIFamily(ng.NodeGroupBase, fmt.Sprintf("nodeGroups[%d]", i)); err != nil {
+			return err
+		}
+	}
+	for i, ng := range cfg.ManagedNodeGroups {
+		if err := validateAMIFamily(ng.NodeGroupBase, fmt.Sprintf("managedNodeGroups[%d]", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -27,6 +27,10 @@ const (
 	ClusterNATDefault = ClusterSingleNAT
 )
 
+// SharedNodeSecurityGroupDisabled is the value of `vpc.sharedNodeSecurityGroup` that disables the creation of the
+// shared node security group
+const SharedNodeSecurityGroupDisabled = "disabled"
+
 // AZSubnetMapping holds subnet to AZ mappings.
 // If the key is an AZ, that also becomes the name of the subnet
 // otherwise use the key to refer to this subnet.
@@ -143,7 +147,8 @@ type (
 		// private subnets or any ad-hoc subnets
 		// +optional
 		ExtraIPv6CIDRs []string `json:"extraIPv6CIDRs,omitempty"`
-		// for pre-defined shared node SG
+		// for pre-defined shared node SG, or `disabled` to not create the shared
+		// node SG for clusters that only run managed nodegroups
 		SharedNodeSecurityGroup string `json:"sharedNodeSecurityGroup,omitempty"`
 		// Automatically add security group rules to and from the default
 		// cluster security group and the shared node security group.
@@ -153,6 +158,13 @@ type (
 		// Defaults to `true`
 		// +optional
 		ManageSharedNodeSecurityGroupRules *bool `json:"manageSharedNodeSecurityGroupRules,omitempty"`
+		// Automatically add security group rules to the control plane security group
+		// allowing communication with unmanaged nodegroups. This option can only be
+		// disabled when the control plane security group is not created by eksctl
+		// (`securityGroup`), which must then allow this traffic.
+		// Defaults to `true`
+		// +optional
+		ManageControlPlaneSecurityGroupRules *bool `json:"manageControlPlaneSecurityGroupRules,omitempty"`
 		// AutoAllocateIPV6 requests an IPv6 CIDR block with /56 prefix for the VPC
 		// +optional
 		AutoAllocateIPv6 *bool `json:"autoAllocateIPv6,omitempty"`
@@ -165,6 +177,18 @@ type (
 		// k8s API endpoint
 		// +optional
 		PublicAccessCIDRs []string `json:"publicAccessCIDRs,omitempty"`
+		// SubnetTags are applied to all subnets created by eksctl, e.g.
+		// `karpenter.sh/discovery` for Karpenter to discover them
+		// +optional
+		SubnetTags map[string]string `json:"subnetTags,omitempty"`
+		// PublicSubnetTags are applied to the public subnets created by eksctl,
+		// and take precedence over `subnetTags`
+		// +optional
+		PublicSubnetTags map[string]string `json:"publicSubnetTags,omitempty"`
+		// PrivateSubnetTags are applied to the private subnets created by eksctl,
+		// and take precedence over `subnetTags`
+		// +optional
+		PrivateSubnetTags map[string]string `json:"privateSubnetTags,omitempty"`
 	}
 	// ClusterSubnets holds private and public subnets
 	ClusterSubnets struct {
@@ -229,6 +253,27 @@ func SubnetTopologies() []SubnetTopology {
 	}
 }
 
+// TagsOfSubnets returns the tags to apply to the subnets of topology created by eksctl, merging subnetTags with the
+// tags of the topology, which take precedence
+func (c *ClusterVPC) TagsOfSubnets(topology SubnetTopology) map[string]string {
+	topologyTags := c.PrivateSubnetTags
+	if topology == SubnetTopologyPublic {
+		topologyTags = c.PublicSubnetTags
+	}
+	if len(c.SubnetTags) == 0 && len(topologyTags) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string, len(c.SubnetTags)+len(topologyTags))
+	for k, v := range c.SubnetTags {
+		tags[k] = v
+	}
+	for k, v := range topologyTags {
+		tags[k] = v
+	}
+	return tags
+}
+
 // DefaultCIDR returns default global CIDR for VPC
 func DefaultCIDR() ipnet.IPNet {
 	return ipnet.IPNet{
@@ -97,6 +97,58 @@ func (in *Addon) DeepCopy() *Addon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservation) DeepCopyInto(out *CapacityReservation) {
+	*out = *in
+	if in.CapacityReservationPreference != nil {
+		in, out := &in.CapacityReservationPreference, &out.CapacityReservationPreference
+		*out = new(string)
+		**out = **in
+	}
+	if in.CapacityReservationTarget != nil {
+		in, out := &in.CapacityReservationTarget, &out.CapacityReservationTarget
+		*out = new(CapacityReservationTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservation.
+func (in *CapacityReservation) DeepCopy() *CapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationTarget) DeepCopyInto(out *CapacityReservationTarget) {
+	*out = *in
+	if in.CapacityReservationID != nil {
+		in, out := &in.CapacityReservationID, &out.CapacityReservationID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CapacityReservationResourceGroupARN != nil {
+		in, out := &in.CapacityReservationResourceGroupARN, &out.CapacityReservationResourceGroupARN
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationTarget.
+func (in *CapacityReservationTarget) DeepCopy() *CapacityReservationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCloudWatch) DeepCopyInto(out *ClusterCloudWatch) {
 	*out = *in
@@ -186,6 +238,11 @@ func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 		*out = new(PrivateCluster)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PrivateDNSNameOptions != nil {
+		in, out := &in.PrivateDNSNameOptions, &out.PrivateDNSNameOptions
+		*out = new(PrivateDNSNameOptions)
+		**out = **in
+	}
 	if in.NodeGroups != nil {
 		in, out := &in.NodeGroups, &out.NodeGroups
 		*out = make([]*NodeGroup, len(*in))
@@ -208,6 +265,16 @@ func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 			}
 		}
 	}
+	if in.NodeGroupDefaults != nil {
+		in, out := &in.NodeGroupDefaults, &out.NodeGroupDefaults
+		*out = new(NodeGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedNodeGroupDefaults != nil {
+		in, out := &in.ManagedNodeGroupDefaults, &out.ManagedNodeGroupDefaults
+		*out = new(ManagedNodeGroup)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FargateProfiles != nil {
 		in, out := &in.FargateProfiles, &out.FargateProfiles
 		*out = make([]*FargateProfile, len(*in))
@@ -224,6 +291,26 @@ func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AvailabilityZoneIDs != nil {
+		in, out := &in.AvailabilityZoneIDs, &out.AvailabilityZoneIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailabilityZoneCount != nil {
+		in, out := &in.AvailabilityZoneCount, &out.AvailabilityZoneCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.DeniedAvailabilityZoneIDs != nil {
+		in, out := &in.DeniedAvailabilityZoneIDs, &out.DeniedAvailabilityZoneIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalZones != nil {
+		in, out := &in.LocalZones, &out.LocalZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.CloudWatch != nil {
 		in, out := &in.CloudWatch, &out.CloudWatch
 		*out = new(ClusterCloudWatch)
@@ -609,6 +696,11 @@ func (in *ClusterVPC) DeepCopyInto(out *ClusterVPC) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ManageControlPlaneSecurityGroupRules != nil {
+		in, out := &in.ManageControlPlaneSecurityGroupRules, &out.ManageControlPlaneSecurityGroupRules
+		*out = new(bool)
+		**out = **in
+	}
 	if in.AutoAllocateIPv6 != nil {
 		in, out := &in.AutoAllocateIPv6, &out.AutoAllocateIPv6
 		*out = new(bool)
@@ -629,6 +721,27 @@ func (in *ClusterVPC) DeepCopyInto(out *ClusterVPC) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SubnetTags != nil {
+		in, out := &in.SubnetTags, &out.SubnetTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PublicSubnetTags != nil {
+		in, out := &in.PublicSubnetTags, &out.PublicSubnetTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PrivateSubnetTags != nil {
+		in, out := &in.PrivateSubnetTags, &out.PrivateSubnetTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -795,6 +908,68 @@ func (in InlineDocument) DeepCopyInto(out *InlineDocument) {
 	}
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRequirements) DeepCopyInto(out *InstanceRequirements) {
+	*out = *in
+	if in.VCPUCount != nil {
+		in, out := &in.VCPUCount, &out.VCPUCount
+		*out = new(InstanceRequirementsRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryMiB != nil {
+		in, out := &in.MemoryMiB, &out.MemoryMiB
+		*out = new(InstanceRequirementsRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUManufacturers != nil {
+		in, out := &in.CPUManufacturers, &out.CPUManufacturers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedInstanceTypes != nil {
+		in, out := &in.ExcludedInstanceTypes, &out.ExcludedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRequirements.
+func (in *InstanceRequirements) DeepCopy() *InstanceRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRequirementsRange) DeepCopyInto(out *InstanceRequirementsRange) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(int)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRequirementsRange.
+func (in *InstanceRequirementsRange) DeepCopy() *InstanceRequirementsRange {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRequirementsRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceSelector) DeepCopyInto(out *InstanceSelector) {
 	*out = *in
@@ -816,6 +991,53 @@ func (in *InstanceSelector) DeepCopy() *InstanceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTypeOverride) DeepCopyInto(out *InstanceTypeOverride) {
+	*out = *in
+	if in.WeightedCapacity != nil {
+		in, out := &in.WeightedCapacity, &out.WeightedCapacity
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTypeOverride.
+func (in *InstanceTypeOverride) DeepCopy() *InstanceTypeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTypeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstancesDistributionCapacityReservation) DeepCopyInto(out *InstancesDistributionCapacityReservation) {
+	*out = *in
+	if in.CapacityReservationIDs != nil {
+		in, out := &in.CapacityReservationIDs, &out.CapacityReservationIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CapacityReservationResourceGroupARNs != nil {
+		in, out := &in.CapacityReservationResourceGroupARNs, &out.CapacityReservationResourceGroupARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancesDistributionCapacityReservation.
+func (in *InstancesDistributionCapacityReservation) DeepCopy() *InstancesDistributionCapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(InstancesDistributionCapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Karpenter) DeepCopyInto(out *Karpenter) {
 	*out = *in
@@ -1025,11 +1247,31 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DisableDefaultInstanceTags != nil {
+		in, out := &in.DisableDefaultInstanceTags, &out.DisableDefaultInstanceTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaxInstanceLifetime != nil {
 		in, out := &in.MaxInstanceLifetime, &out.MaxInstanceLifetime
 		*out = new(int)
 		**out = **in
 	}
+	if in.ASGContext != nil {
+		in, out := &in.ASGContext, &out.ASGContext
+		*out = new(string)
+		**out = **in
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(WarmPool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LaunchTemplate != nil {
+		in, out := &in.LaunchTemplate, &out.LaunchTemplate
+		*out = new(LaunchTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1051,6 +1293,11 @@ func (in *NodeGroupBase) DeepCopyInto(out *NodeGroupBase) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AvailabilityZoneIDs != nil {
+		in, out := &in.AvailabilityZoneIDs, &out.AvailabilityZoneIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = make([]string, len(*in))
@@ -1078,6 +1325,11 @@ func (in *NodeGroupBase) DeepCopyInto(out *NodeGroupBase) {
 			(*out)[key] = val
 		}
 	}
+	if in.AssociatePublicIPAddress != nil {
+		in, out := &in.AssociatePublicIPAddress, &out.AssociatePublicIPAddress
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -1166,10 +1418,20 @@ func (in *NodeGroupBase) DeepCopyInto(out *NodeGroupBase) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HTTPPutResponseHopLimit != nil {
+		in, out := &in.HTTPPutResponseHopLimit, &out.HTTPPutResponseHopLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.DisableLaunchTemplateMetadataOptions != nil {
+		in, out := &in.DisableLaunchTemplateMetadataOptions, &out.DisableLaunchTemplateMetadataOptions
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Placement != nil {
 		in, out := &in.Placement, &out.Placement
 		*out = new(Placement)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.EFAEnabled != nil {
 		in, out := &in.EFAEnabled, &out.EFAEnabled
@@ -1191,6 +1453,38 @@ func (in *NodeGroupBase) DeepCopyInto(out *NodeGroupBase) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DisableRollback != nil {
+		in, out := &in.DisableRollback, &out.DisableRollback
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LaunchTemplateTagSpecifications != nil {
+		in, out := &in.LaunchTemplateTagSpecifications, &out.LaunchTemplateTagSpecifications
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(CapacityReservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateDNSNameOptions != nil {
+		in, out := &in.PrivateDNSNameOptions, &out.PrivateDNSNameOptions
+		*out = new(PrivateDNSNameOptions)
+		**out = **in
+	}
 	return
 }
 
@@ -1341,6 +1635,18 @@ func (in *NodeGroupInstancesDistribution) DeepCopyInto(out *NodeGroupInstancesDi
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstanceTypeOverrides != nil {
+		in, out := &in.InstanceTypeOverrides, &out.InstanceTypeOverrides
+		*out = make([]InstanceTypeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstanceRequirements != nil {
+		in, out := &in.InstanceRequirements, &out.InstanceRequirements
+		*out = new(InstanceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MaxPrice != nil {
 		in, out := &in.MaxPrice, &out.MaxPrice
 		*out = new(float64)
@@ -1356,6 +1662,11 @@ func (in *NodeGroupInstancesDistribution) DeepCopyInto(out *NodeGroupInstancesDi
 		*out = new(int)
 		**out = **in
 	}
+	if in.OnDemandAllocationStrategy != nil {
+		in, out := &in.OnDemandAllocationStrategy, &out.OnDemandAllocationStrategy
+		*out = new(string)
+		**out = **in
+	}
 	if in.SpotInstancePools != nil {
 		in, out := &in.SpotInstancePools, &out.SpotInstancePools
 		*out = new(int)
@@ -1366,6 +1677,11 @@ func (in *NodeGroupInstancesDistribution) DeepCopyInto(out *NodeGroupInstancesDi
 		*out = new(string)
 		**out = **in
 	}
+	if in.CapacityReservation != nil {
+		in, out := &in.CapacityReservation, &out.CapacityReservation
+		*out = new(InstancesDistributionCapacityReservation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1397,6 +1713,16 @@ func (in *NodeGroupSGs) DeepCopyInto(out *NodeGroupSGs) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RestrictControlPlaneRules != nil {
+		in, out := &in.RestrictControlPlaneRules, &out.RestrictControlPlaneRules
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdditionalControlPlanePorts != nil {
+		in, out := &in.AdditionalControlPlanePorts, &out.AdditionalControlPlanePorts
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1433,6 +1759,16 @@ func (in *NodeGroupSSH) DeepCopyInto(out *NodeGroupSSH) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PublicKeyNames != nil {
+		in, out := &in.PublicKeyNames, &out.PublicKeyNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalPublicKeys != nil {
+		in, out := &in.AdditionalPublicKeys, &out.AdditionalPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.SourceSecurityGroupIDs != nil {
 		in, out := &in.SourceSecurityGroupIDs, &out.SourceSecurityGroupIDs
 		*out = make([]string, len(*in))
@@ -1531,6 +1867,11 @@ func (in *OIDCIdentityProvider) DeepCopy() *OIDCIdentityProvider {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Placement) DeepCopyInto(out *Placement) {
 	*out = *in
+	if in.PartitionNumber != nil {
+		in, out := &in.PartitionNumber, &out.PartitionNumber
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -1565,6 +1906,22 @@ func (in *PrivateCluster) DeepCopy() *PrivateCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateDNSNameOptions) DeepCopyInto(out *PrivateDNSNameOptions) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateDNSNameOptions.
+func (in *PrivateDNSNameOptions) DeepCopy() *PrivateDNSNameOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateDNSNameOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -1671,6 +2028,11 @@ func (in *VolumeMapping) DeepCopyInto(out *VolumeMapping) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.VolumeInitializationRate != nil {
+		in, out := &in.VolumeInitializationRate, &out.VolumeInitializationRate
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -1684,6 +2046,37 @@ func (in *VolumeMapping) DeepCopy() *VolumeMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WarmPool) DeepCopyInto(out *WarmPool) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxPreparedCapacity != nil {
+		in, out := &in.MaxPreparedCapacity, &out.MaxPreparedCapacity
+		*out = new(int)
+		**out = **in
+	}
+	if in.ReuseOnScaleIn != nil {
+		in, out := &in.ReuseOnScaleIn, &out.ReuseOnScaleIn
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmPool.
+func (in *WarmPool) DeepCopy() *WarmPool {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WellKnownPolicies) DeepCopyInto(out *WellKnownPolicies) {
 	*out = *in
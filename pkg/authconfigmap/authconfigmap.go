@@ -282,8 +282,13 @@ func ObjectMeta() metav1.ObjectMeta {
 }
 
 // AddNodeGroup creates or adds a nodegroup IAM role in the auth
-// ConfigMap for the given nodegroup.
+// ConfigMap for the given nodegroup. The role is read from ng.IAM.InstanceRoleARN,
+// which is populated from the outputs of the nodegroup stack.
 func AddNodeGroup(clientSet kubernetes.Interface, ng *api.NodeGroup) error {
+	if ng.IAM == nil || ng.IAM.InstanceRoleARN == "" {
+		return fmt.Errorf("instance role ARN of nodegroup %q is not set", ng.Name)
+	}
+
 	acm, err := NewFromClientSet(clientSet)
 	if err != nil {
 		return err
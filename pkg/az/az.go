@@ -4,60 +4,377 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kris-nova/logger"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
 	"github.com/weaveworks/eksctl/pkg/utils/strings"
 )
 
+// Types of zones, as reported by EC2
+const (
+	ZoneTypeAvailabilityZone = "availability-zone"
+	ZoneTypeLocalZone        = "local-zone"
+	ZoneTypeWavelengthZone   = "wavelength-zone"
+)
+
+// Zone is an available zone of a region
+type Zone struct {
+	Name string
+	ID   string
+}
+
 var zoneIDsToAvoid = map[string][]string{
 	api.RegionCNNorth1: {"cnn1-az4"}, // https://github.com/weaveworks/eksctl/issues/3916
 }
 
-func GetAvailabilityZones(ctx context.Context, ec2API awsapi.EC2, region string) ([]string, error) {
+// availabilityZoneNamePattern matches the names of availability zones, e.g. us-west-2a, but not those of Local Zones,
+// e.g. us-west-2-lax-1a, or Wavelength Zones, e.g. us-east-1-wl1-bos-wlz-1
+var availabilityZoneNamePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+[a-z]$`)
+
+// Option customizes the zones returned by GetAvailabilityZones
+type Option func(*options)
+
+type options struct {
+	localZones    []string
+	instanceTypes []string
+	count         int
+	zoneIDs       []string
+}
+
+// WithLocalZones makes GetAvailabilityZones return zones, which must be available Local Zones or Wavelength Zones of
+// the region, after the availability zones it selects
+func WithLocalZones(zones []string) Option {
+	return func(o *options) {
+		o.localZones = zones
+	}
+}
+
+// WithInstanceTypes makes GetAvailabilityZones only select availability zones that offer all instanceTypes. All zones
+// are selected from, with a warning, when fewer than the required number of zones offer them
+func WithInstanceTypes(instanceTypes []string) Option {
+	return func(o *options) {
+		o.instanceTypes = instanceTypes
+	}
+}
+
+// WithCount makes GetAvailabilityZones select up to count availability zones, instead of the recommended number of
+// zones for the region. All zones are selected when fewer than count zones can be selected
+func WithCount(count int) Option {
+	return func(o *options) {
+		o.count = count
+	}
+}
+
+// WithZoneIDs makes GetAvailabilityZones return the names of the availability zones with zoneIDs, in the same order,
+// instead of selecting zones. An error is returned if any of zoneIDs is not an available zone of the region, or is
+// denied
+func WithZoneIDs(zoneIDs []string) Option {
+	return func(o *options) {
+		o.zoneIDs = zoneIDs
+	}
+}
+
+// GetAvailabilityZones selects the availability zones of the region for a cluster. The zones whose ID is in
+// deniedZoneIDs are never selected, along with the zones that are known to be capacity-constrained
+func GetAvailabilityZones(ctx context.Context, ec2API awsapi.EC2, region string, deniedZoneIDs []string, opts ...Option) ([]string, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	azs, err := describeZones(ctx, ec2API, region, ZoneTypeAvailabilityZone)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []string
+	if len(o.zoneIDs) > 0 {
+		if zones, err = zonesWithIDs(region, filterZones(region, azs, deniedZoneIDs...), o.zoneIDs); err != nil {
+			return nil, err
+		}
+	} else {
+		candidates := zoneNames(filterZones(region, azs, deniedZoneIDs...))
+		if len(o.instanceTypes) > 0 {
+			if candidates, err = zonesOfferingInstanceTypes(ctx, ec2API, candidates, o.instanceTypes, o.count); err != nil {
+				return nil, err
+			}
+		}
+
+		if zones, err = selectZones(region, candidates, o.count); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.localZones) == 0 {
+		return zones, nil
+	}
+	if err := CheckLocalZones(ctx, ec2API, region, o.localZones); err != nil {
+		return nil, err
+	}
+	return append(zones, o.localZones...), nil
+}
+
+// GetLocalZones returns the available Local Zones and Wavelength Zones of the region. Unlike availability zones,
+// they are never selected for a cluster, and are only used when listed in its config
+func GetLocalZones(ctx context.Context, ec2API awsapi.EC2, region string) ([]Zone, error) {
+	zones, err := describeZones(ctx, ec2API, region, ZoneTypeLocalZone, ZoneTypeWavelengthZone)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterZones(region, zones), nil
+}
+
+// CheckLocalZones returns an error if any of zones is not an available Local Zone or Wavelength Zone of the region
+func CheckLocalZones(ctx context.Context, ec2API awsapi.EC2, region string, zones []string) error {
+	localZones, err := GetLocalZones(ctx, ec2API, region)
+	if err != nil {
+		return err
+	}
+
+	available := zoneNames(localZones)
+	for _, zone := range zones {
+		if !strings.Contains(available, zone) {
+			return fmt.Errorf("zone %q is not an available Local Zone or Wavelength Zone of region %s, available zones are %v", zone, region, available)
+		}
+	}
+	return nil
+}
+
+// IsAvailabilityZoneName returns true if name is named like an availability zone, as opposed to a Local Zone or a
+// Wavelength Zone, whose names have the location of the zone after the region
+func IsAvailabilityZoneName(name string) bool {
+	return availabilityZoneNamePattern.MatchString(name)
+}
+
+// GetAvailabilityZonesFromList selects the zones like GetAvailabilityZones, but only among the available zones of
+// the region that are in allowed. All available zones are considered when allowed is empty
+func GetAvailabilityZonesFromList(ctx context.Context, ec2API awsapi.EC2, region string, allowed []string) ([]string, error) {
 	zones, err := getZones(ctx, ec2API, region)
 	if err != nil {
 		return nil, err
 	}
+	if len(allowed) == 0 {
+		return selectZones(region, zones, 0)
+	}
 
-	numberOfZones := len(zones)
-	if numberOfZones < api.MinRequiredAvailabilityZones {
-		return nil, fmt.Errorf("only %d zones discovered %v, at least %d are required", numberOfZones, zones, api.MinRequiredAvailabilityZones)
+	var allowedZones []string
+	for _, z := range zones {
+		if strings.Contains(allowed, z) {
+			allowedZones = append(allowedZones, z)
+		}
 	}
+	if len(allowedZones) == 0 {
+		return nil, fmt.Errorf("none of the zones %v are available in region %s, available zones are %v", allowed, region, zones)
+	}
+	return selectZones(region, allowedZones, 0)
+}
 
-	if numberOfZones < api.RecommendedAvailabilityZones {
-		return zones, nil
+// GetAvailabilityZonesWithCount returns up to count random unique zones of the region. At least 2 zones must be
+// available, unless a single zone is requested. Unlike GetAvailabilityZones, count is also honored in us-east-1
+func GetAvailabilityZonesWithCount(ctx context.Context, ec2API awsapi.EC2, region string, count int) ([]string, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("the number of zones to select must be at least 1, got %d", count)
 	}
 
-	return randomSelectionOfZones(region, zones), nil
+	zones, err := getZones(ctx, ec2API, region)
+	if err != nil {
+		return nil, err
+	}
+	return selectZones(region, zones, count)
 }
 
-func randomSelectionOfZones(region string, availableZones []string) []string {
-	var zones []string
+// GetZonesOfType returns all available zones of the region of the given type. At least 2 zones must be available
+// for availability zones, while Local Zones and Wavelength Zones only require one zone
+func GetZonesOfType(ctx context.Context, ec2API awsapi.EC2, region, zoneType string) ([]Zone, error) {
+	minRequiredZones := 1
+	switch zoneType {
+	case ZoneTypeAvailabilityZone:
+		minRequiredZones = api.MinRequiredAvailabilityZones
+	case ZoneTypeLocalZone, ZoneTypeWavelengthZone:
+	default:
+		return nil, fmt.Errorf("invalid zone type %q, valid zone types are %q, %q and %q", zoneType, ZoneTypeAvailabilityZone, ZoneTypeLocalZone, ZoneTypeWavelengthZone)
+	}
+
+	azs, err := describeZones(ctx, ec2API, region, zoneType)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := filterZones(region, azs)
+	if err := checkNumberOfZones(zoneNames(zones), minRequiredZones); err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+// GetAvailabilityZoneIDs selects the zones like GetAvailabilityZones, returning the IDs of the zones along with their
+// names. Unlike zone names, which are mapped to zones independently for each account, zone IDs are the same in all
+// accounts
+func GetAvailabilityZoneIDs(ctx context.Context, ec2API awsapi.EC2, region string) ([]Zone, error) {
+	azs, err := describeZones(ctx, ec2API, region, ZoneTypeAvailabilityZone)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := filterZones(region, azs)
+	selectedNames, err := selectZones(region, zoneNames(zones), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneIDs := make(map[string]string, len(zones))
+	for _, z := range zones {
+		zoneIDs[z.Name] = z.ID
+	}
+	selectedZones := make([]Zone, 0, len(selectedNames))
+	for _, name := range selectedNames {
+		selectedZones = append(selectedZones, Zone{Name: name, ID: zoneIDs[name]})
+	}
+	return selectedZones, nil
+}
+
+// zonesWithIDs returns the names of the zones with zoneIDs, in the same order
+func zonesWithIDs(region string, zones []Zone, zoneIDs []string) ([]string, error) {
+	zoneNamesByID := make(map[string]string, len(zones))
+	var availableIDs []string
+	for _, z := range zones {
+		zoneNamesByID[z.ID] = z.Name
+		availableIDs = append(availableIDs, z.ID)
+	}
+
+	names := make([]string, 0, len(zoneIDs))
+	for _, id := range zoneIDs {
+		name, ok := zoneNamesByID[id]
+		if !ok {
+			return nil, fmt.Errorf("zone ID %q is not an available availability zone of region %s, available zone IDs are %v", id, region, availableIDs)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// zonesOfferingInstanceTypes returns the zones that offer all instanceTypes, or all zones if fewer than the required
+// number of zones, or than count if set, offer them
+func zonesOfferingInstanceTypes(ctx context.Context, ec2API awsapi.EC2, zones, instanceTypes []string, count int) ([]string, error) {
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: ec2types.LocationTypeAvailabilityZone,
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: instanceTypes,
+			},
+		},
+	}
+
+	offerings := map[string]sets.String{}
+	paginator := ec2.NewDescribeInstanceTypeOfferingsPaginator(ec2API, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting instance type offerings for %v: %w", instanceTypes, err)
+		}
+		for _, offering := range output.InstanceTypeOfferings {
+			zone := aws.StringValue(offering.Location)
+			if offerings[zone] == nil {
+				offerings[zone] = sets.NewString()
+			}
+			offerings[zone].Insert(string(offering.InstanceType))
+		}
+	}
+
+	var (
+		offeringZones []string
+		missing       []string
+	)
+	for _, zone := range zones {
+		offered := offerings[zone]
+		if offered.HasAll(instanceTypes...) {
+			offeringZones = append(offeringZones, zone)
+			continue
+		}
+		for _, instanceType := range instanceTypes {
+			if !offered.Has(instanceType) {
+				missing = append(missing, fmt.Sprintf("%s/%s", zone, instanceType))
+			}
+		}
+	}
+
+	minRequiredZones := api.MinRequiredAvailabilityZones
+	if count > minRequiredZones {
+		minRequiredZones = count
+	}
+	if len(offeringZones) < minRequiredZones {
+		logger.Warning("only %d zone(s) offer all of the instance types %v, selecting among all zones; zone/instance type pairs that are not offered are %v",
+			len(offeringZones), instanceTypes, missing)
+		return zones, nil
+	}
+	return offeringZones, nil
+}
+
+// selectZones returns up to count random zones of zones, or the recommended number of zones for the region if count
+// is 0. At least 2 zones are required, unless a single zone is requested
+func selectZones(region string, zones []string, count int) ([]string, error) {
+	minRequiredZones := api.MinRequiredAvailabilityZones
+	if count == 1 {
+		minRequiredZones = 1
+	}
+	if err := checkNumberOfZones(zones, minRequiredZones); err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		if count > len(zones) {
+			count = len(zones)
+		}
+		return randomSelectionOfZones(zones, count), nil
+	}
+
+	if len(zones) < api.RecommendedAvailabilityZones {
+		return zones, nil
+	}
+
 	desiredNumberOfAZs := api.RecommendedAvailabilityZones
 	if region == api.RegionUSEast1 {
 		desiredNumberOfAZs = api.MinRequiredAvailabilityZones
 	}
+	return randomSelectionOfZones(zones, desiredNumberOfAZs), nil
+}
 
-	for len(zones) < desiredNumberOfAZs {
-		rand := rand.New(rand.NewSource(time.Now().UnixNano()))
-		for _, rn := range rand.Perm(len(availableZones)) {
-			zones = append(zones, availableZones[rn])
-			if len(zones) == desiredNumberOfAZs {
-				break
-			}
-		}
+func checkNumberOfZones(zones []string, minRequiredZones int) error {
+	if numberOfZones := len(zones); numberOfZones < minRequiredZones {
+		return fmt.Errorf("only %d zones discovered %v, at least %d are required", numberOfZones, zones, minRequiredZones)
 	}
+	return nil
+}
 
+// randomSelectionOfZones returns count unique zones of availableZones, which must have at least count zones
+func randomSelectionOfZones(availableZones []string, count int) []string {
+	zones := make([]string, 0, count)
+	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for _, rn := range rand.Perm(len(availableZones))[:count] {
+		zones = append(zones, availableZones[rn])
+	}
 	return zones
 }
 
 func getZones(ctx context.Context, ec2API awsapi.EC2, region string) ([]string, error) {
+	zones, err := describeZones(ctx, ec2API, region, ZoneTypeAvailabilityZone)
+	if err != nil {
+		return nil, err
+	}
+
+	return zoneNames(filterZones(region, zones)), nil
+}
+
+func describeZones(ctx context.Context, ec2API awsapi.EC2, region string, zoneTypes ...string) ([]ec2types.AvailabilityZone, error) {
 	input := &ec2.DescribeAvailabilityZonesInput{
 		Filters: []ec2types.Filter{
 			{
@@ -68,7 +385,7 @@ func getZones(ctx context.Context, ec2API awsapi.EC2, region string) ([]string,
 				Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
 			}, {
 				Name:   aws.String("zone-type"),
-				Values: []string{string(ec2types.LocationTypeAvailabilityZone)},
+				Values: zoneTypes,
 			},
 		},
 	}
@@ -78,17 +395,26 @@ func getZones(ctx context.Context, ec2API awsapi.EC2, region string) ([]string,
 		return nil, fmt.Errorf("error getting availability zones for region %s: %w", region, err)
 	}
 
-	return filterZones(region, output.AvailabilityZones), nil
+	return output.AvailabilityZones, nil
 }
 
-func filterZones(region string, zones []ec2types.AvailabilityZone) []string {
-	var filteredZones []string
-	azsToAvoid := zoneIDsToAvoid[region]
+// filterZones removes the zones to avoid in the region, along with the zones whose ID is in deniedZoneIDs
+func filterZones(region string, zones []ec2types.AvailabilityZone, deniedZoneIDs ...string) []Zone {
+	var filteredZones []Zone
+	azsToAvoid := append(append([]string{}, zoneIDsToAvoid[region]...), deniedZoneIDs...)
 	for _, z := range zones {
 		if !strings.Contains(azsToAvoid, *z.ZoneId) {
-			filteredZones = append(filteredZones, *z.ZoneName)
+			filteredZones = append(filteredZones, Zone{Name: *z.ZoneName, ID: *z.ZoneId})
 		}
 	}
 
 	return filteredZones
 }
+
+func zoneNames(zones []Zone) []string {
+	var names []string
+	for _, z := range zones {
+		names = append(names, z.Name)
+	}
+	return names
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
 
@@ -52,7 +53,7 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("errors", func() {
-			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).To(MatchError("only 1 zones discovered [zone1], at least 2 are required"))
 		})
 	})
@@ -83,7 +84,7 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("should return the 2 available AZs", func() {
-			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(zones).To(HaveLen(2))
 			Expect(zones).To(ConsistOf("zone1", "zone2"))
@@ -117,7 +118,7 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("should return the 3 available AZs", func() {
-			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(zones).To(HaveLen(3))
 			Expect(zones).To(ConsistOf("zone1", "zone2", "zone3"))
@@ -152,11 +153,30 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("should return a random set of 3 available AZs", func() {
-			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(zones).To(HaveLen(3))
 			Expect(zonesAreUnique(zones)).To(BeTrue())
 		})
+
+		It("should return all 4 AZs when 4 are requested", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithCount(4))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone1", "zone2", "zone3", "zone4"))
+		})
+
+		It("should return a random set of 2 AZs when 2 are requested", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithCount(2))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(HaveLen(2))
+			Expect(zonesAreUnique(zones)).To(BeTrue())
+		})
+
+		It("should return all 4 available AZs when 5 are requested", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithCount(5))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone1", "zone2", "zone3", "zone4"))
+		})
 	})
 
 	When("fetching the AZs errors", func() {
@@ -180,7 +200,7 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("errors", func() {
-			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).To(MatchError(fmt.Sprintf("error getting availability zones for region %s: foo", region)))
 		})
 	})
@@ -214,11 +234,69 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("should not use the denylisted zones", func() {
-			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(zones).To(HaveLen(2))
 			Expect(zones).To(ConsistOf("zone1", "zone2"))
 		})
+
+		It("should not use the zones denied by the user either", func() {
+			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, []string{"id-zone2"})
+			Expect(err).To(MatchError("only 1 zones discovered [zone1], at least 2 are required"))
+		})
+	})
+
+	When("the user denies zones", func() {
+		BeforeEach(func() {
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone1"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone2"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone3"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone4"),
+				},
+			}, nil)
+		})
+
+		It("should not use the denied zones", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, []string{"id-zone1", "id-zone3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone2", "zone4"))
+		})
+
+		It("should ignore denied zones that do not exist in the region", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, []string{"id-zone4", "use1-az3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone1", "zone2", "zone3"))
+		})
+	})
+
+	When("zone IDs are requested", func() {
+		BeforeEach(func() {
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-1a", "usw1-az3"),
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-1b", "usw1-az1"),
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-1c", "usw1-az2"),
+				},
+			}, nil)
+		})
+
+		It("should return the names of the zones with these IDs, in the same order", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithZoneIDs([]string{"usw1-az1", "usw1-az3"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(Equal([]string{"us-west-1b", "us-west-1a"}))
+		})
+
+		It("errors when a zone ID is not available in the region", func() {
+			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithZoneIDs([]string{"usw1-az1", "use1-az1"}))
+			Expect(err).To(MatchError(`zone ID "use1-az1" is not an available availability zone of region us-west-1, available zone IDs are [usw1-az3 usw1-az1 usw1-az2]`))
+		})
+
+		It("errors when a zone ID is denied", func() {
+			_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, []string{"usw1-az2"}, az.WithZoneIDs([]string{"usw1-az1", "usw1-az2"}))
+			Expect(err).To(MatchError(`zone ID "usw1-az2" is not an available availability zone of region us-west-1, available zone IDs are [usw1-az3 usw1-az1]`))
+		})
 	})
 
 	When("using us-east-1", func() {
@@ -251,12 +329,525 @@ var _ = Describe("AZ", func() {
 		})
 
 		It("should only use 2 AZs, rather than the default 3", func() {
-			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region)
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(zones).To(HaveLen(2))
 			Expect(zonesAreUnique(zones)).To(BeTrue())
 		})
+
+		It("should honor the requested count rather than the default 2", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithCount(3))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(HaveLen(3))
+			Expect(zonesAreUnique(zones)).To(BeTrue())
+		})
 	})
+
+	When("selecting the AZs from a list", func() {
+		JustBeforeEach(func() {
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+				Filters: []ec2types.Filter{
+					{
+						Name:   aws.String("region-name"),
+						Values: []string{region},
+					},
+					{
+						Name:   aws.String("state"),
+						Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+					},
+					{
+						Name:   aws.String("zone-type"),
+						Values: []string{string(ec2types.LocationTypeAvailabilityZone)},
+					},
+				},
+			}).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone1"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone2"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone3"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone4"),
+				},
+			}, nil)
+		})
+
+		It("should only return the available AZs that are in the list", func() {
+			zones, err := az.GetAvailabilityZonesFromList(context.Background(), p.MockEC2(), region, []string{"zone2", "zone4", "zone5"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone2", "zone4"))
+		})
+
+		It("should return a random set of 3 AZs from the list", func() {
+			zones, err := az.GetAvailabilityZonesFromList(context.Background(), p.MockEC2(), region, []string{"zone1", "zone2", "zone3", "zone4"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(HaveLen(3))
+			Expect(zonesAreUnique(zones)).To(BeTrue())
+		})
+
+		It("errors when fewer than 2 AZs of the list are available", func() {
+			_, err := az.GetAvailabilityZonesFromList(context.Background(), p.MockEC2(), region, []string{"zone1", "zone5"})
+			Expect(err).To(MatchError("only 1 zones discovered [zone1], at least 2 are required"))
+		})
+
+		It("errors when none of the AZs of the list are available", func() {
+			_, err := az.GetAvailabilityZonesFromList(context.Background(), p.MockEC2(), region, []string{"zone5", "zone6"})
+			Expect(err).To(MatchError("none of the zones [zone5 zone6] are available in region us-west-1, available zones are [zone1 zone2 zone3 zone4]"))
+		})
+
+		It("should select from all available AZs when the list is empty", func() {
+			zones, err := az.GetAvailabilityZonesFromList(context.Background(), p.MockEC2(), region, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(HaveLen(3))
+			Expect(zonesAreUnique(zones)).To(BeTrue())
+		})
+
+		When("using us-east-1", func() {
+			BeforeEach(func() {
+				region = "us-east-1"
+			})
+
+			It("should only use 2 AZs of the list", func() {
+				zones, err := az.GetAvailabilityZonesFromList(context.Background(), p.MockEC2(), region, []string{"zone1", "zone2", "zone3"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(HaveLen(2))
+				Expect(zonesAreUnique(zones)).To(BeTrue())
+			})
+		})
+	})
+
+	When("selecting the AZs that offer the instance types", func() {
+		var (
+			offerings    []ec2types.InstanceTypeOffering
+			offeringsErr error
+		)
+
+		offering := func(instanceType ec2types.InstanceType, zone string) ec2types.InstanceTypeOffering {
+			return ec2types.InstanceTypeOffering{
+				InstanceType: instanceType,
+				Location:     aws.String(zone),
+				LocationType: ec2types.LocationTypeAvailabilityZone,
+			}
+		}
+
+		BeforeEach(func() {
+			offeringsErr = nil
+			offerings = []ec2types.InstanceTypeOffering{
+				offering(ec2types.InstanceTypeM5Large, "zone1"),
+				offering(ec2types.InstanceTypeM5Large, "zone2"),
+				offering(ec2types.InstanceTypeM5Large, "zone3"),
+				offering(ec2types.InstanceTypeM5Large, "zone4"),
+				offering(ec2types.InstanceTypeP4d24xlarge, "zone2"),
+				offering(ec2types.InstanceTypeP4d24xlarge, "zone4"),
+			}
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone1"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone2"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone3"),
+					createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone4"),
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			p.MockEC2().On("DescribeInstanceTypeOfferings", mock.Anything, &ec2.DescribeInstanceTypeOfferingsInput{
+				LocationType: ec2types.LocationTypeAvailabilityZone,
+				Filters: []ec2types.Filter{
+					{
+						Name:   aws.String("instance-type"),
+						Values: []string{"m5.large", "p4d.24xlarge"},
+					},
+				},
+			}).Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+				InstanceTypeOfferings: offerings,
+			}, offeringsErr)
+		})
+
+		It("should only return the AZs that offer all instance types", func() {
+			zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithInstanceTypes([]string{"m5.large", "p4d.24xlarge"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone2", "zone4"))
+		})
+
+		When("fewer than 2 AZs offer all instance types", func() {
+			BeforeEach(func() {
+				offerings = offerings[:5]
+			})
+
+			It("should return a random set of 3 available AZs", func() {
+				zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithInstanceTypes([]string{"m5.large", "p4d.24xlarge"}))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(HaveLen(3))
+				Expect(zonesAreUnique(zones)).To(BeTrue())
+			})
+		})
+
+		When("fewer AZs than requested offer all instance types", func() {
+			It("should return a random set of the requested number of available AZs", func() {
+				zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithInstanceTypes([]string{"m5.large", "p4d.24xlarge"}), az.WithCount(3))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(HaveLen(3))
+				Expect(zonesAreUnique(zones)).To(BeTrue())
+			})
+		})
+
+		When("fetching the instance type offerings errors", func() {
+			BeforeEach(func() {
+				offeringsErr = fmt.Errorf("err")
+			})
+
+			It("errors", func() {
+				_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithInstanceTypes([]string{"m5.large", "p4d.24xlarge"}))
+				Expect(err).To(MatchError("error getting instance type offerings for [m5.large p4d.24xlarge]: err"))
+			})
+		})
+	})
+
+	When("selecting a number of AZs", func() {
+		var availableZones []ec2types.AvailabilityZone
+
+		BeforeEach(func() {
+			availableZones = []ec2types.AvailabilityZone{
+				createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone1"),
+				createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone2"),
+				createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone3"),
+				createAvailabilityZone(region, ec2types.AvailabilityZoneStateAvailable, "zone4"),
+			}
+		})
+
+		JustBeforeEach(func() {
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+				Filters: []ec2types.Filter{
+					{
+						Name:   aws.String("region-name"),
+						Values: []string{region},
+					},
+					{
+						Name:   aws.String("state"),
+						Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+					},
+					{
+						Name:   aws.String("zone-type"),
+						Values: []string{string(ec2types.LocationTypeAvailabilityZone)},
+					},
+				},
+			}).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: availableZones,
+			}, nil)
+		})
+
+		It("should return a single AZ when 1 is requested", func() {
+			zones, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(HaveLen(1))
+		})
+
+		It("should return all 4 available AZs when 5 are requested", func() {
+			zones, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(ConsistOf("zone1", "zone2", "zone3", "zone4"))
+		})
+
+		It("errors when the count is not positive", func() {
+			_, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 0)
+			Expect(err).To(MatchError("the number of zones to select must be at least 1, got 0"))
+		})
+
+		When("only 1 AZ is available", func() {
+			BeforeEach(func() {
+				availableZones = availableZones[:1]
+			})
+
+			It("should return it when 1 is requested", func() {
+				zones, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(ConsistOf("zone1"))
+			})
+
+			It("errors when more than 1 is requested", func() {
+				_, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 3)
+				Expect(err).To(MatchError("only 1 zones discovered [zone1], at least 2 are required"))
+			})
+		})
+
+		When("the region contains zones that are denylisted", func() {
+			BeforeEach(func() {
+				region = api.RegionCNNorth1
+				availableZones[3] = createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "zone4", "cnn1-az4")
+			})
+
+			It("should not use the denylisted zones", func() {
+				zones, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 4)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(ConsistOf("zone1", "zone2", "zone3"))
+			})
+		})
+
+		When("using us-east-1", func() {
+			BeforeEach(func() {
+				region = "us-east-1"
+			})
+
+			It("should honor the requested count rather than the default 2", func() {
+				zones, err := az.GetAvailabilityZonesWithCount(context.Background(), p.MockEC2(), region, 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(HaveLen(3))
+				Expect(zonesAreUnique(zones)).To(BeTrue())
+			})
+		})
+	})
+
+	When("getting the IDs of AZs", func() {
+		var availableZones []ec2types.AvailabilityZone
+
+		BeforeEach(func() {
+			region = "us-west-2"
+			availableZones = []ec2types.AvailabilityZone{
+				createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2a", "usw2-az2"),
+				createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2b", "usw2-az1"),
+				createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2c", "usw2-az3"),
+				createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2d", "usw2-az4"),
+			}
+		})
+
+		JustBeforeEach(func() {
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+				Filters: []ec2types.Filter{
+					{
+						Name:   aws.String("region-name"),
+						Values: []string{region},
+					},
+					{
+						Name:   aws.String("state"),
+						Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+					},
+					{
+						Name:   aws.String("zone-type"),
+						Values: []string{string(ec2types.LocationTypeAvailabilityZone)},
+					},
+				},
+			}).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: availableZones,
+			}, nil)
+		})
+
+		It("should return the IDs of a random set of 3 AZs", func() {
+			zones, err := az.GetAvailabilityZoneIDs(context.Background(), p.MockEC2(), region)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(HaveLen(3))
+			zoneIDs := map[string]string{
+				"us-west-2a": "usw2-az2",
+				"us-west-2b": "usw2-az1",
+				"us-west-2c": "usw2-az3",
+				"us-west-2d": "usw2-az4",
+			}
+			var names []string
+			for _, z := range zones {
+				Expect(z.ID).To(Equal(zoneIDs[z.Name]))
+				names = append(names, z.Name)
+			}
+			Expect(zonesAreUnique(names)).To(BeTrue())
+		})
+
+		When("the region contains zones that are denylisted", func() {
+			BeforeEach(func() {
+				region = api.RegionCNNorth1
+				availableZones = []ec2types.AvailabilityZone{
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "cn-north-1a", "cnn1-az1"),
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "cn-north-1b", "cnn1-az2"),
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "cn-north-1d", "cnn1-az4"),
+				}
+			})
+
+			It("should not return the denylisted zone IDs", func() {
+				zones, err := az.GetAvailabilityZoneIDs(context.Background(), p.MockEC2(), region)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(ConsistOf(
+					az.Zone{Name: "cn-north-1a", ID: "cnn1-az1"},
+					az.Zone{Name: "cn-north-1b", ID: "cnn1-az2"},
+				))
+			})
+		})
+	})
+
+	When("getting zones of a type", func() {
+		var (
+			zoneType       string
+			availableZones []ec2types.AvailabilityZone
+		)
+
+		BeforeEach(func() {
+			region = "us-west-2"
+			zoneType = az.ZoneTypeLocalZone
+			availableZones = []ec2types.AvailabilityZone{
+				createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2-lax-1a", "usw2-lax1-az1"),
+			}
+		})
+
+		JustBeforeEach(func() {
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+				Filters: []ec2types.Filter{
+					{
+						Name:   aws.String("region-name"),
+						Values: []string{region},
+					},
+					{
+						Name:   aws.String("state"),
+						Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+					},
+					{
+						Name:   aws.String("zone-type"),
+						Values: []string{zoneType},
+					},
+				},
+			}).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: availableZones,
+			}, nil)
+		})
+
+		It("should return a single Local Zone with its ID", func() {
+			zones, err := az.GetZonesOfType(context.Background(), p.MockEC2(), region, zoneType)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(Equal([]az.Zone{{Name: "us-west-2-lax-1a", ID: "usw2-lax1-az1"}}))
+		})
+
+		When("no Local Zones are available", func() {
+			BeforeEach(func() {
+				availableZones = nil
+			})
+
+			It("errors", func() {
+				_, err := az.GetZonesOfType(context.Background(), p.MockEC2(), region, zoneType)
+				Expect(err).To(MatchError("only 0 zones discovered [], at least 1 are required"))
+			})
+		})
+
+		When("getting Wavelength Zones", func() {
+			BeforeEach(func() {
+				zoneType = az.ZoneTypeWavelengthZone
+				availableZones = []ec2types.AvailabilityZone{
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2-wl1-sea-wlz-1", "usw2-wl1-sea-wlz1"),
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2-wl1-den-wlz-1", "usw2-wl1-den-wlz1"),
+				}
+			})
+
+			It("should return all of them", func() {
+				zones, err := az.GetZonesOfType(context.Background(), p.MockEC2(), region, zoneType)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(ConsistOf(
+					az.Zone{Name: "us-west-2-wl1-sea-wlz-1", ID: "usw2-wl1-sea-wlz1"},
+					az.Zone{Name: "us-west-2-wl1-den-wlz-1", ID: "usw2-wl1-den-wlz1"},
+				))
+			})
+		})
+
+		When("getting availability zones", func() {
+			BeforeEach(func() {
+				zoneType = az.ZoneTypeAvailabilityZone
+				availableZones = []ec2types.AvailabilityZone{
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2a", "usw2-az1"),
+				}
+			})
+
+			It("errors when fewer than 2 are available", func() {
+				_, err := az.GetZonesOfType(context.Background(), p.MockEC2(), region, zoneType)
+				Expect(err).To(MatchError("only 1 zones discovered [us-west-2a], at least 2 are required"))
+			})
+		})
+
+		It("errors for an unknown zone type", func() {
+			_, err := az.GetZonesOfType(context.Background(), p.MockEC2(), region, "outpost")
+			Expect(err).To(MatchError(`invalid zone type "outpost", valid zone types are "availability-zone", "local-zone" and "wavelength-zone"`))
+		})
+	})
+
+	When("getting Local Zones", func() {
+		BeforeEach(func() {
+			region = "us-west-2"
+			p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+				Filters: []ec2types.Filter{
+					{
+						Name:   aws.String("region-name"),
+						Values: []string{region},
+					},
+					{
+						Name:   aws.String("state"),
+						Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+					},
+					{
+						Name:   aws.String("zone-type"),
+						Values: []string{az.ZoneTypeLocalZone, az.ZoneTypeWavelengthZone},
+					},
+				},
+			}).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2-lax-1a", "usw2-lax1-az1"),
+					createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2-wl1-sea-wlz-1", "usw2-wl1-sea-wlz1"),
+				},
+			}, nil)
+		})
+
+		It("returns the Local Zones and Wavelength Zones", func() {
+			zones, err := az.GetLocalZones(context.Background(), p.MockEC2(), region)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(Equal([]az.Zone{
+				{Name: "us-west-2-lax-1a", ID: "usw2-lax1-az1"},
+				{Name: "us-west-2-wl1-sea-wlz-1", ID: "usw2-wl1-sea-wlz1"},
+			}))
+		})
+
+		It("errors when checking a zone that is not available", func() {
+			err := az.CheckLocalZones(context.Background(), p.MockEC2(), region, []string{"us-west-2-lax-1a", "us-west-2-den-1a"})
+			Expect(err).To(MatchError(`zone "us-west-2-den-1a" is not an available Local Zone or Wavelength Zone of region us-west-2, available zones are [us-west-2-lax-1a us-west-2-wl1-sea-wlz-1]`))
+		})
+
+		When("selecting availability zones", func() {
+			BeforeEach(func() {
+				p.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+					Filters: []ec2types.Filter{
+						{
+							Name:   aws.String("region-name"),
+							Values: []string{region},
+						},
+						{
+							Name:   aws.String("state"),
+							Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+						},
+						{
+							Name:   aws.String("zone-type"),
+							Values: []string{az.ZoneTypeAvailabilityZone},
+						},
+					},
+				}).Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []ec2types.AvailabilityZone{
+						createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2a", "usw2-az1"),
+						createAvailabilityZoneWithID(region, ec2types.AvailabilityZoneStateAvailable, "us-west-2b", "usw2-az2"),
+					},
+				}, nil)
+			})
+
+			It("returns the Local Zones after the availability zones", func() {
+				zones, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithLocalZones([]string{"us-west-2-lax-1a"}))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(HaveLen(3))
+				Expect(zones[:2]).To(ConsistOf("us-west-2a", "us-west-2b"))
+				Expect(zones[2]).To(Equal("us-west-2-lax-1a"))
+			})
+
+			It("errors when a Local Zone is not available", func() {
+				_, err := az.GetAvailabilityZones(context.Background(), p.MockEC2(), region, nil, az.WithLocalZones([]string{"us-west-2-den-1a"}))
+				Expect(err).To(MatchError(ContainSubstring(`zone "us-west-2-den-1a" is not an available Local Zone or Wavelength Zone of region us-west-2`)))
+			})
+		})
+	})
+
+	DescribeTable("recognizing the names of availability zones", func(name string, expected bool) {
+		Expect(az.IsAvailabilityZoneName(name)).To(Equal(expected))
+	},
+		Entry("an availability zone", "us-west-2a", true),
+		Entry("an availability zone of GovCloud", "us-gov-west-1b", true),
+		Entry("a Local Zone", "us-west-2-lax-1a", false),
+		Entry("a Wavelength Zone", "us-east-1-wl1-bos-wlz-1", false),
+	)
 })
 
 func zonesAreUnique(zones []string) bool {
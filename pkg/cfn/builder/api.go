@@ -103,6 +103,12 @@ func (r *resourceSet) newResource(name string, resource gfn.Resource) *gfnt.Valu
 	return gfnt.MakeRef(name)
 }
 
+// newParameter adds a parameter, it returns a reference
+func (r *resourceSet) newParameter(name string, parameter gfn.Parameter) *gfnt.Value {
+	r.template.Parameters[name] = parameter
+	return gfnt.MakeRef(name)
+}
+
 // renderJSON renders template as JSON
 func (r *resourceSet) renderJSON() ([]byte, error) {
 	return r.template.JSON()
@@ -1,6 +1,10 @@
 package builder
 
 import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	gfn "github.com/weaveworks/goformation/v4/cloudformation"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 
@@ -94,3 +98,61 @@ func makeBlockDeviceMapping(vm *api.VolumeMapping) *gfnec2.LaunchTemplate_BlockD
 
 	return &mapping
 }
+
+// newLaunchTemplate returns a launch template resource for launchTemplateData. goformation does not support
+// Ebs.VolumeInitializationRate or PrivateDnsNameOptions, so when the nodegroup sets either of them the launch
+// template is rendered as a raw resource with the properties added to its data
+func newLaunchTemplate(launchTemplateName *gfnt.Value, launchTemplateData *gfnec2.LaunchTemplate_LaunchTemplateData, ng *api.NodeGroupBase) (gfn.Resource, error) {
+	launchTemplate := &gfnec2.LaunchTemplate{
+		LaunchTemplateName: launchTemplateName,
+		LaunchTemplateData: launchTemplateData,
+	}
+
+	initializationRates := map[string]int{}
+	for _, volume := range ng.AdditionalVolumes {
+		if volume.VolumeInitializationRate != nil && api.IsSetAndNonEmptyString(volume.VolumeName) {
+			initializationRates[*volume.VolumeName] = *volume.VolumeInitializationRate
+		}
+	}
+	var hostnameType string
+	if ng.PrivateDNSNameOptions != nil {
+		hostnameType = ng.PrivateDNSNameOptions.HostnameType
+	}
+	if len(initializationRates) == 0 && hostnameType == "" {
+		return launchTemplate, nil
+	}
+
+	data, err := json.Marshal(launchTemplateData)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling launch template data")
+	}
+	var renderedData map[string]interface{}
+	if err := json.Unmarshal(data, &renderedData); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling launch template data")
+	}
+
+	mappings, _ := renderedData["BlockDeviceMappings"].([]interface{})
+	for _, m := range mappings {
+		mapping, _ := m.(map[string]interface{})
+		deviceName, _ := mapping["DeviceName"].(string)
+		if rate, ok := initializationRates[deviceName]; ok {
+			if ebs, ok := mapping["Ebs"].(map[string]interface{}); ok {
+				ebs["VolumeInitializationRate"] = rate
+			}
+		}
+	}
+
+	if hostnameType != "" {
+		renderedData["PrivateDnsNameOptions"] = map[string]interface{}{
+			"HostnameType": hostnameType,
+		}
+	}
+
+	return &awsCloudFormationResource{
+		Type: launchTemplate.AWSCloudFormationType(),
+		Properties: map[string]interface{}{
+			"LaunchTemplateName": launchTemplateName,
+			"LaunchTemplateData": renderedData,
+		},
+	}, nil
+}
@@ -141,7 +141,11 @@ func (c *ClusterResourceSet) addResourcesForSecurityGroups(vpcID *gfnt.Value) *c
 	}
 	c.securityGroups = []*gfnt.Value{refControlPlaneSG} // only this one SG is passed to EKS API, nodes are isolated
 
-	if c.spec.VPC.SharedNodeSecurityGroup == "" {
+	sharedNodeSGDisabled := c.spec.IsSharedNodeSecurityGroupDisabled()
+	if sharedNodeSGDisabled {
+		// the output records that the shared node security group is disabled, for nodegroups created later on
+		refClusterSharedNodeSG = gfnt.NewString(api.SharedNodeSecurityGroupDisabled)
+	} else if c.spec.VPC.SharedNodeSecurityGroup == "" {
 		refClusterSharedNodeSG = c.newResource(cfnSharedNodeSGResource, &gfnec2.SecurityGroup{
 			GroupDescription: gfnt.NewString("Communication between all nodes in the cluster"),
 			VpcId:            vpcID,
@@ -158,7 +162,7 @@ func (c *ClusterResourceSet) addResourcesForSecurityGroups(vpcID *gfnt.Value) *c
 		refClusterSharedNodeSG = gfnt.NewString(c.spec.VPC.SharedNodeSecurityGroup)
 	}
 
-	if api.IsEnabled(c.spec.VPC.ManageSharedNodeSecurityGroupRules) {
+	if api.IsEnabled(c.spec.VPC.ManageSharedNodeSecurityGroupRules) && !sharedNodeSGDisabled {
 		// To enable communication between both managed and unmanaged nodegroups, this allows ingress traffic from
 		// the default cluster security group ID that EKS creates by default
 		// EKS attaches this to Managed Nodegroups by default, but we need to handle this for unmanaged nodegroups
@@ -260,6 +260,24 @@ var _ = Describe("Cluster Template Builder", func() {
 			})
 		})
 
+		Context("if SharedNodeSecurityGroup is disabled", func() {
+			BeforeEach(func() {
+				cfg.VPC.SharedNodeSecurityGroup = api.SharedNodeSecurityGroupDisabled
+				cfg.VPC.ManageSharedNodeSecurityGroupRules = api.Enabled()
+			})
+
+			It("should not add the shared security group or its rules", func() {
+				Expect(clusterTemplate.Resources).NotTo(HaveKey("ClusterSharedNodeSecurityGroup"))
+				Expect(clusterTemplate.Resources).NotTo(HaveKey("IngressInterNodeGroupSG"))
+				Expect(clusterTemplate.Resources).NotTo(HaveKey("IngressDefaultClusterToNodeSG"))
+				Expect(clusterTemplate.Resources).NotTo(HaveKey("IngressNodeToDefaultClusterSG"))
+			})
+
+			It("should record that the shared security group is disabled in the outputs", func() {
+				Expect(clusterTemplate.Outputs).To(HaveKey("SharedNodeSecurityGroup"))
+			})
+		})
+
 		Context("if the control plane SecurityGroup is set", func() {
 			BeforeEach(func() {
 				cfg.VPC.SecurityGroup = "foo"
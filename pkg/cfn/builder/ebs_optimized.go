@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"context"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/awsapi"
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
+)
+
+// validateEBSOptimized checks that the instance types support the requested EBS optimization. It errors when EBS
+// optimization is enabled for an instance type that does not support it, and warns when the setting has no effect
+// because EBS optimization is always enabled for the instance type
+func validateEBSOptimized(ctx context.Context, ebsOptimized bool, instanceTypes []string, ec2API awsapi.EC2) error {
+	if len(instanceTypes) == 0 {
+		return nil
+	}
+
+	info, err := instancetypes.InfoList(ctx, ec2API, instanceTypes)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't retrieve instance type description for %v", instanceTypes)
+	}
+
+	for _, it := range info {
+		switch it.EBSOptimizedSupport {
+		case ec2types.EbsOptimizedSupportUnsupported:
+			if ebsOptimized {
+				return errors.Errorf("instance type %s does not support EBS optimization", it.InstanceType)
+			}
+		case ec2types.EbsOptimizedSupportDefault:
+			if ebsOptimized {
+				logger.Warning("EBS optimization is always enabled for instance type %s, setting ebsOptimized has no effect", it.InstanceType)
+			} else {
+				logger.Warning("EBS optimization is always enabled for instance type %s and cannot be disabled with ebsOptimized", it.InstanceType)
+			}
+		}
+	}
+	return nil
+}
@@ -3,3 +3,7 @@ package builder
 func NewRS() *resourceSet {
 	return newResourceSet()
 }
+
+func (n *NodeGroupResourceSet) AddScalingParameters() {
+	n.addScalingParameters()
+}
@@ -2,6 +2,7 @@ package fakes
 
 type FakeTemplate struct {
 	Description string
+	Parameters  map[string]Parameter
 	Resources   map[string]struct {
 		Type         string
 		Properties   Properties
@@ -12,6 +13,12 @@ type FakeTemplate struct {
 	Outputs  interface{}
 }
 
+type Parameter struct {
+	Type        string
+	Description string
+	Default     string
+}
+
 type Tag struct {
 	Key   interface{}
 	Value interface{}
@@ -48,6 +55,8 @@ type Properties struct {
 	LaunchTemplateName interface{}
 	Strategy           string
 
+	KeyName, PublicKeyMaterial string
+
 	CapacityRebalance bool
 
 	VPCZoneIdentifier interface{}
@@ -55,8 +64,17 @@ type Properties struct {
 	LoadBalancerNames                 []string
 	MetricsCollection                 []map[string]interface{}
 	TargetGroupARNs                   []string
-	DesiredCapacity, MinSize, MaxSize string
+	DesiredCapacity, MinSize, MaxSize interface{}
 	MaxInstanceLifetime               int
+	Context                           string
+
+	CapacityReservationSpecification *struct {
+		CapacityReservationPreference string
+		CapacityReservationTarget     *struct {
+			CapacityReservationIds               []string
+			CapacityReservationResourceGroupArns []string
+		}
+	}
 
 	CidrIP, CidrIPv6, IPProtocol string
 	FromPort, ToPort             int
@@ -92,8 +110,9 @@ type Properties struct {
 		Resources []string
 	}
 	LaunchTemplate struct {
-		LaunchTemplateName map[string]interface{}
-		Version            map[string]interface{}
+		LaunchTemplateId   interface{}
+		LaunchTemplateName interface{}
+		Version            interface{}
 		Overrides          []struct {
 			InstanceType string
 		}
@@ -106,12 +125,15 @@ type Properties struct {
 				Version            map[string]interface{}
 			}
 			Overrides []struct {
-				InstanceType string
+				InstanceType         string
+				WeightedCapacity     string
+				InstanceRequirements map[string]interface{}
 			}
 		}
 		InstancesDistribution struct {
 			OnDemandBaseCapacity                string
 			OnDemandPercentageAboveBaseCapacity string
+			OnDemandAllocationStrategy          string
 			SpotMaxPrice                        string
 			SpotInstancePools                   string
 			SpotAllocationStrategy              string
@@ -144,13 +166,14 @@ type SGIngress struct {
 }
 
 type LaunchTemplateData struct {
-	IamInstanceProfile              struct{ Arn interface{} }
-	UserData, InstanceType, ImageID string
-	BlockDeviceMappings             []BlockDeviceMappings
-	EbsOptimized                    *bool
-	Monitoring                      *Monitoring
-	NetworkInterfaces               []NetworkInterface
-	InstanceMarketOptions           *struct {
+	IamInstanceProfile     struct{ Arn interface{} }
+	UserData, InstanceType string
+	ImageID                interface{}
+	BlockDeviceMappings    []BlockDeviceMappings
+	EbsOptimized           *bool
+	Monitoring             *Monitoring
+	NetworkInterfaces      []NetworkInterface
+	InstanceMarketOptions  *struct {
 		MarketType  string
 		SpotOptions struct {
 			SpotInstanceType string
@@ -160,14 +183,28 @@ type LaunchTemplateData struct {
 	CreditSpecification *struct {
 		CPUCredits string
 	}
-	MetadataOptions   MetadataOptions
-	TagSpecifications []TagSpecification
-	Placement         Placement
-	KeyName           string
+	MetadataOptions                  *MetadataOptions
+	TagSpecifications                []TagSpecification
+	Placement                        Placement
+	KeyName                          interface{}
+	CapacityReservationSpecification *struct {
+		CapacityReservationPreference string
+		CapacityReservationTarget     *struct {
+			CapacityReservationID               string
+			CapacityReservationResourceGroupARN string
+		}
+	}
+	InstanceInitiatedShutdownBehavior string
+	PrivateDnsNameOptions             *struct {
+		HostnameType string
+	}
 }
 
 type Placement struct {
-	GroupName interface{}
+	GroupName            interface{}
+	Tenancy              string
+	HostResourceGroupArn string
+	PartitionNumber      float64
 }
 
 type BlockDeviceMappings struct {
@@ -187,9 +224,10 @@ type TagSpecification struct {
 
 type NetworkInterface struct {
 	DeviceIndex              int
-	AssociatePublicIPAddress bool
+	AssociatePublicIPAddress *bool
 	NetworkCardIndex         int
 	InterfaceType            string
+	Ipv6AddressCount         int
 }
 
 type Monitoring struct {
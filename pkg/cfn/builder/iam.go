@@ -130,7 +130,7 @@ func (n *NodeGroupResourceSet) addResourcesForIAM(ctx context.Context) error {
 		n.instanceProfileARN = gfnt.NewString(n.spec.IAM.InstanceProfileARN)
 		if n.spec.IAM.InstanceRoleARN != "" {
 			n.rs.defineOutputWithoutCollector(outputs.NodeGroupInstanceProfileARN, n.spec.IAM.InstanceProfileARN, true)
-			n.rs.defineOutputWithoutCollector(outputs.NodeGroupInstanceRoleARN, n.spec.IAM.InstanceRoleARN, true)
+			n.rs.defineOutput(outputs.NodeGroupInstanceRoleARN, n.spec.IAM.InstanceRoleARN, true, outputs.NewInstanceRoleARNCollector(n.spec.IAM))
 			return nil
 		}
 		// if instance role is not given, export profile and use the getter to call importer function
@@ -156,7 +156,7 @@ func (n *NodeGroupResourceSet) addResourcesForIAM(ctx context.Context) error {
 			n.spec.IAM.InstanceProfileARN = v
 			return nil
 		})
-		n.rs.defineOutputWithoutCollector(outputs.NodeGroupInstanceRoleARN, roleARN, true)
+		n.rs.defineOutput(outputs.NodeGroupInstanceRoleARN, roleARN, true, outputs.NewInstanceRoleARNCollector(n.spec.IAM))
 		return nil
 	}
 
@@ -167,7 +167,7 @@ func (n *NodeGroupResourceSet) addResourcesForIAM(ctx context.Context) error {
 		n.rs.withNamedIAM = true
 	}
 
-	if err := createRole(n.rs, n.clusterSpec.IAM, n.spec.IAM, false, n.forceAddCNIPolicy); err != nil {
+	if err := createRole(n.rs, n.clusterSpec.IAM, n.spec.Name, n.spec.IAM, false, n.forceAddCNIPolicy); err != nil {
 		return err
 	}
 
@@ -181,10 +181,7 @@ func (n *NodeGroupResourceSet) addResourcesForIAM(ctx context.Context) error {
 		n.spec.IAM.InstanceProfileARN = v
 		return nil
 	})
-	n.rs.defineOutputFromAtt(outputs.NodeGroupInstanceRoleARN, cfnIAMInstanceRoleName, "Arn", true, func(v string) error {
-		n.spec.IAM.InstanceRoleARN = v
-		return nil
-	})
+	n.rs.defineOutputFromAtt(outputs.NodeGroupInstanceRoleARN, cfnIAMInstanceRoleName, "Arn", true, outputs.NewInstanceRoleARNCollector(n.spec.IAM))
 	return nil
 }
 
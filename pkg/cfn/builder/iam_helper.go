@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/kris-nova/logger"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
 	gfn "github.com/weaveworks/goformation/v4/cloudformation"
@@ -77,8 +78,8 @@ func createWellKnownPolicies(wellKnownPolicies api.WellKnownPolicies) ([]managed
 }
 
 // createRole creates an IAM role with policies required for the worker nodes and addons
-func createRole(cfnTemplate cfnTemplate, clusterIAMConfig *api.ClusterIAM, iamConfig *api.NodeGroupIAM, managed, forceAddCNIPolicy bool) error {
-	managedPolicyARNs, err := makeManagedPolicies(clusterIAMConfig, iamConfig, managed, forceAddCNIPolicy)
+func createRole(cfnTemplate cfnTemplate, clusterIAMConfig *api.ClusterIAM, nodeGroupName string, iamConfig *api.NodeGroupIAM, managed, forceAddCNIPolicy bool) error {
+	managedPolicyARNs, err := makeManagedPolicies(clusterIAMConfig, nodeGroupName, iamConfig, managed, forceAddCNIPolicy)
 	if err != nil {
 		return err
 	}
@@ -149,13 +150,44 @@ func createRole(cfnTemplate cfnTemplate, clusterIAMConfig *api.ClusterIAM, iamCo
 	return nil
 }
 
-func makeManagedPolicies(iamCluster *api.ClusterIAM, iamConfig *api.NodeGroupIAM, managed, forceAddCNIPolicy bool) (*gfnt.Value, error) {
+// attachCNIPolicy returns whether the AmazonEKS_CNI_Policy should be attached to the instance role, along with the
+// reason for it
+func attachCNIPolicy(iamCluster *api.ClusterIAM, iamConfig *api.NodeGroupIAM, forceAddCNIPolicy bool) (bool, string) {
+	switch iamConfig.AttachCNIPolicy {
+	case api.AttachCNIPolicyAlways:
+		return true, "iam.attachCNIPolicy is always"
+	case api.AttachCNIPolicyNever:
+		return false, "iam.attachCNIPolicy is never"
+	}
+
+	switch {
+	case len(iamConfig.AttachPolicyARNs) > 0:
+		return false, "iam.attachPolicyARNs replaces the default policies"
+	case !api.IsEnabled(iamCluster.WithOIDC):
+		return true, "iam.withOIDC is not enabled, so the VPC CNI cannot use IRSA"
+	case forceAddCNIPolicy:
+		return true, "the aws-node service account does not use IRSA"
+	default:
+		return false, "the aws-node service account uses IRSA"
+	}
+}
+
+func makeManagedPolicies(iamCluster *api.ClusterIAM, nodeGroupName string, iamConfig *api.NodeGroupIAM, managed, forceAddCNIPolicy bool) (*gfnt.Value, error) {
 	managedPolicyNames := sets.NewString()
+	attach, reason := attachCNIPolicy(iamCluster, iamConfig, forceAddCNIPolicy)
+	if attach {
+		managedPolicyNames.Insert(iamPolicyAmazonEKSCNIPolicy)
+	}
+	if iamConfig.AttachCNIPolicy == "" || iamConfig.AttachCNIPolicy == api.AttachCNIPolicyAuto {
+		if attach {
+			logger.Info("attaching %s to the instance role of nodegroup %q as %s", iamPolicyAmazonEKSCNIPolicy, nodeGroupName, reason)
+		} else {
+			logger.Info("not attaching %s to the instance role of nodegroup %q as %s", iamPolicyAmazonEKSCNIPolicy, nodeGroupName, reason)
+		}
+	}
+
 	if len(iamConfig.AttachPolicyARNs) == 0 {
 		managedPolicyNames.Insert(iamDefaultNodePolicies...)
-		if !api.IsEnabled(iamCluster.WithOIDC) || forceAddCNIPolicy {
-			managedPolicyNames.Insert(iamPolicyAmazonEKSCNIPolicy)
-		}
 		if managed {
 			// The Managed Nodegroup API requires this managed policy to be present, even though
 			// AmazonEC2ContainerRegistryPowerUser (attached if imageBuilder is enabled) contains a superset of the
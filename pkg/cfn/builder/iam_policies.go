@@ -0,0 +1,127 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gfn "github.com/weaveworks/goformation/v4/cloudformation"
+	gfniam "github.com/weaveworks/goformation/v4/cloudformation/iam"
+	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+)
+
+// NodeGroupIAMPolicies holds the IAM policies eksctl attaches to the instance role of a nodegroup
+type NodeGroupIAMPolicies struct {
+	// ManagedPolicyARNs are the ARNs of the managed policies attached to the role
+	ManagedPolicyARNs []string `json:"managedPolicyARNs"`
+	// InlinePolicies maps the logical name of each inline policy to its policy document
+	InlinePolicies map[string]json.RawMessage `json:"inlinePolicies,omitempty"`
+}
+
+// RenderNodeGroupIAMPolicies renders the IAM policies that would be attached to the instance role created for np,
+// without creating anything. References to the partition are resolved for the region of the cluster.
+// It returns nil if the nodegroup uses an existing instance role or instance profile, as eksctl does not create
+// a role for it
+func RenderNodeGroupIAMPolicies(clusterConfig *api.ClusterConfig, np api.NodePool, forceAddCNIPolicy bool) (*NodeGroupIAMPolicies, error) {
+	iamConfig := np.BaseNodeGroup().IAM
+	_, managed := np.(*api.ManagedNodeGroup)
+	if iamConfig.InstanceRoleARN != "" || (!managed && iamConfig.InstanceProfileARN != "") {
+		return nil, nil
+	}
+
+	recorder := &iamPolicyRecorder{
+		inlinePolicies: map[string]interface{}{},
+	}
+	if err := createRole(recorder, clusterConfig.IAM, np.BaseNodeGroup().Name, iamConfig, managed, forceAddCNIPolicy); err != nil {
+		return nil, err
+	}
+
+	partition := api.Partition(clusterConfig.Metadata.Region)
+	policies := &NodeGroupIAMPolicies{
+		InlinePolicies: map[string]json.RawMessage{},
+	}
+
+	managedPolicyARNs, err := renderIAMValue(recorder.role.ManagedPolicyArns, partition)
+	if err != nil {
+		return nil, err
+	}
+	policyARNs, ok := managedPolicyARNs.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected managed policy ARNs %v", managedPolicyARNs)
+	}
+	for _, policyARN := range policyARNs {
+		s, ok := policyARN.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected managed policy ARN %v", policyARN)
+		}
+		policies.ManagedPolicyARNs = append(policies.ManagedPolicyARNs, s)
+	}
+
+	for name, document := range recorder.inlinePolicies {
+		rendered, err := renderIAMValue(document, partition)
+		if err != nil {
+			return nil, fmt.Errorf("rendering policy %s: %w", name, err)
+		}
+		policyJSON, err := json.Marshal(rendered)
+		if err != nil {
+			return nil, err
+		}
+		policies.InlinePolicies[name] = policyJSON
+	}
+	return policies, nil
+}
+
+// iamPolicyRecorder implements cfnTemplate to record the role and policies created by createRole
+type iamPolicyRecorder struct {
+	role           *gfniam.Role
+	inlinePolicies map[string]interface{}
+}
+
+func (r *iamPolicyRecorder) attachAllowPolicy(name string, _ *gfnt.Value, statements []cft.MapOfInterfaces) {
+	r.inlinePolicies[name] = cft.MakePolicyDocument(statements...)
+}
+
+func (r *iamPolicyRecorder) attachAllowPolicyDocument(name string, _ *gfnt.Value, document api.InlineDocument) {
+	r.inlinePolicies[name] = document
+}
+
+func (r *iamPolicyRecorder) newResource(name string, resource gfn.Resource) *gfnt.Value {
+	if role, ok := resource.(*gfniam.Role); ok {
+		r.role = role
+	}
+	return gfnt.MakeRef(name)
+}
+
+// renderIAMValue renders value as JSON, replacing the Fn::Sub functions referencing the partition with the strings
+// they evaluate to
+func renderIAMValue(value interface{}, partition string) (interface{}, error) {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(valueJSON, &decoded); err != nil {
+		return nil, err
+	}
+	return resolvePartition(decoded, partition), nil
+}
+
+func resolvePartition(value interface{}, partition string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if sub, ok := v["Fn::Sub"].(string); ok && len(v) == 1 {
+			return strings.ReplaceAll(sub, fmt.Sprintf("${%s}", gfnt.Partition), partition)
+		}
+		for key, elem := range v {
+			v[key] = resolvePartition(elem, partition)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = resolvePartition(elem, partition)
+		}
+	}
+	return value
+}
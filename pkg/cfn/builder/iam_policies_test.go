@@ -0,0 +1,110 @@
+package builder_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+)
+
+var _ = Describe("RenderNodeGroupIAMPolicies", func() {
+	var (
+		cfg *api.ClusterConfig
+		ng  *api.NodeGroup
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Region = api.RegionUSWest2
+		ng = cfg.NewNodeGroup()
+		ng.Name = "ng"
+	})
+
+	It("renders the managed policies attached by default", func() {
+		policies, err := builder.RenderNodeGroupIAMPolicies(cfg, ng, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies.ManagedPolicyARNs).To(ConsistOf(
+			"arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+			"arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+			"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy",
+			"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore",
+		))
+		Expect(policies.InlinePolicies).To(BeEmpty())
+	})
+
+	It("renders the inline policies of addons for the partition of the region", func() {
+		cfg.Metadata.Region = api.RegionCNNorth1
+		ng.IAM.WithAddonPolicies.AutoScaler = api.Enabled()
+		ng.IAM.WithAddonPolicies.ExternalDNS = api.Enabled()
+
+		policies, err := builder.RenderNodeGroupIAMPolicies(cfg, ng, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies.ManagedPolicyARNs).To(ContainElement("arn:aws-cn:iam::aws:policy/AmazonEKSWorkerNodePolicy"))
+		Expect(policies.InlinePolicies).To(HaveKey("PolicyAutoScaling"))
+		Expect(policies.InlinePolicies).To(HaveKey("PolicyExternalDNSHostedZones"))
+		Expect(string(policies.InlinePolicies["PolicyExternalDNSChangeSet"])).To(MatchJSON(`{
+			"Version": "2012-10-17",
+			"Statement": [
+				{
+					"Effect": "Allow",
+					"Resource": "arn:aws-cn:route53:::hostedzone/*",
+					"Action": ["route53:ChangeResourceRecordSets"]
+				}
+			]
+		}`))
+	})
+
+	It("renders the attached policy ARNs and documents of managed nodegroups", func() {
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng"
+		mng.IAM.AttachPolicyARNs = []string{"arn:aws:iam::123456789012:policy/my-policy"}
+		mng.IAM.AttachPolicy = api.InlineDocument{
+			"Version": "2012-10-17",
+			"Statement": []interface{}{
+				map[string]interface{}{
+					"Effect":   "Allow",
+					"Action":   "s3:GetObject",
+					"Resource": "*",
+				},
+			},
+		}
+
+		policies, err := builder.RenderNodeGroupIAMPolicies(cfg, mng, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies.ManagedPolicyARNs).To(Equal([]string{"arn:aws:iam::123456789012:policy/my-policy"}))
+		Expect(string(policies.InlinePolicies["Policy1"])).To(MatchJSON(`{
+			"Version": "2012-10-17",
+			"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]
+		}`))
+	})
+
+	DescribeTable("attaching the AmazonEKS_CNI_Policy", func(attachCNIPolicy string, withOIDC bool, attachPolicyARNs []string, expectAttached bool) {
+		cfg.IAM.WithOIDC = &withOIDC
+		ng.IAM.AttachCNIPolicy = attachCNIPolicy
+		ng.IAM.AttachPolicyARNs = attachPolicyARNs
+
+		policies, err := builder.RenderNodeGroupIAMPolicies(cfg, ng, false)
+		Expect(err).NotTo(HaveOccurred())
+		if expectAttached {
+			Expect(policies.ManagedPolicyARNs).To(ContainElement("arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"))
+		} else {
+			Expect(policies.ManagedPolicyARNs).NotTo(ContainElement("arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy"))
+		}
+	},
+		Entry("auto attaches it without withOIDC", api.AttachCNIPolicyAuto, false, nil, true),
+		Entry("auto does not attach it with withOIDC", api.AttachCNIPolicyAuto, true, nil, false),
+		Entry("auto does not attach it with attachPolicyARNs", api.AttachCNIPolicyAuto, false, []string{"arn:aws:iam::123456789012:policy/my-policy"}, false),
+		Entry("always attaches it with withOIDC", api.AttachCNIPolicyAlways, true, nil, true),
+		Entry("always attaches it with attachPolicyARNs", api.AttachCNIPolicyAlways, true, []string{"arn:aws:iam::123456789012:policy/my-policy"}, true),
+		Entry("never does not attach it without withOIDC", api.AttachCNIPolicyNever, false, nil, false),
+	)
+
+	It("returns nil for nodegroups using an existing instance role", func() {
+		ng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/my-role"
+		policies, err := builder.RenderNodeGroupIAMPolicies(cfg, ng, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies).To(BeNil())
+	})
+})
@@ -26,10 +26,13 @@ func NewLaunchTemplateFetcher(fetcher launchTemplateFetcher) *LaunchTemplateFetc
 	return &LaunchTemplateFetcher{fetcher: fetcher}
 }
 
-// Fetch fetches the specified launch template
+// Fetch fetches the specified launch template, referenced by ID or by name
 func (l *LaunchTemplateFetcher) Fetch(ctx context.Context, launchTemplate *api.LaunchTemplate) (*ec2types.ResponseLaunchTemplateData, error) {
-	input := &ec2.DescribeLaunchTemplateVersionsInput{
-		LaunchTemplateId: aws.String(launchTemplate.ID),
+	input := &ec2.DescribeLaunchTemplateVersionsInput{}
+	if launchTemplate.ID != "" {
+		input.LaunchTemplateId = aws.String(launchTemplate.ID)
+	} else {
+		input.LaunchTemplateName = aws.String(launchTemplate.Name)
 	}
 	if version := launchTemplate.Version; version != nil {
 		input.Versions = []string{*version}
@@ -42,6 +45,9 @@ func (l *LaunchTemplateFetcher) Fetch(ctx context.Context, launchTemplate *api.L
 		return nil, err
 	}
 	if len(output.LaunchTemplateVersions) != 1 {
+		if launchTemplate.ID == "" {
+			return nil, errors.Errorf("failed to find launch template with name %q", launchTemplate.Name)
+		}
 		return nil, errors.Errorf("failed to find launch template with ID %q", launchTemplate.ID)
 	}
 
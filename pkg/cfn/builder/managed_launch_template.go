@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/weaveworks/goformation/v4/cloudformation/cloudformation"
@@ -14,8 +15,12 @@ import (
 
 func (m *ManagedNodeGroupResourceSet) makeLaunchTemplateData(ctx context.Context) (*gfnec2.LaunchTemplate_LaunchTemplateData, error) {
 	mng := m.nodeGroup
+	tagSpecifications, err := makeTags(mng.NodeGroupBase, mng.Tags, m.clusterConfig.Metadata, false)
+	if err != nil {
+		return nil, err
+	}
 	launchTemplateData := &gfnec2.LaunchTemplate_LaunchTemplateData{
-		TagSpecifications: makeTags(mng.NodeGroupBase, m.clusterConfig.Metadata),
+		TagSpecifications: tagSpecifications,
 		MetadataOptions:   makeMetadataOptions(mng.NodeGroupBase),
 	}
 
@@ -36,8 +41,16 @@ func (m *ManagedNodeGroupResourceSet) makeLaunchTemplateData(ctx context.Context
 		launchTemplateData.ImageId = gfnt.NewString(mng.AMI)
 	}
 
-	if mng.SSH != nil && api.IsSetAndNonEmptyString(mng.SSH.PublicKeyName) {
-		launchTemplateData.KeyName = gfnt.NewString(*mng.SSH.PublicKeyName)
+	if mng.CapacityReservation != nil {
+		launchTemplateData.CapacityReservationSpecification = makeCapacityReservationSpecification(mng.CapacityReservation)
+	}
+
+	keyName, err := makeSSHKeyName(mng.NodeGroupBase, m.clusterConfig.Metadata.Name, m.newResource)
+	if err != nil {
+		return nil, err
+	}
+	if keyName != nil {
+		launchTemplateData.KeyName = keyName
 
 		if *mng.SSH.Allow {
 			vpcID := m.vpcImporter.VPC()
@@ -57,10 +70,10 @@ func (m *ManagedNodeGroupResourceSet) makeLaunchTemplateData(ctx context.Context
 		desc := "worker nodes in group " + m.nodeGroup.Name
 		efaSG := m.addEFASecurityGroup(m.vpcImporter.VPC(), m.clusterConfig.Metadata.Name, desc)
 		securityGroupIDs = append(securityGroupIDs, efaSG)
-		if err := buildNetworkInterfaces(ctx, launchTemplateData, mng.InstanceTypeList(), true, securityGroupIDs, m.ec2API); err != nil {
+		if err := buildNetworkInterfaces(ctx, launchTemplateData, mng.InstanceTypeList(), true, false, securityGroupIDs, m.ec2API); err != nil {
 			return nil, errors.Wrap(err, "couldn't build network interfaces for launch template data")
 		}
-		if mng.Placement == nil {
+		if mng.Placement == nil || mng.Placement.GroupName == "" {
 			groupName := m.newResource("NodeGroupPlacementGroup", &gfnec2.PlacementGroup{
 				Strategy: gfnt.NewString("cluster"),
 			})
@@ -73,13 +86,18 @@ func (m *ManagedNodeGroupResourceSet) makeLaunchTemplateData(ctx context.Context
 	}
 
 	if mng.EBSOptimized != nil {
+		if err := validateEBSOptimized(ctx, *mng.EBSOptimized, mng.InstanceTypeList(), m.ec2API); err != nil {
+			return nil, err
+		}
 		launchTemplateData.EbsOptimized = gfnt.NewBoolean(*mng.EBSOptimized)
 	}
 
 	if mng.Placement != nil {
-		launchTemplateData.Placement = &gfnec2.LaunchTemplate_Placement{
-			GroupName: gfnt.NewString(mng.Placement.GroupName),
+		placement, err := makePlacement(ctx, launchTemplateData.Placement, mng.Placement, m.ec2API)
+		if err != nil {
+			return nil, err
 		}
+		launchTemplateData.Placement = placement
 	}
 
 	if mng.EnableDetailedMonitoring != nil {
@@ -137,33 +155,74 @@ func makeSSHIngressRules(n *api.NodeGroupBase, vpcCIDR, description string) []gf
 	return sgIngressRules
 }
 
-func makeTags(ng *api.NodeGroupBase, meta *api.ClusterMeta) []gfnec2.LaunchTemplate_TagSpecification {
+// makeTags returns the tag specifications of the launch template of ng, tagging its resources with tags
+func makeTags(ng *api.NodeGroupBase, tags map[string]string, meta *api.ClusterMeta, disableInstanceNameTag bool) ([]gfnec2.LaunchTemplate_TagSpecification, error) {
+	instanceName, err := ng.InstanceNameTag(meta)
+	if err != nil {
+		return nil, err
+	}
 	cfnTags := []cloudformation.Tag{
 		{
 			Key:   gfnt.NewString("Name"),
-			Value: gfnt.NewString(generateNodeName(ng, meta)),
+			Value: gfnt.NewString(instanceName),
 		},
 	}
-	for k, v := range ng.Tags {
+	cfnTagKeys := []string{"Name"}
+	for k, v := range tags {
 		cfnTags = append(cfnTags, cloudformation.Tag{
 			Key:   gfnt.NewString(k),
 			Value: gfnt.NewString(v),
 		})
+		cfnTagKeys = append(cfnTagKeys, k)
 	}
 
 	var launchTemplateTagSpecs []gfnec2.LaunchTemplate_TagSpecification
-
-	launchTemplateTagSpecs = append(launchTemplateTagSpecs,
-		gfnec2.LaunchTemplate_TagSpecification{
-			ResourceType: gfnt.NewString("instance"),
-			Tags:         cfnTags,
-		}, gfnec2.LaunchTemplate_TagSpecification{
-			ResourceType: gfnt.NewString("volume"),
-			Tags:         cfnTags,
-		}, gfnec2.LaunchTemplate_TagSpecification{
-			ResourceType: gfnt.NewString("network-interface"),
-			Tags:         cfnTags,
+	for _, resourceType := range []string{
+		api.LaunchTemplateResourceTypeInstance,
+		api.LaunchTemplateResourceTypeVolume,
+		api.LaunchTemplateResourceTypeNetworkInterface,
+	} {
+		defaultTags, defaultTagKeys := cfnTags, cfnTagKeys
+		if disableInstanceNameTag && resourceType == api.LaunchTemplateResourceTypeInstance {
+			// the Name tag is the first of the default tags
+			defaultTags, defaultTagKeys = cfnTags[1:], cfnTagKeys[1:]
+		}
+		tags := mergeTags(defaultTags, defaultTagKeys, ng.LaunchTemplateTagSpecifications[resourceType])
+		if len(tags) == 0 {
+			continue
+		}
+		launchTemplateTagSpecs = append(launchTemplateTagSpecs, gfnec2.LaunchTemplate_TagSpecification{
+			ResourceType: gfnt.NewString(resourceType),
+			Tags:         tags,
 		})
+	}
+
+	return launchTemplateTagSpecs, nil
+}
 
-	return launchTemplateTagSpecs
+// mergeTags adds tags to defaultTags, whose keys are defaultTagKeys, replacing the default tags with the same keys
+func mergeTags(defaultTags []cloudformation.Tag, defaultTagKeys []string, tags map[string]string) []cloudformation.Tag {
+	if len(tags) == 0 {
+		return defaultTags
+	}
+
+	var merged []cloudformation.Tag
+	for i, key := range defaultTagKeys {
+		if _, ok := tags[key]; !ok {
+			merged = append(merged, defaultTags[i])
+		}
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		merged = append(merged, cloudformation.Tag{
+			Key:   gfnt.NewString(k),
+			Value: gfnt.NewString(tags[k]),
+		})
+	}
+	return merged
 }
@@ -10,7 +10,6 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/pkg/errors"
-	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfneks "github.com/weaveworks/goformation/v4/cloudformation/eks"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 	corev1 "k8s.io/api/core/v1"
@@ -61,7 +60,7 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources(ctx context.Context) error
 
 	var nodeRole *gfnt.Value
 	if m.nodeGroup.IAM.InstanceRoleARN == "" {
-		if err := createRole(m.resourceSet, m.clusterConfig.IAM, m.nodeGroup.IAM, true, m.forceAddCNIPolicy); err != nil {
+		if err := createRole(m.resourceSet, m.clusterConfig.IAM, m.nodeGroup.Name, m.nodeGroup.IAM, true, m.forceAddCNIPolicy); err != nil {
 			return err
 		}
 		nodeRole = gfnt.MakeFnGetAttString(cfnIAMInstanceRoleName, "Arn")
@@ -69,7 +68,7 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources(ctx context.Context) error
 		nodeRole = gfnt.NewString(NormalizeARN(m.nodeGroup.IAM.InstanceRoleARN))
 	}
 
-	subnets, err := AssignSubnets(ctx, m.nodeGroup.NodeGroupBase, m.vpcImporter, m.clusterConfig, m.ec2API)
+	subnets, err := AssignSubnets(ctx, m.nodeGroup.NodeGroupBase, m.nodeGroup.InstanceTypeList(), m.vpcImporter, m.clusterConfig, m.ec2API)
 	if err != nil {
 		return err
 	}
@@ -172,10 +171,11 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources(ctx context.Context) error
 		}
 		managedResource.InstanceTypes = gfnt.NewStringSlice(instanceTypes...)
 
-		ltRef := m.newResource("LaunchTemplate", &gfnec2.LaunchTemplate{
-			LaunchTemplateName: gfnt.MakeFnSubString(fmt.Sprintf("${%s}", gfnt.StackName)),
-			LaunchTemplateData: launchTemplateData,
-		})
+		launchTemplateResource, err := newLaunchTemplate(gfnt.MakeFnSubString(fmt.Sprintf("${%s}", gfnt.StackName)), launchTemplateData, m.nodeGroup.NodeGroupBase)
+		if err != nil {
+			return err
+		}
+		ltRef := m.newResource("LaunchTemplate", launchTemplateResource)
 		launchTemplate = &gfneks.Nodegroup_LaunchTemplateSpecification{
 			Id: ltRef,
 		}
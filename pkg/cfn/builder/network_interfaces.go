@@ -4,55 +4,59 @@ import (
 	"context"
 	"math"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
-
+	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 
 	"github.com/weaveworks/eksctl/pkg/awsapi"
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
 )
 
-func defaultNetworkInterface(securityGroups []*gfnt.Value, device, card int) gfnec2.LaunchTemplate_NetworkInterface {
-	return gfnec2.LaunchTemplate_NetworkInterface{
+func defaultNetworkInterface(securityGroups []*gfnt.Value, device, card int, ipv6Enabled bool) gfnec2.LaunchTemplate_NetworkInterface {
+	ni := gfnec2.LaunchTemplate_NetworkInterface{
 		// Explicitly un-setting this so that it doesn't get defaulted to true
 		AssociatePublicIpAddress: nil,
 		DeviceIndex:              gfnt.NewInteger(device),
 		Groups:                   gfnt.NewSlice(securityGroups...),
 		NetworkCardIndex:         gfnt.NewInteger(card),
 	}
+	if ipv6Enabled {
+		// nodes of IPv6 clusters register with the IPv6 address of their primary network interface
+		ni.Ipv6AddressCount = gfnt.NewInteger(1)
+	}
+	return ni
 }
 
+// buildNetworkInterfaces sets the network interfaces of the launch template. When EFA is enabled, it queries the
+// instance types for their maximum number of network cards and adds an EFA interface for each card that all of them
+// have, e.g. four interfaces for p4d.24xlarge, so that the instances get the full EFA bandwidth
 func buildNetworkInterfaces(
 	ctx context.Context,
 	launchTemplateData *gfnec2.LaunchTemplate_LaunchTemplateData,
 	instanceTypes []string,
 	efaEnabled bool,
+	ipv6Enabled bool,
 	securityGroups []*gfnt.Value,
 	ec2API awsapi.EC2,
 ) error {
-	firstNI := defaultNetworkInterface(securityGroups, 0, 0)
+	firstNI := defaultNetworkInterface(securityGroups, 0, 0, ipv6Enabled)
+	if efaEnabled && len(instanceTypes) == 0 {
+		// the instance types selected by instanceRequirements are only known to EC2 Auto Scaling, so their support
+		// for EFA cannot be checked
+		logger.Warning("not configuring EFA network interfaces as the instance types of the nodegroup are not known")
+		efaEnabled = false
+	}
 	if efaEnabled {
-		var instanceTypeList []ec2types.InstanceType
-		for _, it := range instanceTypes {
-			instanceTypeList = append(instanceTypeList, ec2types.InstanceType(it))
-		}
-		input := &ec2.DescribeInstanceTypesInput{
-			InstanceTypes: instanceTypeList,
-		}
-
-		info, err := ec2API.DescribeInstanceTypes(ctx, input)
+		info, err := instancetypes.InfoList(ctx, ec2API, instanceTypes)
 		if err != nil {
 			return errors.Wrapf(err, "couldn't retrieve instance type description for %v", instanceTypes)
 		}
 
 		var numEFAs = math.MaxFloat64
-		for _, it := range info.InstanceTypes {
-			networkInfo := it.NetworkInfo
-			numEFAs = math.Min(float64(aws.ToInt32(networkInfo.MaximumNetworkCards)), numEFAs)
-			if !aws.ToBool(networkInfo.EfaSupported) {
+		for _, it := range info {
+			numEFAs = math.Min(float64(it.MaxNetworkCards), numEFAs)
+			if !it.EFASupported {
 				return errors.Errorf("instance type %s does not support EFA", it.InstanceType)
 			}
 		}
@@ -64,7 +68,7 @@ func buildNetworkInterfaces(
 		// Due to ASG incompatibilities, we create each network card
 		// with its own device
 		for i := 1; i < int(numEFAs); i++ {
-			ni := defaultNetworkInterface(securityGroups, i, i)
+			ni := defaultNetworkInterface(securityGroups, i, i, ipv6Enabled)
 			ni.InterfaceType = gfnt.NewString("efa")
 			nis = append(nis, ni)
 		}
@@ -3,8 +3,13 @@ package builder
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 
 	"github.com/pkg/errors"
@@ -12,9 +17,11 @@ import (
 	gfncfn "github.com/weaveworks/goformation/v4/cloudformation/cloudformation"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kris-nova/logger"
 
+	"github.com/weaveworks/eksctl/pkg/ami"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
@@ -25,6 +32,18 @@ import (
 // MaximumTagNumber for ASGs as described here https://docs.aws.amazon.com/autoscaling/ec2/userguide/autoscaling-tagging.html
 const MaximumTagNumber = 50
 
+// Names of the template parameters holding the scaling configuration of the nodegroup's ASG, which allow
+// scaling the nodegroup by updating the stack parameters without re-rendering the template
+const (
+	NodeGroupMinSizeParameter         = "MinSize"
+	NodeGroupMaxSizeParameter         = "MaxSize"
+	NodeGroupDesiredCapacityParameter = "DesiredCapacity"
+)
+
+// NodeGroupImageIDParameter is the name of the template parameter holding the SSM parameter of the AMI of the
+// nodegroup, when the AMI is resolved by CloudFormation
+const NodeGroupImageIDParameter = "ImageId"
+
 // NodeGroupResourceSet stores the resource information of the nodegroup
 type NodeGroupResourceSet struct {
 	rs                *resourceSet
@@ -39,6 +58,11 @@ type NodeGroupResourceSet struct {
 	vpc                *gfnt.Value
 	vpcImporter        vpc.Importer
 	bootstrapper       nodebootstrap.Bootstrapper
+
+	launchTemplateID      string
+	launchTemplateVersion string
+
+	reservedTagKeys []string
 }
 
 // NewNodeGroupResourceSet returns a resource set for a nodegroup embedded in a cluster config
@@ -58,8 +82,15 @@ func NewNodeGroupResourceSet(ec2API awsapi.EC2, iamAPI awsapi.IAM, spec *api.Clu
 // AddAllResources adds all the information about the nodegroup to the resource set
 func (n *NodeGroupResourceSet) AddAllResources(ctx context.Context) error {
 
-	if n.clusterSpec.IPv6Enabled() {
-		return errors.New("unmanaged nodegroups are not supported with IPv6 clusters")
+	if n.clusterSpec.IPv6Enabled() && n.spec.AMIFamily != api.NodeImageFamilyAmazonLinux2 {
+		return fmt.Errorf("unmanaged nodegroups in IPv6 clusters are only supported with the %s AMI family", api.NodeImageFamilyAmazonLinux2)
+	}
+
+	// an unknown strategy would only be rejected when the stack is deployed
+	if n.spec.InstancesDistribution != nil {
+		if err := api.ValidateSpotAllocationStrategy(n.spec.InstancesDistribution.SpotAllocationStrategy); err != nil {
+			return err
+		}
 	}
 
 	n.rs.template.Description = fmt.Sprintf(
@@ -117,22 +148,28 @@ func (n *NodeGroupResourceSet) AddAllResources(ctx context.Context) error {
 	if err := n.addResourcesForIAM(ctx); err != nil {
 		return err
 	}
-	n.addResourcesForSecurityGroups()
+	if err := n.addResourcesForSecurityGroups(); err != nil {
+		return err
+	}
 
 	return n.addResourcesForNodeGroup(ctx)
 }
 
-func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
+func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() error {
 	for _, id := range n.spec.SecurityGroups.AttachIDs {
 		n.securityGroups = append(n.securityGroups, gfnt.NewString(id))
 	}
 
 	if api.IsEnabled(n.spec.SecurityGroups.WithShared) {
-		n.securityGroups = append(n.securityGroups, n.vpcImporter.SharedNodeSecurityGroup())
+		sharedNodeSG, err := n.vpcImporter.SharedNodeSecurityGroup()
+		if err != nil {
+			return errors.Wrapf(err, "nodegroup %q", n.spec.Name)
+		}
+		n.securityGroups = append(n.securityGroups, sharedNodeSG)
 	}
 
 	if api.IsDisabled(n.spec.SecurityGroups.WithLocal) {
-		return
+		return nil
 	}
 
 	desc := "worker nodes in group " + n.spec.Name
@@ -156,14 +193,30 @@ func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
 		n.securityGroups = append(n.securityGroups, efaSG)
 	}
 
+	if api.IsDisabled(n.clusterSpec.VPC.ManageControlPlaneSecurityGroupRules) {
+		// the control plane security group is managed outside of eksctl, which allows the traffic with the nodes
+		return nil
+	}
+
+	nodePorts := makeControlPlaneNodePorts(n.spec.SecurityGroups)
 	n.newResource("EgressInterCluster", &gfnec2.SecurityGroupEgress{
 		GroupId:                    refControlPlaneSG,
 		DestinationSecurityGroupId: refNodeGroupLocalSG,
-		Description:                gfnt.NewString("Allow control plane to communicate with " + desc + " (kubelet and workload TCP ports)"),
+		Description:                gfnt.NewString("Allow control plane to communicate with " + desc + " (" + nodePorts.description + ")"),
 		IpProtocol:                 sgProtoTCP,
-		FromPort:                   sgMinNodePort,
-		ToPort:                     sgMaxNodePort,
+		FromPort:                   nodePorts.fromPort,
+		ToPort:                     nodePorts.toPort,
 	})
+	for _, port := range nodePorts.additionalPorts {
+		n.newResource(fmt.Sprintf("EgressInterClusterPort%d", port), &gfnec2.SecurityGroupEgress{
+			GroupId:                    refControlPlaneSG,
+			DestinationSecurityGroupId: refNodeGroupLocalSG,
+			Description:                gfnt.NewString(fmt.Sprintf("Allow control plane to communicate with %s (port %d)", desc, port)),
+			IpProtocol:                 sgProtoTCP,
+			FromPort:                   gfnt.NewInteger(port),
+			ToPort:                     gfnt.NewInteger(port),
+		})
+	}
 	n.newResource("EgressInterClusterAPI", &gfnec2.SecurityGroupEgress{
 		GroupId:                    refControlPlaneSG,
 		DestinationSecurityGroupId: refNodeGroupLocalSG,
@@ -180,16 +233,44 @@ func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
 		FromPort:              sgPortHTTPS,
 		ToPort:                sgPortHTTPS,
 	})
+	return nil
+}
+
+// controlPlaneNodePorts are the ports the control plane can reach the nodes of a nodegroup on, other than HTTPS
+type controlPlaneNodePorts struct {
+	fromPort, toPort *gfnt.Value
+	description      string
+	additionalPorts  []int
+}
+
+// makeControlPlaneNodePorts returns the kubelet and workload ports from 1025, or only the kubelet port and the
+// additional ports when the rules are restricted. The rules keep their logical IDs either way, so that restricting
+// them updates the rules of an existing nodegroup without replacing its security group
+func makeControlPlaneNodePorts(sgs *api.NodeGroupSGs) controlPlaneNodePorts {
+	if sgs == nil || !api.IsEnabled(sgs.RestrictControlPlaneRules) {
+		return controlPlaneNodePorts{
+			fromPort:    sgMinNodePort,
+			toPort:      sgMaxNodePort,
+			description: "kubelet and workload TCP ports",
+		}
+	}
+	return controlPlaneNodePorts{
+		fromPort:        sgPortKubelet,
+		toPort:          sgPortKubelet,
+		description:     "kubelet",
+		additionalPorts: sgs.AdditionalControlPlanePorts,
+	}
 }
 
 func makeNodeIngressRules(ng *api.NodeGroupBase, controlPlaneSG *gfnt.Value, vpcCIDR, description string) []gfnec2.SecurityGroup_Ingress {
+	nodePorts := makeControlPlaneNodePorts(ng.SecurityGroups)
 	ingressRules := []gfnec2.SecurityGroup_Ingress{
 		{
 			SourceSecurityGroupId: controlPlaneSG,
-			Description:           gfnt.NewString(fmt.Sprintf("[IngressInterCluster] Allow %s to communicate with control plane (kubelet and workload TCP ports)", description)),
+			Description:           gfnt.NewString(fmt.Sprintf("[IngressInterCluster] Allow %s to communicate with control plane (%s)", description, nodePorts.description)),
 			IpProtocol:            sgProtoTCP,
-			FromPort:              sgMinNodePort,
-			ToPort:                sgMaxNodePort,
+			FromPort:              nodePorts.fromPort,
+			ToPort:                nodePorts.toPort,
 		},
 		{
 			SourceSecurityGroupId: controlPlaneSG,
@@ -199,6 +280,15 @@ func makeNodeIngressRules(ng *api.NodeGroupBase, controlPlaneSG *gfnt.Value, vpc
 			ToPort:                sgPortHTTPS,
 		},
 	}
+	for _, port := range nodePorts.additionalPorts {
+		ingressRules = append(ingressRules, gfnec2.SecurityGroup_Ingress{
+			SourceSecurityGroupId: controlPlaneSG,
+			Description:           gfnt.NewString(fmt.Sprintf("[IngressInterClusterPort%d] Allow %s to communicate with control plane (port %d)", port, description, port)),
+			IpProtocol:            sgProtoTCP,
+			FromPort:              gfnt.NewInteger(port),
+			ToPort:                gfnt.NewInteger(port),
+		})
+	}
 
 	return append(ingressRules, makeSSHIngressRules(ng, vpcCIDR, description)...)
 }
@@ -208,6 +298,19 @@ func (n *NodeGroupResourceSet) RenderJSON() ([]byte, error) {
 	return n.rs.renderJSON()
 }
 
+// RenderJSONForSpecification returns the rendered JSON, returning an error if the template uses
+// resource types or properties that are not supported by the given CloudFormation resource specification
+func (n *NodeGroupResourceSet) RenderJSONForSpecification(spec *ResourceSpecification) ([]byte, error) {
+	templateBody, err := n.RenderJSON()
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.ValidateTemplate(templateBody); err != nil {
+		return nil, err
+	}
+	return templateBody, nil
+}
+
 // Template returns the CloudFormation template
 func (n *NodeGroupResourceSet) Template() gfn.Template {
 	return *n.rs.template
@@ -217,33 +320,150 @@ func (n *NodeGroupResourceSet) newResource(name string, resource gfn.Resource) *
 	return n.rs.newResource(name, resource)
 }
 
+func (n *NodeGroupResourceSet) newScalingParameter(name, description string, value int) *gfnt.Value {
+	return n.rs.newParameter(name, gfn.Parameter{
+		Type:        "Number",
+		Description: description,
+		Default:     strconv.Itoa(value),
+	})
+}
+
+// addImageIDParameter adds the parameter referenced by the ImageId of the launch template when the AMI is resolved by
+// CloudFormation, which defaults to the SSM parameter the SSM resolver reads the AMI from
+func (n *NodeGroupResourceSet) addImageIDParameter(ctx context.Context) (*gfnt.Value, error) {
+	parameterName, err := ami.MakeSSMParameterNameForInstanceType(ctx, n.ec2API, n.clusterSpec.Metadata.Version, api.SelectInstanceType(n.spec), n.spec.AMIFamily, n.spec.AMIReleaseVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to determine the SSM parameter of the AMI")
+	}
+	return n.rs.newParameter(NodeGroupImageIDParameter, gfn.Parameter{
+		Type:        "AWS::SSM::Parameter::Value<AWS::EC2::Image::Id>",
+		Description: "SSM parameter holding the AMI of the nodegroup",
+		Default:     parameterName,
+	}), nil
+}
+
+// addScalingParameters adds the parameters referenced by the MinSize, MaxSize and DesiredCapacity properties of the ASG,
+// skipping those that are not set
+func (n *NodeGroupResourceSet) addScalingParameters() *nodeGroupScaling {
+	scaling := &nodeGroupScaling{}
+	if n.spec.MinSize != nil {
+		scaling.MinSize = n.newScalingParameter(NodeGroupMinSizeParameter, "Minimum size of the nodegroup", *n.spec.MinSize)
+	}
+	if n.spec.MaxSize != nil {
+		scaling.MaxSize = n.newScalingParameter(NodeGroupMaxSizeParameter, "Maximum size of the nodegroup", *n.spec.MaxSize)
+	}
+	desiredCapacity := n.spec.DesiredCapacity
+	if desiredCapacity == nil {
+		desiredCapacity = n.spec.MinSize
+	}
+	if desiredCapacity != nil {
+		scaling.DesiredCapacity = n.newScalingParameter(NodeGroupDesiredCapacityParameter, "Desired capacity of the nodegroup", *desiredCapacity)
+	}
+	return scaling
+}
+
 func (n *NodeGroupResourceSet) addResourcesForNodeGroup(ctx context.Context) error {
+	instanceName, err := n.spec.InstanceNameTag(n.clusterSpec.Metadata)
+	if err != nil {
+		return err
+	}
+
+	tags := n.reservedASGTags(instanceName)
+	n.setReservedTagKeys(tags)
+
 	launchTemplateName := gfnt.MakeFnSubString(fmt.Sprintf("${%s}", gfnt.StackName))
+	// the ASG of a nodegroup using an existing launch template references it, see nodeGroupResource
+	if n.spec.LaunchTemplate == nil {
+		if err := n.addLaunchTemplate(ctx, launchTemplateName); err != nil {
+			return errors.Wrap(err, "could not add resources for nodegroup")
+		}
+	} else if err := n.checkLaunchTemplateInstanceProfile(ctx); err != nil {
+		return err
+	}
+
+	vpcZoneIdentifier, err := AssignSubnets(ctx, n.spec.NodeGroupBase, n.spec.InstanceTypeList(), n.vpcImporter, n.clusterSpec, n.ec2API)
+	if err != nil {
+		return err
+	}
+
+	if api.IsEnabled(n.spec.PropagateASGTags) {
+		clusterTags, err := generateClusterAutoscalerTags(n.spec)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, clusterTags...)
+		if len(tags) > MaximumTagNumber {
+			return fmt.Errorf("number of tags is exceeding the configured amount %d, was: %d. Due to desiredCapacity==0 we added an extra %d number of tags to ensure the nodegroup is scaled correctly", MaximumTagNumber, len(tags), len(clusterTags))
+		}
+	}
+
+	disableInstanceTagPropagation(tags, n.spec.DisableDefaultInstanceTags)
+
+	asg := nodeGroupResource(launchTemplateName, vpcZoneIdentifier, tags, n.addScalingParameters(), n.spec)
+	refASG := n.newResource("NodeGroup", asg)
+
+	if n.spec.WarmPool != nil {
+		n.newResource("NodeGroupWarmPool", warmPoolResource(refASG, n.spec.WarmPool))
+	}
+
+	return nil
+}
+
+// addLaunchTemplate adds the launch template of the nodegroup, along with its outputs
+func (n *NodeGroupResourceSet) addLaunchTemplate(ctx context.Context, launchTemplateName *gfnt.Value) error {
 	launchTemplateData, err := newLaunchTemplateData(ctx, n)
 	if err != nil {
-		return errors.Wrap(err, "could not add resources for nodegroup")
+		return err
 	}
 
-	if n.spec.SSH != nil && api.IsSetAndNonEmptyString(n.spec.SSH.PublicKeyName) {
-		launchTemplateData.KeyName = gfnt.NewString(*n.spec.SSH.PublicKeyName)
+	keyName, err := makeSSHKeyName(n.spec.NodeGroupBase, n.clusterSpec.Metadata.Name, n.newResource)
+	if err != nil {
+		return err
+	}
+	if keyName != nil {
+		launchTemplateData.KeyName = keyName
 	}
 
 	launchTemplateData.BlockDeviceMappings = makeBlockDeviceMappings(n.spec.NodeGroupBase)
 
-	n.newResource("NodeGroupLaunchTemplate", &gfnec2.LaunchTemplate{
-		LaunchTemplateName: launchTemplateName,
-		LaunchTemplateData: launchTemplateData,
+	launchTemplate, err := newLaunchTemplate(launchTemplateName, launchTemplateData, n.spec.NodeGroupBase)
+	if err != nil {
+		return err
+	}
+	n.newResource("NodeGroupLaunchTemplate", launchTemplate)
+	n.rs.defineOutput(outputs.NodeGroupLaunchTemplateID, gfnt.MakeRef("NodeGroupLaunchTemplate"), false, func(v string) error {
+		n.launchTemplateID = v
+		return nil
 	})
+	n.rs.defineOutputFromAtt(outputs.NodeGroupLaunchTemplateVersion, "NodeGroupLaunchTemplate", "LatestVersionNumber", false, func(v string) error {
+		n.launchTemplateVersion = v
+		return nil
+	})
+	return nil
+}
 
-	vpcZoneIdentifier, err := AssignSubnets(ctx, n.spec.NodeGroupBase, n.vpcImporter, n.clusterSpec, n.ec2API)
+// checkLaunchTemplateInstanceProfile checks that the existing launch template of the nodegroup launches instances with
+// iam.instanceProfileARN, as the role of that profile is the one authorized to join the cluster
+func (n *NodeGroupResourceSet) checkLaunchTemplateInstanceProfile(ctx context.Context) error {
+	launchTemplateData, err := NewLaunchTemplateFetcher(n.ec2API).Fetch(ctx, n.spec.LaunchTemplate)
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "fetching the launch template of nodegroup %q", n.spec.Name)
 	}
+	profileARN := n.spec.IAM.InstanceProfileARN
+	if profile := launchTemplateData.IamInstanceProfile; profile != nil {
+		if aws.ToString(profile.Arn) == profileARN || (profile.Name != nil && strings.HasSuffix(profileARN, "/"+*profile.Name)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the launch template of nodegroup %q must use the instance profile set in iam.instanceProfileARN (%s), otherwise its nodes cannot join the cluster", n.spec.Name, profileARN)
+}
 
+// reservedASGTags returns the tags that eksctl sets on the ASG of the nodegroup, which take precedence over user tags
+func (n *NodeGroupResourceSet) reservedASGTags(instanceName string) []map[string]interface{} {
 	tags := []map[string]interface{}{
 		{
 			"Key":               "Name",
-			"Value":             generateNodeName(n.spec.NodeGroupBase, n.clusterSpec.Metadata),
+			"Value":             instanceName,
 			"PropagateAtLaunch": "true",
 		},
 		{
@@ -255,33 +475,58 @@ func (n *NodeGroupResourceSet) addResourcesForNodeGroup(ctx context.Context) err
 	if api.IsEnabled(n.spec.IAM.WithAddonPolicies.AutoScaler) {
 		tags = append(tags,
 			map[string]interface{}{
-				"Key":               "k8s.io/cluster-autoscaler/enabled",
+				"Key":               api.ClusterAutoscalerTagPrefix + "enabled",
 				"Value":             "true",
 				"PropagateAtLaunch": "true",
 			},
 			map[string]interface{}{
-				"Key":               "k8s.io/cluster-autoscaler/" + n.clusterSpec.Metadata.Name,
+				"Key":               api.ClusterAutoscalerTagPrefix + n.clusterSpec.Metadata.Name,
 				"Value":             "owned",
 				"PropagateAtLaunch": "true",
 			},
 		)
 	}
+	return tags
+}
 
-	if api.IsEnabled(n.spec.PropagateASGTags) {
-		clusterTags, err := generateClusterAutoscalerTags(n.spec)
-		if err != nil {
-			return err
-		}
-		tags = append(tags, clusterTags...)
-		if len(tags) > MaximumTagNumber {
-			return fmt.Errorf("number of tags is exceeding the configured amount %d, was: %d. Due to desiredCapacity==0 we added an extra %d number of tags to ensure the nodegroup is scaled correctly", MaximumTagNumber, len(tags), len(clusterTags))
+// setReservedTagKeys records the keys of the tags set by eksctl on the ASG, warning about the nodegroup tags,
+// including those inherited from metadata.tags, that use them
+func (n *NodeGroupResourceSet) setReservedTagKeys(reservedTags []map[string]interface{}) {
+	n.reservedTagKeys = nil
+	for _, tag := range reservedTags {
+		key := tag["Key"].(string)
+		if _, ok := n.spec.Tags[key]; ok {
+			logger.Warning("ignoring tag %q of nodegroup %q as it is set by eksctl", key, n.spec.Name)
 		}
+		n.reservedTagKeys = append(n.reservedTagKeys, key)
 	}
+}
 
-	asg := nodeGroupResource(launchTemplateName, vpcZoneIdentifier, tags, n.spec)
-	n.newResource("NodeGroup", asg)
+// WithoutReservedTags returns a copy of tags without the keys set by eksctl on the ASG of the nodegroup, as stack tags
+// are propagated to the ASG and CloudFormation rejects duplicate tag keys
+func (n *NodeGroupResourceSet) WithoutReservedTags(tags map[string]string) map[string]string {
+	stackTags := make(map[string]string, len(tags))
+	for k, v := range tags {
+		stackTags[k] = v
+	}
+	for _, key := range n.reservedTagKeys {
+		delete(stackTags, key)
+	}
+	return stackTags
+}
 
-	return nil
+// disableInstanceTagPropagation keeps the tags of the ASG whose keys are in disabledKeys from being propagated to
+// its instances
+func disableInstanceTagPropagation(tags []map[string]interface{}, disabledKeys []string) {
+	if len(disabledKeys) == 0 {
+		return
+	}
+	disabled := sets.NewString(disabledKeys...)
+	for _, tag := range tags {
+		if disabled.Has(tag["Key"].(string)) {
+			tag["PropagateAtLaunch"] = "false"
+		}
+	}
 }
 
 func generateClusterAutoscalerTags(spec *api.NodeGroup) ([]map[string]interface{}, error) {
@@ -292,7 +537,7 @@ func generateClusterAutoscalerTags(spec *api.NodeGroup) ([]map[string]interface{
 	for k, v := range spec.Labels {
 		duplicates[k] = v
 		result = append(result, map[string]interface{}{
-			"Key":               "k8s.io/cluster-autoscaler/node-template/label/" + k,
+			"Key":               api.ClusterAutoscalerTagPrefix + "node-template/label/" + k,
 			"Value":             v,
 			"PropagateAtLaunch": "true",
 		})
@@ -305,7 +550,7 @@ func generateClusterAutoscalerTags(spec *api.NodeGroup) ([]map[string]interface{
 		}
 		duplicates[taint.Key] = taint.Value
 		result = append(result, map[string]interface{}{
-			"Key":               "k8s.io/cluster-autoscaler/node-template/taints/" + taint.Key,
+			"Key":               api.ClusterAutoscalerTagPrefix + "node-template/taints/" + taint.Key,
 			"Value":             taint.Value,
 			"PropagateAtLaunch": "true",
 		})
@@ -313,49 +558,268 @@ func generateClusterAutoscalerTags(spec *api.NodeGroup) ([]map[string]interface{
 	return result, nil
 }
 
-// generateNodeName formulates the name based on the configuration in input
-func generateNodeName(ng *api.NodeGroupBase, meta *api.ClusterMeta) string {
-	var nameParts []string
-	if ng.InstancePrefix != "" {
-		nameParts = append(nameParts, ng.InstancePrefix, "-")
-	}
-	// this overrides the default naming convention
-	if ng.InstanceName != "" {
-		nameParts = append(nameParts, ng.InstanceName)
-	} else {
-		nameParts = append(nameParts, fmt.Sprintf("%s-%s-Node", meta.Name, ng.Name))
-	}
-	return strings.Join(nameParts, "")
+// AssignSubnets subnets based on the specified availability zones
+func AssignSubnets(ctx context.Context, spec *api.NodeGroupBase, instanceTypes []string, vpcImporter vpc.Importer, clusterSpec *api.ClusterConfig, ec2API awsapi.EC2) (*gfnt.Value, error) {
+	subnets, _, err := AssignSubnetsResolved(ctx, spec, instanceTypes, vpcImporter, clusterSpec, ec2API)
+	return subnets, err
 }
 
-// AssignSubnets subnets based on the specified availability zones
-func AssignSubnets(ctx context.Context, spec *api.NodeGroupBase, vpcImporter vpc.Importer, clusterSpec *api.ClusterConfig, ec2API awsapi.EC2) (*gfnt.Value, error) {
+// AssignSubnetsResolved is like AssignSubnets, but also returns the IDs of the subnets the nodegroup will use. When
+// the subnets are imported from the cluster stack, the IDs are resolved from the VPC of clusterSpec, and are nil if
+// it does not list any
+func AssignSubnetsResolved(ctx context.Context, spec *api.NodeGroupBase, instanceTypes []string, vpcImporter vpc.Importer, clusterSpec *api.ClusterConfig, ec2API awsapi.EC2) (*gfnt.Value, []string, error) {
 	// Currently, goformation type system doesn't allow specifying `VPCZoneIdentifier: { "Fn::ImportValue": ... }`,
 	// and tags don't have `PropagateAtLaunch` field, so we have a custom method here until this gets resolved
 
-	if len(spec.AvailabilityZones) > 0 || len(spec.Subnets) > 0 || api.IsEnabled(spec.EFAEnabled) {
+	if spec.OutpostARN != "" {
+		subnetIDs, err := selectOutpostSubnets(ctx, spec, clusterSpec, ec2API)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gfnt.NewStringSlice(subnetIDs...), subnetIDs, nil
+	}
+
+	if len(spec.AvailabilityZones) > 0 || len(spec.AvailabilityZoneIDs) > 0 || len(spec.Subnets) > 0 || api.IsEnabled(spec.EFAEnabled) {
 		subnets := clusterSpec.VPC.Subnets.Public
 		typ := "public"
 		if spec.PrivateNetworking {
 			subnets = clusterSpec.VPC.Subnets.Private
 			typ = "private"
 		}
-		subnetIDs, err := vpc.SelectNodeGroupSubnets(ctx, spec.AvailabilityZones, spec.Subnets, subnets, ec2API, clusterSpec.VPC.ID)
-		if api.IsEnabled(spec.EFAEnabled) && len(subnetIDs) > 1 {
-			subnetIDs = []string{subnetIDs[0]}
-			logger.Info("EFA requires all nodes be in a single subnet, arbitrarily choosing one: %s", subnetIDs)
+		subnetIDs, err := vpc.SelectNodeGroupSubnets(ctx, spec.AvailabilityZones, spec.AvailabilityZoneIDs, spec.Subnets, subnets, ec2API, clusterSpec.VPC.ID)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "couldn't find %s subnets", typ)
 		}
-		return gfnt.NewStringSlice(subnetIDs...), errors.Wrapf(err, "couldn't find %s subnets", typ)
+		if err := checkNodeGroupZones(spec, subnetIDs, subnets, clusterSpec); err != nil {
+			return nil, nil, err
+		}
+		// the instance types selected by instanceRequirements are not known, see buildNetworkInterfaces
+		if api.IsEnabled(spec.EFAEnabled) && len(instanceTypes) > 0 {
+			if len(subnetIDs) == 0 {
+				subnetIDs = subnets.WithIDs()
+				sort.Strings(subnetIDs)
+			}
+			subnetID, err := selectEFASubnet(ctx, instanceTypes, subnetIDs, subnets, ec2API)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(subnetIDs) > 1 {
+				logger.Info("EFA requires all nodes be in a single subnet, choosing one in an availability zone that offers the instance type(s): %s", subnetID)
+			}
+			subnetIDs = []string{subnetID}
+		}
+		return gfnt.NewStringSlice(subnetIDs...), subnetIDs, nil
 	}
 
-	var subnets *gfnt.Value
+	var (
+		subnets       *gfnt.Value
+		subnetMapping api.AZSubnetMapping
+	)
 	if spec.PrivateNetworking {
 		subnets = vpcImporter.SubnetsPrivate()
+		if clusterSpec.VPC.Subnets != nil {
+			subnetMapping = clusterSpec.VPC.Subnets.Private
+		}
 	} else {
 		subnets = vpcImporter.SubnetsPublic()
+		if clusterSpec.VPC.Subnets != nil {
+			subnetMapping = clusterSpec.VPC.Subnets.Public
+		}
 	}
 
-	return subnets, nil
+	if len(clusterSpec.LocalZones) > 0 {
+		// nodegroups that list neither zones nor subnets are only placed in the availability zones of the cluster
+		var subnetIDs []string
+		for _, s := range subnetMapping {
+			if s.ID != "" && !clusterSpec.IsLocalZone(s.AZ) {
+				subnetIDs = append(subnetIDs, s.ID)
+			}
+		}
+		if len(subnetIDs) == 0 {
+			return nil, nil, fmt.Errorf("couldn't find the subnets of the availability zones of the cluster for nodegroup %q, which lists no zones or subnets", spec.Name)
+		}
+		sort.Strings(subnetIDs)
+		return gfnt.NewStringSlice(subnetIDs...), subnetIDs, nil
+	}
+
+	subnetIDs := subnetMapping.WithIDs()
+	if len(subnetIDs) == 0 {
+		return subnets, nil, nil
+	}
+	sort.Strings(subnetIDs)
+	return subnets, subnetIDs, nil
+}
+
+// checkNodeGroupZones returns an error if the zones or subnets of a nodegroup are in both Local Zones or Wavelength
+// Zones and availability zones of the cluster, as the instance types, EBS volume types and latency of these zones
+// differ
+func checkNodeGroupZones(spec *api.NodeGroupBase, subnetIDs []string, subnets api.AZSubnetMapping, clusterSpec *api.ClusterConfig) error {
+	if len(clusterSpec.LocalZones) == 0 {
+		return nil
+	}
+
+	zones := sets.NewString(spec.AvailabilityZones...)
+	selected := sets.NewString(subnetIDs...)
+	for _, s := range subnets {
+		if s.ID != "" && selected.Has(s.ID) {
+			zones.Insert(s.AZ)
+		}
+	}
+
+	var availabilityZones, localZones []string
+	for _, zone := range zones.List() {
+		if clusterSpec.IsLocalZone(zone) {
+			localZones = append(localZones, zone)
+		} else {
+			availabilityZones = append(availabilityZones, zone)
+		}
+	}
+	if len(localZones) > 0 && len(availabilityZones) > 0 {
+		return fmt.Errorf("nodegroup %q cannot be placed in both Local Zones or Wavelength Zones %v and availability zones %v; please create a nodegroup for each kind of zone",
+			spec.Name, localZones, availabilityZones)
+	}
+	return nil
+}
+
+// selectOutpostSubnets returns the subnets on the nodegroup's Outpost, out of the subnets selected by its
+// availability zones or subnets, or out of all public or private subnets of the cluster when neither is set
+func selectOutpostSubnets(ctx context.Context, spec *api.NodeGroupBase, clusterSpec *api.ClusterConfig, ec2API awsapi.EC2) ([]string, error) {
+	subnets := clusterSpec.VPC.Subnets.Public
+	typ := "public"
+	if spec.PrivateNetworking {
+		subnets = clusterSpec.VPC.Subnets.Private
+		typ = "private"
+	}
+	subnetIDs, err := vpc.SelectNodeGroupSubnets(ctx, spec.AvailabilityZones, spec.AvailabilityZoneIDs, spec.Subnets, subnets, ec2API, clusterSpec.VPC.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't find %s subnets", typ)
+	}
+	if len(subnetIDs) == 0 {
+		subnetIDs = subnets.WithIDs()
+		sort.Strings(subnetIDs)
+	}
+	if len(subnetIDs) == 0 {
+		return nil, fmt.Errorf("no %s subnets found for nodegroup %q on Outpost %q", typ, spec.Name, spec.OutpostARN)
+	}
+
+	output, err := ec2API.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: subnetIDs,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't describe subnets %v", subnetIDs)
+	}
+	onOutpost := sets.NewString()
+	for _, s := range output.Subnets {
+		if aws.ToString(s.OutpostArn) == spec.OutpostARN {
+			onOutpost.Insert(aws.ToString(s.SubnetId))
+		}
+	}
+
+	var outpostSubnetIDs []string
+	for _, subnetID := range subnetIDs {
+		if onOutpost.Has(subnetID) {
+			outpostSubnetIDs = append(outpostSubnetIDs, subnetID)
+		}
+	}
+	if len(outpostSubnetIDs) == 0 {
+		return nil, fmt.Errorf("none of the %s subnets considered for nodegroup %q (%s) are on Outpost %q; set subnets to subnets of the VPC on the Outpost",
+			typ, spec.Name, strings.Join(subnetIDs, ", "), spec.OutpostARN)
+	}
+	return outpostSubnetIDs, nil
+}
+
+// selectEFASubnet returns the first of subnetIDs located in an availability zone that offers all instanceTypes,
+// as EFA-enabled instances can only be launched in a single subnet
+func selectEFASubnet(ctx context.Context, instanceTypes, subnetIDs []string, subnets api.AZSubnetMapping, ec2API awsapi.EC2) (string, error) {
+	efaAZs, err := instanceTypeOfferingAZs(ctx, instanceTypes, ec2API)
+	if err != nil {
+		return "", err
+	}
+	subnetAZs, err := subnetAvailabilityZones(ctx, subnetIDs, subnets, ec2API)
+	if err != nil {
+		return "", err
+	}
+
+	nodeGroupAZs := sets.NewString()
+	for _, subnetID := range subnetIDs {
+		az := subnetAZs[subnetID]
+		if efaAZs.Has(az) {
+			return subnetID, nil
+		}
+		nodeGroupAZs.Insert(az)
+	}
+	return "", fmt.Errorf("none of the nodegroup's availability zones (%s) offer the EFA-enabled instance type(s) %s; availability zones that offer them: %s",
+		strings.Join(nodeGroupAZs.List(), ", "), strings.Join(instanceTypes, ", "), strings.Join(efaAZs.List(), ", "))
+}
+
+// instanceTypeOfferingAZs returns the availability zones that offer all instanceTypes
+func instanceTypeOfferingAZs(ctx context.Context, instanceTypes []string, ec2API awsapi.EC2) (sets.String, error) {
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: ec2types.LocationTypeAvailabilityZone,
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: instanceTypes,
+			},
+		},
+	}
+
+	azsByInstanceType := map[string]sets.String{}
+	for _, it := range instanceTypes {
+		azsByInstanceType[it] = sets.NewString()
+	}
+	paginator := ec2.NewDescribeInstanceTypeOfferingsPaginator(ec2API, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't retrieve instance type offerings for %v", instanceTypes)
+		}
+		for _, offering := range output.InstanceTypeOfferings {
+			if azs, ok := azsByInstanceType[string(offering.InstanceType)]; ok {
+				azs.Insert(aws.ToString(offering.Location))
+			}
+		}
+	}
+
+	var azs sets.String
+	for _, it := range instanceTypes {
+		if azs == nil {
+			azs = azsByInstanceType[it]
+		} else {
+			azs = azs.Intersection(azsByInstanceType[it])
+		}
+	}
+	return azs, nil
+}
+
+// subnetAvailabilityZones returns the availability zones of subnetIDs keyed by subnet ID, using the subnet mapping
+// where possible and describing the remaining subnets
+func subnetAvailabilityZones(ctx context.Context, subnetIDs []string, subnets api.AZSubnetMapping, ec2API awsapi.EC2) (map[string]string, error) {
+	subnetAZs := map[string]string{}
+	for _, s := range subnets {
+		subnetAZs[s.ID] = s.AZ
+	}
+
+	var unknownSubnetIDs []string
+	for _, subnetID := range subnetIDs {
+		if subnetAZs[subnetID] == "" {
+			unknownSubnetIDs = append(unknownSubnetIDs, subnetID)
+		}
+	}
+	if len(unknownSubnetIDs) == 0 {
+		return subnetAZs, nil
+	}
+
+	output, err := ec2API.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: unknownSubnetIDs,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't describe subnets %v", unknownSubnetIDs)
+	}
+	for _, s := range output.Subnets {
+		subnetAZs[aws.ToString(s.SubnetId)] = aws.ToString(s.AvailabilityZone)
+	}
+	return subnetAZs, nil
 }
 
 // GetAllOutputs collects all outputs of the nodegroup
@@ -363,27 +827,59 @@ func (n *NodeGroupResourceSet) GetAllOutputs(stack cfn.Stack) error {
 	return n.rs.GetAllOutputs(stack)
 }
 
+// LaunchTemplateID returns the ID of the launch template of the nodegroup, once collected from the stack outputs
+func (n *NodeGroupResourceSet) LaunchTemplateID() string {
+	return n.launchTemplateID
+}
+
+// LaunchTemplateVersion returns the latest version of the launch template of the nodegroup, once collected from the
+// stack outputs
+func (n *NodeGroupResourceSet) LaunchTemplateVersion() string {
+	return n.launchTemplateVersion
+}
+
 func newLaunchTemplateData(ctx context.Context, n *NodeGroupResourceSet) (*gfnec2.LaunchTemplate_LaunchTemplateData, error) {
 	userData, err := n.bootstrapper.UserData()
 	if err != nil {
 		return nil, err
 	}
 
+	disableInstanceNameTag := sets.NewString(n.spec.DisableDefaultInstanceTags...).Has("Name")
+	// the tags set by eksctl on the ASG are propagated to the instances, so they must not be duplicated
+	tagSpecifications, err := makeTags(n.spec.NodeGroupBase, n.WithoutReservedTags(n.spec.Tags), n.clusterSpec.Metadata, disableInstanceNameTag)
+	if err != nil {
+		return nil, err
+	}
+
+	imageID := gfnt.NewString(n.spec.AMI)
+	if n.spec.AMIResolutionMode == api.AMIResolutionModeCloudFormation {
+		if imageID, err = n.addImageIDParameter(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	launchTemplateData := &gfnec2.LaunchTemplate_LaunchTemplateData{
 		IamInstanceProfile: &gfnec2.LaunchTemplate_IamInstanceProfile{
 			Arn: n.instanceProfileARN,
 		},
-		ImageId:           gfnt.NewString(n.spec.AMI),
+		ImageId:           imageID,
 		UserData:          gfnt.NewString(userData),
 		MetadataOptions:   makeMetadataOptions(n.spec.NodeGroupBase),
-		TagSpecifications: makeTags(n.spec.NodeGroupBase, n.clusterSpec.Metadata),
+		TagSpecifications: tagSpecifications,
 	}
 
-	if err := buildNetworkInterfaces(ctx, launchTemplateData, n.spec.InstanceTypeList(), api.IsEnabled(n.spec.EFAEnabled), n.securityGroups, n.ec2API); err != nil {
+	if err := buildNetworkInterfaces(ctx, launchTemplateData, n.spec.InstanceTypeList(), api.IsEnabled(n.spec.EFAEnabled), n.clusterSpec.IPv6Enabled(), n.securityGroups, n.ec2API); err != nil {
 		return nil, errors.Wrap(err, "couldn't build network interfaces for launch template data")
 	}
+	if api.IsEnabled(n.spec.AssociatePublicIPAddress) {
+		// EC2 only associates a public IP address with instances launched with a single network interface
+		if len(launchTemplateData.NetworkInterfaces) > 1 {
+			return nil, errors.New("associatePublicIPAddress cannot be enabled for instance types with multiple EFA network interfaces")
+		}
+		launchTemplateData.NetworkInterfaces[0].AssociatePublicIpAddress = gfnt.True()
+	}
 
-	if api.IsEnabled(n.spec.EFAEnabled) && n.spec.Placement == nil {
+	if api.IsEnabled(n.spec.EFAEnabled) && (n.spec.Placement == nil || n.spec.Placement.GroupName == "") {
 		groupName := n.newResource("NodeGroupPlacementGroup", &gfnec2.PlacementGroup{
 			Strategy: gfnt.NewString("cluster"),
 		})
@@ -394,10 +890,19 @@ func newLaunchTemplateData(ctx context.Context, n *NodeGroupResourceSet) (*gfnec
 
 	if !api.HasMixedInstances(n.spec) {
 		launchTemplateData.InstanceType = gfnt.NewString(n.spec.InstanceType)
-	} else {
-		launchTemplateData.InstanceType = gfnt.NewString(n.spec.InstancesDistribution.InstanceTypes[0])
+	} else if instanceTypes := n.spec.InstancesDistribution.GetInstanceTypes(); len(instanceTypes) > 0 {
+		launchTemplateData.InstanceType = gfnt.NewString(instanceTypes[0])
+	}
+	// the instances distribution of mixed instances nodegroups sets their purchase options
+	if n.spec.Spot && !api.HasMixedInstances(n.spec) {
+		launchTemplateData.InstanceMarketOptions = &gfnec2.LaunchTemplate_InstanceMarketOptions{
+			MarketType: gfnt.NewString("spot"),
+		}
 	}
 	if n.spec.EBSOptimized != nil {
+		if err := validateEBSOptimized(ctx, *n.spec.EBSOptimized, n.spec.InstanceTypeList(), n.ec2API); err != nil {
+			return nil, err
+		}
 		launchTemplateData.EbsOptimized = gfnt.NewBoolean(*n.spec.EBSOptimized)
 	}
 
@@ -407,10 +912,16 @@ func newLaunchTemplateData(ctx context.Context, n *NodeGroupResourceSet) (*gfnec
 		}
 	}
 
+	if n.spec.ShutdownBehavior != "" {
+		launchTemplateData.InstanceInitiatedShutdownBehavior = gfnt.NewString(n.spec.ShutdownBehavior)
+	}
+
 	if n.spec.Placement != nil {
-		launchTemplateData.Placement = &gfnec2.LaunchTemplate_Placement{
-			GroupName: gfnt.NewString(n.spec.Placement.GroupName),
+		placement, err := makePlacement(ctx, launchTemplateData.Placement, n.spec.Placement, n.ec2API)
+		if err != nil {
+			return nil, err
 		}
+		launchTemplateData.Placement = placement
 	}
 
 	if n.spec.EnableDetailedMonitoring != nil {
@@ -419,16 +930,42 @@ func newLaunchTemplateData(ctx context.Context, n *NodeGroupResourceSet) (*gfnec
 		}
 	}
 
+	if n.spec.CapacityReservation != nil {
+		launchTemplateData.CapacityReservationSpecification = makeCapacityReservationSpecification(n.spec.CapacityReservation)
+	}
+
 	return launchTemplateData, nil
 }
 
+func makeCapacityReservationSpecification(cr *api.CapacityReservation) *gfnec2.LaunchTemplate_CapacityReservationSpecification {
+	spec := &gfnec2.LaunchTemplate_CapacityReservationSpecification{}
+	if cr.CapacityReservationPreference != nil {
+		spec.CapacityReservationPreference = gfnt.NewString(*cr.CapacityReservationPreference)
+	}
+	if target := cr.CapacityReservationTarget; target != nil {
+		spec.CapacityReservationTarget = &gfnec2.LaunchTemplate_CapacityReservationTarget{}
+		if target.CapacityReservationID != nil {
+			spec.CapacityReservationTarget.CapacityReservationId = gfnt.NewString(*target.CapacityReservationID)
+		}
+		if target.CapacityReservationResourceGroupARN != nil {
+			spec.CapacityReservationTarget.CapacityReservationResourceGroupArn = gfnt.NewString(*target.CapacityReservationResourceGroupARN)
+		}
+	}
+	return spec
+}
+
 func makeMetadataOptions(ng *api.NodeGroupBase) *gfnec2.LaunchTemplate_MetadataOptions {
+	if api.IsEnabled(ng.DisableLaunchTemplateMetadataOptions) {
+		return nil
+	}
 	imdsv2TokensRequired := "optional"
 	if api.IsEnabled(ng.DisableIMDSv1) || api.IsEnabled(ng.DisablePodIMDS) {
 		imdsv2TokensRequired = "required"
 	}
 	hopLimit := 2
-	if api.IsEnabled(ng.DisablePodIMDS) {
+	if ng.HTTPPutResponseHopLimit != nil {
+		hopLimit = *ng.HTTPPutResponseHopLimit
+	} else if api.IsEnabled(ng.DisablePodIMDS) {
 		hopLimit = 1
 	}
 	return &gfnec2.LaunchTemplate_MetadataOptions{
@@ -437,25 +974,37 @@ func makeMetadataOptions(ng *api.NodeGroupBase) *gfnec2.LaunchTemplate_MetadataO
 	}
 }
 
-func nodeGroupResource(launchTemplateName *gfnt.Value, vpcZoneIdentifier interface{}, tags []map[string]interface{}, ng *api.NodeGroup) *awsCloudFormationResource {
+// formatSpotMaxPrice formats price with the six decimal places spot prices are limited to, without trailing zeros so
+// that the price renders the way it is usually written (e.g. 0.04 rather than 0.040000)
+func formatSpotMaxPrice(price float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", price), "0"), ".")
+}
+
+// nodeGroupScaling holds references to the scaling parameters of the nodegroup
+type nodeGroupScaling struct {
+	MinSize, MaxSize, DesiredCapacity *gfnt.Value
+}
+
+func nodeGroupResource(launchTemplateName *gfnt.Value, vpcZoneIdentifier interface{}, tags []map[string]interface{}, scaling *nodeGroupScaling, ng *api.NodeGroup) *awsCloudFormationResource {
 	ngProps := map[string]interface{}{
 		"VPCZoneIdentifier": vpcZoneIdentifier,
 		"Tags":              tags,
 	}
 
-	if ng.InstancesDistribution != nil && ng.InstancesDistribution.CapacityRebalance {
-		ngProps["CapacityRebalance"] = ng.InstancesDistribution.CapacityRebalance
+	if scaling.DesiredCapacity != nil {
+		ngProps["DesiredCapacity"] = scaling.DesiredCapacity
 	}
-
-	if ng.DesiredCapacity != nil {
-		ngProps["DesiredCapacity"] = fmt.Sprintf("%d", *ng.DesiredCapacity)
+	if scaling.MinSize != nil {
+		ngProps["MinSize"] = scaling.MinSize
 	}
-	if ng.MinSize != nil {
-		ngProps["MinSize"] = fmt.Sprintf("%d", *ng.MinSize)
+	if scaling.MaxSize != nil {
+		ngProps["MaxSize"] = scaling.MaxSize
 	}
-	if ng.MaxSize != nil {
-		ngProps["MaxSize"] = fmt.Sprintf("%d", *ng.MaxSize)
+
+	if ng.InstancesDistribution != nil && ng.InstancesDistribution.CapacityRebalance {
+		ngProps["CapacityRebalance"] = ng.InstancesDistribution.CapacityRebalance
 	}
+
 	if len(ng.ASGMetricsCollection) > 0 {
 		ngProps["MetricsCollection"] = metricsCollectionResource(ng.ASGMetricsCollection)
 	}
@@ -465,8 +1014,14 @@ func nodeGroupResource(launchTemplateName *gfnt.Value, vpcZoneIdentifier interfa
 	if len(ng.TargetGroupARNs) > 0 {
 		ngProps["TargetGroupARNs"] = ng.TargetGroupARNs
 	}
-	if api.HasMixedInstances(ng) {
+	if ng.LaunchTemplate != nil {
+		ngProps["LaunchTemplate"] = existingLaunchTemplate(ng.LaunchTemplate)
+	} else if api.HasMixedInstances(ng) {
 		ngProps["MixedInstancesPolicy"] = *mixedInstancesPolicy(launchTemplateName, ng)
+		// the Capacity Reservation preferences of the policy are a property of the ASG in CloudFormation
+		if cr := ng.InstancesDistribution.CapacityReservation; cr != nil {
+			ngProps["CapacityReservationSpecification"] = capacityReservationSpecification(cr)
+		}
 	} else {
 		ngProps["LaunchTemplate"] = map[string]interface{}{
 			"LaunchTemplateName": launchTemplateName,
@@ -478,6 +1033,10 @@ func nodeGroupResource(launchTemplateName *gfnt.Value, vpcZoneIdentifier interfa
 		ngProps["MaxInstanceLifetime"] = *ng.MaxInstanceLifetime
 	}
 
+	if ng.ASGContext != nil {
+		ngProps["Context"] = *ng.ASGContext
+	}
+
 	rollingUpdate := map[string]interface{}{}
 	if len(ng.ASGSuspendProcesses) > 0 {
 		rollingUpdate["SuspendProcesses"] = ng.ASGSuspendProcesses
@@ -492,13 +1051,73 @@ func nodeGroupResource(launchTemplateName *gfnt.Value, vpcZoneIdentifier interfa
 	}
 }
 
-func mixedInstancesPolicy(launchTemplateName *gfnt.Value, ng *api.NodeGroup) *map[string]interface{} {
-	instanceTypes := ng.InstancesDistribution.InstanceTypes
-	overrides := make([]map[string]string, len(instanceTypes))
+// existingLaunchTemplate returns the launch template of the ASG for an existing launch template, which is referenced by
+// ID or by name
+func existingLaunchTemplate(lt *api.LaunchTemplate) map[string]interface{} {
+	launchTemplate := map[string]interface{}{}
+	if lt.ID != "" {
+		launchTemplate["LaunchTemplateId"] = lt.ID
+	} else {
+		launchTemplate["LaunchTemplateName"] = lt.Name
+	}
+	if lt.Version != nil {
+		launchTemplate["Version"] = *lt.Version
+	}
+	return launchTemplate
+}
+
+// warmPoolResource returns the warm pool of the ASG, which CloudFormation models as a separate resource rather than
+// as a property of the AutoScalingGroup
+func warmPoolResource(refASG *gfnt.Value, warmPool *api.WarmPool) *awsCloudFormationResource {
+	warmPoolProps := map[string]interface{}{
+		"AutoScalingGroupName": refASG,
+	}
+	if warmPool.MinSize != nil {
+		warmPoolProps["MinSize"] = *warmPool.MinSize
+	}
+	if warmPool.MaxPreparedCapacity != nil {
+		warmPoolProps["MaxGroupPreparedCapacity"] = *warmPool.MaxPreparedCapacity
+	}
+	if warmPool.PoolState != "" {
+		warmPoolProps["PoolState"] = warmPool.PoolState
+	}
+	if warmPool.ReuseOnScaleIn != nil {
+		warmPoolProps["InstanceReusePolicy"] = map[string]interface{}{
+			"ReuseOnScaleIn": *warmPool.ReuseOnScaleIn,
+		}
+	}
 
-	for i, instanceType := range instanceTypes {
-		overrides[i] = map[string]string{
-			"InstanceType": instanceType,
+	return &awsCloudFormationResource{
+		Type:       "AWS::AutoScaling::WarmPool",
+		Properties: warmPoolProps,
+	}
+}
+
+func mixedInstancesPolicy(launchTemplateName *gfnt.Value, ng *api.NodeGroup) *map[string]interface{} {
+	var overrides []map[string]interface{}
+	if requirements := ng.InstancesDistribution.InstanceRequirements; requirements != nil {
+		overrides = []map[string]interface{}{
+			{
+				"InstanceRequirements": instanceRequirements(requirements),
+			},
+		}
+	} else if instanceTypeOverrides := ng.InstancesDistribution.InstanceTypeOverrides; len(instanceTypeOverrides) > 0 {
+		overrides = make([]map[string]interface{}, len(instanceTypeOverrides))
+		for i, override := range instanceTypeOverrides {
+			overrides[i] = map[string]interface{}{
+				"InstanceType": override.InstanceType,
+			}
+			if override.WeightedCapacity != nil {
+				overrides[i]["WeightedCapacity"] = fmt.Sprintf("%d", *override.WeightedCapacity)
+			}
+		}
+	} else {
+		instanceTypes := ng.InstancesDistribution.InstanceTypes
+		overrides = make([]map[string]interface{}, len(instanceTypes))
+		for i, instanceType := range instanceTypes {
+			overrides[i] = map[string]interface{}{
+				"InstanceType": instanceType,
+			}
 		}
 	}
 	policy := map[string]interface{}{
@@ -515,8 +1134,8 @@ func mixedInstancesPolicy(launchTemplateName *gfnt.Value, ng *api.NodeGroup) *ma
 	instancesDistribution := map[string]string{}
 
 	// Only set the price if it was specified so otherwise AWS picks "on-demand price" as the default
-	if ng.InstancesDistribution.MaxPrice != nil {
-		instancesDistribution["SpotMaxPrice"] = fmt.Sprintf("%f", *ng.InstancesDistribution.MaxPrice)
+	if maxPrice := ng.InstancesDistribution.MaxPrice; maxPrice != nil && *maxPrice != 0 {
+		instancesDistribution["SpotMaxPrice"] = formatSpotMaxPrice(*maxPrice)
 	}
 	if ng.InstancesDistribution.OnDemandBaseCapacity != nil {
 		instancesDistribution["OnDemandBaseCapacity"] = fmt.Sprintf("%d", *ng.InstancesDistribution.OnDemandBaseCapacity)
@@ -524,6 +1143,10 @@ func mixedInstancesPolicy(launchTemplateName *gfnt.Value, ng *api.NodeGroup) *ma
 	if ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity != nil {
 		instancesDistribution["OnDemandPercentageAboveBaseCapacity"] = fmt.Sprintf("%d", *ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity)
 	}
+	// Only set the on-demand allocation strategy if it was specified so otherwise AWS picks its default
+	if ng.InstancesDistribution.OnDemandAllocationStrategy != nil {
+		instancesDistribution["OnDemandAllocationStrategy"] = *ng.InstancesDistribution.OnDemandAllocationStrategy
+	}
 	if ng.InstancesDistribution.SpotInstancePools != nil {
 		instancesDistribution["SpotInstancePools"] = fmt.Sprintf("%d", *ng.InstancesDistribution.SpotInstancePools)
 	}
@@ -537,6 +1160,47 @@ func mixedInstancesPolicy(launchTemplateName *gfnt.Value, ng *api.NodeGroup) *ma
 	return &policy
 }
 
+func capacityReservationSpecification(cr *api.InstancesDistributionCapacityReservation) map[string]interface{} {
+	spec := map[string]interface{}{
+		"CapacityReservationPreference": cr.CapacityReservationPreference,
+	}
+	target := map[string]interface{}{}
+	if len(cr.CapacityReservationIDs) > 0 {
+		target["CapacityReservationIds"] = cr.CapacityReservationIDs
+	}
+	if len(cr.CapacityReservationResourceGroupARNs) > 0 {
+		target["CapacityReservationResourceGroupArns"] = cr.CapacityReservationResourceGroupARNs
+	}
+	if len(target) > 0 {
+		spec["CapacityReservationTarget"] = target
+	}
+	return spec
+}
+
+func instanceRequirements(requirements *api.InstanceRequirements) map[string]interface{} {
+	makeRange := func(r *api.InstanceRequirementsRange) map[string]interface{} {
+		rangeProps := map[string]interface{}{
+			"Min": *r.Min,
+		}
+		if r.Max != nil {
+			rangeProps["Max"] = *r.Max
+		}
+		return rangeProps
+	}
+
+	requirementsProps := map[string]interface{}{
+		"VCpuCount": makeRange(requirements.VCPUCount),
+		"MemoryMiB": makeRange(requirements.MemoryMiB),
+	}
+	if len(requirements.CPUManufacturers) > 0 {
+		requirementsProps["CpuManufacturers"] = requirements.CPUManufacturers
+	}
+	if len(requirements.ExcludedInstanceTypes) > 0 {
+		requirementsProps["ExcludedInstanceTypes"] = requirements.ExcludedInstanceTypes
+	}
+	return requirementsProps
+}
+
 func metricsCollectionResource(asgMetricsCollection []api.MetricsCollection) []map[string]interface{} {
 	var metricsCollections []map[string]interface{}
 	for _, m := range asgMetricsCollection {
@@ -13,6 +13,7 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 
@@ -81,6 +82,19 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupFeatureLocalSecurityGroup))
 		})
 
+		It("should add the launch template outputs", func() {
+			Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupLaunchTemplateID))
+			Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupLaunchTemplateVersion))
+
+			templateOutputs := ngTemplate.Outputs.(map[string]interface{})
+			Expect(templateOutputs[outputs.NodeGroupLaunchTemplateID]).To(HaveKeyWithValue("Value", map[string]interface{}{
+				"Ref": "NodeGroupLaunchTemplate",
+			}))
+			Expect(templateOutputs[outputs.NodeGroupLaunchTemplateVersion]).To(HaveKeyWithValue("Value", map[string]interface{}{
+				"Fn::GetAtt": []interface{}{"NodeGroupLaunchTemplate", "LatestVersionNumber"},
+			}))
+		})
+
 		Context("ipv6 cluster", func() {
 			BeforeEach(func() {
 				cfg.KubernetesNetworkConfig.IPFamily = api.IPV6Family
@@ -89,9 +103,26 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				cfg.KubernetesNetworkConfig.IPFamily = api.IPV4Family
 			})
 
-			When("an unmanaged nodegroup is created", func() {
+			When("an unmanaged AmazonLinux2 nodegroup is created", func() {
+				BeforeEach(func() {
+					ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+				})
+
+				It("requests an IPv6 address for the network interface of the nodes", func() {
+					Expect(addErr).NotTo(HaveOccurred())
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					Expect(properties.LaunchTemplateData.NetworkInterfaces).To(HaveLen(1))
+					Expect(properties.LaunchTemplateData.NetworkInterfaces[0].Ipv6AddressCount).To(Equal(1))
+				})
+			})
+
+			When("an unmanaged nodegroup with another AMI family is created", func() {
+				BeforeEach(func() {
+					ng.AMIFamily = api.NodeImageFamilyBottlerocket
+				})
+
 				It("returns an error", func() {
-					Expect(addErr).To(MatchError(ContainSubstring("unmanaged nodegroups are not supported with IPv6 clusters")))
+					Expect(addErr).To(MatchError("unmanaged nodegroups in IPv6 clusters are only supported with the AmazonLinux2 AMI family"))
 				})
 			})
 		})
@@ -138,6 +169,62 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			})
 		})
 
+		Context("if ng.ASGContext is set", func() {
+			BeforeEach(func() {
+				ng.ASGContext = aws.String("my-context")
+			})
+
+			It("sets the ASG context", func() {
+				Expect(ngTemplate.Resources["NodeGroup"].Properties.Context).To(Equal("my-context"))
+			})
+		})
+
+		Context("if ng.ASGContext is not set", func() {
+			It("does not set the ASG context", func() {
+				templateBody, err := ngrs.RenderJSON()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(templateBody)).NotTo(ContainSubstring(`"Context"`))
+			})
+		})
+
+		Context("if ng.WarmPool is set", func() {
+			BeforeEach(func() {
+				ng.WarmPool = &api.WarmPool{
+					MinSize:             aws.Int(1),
+					MaxPreparedCapacity: aws.Int(4),
+					PoolState:           api.WarmPoolStateHibernated,
+					ReuseOnScaleIn:      aws.Bool(true),
+				}
+			})
+
+			It("adds a warm pool for the ASG", func() {
+				templateBody, err := ngrs.RenderJSON()
+				Expect(err).NotTo(HaveOccurred())
+				var template struct {
+					Resources map[string]json.RawMessage
+				}
+				Expect(json.Unmarshal(templateBody, &template)).To(Succeed())
+				Expect(string(template.Resources["NodeGroupWarmPool"])).To(MatchJSON(`{
+					"Type": "AWS::AutoScaling::WarmPool",
+					"Properties": {
+						"AutoScalingGroupName": {"Ref": "NodeGroup"},
+						"MinSize": 1,
+						"MaxGroupPreparedCapacity": 4,
+						"PoolState": "Hibernated",
+						"InstanceReusePolicy": {
+							"ReuseOnScaleIn": true
+						}
+					}
+				}`))
+			})
+		})
+
+		Context("if ng.WarmPool is not set", func() {
+			It("does not add a warm pool", func() {
+				Expect(ngTemplate.Resources).NotTo(HaveKey("NodeGroupWarmPool"))
+			})
+		})
+
 		Context("if ng.MaxSize is nil", func() {
 			BeforeEach(func() {
 				ng.MaxSize = nil
@@ -189,7 +276,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			})
 
 			It("adds the InstanceProfileARN output", func() {
-				Expect(ngTemplate.Outputs).To(HaveLen(4))
+				Expect(ngTemplate.Outputs).To(HaveLen(6))
 				Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupInstanceProfileARN))
 			})
 
@@ -199,7 +286,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 
 				It("adds the InstanceRoleARN output", func() {
-					Expect(ngTemplate.Outputs).To(HaveLen(5))
+					Expect(ngTemplate.Outputs).To(HaveLen(7))
 					Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupInstanceRoleARN))
 					Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupInstanceProfileARN))
 				})
@@ -218,7 +305,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			})
 
 			It("adds the InstanceRoleARN and InstanceProfileARN outputs", func() {
-				Expect(ngTemplate.Outputs).To(HaveLen(5))
+				Expect(ngTemplate.Outputs).To(HaveLen(7))
 				Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupInstanceRoleARN))
 				Expect(ngTemplate.Outputs).To(HaveKey(outputs.NodeGroupInstanceProfileARN))
 			})
@@ -336,6 +423,40 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 						Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).To(HaveLen(3))
 						Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).NotTo(ContainElement(makePolicyARNRef("AmazonEKS_CNI_Policy")))
 					})
+
+					Context("ng.IAM.AttachCNIPolicy is always", func() {
+						BeforeEach(func() {
+							ng.IAM.AttachCNIPolicy = api.AttachCNIPolicyAlways
+						})
+
+						It("adds the AmazonEKS_CNI_Policy", func() {
+							Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).To(HaveLen(4))
+							Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).To(ContainElement(makePolicyARNRef("AmazonEKS_CNI_Policy")))
+						})
+					})
+
+					Context("ng.IAM.AttachCNIPolicy is auto", func() {
+						BeforeEach(func() {
+							ng.IAM.AttachCNIPolicy = api.AttachCNIPolicyAuto
+						})
+
+						It("does not add the AmazonEKS_CNI_Policy", func() {
+							Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).To(HaveLen(3))
+							Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).NotTo(ContainElement(makePolicyARNRef("AmazonEKS_CNI_Policy")))
+						})
+					})
+				})
+
+				Context("ng.IAM.AttachCNIPolicy is never", func() {
+					BeforeEach(func() {
+						ng.IAM.AttachCNIPolicy = api.AttachCNIPolicyNever
+						forceAddCNIPolicy = true
+					})
+
+					It("does not add the AmazonEKS_CNI_Policy", func() {
+						Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).To(HaveLen(3))
+						Expect(ngTemplate.Resources["NodeInstanceRole"].Properties.ManagedPolicyArns).NotTo(ContainElement(makePolicyARNRef("AmazonEKS_CNI_Policy")))
+					})
 				})
 			})
 
@@ -601,6 +722,70 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				Expect(properties.ToPort).To(Equal(443))
 			})
 
+			Context("vpc.manageControlPlaneSecurityGroupRules is disabled", func() {
+				BeforeEach(func() {
+					cfg.VPC.ManageControlPlaneSecurityGroupRules = aws.Bool(false)
+				})
+
+				It("does not add rules to the control plane security group", func() {
+					Expect(ngTemplate.Resources).To(HaveKey("SG"))
+					Expect(ngTemplate.Resources).NotTo(HaveKey("EgressInterCluster"))
+					Expect(ngTemplate.Resources).NotTo(HaveKey("EgressInterClusterAPI"))
+					Expect(ngTemplate.Resources).NotTo(HaveKey("IngressInterClusterCP"))
+				})
+			})
+
+			Context("ng.SecurityGroups.RestrictControlPlaneRules is enabled", func() {
+				BeforeEach(func() {
+					ng.SecurityGroups.RestrictControlPlaneRules = aws.Bool(true)
+					ng.SecurityGroups.AdditionalControlPlanePorts = []int{8443, 9443}
+				})
+
+				It("narrows the ingress rules of the SG resource to the kubelet and additional ports", func() {
+					Expect(ngTemplate.Resources).To(HaveKey("SG"))
+					properties := ngTemplate.Resources["SG"].Properties
+					Expect(properties.GroupDescription).To(Equal("Communication between the control plane and worker nodes in group ng-abcd1234"))
+					Expect(properties.SecurityGroupIngress).To(HaveLen(4))
+					Expect(properties.SecurityGroupIngress[0].Description).To(Equal("[IngressInterCluster] Allow worker nodes in group ng-abcd1234 to communicate with control plane (kubelet)"))
+					Expect(properties.SecurityGroupIngress[0].FromPort).To(Equal(float64(10250)))
+					Expect(properties.SecurityGroupIngress[0].ToPort).To(Equal(float64(10250)))
+					Expect(properties.SecurityGroupIngress[1].FromPort).To(Equal(float64(443)))
+					Expect(properties.SecurityGroupIngress[1].ToPort).To(Equal(float64(443)))
+					Expect(properties.SecurityGroupIngress[2].SourceSecurityGroupID).To(ContainElement(sgID))
+					Expect(properties.SecurityGroupIngress[2].Description).To(Equal("[IngressInterClusterPort8443] Allow worker nodes in group ng-abcd1234 to communicate with control plane (port 8443)"))
+					Expect(properties.SecurityGroupIngress[2].IPProtocol).To(Equal("tcp"))
+					Expect(properties.SecurityGroupIngress[2].FromPort).To(Equal(float64(8443)))
+					Expect(properties.SecurityGroupIngress[2].ToPort).To(Equal(float64(8443)))
+					Expect(properties.SecurityGroupIngress[3].Description).To(Equal("[IngressInterClusterPort9443] Allow worker nodes in group ng-abcd1234 to communicate with control plane (port 9443)"))
+					Expect(properties.SecurityGroupIngress[3].FromPort).To(Equal(float64(9443)))
+					Expect(properties.SecurityGroupIngress[3].ToPort).To(Equal(float64(9443)))
+				})
+
+				It("narrows the EgressInterCluster resource to the kubelet port", func() {
+					Expect(ngTemplate.Resources).To(HaveKey("EgressInterCluster"))
+					properties := ngTemplate.Resources["EgressInterCluster"].Properties
+					Expect(properties.Description).To(Equal("Allow control plane to communicate with worker nodes in group ng-abcd1234 (kubelet)"))
+					Expect(properties.FromPort).To(Equal(10250))
+					Expect(properties.ToPort).To(Equal(10250))
+					Expect(ngTemplate.Resources).To(HaveKey("EgressInterClusterAPI"))
+					Expect(ngTemplate.Resources).To(HaveKey("IngressInterClusterCP"))
+				})
+
+				It("adds an egress resource for each additional port", func() {
+					for _, port := range []int{8443, 9443} {
+						name := fmt.Sprintf("EgressInterClusterPort%d", port)
+						Expect(ngTemplate.Resources).To(HaveKey(name))
+						properties := ngTemplate.Resources[name].Properties
+						Expect(properties.GroupID).To(ContainElement(sgID))
+						Expect(properties.DestinationSecurityGroupID).To(Equal(makeRef("SG")))
+						Expect(properties.Description).To(Equal(fmt.Sprintf("Allow control plane to communicate with worker nodes in group ng-abcd1234 (port %d)", port)))
+						Expect(properties.IPProtocol).To(Equal("tcp"))
+						Expect(properties.FromPort).To(Equal(port))
+						Expect(properties.ToPort).To(Equal(port))
+					}
+				})
+			})
+
 			Context("ng.EFA is enabled", func() {
 				BeforeEach(func() {
 					ng.EFAEnabled = aws.Bool(true)
@@ -622,6 +807,24 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 							},
 						}, nil,
 					)
+					mockEC2.On("DescribeInstanceTypeOfferings", mock.Anything, &ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: ec2types.LocationTypeAvailabilityZone,
+						Filters: []ec2types.Filter{
+							{
+								Name:   aws.String("instance-type"),
+								Values: []string{"m5.large"},
+							},
+						},
+					}).Return(
+						&ec2.DescribeInstanceTypeOfferingsOutput{
+							InstanceTypeOfferings: []ec2types.InstanceTypeOffering{
+								{
+									InstanceType: ec2types.InstanceTypeM5Large,
+									Location:     aws.String(azA),
+								},
+							},
+						}, nil,
+					)
 				})
 
 				It("adds the efa sg resources", func() {
@@ -675,6 +878,139 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				Expect(properties.LaunchTemplateData.TagSpecifications[2].Tags[0].Value).To(Equal("bonsai-ng-abcd1234-Node"))
 			})
 
+			Context("ng.EFAEnabled is set on an instance type with multiple network cards", func() {
+				BeforeEach(func() {
+					ng.InstanceType = "p4d.24xlarge"
+					ng.EFAEnabled = aws.Bool(true)
+					mockEC2.On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+						InstanceTypes: []ec2types.InstanceType{ec2types.InstanceTypeP4d24xlarge},
+					}).Return(&ec2.DescribeInstanceTypesOutput{
+						InstanceTypes: []ec2types.InstanceTypeInfo{
+							{
+								InstanceType: ec2types.InstanceTypeP4d24xlarge,
+								NetworkInfo: &ec2types.NetworkInfo{
+									EfaSupported:             aws.Bool(true),
+									MaximumNetworkCards:      aws.Int32(4),
+									MaximumNetworkInterfaces: aws.Int32(60),
+								},
+							},
+						},
+					}, nil)
+					mockEC2.On("DescribeInstanceTypeOfferings", mock.Anything, &ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: ec2types.LocationTypeAvailabilityZone,
+						Filters: []ec2types.Filter{
+							{
+								Name:   aws.String("instance-type"),
+								Values: []string{"p4d.24xlarge"},
+							},
+						},
+					}).Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+						InstanceTypeOfferings: []ec2types.InstanceTypeOffering{
+							{
+								InstanceType: ec2types.InstanceTypeP4d24xlarge,
+								Location:     aws.String(azA),
+							},
+						},
+					}, nil)
+				})
+
+				It("adds an EFA network interface for each network card", func() {
+					Expect(addErr).NotTo(HaveOccurred())
+					networkInterfaces := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.NetworkInterfaces
+					Expect(networkInterfaces).To(HaveLen(4))
+					for i, ni := range networkInterfaces {
+						Expect(ni.InterfaceType).To(Equal("efa"))
+						Expect(ni.DeviceIndex).To(Equal(i))
+						Expect(ni.NetworkCardIndex).To(Equal(i))
+					}
+				})
+
+				Context("and ng.AssociatePublicIPAddress is enabled", func() {
+					BeforeEach(func() {
+						ng.AssociatePublicIPAddress = aws.Bool(true)
+					})
+
+					It("returns an error", func() {
+						Expect(addErr).To(MatchError(ContainSubstring("associatePublicIPAddress cannot be enabled for instance types with multiple EFA network interfaces")))
+					})
+				})
+			})
+
+			Context("ng.AssociatePublicIPAddress is enabled", func() {
+				BeforeEach(func() {
+					ng.AssociatePublicIPAddress = aws.Bool(true)
+				})
+
+				It("associates a public IP address with the network interface", func() {
+					networkInterfaces := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.NetworkInterfaces
+					Expect(networkInterfaces).To(HaveLen(1))
+					Expect(networkInterfaces[0].AssociatePublicIPAddress).To(Equal(aws.Bool(true)))
+				})
+			})
+
+			Context("ng.AssociatePublicIPAddress is disabled", func() {
+				BeforeEach(func() {
+					ng.AssociatePublicIPAddress = aws.Bool(false)
+				})
+
+				It("leaves the association of a public IP address to the subnet", func() {
+					networkInterfaces := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.NetworkInterfaces
+					Expect(networkInterfaces).To(HaveLen(1))
+					Expect(networkInterfaces[0].AssociatePublicIPAddress).To(BeNil())
+				})
+			})
+
+			Context("ng.AssociatePublicIPAddress is not set", func() {
+				It("leaves the association of a public IP address to the subnet", func() {
+					networkInterfaces := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.NetworkInterfaces
+					Expect(networkInterfaces).To(HaveLen(1))
+					Expect(networkInterfaces[0].AssociatePublicIPAddress).To(BeNil())
+				})
+			})
+
+			Context("ng.AMIResolutionMode is cloudformation", func() {
+				BeforeEach(func() {
+					cfg.Metadata.Version = "1.21"
+					ng.AMI = ""
+					ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+					ng.AMIResolutionMode = api.AMIResolutionModeCloudFormation
+					ng.InstanceType = "g4dn.xlarge"
+					mockEC2.On("DescribeInstanceTypes",
+						mock.Anything,
+						&ec2.DescribeInstanceTypesInput{
+							InstanceTypes: []ec2types.InstanceType{ec2types.InstanceTypeG4dnXlarge},
+						},
+					).Return(
+						&ec2.DescribeInstanceTypesOutput{
+							InstanceTypes: []ec2types.InstanceTypeInfo{
+								{
+									InstanceType: ec2types.InstanceTypeG4dnXlarge,
+									GpuInfo: &ec2types.GpuInfo{
+										Gpus: []ec2types.GpuDeviceInfo{
+											{
+												Manufacturer: aws.String("NVIDIA"),
+												Count:        aws.Int32(1),
+											},
+										},
+									},
+								},
+							},
+						}, nil,
+					)
+				})
+
+				It("references the AMI from an SSM parameter resolved by CloudFormation", func() {
+					Expect(addErr).NotTo(HaveOccurred())
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					Expect(properties.LaunchTemplateData.ImageID).To(Equal(makeRef(builder.NodeGroupImageIDParameter)))
+					Expect(ngTemplate.Parameters).To(HaveKeyWithValue(builder.NodeGroupImageIDParameter, fakes.Parameter{
+						Type:        "AWS::SSM::Parameter::Value<AWS::EC2::Image::Id>",
+						Description: "SSM parameter holding the AMI of the nodegroup",
+						Default:     "/aws/service/eks/optimized-ami/1.21/amazon-linux-2-gpu/recommended/image_id",
+					}))
+				})
+			})
+
 			Context("creating userdata fails", func() {
 				BeforeEach(func() {
 					fakeBootstrapper.UserDataReturns("", errors.New("this is fine"))
@@ -696,6 +1032,82 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 			})
 
+			Context("ng.LaunchTemplateTagSpecifications is set", func() {
+				BeforeEach(func() {
+					ng.Tags = map[string]string{"team": "nodes"}
+					ng.LaunchTemplateTagSpecifications = map[string]map[string]string{
+						"volume": {
+							"cost-center": "1234",
+							"team":        "storage",
+						},
+						"network-interface": {
+							"Name": "bonsai-eni",
+						},
+					}
+				})
+
+				It("merges the tags of each resource type with the default tags", func() {
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					tagSpecs := properties.LaunchTemplateData.TagSpecifications
+					Expect(tagSpecs).To(HaveLen(3))
+
+					Expect(tagSpecs[0].ResourceType).To(Equal(aws.String("instance")))
+					Expect(tagSpecs[0].Tags).To(ConsistOf(
+						fakes.Tag{Key: "Name", Value: "bonsai-ng-abcd1234-Node"},
+						fakes.Tag{Key: "team", Value: "nodes"},
+					))
+
+					Expect(tagSpecs[1].ResourceType).To(Equal(aws.String("volume")))
+					Expect(tagSpecs[1].Tags).To(ConsistOf(
+						fakes.Tag{Key: "Name", Value: "bonsai-ng-abcd1234-Node"},
+						fakes.Tag{Key: "cost-center", Value: "1234"},
+						fakes.Tag{Key: "team", Value: "storage"},
+					))
+
+					Expect(tagSpecs[2].ResourceType).To(Equal(aws.String("network-interface")))
+					Expect(tagSpecs[2].Tags).To(ConsistOf(
+						fakes.Tag{Key: "Name", Value: "bonsai-eni"},
+						fakes.Tag{Key: "team", Value: "nodes"},
+					))
+				})
+			})
+
+			Context("ng.CapacityReservation targets a Capacity Reservation", func() {
+				BeforeEach(func() {
+					ng.CapacityReservation = &api.CapacityReservation{
+						CapacityReservationTarget: &api.CapacityReservationTarget{
+							CapacityReservationID: aws.String("cr-1234"),
+						},
+					}
+				})
+
+				It("sets the CapacityReservationSpecification of the LaunchTemplateData", func() {
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					spec := properties.LaunchTemplateData.CapacityReservationSpecification
+					Expect(spec).NotTo(BeNil())
+					Expect(spec.CapacityReservationPreference).To(BeEmpty())
+					Expect(spec.CapacityReservationTarget).NotTo(BeNil())
+					Expect(spec.CapacityReservationTarget.CapacityReservationID).To(Equal("cr-1234"))
+					Expect(spec.CapacityReservationTarget.CapacityReservationResourceGroupARN).To(BeEmpty())
+				})
+			})
+
+			Context("ng.CapacityReservation sets a preference", func() {
+				BeforeEach(func() {
+					ng.CapacityReservation = &api.CapacityReservation{
+						CapacityReservationPreference: aws.String("none"),
+					}
+				})
+
+				It("sets the CapacityReservationPreference of the LaunchTemplateData", func() {
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					spec := properties.LaunchTemplateData.CapacityReservationSpecification
+					Expect(spec).NotTo(BeNil())
+					Expect(spec.CapacityReservationPreference).To(Equal("none"))
+					Expect(spec.CapacityReservationTarget).To(BeNil())
+				})
+			})
+
 			Context("ng.DisablePodIMDS is enabled", func() {
 				BeforeEach(func() {
 					ng.DisablePodIMDS = aws.Bool(true)
@@ -706,6 +1118,41 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					Expect(properties.LaunchTemplateData.MetadataOptions.HTTPTokens).To(Equal("required"))
 					Expect(properties.LaunchTemplateData.MetadataOptions.HTTPPutResponseHopLimit).To(Equal(float64(1)))
 				})
+
+				When("ng.HTTPPutResponseHopLimit is set", func() {
+					BeforeEach(func() {
+						ng.HTTPPutResponseHopLimit = aws.Int(3)
+					})
+
+					It("uses the hop limit of the nodegroup and still requires HttpTokens", func() {
+						properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+						Expect(properties.LaunchTemplateData.MetadataOptions.HTTPTokens).To(Equal("required"))
+						Expect(properties.LaunchTemplateData.MetadataOptions.HTTPPutResponseHopLimit).To(Equal(float64(3)))
+					})
+				})
+			})
+
+			Context("ng.HTTPPutResponseHopLimit is set", func() {
+				BeforeEach(func() {
+					ng.HTTPPutResponseHopLimit = aws.Int(1)
+				})
+
+				It("sets the hop limit on the LaunchTemplateData MetadataOptions", func() {
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					Expect(properties.LaunchTemplateData.MetadataOptions.HTTPPutResponseHopLimit).To(Equal(float64(1)))
+					Expect(properties.LaunchTemplateData.MetadataOptions.HTTPTokens).To(Equal("optional"))
+				})
+			})
+
+			Context("ng.DisableLaunchTemplateMetadataOptions is enabled", func() {
+				BeforeEach(func() {
+					ng.DisableLaunchTemplateMetadataOptions = aws.Bool(true)
+				})
+
+				It("omits the MetadataOptions from the LaunchTemplateData", func() {
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					Expect(properties.LaunchTemplateData.MetadataOptions).To(BeNil())
+				})
 			})
 
 			Context("ng.EFAEnabled is true and ng.Placement is nil", func() {
@@ -735,14 +1182,60 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			})
 
 			Context("ng.EBSOptimized is true", func() {
+				mockEBSOptimizedSupport := func(instanceType ec2types.InstanceType, support ec2types.EbsOptimizedSupport) {
+					mockEC2.On("DescribeInstanceTypes",
+						mock.Anything,
+						&ec2.DescribeInstanceTypesInput{
+							InstanceTypes: []ec2types.InstanceType{instanceType},
+						},
+					).Return(
+						&ec2.DescribeInstanceTypesOutput{
+							InstanceTypes: []ec2types.InstanceTypeInfo{
+								{
+									InstanceType: instanceType,
+									EbsInfo: &ec2types.EbsInfo{
+										EbsOptimizedSupport: support,
+									},
+								},
+							},
+						}, nil,
+					)
+				}
+
 				BeforeEach(func() {
 					ng.EBSOptimized = aws.Bool(true)
+					ng.InstanceType = "m4.large"
+					mockEBSOptimizedSupport(ec2types.InstanceTypeM4Large, ec2types.EbsOptimizedSupportSupported)
 				})
 
 				It("enables the value on the launch template", func() {
 					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
 					Expect(properties.LaunchTemplateData.EbsOptimized).To(Equal(aws.Bool(true)))
 				})
+
+				When("EBS optimization is always enabled for the instance type", func() {
+					BeforeEach(func() {
+						ng.InstanceType = "c5.large"
+						mockEBSOptimizedSupport(ec2types.InstanceTypeC5Large, ec2types.EbsOptimizedSupportDefault)
+					})
+
+					It("enables the value on the launch template", func() {
+						Expect(addErr).NotTo(HaveOccurred())
+						properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+						Expect(properties.LaunchTemplateData.EbsOptimized).To(Equal(aws.Bool(true)))
+					})
+				})
+
+				When("the instance type does not support EBS optimization", func() {
+					BeforeEach(func() {
+						ng.InstanceType = "t2.micro"
+						mockEBSOptimizedSupport(ec2types.InstanceTypeT2Micro, ec2types.EbsOptimizedSupportUnsupported)
+					})
+
+					It("errors", func() {
+						Expect(addErr).To(MatchError(ContainSubstring("instance type t2.micro does not support EBS optimization")))
+					})
+				})
 			})
 
 			Context("ng.CPUCredits are set", func() {
@@ -765,6 +1258,73 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
 					Expect(properties.LaunchTemplateData.Placement.GroupName).To(Equal("one-direction"))
 				})
+
+				When("a dedicated host tenancy is set", func() {
+					BeforeEach(func() {
+						ng.Placement = &api.Placement{
+							Tenancy:              api.PlacementTenancyHost,
+							HostResourceGroupARN: "arn:aws:resource-groups:us-west-2:123456789012:group/hosts",
+						}
+					})
+
+					It("sets the tenancy and host resource group on the LaunchTemplateData", func() {
+						placement := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.Placement
+						Expect(placement.GroupName).To(BeNil())
+						Expect(placement.Tenancy).To(Equal("host"))
+						Expect(placement.HostResourceGroupArn).To(Equal("arn:aws:resource-groups:us-west-2:123456789012:group/hosts"))
+					})
+				})
+
+				When("a partition is set", func() {
+					mockPlacementGroup := func(groupName string, strategy ec2types.PlacementStrategy) {
+						mockEC2.On("DescribePlacementGroups", mock.Anything, &ec2.DescribePlacementGroupsInput{
+							GroupNames: []string{groupName},
+						}).Return(&ec2.DescribePlacementGroupsOutput{
+							PlacementGroups: []ec2types.PlacementGroup{
+								{GroupName: aws.String(groupName), Strategy: strategy},
+							},
+						}, nil)
+					}
+
+					When("the placement group uses the partition strategy", func() {
+						BeforeEach(func() {
+							mockPlacementGroup("partitions", ec2types.PlacementStrategyPartition)
+							ng.Placement = &api.Placement{GroupName: "partitions", PartitionNumber: aws.Int(3)}
+						})
+
+						It("sets the partition number on the LaunchTemplateData", func() {
+							Expect(addErr).NotTo(HaveOccurred())
+							placement := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.Placement
+							Expect(placement.GroupName).To(Equal("partitions"))
+							Expect(placement.PartitionNumber).To(Equal(float64(3)))
+						})
+					})
+
+					When("the placement group does not use the partition strategy", func() {
+						BeforeEach(func() {
+							mockPlacementGroup("spread", ec2types.PlacementStrategySpread)
+							ng.Placement = &api.Placement{GroupName: "spread", PartitionNumber: aws.Int(1)}
+						})
+
+						It("errors", func() {
+							Expect(addErr).To(MatchError(ContainSubstring("placement.partitionNumber can only be set for placement groups with the partition strategy, placement group spread uses the spread strategy")))
+						})
+					})
+				})
+			})
+
+			Context("ng.EFAEnabled is true and only the tenancy is set", func() {
+				BeforeEach(func() {
+					ng.EFAEnabled = aws.Bool(true)
+					ng.Placement = &api.Placement{Tenancy: api.PlacementTenancyDefault}
+				})
+
+				It("creates NodeGroupPlacementGroup resource and sets the tenancy", func() {
+					Expect(ngTemplate.Resources).To(HaveKey("NodeGroupPlacementGroup"))
+					placement := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.Placement
+					Expect(placement.GroupName).To(Equal(makeRef("NodeGroupPlacementGroup")))
+					Expect(placement.Tenancy).To(Equal("default"))
+				})
 			})
 
 			It("creates new NodeGroup resource", func() {
@@ -772,7 +1332,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				Expect(ngTemplate.Resources["NodeGroup"].Type).To(Equal("AWS::AutoScaling::AutoScalingGroup"))
 				Expect(ngTemplate.Resources["NodeGroup"].UpdatePolicy["AutoScalingRollingUpdate"]).To(Equal(map[string]interface{}{}))
 				Expect(ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate.LaunchTemplateName).To(Equal(map[string]interface{}{"Fn::Sub": "${AWS::StackName}"}))
-				Expect(ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate.Version["Fn::GetAtt"]).To(Equal([]interface{}{"NodeGroupLaunchTemplate", "LatestVersionNumber"}))
+				Expect(ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate.Version).To(HaveKeyWithValue("Fn::GetAtt", []interface{}{"NodeGroupLaunchTemplate", "LatestVersionNumber"}))
 				tags := ngTemplate.Resources["NodeGroup"].Properties.Tags
 				Expect(tags).To(HaveLen(2))
 				Expect(tags[0].Key).To(Equal("Name"))
@@ -807,21 +1367,202 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 			})
 
-			Context("ng.InstancesDistribution and ng.InstancesDistribution.CapacityRebalance are set", func() {
+			Context("ng.DisableDefaultInstanceTags is set", func() {
 				BeforeEach(func() {
-					ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
-						CapacityRebalance: true,
-					}
+					ng.DisableDefaultInstanceTags = []string{"Name"}
 				})
 
-				It("sets CapacityRebalance on the resource", func() {
-					Expect(ngTemplate.Resources["NodeGroup"].Properties.CapacityRebalance).To(BeTrue())
+				It("keeps the Name tag on the ASG without propagating it to the instances", func() {
+					tags := ngTemplate.Resources["NodeGroup"].Properties.Tags
+					Expect(tags).To(HaveLen(2))
+					Expect(tags[0].Key).To(Equal("Name"))
+					Expect(tags[0].Value).To(Equal("bonsai-ng-abcd1234-Node"))
+					Expect(tags[0].PropagateAtLaunch).To(Equal("false"))
+					Expect(tags[1].Key).To(Equal("kubernetes.io/cluster/bonsai"))
+					Expect(tags[1].PropagateAtLaunch).To(Equal("true"))
 				})
-			})
 
-			Context("ng.PropagateASGTags", func() {
-				When("PropagateASGTags is enabled and there are labels and taints", func() {
-					BeforeEach(func() {
+				It("does not tag the instances with the Name tag in the launch template", func() {
+					tagSpecs := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.TagSpecifications
+					Expect(tagSpecs).To(HaveLen(2))
+					Expect(tagSpecs[0].ResourceType).To(Equal(aws.String("volume")))
+					Expect(tagSpecs[0].Tags).To(ConsistOf(fakes.Tag{Key: "Name", Value: "bonsai-ng-abcd1234-Node"}))
+					Expect(tagSpecs[1].ResourceType).To(Equal(aws.String("network-interface")))
+				})
+
+				When("the nodegroup has tags", func() {
+					BeforeEach(func() {
+						ng.Tags = map[string]string{"team": "nodes"}
+					})
+
+					It("only tags the instances with the tags of the nodegroup", func() {
+						tagSpecs := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.TagSpecifications
+						Expect(tagSpecs).To(HaveLen(3))
+						Expect(tagSpecs[0].ResourceType).To(Equal(aws.String("instance")))
+						Expect(tagSpecs[0].Tags).To(ConsistOf(fakes.Tag{Key: "team", Value: "nodes"}))
+					})
+				})
+			})
+
+			Context("ng.LaunchTemplate is set", func() {
+				const instanceProfileARN = "arn:aws:iam::123456789012:instance-profile/node-profile"
+
+				mockLaunchTemplate := func(matches func(*ec2.DescribeLaunchTemplateVersionsInput) bool, profile *ec2types.LaunchTemplateIamInstanceProfileSpecification) {
+					mockEC2.On("DescribeLaunchTemplateVersions", mock.Anything, mock.MatchedBy(matches)).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+						LaunchTemplateVersions: []ec2types.LaunchTemplateVersion{
+							{
+								LaunchTemplateData: &ec2types.ResponseLaunchTemplateData{
+									IamInstanceProfile: profile,
+								},
+							},
+						},
+					}, nil)
+				}
+
+				BeforeEach(func() {
+					ng.LaunchTemplate = &api.LaunchTemplate{
+						ID:      "lt-1234",
+						Version: aws.String("3"),
+					}
+					ng.IAM.InstanceProfileARN = instanceProfileARN
+					mockLaunchTemplate(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+						return aws.ToString(input.LaunchTemplateId) == "lt-1234"
+					}, &ec2types.LaunchTemplateIamInstanceProfileSpecification{Arn: aws.String(instanceProfileARN)})
+				})
+
+				It("does not create a launch template", func() {
+					Expect(ngTemplate.Resources).NotTo(HaveKey("NodeGroupLaunchTemplate"))
+					Expect(ngTemplate.Outputs).NotTo(HaveKey(outputs.NodeGroupLaunchTemplateID))
+				})
+
+				It("does not create the IAM resources of the nodegroup", func() {
+					Expect(ngTemplate.Resources).NotTo(HaveKey("NodeInstanceRole"))
+					Expect(ngTemplate.Resources).NotTo(HaveKey("NodeInstanceProfile"))
+				})
+
+				When("the launch template uses another instance profile", func() {
+					BeforeEach(func() {
+						ng.LaunchTemplate = &api.LaunchTemplate{
+							ID:      "lt-5678",
+							Version: aws.String("1"),
+						}
+						mockLaunchTemplate(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+							return aws.ToString(input.LaunchTemplateId) == "lt-5678"
+						}, &ec2types.LaunchTemplateIamInstanceProfileSpecification{Name: aws.String("other-profile")})
+					})
+
+					It("returns an error", func() {
+						Expect(addErr).To(MatchError(`the launch template of nodegroup "ng-abcd1234" must use the instance profile set in iam.instanceProfileARN (` + instanceProfileARN + `), otherwise its nodes cannot join the cluster`))
+					})
+				})
+
+				It("references the launch template in the ASG", func() {
+					launchTemplate := ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate
+					Expect(launchTemplate.LaunchTemplateId).To(Equal("lt-1234"))
+					Expect(launchTemplate.LaunchTemplateName).To(BeNil())
+					Expect(launchTemplate.Version).To(Equal("3"))
+				})
+
+				When("the launch template is referenced by name", func() {
+					BeforeEach(func() {
+						ng.LaunchTemplate = &api.LaunchTemplate{
+							Name:    "my-launch-template",
+							Version: aws.String("1"),
+						}
+						mockLaunchTemplate(func(input *ec2.DescribeLaunchTemplateVersionsInput) bool {
+							return aws.ToString(input.LaunchTemplateName) == "my-launch-template"
+						}, &ec2types.LaunchTemplateIamInstanceProfileSpecification{Name: aws.String("node-profile")})
+					})
+
+					It("references the launch template by name in the ASG", func() {
+						launchTemplate := ngTemplate.Resources["NodeGroup"].Properties.LaunchTemplate
+						Expect(launchTemplate.LaunchTemplateId).To(BeNil())
+						Expect(launchTemplate.LaunchTemplateName).To(Equal("my-launch-template"))
+						Expect(launchTemplate.Version).To(Equal("1"))
+					})
+				})
+			})
+
+			Context("ng.ShutdownBehavior is set", func() {
+				BeforeEach(func() {
+					ng.ShutdownBehavior = api.ShutdownBehaviorTerminate
+				})
+
+				It("sets the shutdown behavior of the instances", func() {
+					launchTemplateData := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData
+					Expect(launchTemplateData.InstanceInitiatedShutdownBehavior).To(Equal("terminate"))
+				})
+			})
+
+			It("does not set the hostname type of the instances by default", func() {
+				Expect(ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.PrivateDnsNameOptions).To(BeNil())
+			})
+
+			Context("cfg.PrivateDNSNameOptions is set without a hostname type", func() {
+				BeforeEach(func() {
+					cfg.PrivateDNSNameOptions = &api.PrivateDNSNameOptions{}
+					api.SetClusterConfigDefaults(cfg)
+				})
+
+				It("does not set the hostname type of the instances", func() {
+					Expect(ng.PrivateDNSNameOptions).To(BeNil())
+					Expect(ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.PrivateDnsNameOptions).To(BeNil())
+				})
+			})
+
+			Context("ng.PrivateDNSNameOptions is set", func() {
+				BeforeEach(func() {
+					ng.PrivateDNSNameOptions = &api.PrivateDNSNameOptions{HostnameType: api.HostnameTypeResourceName}
+				})
+
+				It("sets the hostname type of the instances", func() {
+					launchTemplateData := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData
+					Expect(launchTemplateData.PrivateDnsNameOptions).NotTo(BeNil())
+					Expect(launchTemplateData.PrivateDnsNameOptions.HostnameType).To(Equal("resource-name"))
+					Expect(launchTemplateData.BlockDeviceMappings).NotTo(BeEmpty())
+				})
+			})
+
+			Context("ng.Spot is set", func() {
+				BeforeEach(func() {
+					ng.Spot = true
+				})
+
+				It("launches spot instances", func() {
+					marketOptions := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.InstanceMarketOptions
+					Expect(marketOptions).NotTo(BeNil())
+					Expect(marketOptions.MarketType).To(Equal("spot"))
+					Expect(marketOptions.SpotOptions.SpotInstanceType).To(BeEmpty())
+				})
+
+				When("the nodegroup has mixed instances", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
+							InstanceTypes: []string{"m5.large", "m5a.large"},
+						}
+					})
+
+					It("does not set the market options in the launch template", func() {
+						Expect(ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.InstanceMarketOptions).To(BeNil())
+					})
+				})
+			})
+
+			Context("ng.InstancesDistribution and ng.InstancesDistribution.CapacityRebalance are set", func() {
+				BeforeEach(func() {
+					ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
+						CapacityRebalance: true,
+					}
+				})
+
+				It("sets CapacityRebalance on the resource", func() {
+					Expect(ngTemplate.Resources["NodeGroup"].Properties.CapacityRebalance).To(BeTrue())
+				})
+			})
+
+			Context("ng.PropagateASGTags", func() {
+				When("PropagateASGTags is enabled and there are labels and taints", func() {
+					BeforeEach(func() {
 						ng.PropagateASGTags = api.Enabled()
 						ng.Labels = map[string]string{
 							"test": "label",
@@ -921,8 +1662,13 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					ng.MinSize = aws.Int(3)
 				})
 
-				It("sets MinSize on the resource", func() {
-					Expect(ngTemplate.Resources["NodeGroup"].Properties.MinSize).To(Equal("3"))
+				It("sets MinSize on the resource as a parameter", func() {
+					Expect(ngTemplate.Resources["NodeGroup"].Properties.MinSize).To(Equal(map[string]interface{}{"Ref": builder.NodeGroupMinSizeParameter}))
+					Expect(ngTemplate.Parameters).To(HaveKeyWithValue(builder.NodeGroupMinSizeParameter, fakes.Parameter{
+						Type:        "Number",
+						Description: "Minimum size of the nodegroup",
+						Default:     "3",
+					}))
 				})
 			})
 
@@ -931,8 +1677,34 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					ng.MaxSize = aws.Int(7)
 				})
 
-				It("sets MaxSize on the resource", func() {
-					Expect(ngTemplate.Resources["NodeGroup"].Properties.MaxSize).To(Equal("7"))
+				It("sets MaxSize on the resource as a parameter", func() {
+					Expect(ngTemplate.Resources["NodeGroup"].Properties.MaxSize).To(Equal(map[string]interface{}{"Ref": builder.NodeGroupMaxSizeParameter}))
+					Expect(ngTemplate.Parameters[builder.NodeGroupMaxSizeParameter].Default).To(Equal("7"))
+				})
+			})
+
+			Context("ng.DesiredCapacity is set", func() {
+				BeforeEach(func() {
+					ng.MinSize = aws.Int(1)
+					ng.DesiredCapacity = aws.Int(4)
+					ng.MaxSize = aws.Int(7)
+				})
+
+				It("sets DesiredCapacity on the resource as a parameter", func() {
+					Expect(ngTemplate.Resources["NodeGroup"].Properties.DesiredCapacity).To(Equal(map[string]interface{}{"Ref": builder.NodeGroupDesiredCapacityParameter}))
+					Expect(ngTemplate.Parameters[builder.NodeGroupDesiredCapacityParameter].Default).To(Equal("4"))
+				})
+			})
+
+			Context("ng.DesiredCapacity is not set", func() {
+				BeforeEach(func() {
+					ng.MinSize = aws.Int(2)
+					ng.DesiredCapacity = nil
+					ng.MaxSize = aws.Int(7)
+				})
+
+				It("defaults the DesiredCapacity parameter to MinSize", func() {
+					Expect(ngTemplate.Parameters[builder.NodeGroupDesiredCapacityParameter].Default).To(Equal("2"))
 				})
 			})
 
@@ -1005,7 +1777,47 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 
 					It("adds max price to the mixed instance policy", func() {
 						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
-						Expect(policyTemplate.InstancesDistribution.SpotMaxPrice).To(Equal("20.000000"))
+						Expect(policyTemplate.InstancesDistribution.SpotMaxPrice).To(Equal("20"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.MaxPrice has decimal places", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.MaxPrice = aws.Float64(0.04)
+					})
+
+					It("adds max price without trailing zeros to the mixed instance policy", func() {
+						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
+						Expect(policyTemplate.InstancesDistribution.SpotMaxPrice).To(Equal("0.04"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.MaxPrice is more precise than spot prices", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.MaxPrice = aws.Float64(0.1234567)
+					})
+
+					It("rounds max price to six decimal places", func() {
+						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
+						Expect(policyTemplate.InstancesDistribution.SpotMaxPrice).To(Equal("0.123457"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.MaxPrice is nil", func() {
+					It("does not add max price to the mixed instance policy", func() {
+						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
+						Expect(policyTemplate.InstancesDistribution.SpotMaxPrice).To(BeEmpty())
+					})
+				})
+
+				Context("ng.InstancesDistribution.MaxPrice is zero", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.MaxPrice = aws.Float64(0)
+					})
+
+					It("does not add max price to the mixed instance policy", func() {
+						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
+						Expect(policyTemplate.InstancesDistribution.SpotMaxPrice).To(BeEmpty())
 					})
 				})
 
@@ -1031,6 +1843,50 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					})
 				})
 
+				Context("ng.InstancesDistribution.OnDemandAllocationStrategy is not nil", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.OnDemandAllocationStrategy = aws.String(api.OnDemandAllocationStrategyPrioritized)
+					})
+
+					It("adds the on demand allocation strategy to the mixed instance policy", func() {
+						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
+						Expect(policyTemplate.InstancesDistribution.OnDemandAllocationStrategy).To(Equal("prioritized"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.OnDemandAllocationStrategy is nil", func() {
+					It("does not add the on demand allocation strategy to the mixed instance policy", func() {
+						templateBody, err := ngrs.RenderJSON()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(templateBody)).NotTo(ContainSubstring("OnDemandAllocationStrategy"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.CapacityReservation is not nil", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.OnDemandBaseCapacity = aws.Int(2)
+						ng.InstancesDistribution.CapacityReservation = &api.InstancesDistributionCapacityReservation{
+							CapacityReservationPreference:        api.CapacityReservationPreferenceReservationsFirst,
+							CapacityReservationResourceGroupARNs: []string{"arn:aws:resource-groups:us-west-2:123456789012:group/reservations"},
+						}
+					})
+
+					It("adds the capacity reservation preferences to the resource", func() {
+						spec := ngTemplate.Resources["NodeGroup"].Properties.CapacityReservationSpecification
+						Expect(spec).NotTo(BeNil())
+						Expect(spec.CapacityReservationPreference).To(Equal("capacity-reservations-first"))
+						Expect(spec.CapacityReservationTarget).NotTo(BeNil())
+						Expect(spec.CapacityReservationTarget.CapacityReservationResourceGroupArns).To(ConsistOf("arn:aws:resource-groups:us-west-2:123456789012:group/reservations"))
+						Expect(spec.CapacityReservationTarget.CapacityReservationIds).To(BeEmpty())
+					})
+				})
+
+				Context("ng.InstancesDistribution.CapacityReservation is nil", func() {
+					It("does not add capacity reservation preferences to the resource", func() {
+						Expect(ngTemplate.Resources["NodeGroup"].Properties.CapacityReservationSpecification).To(BeNil())
+					})
+				})
+
 				Context("ng.InstancesDistribution.SpotInstancePools is not nil", func() {
 					BeforeEach(func() {
 						ng.InstancesDistribution.SpotInstancePools = aws.Int(2)
@@ -1044,12 +1900,137 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 
 				Context("ng.InstancesDistribution.SpotAllocationStrategy is not nil", func() {
 					BeforeEach(func() {
-						ng.InstancesDistribution.SpotAllocationStrategy = aws.String("foo")
+						ng.InstancesDistribution.SpotAllocationStrategy = aws.String(api.SpotAllocationStrategyPriceCapacityOptimized)
 					})
 
-					It("adds spot instance pools to the mixed instance policy", func() {
+					It("adds the spot allocation strategy to the mixed instance policy", func() {
+						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
+						Expect(policyTemplate.InstancesDistribution.SpotAllocationStrategy).To(Equal("price-capacity-optimized"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.SpotAllocationStrategy is capacity-optimized-prioritized", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.InstanceTypes = []string{"type-2", "type-3", "type-1"}
+						ng.InstancesDistribution.SpotAllocationStrategy = aws.String(api.SpotAllocationStrategyCapacityOptimizedPrioritized)
+					})
+
+					It("keeps the order of the instance types, which is their priority", func() {
 						policyTemplate := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy
-						Expect(policyTemplate.InstancesDistribution.SpotAllocationStrategy).To(Equal("foo"))
+						Expect(policyTemplate.InstancesDistribution.SpotAllocationStrategy).To(Equal("capacity-optimized-prioritized"))
+						Expect(policyTemplate.LaunchTemplate.Overrides).To(HaveLen(3))
+						Expect(policyTemplate.LaunchTemplate.Overrides[0].InstanceType).To(Equal("type-2"))
+						Expect(policyTemplate.LaunchTemplate.Overrides[1].InstanceType).To(Equal("type-3"))
+						Expect(policyTemplate.LaunchTemplate.Overrides[2].InstanceType).To(Equal("type-1"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.SpotAllocationStrategy is unknown", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.SpotAllocationStrategy = aws.String("capacity-optimised")
+					})
+
+					It("rejects the strategy before adding the nodegroup resources", func() {
+						Expect(addErr).To(MatchError("spotAllocationStrategy should be one of: lowest-price, capacity-optimized, capacity-optimized-prioritized, price-capacity-optimized"))
+						Expect(ngTemplate.Resources).NotTo(HaveKey("NodeGroup"))
+					})
+				})
+
+				Context("ng.InstancesDistribution.InstanceTypeOverrides are set", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.InstanceTypes = nil
+						ng.InstancesDistribution.SpotAllocationStrategy = aws.String(api.SpotAllocationStrategyCapacityOptimizedPrioritized)
+						ng.InstancesDistribution.InstanceTypeOverrides = []api.InstanceTypeOverride{
+							{InstanceType: "type-2", WeightedCapacity: aws.Int(2)},
+							{InstanceType: "type-1", WeightedCapacity: aws.Int(1)},
+						}
+					})
+
+					It("adds the overrides in order with their weighted capacity to the mixed instance policy", func() {
+						overrides := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy.LaunchTemplate.Overrides
+						Expect(overrides).To(HaveLen(2))
+						Expect(overrides[0].InstanceType).To(Equal("type-2"))
+						Expect(overrides[0].WeightedCapacity).To(Equal("2"))
+						Expect(overrides[1].InstanceType).To(Equal("type-1"))
+						Expect(overrides[1].WeightedCapacity).To(Equal("1"))
+					})
+
+					It("uses the first instance type of the overrides in the launch template", func() {
+						Expect(ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.InstanceType).To(Equal("type-2"))
+					})
+
+					Context("without a spot allocation strategy", func() {
+						BeforeEach(func() {
+							ng.InstancesDistribution.SpotAllocationStrategy = nil
+							ng.InstancesDistribution.InstanceTypeOverrides = []api.InstanceTypeOverride{
+								{InstanceType: "m5.8xlarge", WeightedCapacity: aws.Int(8)},
+								{InstanceType: "m5.xlarge", WeightedCapacity: aws.Int(1)},
+							}
+						})
+
+						It("adds the weighted capacity of each override to the mixed instance policy", func() {
+							overrides := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy.LaunchTemplate.Overrides
+							Expect(overrides).To(HaveLen(2))
+							Expect(overrides[0].InstanceType).To(Equal("m5.8xlarge"))
+							Expect(overrides[0].WeightedCapacity).To(Equal("8"))
+							Expect(overrides[1].InstanceType).To(Equal("m5.xlarge"))
+							Expect(overrides[1].WeightedCapacity).To(Equal("1"))
+						})
+					})
+
+					Context("without weights", func() {
+						BeforeEach(func() {
+							for i := range ng.InstancesDistribution.InstanceTypeOverrides {
+								ng.InstancesDistribution.InstanceTypeOverrides[i].WeightedCapacity = nil
+							}
+						})
+
+						It("does not add a weighted capacity to the overrides", func() {
+							overrides := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy.LaunchTemplate.Overrides
+							Expect(overrides).To(HaveLen(2))
+							Expect(overrides[0].InstanceType).To(Equal("type-2"))
+							Expect(overrides[0].WeightedCapacity).To(BeEmpty())
+							Expect(overrides[1].InstanceType).To(Equal("type-1"))
+							Expect(overrides[1].WeightedCapacity).To(BeEmpty())
+						})
+					})
+				})
+
+				Context("ng.InstancesDistribution.InstanceRequirements are set", func() {
+					BeforeEach(func() {
+						ng.InstancesDistribution.InstanceTypes = nil
+						ng.InstancesDistribution.InstanceRequirements = &api.InstanceRequirements{
+							VCPUCount: &api.InstanceRequirementsRange{
+								Min: aws.Int(2),
+								Max: aws.Int(8),
+							},
+							MemoryMiB: &api.InstanceRequirementsRange{
+								Min: aws.Int(4096),
+							},
+							CPUManufacturers:      []string{"intel"},
+							ExcludedInstanceTypes: []string{"t2.*"},
+						}
+					})
+
+					It("adds the instance requirements as the only override of the mixed instance policy", func() {
+						overrides := ngTemplate.Resources["NodeGroup"].Properties.MixedInstancesPolicy.LaunchTemplate.Overrides
+						Expect(overrides).To(HaveLen(1))
+						Expect(overrides[0].InstanceType).To(BeEmpty())
+						Expect(overrides[0].InstanceRequirements).To(Equal(map[string]interface{}{
+							"VCpuCount": map[string]interface{}{
+								"Min": 2.0,
+								"Max": 8.0,
+							},
+							"MemoryMiB": map[string]interface{}{
+								"Min": 4096.0,
+							},
+							"CpuManufacturers":      []interface{}{"intel"},
+							"ExcludedInstanceTypes": []interface{}{"t2.*"},
+						}))
+					})
+
+					It("does not set an instance type in the launch template", func() {
+						Expect(ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.InstanceType).To(BeEmpty())
 					})
 				})
 			})
@@ -1095,6 +2076,51 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 			})
 
+			Context("ng.SSH.PublicKey", func() {
+				const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBoB6Gtu8zPAPO1yF4OwysWUD8ZSEQYzMpOT0YvF9qJV user@example\n"
+
+				BeforeEach(func() {
+					ng.SSH = &api.NodeGroupSSH{
+						Allow:     aws.Bool(true),
+						PublicKey: aws.String(publicKey),
+					}
+				})
+
+				It("imports the key as a new key pair", func() {
+					Expect(ngTemplate.Resources).To(HaveKey("SSHKeyPair"))
+					keyPair := ngTemplate.Resources["SSHKeyPair"]
+					Expect(keyPair.Type).To(Equal("AWS::EC2::KeyPair"))
+					Expect(keyPair.Properties.KeyName).To(Equal("eksctl-bonsai-nodegroup-ng-abcd1234-HvE7+gmH78VS53+iPuRDh/gKjVo26OzYU/qOnJWAgyk"))
+					Expect(keyPair.Properties.PublicKeyMaterial).To(Equal(publicKey))
+				})
+
+				It("references the key pair in the launch template data", func() {
+					properties := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties
+					Expect(properties.LaunchTemplateData.KeyName).To(Equal(map[string]interface{}{"Ref": "SSHKeyPair"}))
+				})
+
+				When("SSH is not allowed", func() {
+					BeforeEach(func() {
+						ng.SSH.Allow = aws.Bool(false)
+					})
+
+					It("does not import the key", func() {
+						Expect(ngTemplate.Resources).NotTo(HaveKey("SSHKeyPair"))
+						Expect(ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.KeyName).To(BeNil())
+					})
+				})
+
+				When("the key is invalid", func() {
+					BeforeEach(func() {
+						ng.SSH.PublicKey = aws.String("not-a-key")
+					})
+
+					It("errors", func() {
+						Expect(addErr).To(MatchError(ContainSubstring("parsing key")))
+					})
+				})
+			})
+
 			Context("ng.VolumeSize > 0", func() {
 				BeforeEach(func() {
 					ng.VolumeSize = aws.Int(20)
@@ -1146,6 +2172,19 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					})
 				})
 
+				Context("ng.VolumeType is GP2 and ng.VolumeThroughput is set", func() {
+					BeforeEach(func() {
+						ng.VolumeType = aws.String(api.NodeVolumeTypeGP2)
+						ng.VolumeThroughput = aws.Int(500)
+					})
+
+					It("Throughput is not set on the block device mapping", func() {
+						mapping := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.BlockDeviceMappings[0]
+						Expect(mapping.Ebs["VolumeType"]).To(Equal(api.NodeVolumeTypeGP2))
+						Expect(mapping.Ebs).NotTo(HaveKey("Throughput"))
+					})
+				})
+
 				Context("ng.AdditionalEncryptedVolume is set", func() {
 					BeforeEach(func() {
 						ng.AdditionalEncryptedVolume = "/foo/bar"
@@ -1180,6 +2219,55 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 						Expect(mapping.Ebs["VolumeSize"]).To(Equal(float64(20)))
 						Expect(mapping.Ebs["VolumeType"]).To(Equal(api.NodeVolumeTypeGP3))
 						Expect(mapping.Ebs["SnapshotId"]).To(Equal("snapshot-id"))
+						Expect(mapping.Ebs).NotTo(HaveKey("VolumeInitializationRate"))
+					})
+					When("VolumeInitializationRate is set", func() {
+						BeforeEach(func() {
+							ng.AdditionalVolumes[0].VolumeInitializationRate = aws.Int(200)
+						})
+						It("sets the volume initialization rate on the additional volume only", func() {
+							mappings := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.BlockDeviceMappings
+							Expect(mappings).To(HaveLen(2))
+							Expect(mappings[0].Ebs).NotTo(HaveKey("VolumeInitializationRate"))
+							Expect(mappings[1].DeviceName).To(Equal("/foo/bar-add-1"))
+							Expect(mappings[1].Ebs["SnapshotId"]).To(Equal("snapshot-id"))
+							Expect(mappings[1].Ebs["VolumeInitializationRate"]).To(Equal(float64(200)))
+						})
+					})
+					When("the root and additional volumes are gp3 volumes with different throughput", func() {
+						BeforeEach(func() {
+							ng.VolumeType = aws.String(api.NodeVolumeTypeGP3)
+							ng.VolumeIOPS = aws.Int(3000)
+							ng.VolumeThroughput = aws.Int(125)
+							ng.AdditionalVolumes = []*api.VolumeMapping{
+								{
+									VolumeSize:       aws.Int(20),
+									VolumeType:       aws.String(api.NodeVolumeTypeGP3),
+									VolumeName:       aws.String("/foo/bar-add-1"),
+									VolumeIOPS:       aws.Int(4000),
+									VolumeThroughput: aws.Int(250),
+								},
+								{
+									VolumeSize:       aws.Int(100),
+									VolumeType:       aws.String(api.NodeVolumeTypeGP3),
+									VolumeName:       aws.String("/foo/bar-add-2"),
+									VolumeIOPS:       aws.Int(16000),
+									VolumeThroughput: aws.Int(1000),
+								},
+							}
+						})
+						It("sets the IOPS and throughput of each volume on its block device mapping", func() {
+							mappings := ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.BlockDeviceMappings
+							Expect(mappings).To(HaveLen(3))
+							Expect(mappings[0].Ebs["Iops"]).To(Equal(float64(3000)))
+							Expect(mappings[0].Ebs["Throughput"]).To(Equal(float64(125)))
+							Expect(mappings[1].DeviceName).To(Equal("/foo/bar-add-1"))
+							Expect(mappings[1].Ebs["Iops"]).To(Equal(float64(4000)))
+							Expect(mappings[1].Ebs["Throughput"]).To(Equal(float64(250)))
+							Expect(mappings[2].DeviceName).To(Equal("/foo/bar-add-2"))
+							Expect(mappings[2].Ebs["Iops"]).To(Equal(float64(16000)))
+							Expect(mappings[2].Ebs["Throughput"]).To(Equal(float64(1000)))
+						})
 					})
 					When("VolumeSize is empty", func() {
 						BeforeEach(func() {
@@ -1253,6 +2341,137 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 			})
 		})
+
+		Context("rendering for a CloudFormation resource specification", func() {
+			It("returns an error listing the resource types that are not supported", func() {
+				spec := &builder.ResourceSpecification{
+					ResourceSpecificationVersion: "1.0.0",
+					ResourceTypes: map[string]builder.ResourceSpecificationType{
+						"AWS::EC2::SecurityGroup": {},
+					},
+				}
+				_, err := ngrs.RenderJSONForSpecification(spec)
+				Expect(err).To(MatchError(ContainSubstring("not supported by CloudFormation resource specification version 1.0.0")))
+				Expect(err).To(MatchError(ContainSubstring("Resources.NodeGroupLaunchTemplate.Type (AWS::EC2::LaunchTemplate)")))
+				Expect(err).To(MatchError(ContainSubstring("Resources.NodeGroup.Type (AWS::AutoScaling::AutoScalingGroup)")))
+			})
+		})
+	})
+
+	Describe("scaling parameters", func() {
+		DescribeTable("only adds the parameters of the sizes that are set", func(minSize, maxSize, desiredCapacity *int, expectedParameters map[string]string) {
+			ng.MinSize = minSize
+			ng.MaxSize = maxSize
+			ng.DesiredCapacity = desiredCapacity
+
+			ngrs := builder.NewNodeGroupResourceSet(mockEC2, mockIAM, cfg, ng, fakeBootstrapper, forceAddCNIPolicy, fakeVPCImporter)
+			Expect(ngrs.AddScalingParameters).NotTo(Panic())
+			templateBody, err := ngrs.RenderJSON()
+			Expect(err).NotTo(HaveOccurred())
+			var template struct {
+				Parameters map[string]struct {
+					Default string
+				}
+			}
+			Expect(json.Unmarshal(templateBody, &template)).To(Succeed())
+			defaults := map[string]string{}
+			for name, parameter := range template.Parameters {
+				defaults[name] = parameter.Default
+			}
+			Expect(defaults).To(Equal(expectedParameters))
+		},
+			Entry("all sizes are set", aws.Int(1), aws.Int(4), aws.Int(2), map[string]string{
+				builder.NodeGroupMinSizeParameter:         "1",
+				builder.NodeGroupMaxSizeParameter:         "4",
+				builder.NodeGroupDesiredCapacityParameter: "2",
+			}),
+			Entry("the desired capacity is not set", aws.Int(1), aws.Int(4), nil, map[string]string{
+				builder.NodeGroupMinSizeParameter:         "1",
+				builder.NodeGroupMaxSizeParameter:         "4",
+				builder.NodeGroupDesiredCapacityParameter: "1",
+			}),
+			Entry("only the desired capacity is set", nil, nil, aws.Int(2), map[string]string{
+				builder.NodeGroupDesiredCapacityParameter: "2",
+			}),
+			Entry("no size is set", nil, nil, nil, map[string]string{}),
+		)
+	})
+
+	Describe("tags colliding with tags set by eksctl", func() {
+		type tagCollisionEntry struct {
+			tags          map[string]string
+			metadataTags  map[string]string
+			autoScaler    bool
+			key           string
+			expectedValue string
+		}
+
+		DescribeTable("keeps the tag set by eksctl", func(e tagCollisionEntry) {
+			ng.Tags = e.tags
+			cfg.Metadata.Tags = e.metadataTags
+			ng.IAM.WithAddonPolicies.AutoScaler = aws.Bool(e.autoScaler)
+
+			ngrs := builder.NewNodeGroupResourceSet(mockEC2, mockIAM, cfg, ng, fakeBootstrapper, forceAddCNIPolicy, fakeVPCImporter)
+			Expect(ngrs.AddAllResources(context.Background())).To(Succeed())
+			templateBody, err := ngrs.RenderJSON()
+			Expect(err).NotTo(HaveOccurred())
+			ngTemplate := &fakes.FakeTemplate{}
+			Expect(json.Unmarshal(templateBody, ngTemplate)).To(Succeed())
+
+			countKey := func(tags []fakes.Tag) int {
+				count := 0
+				for _, tag := range tags {
+					if tag.Key == e.key {
+						count++
+						Expect(tag.Value).To(Equal(e.expectedValue))
+					}
+				}
+				return count
+			}
+			Expect(countKey(ngTemplate.Resources["NodeGroup"].Properties.Tags)).To(Equal(1))
+			for _, tagSpecification := range ngTemplate.Resources["NodeGroupLaunchTemplate"].Properties.LaunchTemplateData.TagSpecifications {
+				Expect(countKey(tagSpecification.Tags)).To(BeNumerically("<=", 1))
+			}
+			if _, ok := e.tags[e.key]; ok {
+				Expect(ng.Tags).To(HaveKeyWithValue(e.key, e.tags[e.key]))
+			}
+			stackTags := ngrs.WithoutReservedTags(ng.Tags)
+			Expect(stackTags).NotTo(HaveKey(e.key))
+			Expect(stackTags).To(HaveLen(len(ng.Tags) - 1))
+		},
+			Entry("Name in nodegroup tags", tagCollisionEntry{
+				tags:          map[string]string{"Name": "custom", "team": "a"},
+				key:           "Name",
+				expectedValue: "bonsai-ng-abcd1234-Node",
+			}),
+			Entry("Name in metadata.tags", tagCollisionEntry{
+				metadataTags:  map[string]string{"Name": "custom"},
+				key:           "Name",
+				expectedValue: "bonsai-ng-abcd1234-Node",
+			}),
+			Entry("cluster ownership tag in nodegroup tags", tagCollisionEntry{
+				tags:          map[string]string{"kubernetes.io/cluster/bonsai": "shared"},
+				key:           "kubernetes.io/cluster/bonsai",
+				expectedValue: "owned",
+			}),
+			Entry("cluster ownership tag in metadata.tags", tagCollisionEntry{
+				metadataTags:  map[string]string{"kubernetes.io/cluster/bonsai": "shared"},
+				key:           "kubernetes.io/cluster/bonsai",
+				expectedValue: "owned",
+			}),
+			Entry("cluster autoscaler discovery tag", tagCollisionEntry{
+				tags:          map[string]string{"k8s.io/cluster-autoscaler/enabled": "false"},
+				autoScaler:    true,
+				key:           "k8s.io/cluster-autoscaler/enabled",
+				expectedValue: "true",
+			}),
+			Entry("cluster autoscaler ownership tag", tagCollisionEntry{
+				tags:          map[string]string{"k8s.io/cluster-autoscaler/bonsai": "shared"},
+				autoScaler:    true,
+				key:           "k8s.io/cluster-autoscaler/bonsai",
+				expectedValue: "owned",
+			}),
+		)
 	})
 
 	Describe("AssignSubnets", func() {
@@ -1263,7 +2482,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 		})
 
 		It("returns public subnets", func() {
-			subnets, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, nil)
+			subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(subnets).To(Equal(gfnt.NewString("subnet-1")))
 		})
@@ -1282,7 +2501,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			}, nil)
 			ngBase := ngBase.DeepCopy()
 			ngBase.Subnets = []string{"fake-id"}
-			subnets, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, mockEC2)
+			subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockEC2)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(subnets).To(Equal(gfnt.NewStringSlice("fake-id")))
 		})
@@ -1301,7 +2520,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			}, nil)
 			ngBase := ngBase.DeepCopy()
 			ngBase.Subnets = []string{"fake-id"}
-			_, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, mockEC2)
+			_, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockEC2)
 			Expect(err).To(MatchError(ContainSubstring("subnet with id \"fake-id\" is not in the attached vpc with id \"\"")))
 		})
 
@@ -1312,7 +2531,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			}).Return(nil, errors.New("nope"))
 			ngBase := ngBase.DeepCopy()
 			ngBase.Subnets = []string{"fake-id"}
-			_, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, mockEC2)
+			_, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockEC2)
 			Expect(err).To(MatchError(ContainSubstring("nope")))
 		})
 
@@ -1323,7 +2542,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			})
 
 			It("returns private subnets", func() {
-				subnets, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, nil)
+				subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(subnets).To(Equal(gfnt.NewString("subnet-2")))
 			})
@@ -1336,7 +2555,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 			})
 
 			It("maps subnets to azs", func() {
-				subnets, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, nil)
+				subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet1, publicSubnet2)))
 			})
@@ -1348,7 +2567,7 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 				})
 
 				It("maps private subnets to azs", func() {
-					subnets, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, nil)
+					subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(subnets).To(Equal(gfnt.NewStringSlice(privateSubnet1, privateSubnet2)))
 				})
@@ -1364,22 +2583,218 @@ var _ = Describe("Unmanaged NodeGroup Template Builder", func() {
 					mockEC2.On("DescribeSubnets", mock.Anything, &ec2.DescribeSubnetsInput{
 						SubnetIds: []string{"not-a-thing"},
 					}).Return(nil, errors.New("nope"))
-					_, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, mockEC2)
+					_, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockEC2)
 					Expect(err).To(MatchError(ContainSubstring("couldn't find public subnets")))
 				})
 			})
 		})
 
-		Context("when EFA is enabled and > 1 subnets are set", func() {
+		Context("when outpostARN is set", func() {
+			const outpostARN = "arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0"
+
+			mockSubnets := func(subnetIDs []string, outpostSubnetIDs ...string) *mocksv2.EC2 {
+				var subnets []ec2types.Subnet
+				for _, subnetID := range subnetIDs {
+					subnet := ec2types.Subnet{
+						SubnetId: aws.String(subnetID),
+						VpcId:    aws.String(cfg.VPC.ID),
+					}
+					for _, id := range outpostSubnetIDs {
+						if id == subnetID {
+							subnet.OutpostArn = aws.String(outpostARN)
+						}
+					}
+					subnets = append(subnets, subnet)
+				}
+				mockEC2 := &mocksv2.EC2{}
+				mockEC2.On("DescribeSubnets", mock.Anything, &ec2.DescribeSubnetsInput{
+					SubnetIds: subnetIDs,
+				}).Return(&ec2.DescribeSubnetsOutput{
+					Subnets: subnets,
+				}, nil)
+				return mockEC2
+			}
+
+			BeforeEach(func() {
+				ngBase.OutpostARN = outpostARN
+			})
+
+			It("chooses the cluster subnets on the Outpost", func() {
+				subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockSubnets([]string{publicSubnet1, publicSubnet2}, publicSubnet2))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet2)))
+			})
+
+			Context("and private networking is enabled", func() {
+				BeforeEach(func() {
+					ngBase.PrivateNetworking = true
+				})
+
+				It("chooses the private cluster subnets on the Outpost", func() {
+					subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockSubnets([]string{privateSubnet1, privateSubnet2}, privateSubnet1, privateSubnet2))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(subnets).To(Equal(gfnt.NewStringSlice(privateSubnet1, privateSubnet2)))
+				})
+			})
+
+			Context("and subnets are set", func() {
+				BeforeEach(func() {
+					ngBase.Subnets = []string{publicSubnet1}
+				})
+
+				It("only considers the nodegroup's subnets", func() {
+					subnets, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockSubnets([]string{publicSubnet1}, publicSubnet1))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet1)))
+				})
+			})
+
+			Context("and none of the subnets are on the Outpost", func() {
+				It("returns an error listing the subnets considered", func() {
+					_, err := builder.AssignSubnets(context.Background(), ngBase, nil, fakeVPCImporter, cfg, mockSubnets([]string{publicSubnet1, publicSubnet2}))
+					Expect(err).To(MatchError(fmt.Sprintf(`none of the public subnets considered for nodegroup "ng-abcd1234" (%s, %s) are on Outpost %q; set subnets to subnets of the VPC on the Outpost`, publicSubnet1, publicSubnet2, outpostARN)))
+				})
+			})
+		})
+
+		Context("when EFA is enabled", func() {
+			mockOfferings := func(azs ...string) *mocksv2.EC2 {
+				var offerings []ec2types.InstanceTypeOffering
+				for _, az := range azs {
+					offerings = append(offerings, ec2types.InstanceTypeOffering{
+						InstanceType: ec2types.InstanceTypeM5Large,
+						Location:     aws.String(az),
+					})
+				}
+				mockEC2 := &mocksv2.EC2{}
+				mockEC2.On("DescribeInstanceTypeOfferings", mock.Anything, &ec2.DescribeInstanceTypeOfferingsInput{
+					LocationType: ec2types.LocationTypeAvailabilityZone,
+					Filters: []ec2types.Filter{
+						{
+							Name:   aws.String("instance-type"),
+							Values: []string{"m5.large"},
+						},
+					},
+				}).Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+					InstanceTypeOfferings: offerings,
+				}, nil)
+				return mockEC2
+			}
+
 			BeforeEach(func() {
-				ngBase.Subnets = []string{publicSubnet1, publicSubnet2}
 				ngBase.EFAEnabled = aws.Bool(true)
 			})
 
-			It("choses only the first subnet", func() {
-				subnets, err := builder.AssignSubnets(context.Background(), ngBase, fakeVPCImporter, cfg, nil)
+			Context("and > 1 subnets are set", func() {
+				BeforeEach(func() {
+					ngBase.Subnets = []string{publicSubnet1, publicSubnet2}
+				})
+
+				It("chooses the first subnet in an AZ that offers the instance type", func() {
+					subnets, err := builder.AssignSubnets(context.Background(), ngBase, []string{"m5.large"}, fakeVPCImporter, cfg, mockOfferings(azA, azB))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet1)))
+
+					subnets, err = builder.AssignSubnets(context.Background(), ngBase, []string{"m5.large"}, fakeVPCImporter, cfg, mockOfferings(azB, azC))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet2)))
+				})
+			})
+
+			Context("and no AZs or subnets are set", func() {
+				It("chooses a cluster subnet in an AZ that offers the instance type", func() {
+					subnets, err := builder.AssignSubnets(context.Background(), ngBase, []string{"m5.large"}, fakeVPCImporter, cfg, mockOfferings(azB))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet2)))
+				})
+			})
+
+			Context("and none of the nodegroup's AZs offer the instance type", func() {
+				BeforeEach(func() {
+					ngBase.AvailabilityZones = []string{azA}
+				})
+
+				It("returns an error listing the AZs that offer the instance type", func() {
+					_, err := builder.AssignSubnets(context.Background(), ngBase, []string{"m5.large"}, fakeVPCImporter, cfg, mockOfferings(azB, azC))
+					Expect(err).To(MatchError("none of the nodegroup's availability zones (us-west-2a) offer the EFA-enabled instance type(s) m5.large; availability zones that offer them: us-west-2b, us-west-2c"))
+				})
+			})
+		})
+	})
+
+	Describe("AssignSubnetsResolved", func() {
+		var ngBase *api.NodeGroupBase
+		BeforeEach(func() {
+			ngBase = ng.NodeGroupBase
+			fakeVPCImporter.SubnetsPublicReturns(gfnt.MakeFnSplit(",", gfnt.MakeFnImportValueString("cluster::SubnetsPublic")))
+			fakeVPCImporter.SubnetsPrivateReturns(gfnt.MakeFnSplit(",", gfnt.MakeFnImportValueString("cluster::SubnetsPrivate")))
+		})
+
+		It("resolves imported public subnets from the cluster VPC", func() {
+			subnets, subnetIDs, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnets).To(Equal(gfnt.MakeFnSplit(",", gfnt.MakeFnImportValueString("cluster::SubnetsPublic"))))
+			Expect(subnetIDs).To(Equal([]string{publicSubnet1, publicSubnet2}))
+		})
+
+		It("resolves imported private subnets from the cluster VPC", func() {
+			ngBase.PrivateNetworking = true
+			_, subnetIDs, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnetIDs).To(Equal([]string{privateSubnet1, privateSubnet2}))
+		})
+
+		It("returns no IDs when the cluster VPC does not list its subnets", func() {
+			cfg.VPC.Subnets = nil
+			subnets, subnetIDs, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnets).To(Equal(gfnt.MakeFnSplit(",", gfnt.MakeFnImportValueString("cluster::SubnetsPublic"))))
+			Expect(subnetIDs).To(BeNil())
+		})
+
+		It("returns the subnets selected for the nodegroup", func() {
+			ngBase.Subnets = []string{publicSubnet2}
+			subnets, subnetIDs, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet2)))
+			Expect(subnetIDs).To(Equal([]string{publicSubnet2}))
+		})
+
+		Context("the cluster has a Local Zone", func() {
+			const (
+				localZone         = "us-west-2-lax-1a"
+				localPublicSubnet = "subnet-local-public"
+			)
+
+			BeforeEach(func() {
+				cfg.LocalZones = []string{localZone}
+				cfg.VPC.Subnets.Public.SetAZ(localZone, api.Network{ID: localPublicSubnet})
+			})
+
+			It("only places nodegroups that list no zones in the availability zones", func() {
+				subnets, subnetIDs, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet1)))
+				Expect(subnets).To(Equal(gfnt.NewStringSlice(publicSubnet1, publicSubnet2)))
+				Expect(subnetIDs).To(Equal([]string{publicSubnet1, publicSubnet2}))
+			})
+
+			It("places nodegroups in the Local Zone they list", func() {
+				ngBase.AvailabilityZones = []string{localZone}
+				_, subnetIDs, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(subnetIDs).To(Equal([]string{localPublicSubnet}))
+			})
+
+			It("refuses to place a nodegroup in both the Local Zone and an availability zone", func() {
+				ngBase.AvailabilityZones = []string{azA, localZone}
+				_, _, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+				Expect(err).To(MatchError(`nodegroup "ng-abcd1234" cannot be placed in both Local Zones or Wavelength Zones [us-west-2-lax-1a] and availability zones [us-west-2a]; please create a nodegroup for each kind of zone`))
+			})
+
+			It("refuses to place a nodegroup in subnets of both the Local Zone and an availability zone", func() {
+				ngBase.Subnets = []string{publicSubnet1, localPublicSubnet}
+				_, _, err := builder.AssignSubnetsResolved(context.Background(), ngBase, nil, fakeVPCImporter, cfg, nil)
+				Expect(err).To(MatchError(ContainSubstring("cannot be placed in both Local Zones or Wavelength Zones [us-west-2-lax-1a] and availability zones [us-west-2a]")))
 			})
 		})
 	})
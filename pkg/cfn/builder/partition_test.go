@@ -0,0 +1,128 @@
+package builder_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Partitions", func() {
+	type partitionEntry struct {
+		region       string
+		partition    string
+		ec2Principal string
+	}
+
+	// renderForPartition renders the template of the resource set and resolves the references to the partition
+	// as CloudFormation would in a region of that partition
+	renderForPartition := func(rs interface{ RenderJSON() ([]byte, error) }, partition string) string {
+		templateBody, err := rs.RenderJSON()
+		Expect(err).NotTo(HaveOccurred())
+		var template map[string]interface{}
+		Expect(json.Unmarshal(templateBody, &template)).To(Succeed())
+
+		mappings, _ := template["Mappings"].(map[string]interface{})
+		resolved, err := json.Marshal(resolvePartition(template["Resources"], partition, mappings))
+		Expect(err).NotTo(HaveOccurred())
+		return string(resolved)
+	}
+
+	expectPartitionARNs := func(template, partition string) {
+		for _, s := range strings.Split(template, `"`) {
+			if strings.HasPrefix(s, "arn:") {
+				Expect(s).To(HavePrefix("arn:"+partition+":"), "ARN %q does not belong to partition %q", s, partition)
+			}
+		}
+	}
+
+	DescribeTable("renders templates that can be deployed in the partition of the region",
+		func(e partitionEntry) {
+			Expect(api.Partition(e.region)).To(Equal(e.partition))
+
+			cfg := api.NewClusterConfig()
+			cfg.Metadata.Name = "test-partition"
+			cfg.Metadata.Region = e.region
+			cfg.VPC = vpcConfig()
+			cfg.AvailabilityZones = []string{e.region + "a", e.region + "b"}
+			cfg.Karpenter = &api.Karpenter{
+				Version: "0.4.3",
+			}
+
+			provider := mockprovider.NewMockProvider()
+			crs := builder.NewClusterResourceSet(provider.EC2(), e.region, cfg, nil)
+			Expect(crs.AddAllResources(context.Background())).To(Succeed())
+			clusterTemplate := renderForPartition(crs, e.partition)
+			expectPartitionARNs(clusterTemplate, e.partition)
+			Expect(clusterTemplate).To(ContainSubstring(`"eks.amazonaws.com"`))
+
+			frs := builder.NewFargateResourceSet(cfg)
+			Expect(frs.AddAllResources()).To(Succeed())
+			fargateTemplate := renderForPartition(frs, e.partition)
+			expectPartitionARNs(fargateTemplate, e.partition)
+			Expect(fargateTemplate).To(ContainSubstring(`"eks-fargate-pods.amazonaws.com"`))
+
+			krs := builder.NewKarpenterResourceSet(cfg, "eksctl-KarpenterNodeInstanceProfile-test-partition")
+			Expect(krs.AddAllResources()).To(Succeed())
+			karpenterTemplate := renderForPartition(krs, e.partition)
+			expectPartitionARNs(karpenterTemplate, e.partition)
+			Expect(karpenterTemplate).To(ContainSubstring(`"` + e.ec2Principal + `"`))
+		},
+		Entry("aws", partitionEntry{
+			region:       api.RegionUSWest2,
+			partition:    api.PartitionAWS,
+			ec2Principal: "ec2.amazonaws.com",
+		}),
+		Entry("aws-cn", partitionEntry{
+			region:       api.RegionCNNorth1,
+			partition:    api.PartitionChina,
+			ec2Principal: "ec2.amazonaws.com.cn",
+		}),
+		Entry("aws-us-gov", partitionEntry{
+			region:       api.RegionUSGovWest1,
+			partition:    api.PartitionUSGov,
+			ec2Principal: "ec2.amazonaws.com",
+		}),
+	)
+})
+
+// resolvePartition substitutes the references to AWS::Partition, and the lookups in mappings keyed by it, with their
+// values in the partition
+func resolvePartition(obj interface{}, partition string, mappings map[string]interface{}) interface{} {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		if o["Ref"] == "AWS::Partition" {
+			return partition
+		}
+		if sub, ok := o["Fn::Sub"].(string); ok {
+			return strings.ReplaceAll(sub, "${AWS::Partition}", partition)
+		}
+		if args, ok := o["Fn::FindInMap"].([]interface{}); ok && len(args) == 3 {
+			if ref, ok := args[1].(map[string]interface{}); ok && ref["Ref"] == "AWS::Partition" {
+				mapping := mappings[args[0].(string)].(map[string]interface{})
+				Expect(mapping).To(HaveKey(partition))
+				return mapping[partition].(map[string]interface{})[args[2].(string)]
+			}
+		}
+		resolved := make(map[string]interface{}, len(o))
+		for k, v := range o {
+			resolved[k] = resolvePartition(v, partition, mappings)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(o))
+		for i, v := range o {
+			resolved[i] = resolvePartition(v, partition, mappings)
+		}
+		return resolved
+	default:
+		return obj
+	}
+}
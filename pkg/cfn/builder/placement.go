@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/pkg/errors"
+	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
+	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/awsapi"
+)
+
+// makePlacement sets the placement options of the nodegroup on the placement of the launch template, which holds
+// the placement group created for EFA, if any
+func makePlacement(ctx context.Context, launchTemplatePlacement *gfnec2.LaunchTemplate_Placement, placement *api.Placement, ec2API awsapi.EC2) (*gfnec2.LaunchTemplate_Placement, error) {
+	if launchTemplatePlacement == nil {
+		launchTemplatePlacement = &gfnec2.LaunchTemplate_Placement{}
+	}
+	if placement.GroupName != "" {
+		launchTemplatePlacement.GroupName = gfnt.NewString(placement.GroupName)
+	}
+	if placement.Tenancy != "" {
+		launchTemplatePlacement.Tenancy = gfnt.NewString(placement.Tenancy)
+	}
+	if placement.HostResourceGroupARN != "" {
+		launchTemplatePlacement.HostResourceGroupArn = gfnt.NewString(placement.HostResourceGroupARN)
+	}
+	if placement.PartitionNumber != nil {
+		if err := validatePartitionPlacementGroup(ctx, placement.GroupName, ec2API); err != nil {
+			return nil, err
+		}
+		launchTemplatePlacement.PartitionNumber = gfnt.NewInteger(*placement.PartitionNumber)
+	}
+	return launchTemplatePlacement, nil
+}
+
+// validatePartitionPlacementGroup checks that a partition number can be set for the placement group, which is only
+// the case for placement groups with the partition strategy
+func validatePartitionPlacementGroup(ctx context.Context, groupName string, ec2API awsapi.EC2) error {
+	output, err := ec2API.DescribePlacementGroups(ctx, &ec2.DescribePlacementGroupsInput{
+		GroupNames: []string{groupName},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "couldn't retrieve placement group %s", groupName)
+	}
+	if len(output.PlacementGroups) == 0 {
+		return errors.Errorf("placement group %s was not found", groupName)
+	}
+	if strategy := output.PlacementGroups[0].Strategy; strategy != ec2types.PlacementStrategyPartition {
+		return errors.Errorf("placement.partitionNumber can only be set for placement groups with the %s strategy, placement group %s uses the %s strategy",
+			ec2types.PlacementStrategyPartition, groupName, strategy)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ResourceSpecification is a version of the CloudFormation resource specification
+// (https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/cfn-resource-specification.html),
+// used to check that a rendered template only uses resource types and properties available in that version
+type ResourceSpecification struct {
+	ResourceSpecificationVersion string
+	ResourceTypes                map[string]ResourceSpecificationType
+	PropertyTypes                map[string]ResourceSpecificationType
+}
+
+// ResourceSpecificationType holds the properties of a resource type or property type
+type ResourceSpecificationType struct {
+	Properties map[string]ResourceSpecificationProperty
+}
+
+// ResourceSpecificationProperty describes a property of a resource type or property type
+type ResourceSpecificationProperty struct {
+	// Type is the name of the property type, or `List` or `Map`; it is empty for primitive properties
+	Type string
+	// ItemType is the name of the property type of the items of a `List` or `Map` property
+	ItemType string
+}
+
+// LoadResourceSpecification reads a CloudFormation resource specification document
+func LoadResourceSpecification(r io.Reader) (*ResourceSpecification, error) {
+	var spec ResourceSpecification
+	if err := json.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, errors.Wrap(err, "decoding CloudFormation resource specification")
+	}
+	if spec.ResourceSpecificationVersion == "" || len(spec.ResourceTypes) == 0 {
+		return nil, errors.New("invalid CloudFormation resource specification: ResourceSpecificationVersion and ResourceTypes must be set")
+	}
+	return &spec, nil
+}
+
+// ValidateTemplate returns an error listing every resource type and property used in template
+// that is not supported by this version of the resource specification
+func (s *ResourceSpecification) ValidateTemplate(template []byte) error {
+	var parsed struct {
+		Resources map[string]struct {
+			Type       string
+			Properties map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(template, &parsed); err != nil {
+		return errors.Wrap(err, "parsing template")
+	}
+
+	var resourceNames []string
+	for name := range parsed.Resources {
+		resourceNames = append(resourceNames, name)
+	}
+	sort.Strings(resourceNames)
+
+	var unsupported []string
+	for _, name := range resourceNames {
+		resource := parsed.Resources[name]
+		path := fmt.Sprintf("Resources.%s", name)
+		resourceType, ok := s.ResourceTypes[resource.Type]
+		if !ok {
+			unsupported = append(unsupported, fmt.Sprintf("%s.Type (%s)", path, resource.Type))
+			continue
+		}
+		unsupported = append(unsupported, s.unsupportedProperties(path+".Properties", resource.Type, resourceType, resource.Properties)...)
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("template uses the following fields not supported by CloudFormation resource specification version %s: %s",
+			s.ResourceSpecificationVersion, strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+func (s *ResourceSpecification) unsupportedProperties(path, resourceTypeName string, specType ResourceSpecificationType, properties map[string]interface{}) []string {
+	var unsupported []string
+	for _, name := range sortedKeys(properties) {
+		propertyPath := fmt.Sprintf("%s.%s", path, name)
+		property, ok := specType.Properties[name]
+		if !ok {
+			unsupported = append(unsupported, propertyPath)
+			continue
+		}
+
+		switch property.Type {
+		case "":
+			// primitive property
+		case "List":
+			items, _ := properties[name].([]interface{})
+			for i, item := range items {
+				unsupported = append(unsupported, s.unsupportedPropertyType(fmt.Sprintf("%s[%d]", propertyPath, i), resourceTypeName, property.ItemType, item)...)
+			}
+		case "Map":
+			items, _ := properties[name].(map[string]interface{})
+			for _, key := range sortedKeys(items) {
+				unsupported = append(unsupported, s.unsupportedPropertyType(fmt.Sprintf("%s.%s", propertyPath, key), resourceTypeName, property.ItemType, items[key])...)
+			}
+		default:
+			unsupported = append(unsupported, s.unsupportedPropertyType(propertyPath, resourceTypeName, property.Type, properties[name])...)
+		}
+	}
+	return unsupported
+}
+
+func (s *ResourceSpecification) unsupportedPropertyType(path, resourceTypeName, propertyTypeName string, value interface{}) []string {
+	if propertyTypeName == "" {
+		// list or map of primitive values
+		return nil
+	}
+	properties, ok := value.(map[string]interface{})
+	if !ok || isIntrinsicFunction(properties) {
+		return nil
+	}
+
+	// property types are scoped to their resource type, except for the shared Tag property type
+	specType, ok := s.PropertyTypes[resourceTypeName+"."+propertyTypeName]
+	if !ok {
+		specType, ok = s.PropertyTypes[propertyTypeName]
+	}
+	if !ok {
+		return []string{path}
+	}
+	return s.unsupportedProperties(path, resourceTypeName, specType, properties)
+}
+
+func isIntrinsicFunction(value map[string]interface{}) bool {
+	if len(value) != 1 {
+		return false
+	}
+	for key := range value {
+		return key == "Ref" || strings.HasPrefix(key, "Fn::")
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
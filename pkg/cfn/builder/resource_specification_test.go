@@ -0,0 +1,116 @@
+package builder_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+)
+
+var _ = Describe("CloudFormation resource specification", func() {
+	const specification = `{
+  "ResourceSpecificationVersion": "42.0.0",
+  "PropertyTypes": {
+    "AWS::EC2::LaunchTemplate.LaunchTemplateData": {
+      "Properties": {
+        "BlockDeviceMappings": {"Type": "List", "ItemType": "BlockDeviceMapping"},
+        "ImageId": {"PrimitiveType": "String"},
+        "TagSpecifications": {"Type": "List", "ItemType": "TagSpecification"}
+      }
+    },
+    "AWS::EC2::LaunchTemplate.BlockDeviceMapping": {
+      "Properties": {
+        "DeviceName": {"PrimitiveType": "String"},
+        "Ebs": {"Type": "Ebs"}
+      }
+    },
+    "AWS::EC2::LaunchTemplate.Ebs": {
+      "Properties": {
+        "SnapshotId": {"PrimitiveType": "String"},
+        "VolumeSize": {"PrimitiveType": "Integer"}
+      }
+    },
+    "AWS::EC2::LaunchTemplate.TagSpecification": {
+      "Properties": {
+        "ResourceType": {"PrimitiveType": "String"},
+        "Tags": {"Type": "List", "ItemType": "Tag"}
+      }
+    },
+    "Tag": {
+      "Properties": {
+        "Key": {"PrimitiveType": "String"},
+        "Value": {"PrimitiveType": "String"}
+      }
+    }
+  },
+  "ResourceTypes": {
+    "AWS::EC2::LaunchTemplate": {
+      "Properties": {
+        "LaunchTemplateData": {"Type": "LaunchTemplateData"},
+        "LaunchTemplateName": {"PrimitiveType": "String"}
+      }
+    }
+  }
+}`
+
+	var spec *builder.ResourceSpecification
+
+	BeforeEach(func() {
+		var err error
+		spec, err = builder.LoadResourceSpecification(strings.NewReader(specification))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("fails to load a document that is not a resource specification", func() {
+		_, err := builder.LoadResourceSpecification(strings.NewReader(`{"Resources": {}}`))
+		Expect(err).To(MatchError(ContainSubstring("invalid CloudFormation resource specification")))
+	})
+
+	It("accepts a template that only uses supported resource types and properties", func() {
+		template := `{
+  "Resources": {
+    "LaunchTemplate": {
+      "Type": "AWS::EC2::LaunchTemplate",
+      "Properties": {
+        "LaunchTemplateName": {"Fn::Sub": "${AWS::StackName}"},
+        "LaunchTemplateData": {
+          "ImageId": {"Ref": "ImageId"},
+          "BlockDeviceMappings": [{"DeviceName": "/dev/xvda", "Ebs": {"VolumeSize": 80}}],
+          "TagSpecifications": [{"ResourceType": "instance", "Tags": [{"Key": "Name", "Value": "ng"}]}]
+        }
+      }
+    }
+  }
+}`
+		Expect(spec.ValidateTemplate([]byte(template))).To(Succeed())
+	})
+
+	It("returns an error listing the unsupported resource types and properties", func() {
+		template := `{
+  "Resources": {
+    "LaunchTemplate": {
+      "Type": "AWS::EC2::LaunchTemplate",
+      "Properties": {
+        "LaunchTemplateData": {
+          "BlockDeviceMappings": [
+            {"DeviceName": "/dev/xvda", "Ebs": {"VolumeSize": 80}},
+            {"DeviceName": "/dev/xvdb", "Ebs": {"SnapshotId": "snap-1234", "VolumeInitializationRate": 200}}
+          ],
+          "MetadataOptions": {"HttpTokens": "required"}
+        }
+      }
+    },
+    "NodeGroup": {
+      "Type": "AWS::AutoScaling::AutoScalingGroup",
+      "Properties": {}
+    }
+  }
+}`
+		Expect(spec.ValidateTemplate([]byte(template))).To(MatchError("template uses the following fields not supported by CloudFormation resource specification version 42.0.0: " +
+			"Resources.LaunchTemplate.Properties.LaunchTemplateData.BlockDeviceMappings[1].Ebs.VolumeInitializationRate, " +
+			"Resources.LaunchTemplate.Properties.LaunchTemplateData.MetadataOptions, " +
+			"Resources.NodeGroup.Type (AWS::AutoScaling::AutoScalingGroup)"))
+	})
+})
@@ -0,0 +1,41 @@
+package builder
+
+import (
+	gfn "github.com/weaveworks/goformation/v4/cloudformation"
+	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ssh/client"
+)
+
+// sshKeyPairResourceName is the name of the resource importing the SSH public key of a nodegroup
+const sshKeyPairResourceName = "SSHKeyPair"
+
+// makeSSHKeyName returns the name of the EC2 key pair of the nodegroup, or nil if it has none. PublicKeyName and
+// the first of PublicKeyNames reference an existing key pair, while a PublicKey is imported as a new key pair resource
+func makeSSHKeyName(ng *api.NodeGroupBase, clusterName string, newResource func(name string, resource gfn.Resource) *gfnt.Value) (*gfnt.Value, error) {
+	if ng.SSH == nil {
+		return nil, nil
+	}
+	if api.IsEnabled(ng.SSH.Allow) && api.IsSetAndNonEmptyString(ng.SSH.PublicKey) {
+		keyPair, err := client.NewKeyPair(*ng.SSH.PublicKey, clusterName, ng.Name)
+		if err != nil {
+			return nil, err
+		}
+		// the Ref of a key pair returns its name
+		return newResource(sshKeyPairResourceName, &awsCloudFormationResource{
+			Type: "AWS::EC2::KeyPair",
+			Properties: map[string]interface{}{
+				"KeyName":           keyPair.Name,
+				"PublicKeyMaterial": keyPair.PublicKey,
+			},
+		}), nil
+	}
+	if api.IsSetAndNonEmptyString(ng.SSH.PublicKeyName) {
+		return gfnt.NewString(*ng.SSH.PublicKeyName), nil
+	}
+	if len(ng.SSH.PublicKeyNames) > 0 {
+		return gfnt.NewString(ng.SSH.PublicKeyNames[0]), nil
+	}
+	return nil, nil
+}
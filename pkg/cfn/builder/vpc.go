@@ -3,12 +3,14 @@ package builder
 import (
 	"context"
 	"math"
+	"sort"
 	"strings"
 
 	gfncfn "github.com/weaveworks/goformation/v4/cloudformation/cloudformation"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
 )
 
@@ -58,6 +60,39 @@ func formatAZ(az string) string {
 	return strings.ToUpper(strings.ReplaceAll(az, "-", ""))
 }
 
+// makeSubnetTags returns the tags of a subnet of topology: the role tag that load balancers discover the subnets of the
+// topology by, unless the subnet is in a Local Zone or a Wavelength Zone, and the subnet tags of the VPC
+func makeSubnetTags(clusterVPC *api.ClusterVPC, topology api.SubnetTopology, localZone bool) []gfncfn.Tag {
+	tags := map[string]string{}
+	if !localZone {
+		elbTagKey := "kubernetes.io/role/elb"
+		if topology == api.SubnetTopologyPrivate {
+			elbTagKey = "kubernetes.io/role/internal-elb"
+		}
+		tags[elbTagKey] = "1"
+	}
+	for key, value := range clusterVPC.TagsOfSubnets(topology) {
+		tags[key] = value
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	subnetTags := make([]gfncfn.Tag, 0, len(keys))
+	for _, key := range keys {
+		subnetTags = append(subnetTags, gfncfn.Tag{
+			Key:   gfnt.NewString(key),
+			Value: gfnt.NewString(tags[key]),
+		})
+	}
+	return subnetTags
+}
+
 func getSubnetIPv6CIDRBlock(cidrPartitions int) *gfnt.Value {
 	// get 8 of /64 subnets from the auto-allocated IPv6 block,
 	// and pick one block based on subnetIndexForIPv6 counter;
@@ -7,7 +7,6 @@ import (
 
 	"github.com/weaveworks/eksctl/pkg/awsapi"
 
-	gfncfn "github.com/weaveworks/goformation/v4/cloudformation/cloudformation"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 
@@ -178,7 +177,7 @@ func (v *IPv4VPCResourceSet) addSubnets(refRT *gfnt.Value, topology api.SubnetTo
 		// this is same kind of indexing we have in vpc.SetSubnets
 		switch topology {
 		case api.SubnetTopologyPrivate:
-			subnetIndexForIPv6 = len(v.clusterConfig.AvailabilityZones)
+			subnetIndexForIPv6 = len(v.clusterConfig.AllZones())
 		case api.SubnetTopologyPublic:
 			subnetIndexForIPv6 = 0
 		}
@@ -195,19 +194,15 @@ func (v *IPv4VPCResourceSet) addSubnets(refRT *gfnt.Value, topology api.SubnetTo
 			VpcId:            v.vpcID,
 		}
 
+		// load balancers are not placed in the subnets of Local Zones and Wavelength Zones, which most
+		// load balancer types do not support
+		localZone := v.clusterConfig.IsLocalZone(az)
+		subnet.Tags = makeSubnetTags(v.clusterConfig.VPC, topology, localZone)
 		switch topology {
 		case api.SubnetTopologyPrivate:
 			// Choose the appropriate route table for private subnets
 			refRT = gfnt.MakeRef("PrivateRouteTable" + nameAlias)
-			subnet.Tags = []gfncfn.Tag{{
-				Key:   gfnt.NewString("kubernetes.io/role/internal-elb"),
-				Value: gfnt.NewString("1"),
-			}}
 		case api.SubnetTopologyPublic:
-			subnet.Tags = []gfncfn.Tag{{
-				Key:   gfnt.NewString("kubernetes.io/role/elb"),
-				Value: gfnt.NewString("1"),
-			}}
 			subnet.MapPublicIpOnLaunch = gfnt.True()
 		}
 		subnetAlias := string(topology) + nameAlias
@@ -218,7 +213,7 @@ func (v *IPv4VPCResourceSet) addSubnets(refRT *gfnt.Value, topology api.SubnetTo
 		})
 
 		if api.IsEnabled(v.clusterConfig.VPC.AutoAllocateIPv6) {
-			refSubnetSlices := getSubnetIPv6CIDRBlock((len(v.clusterConfig.AvailabilityZones) * 2) + 2)
+			refSubnetSlices := getSubnetIPv6CIDRBlock((len(v.clusterConfig.AllZones()) * 2) + 2)
 			v.rs.newResource(subnetAlias+"CIDRv6", &gfnec2.SubnetCidrBlock{
 				SubnetId:      refSubnet,
 				Ipv6CidrBlock: gfnt.MakeFnSelect(gfnt.NewInteger(subnetIndexForIPv6), refSubnetSlices),
@@ -259,8 +254,9 @@ var (
 	sgMinNodePort = gfnt.NewInteger(1025)
 	sgMaxNodePort = gfnt.NewInteger(65535)
 
-	sgPortHTTPS = gfnt.NewInteger(443)
-	sgPortSSH   = gfnt.NewInteger(22)
+	sgPortHTTPS   = gfnt.NewInteger(443)
+	sgPortSSH     = gfnt.NewInteger(22)
+	sgPortKubelet = gfnt.NewInteger(10250)
 )
 
 type clusterSecurityGroup struct {
@@ -298,6 +294,13 @@ func (v *IPv4VPCResourceSet) haNAT() {
 			RouteTableId: refRT,
 		})
 	}
+
+	// NAT gateways are not supported in Local Zones and Wavelength Zones, so their private subnets use the NAT
+	// gateway of the first availability zone
+	if len(v.clusterConfig.LocalZones) > 0 {
+		refNG := gfnt.MakeRef("NATGateway" + formatAZ(v.clusterConfig.AvailabilityZones[0]))
+		v.addPrivateRouteTables(v.clusterConfig.LocalZones, refNG)
+	}
 }
 
 func (v *IPv4VPCResourceSet) singleNAT() {
@@ -312,7 +315,13 @@ func (v *IPv4VPCResourceSet) singleNAT() {
 		SubnetId:     gfnt.MakeRef("SubnetPublic" + firstUpperAZ),
 	})
 
-	for _, az := range v.clusterConfig.AvailabilityZones {
+	v.addPrivateRouteTables(v.clusterConfig.AllZones(), refNG)
+}
+
+// addPrivateRouteTables adds a route table sending Internet traffic through the NAT gateway refNG to the private
+// subnet of each zone
+func (v *IPv4VPCResourceSet) addPrivateRouteTables(zones []string, refNG *gfnt.Value) {
+	for _, az := range zones {
 		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
 
 		refRT := v.rs.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
@@ -332,7 +341,7 @@ func (v *IPv4VPCResourceSet) singleNAT() {
 }
 
 func (v *IPv4VPCResourceSet) noNAT() {
-	for _, az := range v.clusterConfig.AvailabilityZones {
+	for _, az := range v.clusterConfig.AllZones() {
 		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
 
 		refRT := v.rs.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfnec2.RouteTable{
@@ -16,6 +16,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder/fakes"
 	"github.com/weaveworks/eksctl/pkg/eks/mocksv2"
+	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
 )
 
 var _ = Describe("VPC Template Builder", func() {
@@ -258,6 +259,76 @@ var _ = Describe("VPC Template Builder", func() {
 			})
 		})
 
+		Context("a Local Zone is set", func() {
+			const localZone = "us-west-2-lax-1a"
+
+			BeforeEach(func() {
+				*cfg.VPC.NAT.Gateway = api.ClusterHighlyAvailableNAT
+				cfg.LocalZones = []string{localZone}
+				cfg.VPC.Subnets.Public.SetAZ(localZone, api.Network{CIDR: ipnet.MustParseCIDR("192.168.64.0/19")})
+				cfg.VPC.Subnets.Private.SetAZ(localZone, api.Network{CIDR: ipnet.MustParseCIDR("192.168.160.0/19")})
+			})
+
+			It("adds the subnets of the Local Zone without load balancer role tags", func() {
+				Expect(vpcTemplate.Resources).To(HaveKey("SubnetPublicUSWEST2LAX1A"))
+				properties := vpcTemplate.Resources["SubnetPublicUSWEST2LAX1A"].Properties
+				Expect(properties.AvailabilityZone).To(Equal(localZone))
+				Expect(properties.Tags).To(HaveLen(1))
+				Expect(properties.Tags[0].Key).To(Equal("Name"))
+				Expect(properties.MapPublicIPOnLaunch).To(BeTrue())
+
+				Expect(vpcTemplate.Resources).To(HaveKey("SubnetPrivateUSWEST2LAX1A"))
+				properties = vpcTemplate.Resources["SubnetPrivateUSWEST2LAX1A"].Properties
+				Expect(properties.AvailabilityZone).To(Equal(localZone))
+				Expect(properties.Tags).To(HaveLen(1))
+				Expect(properties.Tags[0].Key).To(Equal("Name"))
+
+				Expect(vpcTemplate.Resources[publicSubnetRef1].Properties.Tags[0].Key).To(Equal("kubernetes.io/role/elb"))
+				Expect(vpcTemplate.Resources[privateSubnetRef1].Properties.Tags[0].Key).To(Equal("kubernetes.io/role/internal-elb"))
+			})
+
+			It("routes the private subnet of the Local Zone through the NAT gateway of the first availability zone", func() {
+				Expect(vpcTemplate.Resources).NotTo(HaveKey("NATGatewayUSWEST2LAX1A"))
+				Expect(vpcTemplate.Resources).To(HaveKey("PrivateRouteTableUSWEST2LAX1A"))
+				Expect(vpcTemplate.Resources).To(HaveKey("NATPrivateSubnetRouteUSWEST2LAX1A"))
+				Expect(vpcTemplate.Resources["NATPrivateSubnetRouteUSWEST2LAX1A"].Properties.RouteTableID).To(Equal(makeRef("PrivateRouteTableUSWEST2LAX1A")))
+				Expect(vpcTemplate.Resources["NATPrivateSubnetRouteUSWEST2LAX1A"].Properties.NatGatewayID).To(Equal(makeRef("NATGatewayUSWEST2A")))
+				Expect(vpcTemplate.Resources).To(HaveKey("RouteTableAssociationPrivateUSWEST2LAX1A"))
+				Expect(vpcTemplate.Resources["RouteTableAssociationPrivateUSWEST2LAX1A"].Properties.SubnetID).To(Equal(makeRef("SubnetPrivateUSWEST2LAX1A")))
+			})
+		})
+
+		Context("subnet tags are set", func() {
+			BeforeEach(func() {
+				cfg.VPC.SubnetTags = map[string]string{
+					"team":                   "platform",
+					"karpenter.sh/discovery": "cluster-1",
+				}
+				cfg.VPC.PublicSubnetTags = map[string]string{
+					"team": "web",
+				}
+			})
+
+			It("adds the tags to the subnets sorted by key, the tags of the tier taking precedence", func() {
+				tags := vpcTemplate.Resources[publicSubnetRef1].Properties.Tags
+				Expect(tags).To(HaveLen(4))
+				Expect(tags[0].Key).To(Equal("karpenter.sh/discovery"))
+				Expect(tags[0].Value).To(Equal("cluster-1"))
+				Expect(tags[1].Key).To(Equal("kubernetes.io/role/elb"))
+				Expect(tags[2].Key).To(Equal("team"))
+				Expect(tags[2].Value).To(Equal("web"))
+				Expect(tags[3].Key).To(Equal("Name"))
+
+				tags = vpcTemplate.Resources[privateSubnetRef1].Properties.Tags
+				Expect(tags).To(HaveLen(4))
+				Expect(tags[0].Key).To(Equal("karpenter.sh/discovery"))
+				Expect(tags[1].Key).To(Equal("kubernetes.io/role/internal-elb"))
+				Expect(tags[2].Key).To(Equal("team"))
+				Expect(tags[2].Value).To(Equal("platform"))
+				Expect(tags[3].Key).To(Equal("Name"))
+			})
+		})
+
 		Context("an invalid nat option is set", func() {
 			BeforeEach(func() {
 				*cfg.VPC.NAT.Gateway = "some-trash"
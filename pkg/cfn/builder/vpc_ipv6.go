@@ -6,7 +6,6 @@ import (
 
 	"github.com/weaveworks/eksctl/pkg/awsapi"
 
-	"github.com/weaveworks/goformation/v4/cloudformation/cloudformation"
 	gfnec2 "github.com/weaveworks/goformation/v4/cloudformation/ec2"
 	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
 
@@ -188,13 +187,13 @@ func (v *IPv6VPCResourceSet) createSubnet(az, azFormatted string, i, cidrPartiti
 	var assignIpv6AddressOnCreation *gfnt.Value
 	subnetKey := PublicSubnetKey + azFormatted
 	mapPublicIPOnLaunch := gfnt.True()
-	elbTagKey := "kubernetes.io/role/elb"
+	topology := api.SubnetTopologyPublic
 
 	if private {
 		subnetKey = PrivateSubnetKey + azFormatted
 		mapPublicIPOnLaunch = nil
 		assignIpv6AddressOnCreation = gfnt.True()
-		elbTagKey = "kubernetes.io/role/internal-elb"
+		topology = api.SubnetTopologyPrivate
 	}
 
 	return v.rs.newResource(subnetKey, &gfnec2.Subnet{
@@ -205,9 +204,6 @@ func (v *IPv6VPCResourceSet) createSubnet(az, azFormatted string, i, cidrPartiti
 		MapPublicIpOnLaunch:         mapPublicIPOnLaunch,
 		AssignIpv6AddressOnCreation: assignIpv6AddressOnCreation,
 		VpcId:                       gfnt.MakeRef(VPCResourceKey),
-		Tags: []cloudformation.Tag{{
-			Key:   gfnt.NewString(elbTagKey),
-			Value: gfnt.NewString("1"),
-		}},
+		Tags:                        makeSubnetTags(v.clusterConfig.VPC, topology, false),
 	})
 }
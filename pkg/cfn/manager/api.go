@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
@@ -23,11 +24,12 @@ import (
 )
 
 const (
-	resourcesRootPath = "Resources"
-	outputsRootPath   = "Outputs"
-	mappingsRootPath  = "Mappings"
-	ourStackRegexFmt  = "^(eksctl|EKS)-%s-((cluster|nodegroup-.+|addon-.+|fargate|karpenter)|(VPC|ServiceRole|ControlPlane|DefaultNodeGroup))$"
-	clusterStackRegex = "eksctl-.*-cluster"
+	resourcesRootPath  = "Resources"
+	outputsRootPath    = "Outputs"
+	mappingsRootPath   = "Mappings"
+	parametersRootPath = "Parameters"
+	ourStackRegexFmt   = "^(eksctl|EKS)-%s-((cluster|nodegroup-.+|addon-.+|fargate|karpenter)|(VPC|ServiceRole|ControlPlane|DefaultNodeGroup))$"
+	clusterStackRegex  = "eksctl-.*-cluster"
 )
 
 var (
@@ -59,6 +61,11 @@ type TemplateURL string
 
 func (u TemplateURL) isTemplateData() {}
 
+// PreviousTemplate reuses the template the stack was last deployed with.
+type PreviousTemplate struct{}
+
+func (PreviousTemplate) isTemplateData() {}
+
 // ChangeSet represents a CloudFormation ChangeSet
 type ChangeSet = cloudformation.DescribeChangeSetOutput
 
@@ -77,6 +84,8 @@ type StackCollection struct {
 	region          string
 	waitTimeout     time.Duration
 	sharedTags      []*cloudformation.Tag
+
+	clusterConfigHash string
 }
 
 func newTag(key, value string) *cloudformation.Tag {
@@ -111,9 +120,13 @@ func NewStackCollection(provider api.ClusterProvider, spec *api.ClusterConfig) S
 
 // DoCreateStackRequest requests the creation of a CloudFormation stack
 func (c *StackCollection) DoCreateStackRequest(i *Stack, templateData TemplateData, tags, parameters map[string]string, withIAM bool, withNamedIAM bool) error {
+	return c.doCreateStackRequest(i, templateData, tags, parameters, withIAM, withNamedIAM, c.disableRollback)
+}
+
+func (c *StackCollection) doCreateStackRequest(i *Stack, templateData TemplateData, tags, parameters map[string]string, withIAM, withNamedIAM, disableRollback bool) error {
 	input := &cloudformation.CreateStackInput{
 		StackName:       i.StackName,
-		DisableRollback: aws.Bool(c.disableRollback),
+		DisableRollback: aws.Bool(disableRollback),
 	}
 	input.Tags = append(input.Tags, c.sharedTags...)
 	for k, v := range tags {
@@ -163,7 +176,7 @@ func (c *StackCollection) DoCreateStackRequest(i *Stack, templateData TemplateDa
 // assume completion, do not expect more then one error value on the
 // channel, it's closed immediately after it is written to
 func (c *StackCollection) CreateStack(stackName string, resourceSet builder.ResourceSetReader, tags, parameters map[string]string, errs chan error) error {
-	stack, err := c.createStackRequest(stackName, resourceSet, tags, parameters)
+	stack, err := c.createStackRequest(stackName, resourceSet, tags, parameters, c.disableRollback)
 	if err != nil {
 		return err
 	}
@@ -172,10 +185,31 @@ func (c *StackCollection) CreateStack(stackName string, resourceSet builder.Reso
 	return nil
 }
 
+// createNodeGroupStack creates the stack of a nodegroup with tags like CreateStack, without rolling it back
+// on failure if either the nodegroup or the provider has disabled rollback
+func (c *StackCollection) createNodeGroupStack(stackName string, resourceSet builder.ResourceSetReader, ng *api.NodeGroupBase, tags map[string]string, errs chan error) error {
+	stack, err := c.createStackRequest(stackName, resourceSet, tags, nil, c.nodeGroupDisableRollback(ng))
+	if err != nil {
+		return err
+	}
+
+	go c.waitUntilStackIsCreated(stack, resourceSet, errs)
+	return nil
+}
+
+func (c *StackCollection) nodeGroupDisableRollback(ng *api.NodeGroupBase) bool {
+	return c.disableRollback || api.IsEnabled(ng.DisableRollback)
+}
+
 // createClusterStack creates the cluster stack
 func (c *StackCollection) createClusterStack(stackName string, resourceSet builder.ResourceSetReader, errCh chan error) error {
-	// Unlike with `createNodeGroupTask`, all tags are already set for the cluster stack
-	stack, err := c.createStackRequest(stackName, resourceSet, nil, nil)
+	// Unlike with `createNodeGroupTask`, all tags are already set for the cluster stack, apart from the hash of
+	// the config it is created from
+	var tags map[string]string
+	if c.clusterConfigHash != "" {
+		tags = map[string]string{api.ClusterConfigHashTag: c.clusterConfigHash}
+	}
+	stack, err := c.createStackRequest(stackName, resourceSet, tags, nil, c.disableRollback)
 	if err != nil {
 		return err
 	}
@@ -221,14 +255,14 @@ func (c *StackCollection) createClusterStack(stackName string, resourceSet build
 	return nil
 }
 
-func (c *StackCollection) createStackRequest(stackName string, resourceSet builder.ResourceSetReader, tags, parameters map[string]string) (*Stack, error) {
+func (c *StackCollection) createStackRequest(stackName string, resourceSet builder.ResourceSetReader, tags, parameters map[string]string, disableRollback bool) (*Stack, error) {
 	stack := &Stack{StackName: &stackName}
 	templateBody, err := resourceSet.RenderJSON()
 	if err != nil {
 		return nil, errors.Wrapf(err, "rendering template for %q stack", *stack.StackName)
 	}
 
-	if err := c.DoCreateStackRequest(stack, TemplateBody(templateBody), tags, parameters, resourceSet.WithIAM(), resourceSet.WithNamedIAM()); err != nil {
+	if err := c.doCreateStackRequest(stack, TemplateBody(templateBody), tags, parameters, resourceSet.WithIAM(), resourceSet.WithNamedIAM(), disableRollback); err != nil {
 		return nil, err
 	}
 
@@ -256,6 +290,7 @@ func (c *StackCollection) UpdateStack(options UpdateStackOptions) error {
 		options.Description,
 		options.TemplateData,
 		options.Parameters,
+		previousParameterKeys(options.Stack, options.TemplateData, options.Parameters),
 		options.Stack.Capabilities,
 		options.Stack.Tags,
 	); err != nil {
@@ -320,18 +355,51 @@ func (c *StackCollection) GetManagedNodeGroupTemplate(options GetNodegroupOption
 	return templateBody, nil
 }
 
-// UpdateNodeGroupStack updates the nodegroup stack with the specified template
-func (c *StackCollection) UpdateNodeGroupStack(nodeGroupName, template string, wait bool) error {
-	stackName := c.makeNodeGroupStackName(nodeGroupName)
+// UpdateNodeGroupStack updates the nodegroup stack with the specified template and parameters
+func (c *StackCollection) UpdateNodeGroupStack(ctx context.Context, options UpdateNodeGroupStackOptions) error {
+	if options.Stack == nil {
+		stack, err := c.DescribeNodeGroupStack(options.NodeGroupName)
+		if err != nil {
+			return err
+		}
+		options.Stack = stack
+	}
+	if options.PreserveDesiredCapacity {
+		if err := c.preserveDesiredCapacity(ctx, &options); err != nil {
+			return err
+		}
+	}
 	return c.UpdateStack(UpdateStackOptions{
-		StackName:     stackName,
+		Stack:         options.Stack,
 		ChangeSetName: c.MakeChangeSetName("update-nodegroup"),
 		Description:   "updating nodegroup stack",
-		TemplateData:  TemplateBody(template),
-		Wait:          wait,
+		TemplateData:  options.TemplateData,
+		Parameters:    options.Parameters,
+		Wait:          options.Wait,
 	})
 }
 
+// previousParameterKeys returns the keys of the parameters of stack that are not set in parameters and are
+// still declared by templateData, so that they keep their previous values rather than reverting to their defaults
+func previousParameterKeys(stack *Stack, templateData TemplateData, parameters map[string]string) []string {
+	var keys []string
+	for _, p := range stack.Parameters {
+		key := aws.StringValue(p.ParameterKey)
+		if _, ok := parameters[key]; ok {
+			continue
+		}
+		switch data := templateData.(type) {
+		case PreviousTemplate:
+			keys = append(keys, key)
+		case TemplateBody:
+			if gjson.GetBytes(data, parametersRootPath+"."+key).Exists() {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
 // ListStacksMatching gets all of CloudFormation stacks with names matching nameRegex.
 func (c *StackCollection) ListStacksMatching(nameRegex string, statusFilters ...string) ([]*Stack, error) {
 	var (
@@ -482,6 +550,11 @@ func (c *StackCollection) DeleteStackBySpec(s *Stack) (*Stack, error) {
 			fmt.Sprintf("%s:%s", api.ClusterNameTag, c.spec.Metadata.Name))
 	}
 
+	if aws.StringValue(s.StackStatus) == cloudformation.StackStatusDeleteInProgress {
+		logger.Info("stack %q is already being deleted", *s.StackName)
+		return s, nil
+	}
+
 	input := &cloudformation.DeleteStackInput{
 		StackName: s.StackId,
 	}
@@ -631,7 +704,7 @@ func (c *StackCollection) LookupCloudTrailEvents(ctx context.Context, i *Stack)
 }
 
 func (c *StackCollection) doCreateChangeSetRequest(stackName, changeSetName, description string, templateData TemplateData,
-	parameters map[string]string, capabilities []*string, tags []*cloudformation.Tag) error {
+	parameters map[string]string, previousParameterKeys []string, capabilities []*string, tags []*cloudformation.Tag) error {
 	input := &cloudformation.CreateChangeSetInput{
 		StackName:     &stackName,
 		ChangeSetName: &changeSetName,
@@ -646,6 +719,8 @@ func (c *StackCollection) doCreateChangeSetRequest(stackName, changeSetName, des
 		input.SetTemplateBody(string(data))
 	case TemplateURL:
 		input.SetTemplateURL(string(data))
+	case PreviousTemplate:
+		input.SetUsePreviousTemplate(true)
 	default:
 		return fmt.Errorf("unknown template data type: %T", templateData)
 	}
@@ -662,6 +737,12 @@ func (c *StackCollection) doCreateChangeSetRequest(stackName, changeSetName, des
 		}
 		input.Parameters = append(input.Parameters, p)
 	}
+	for _, k := range previousParameterKeys {
+		input.Parameters = append(input.Parameters, &cloudformation.Parameter{
+			ParameterKey:     aws.String(k),
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
 
 	logger.Debug("creating changeSet, input = %#v", input)
 	s, err := c.cloudformationAPI.CreateChangeSet(input)
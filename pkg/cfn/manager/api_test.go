@@ -1,13 +1,18 @@
 package manager
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/awstesting"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -237,6 +242,209 @@ var _ = Describe("StackCollection", func() {
 		})
 	})
 
+	Context("UpdateNodeGroupStack", func() {
+		var (
+			p         *mockprovider.MockProvider
+			stackName string
+			cfg       *api.ClusterConfig
+			stack     *cfn.Stack
+		)
+
+		createChangeSetInput := func() *cfn.CreateChangeSetInput {
+			for _, call := range p.MockCloudFormation().Calls {
+				if call.Method == "CreateChangeSet" {
+					return call.Arguments.Get(0).(*cfn.CreateChangeSetInput)
+				}
+			}
+			return nil
+		}
+
+		mockAutoScalingGroup := func(minSize, maxSize, desiredCapacity int32) {
+			p.MockCloudFormation().On("DescribeStackResource", &cfn.DescribeStackResourceInput{
+				StackName:         &stackName,
+				LogicalResourceId: aws.String("NodeGroup"),
+			}).Return(&cfn.DescribeStackResourceOutput{
+				StackResourceDetail: &cfn.StackResourceDetail{PhysicalResourceId: aws.String("asg")},
+			}, nil)
+			p.MockASG().On("DescribeAutoScalingGroups", mock.Anything, &autoscaling.DescribeAutoScalingGroupsInput{
+				AutoScalingGroupNames: []string{"asg"},
+			}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+				AutoScalingGroups: []autoscalingtypes.AutoScalingGroup{{
+					MinSize:         awsv2.Int32(minSize),
+					MaxSize:         awsv2.Int32(maxSize),
+					DesiredCapacity: awsv2.Int32(desiredCapacity),
+				}},
+			}, nil)
+		}
+
+		BeforeEach(func() {
+			stackName = "eksctl-cluster-nodegroup-ng"
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Name = "cluster"
+			stack = &cfn.Stack{
+				StackName:   &stackName,
+				StackStatus: aws.String(cfn.StackStatusCreateComplete),
+				Tags: []*cfn.Tag{
+					{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng")},
+				},
+				Parameters: []*cfn.Parameter{
+					{ParameterKey: aws.String("MinSize"), ParameterValue: aws.String("1")},
+					{ParameterKey: aws.String("MaxSize"), ParameterValue: aws.String("3")},
+					{ParameterKey: aws.String("DesiredCapacity"), ParameterValue: aws.String("2")},
+				},
+			}
+			describeOutput := &cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}
+			describeChangeSetCreateCompleteOutput := &cfn.DescribeChangeSetOutput{
+				StackName: &stackName,
+				Status:    aws.String(cfn.ChangeSetStatusCreateComplete),
+			}
+			describeStacksUpdateCompleteOutput := &cfn.DescribeStacksOutput{
+				Stacks: []*cfn.Stack{
+					{
+						StackName:   &stackName,
+						StackStatus: aws.String(cfn.StackStatusUpdateComplete),
+					},
+				},
+			}
+
+			p = mockprovider.NewMockProvider()
+			p.MockCloudFormation().On("DescribeStacks", &cfn.DescribeStacksInput{StackName: &stackName}).Return(describeOutput, nil)
+			p.MockCloudFormation().On("CreateChangeSet", mock.Anything).Return(nil, nil)
+			req := awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, describeChangeSetCreateCompleteOutput)
+			p.MockCloudFormation().On("DescribeChangeSetRequest", mock.Anything).Return(req, describeChangeSetCreateCompleteOutput)
+			p.MockCloudFormation().On("DescribeChangeSet", mock.Anything).Return(describeChangeSetCreateCompleteOutput, nil)
+			p.MockCloudFormation().On("ExecuteChangeSet", mock.Anything).Return(nil, nil)
+			req = awstesting.NewClient(nil).NewRequest(&request.Operation{Name: "Operation"}, nil, describeStacksUpdateCompleteOutput)
+			p.MockCloudFormation().On("DescribeStacksRequest", mock.Anything).Return(req, describeStacksUpdateCompleteOutput)
+		})
+
+		It("reuses the previous template and the values of the parameters that are not updated", func() {
+			sm := NewStackCollection(p, cfg)
+			err := sm.UpdateNodeGroupStack(context.Background(), UpdateNodeGroupStackOptions{
+				NodeGroupName:           "ng",
+				TemplateData:            PreviousTemplate{},
+				Parameters:              map[string]string{"DesiredCapacity": "3"},
+				Wait:                    true,
+				PreserveDesiredCapacity: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			input := createChangeSetInput()
+			Expect(input.UsePreviousTemplate).To(Equal(aws.Bool(true)))
+			Expect(input.TemplateBody).To(BeNil())
+			Expect(input.TemplateURL).To(BeNil())
+			Expect(input.Parameters).To(ConsistOf(
+				&cfn.Parameter{ParameterKey: aws.String("DesiredCapacity"), ParameterValue: aws.String("3")},
+				&cfn.Parameter{ParameterKey: aws.String("MinSize"), UsePreviousValue: aws.Bool(true)},
+				&cfn.Parameter{ParameterKey: aws.String("MaxSize"), UsePreviousValue: aws.Bool(true)},
+			))
+			By("not looking up the current desired capacity when a new one is set")
+			p.MockASG().AssertNotCalled(GinkgoT(), "DescribeAutoScalingGroups", mock.Anything, mock.Anything)
+		})
+
+		It("keeps the current desired capacity of an autoscaled nodegroup in the parameters", func() {
+			mockAutoScalingGroup(1, 10, 5)
+
+			sm := NewStackCollection(p, cfg)
+			err := sm.UpdateNodeGroupStack(context.Background(), UpdateNodeGroupStackOptions{
+				Stack:                   stack,
+				TemplateData:            PreviousTemplate{},
+				Parameters:              map[string]string{"MaxSize": "10"},
+				Wait:                    true,
+				PreserveDesiredCapacity: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createChangeSetInput().Parameters).To(ConsistOf(
+				&cfn.Parameter{ParameterKey: aws.String("MaxSize"), ParameterValue: aws.String("10")},
+				&cfn.Parameter{ParameterKey: aws.String("DesiredCapacity"), ParameterValue: aws.String("5")},
+				&cfn.Parameter{ParameterKey: aws.String("MinSize"), UsePreviousValue: aws.Bool(true)},
+			))
+		})
+
+		It("keeps the current desired capacity of an autoscaled nodegroup in the template", func() {
+			mockAutoScalingGroup(1, 10, 5)
+
+			sm := NewStackCollection(p, cfg)
+			err := sm.UpdateNodeGroupStack(context.Background(), UpdateNodeGroupStackOptions{
+				Stack:                   stack,
+				TemplateData:            TemplateBody(`{"Resources":{"NodeGroup":{"Properties":{"DesiredCapacity":"2","MinSize":"1","MaxSize":"10"}}}}`),
+				Wait:                    true,
+				PreserveDesiredCapacity: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*createChangeSetInput().TemplateBody).To(Equal(`{"Resources":{"NodeGroup":{"Properties":{"DesiredCapacity":5,"MinSize":"1","MaxSize":"10"}}}}`))
+		})
+
+		It("keeps the current desired size of an autoscaled managed nodegroup", func() {
+			stack.Tags = append(stack.Tags, &cfn.Tag{Key: aws.String(api.NodeGroupTypeTag), Value: aws.String(string(api.NodeGroupTypeManaged))})
+			p.MockEKS().On("DescribeNodegroup", &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String("cluster"),
+				NodegroupName: aws.String("ng"),
+			}).Return(&eks.DescribeNodegroupOutput{
+				Nodegroup: &eks.Nodegroup{
+					ScalingConfig: &eks.NodegroupScalingConfig{
+						MinSize:     aws.Int64(1),
+						MaxSize:     aws.Int64(4),
+						DesiredSize: aws.Int64(3),
+					},
+				},
+			}, nil)
+
+			sm := NewStackCollection(p, cfg)
+			err := sm.UpdateNodeGroupStack(context.Background(), UpdateNodeGroupStackOptions{
+				Stack:                   stack,
+				TemplateData:            TemplateBody(`{"Resources":{"ManagedNodeGroup":{"Properties":{"Labels":{"team":"a"},"ScalingConfig":{"DesiredSize":2,"MaxSize":4,"MinSize":1}}}}}`),
+				Wait:                    true,
+				PreserveDesiredCapacity: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*createChangeSetInput().TemplateBody).To(Equal(`{"Resources":{"ManagedNodeGroup":{"Properties":{"Labels":{"team":"a"},"ScalingConfig":{"DesiredSize":3,"MaxSize":4,"MinSize":1}}}}}`))
+		})
+
+		DescribeTable("does not change the desired capacity", func(preserveDesiredCapacity bool) {
+			mockAutoScalingGroup(3, 3, 3)
+
+			sm := NewStackCollection(p, cfg)
+			err := sm.UpdateNodeGroupStack(context.Background(), UpdateNodeGroupStackOptions{
+				Stack:                   stack,
+				TemplateData:            PreviousTemplate{},
+				Parameters:              map[string]string{"MaxSize": "3"},
+				Wait:                    true,
+				PreserveDesiredCapacity: preserveDesiredCapacity,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createChangeSetInput().Parameters).To(ConsistOf(
+				&cfn.Parameter{ParameterKey: aws.String("MaxSize"), ParameterValue: aws.String("3")},
+				&cfn.Parameter{ParameterKey: aws.String("DesiredCapacity"), UsePreviousValue: aws.Bool(true)},
+				&cfn.Parameter{ParameterKey: aws.String("MinSize"), UsePreviousValue: aws.Bool(true)},
+			))
+		},
+			Entry("of a nodegroup of a fixed size", true),
+			Entry("when preserving it is disabled", false),
+		)
+
+		It("only reuses the values of the parameters declared by a new template", func() {
+			sm := NewStackCollection(p, api.NewClusterConfig())
+			err := sm.UpdateStack(UpdateStackOptions{
+				StackName:     stackName,
+				ChangeSetName: "eksctl-changeset",
+				Description:   "description",
+				TemplateData:  TemplateBody(`{"Parameters": {"MinSize": {"Type": "Number"}, "MaxSize": {"Type": "Number"}}}`),
+				Parameters:    map[string]string{"MaxSize": "5"},
+				Wait:          true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			args := p.MockCloudFormation().Calls[1].Arguments.Get(0)
+			createChangeSetInput := args.(*cfn.CreateChangeSetInput)
+			Expect(createChangeSetInput.UsePreviousTemplate).To(BeNil())
+			Expect(createChangeSetInput.Parameters).To(ConsistOf(
+				&cfn.Parameter{ParameterKey: aws.String("MaxSize"), ParameterValue: aws.String("5")},
+				&cfn.Parameter{ParameterKey: aws.String("MinSize"), UsePreviousValue: aws.Bool(true)},
+			))
+		})
+	})
+
 	Context("HasClusterStackFromList", func() {
 		type clusterInput struct {
 			clusterName   string
@@ -367,4 +575,71 @@ var _ = Describe("StackCollection", func() {
 			})
 		})
 	})
+
+	Context("ListAddonIAMStacksToDelete", func() {
+		var (
+			p   *mockprovider.MockProvider
+			cfg *api.ClusterConfig
+		)
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Name = "my-cluster"
+			p = mockprovider.NewMockProvider()
+
+			stacks := map[string]*cfn.Stack{
+				"eksctl-my-cluster-addon-vpc-cni": {
+					StackName:   aws.String("eksctl-my-cluster-addon-vpc-cni"),
+					StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-addon-vpc-cni/1"),
+					StackStatus: aws.String(cfn.StackStatusCreateComplete),
+					Tags: []*cfn.Tag{
+						{Key: aws.String(api.ClusterNameTag), Value: aws.String("my-cluster")},
+						{Key: aws.String(api.AddonNameTag), Value: aws.String("vpc-cni")},
+					},
+				},
+				"eksctl-my-cluster-nodegroup-ng": {
+					StackName:   aws.String("eksctl-my-cluster-nodegroup-ng"),
+					StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-nodegroup-ng/2"),
+					StackStatus: aws.String(cfn.StackStatusCreateComplete),
+					Tags: []*cfn.Tag{
+						{Key: aws.String(api.ClusterNameTag), Value: aws.String("my-cluster")},
+					},
+				},
+			}
+			p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.AnythingOfType("func(*cloudformation.ListStacksOutput, bool) bool")).Run(func(args mock.Arguments) {
+				fn := args.Get(1) // the passed in function
+				var summaries []*cfn.StackSummary
+				for _, s := range stacks {
+					summaries = append(summaries, &cfn.StackSummary{StackName: s.StackName, StackId: s.StackId})
+				}
+				fn.(func(p *cfn.ListStacksOutput, _ bool) bool)(&cfn.ListStacksOutput{
+					StackSummaries: summaries,
+				}, true)
+			}).Return(nil)
+			for _, s := range stacks {
+				p.MockCloudFormation().On("DescribeStacks", &cfn.DescribeStacksInput{StackName: s.StackId}).Return(&cfn.DescribeStacksOutput{
+					Stacks: []*cfn.Stack{s},
+				}, nil)
+			}
+		})
+
+		It("returns the addon IAM stacks without deleting them", func() {
+			sm := NewStackCollection(p, cfg)
+			addonStacks, err := sm.ListAddonIAMStacksToDelete()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addonStacks).To(HaveLen(1))
+			Expect(addonStacks[0].AddonName).To(Equal("vpc-cni"))
+			Expect(addonStacks[0].StackName).To(Equal("eksctl-my-cluster-addon-vpc-cni"))
+			Expect(addonStacks[0].StackARN).To(Equal("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-addon-vpc-cni/1"))
+			Expect(p.MockCloudFormation().AssertNotCalled(GinkgoT(), "DeleteStack", mock.Anything)).To(BeTrue())
+		})
+
+		It("deletes the same stacks in NewTaskToDeleteAddonIAM", func() {
+			sm := NewStackCollection(p, cfg)
+			taskTree, err := sm.NewTaskToDeleteAddonIAM(false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskTree.Len()).To(Equal(1))
+			Expect(taskTree.Describe()).To(ContainSubstring(`delete addon IAM "eksctl-my-cluster-addon-vpc-cni"`))
+		})
+	})
 })
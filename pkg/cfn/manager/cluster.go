@@ -42,6 +42,23 @@ func (c *StackCollection) createClusterTask(ctx context.Context, errs chan error
 	return c.createClusterStack(name, stack, errs)
 }
 
+// SetClusterConfigHash sets the hash of the config the cluster is created from, which is recorded in a tag of the
+// cluster stack
+func (c *StackCollection) SetClusterConfigHash(hash string) {
+	c.clusterConfigHash = hash
+}
+
+// GetClusterConfigHashTag returns the hash of the config a cluster was created from, as recorded in the tags of its
+// stack, or an empty string if it was not recorded
+func GetClusterConfigHashTag(tags []*cfn.Tag) string {
+	for _, tag := range tags {
+		if *tag.Key == api.ClusterConfigHashTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
 // DescribeClusterStack calls DescribeStacks and filters out cluster stack
 func (c *StackCollection) DescribeClusterStack() (*Stack, error) {
 	stacks, err := c.DescribeStacks()
@@ -101,7 +118,7 @@ func (c *StackCollection) RefreshFargatePodExecutionRoleARN() error {
 
 // AppendNewClusterStackResource will update cluster
 // stack with new resources in append-only way
-func (c *StackCollection) AppendNewClusterStackResource(ctx context.Context, plan bool) (bool, error) {
+func (c *StackCollection) AppendNewClusterStackResource(templates *ClusterStackTemplates, plan bool) (bool, error) {
 	name := c.MakeClusterStackName()
 
 	// NOTE: currently we can only append new resources to the stack,
@@ -110,43 +127,19 @@ func (c *StackCollection) AppendNewClusterStackResource(ctx context.Context, pla
 	//   is managed as part of the stack;
 	// - CloudFormation cannot yet upgrade EKS control plane itself;
 
-	currentTemplate, err := c.GetStackTemplate(name)
-	if err != nil {
-		return false, errors.Wrapf(err, "error getting stack template %s", name)
-	}
+	currentTemplate, newTemplate := templates.Current, templates.New
 
 	currentResources := gjson.Get(currentTemplate, resourcesRootPath)
 	currentOutputs := gjson.Get(currentTemplate, outputsRootPath)
 	currentMappings := gjson.Get(currentTemplate, mappingsRootPath)
-	if !currentResources.IsObject() || !currentOutputs.IsObject() {
-		return false, fmt.Errorf("unexpected template format of the current stack ")
-	}
 
-	if err := c.importServiceRoleARN(currentResources); err != nil {
-		return false, err
-	}
-
-	logger.Info("re-building cluster stack %q", name)
-	newStack := builder.NewClusterResourceSet(c.ec2API, c.region, c.spec, &currentResources)
-	if err := newStack.AddAllResources(ctx); err != nil {
-		return false, err
-	}
-
-	newTemplate, err := newStack.RenderJSON()
-	if err != nil {
-		return false, errors.Wrapf(err, "rendering template for %q stack", name)
-	}
-	logger.Debug("newTemplate = %s", newTemplate)
-
-	newResources := gjson.Get(string(newTemplate), resourcesRootPath)
-	newOutputs := gjson.Get(string(newTemplate), outputsRootPath)
-	newMappings := gjson.Get(string(newTemplate), mappingsRootPath)
+	newResources := gjson.Get(newTemplate, resourcesRootPath)
+	newOutputs := gjson.Get(newTemplate, outputsRootPath)
+	newMappings := gjson.Get(newTemplate, mappingsRootPath)
 	if !newResources.IsObject() || !newOutputs.IsObject() || !newMappings.IsObject() {
 		return false, errors.New("unexpected template format of the new version of the stack")
 	}
 
-	logger.Debug("currentTemplate = %s", currentTemplate)
-
 	var iterErr error
 	iterFunc := func(list *[]string, root string, currentSet, key, value gjson.Result) bool {
 		k := key.String()
@@ -206,6 +199,48 @@ func (c *StackCollection) AppendNewClusterStackResource(ctx context.Context, pla
 	})
 }
 
+// ClusterStackTemplates holds the current template of the cluster stack, along with the template rebuilt from the
+// cluster config on top of its resources
+type ClusterStackTemplates struct {
+	Current string
+	New     string
+}
+
+// RenderClusterStackTemplates returns the templates that AppendNewClusterStackResource updates the cluster stack
+// from, which can also be compared with DiffTemplateResources, e.g. for the version the cluster is being upgraded to
+func (c *StackCollection) RenderClusterStackTemplates(ctx context.Context) (*ClusterStackTemplates, error) {
+	name := c.MakeClusterStackName()
+	currentTemplate, err := c.GetStackTemplate(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting stack template %s", name)
+	}
+
+	currentResources := gjson.Get(currentTemplate, resourcesRootPath)
+	currentOutputs := gjson.Get(currentTemplate, outputsRootPath)
+	if !currentResources.IsObject() || !currentOutputs.IsObject() {
+		return nil, fmt.Errorf("unexpected template format of the current stack ")
+	}
+
+	if err := c.importServiceRoleARN(currentResources); err != nil {
+		return nil, err
+	}
+
+	logger.Info("re-building cluster stack %q", name)
+	newStack := builder.NewClusterResourceSet(c.ec2API, c.region, c.spec, &currentResources)
+	if err := newStack.AddAllResources(ctx); err != nil {
+		return nil, err
+	}
+
+	newTemplate, err := newStack.RenderJSON()
+	if err != nil {
+		return nil, errors.Wrapf(err, "rendering template for %q stack", name)
+	}
+	logger.Debug("newTemplate = %s", newTemplate)
+	logger.Debug("currentTemplate = %s", currentTemplate)
+
+	return &ClusterStackTemplates{Current: currentTemplate, New: string(newTemplate)}, nil
+}
+
 func (c *StackCollection) importServiceRoleARN(resources gjson.Result) error {
 	s, err := c.DescribeClusterStack()
 	if err != nil {
@@ -75,7 +75,11 @@ func (c *StackCollection) FixClusterCompatibility(ctx context.Context) error {
 	}
 
 	logger.Info("adding missing resources to cluster stack")
-	_, err = c.AppendNewClusterStackResource(ctx, false)
+	templates, err := c.RenderClusterStackTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = c.AppendNewClusterStackResource(templates, false)
 	return err
 }
 
@@ -47,7 +47,7 @@ func (c *StackCollection) NewTasksToCreateClusterWithNodeGroups(ctx context.Cont
 	}
 
 	appendNodeGroupTasksTo := func(taskTree *tasks.TaskTree) {
-		vpcImporter := vpc.NewStackConfigImporter(c.MakeClusterStackName())
+		vpcImporter := vpc.NewStackConfigImporter(c.MakeClusterStackName(), c.spec.VPC)
 		nodeGroupTasks := c.NewUnmanagedNodeGroupTask(ctx, nodeGroups, false, vpcImporter)
 		managedNodeGroupTasks := c.NewManagedNodeGroupTask(ctx, managedNodeGroups, false, vpcImporter)
 		if managedNodeGroupTasks.Len() > 0 {
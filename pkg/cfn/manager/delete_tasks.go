@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
@@ -133,10 +135,7 @@ func (d *DeleteUnownedNodegroupTask) Describe() string {
 }
 
 func (d *DeleteUnownedNodegroupTask) Do() error {
-	out, err := d.eksAPI.DeleteNodegroup(&eks.DeleteNodegroupInput{
-		ClusterName:   &d.cluster,
-		NodegroupName: &d.nodegroup,
-	})
+	out, err := d.deleteNodegroup()
 	if err != nil {
 		return err
 	}
@@ -163,6 +162,53 @@ func (d *DeleteUnownedNodegroupTask) Do() error {
 	return nil
 }
 
+// deleteNodegroup sends a request to delete the nodegroup, unless a previous, interrupted run has already
+// deleted it or is still deleting it; in that case only the wait condition applies. Nodegroups that are being
+// created or updated cannot be deleted
+func (d *DeleteUnownedNodegroupTask) deleteNodegroup() (*eks.DeleteNodegroupOutput, error) {
+	ng, err := d.eksAPI.DescribeNodegroup(&eks.DescribeNodegroupInput{
+		ClusterName:   &d.cluster,
+		NodegroupName: &d.nodegroup,
+	})
+	if err != nil {
+		if isEKSErrorCode(err, eks.ErrCodeResourceNotFoundException) {
+			logger.Info("nodegroup %q has already been deleted", d.nodegroup)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "describing nodegroup %q", d.nodegroup)
+	}
+	var status string
+	if ng.Nodegroup != nil {
+		status = aws.StringValue(ng.Nodegroup.Status)
+	}
+	if status == eks.NodegroupStatusDeleting {
+		logger.Info("nodegroup %q is already being deleted", d.nodegroup)
+		return nil, nil
+	}
+
+	out, err := d.eksAPI.DeleteNodegroup(&eks.DeleteNodegroupInput{
+		ClusterName:   &d.cluster,
+		NodegroupName: &d.nodegroup,
+	})
+	if err != nil {
+		switch {
+		case isEKSErrorCode(err, eks.ErrCodeResourceNotFoundException):
+			logger.Info("nodegroup %q has already been deleted", d.nodegroup)
+			return nil, nil
+		case isEKSErrorCode(err, eks.ErrCodeResourceInUseException):
+			// the nodegroup is being created or updated, which must complete before it can be deleted
+			return nil, errors.Wrapf(err, "nodegroup %q cannot be deleted while its status is %s, retry once the operation in progress completes", d.nodegroup, status)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func isEKSErrorCode(err error, code string) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == code
+}
+
 func (c *StackCollection) NewTaskToDeleteUnownedNodeGroup(clusterName, nodegroup string, eksAPI eksiface.EKSAPI, waitCondition *DeleteWaitCondition) tasks.Task {
 	return tasks.SynchronousTask{
 		SynchronousTaskIface: &DeleteUnownedNodegroupTask{
@@ -284,13 +330,14 @@ func stacksToServiceAccountMap(stacks []*cloudformation.Stack) map[string]*cloud
 
 // NewTaskToDeleteAddonIAM defines tasks required to delete all of the addons
 func (c *StackCollection) NewTaskToDeleteAddonIAM(wait bool) (*tasks.TaskTree, error) {
-	stacks, err := c.GetIAMAddonsStacks()
+	addonStacks, err := c.ListAddonIAMStacksToDelete()
 	if err != nil {
 		return nil, err
 	}
 	taskTree := &tasks.TaskTree{Parallel: true}
-	for _, s := range stacks {
-		info := fmt.Sprintf("delete addon IAM %q", *s.StackName)
+	for _, addonStack := range addonStacks {
+		s := addonStack.Stack
+		info := fmt.Sprintf("delete addon IAM %q", addonStack.StackName)
 
 		deleteStackTasks := &tasks.TaskTree{
 			Parallel:  false,
@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Delete tasks", func() {
+	var (
+		p   *mockprovider.MockProvider
+		cfg *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "my-cluster"
+		p = mockprovider.NewMockProvider()
+	})
+
+	newStack := func(name, status string) *Stack {
+		return &Stack{
+			StackName:   aws.String(name),
+			StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus: aws.String(status),
+			Tags: []*cfn.Tag{
+				{Key: aws.String(api.ClusterNameTag), Value: aws.String("my-cluster")},
+			},
+		}
+	}
+
+	Context("a partially-deleted cluster", func() {
+		BeforeEach(func() {
+			p.MockCloudFormation().On("DeleteStack", mock.Anything).Return(&cfn.DeleteStackOutput{}, nil)
+		})
+
+		It("does not delete a stack that is already being deleted", func() {
+			sm := NewStackCollection(p, cfg)
+			stack := newStack("eksctl-my-cluster-nodegroup-ng-1", cfn.StackStatusDeleteInProgress)
+			s, err := sm.DeleteStackBySpec(stack)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(stack))
+			Expect(p.MockCloudFormation().AssertNotCalled(GinkgoT(), "DeleteStack", mock.Anything)).To(BeTrue())
+		})
+
+		It("only deletes the nodegroup stacks that are not being deleted yet", func() {
+			sm := NewStackCollection(p, cfg)
+			taskTree, err := sm.NewTasksToDeleteNodeGroups([]NodeGroupStack{
+				{
+					NodeGroupName: "ng-1",
+					Stack:         newStack("eksctl-my-cluster-nodegroup-ng-1", cfn.StackStatusDeleteInProgress),
+				},
+				{
+					NodeGroupName: "ng-2",
+					Stack:         newStack("eksctl-my-cluster-nodegroup-ng-2", cfn.StackStatusCreateComplete),
+				},
+			}, deleteAll, false, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskTree.Len()).To(Equal(2))
+			Expect(taskTree.DoAllSync()).To(BeEmpty())
+
+			Expect(p.MockCloudFormation().AssertNumberOfCalls(GinkgoT(), "DeleteStack", 1)).To(BeTrue())
+			input := p.MockCloudFormation().Calls[0].Arguments.Get(0).(*cfn.DeleteStackInput)
+			Expect(*input.StackName).To(Equal("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-nodegroup-ng-2/1"))
+		})
+	})
+
+	Context("DeleteUnownedNodegroupTask", func() {
+		type unownedEntry struct {
+			describeOutput *eks.DescribeNodegroupOutput
+			describeErr    error
+			deleteErr      error
+
+			expectDelete bool
+			expectedErr  string
+		}
+
+		DescribeTable("deleting an unowned nodegroup", func(e unownedEntry) {
+			describeInput := &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String("my-cluster"),
+				NodegroupName: aws.String("ng"),
+			}
+			p.MockEKS().On("DescribeNodegroup", describeInput).Return(e.describeOutput, e.describeErr)
+			p.MockEKS().On("DeleteNodegroup", &eks.DeleteNodegroupInput{
+				ClusterName:   aws.String("my-cluster"),
+				NodegroupName: aws.String("ng"),
+			}).Return(&eks.DeleteNodegroupOutput{}, e.deleteErr)
+
+			waited := false
+			sm := NewStackCollection(p, cfg)
+			task := sm.NewTaskToDeleteUnownedNodeGroup("my-cluster", "ng", p.EKS(), &DeleteWaitCondition{
+				Condition: func() (bool, error) {
+					waited = true
+					return true, nil
+				},
+				Timeout:  time.Second,
+				Interval: time.Millisecond,
+			})
+
+			err := task.Do(make(chan error, 1))
+			if e.expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(e.expectedErr)))
+				Expect(waited).To(BeFalse())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waited).To(BeTrue())
+			if e.expectDelete {
+				Expect(p.MockEKS().AssertNumberOfCalls(GinkgoT(), "DeleteNodegroup", 1)).To(BeTrue())
+			} else {
+				Expect(p.MockEKS().AssertNotCalled(GinkgoT(), "DeleteNodegroup", mock.Anything)).To(BeTrue())
+			}
+		},
+			Entry("an active nodegroup is deleted", unownedEntry{
+				describeOutput: &eks.DescribeNodegroupOutput{
+					Nodegroup: &eks.Nodegroup{Status: aws.String(eks.NodegroupStatusActive)},
+				},
+				expectDelete: true,
+			}),
+			Entry("a nodegroup that is being deleted is awaited", unownedEntry{
+				describeOutput: &eks.DescribeNodegroupOutput{
+					Nodegroup: &eks.Nodegroup{Status: aws.String(eks.NodegroupStatusDeleting)},
+				},
+			}),
+			Entry("a nodegroup that no longer exists is awaited", unownedEntry{
+				describeErr: awserr.New(eks.ErrCodeResourceNotFoundException, "", nil),
+			}),
+			Entry("a nodegroup that is being updated is not deleted", unownedEntry{
+				describeOutput: &eks.DescribeNodegroupOutput{
+					Nodegroup: &eks.Nodegroup{Status: aws.String(eks.NodegroupStatusUpdating)},
+				},
+				deleteErr:    awserr.New(eks.ErrCodeResourceInUseException, "nodegroup is in use", nil),
+				expectDelete: true,
+				expectedErr:  `nodegroup "ng" cannot be deleted while its status is UPDATING`,
+			}),
+			Entry("other errors are returned", unownedEntry{
+				describeOutput: &eks.DescribeNodegroupOutput{
+					Nodegroup: &eks.Nodegroup{Status: aws.String(eks.NodegroupStatusActive)},
+				},
+				deleteErr:    awserr.New(eks.ErrCodeServerException, "server error", nil),
+				expectDelete: true,
+				expectedErr:  "server error",
+			}),
+		)
+	})
+})
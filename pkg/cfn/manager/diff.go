@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// ResourceChange describes a resource that differs between two versions of a stack template
+type ResourceChange struct {
+	LogicalID string
+	Type      string
+}
+
+// TemplateDiff holds the resources that are added, changed or removed by a new version of a stack template
+type TemplateDiff struct {
+	Added   []ResourceChange
+	Changed []ResourceChange
+	Removed []ResourceChange
+}
+
+// IsEmpty returns true if the templates define the same resources
+func (d *TemplateDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// DiffTemplateResources compares the resources of two stack templates, the changes are sorted by logical ID
+func DiffTemplateResources(currentTemplate, newTemplate string) (*TemplateDiff, error) {
+	currentResources := gjson.Get(currentTemplate, resourcesRootPath)
+	if !currentResources.IsObject() {
+		return nil, errors.New("unexpected template format of the current stack")
+	}
+	newResources := gjson.Get(newTemplate, resourcesRootPath)
+	if !newResources.IsObject() {
+		return nil, errors.New("unexpected template format of the new version of the stack")
+	}
+
+	resourceType := func(resource gjson.Result) string {
+		return resource.Get("Type").String()
+	}
+
+	current, updated := currentResources.Map(), newResources.Map()
+	diff := &TemplateDiff{}
+	for logicalID, newResource := range updated {
+		change := ResourceChange{LogicalID: logicalID, Type: resourceType(newResource)}
+		currentResource, ok := current[logicalID]
+		if !ok {
+			diff.Added = append(diff.Added, change)
+			continue
+		}
+		equal, err := jsonEqual(currentResource.Raw, newResource.Raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "comparing resource %q", logicalID)
+		}
+		if !equal {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for logicalID, currentResource := range current {
+		if _, ok := updated[logicalID]; !ok {
+			diff.Removed = append(diff.Removed, ResourceChange{LogicalID: logicalID, Type: resourceType(currentResource)})
+		}
+	}
+
+	for _, changes := range [][]ResourceChange{diff.Added, diff.Changed, diff.Removed} {
+		sort.Slice(changes, func(i, j int) bool {
+			return changes[i].LogicalID < changes[j].LogicalID
+		})
+	}
+	return diff, nil
+}
+
+// jsonEqual compares two JSON documents regardless of the order of their keys
+func jsonEqual(a, b string) (bool, error) {
+	var va, vb interface{}
+	if err := json.Unmarshal([]byte(a), &va); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(b), &vb); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(va, vb), nil
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffTemplateResources", func() {
+	currentTemplate := `{
+		"Resources": {
+			"ControlPlane": {"Type": "AWS::EKS::Cluster", "Properties": {"Name": "test", "Version": "1.21"}},
+			"ClusterSharedNodeSecurityGroup": {"Type": "AWS::EC2::SecurityGroup", "Properties": {"GroupDescription": "shared", "VpcId": {"Ref": "VPC"}}},
+			"NATIP": {"Type": "AWS::EC2::EIP", "Properties": {"Domain": "vpc"}}
+		},
+		"Outputs": {}
+	}`
+
+	It("reports the added, changed and removed resources sorted by logical ID", func() {
+		newTemplate := `{
+			"Resources": {
+				"ClusterSharedNodeSecurityGroup": {"Properties": {"VpcId": {"Ref": "VPC"}, "GroupDescription": "shared"}, "Type": "AWS::EC2::SecurityGroup"},
+				"ControlPlane": {"Type": "AWS::EKS::Cluster", "Properties": {"Name": "test", "Version": "1.22"}},
+				"PolicyELBPermissions": {"Type": "AWS::IAM::Policy", "Properties": {}},
+				"IngressDefaultClusterToNodeSG": {"Type": "AWS::EC2::SecurityGroupIngress", "Properties": {}}
+			},
+			"Outputs": {}
+		}`
+
+		diff, err := DiffTemplateResources(currentTemplate, newTemplate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeFalse())
+		Expect(diff.Added).To(Equal([]ResourceChange{
+			{LogicalID: "IngressDefaultClusterToNodeSG", Type: "AWS::EC2::SecurityGroupIngress"},
+			{LogicalID: "PolicyELBPermissions", Type: "AWS::IAM::Policy"},
+		}))
+		Expect(diff.Changed).To(Equal([]ResourceChange{
+			{LogicalID: "ControlPlane", Type: "AWS::EKS::Cluster"},
+		}))
+		Expect(diff.Removed).To(Equal([]ResourceChange{
+			{LogicalID: "NATIP", Type: "AWS::EC2::EIP"},
+		}))
+	})
+
+	It("reports no changes for the same resources", func() {
+		diff, err := DiffTemplateResources(currentTemplate, currentTemplate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+	})
+
+	It("fails if a template has no resources", func() {
+		_, err := DiffTemplateResources(currentTemplate, `{"Outputs": {}}`)
+		Expect(err).To(MatchError("unexpected template format of the new version of the stack"))
+	})
+})
@@ -19,10 +19,10 @@ import (
 )
 
 type FakeStackManager struct {
-	AppendNewClusterStackResourceStub        func(context.Context, bool) (bool, error)
+	AppendNewClusterStackResourceStub        func(*manager.ClusterStackTemplates, bool) (bool, error)
 	appendNewClusterStackResourceMutex       sync.RWMutex
 	appendNewClusterStackResourceArgsForCall []struct {
-		arg1 context.Context
+		arg1 *manager.ClusterStackTemplates
 		arg2 bool
 	}
 	appendNewClusterStackResourceReturns struct {
@@ -369,6 +369,19 @@ type FakeStackManager struct {
 		result1 string
 		result2 error
 	}
+	GetNodeGroupInstanceRoleARNStub        func(*cloudformation.Stack) (string, error)
+	getNodeGroupInstanceRoleARNMutex       sync.RWMutex
+	getNodeGroupInstanceRoleARNArgsForCall []struct {
+		arg1 *cloudformation.Stack
+	}
+	getNodeGroupInstanceRoleARNReturns struct {
+		result1 string
+		result2 error
+	}
+	getNodeGroupInstanceRoleARNReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	GetNodeGroupNameStub        func(*cloudformation.Stack) string
 	getNodeGroupNameMutex       sync.RWMutex
 	getNodeGroupNameArgsForCall []struct {
@@ -433,6 +446,18 @@ type FakeStackManager struct {
 		result1 bool
 		result2 error
 	}
+	ListAddonIAMStacksToDeleteStub        func() ([]manager.AddonIAMStack, error)
+	listAddonIAMStacksToDeleteMutex       sync.RWMutex
+	listAddonIAMStacksToDeleteArgsForCall []struct {
+	}
+	listAddonIAMStacksToDeleteReturns struct {
+		result1 []manager.AddonIAMStack
+		result2 error
+	}
+	listAddonIAMStacksToDeleteReturnsOnCall map[int]struct {
+		result1 []manager.AddonIAMStack
+		result2 error
+	}
 	ListClusterStackNamesStub        func() ([]string, error)
 	listClusterStackNamesMutex       sync.RWMutex
 	listClusterStackNamesArgsForCall []struct {
@@ -700,6 +725,24 @@ type FakeStackManager struct {
 	refreshFargatePodExecutionRoleARNReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenderClusterStackTemplatesStub        func(context.Context) (*manager.ClusterStackTemplates, error)
+	renderClusterStackTemplatesMutex       sync.RWMutex
+	renderClusterStackTemplatesArgsForCall []struct {
+		arg1 context.Context
+	}
+	renderClusterStackTemplatesReturns struct {
+		result1 *manager.ClusterStackTemplates
+		result2 error
+	}
+	renderClusterStackTemplatesReturnsOnCall map[int]struct {
+		result1 *manager.ClusterStackTemplates
+		result2 error
+	}
+	SetClusterConfigHashStub        func(string)
+	setClusterConfigHashMutex       sync.RWMutex
+	setClusterConfigHashArgsForCall []struct {
+		arg1 string
+	}
 	StackStatusIsNotReadyStub        func(*cloudformation.Stack) bool
 	stackStatusIsNotReadyMutex       sync.RWMutex
 	stackStatusIsNotReadyArgsForCall []struct {
@@ -722,12 +765,11 @@ type FakeStackManager struct {
 	stackStatusIsNotTransitionalReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	UpdateNodeGroupStackStub        func(string, string, bool) error
+	UpdateNodeGroupStackStub        func(context.Context, manager.UpdateNodeGroupStackOptions) error
 	updateNodeGroupStackMutex       sync.RWMutex
 	updateNodeGroupStackArgsForCall []struct {
-		arg1 string
-		arg2 string
-		arg3 bool
+		arg1 context.Context
+		arg2 manager.UpdateNodeGroupStackOptions
 	}
 	updateNodeGroupStackReturns struct {
 		result1 error
@@ -750,11 +792,11 @@ type FakeStackManager struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStackManager) AppendNewClusterStackResource(arg1 context.Context, arg2 bool) (bool, error) {
+func (fake *FakeStackManager) AppendNewClusterStackResource(arg1 *manager.ClusterStackTemplates, arg2 bool) (bool, error) {
 	fake.appendNewClusterStackResourceMutex.Lock()
 	ret, specificReturn := fake.appendNewClusterStackResourceReturnsOnCall[len(fake.appendNewClusterStackResourceArgsForCall)]
 	fake.appendNewClusterStackResourceArgsForCall = append(fake.appendNewClusterStackResourceArgsForCall, struct {
-		arg1 context.Context
+		arg1 *manager.ClusterStackTemplates
 		arg2 bool
 	}{arg1, arg2})
 	stub := fake.AppendNewClusterStackResourceStub
@@ -776,13 +818,13 @@ func (fake *FakeStackManager) AppendNewClusterStackResourceCallCount() int {
 	return len(fake.appendNewClusterStackResourceArgsForCall)
 }
 
-func (fake *FakeStackManager) AppendNewClusterStackResourceCalls(stub func(context.Context, bool) (bool, error)) {
+func (fake *FakeStackManager) AppendNewClusterStackResourceCalls(stub func(*manager.ClusterStackTemplates, bool) (bool, error)) {
 	fake.appendNewClusterStackResourceMutex.Lock()
 	defer fake.appendNewClusterStackResourceMutex.Unlock()
 	fake.AppendNewClusterStackResourceStub = stub
 }
 
-func (fake *FakeStackManager) AppendNewClusterStackResourceArgsForCall(i int) (context.Context, bool) {
+func (fake *FakeStackManager) AppendNewClusterStackResourceArgsForCall(i int) (*manager.ClusterStackTemplates, bool) {
 	fake.appendNewClusterStackResourceMutex.RLock()
 	defer fake.appendNewClusterStackResourceMutex.RUnlock()
 	argsForCall := fake.appendNewClusterStackResourceArgsForCall[i]
@@ -2443,6 +2485,70 @@ func (fake *FakeStackManager) GetManagedNodeGroupTemplateReturnsOnCall(i int, re
 	}{result1, result2}
 }
 
+func (fake *FakeStackManager) GetNodeGroupInstanceRoleARN(arg1 *cloudformation.Stack) (string, error) {
+	fake.getNodeGroupInstanceRoleARNMutex.Lock()
+	ret, specificReturn := fake.getNodeGroupInstanceRoleARNReturnsOnCall[len(fake.getNodeGroupInstanceRoleARNArgsForCall)]
+	fake.getNodeGroupInstanceRoleARNArgsForCall = append(fake.getNodeGroupInstanceRoleARNArgsForCall, struct {
+		arg1 *cloudformation.Stack
+	}{arg1})
+	stub := fake.GetNodeGroupInstanceRoleARNStub
+	fakeReturns := fake.getNodeGroupInstanceRoleARNReturns
+	fake.recordInvocation("GetNodeGroupInstanceRoleARN", []interface{}{arg1})
+	fake.getNodeGroupInstanceRoleARNMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStackManager) GetNodeGroupInstanceRoleARNCallCount() int {
+	fake.getNodeGroupInstanceRoleARNMutex.RLock()
+	defer fake.getNodeGroupInstanceRoleARNMutex.RUnlock()
+	return len(fake.getNodeGroupInstanceRoleARNArgsForCall)
+}
+
+func (fake *FakeStackManager) GetNodeGroupInstanceRoleARNCalls(stub func(*cloudformation.Stack) (string, error)) {
+	fake.getNodeGroupInstanceRoleARNMutex.Lock()
+	defer fake.getNodeGroupInstanceRoleARNMutex.Unlock()
+	fake.GetNodeGroupInstanceRoleARNStub = stub
+}
+
+func (fake *FakeStackManager) GetNodeGroupInstanceRoleARNArgsForCall(i int) *cloudformation.Stack {
+	fake.getNodeGroupInstanceRoleARNMutex.RLock()
+	defer fake.getNodeGroupInstanceRoleARNMutex.RUnlock()
+	argsForCall := fake.getNodeGroupInstanceRoleARNArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStackManager) GetNodeGroupInstanceRoleARNReturns(result1 string, result2 error) {
+	fake.getNodeGroupInstanceRoleARNMutex.Lock()
+	defer fake.getNodeGroupInstanceRoleARNMutex.Unlock()
+	fake.GetNodeGroupInstanceRoleARNStub = nil
+	fake.getNodeGroupInstanceRoleARNReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStackManager) GetNodeGroupInstanceRoleARNReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getNodeGroupInstanceRoleARNMutex.Lock()
+	defer fake.getNodeGroupInstanceRoleARNMutex.Unlock()
+	fake.GetNodeGroupInstanceRoleARNStub = nil
+	if fake.getNodeGroupInstanceRoleARNReturnsOnCall == nil {
+		fake.getNodeGroupInstanceRoleARNReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getNodeGroupInstanceRoleARNReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStackManager) GetNodeGroupName(arg1 *cloudformation.Stack) string {
 	fake.getNodeGroupNameMutex.Lock()
 	ret, specificReturn := fake.getNodeGroupNameReturnsOnCall[len(fake.getNodeGroupNameArgsForCall)]
@@ -2766,6 +2872,62 @@ func (fake *FakeStackManager) HasClusterStackFromListReturnsOnCall(i int, result
 	}{result1, result2}
 }
 
+func (fake *FakeStackManager) ListAddonIAMStacksToDelete() ([]manager.AddonIAMStack, error) {
+	fake.listAddonIAMStacksToDeleteMutex.Lock()
+	ret, specificReturn := fake.listAddonIAMStacksToDeleteReturnsOnCall[len(fake.listAddonIAMStacksToDeleteArgsForCall)]
+	fake.listAddonIAMStacksToDeleteArgsForCall = append(fake.listAddonIAMStacksToDeleteArgsForCall, struct {
+	}{})
+	stub := fake.ListAddonIAMStacksToDeleteStub
+	fakeReturns := fake.listAddonIAMStacksToDeleteReturns
+	fake.recordInvocation("ListAddonIAMStacksToDelete", []interface{}{})
+	fake.listAddonIAMStacksToDeleteMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStackManager) ListAddonIAMStacksToDeleteCallCount() int {
+	fake.listAddonIAMStacksToDeleteMutex.RLock()
+	defer fake.listAddonIAMStacksToDeleteMutex.RUnlock()
+	return len(fake.listAddonIAMStacksToDeleteArgsForCall)
+}
+
+func (fake *FakeStackManager) ListAddonIAMStacksToDeleteCalls(stub func() ([]manager.AddonIAMStack, error)) {
+	fake.listAddonIAMStacksToDeleteMutex.Lock()
+	defer fake.listAddonIAMStacksToDeleteMutex.Unlock()
+	fake.ListAddonIAMStacksToDeleteStub = stub
+}
+
+func (fake *FakeStackManager) ListAddonIAMStacksToDeleteReturns(result1 []manager.AddonIAMStack, result2 error) {
+	fake.listAddonIAMStacksToDeleteMutex.Lock()
+	defer fake.listAddonIAMStacksToDeleteMutex.Unlock()
+	fake.ListAddonIAMStacksToDeleteStub = nil
+	fake.listAddonIAMStacksToDeleteReturns = struct {
+		result1 []manager.AddonIAMStack
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStackManager) ListAddonIAMStacksToDeleteReturnsOnCall(i int, result1 []manager.AddonIAMStack, result2 error) {
+	fake.listAddonIAMStacksToDeleteMutex.Lock()
+	defer fake.listAddonIAMStacksToDeleteMutex.Unlock()
+	fake.ListAddonIAMStacksToDeleteStub = nil
+	if fake.listAddonIAMStacksToDeleteReturnsOnCall == nil {
+		fake.listAddonIAMStacksToDeleteReturnsOnCall = make(map[int]struct {
+			result1 []manager.AddonIAMStack
+			result2 error
+		})
+	}
+	fake.listAddonIAMStacksToDeleteReturnsOnCall[i] = struct {
+		result1 []manager.AddonIAMStack
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStackManager) ListClusterStackNames() ([]string, error) {
 	fake.listClusterStackNamesMutex.Lock()
 	ret, specificReturn := fake.listClusterStackNamesReturnsOnCall[len(fake.listClusterStackNamesArgsForCall)]
@@ -4049,6 +4211,104 @@ func (fake *FakeStackManager) RefreshFargatePodExecutionRoleARNReturnsOnCall(i i
 	}{result1}
 }
 
+func (fake *FakeStackManager) RenderClusterStackTemplates(arg1 context.Context) (*manager.ClusterStackTemplates, error) {
+	fake.renderClusterStackTemplatesMutex.Lock()
+	ret, specificReturn := fake.renderClusterStackTemplatesReturnsOnCall[len(fake.renderClusterStackTemplatesArgsForCall)]
+	fake.renderClusterStackTemplatesArgsForCall = append(fake.renderClusterStackTemplatesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RenderClusterStackTemplatesStub
+	fakeReturns := fake.renderClusterStackTemplatesReturns
+	fake.recordInvocation("RenderClusterStackTemplates", []interface{}{arg1})
+	fake.renderClusterStackTemplatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStackManager) RenderClusterStackTemplatesCallCount() int {
+	fake.renderClusterStackTemplatesMutex.RLock()
+	defer fake.renderClusterStackTemplatesMutex.RUnlock()
+	return len(fake.renderClusterStackTemplatesArgsForCall)
+}
+
+func (fake *FakeStackManager) RenderClusterStackTemplatesCalls(stub func(context.Context) (*manager.ClusterStackTemplates, error)) {
+	fake.renderClusterStackTemplatesMutex.Lock()
+	defer fake.renderClusterStackTemplatesMutex.Unlock()
+	fake.RenderClusterStackTemplatesStub = stub
+}
+
+func (fake *FakeStackManager) RenderClusterStackTemplatesArgsForCall(i int) context.Context {
+	fake.renderClusterStackTemplatesMutex.RLock()
+	defer fake.renderClusterStackTemplatesMutex.RUnlock()
+	argsForCall := fake.renderClusterStackTemplatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStackManager) RenderClusterStackTemplatesReturns(result1 *manager.ClusterStackTemplates, result2 error) {
+	fake.renderClusterStackTemplatesMutex.Lock()
+	defer fake.renderClusterStackTemplatesMutex.Unlock()
+	fake.RenderClusterStackTemplatesStub = nil
+	fake.renderClusterStackTemplatesReturns = struct {
+		result1 *manager.ClusterStackTemplates
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStackManager) RenderClusterStackTemplatesReturnsOnCall(i int, result1 *manager.ClusterStackTemplates, result2 error) {
+	fake.renderClusterStackTemplatesMutex.Lock()
+	defer fake.renderClusterStackTemplatesMutex.Unlock()
+	fake.RenderClusterStackTemplatesStub = nil
+	if fake.renderClusterStackTemplatesReturnsOnCall == nil {
+		fake.renderClusterStackTemplatesReturnsOnCall = make(map[int]struct {
+			result1 *manager.ClusterStackTemplates
+			result2 error
+		})
+	}
+	fake.renderClusterStackTemplatesReturnsOnCall[i] = struct {
+		result1 *manager.ClusterStackTemplates
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStackManager) SetClusterConfigHash(arg1 string) {
+	fake.setClusterConfigHashMutex.Lock()
+	fake.setClusterConfigHashArgsForCall = append(fake.setClusterConfigHashArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SetClusterConfigHashStub
+	fake.recordInvocation("SetClusterConfigHash", []interface{}{arg1})
+	fake.setClusterConfigHashMutex.Unlock()
+	if stub != nil {
+		fake.SetClusterConfigHashStub(arg1)
+	}
+}
+
+func (fake *FakeStackManager) SetClusterConfigHashCallCount() int {
+	fake.renderClusterStackTemplatesMutex.RLock()
+	defer fake.renderClusterStackTemplatesMutex.RUnlock()
+	fake.setClusterConfigHashMutex.RLock()
+	defer fake.setClusterConfigHashMutex.RUnlock()
+	return len(fake.setClusterConfigHashArgsForCall)
+}
+
+func (fake *FakeStackManager) SetClusterConfigHashCalls(stub func(string)) {
+	fake.setClusterConfigHashMutex.Lock()
+	defer fake.setClusterConfigHashMutex.Unlock()
+	fake.SetClusterConfigHashStub = stub
+}
+
+func (fake *FakeStackManager) SetClusterConfigHashArgsForCall(i int) string {
+	fake.setClusterConfigHashMutex.RLock()
+	defer fake.setClusterConfigHashMutex.RUnlock()
+	argsForCall := fake.setClusterConfigHashArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStackManager) StackStatusIsNotReady(arg1 *cloudformation.Stack) bool {
 	fake.stackStatusIsNotReadyMutex.Lock()
 	ret, specificReturn := fake.stackStatusIsNotReadyReturnsOnCall[len(fake.stackStatusIsNotReadyArgsForCall)]
@@ -4171,20 +4431,19 @@ func (fake *FakeStackManager) StackStatusIsNotTransitionalReturnsOnCall(i int, r
 	}{result1}
 }
 
-func (fake *FakeStackManager) UpdateNodeGroupStack(arg1 string, arg2 string, arg3 bool) error {
+func (fake *FakeStackManager) UpdateNodeGroupStack(arg1 context.Context, arg2 manager.UpdateNodeGroupStackOptions) error {
 	fake.updateNodeGroupStackMutex.Lock()
 	ret, specificReturn := fake.updateNodeGroupStackReturnsOnCall[len(fake.updateNodeGroupStackArgsForCall)]
 	fake.updateNodeGroupStackArgsForCall = append(fake.updateNodeGroupStackArgsForCall, struct {
-		arg1 string
-		arg2 string
-		arg3 bool
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 manager.UpdateNodeGroupStackOptions
+	}{arg1, arg2})
 	stub := fake.UpdateNodeGroupStackStub
 	fakeReturns := fake.updateNodeGroupStackReturns
-	fake.recordInvocation("UpdateNodeGroupStack", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdateNodeGroupStack", []interface{}{arg1, arg2})
 	fake.updateNodeGroupStackMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -4198,17 +4457,17 @@ func (fake *FakeStackManager) UpdateNodeGroupStackCallCount() int {
 	return len(fake.updateNodeGroupStackArgsForCall)
 }
 
-func (fake *FakeStackManager) UpdateNodeGroupStackCalls(stub func(string, string, bool) error) {
+func (fake *FakeStackManager) UpdateNodeGroupStackCalls(stub func(context.Context, manager.UpdateNodeGroupStackOptions) error) {
 	fake.updateNodeGroupStackMutex.Lock()
 	defer fake.updateNodeGroupStackMutex.Unlock()
 	fake.UpdateNodeGroupStackStub = stub
 }
 
-func (fake *FakeStackManager) UpdateNodeGroupStackArgsForCall(i int) (string, string, bool) {
+func (fake *FakeStackManager) UpdateNodeGroupStackArgsForCall(i int) (context.Context, manager.UpdateNodeGroupStackOptions) {
 	fake.updateNodeGroupStackMutex.RLock()
 	defer fake.updateNodeGroupStackMutex.RUnlock()
 	argsForCall := fake.updateNodeGroupStackArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStackManager) UpdateNodeGroupStackReturns(result1 error) {
@@ -4354,6 +4613,8 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.getKarpenterStackMutex.RUnlock()
 	fake.getManagedNodeGroupTemplateMutex.RLock()
 	defer fake.getManagedNodeGroupTemplateMutex.RUnlock()
+	fake.getNodeGroupInstanceRoleARNMutex.RLock()
+	defer fake.getNodeGroupInstanceRoleARNMutex.RUnlock()
 	fake.getNodeGroupNameMutex.RLock()
 	defer fake.getNodeGroupNameMutex.RUnlock()
 	fake.getNodeGroupStackTypeMutex.RLock()
@@ -4364,6 +4625,8 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.getUnmanagedNodeGroupAutoScalingGroupNameMutex.RUnlock()
 	fake.hasClusterStackFromListMutex.RLock()
 	defer fake.hasClusterStackFromListMutex.RUnlock()
+	fake.listAddonIAMStacksToDeleteMutex.RLock()
+	defer fake.listAddonIAMStacksToDeleteMutex.RUnlock()
 	fake.listClusterStackNamesMutex.RLock()
 	defer fake.listClusterStackNamesMutex.RUnlock()
 	fake.listIAMServiceAccountStacksMutex.RLock()
@@ -4404,6 +4667,8 @@ func (fake *FakeStackManager) Invocations() map[string][][]interface{} {
 	defer fake.newUnmanagedNodeGroupTaskMutex.RUnlock()
 	fake.refreshFargatePodExecutionRoleARNMutex.RLock()
 	defer fake.refreshFargatePodExecutionRoleARNMutex.RUnlock()
+	fake.setClusterConfigHashMutex.RLock()
+	defer fake.setClusterConfigHashMutex.RUnlock()
 	fake.stackStatusIsNotReadyMutex.RLock()
 	defer fake.stackStatusIsNotReadyMutex.RUnlock()
 	fake.stackStatusIsNotTransitionalMutex.RLock()
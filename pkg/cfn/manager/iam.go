@@ -3,6 +3,7 @@ package manager
 import (
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/kris-nova/logger"
 
@@ -141,6 +142,33 @@ func (c *StackCollection) GetIAMAddonsStacks() ([]*Stack, error) {
 	return iamAddonStacks, nil
 }
 
+// AddonIAMStack represents the stack holding the IAM resources of an addon
+type AddonIAMStack struct {
+	AddonName string
+	StackName string
+	StackARN  string
+	Stack     *Stack
+}
+
+// ListAddonIAMStacksToDelete returns the addon IAM stacks that are deleted along with the cluster, without deleting them
+func (c *StackCollection) ListAddonIAMStacksToDelete() ([]AddonIAMStack, error) {
+	stacks, err := c.GetIAMAddonsStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	addonStacks := []AddonIAMStack{}
+	for _, s := range stacks {
+		addonStacks = append(addonStacks, AddonIAMStack{
+			AddonName: c.GetIAMAddonName(s),
+			StackName: aws.StringValue(s.StackName),
+			StackARN:  aws.StringValue(s.StackId),
+			Stack:     s,
+		})
+	}
+	return addonStacks, nil
+}
+
 func (*StackCollection) GetIAMAddonName(s *Stack) string {
 	for _, tag := range s.Tags {
 		if *tag.Key == api.AddonNameTag {
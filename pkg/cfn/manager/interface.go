@@ -27,6 +27,19 @@ type UpdateStackOptions struct {
 	Wait          bool
 }
 
+// UpdateNodeGroupStackOptions are the options for updating a nodegroup stack
+type UpdateNodeGroupStackOptions struct {
+	NodeGroupName string
+	// Stack is the nodegroup stack, it is described if not set
+	Stack        *Stack
+	TemplateData TemplateData
+	Parameters   map[string]string
+	Wait         bool
+	// PreserveDesiredCapacity keeps the current desired capacity of an autoscaled nodegroup, so that the capacity
+	// set by e.g. cluster-autoscaler is not reset to the one of the template
+	PreserveDesiredCapacity bool
+}
+
 // GetNodegroupOption nodegroup options.
 type GetNodegroupOption struct {
 	Stack         *NodeGroupStack
@@ -38,7 +51,7 @@ var _ StackManager = &StackCollection{}
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o fakes/fake_stack_manager.go . StackManager
 type StackManager interface {
-	AppendNewClusterStackResource(ctx context.Context, plan bool) (bool, error)
+	AppendNewClusterStackResource(templates *ClusterStackTemplates, plan bool) (bool, error)
 	CreateStack(name string, stack builder.ResourceSetReader, tags, parameters map[string]string, errs chan error) error
 	DeleteStackBySpec(s *Stack) (*Stack, error)
 	DeleteStackBySpecSync(s *Stack, errs chan error) error
@@ -66,11 +79,13 @@ type StackManager interface {
 	GetIAMServiceAccounts() ([]*v1alpha5.ClusterIAMServiceAccount, error)
 	GetKarpenterStack() (*Stack, error)
 	GetManagedNodeGroupTemplate(options GetNodegroupOption) (string, error)
+	GetNodeGroupInstanceRoleARN(s *Stack) (string, error)
 	GetNodeGroupName(s *Stack) string
 	GetNodeGroupStackType(options GetNodegroupOption) (v1alpha5.NodeGroupType, error)
 	GetStackTemplate(stackName string) (string, error)
 	GetUnmanagedNodeGroupAutoScalingGroupName(s *Stack) (string, error)
 	HasClusterStackFromList(clusterStackNames []string, clusterName string) (bool, error)
+	ListAddonIAMStacksToDelete() ([]AddonIAMStack, error)
 	ListClusterStackNames() ([]string, error)
 	ListIAMServiceAccountStacks() ([]string, error)
 	ListNodeGroupStacks() ([]NodeGroupStack, error)
@@ -91,8 +106,10 @@ type StackManager interface {
 	NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(ctx context.Context, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) (*tasks.TaskTree, error)
 	NewUnmanagedNodeGroupTask(ctx context.Context, nodeGroups []*v1alpha5.NodeGroup, forceAddCNIPolicy bool, importer vpc.Importer) *tasks.TaskTree
 	RefreshFargatePodExecutionRoleARN() error
+	RenderClusterStackTemplates(ctx context.Context) (*ClusterStackTemplates, error)
+	SetClusterConfigHash(hash string)
 	StackStatusIsNotReady(s *Stack) bool
 	StackStatusIsNotTransitional(s *Stack) bool
-	UpdateNodeGroupStack(nodeGroupName, template string, wait bool) error
+	UpdateNodeGroupStack(ctx context.Context, options UpdateNodeGroupStackOptions) error
 	UpdateStack(options UpdateStackOptions) error
 }
@@ -3,16 +3,20 @@ package manager
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/blang/semver"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
@@ -21,11 +25,17 @@ import (
 	"github.com/weaveworks/eksctl/pkg/vpc"
 )
 
+const (
+	unmanagedDesiredCapacityPath = "Resources.NodeGroup.Properties.DesiredCapacity"
+	managedDesiredCapacityPath   = "Resources.ManagedNodeGroup.Properties.ScalingConfig.DesiredSize"
+)
+
 // NodeGroupStack represents a nodegroup and its type
 type NodeGroupStack struct {
 	NodeGroupName string
-	Type          api.NodeGroupType
-	Stack         *Stack
+	// Type is read from the type tag of the stack, stacks without it are unmanaged
+	Type  api.NodeGroupType
+	Stack *Stack
 }
 
 // makeNodeGroupStackName generates the name of the nodegroup stack identified by its name, isolated by the cluster this StackCollection operates on
@@ -53,8 +63,12 @@ func (c *StackCollection) createNodeGroupTask(ctx context.Context, errs chan err
 	ng.Tags[api.NodeGroupNameTag] = ng.Name
 	ng.Tags[api.OldNodeGroupNameTag] = ng.Name
 	ng.Tags[api.NodeGroupTypeTag] = string(api.NodeGroupTypeUnmanaged)
+	if ng.AMIFamily != "" {
+		ng.Tags[api.NodeGroupAMIFamilyTag] = ng.AMIFamily
+	}
+	setSSHUserTag(ng.NodeGroupBase)
 
-	return c.CreateStack(name, stack, ng.Tags, nil, errs)
+	return c.createNodeGroupStack(name, stack, ng.NodeGroupBase, stack.WithoutReservedTags(ng.Tags), errs)
 }
 
 func (c *StackCollection) createManagedNodeGroupTask(ctx context.Context, errorCh chan error, ng *api.ManagedNodeGroup, forceAddCNIPolicy bool, vpcImporter vpc.Importer) error {
@@ -72,8 +86,20 @@ func (c *StackCollection) createManagedNodeGroupTask(ctx context.Context, errorC
 	if err := stack.AddAllResources(ctx); err != nil {
 		return err
 	}
+	setSSHUserTag(ng.NodeGroupBase)
 
-	return c.CreateStack(name, stack, ng.Tags, nil, errorCh)
+	return c.createNodeGroupStack(name, stack, ng.NodeGroupBase, ng.Tags, errorCh)
+}
+
+// setSSHUserTag records the user to SSH onto the nodes as in the tags of the nodegroup stack
+func setSSHUserTag(ng *api.NodeGroupBase) {
+	if ng.SSH == nil || !api.IsEnabled(ng.SSH.Allow) || ng.SSH.User == "" {
+		return
+	}
+	if ng.Tags == nil {
+		ng.Tags = make(map[string]string)
+	}
+	ng.Tags[api.NodeGroupSSHUserTag] = ng.SSH.User
 }
 
 // DescribeNodeGroupStacks calls DescribeStacks and filters out nodegroups
@@ -112,9 +138,13 @@ func (c *StackCollection) ListNodeGroupStacks() ([]NodeGroupStack, error) {
 	}
 	var nodeGroupStacks []NodeGroupStack
 	for _, stack := range stacks {
-		nodeGroupType, err := GetNodeGroupType(stack.Tags)
-		if err != nil {
-			return nil, err
+		// legacy stacks are only identified by their name, and predate managed nodegroups
+		nodeGroupType := api.NodeGroupTypeUnmanaged
+		if GetNodegroupTagName(stack.Tags) != "" {
+			nodeGroupType, err = GetNodeGroupType(stack.Tags)
+			if err != nil {
+				return nil, err
+			}
 		}
 		nodeGroupStacks = append(nodeGroupStacks, NodeGroupStack{
 			NodeGroupName: c.GetNodeGroupName(stack),
@@ -192,6 +222,35 @@ func (c *StackCollection) GetUnmanagedNodeGroupAutoScalingGroupName(s *Stack) (s
 	return *res.StackResourceDetail.PhysicalResourceId, nil
 }
 
+// GetNodeGroupInstanceRoleARN returns the ARN of the instance role created by a nodegroup stack. It is used for
+// stacks that do not have the InstanceRoleARN output, e.g. stacks that were adopted rather than created by eksctl
+func (c *StackCollection) GetNodeGroupInstanceRoleARN(s *Stack) (string, error) {
+	stackARN, err := arn.Parse(*s.StackId)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing ARN of stack %q", *s.StackName)
+	}
+
+	resources, err := c.cloudformationAPI.DescribeStackResources(&cfn.DescribeStackResourcesInput{
+		StackName: s.StackName,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "getting all resources for %q stack", *s.StackName)
+	}
+
+	for _, r := range resources.StackResources {
+		if *r.ResourceType != "AWS::IAM::Role" || r.PhysicalResourceId == nil {
+			continue
+		}
+		return arn.ARN{
+			Partition: stackARN.Partition,
+			Service:   "iam",
+			AccountID: stackARN.AccountID,
+			Resource:  "role/" + *r.PhysicalResourceId,
+		}.String(), nil
+	}
+	return "", fmt.Errorf("no instance role found in stack %q", *s.StackName)
+}
+
 // GetManagedNodeGroupAutoScalingGroupName returns the managed nodegroup's AutoScalingGroup names
 func (c *StackCollection) getManagedNodeGroupAutoScalingGroupName(s *Stack) (string, error) {
 	input := &eks.DescribeNodegroupInput{
@@ -234,6 +293,104 @@ func (c *StackCollection) GetAutoScalingGroupDesiredCapacity(ctx context.Context
 	return asg.AutoScalingGroups[0], nil
 }
 
+// preserveDesiredCapacity sets the desired capacity of the nodegroup in the template or the parameters of the
+// stack update to the current one if the nodegroup is autoscaled, as CloudFormation would otherwise reset the
+// capacity set since by e.g. cluster-autoscaler. A desired capacity set in the parameters is kept as is
+func (c *StackCollection) preserveDesiredCapacity(ctx context.Context, options *UpdateNodeGroupStackOptions) error {
+	if _, ok := options.Parameters[builder.NodeGroupDesiredCapacityParameter]; ok {
+		return nil
+	}
+
+	nodeGroupType, err := GetNodeGroupType(options.Stack.Tags)
+	if err != nil {
+		// stacks without the nodegroup name tag were created by older versions of eksctl for unmanaged nodegroups
+		nodeGroupType = api.NodeGroupTypeUnmanaged
+	}
+	desiredCapacityPath := unmanagedDesiredCapacityPath
+	if nodeGroupType == api.NodeGroupTypeManaged {
+		desiredCapacityPath = managedDesiredCapacityPath
+	}
+
+	var usesParameter bool
+	switch templateData := options.TemplateData.(type) {
+	case TemplateBody:
+		desiredCapacity := gjson.GetBytes(templateData, desiredCapacityPath)
+		if !desiredCapacity.Exists() {
+			return nil
+		}
+		usesParameter = desiredCapacity.Get("Ref").String() == builder.NodeGroupDesiredCapacityParameter
+	case PreviousTemplate:
+		for _, p := range options.Stack.Parameters {
+			if aws.StringValue(p.ParameterKey) == builder.NodeGroupDesiredCapacityParameter {
+				usesParameter = true
+			}
+		}
+		if !usesParameter {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	desiredCapacity, autoscaled, err := c.getCurrentDesiredCapacity(ctx, options.Stack, nodeGroupType)
+	if err != nil {
+		return errors.Wrapf(err, "getting the desired capacity of nodegroup %q", c.GetNodeGroupName(options.Stack))
+	}
+	if !autoscaled {
+		return nil
+	}
+
+	if usesParameter {
+		parameters := map[string]string{
+			builder.NodeGroupDesiredCapacityParameter: strconv.Itoa(desiredCapacity),
+		}
+		for k, v := range options.Parameters {
+			parameters[k] = v
+		}
+		options.Parameters = parameters
+	} else {
+		template, err := sjson.SetBytes(options.TemplateData.(TemplateBody), desiredCapacityPath, desiredCapacity)
+		if err != nil {
+			return errors.Wrap(err, "unexpected error updating nodegroup template")
+		}
+		options.TemplateData = TemplateBody(template)
+	}
+	logger.Info("preserving the current desired capacity (%d) of autoscaled nodegroup %q", desiredCapacity, c.GetNodeGroupName(options.Stack))
+	return nil
+}
+
+// getCurrentDesiredCapacity returns the current desired capacity of the nodegroup, and whether it is autoscaled,
+// i.e. its minimum and maximum sizes differ
+func (c *StackCollection) getCurrentDesiredCapacity(ctx context.Context, s *Stack, nodeGroupType api.NodeGroupType) (int, bool, error) {
+	if nodeGroupType == api.NodeGroupTypeManaged {
+		output, err := c.eksAPI.DescribeNodegroup(&eks.DescribeNodegroupInput{
+			ClusterName:   aws.String(c.spec.Metadata.Name),
+			NodegroupName: aws.String(c.GetNodeGroupName(s)),
+		})
+		if err != nil {
+			return 0, false, err
+		}
+		scalingConfig := output.Nodegroup.ScalingConfig
+		if scalingConfig == nil || scalingConfig.MinSize == nil || scalingConfig.MaxSize == nil || scalingConfig.DesiredSize == nil {
+			return 0, false, nil
+		}
+		return int(*scalingConfig.DesiredSize), *scalingConfig.MinSize != *scalingConfig.MaxSize, nil
+	}
+
+	asgName, err := c.GetUnmanagedNodeGroupAutoScalingGroupName(s)
+	if err != nil {
+		return 0, false, err
+	}
+	asg, err := c.GetAutoScalingGroupDesiredCapacity(ctx, asgName)
+	if err != nil {
+		return 0, false, err
+	}
+	if asg.MinSize == nil || asg.MaxSize == nil || asg.DesiredCapacity == nil {
+		return 0, false, nil
+	}
+	return int(*asg.DesiredCapacity), *asg.MinSize != *asg.MaxSize, nil
+}
+
 // DescribeNodeGroupStack gets the specified nodegroup stack
 func (c *StackCollection) DescribeNodeGroupStack(nodeGroupName string) (*Stack, error) {
 	stackName := c.makeNodeGroupStackName(nodeGroupName)
@@ -294,6 +451,27 @@ func GetEksctlVersionFromTags(tags []*cfn.Tag) (semver.Version, bool, error) {
 	return semver.Version{}, false, nil
 }
 
+// GetEksctlVersionTag returns the version of eksctl recorded in the tags of the stack, or an empty string if there is none
+func GetEksctlVersionTag(tags []*cfn.Tag) string {
+	for _, tag := range tags {
+		if *tag.Key == api.EksctlVersionTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
+// GetSSHUserTag returns the user to SSH onto the nodes as recorded in the tags of the nodegroup stack, or an empty
+// string if there is none
+func GetSSHUserTag(tags []*cfn.Tag) string {
+	for _, tag := range tags {
+		if *tag.Key == api.NodeGroupSSHUserTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
 // GetNodeGroupName will return nodegroup name based on tags
 func (*StackCollection) GetNodeGroupName(s *Stack) string {
 	if tagName := GetNodegroupTagName(s.Tags); tagName != "" {
@@ -318,3 +496,13 @@ func GetNodegroupTagName(tags []*cfn.Tag) string {
 	}
 	return ""
 }
+
+// GetNodeGroupAMIFamily returns the AMI family of an unmanaged nodegroup stack based on its tags
+func GetNodeGroupAMIFamily(tags []*cfn.Tag) string {
+	for _, tag := range tags {
+		if *tag.Key == api.NodeGroupAMIFamilyTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
@@ -6,8 +6,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 )
 
 var _ = Describe("StackCollection NodeGroup", func() {
@@ -106,4 +108,149 @@ var _ = Describe("StackCollection NodeGroup", func() {
 				api.NodeGroupType("")),
 		)
 	})
+
+	Describe("ListNodeGroupStacks", func() {
+		It("sets the type of each nodegroup from the tags of its stack, and legacy stacks as unmanaged", func() {
+			p := mockprovider.NewMockProvider()
+			cfg := api.NewClusterConfig()
+			cfg.Metadata.Name = "my-cluster"
+			stacks := []*cfn.Stack{
+				{
+					StackName:   aws.String("eksctl-my-cluster-nodegroup-mng-1"),
+					StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-nodegroup-mng-1/1"),
+					StackStatus: aws.String(cfn.StackStatusCreateComplete),
+					Tags: []*cfn.Tag{
+						{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("mng-1")},
+						{Key: aws.String(api.NodeGroupTypeTag), Value: aws.String(string(api.NodeGroupTypeManaged))},
+					},
+				},
+				{
+					StackName:   aws.String("eksctl-my-cluster-nodegroup-ng-1"),
+					StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-nodegroup-ng-1/2"),
+					StackStatus: aws.String(cfn.StackStatusCreateComplete),
+					Tags: []*cfn.Tag{
+						{Key: aws.String(api.NodeGroupNameTag), Value: aws.String("ng-1")},
+						{Key: aws.String(api.NodeGroupTypeTag), Value: aws.String(string(api.NodeGroupTypeUnmanaged))},
+					},
+				},
+				{
+					StackName:   aws.String("eksctl-my-cluster-nodegroup-0"),
+					StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-my-cluster-nodegroup-0/3"),
+					StackStatus: aws.String(cfn.StackStatusCreateComplete),
+				},
+			}
+			p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.AnythingOfType("func(*cloudformation.ListStacksOutput, bool) bool")).Run(func(args mock.Arguments) {
+				var summaries []*cfn.StackSummary
+				for _, s := range stacks {
+					summaries = append(summaries, &cfn.StackSummary{StackName: s.StackName, StackId: s.StackId})
+				}
+				args.Get(1).(func(*cfn.ListStacksOutput, bool) bool)(&cfn.ListStacksOutput{StackSummaries: summaries}, true)
+			}).Return(nil)
+			for _, s := range stacks {
+				p.MockCloudFormation().On("DescribeStacks", &cfn.DescribeStacksInput{StackName: s.StackId}).Return(&cfn.DescribeStacksOutput{
+					Stacks: []*cfn.Stack{s},
+				}, nil)
+			}
+
+			nodeGroupStacks, err := NewStackCollection(p, cfg).ListNodeGroupStacks()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodeGroupStacks).To(HaveLen(3))
+			Expect(nodeGroupStacks[0].NodeGroupName).To(Equal("mng-1"))
+			Expect(nodeGroupStacks[0].Type).To(Equal(api.NodeGroupTypeManaged))
+			Expect(nodeGroupStacks[1].NodeGroupName).To(Equal("ng-1"))
+			Expect(nodeGroupStacks[1].Type).To(Equal(api.NodeGroupTypeUnmanaged))
+			Expect(nodeGroupStacks[2].NodeGroupName).To(Equal("legacy-nodegroup-0"))
+			Expect(nodeGroupStacks[2].Type).To(Equal(api.NodeGroupTypeUnmanaged))
+		})
+	})
+
+	Describe("GetNodeGroupInstanceRoleARN", func() {
+		var (
+			p     *mockprovider.MockProvider
+			sm    *StackCollection
+			stack *Stack
+		)
+
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+			sm = NewStackCollection(p, api.NewClusterConfig()).(*StackCollection)
+			stack = &Stack{
+				StackName: aws.String("eksctl-test-nodegroup-ng-1"),
+				StackId:   aws.String("arn:aws-cn:cloudformation:cn-north-1:123456789012:stack/eksctl-test-nodegroup-ng-1/a1b2c3"),
+			}
+		})
+
+		mockResources := func(resources ...*cfn.StackResource) {
+			p.MockCloudFormation().On("DescribeStackResources", mock.MatchedBy(func(input *cfn.DescribeStackResourcesInput) bool {
+				return *input.StackName == "eksctl-test-nodegroup-ng-1"
+			})).Return(&cfn.DescribeStackResourcesOutput{StackResources: resources}, nil)
+		}
+
+		It("returns the ARN of the role resource of the stack", func() {
+			mockResources(
+				&cfn.StackResource{
+					ResourceType:       aws.String("AWS::IAM::InstanceProfile"),
+					PhysicalResourceId: aws.String("eksctl-test-nodegroup-ng-1-NodeInstanceProfile-ABC"),
+				},
+				&cfn.StackResource{
+					ResourceType:       aws.String("AWS::IAM::Role"),
+					PhysicalResourceId: aws.String("eksctl-test-nodegroup-ng-1-NodeInstanceRole-DEF"),
+				},
+			)
+			roleARN, err := sm.GetNodeGroupInstanceRoleARN(stack)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roleARN).To(Equal("arn:aws-cn:iam::123456789012:role/eksctl-test-nodegroup-ng-1-NodeInstanceRole-DEF"))
+		})
+
+		It("returns an error if the stack has no role resource", func() {
+			mockResources(&cfn.StackResource{
+				ResourceType:       aws.String("AWS::AutoScaling::AutoScalingGroup"),
+				PhysicalResourceId: aws.String("eksctl-test-nodegroup-ng-1-NodeGroup-GHI"),
+			})
+			_, err := sm.GetNodeGroupInstanceRoleARN(stack)
+			Expect(err).To(MatchError(`no instance role found in stack "eksctl-test-nodegroup-ng-1"`))
+		})
+	})
+
+	Describe("disabling rollback", func() {
+		var (
+			p  *mockprovider.MockProvider
+			sm *StackCollection
+		)
+
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+			sm = NewStackCollection(p, api.NewClusterConfig()).(*StackCollection)
+		})
+
+		DescribeTable("nodeGroupDisableRollback", func(providerDisableRollback bool, ngDisableRollback *bool, expected bool) {
+			sm.disableRollback = providerDisableRollback
+			ng := &api.NodeGroupBase{Name: "ng", DisableRollback: ngDisableRollback}
+			Expect(sm.nodeGroupDisableRollback(ng)).To(Equal(expected))
+		},
+			Entry("rolls back by default", false, nil, false),
+			Entry("rolls back if the nodegroup enables it", false, api.Disabled(), false),
+			Entry("does not roll back if the nodegroup disables it", false, api.Enabled(), true),
+			Entry("does not roll back if the provider disables it", true, nil, true),
+		)
+
+		It("sets DisableRollback in the CreateStack request", func() {
+			p.MockCloudFormation().On("CreateStack", mock.Anything).Return(&cfn.CreateStackOutput{
+				StackId: aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-test-nodegroup-ng/1"),
+			}, nil)
+			ng := &api.NodeGroupBase{Name: "ng", DisableRollback: api.Enabled()}
+			_, err := sm.createStackRequest("eksctl-test-nodegroup-ng", &stubResourceSet{}, nil, nil, sm.nodeGroupDisableRollback(ng))
+			Expect(err).NotTo(HaveOccurred())
+
+			input := p.MockCloudFormation().Calls[0].Arguments.Get(0).(*cfn.CreateStackInput)
+			Expect(*input.DisableRollback).To(BeTrue())
+		})
+	})
 })
+
+type stubResourceSet struct{}
+
+func (*stubResourceSet) RenderJSON() ([]byte, error)   { return []byte("{}"), nil }
+func (*stubResourceSet) WithIAM() bool                 { return false }
+func (*stubResourceSet) WithNamedIAM() bool            { return false }
+func (*stubResourceSet) GetAllOutputs(cfn.Stack) error { return nil }
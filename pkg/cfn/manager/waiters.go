@@ -11,6 +11,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/metrics"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 )
 
@@ -24,6 +25,7 @@ const (
 // custom acceptors
 
 func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.WaiterAcceptor) error {
+	defer metrics.TrackStack()()
 	msg := fmt.Sprintf("waiting for CloudFormation stack %q", *i.StackName)
 
 	newRequest := func() *request.Request {
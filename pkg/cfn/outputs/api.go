@@ -34,6 +34,9 @@ const (
 	NodeGroupInstanceRoleARN    = "InstanceRoleARN"
 	NodeGroupInstanceProfileARN = "InstanceProfileARN"
 
+	NodeGroupLaunchTemplateID      = "LaunchTemplateID"
+	NodeGroupLaunchTemplateVersion = "LaunchTemplateVersion"
+
 	// outputs to indicate configuration attributes that may have critical effect
 	// on critical effect on forward-compatibility with respect to overall functionality
 	// and integrity, e.g. networking
@@ -59,6 +62,15 @@ type (
 	CollectorSet struct{ set collectors }
 )
 
+// NewInstanceRoleARNCollector returns a Collector that sets the ARN of the nodegroup's instance role from the
+// InstanceRoleARN output, which is where the aws-auth ConfigMap reads it from
+func NewInstanceRoleARNCollector(ngIAM *api.NodeGroupIAM) Collector {
+	return func(v string) error {
+		ngIAM.InstanceRoleARN = v
+		return nil
+	}
+}
+
 // NewCollectorSet creates a new CollectorSet based on a map of
 // output names to Collector callbacks
 func NewCollectorSet(set map[string]Collector) *CollectorSet {
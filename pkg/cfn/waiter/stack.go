@@ -9,6 +9,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/metrics"
 )
 
 // NextDelay returns the amount of time to wait before the next retry given the number of attempts.
@@ -16,6 +18,7 @@ type NextDelay func(attempts int) time.Duration
 
 // WaitForStack waits for the cluster stack to reach a success or failure state, and returns the stack.
 func WaitForStack(ctx context.Context, cfnAPI cloudformationiface.CloudFormationAPI, stackID, stackName string, nextDelay NextDelay) (*cloudformation.Stack, error) {
+	defer metrics.TrackStack()()
 	var lastStack *cloudformation.Stack
 	waiter := &Waiter{
 		NextDelay: nextDelay,
@@ -3,6 +3,8 @@ package waiter
 import (
 	"context"
 	"time"
+
+	"github.com/weaveworks/eksctl/pkg/metrics"
 )
 
 // A Waiter keeps retrying the specified operation until it returns true, or an error.
@@ -16,6 +18,7 @@ type Waiter struct {
 
 // Wait waits for the specified operation to complete.
 func (w *Waiter) Wait(ctx context.Context) error {
+	defer metrics.TrackWait()()
 	for attempts := 1; ; attempts++ {
 		done, err := w.wait(ctx, w.NextDelay(attempts))
 		if err != nil {
@@ -24,6 +24,7 @@ type Cmd struct {
 	NameArg string
 
 	ClusterConfigFile string
+	ShowMergedConfig  bool
 
 	ProviderConfig api.ProviderConfig
 	ClusterConfig  *api.ClusterConfig
@@ -63,6 +64,10 @@ func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
 		}
 	}
 
+	if err := api.ValidateProviderConfig(&c.ProviderConfig); err != nil {
+		return nil, err
+	}
+
 	ctl, err := eks.New(context.TODO(), &c.ProviderConfig, c.ClusterConfig)
 	if err != nil {
 		return nil, err
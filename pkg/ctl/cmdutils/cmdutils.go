@@ -126,6 +126,11 @@ func AddTimeoutFlag(fs *pflag.FlagSet, p *time.Duration) {
 	AddTimeoutFlagWithValue(fs, p, api.DefaultWaitTimeout)
 }
 
+// AddSummaryFileFlag adds common --summary-file flag
+func AddSummaryFileFlag(fs *pflag.FlagSet, p *string) {
+	fs.StringVar(p, "summary-file", "", "write a JSON summary of the created resources to this file")
+}
+
 // AddClusterFlag adds a common --cluster flag for cluster name.
 // Use this for commands whose principal resource is *not* a cluster.
 func AddClusterFlag(fs *pflag.FlagSet, meta *api.ClusterMeta) {
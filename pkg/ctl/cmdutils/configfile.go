@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/weaveworks/eksctl/pkg/actions/irsa"
@@ -26,6 +27,11 @@ func AddConfigFileFlag(fs *pflag.FlagSet, path *string) {
 	fs.StringVarP(path, "config-file", "f", "", "load configuration from a file (or stdin if set to '-')")
 }
 
+// AddShowMergedConfigFlag adds common --show-merged-config flag
+func AddShowMergedConfigFlag(fs *pflag.FlagSet, showMergedConfig *bool) {
+	fs.BoolVar(showMergedConfig, "show-merged-config", false, "print the config file after merging nodeGroupDefaults and managedNodeGroupDefaults into the nodegroups, and exit")
+}
+
 // ClusterConfigLoader is an interface that loaders should implement
 type ClusterConfigLoader interface {
 	Load() error
@@ -53,6 +59,7 @@ var (
 		"include",
 		"exclude",
 		"only-missing",
+		"show-merged-config",
 	}
 
 	commonCreateFlagsIncompatibleWithDryRun = []string{
@@ -264,6 +271,19 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *filter.NodeGroupFilter, ng *api.
 			return errors.New("vpc.subnets and availabilityZones cannot be set at the same time")
 		}
 
+		if clusterConfig.HasAnySubnets() && len(clusterConfig.AvailabilityZoneIDs) != 0 {
+			return errors.New("vpc.subnets and availabilityZoneIDs cannot be set at the same time")
+		}
+
+		// the VPC CNI of a new cluster only uses IRSA if the cluster has an OIDC provider
+		if clusterConfig.IAM == nil || !api.IsEnabled(clusterConfig.IAM.WithOIDC) {
+			for _, ng := range clusterConfig.AllNodeGroups() {
+				if ng.IAM != nil && ng.IAM.AttachCNIPolicy == api.AttachCNIPolicyNever {
+					return fmt.Errorf("nodegroup %q has iam.attachCNIPolicy set to %s, which requires iam.withOIDC to be enabled", ng.Name, api.AttachCNIPolicyNever)
+				}
+			}
+		}
+
 		if clusterConfig.GitOps != nil {
 			fluxCfg := clusterConfig.GitOps.Flux
 
@@ -277,6 +297,8 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *filter.NodeGroupFilter, ng *api.
 			}
 		}
 
+		warnSharedInstanceNames(clusterConfig)
+
 		return validateDryRun()
 	}
 
@@ -390,6 +412,7 @@ func NewCreateNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *filter.Node
 		if err := ngFilter.AppendGlobs(l.Include, l.Exclude, l.ClusterConfig.GetAllNodeGroupNames()); err != nil {
 			return err
 		}
+		warnSharedInstanceNames(l.ClusterConfig)
 		return validateDryRun()
 	}
 
@@ -441,6 +464,32 @@ func NewCreateNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *filter.Node
 	return l
 }
 
+// warnSharedInstanceNames warns about nodegroups whose instances would get the same Name tag, as their nodes
+// cannot be told apart. Invalid instance names are left to the config validation.
+func warnSharedInstanceNames(clusterConfig *api.ClusterConfig) {
+	type nodeGroupPath struct{ path, name string }
+	instanceNames := map[string]nodeGroupPath{}
+	checkInstanceName := func(ng *api.NodeGroupBase, path string) {
+		instanceName, err := ng.InstanceNameTag(clusterConfig.Metadata)
+		if err != nil {
+			return
+		}
+		if other, ok := instanceNames[instanceName]; ok {
+			logger.Warning("nodegroups %q (%s) and %q (%s) share the instance name %q, so their nodes cannot be told apart; set a different instanceName, instancePrefix or instanceNameTemplate for one of them",
+				other.name, other.path, ng.Name, path, instanceName)
+			return
+		}
+		instanceNames[instanceName] = nodeGroupPath{path: path, name: ng.Name}
+	}
+
+	for i, ng := range clusterConfig.NodeGroups {
+		checkInstanceName(ng.NodeGroupBase, fmt.Sprintf("nodeGroups[%d]", i))
+	}
+	for i, ng := range clusterConfig.ManagedNodeGroups {
+		checkInstanceName(ng.NodeGroupBase, fmt.Sprintf("managedNodeGroups[%d]", i))
+	}
+}
+
 func makeManagedNodegroup(nodeGroup *api.NodeGroup, options CreateManagedNGOptions) *api.ManagedNodeGroup {
 	ngBase := *nodeGroup.NodeGroupBase
 	if ngBase.SecurityGroups != nil {
@@ -666,6 +715,33 @@ func NewUtilsPublicAccessCIDRsLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewUtilsUpdateSubnetTagsLoader loads config or uses flags for `eksctl utils update-subnet-tags`
+func NewUtilsUpdateSubnetTagsLoader(cmd *Cmd, subnetTags map[string]string) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert("subnet-tags")
+
+	l.validateWithConfigFile = func() error {
+		vpc := l.ClusterConfig.VPC
+		if vpc == nil || len(vpc.SubnetTags)+len(vpc.PublicSubnetTags)+len(vpc.PrivateSubnetTags) == 0 {
+			return errors.New("at least one of vpc.subnetTags, vpc.publicSubnetTags or vpc.privateSubnetTags is required")
+		}
+		return api.ValidateSubnetTags(vpc)
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if err := l.validateMetadataWithoutConfigFile(); err != nil {
+			return err
+		}
+		if len(subnetTags) == 0 {
+			return ErrMustBeSet("--subnet-tags")
+		}
+		l.ClusterConfig.VPC.SubnetTags = subnetTags
+		return api.ValidateSubnetTags(l.ClusterConfig.VPC)
+	}
+	return l
+}
+
 func parseList(arg string) ([]string, error) {
 	reader := strings.NewReader(arg)
 	csvReader := csv.NewReader(reader)
@@ -759,9 +835,14 @@ func NewGetIAMServiceAccountLoader(cmd *Cmd, options *irsa.GetOptions) ClusterCo
 }
 
 // NewDeleteIAMServiceAccountLoader will load config or use flags for 'eksctl delete iamserviceaccount'
-func NewDeleteIAMServiceAccountLoader(cmd *Cmd, sa *api.ClusterIAMServiceAccount, saFilter *filter.IAMServiceAccountFilter) ClusterConfigLoader {
+func NewDeleteIAMServiceAccountLoader(cmd *Cmd, sa *api.ClusterIAMServiceAccount, saFilter *filter.IAMServiceAccountFilter, options *irsa.DeleteOptions) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
 
+	l.flagsIncompatibleWithConfigFile.Insert(
+		"all",
+		"selector",
+	)
+
 	l.validateWithConfigFile = func() error {
 		if l.ClusterConfig.IAM == nil || api.IsDisabled(l.ClusterConfig.IAM.WithOIDC) {
 			return fmt.Errorf("'iam.withOIDC' is not enabled in %q", l.ClusterConfigFile)
@@ -769,9 +850,12 @@ func NewDeleteIAMServiceAccountLoader(cmd *Cmd, sa *api.ClusterIAMServiceAccount
 		return saFilter.AppendGlobs(l.Include, l.Exclude, l.ClusterConfig.IAM.ServiceAccounts)
 	}
 
-	l.flagsIncompatibleWithoutConfigFile.Insert(
-		"approve",
-	)
+	if !options.IsBulk() {
+		// deleting iamserviceaccounts in bulk prints the plan unless --approve is set, as many of them may be deleted
+		l.flagsIncompatibleWithoutConfigFile.Insert(
+			"approve",
+		)
+	}
 
 	l.validateWithoutConfigFile = func() error {
 		sa.AttachPolicyARNs = []string{""} // force to pass general validation
@@ -788,6 +872,10 @@ func NewDeleteIAMServiceAccountLoader(cmd *Cmd, sa *api.ClusterIAMServiceAccount
 			sa.Name = l.NameArg
 		}
 
+		if options.IsBulk() {
+			return validateBulkDeleteIAMServiceAccount(l, sa, options)
+		}
+
 		if sa.Name == "" {
 			return ErrMustBeSet("--name")
 		}
@@ -800,6 +888,29 @@ func NewDeleteIAMServiceAccountLoader(cmd *Cmd, sa *api.ClusterIAMServiceAccount
 	return l
 }
 
+func validateBulkDeleteIAMServiceAccount(l *commonClusterConfigLoader, sa *api.ClusterIAMServiceAccount, options *irsa.DeleteOptions) error {
+	if sa.Name != "" {
+		return fmt.Errorf("--name cannot be used with --all or --selector")
+	}
+
+	namespaceSet := l.CobraCommand.Flag("namespace").Changed
+	if options.All && !namespaceSet {
+		return fmt.Errorf("--namespace must be set when using --all")
+	}
+	if options.Selector != "" {
+		if _, err := labels.Parse(options.Selector); err != nil {
+			return errors.Wrapf(err, "invalid --selector %q", options.Selector)
+		}
+	}
+	if namespaceSet {
+		options.Namespace = sa.Namespace
+	}
+
+	// the iamserviceaccounts to delete are only known once the cluster is queried
+	l.ClusterConfig.IAM.ServiceAccounts = nil
+	return nil
+}
+
 // NewUpdateNodegroupLoader will load config or use flags for 'eksctl update nodegroup'.
 func NewUpdateNodegroupLoader(cmd *Cmd) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
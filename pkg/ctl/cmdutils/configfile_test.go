@@ -1,8 +1,11 @@
 package cmdutils
 
 import (
+	"bytes"
+	"io"
 	"path/filepath"
 
+	"github.com/kris-nova/logger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/cobra"
@@ -262,6 +265,71 @@ var _ = Describe("cmdutils configfile", func() {
 			})
 		})
 
+		It("rejects nodegroups that never attach the CNI policy when withOIDC is disabled", func() {
+			cmd := &Cmd{
+				CobraCommand:      newCmd(),
+				ClusterConfigFile: filepath.Join("test_data", "attach-cni-policy-never-without-oidc.yaml"),
+				ClusterConfig:     api.NewClusterConfig(),
+				ProviderConfig:    api.ProviderConfig{},
+			}
+			params := &CreateClusterCmdParams{CreateManagedNGOptions: CreateManagedNGOptions{
+				Managed: true,
+			}}
+			err := NewCreateClusterLoader(cmd, filter.NewNodeGroupFilter(), nil, params).Load()
+			Expect(err).To(MatchError(`nodegroup "mng-1" has iam.attachCNIPolicy set to never, which requires iam.withOIDC to be enabled`))
+		})
+
+		Context("nodegroups sharing an instance name", func() {
+			var (
+				output       *bytes.Buffer
+				loggerWriter io.Writer
+			)
+
+			BeforeEach(func() {
+				output = &bytes.Buffer{}
+				loggerWriter = logger.Writer
+				logger.Writer = output
+			})
+
+			AfterEach(func() {
+				logger.Writer = loggerWriter
+			})
+
+			It("warns about nodegroups generating the same instance name", func() {
+				cmd := &Cmd{
+					CobraCommand:      newCmd(),
+					ClusterConfigFile: filepath.Join("test_data", "nodegroups-sharing-instance-name.yaml"),
+					ClusterConfig:     api.NewClusterConfig(),
+					ProviderConfig:    api.ProviderConfig{},
+				}
+				Expect(NewCreateClusterLoader(cmd, filter.NewNodeGroupFilter(), nil, &CreateClusterCmdParams{}).Load()).To(Succeed())
+				Expect(output.String()).To(ContainSubstring(`nodegroups "ng-1" (nodeGroups[0]) and "mng-1" (managedNodeGroups[0]) share the instance name "team-worker"`))
+				Expect(output.String()).NotTo(ContainSubstring(`"mng-2"`))
+			})
+
+			It("warns when creating the nodegroups of the config file", func() {
+				cmd := &Cmd{
+					CobraCommand:      newCmd(),
+					ClusterConfigFile: filepath.Join("test_data", "nodegroups-sharing-instance-name.yaml"),
+					ClusterConfig:     api.NewClusterConfig(),
+					ProviderConfig:    api.ProviderConfig{},
+				}
+				Expect(NewCreateNodeGroupLoader(cmd, nil, filter.NewNodeGroupFilter(), CreateNGOptions{}, CreateManagedNGOptions{}).Load()).To(Succeed())
+				Expect(output.String()).To(ContainSubstring(`nodegroups "ng-1" (nodeGroups[0]) and "mng-1" (managedNodeGroups[0]) share the instance name "team-worker"`))
+			})
+
+			It("does not warn about nodegroups with the default instance names", func() {
+				cmd := &Cmd{
+					CobraCommand:      newCmd(),
+					ClusterConfigFile: filepath.Join("test_data", "cluster-with-labels.yaml"),
+					ClusterConfig:     api.NewClusterConfig(),
+					ProviderConfig:    api.ProviderConfig{},
+				}
+				Expect(NewCreateClusterLoader(cmd, filter.NewNodeGroupFilter(), nil, &CreateClusterCmdParams{}).Load()).To(Succeed())
+				Expect(output.String()).NotTo(ContainSubstring("share the instance name"))
+			})
+		})
+
 		When("using ipv6", func() {
 			It("should default VPC.NAT to nil", func() {
 				cmd := &Cmd{
@@ -1,6 +1,8 @@
 package cmdutils
 
 import (
+	"time"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 )
 
@@ -19,6 +21,8 @@ type CreateClusterCmdParams struct {
 	WithoutNodeGroup      bool
 	Fargate               bool
 	DryRun                bool
+	IfExists              string
+	SummaryFile           string
 	CreateNGOptions
 	CreateManagedNGOptions
 }
@@ -35,4 +39,7 @@ type CreateNGOptions struct {
 	InstallNeuronDevicePlugin bool
 	InstallNvidiaDevicePlugin bool
 	DryRun                    bool
+	SkipUserDataValidation    bool
+	StrictDeprecations        bool
+	AMIMaxAge                 time.Duration
 }
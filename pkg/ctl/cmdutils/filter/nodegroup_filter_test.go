@@ -475,7 +475,8 @@ const expected = `
 			  },
 			  "ssh": {
 			    "allow": true,
-			    "publicKeyPath": "~/.ssh/id_rsa.pub"
+			    "publicKeyPath": "~/.ssh/id_rsa.pub",
+			    "user": "ec2-user"
 			  },
 			  "iam": {
 				"withAddonPolicies": {
@@ -521,7 +522,8 @@ const expected = `
 			  },
 			  "ssh": {
 			    "allow": true,
-			    "publicKeyPath": "~/.ssh/id_rsa.pub"
+			    "publicKeyPath": "~/.ssh/id_rsa.pub",
+			    "user": "ec2-user"
               },
 			  "iam": {
 			    "withAddonPolicies": {
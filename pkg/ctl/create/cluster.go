@@ -18,6 +18,7 @@ import (
 	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
 
 	"github.com/weaveworks/eksctl/pkg/actions/addon"
+	clusteractions "github.com/weaveworks/eksctl/pkg/actions/cluster"
 	"github.com/weaveworks/eksctl/pkg/actions/flux"
 	karpenteractions "github.com/weaveworks/eksctl/pkg/actions/karpenter"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -28,7 +29,9 @@ import (
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/kops"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/summary"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 	"github.com/weaveworks/eksctl/pkg/utils/kubectl"
 	"github.com/weaveworks/eksctl/pkg/utils/names"
@@ -65,6 +68,9 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 		if err := cmdutils.NewCreateClusterLoader(cmd, ngFilter, ng, params).Load(); err != nil {
 			return err
 		}
+		if cmd.ShowMergedConfig {
+			return cmdutils.PrintDryRunConfig(cmd.ClusterConfig, os.Stdout)
+		}
 		return runFunc(cmd, ngFilter, params)
 	}
 
@@ -79,10 +85,13 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 		fs.StringSliceVar(&params.AvailabilityZones, "zones", nil, "(auto-select if unspecified)")
 		cmdutils.AddVersionFlag(fs, cfg.Metadata, "")
 		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddShowMergedConfigFlag(fs, &cmd.ShowMergedConfig)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 		fs.BoolVarP(&params.InstallWindowsVPCController, "install-vpc-controllers", "", false, "Install VPC controller that's required for Windows workloads")
 		fs.BoolVarP(&params.Fargate, "fargate", "", false, "Create a Fargate profile scheduling pods in the default and kube-system namespaces onto Fargate")
 		fs.BoolVarP(&params.DryRun, "dry-run", "", false, "Dry-run mode that skips cluster creation and outputs a ClusterConfig")
+		cmdutils.AddSummaryFileFlag(fs, &params.SummaryFile)
+		fs.StringVar(&params.IfExists, "if-exists", clusteractions.IfExistsFail, fmt.Sprintf("what to do when the cluster was already created by eksctl, valid options: %s", strings.Join(clusteractions.IfExistsValues(), ", ")))
 
 		_ = fs.MarkDeprecated("install-vpc-controllers", vpcControllerInfoMessage)
 	})
@@ -90,6 +99,9 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 	cmd.FlagSetGroup.InFlagSet("Initial nodegroup", func(fs *pflag.FlagSet) {
 		fs.StringVar(&ng.Name, "nodegroup-name", "", fmt.Sprintf("name of the nodegroup (generated if unspecified, e.g. %q)", exampleNodeGroupName))
 		fs.BoolVar(&params.WithoutNodeGroup, "without-nodegroup", false, "if set, initial nodegroup will not be created")
+		fs.BoolVar(&params.SkipUserDataValidation, "skip-userdata-validation", false, "whether the creation of nodegroups should proceed when the syntax of their bootstrap commands or the size of their user data is invalid")
+		fs.BoolVar(&params.StrictDeprecations, "strict-deprecations", false, "whether to reject nodegroups using an AMI family that is deprecated for the EKS version, instead of warning about them")
+		fs.DurationVar(&params.AMIMaxAge, "ami-max-age", 0, "reject resolved AMIs that are deprecated or older than this (e.g. 2160h), instead of warning about deprecated AMIs; explicitly specified AMIs are not checked")
 		cmdutils.AddCommonCreateNodeGroupFlags(fs, cmd, ng, &params.CreateManagedNGOptions)
 	})
 
@@ -117,6 +129,15 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 	})
 }
 
+func isValidIfExists(value string) bool {
+	for _, v := range clusteractions.IfExistsValues() {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
 func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params *cmdutils.CreateClusterCmdParams) error {
 	cfg := cmd.ClusterConfig
 	meta := cmd.ClusterConfig.Metadata
@@ -125,6 +146,15 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		return api.ErrInvalidName(meta.Name)
 	}
 
+	if !isValidIfExists(params.IfExists) {
+		return fmt.Errorf("invalid value %q for --if-exists, valid options: %s", params.IfExists, strings.Join(clusteractions.IfExistsValues(), ", "))
+	}
+	// the hash identifies the config as loaded, before eksctl fills in the resources it creates
+	configHash, err := clusteractions.ConfigHash(cfg)
+	if err != nil {
+		return err
+	}
+
 	printer := printers.NewJSONPrinter()
 
 	if params.DryRun {
@@ -140,6 +170,14 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		return err
 	}
 
+	ctx := context.TODO()
+
+	if params.IfExists != clusteractions.IfExistsFail && !params.DryRun {
+		if exists, err := handleExistingCluster(ctx, cmd, ctl, ngFilter, params, configHash); exists || err != nil {
+			return err
+		}
+	}
+
 	if cfg.Metadata.Version == "" || cfg.Metadata.Version == "auto" {
 		cfg.Metadata.Version = api.DefaultVersion
 	}
@@ -169,7 +207,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 	}
 
 	// if using a custom shared node security group, warn that the rules are managed by default
-	if cfg.VPC.SharedNodeSecurityGroup != "" && api.IsEnabled(cfg.VPC.ManageSharedNodeSecurityGroupRules) {
+	if cfg.VPC.SharedNodeSecurityGroup != "" && !cfg.IsSharedNodeSecurityGroupDisabled() && api.IsEnabled(cfg.VPC.ManageSharedNodeSecurityGroupRules) {
 		logger.Warning("security group rules may be added by eksctl; see vpc.manageSharedNodeSecurityGroupRules to disable this behavior")
 	}
 
@@ -180,8 +218,6 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		params.KubeconfigPath = kubeconfig.AutoPath(meta.Name)
 	}
 
-	ctx := context.TODO()
-
 	if checkSubnetsGivenAsFlags(params) {
 		// undo defaulting and reset it, as it's not set via config file;
 		// default value here causes errors as vpc.ImportVPC doesn't
@@ -225,11 +261,31 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 		return err
 	}
 
+	if err := api.ValidateAMIFamilyDeprecations(cfg, params.StrictDeprecations); err != nil {
+		return err
+	}
+
+	if !params.SkipUserDataValidation {
+		for _, np := range nodePools {
+			if err := nodebootstrap.ValidateUserData(cfg, np); err != nil {
+				return fmt.Errorf("%w; to ignore this check and proceed with the cluster creation, please run again with --skip-userdata-validation=true", err)
+			}
+		}
+	}
+
 	if params.DryRun {
 		return cmdutils.PrintDryRunConfig(cfg, os.Stdout)
 	}
 
-	if err := nodeGroupService.Normalize(ctx, nodePools, cfg.Metadata); err != nil {
+	if err := nodeGroupService.Normalize(ctx, nodePools, cfg.Metadata, params.AMIMaxAge); err != nil {
+		return err
+	}
+
+	if err := nodeGroupService.CheckMaxPods(ctx, cfg); err != nil {
+		return err
+	}
+
+	if err := nodeGroupService.EnsureServiceLinkedRoles(ctx, cfg); err != nil {
 		return err
 	}
 
@@ -245,6 +301,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 	}
 
 	stackManager := ctl.NewStackManager(cfg)
+	stackManager.SetClusterConfigHash(configHash)
 	if cmd.ClusterConfigFile == "" {
 		logMsg := func(resource string) {
 			logger.Info("will create 2 separate CloudFormation stacks for cluster itself and the initial %s", resource)
@@ -382,7 +439,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 			}
 
 			//TODO why was it returning early before? I want to remove this line :thinking:
-			return nil
+			return printClusterSummary(ctl, cfg, stackManager, params)
 		}
 
 		env, err := ctl.GetCredentialsEnv()
@@ -407,7 +464,21 @@ func doCreateCluster(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params
 
 	logger.Success("%s is ready", meta.LogString())
 
-	return printer.LogObj(logger.Debug, "cfg.json = \\\n%s\n", cfg)
+	if err := printer.LogObj(logger.Debug, "cfg.json = \\\n%s\n", cfg); err != nil {
+		return err
+	}
+	return printClusterSummary(ctl, cfg, stackManager, params)
+}
+
+// printClusterSummary prints the summary of the cluster and of the nodegroups and iamserviceaccounts created with it
+func printClusterSummary(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, stackManager manager.StackManager, params *cmdutils.CreateClusterCmdParams) error {
+	return printSummary(ctl, cfg, params.SummaryFile, func(s *summary.Summary) error {
+		s.KubeconfigPath = params.KubeconfigPath
+		if err := s.AddNodeGroups(cfg, stackManager); err != nil {
+			return err
+		}
+		return s.AddServiceAccounts(cfg.IAM.ServiceAccounts, stackManager)
+	})
 }
 
 // installKarpenter prepares the environment for Karpenter, by creating the following resources:
@@ -444,7 +515,7 @@ func createOrImportVPC(ctx context.Context, cmd *cmdutils.Cmd, cfg *api.ClusterC
 			return nil
 		}
 
-		return vpc.SetSubnets(cfg.VPC, cfg.AvailabilityZones)
+		return vpc.SetSubnets(cfg.VPC, cfg.AllZones())
 	}
 
 	if params.KopsClusterNameForVPC != "" {
@@ -0,0 +1,119 @@
+package create
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/actions/addon"
+	clusteractions "github.com/weaveworks/eksctl/pkg/actions/cluster"
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+// handleExistingCluster applies --if-exists to a cluster that eksctl has already created, and returns true if the
+// cluster exists, in which case there is nothing left to create
+func handleExistingCluster(ctx context.Context, cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, ngFilter *filter.NodeGroupFilter, params *cmdutils.CreateClusterCmdParams, configHash string) (bool, error) {
+	cfg := cmd.ClusterConfig
+	state, err := clusteractions.CheckExistingCluster(ctl.NewStackManager(cfg), configHash)
+	if err != nil {
+		return false, err
+	}
+
+	switch state {
+	case clusteractions.ClusterStackNotFound:
+		return false, nil
+	case clusteractions.ClusterMatchesConfig:
+		if params.IfExists == clusteractions.IfExistsSkip {
+			logger.Success("cluster %q already exists and was created from the same config, skipping its creation", cfg.Metadata.Name)
+			return true, nil
+		}
+		logger.Info("cluster %q already exists and was created from the same config, reconciling its nodegroups and addons", cfg.Metadata.Name)
+	case clusteractions.ClusterDivergesFromConfig:
+		if params.IfExists == clusteractions.IfExistsSkip {
+			return true, fmt.Errorf("cluster %q already exists but was not created from this config; to create the nodegroups and addons it is missing, please run again with --if-exists=%s", cfg.Metadata.Name, clusteractions.IfExistsReconcile)
+		}
+		logger.Warning("cluster %q already exists but was not created from this config; only its nodegroups and addons will be reconciled", cfg.Metadata.Name)
+	}
+
+	return true, reconcileCluster(ctx, cmd, ctl, ngFilter, params)
+}
+
+// reconcileCluster creates the nodegroups of the config that do not exist yet, then creates or updates its addons
+func reconcileCluster(ctx context.Context, cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, ngFilter *filter.NodeGroupFilter, params *cmdutils.CreateClusterCmdParams) error {
+	cfg := cmd.ClusterConfig
+	if err := ctl.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := nodegroup.New(cfg, ctl, clientSet).Create(ctx, nodegroup.CreateOpts{
+		InstallNeuronDevicePlugin: params.InstallNeuronDevicePlugin,
+		InstallNvidiaDevicePlugin: params.InstallNvidiaDevicePlugin,
+		UpdateAuthConfigMap:       true,
+		SkipUserDataValidation:    params.SkipUserDataValidation,
+		StrictDeprecations:        params.StrictDeprecations,
+		ConfigFileProvided:        cmd.ClusterConfigFile != "",
+		AMIMaxAge:                 params.AMIMaxAge,
+	}, ngFilter); err != nil {
+		return err
+	}
+
+	if len(cfg.Addons) > 0 {
+		if err := reconcileAddons(ctx, cmd, ctl, clientSet); err != nil {
+			return err
+		}
+	}
+	logger.Success("reconciled the nodegroups and addons of cluster %q", cfg.Metadata.Name)
+	return nil
+}
+
+// reconcileAddons creates the addons of the config that do not exist, and updates the others
+func reconcileAddons(ctx context.Context, cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, clientSet kubernetes.Interface) error {
+	cfg := cmd.ClusterConfig
+	oidc, err := ctl.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		return err
+	}
+	oidcProviderExists, err := oidc.CheckProviderExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the addon versions are resolved for the version of the control plane
+	cfg.Metadata.Version = ctl.ControlPlaneVersion()
+	addonManager, err := addon.New(cfg, ctl.Provider.EKS(), ctl.NewStackManager(cfg), oidcProviderExists, oidc, clientSet, cmd.ProviderConfig.WaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	existing, err := addonManager.GetAll()
+	if err != nil {
+		return err
+	}
+	existingNames := make(map[string]bool, len(existing))
+	for _, summary := range existing {
+		existingNames[summary.Name] = true
+	}
+
+	for _, a := range cfg.Addons {
+		if existingNames[a.Name] {
+			logger.Info("updating addon %q", a.Name)
+			err = addonManager.Update(a, true)
+		} else {
+			logger.Info("creating addon %q", a.Name)
+			err = addonManager.Create(a, true)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package create
 
 import (
+	"os"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -26,6 +28,35 @@ var _ = Describe("create cluster", func() {
 			Expect(err).To(Not(HaveOccurred()))
 			Expect(count).To(Equal(1))
 		})
+		It("exits after printing the merged config", func() {
+			f, err := os.CreateTemp("", "configfile")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(f.Name())
+			_, err = f.WriteString(`
+apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  region: us-west-2
+nodeGroupDefaults:
+  instanceType: m5.xlarge
+nodeGroups:
+  - name: ng-1
+`)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmd := newMockEmptyCmd("cluster", "--config-file", f.Name(), "--show-merged-config")
+			count := 0
+			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
+				createClusterCmdWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ngFilter *filter.NodeGroupFilter, params *cmdutils.CreateClusterCmdParams) error {
+					count++
+					return nil
+				})
+			})
+			_, err = cmd.execute()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
 		DescribeTable("create cluster successfully",
 			func(args ...string) {
 				commandArgs := append([]string{"cluster"}, args...)
@@ -82,6 +113,7 @@ var _ = Describe("create cluster", func() {
 			Entry("with appmesh-access flag", "--appmesh-access", "true"),
 			Entry("with alb-ingress-access flag", "--alb-ingress-access", "true"),
 			Entry("with managed flag unset", "--managed", "false"),
+			Entry("with if-exists flag", "--if-exists", "skip"),
 		)
 
 		DescribeTable("invalid flags or arguments",
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils/filter"
+	"github.com/weaveworks/eksctl/pkg/summary"
 	"github.com/weaveworks/eksctl/pkg/utils/names"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -23,7 +25,10 @@ type nodegroupOptions struct {
 	cmdutils.CreateManagedNGOptions
 	UpdateAuthConfigMap     bool
 	SkipOutdatedAddonsCheck bool
+	SkipVersionSkewCheck    bool
+	WaitForInstanceProfile  bool
 	SubnetIDs               []string
+	SummaryFile             string
 }
 
 func createNodeGroupCmd(cmd *cmdutils.Cmd) {
@@ -41,6 +46,10 @@ func createNodeGroupCmd(cmd *cmdutils.Cmd) {
 			return errors.Wrap(err, "couldn't create node group filter from command line options")
 		}
 
+		if cmd.ShowMergedConfig {
+			return cmdutils.PrintDryRunConfig(cmd.ClusterConfig, os.Stdout)
+		}
+
 		if options.DryRun {
 			originalWriter := logger.Writer
 			logger.Writer = io.Discard
@@ -64,14 +73,29 @@ func createNodeGroupCmd(cmd *cmdutils.Cmd) {
 		}
 
 		manager := nodegroup.New(cmd.ClusterConfig, ctl, clientSet)
-		return manager.Create(context.TODO(), nodegroup.CreateOpts{
+		if err := manager.Create(context.TODO(), nodegroup.CreateOpts{
 			InstallNeuronDevicePlugin: options.InstallNeuronDevicePlugin,
 			InstallNvidiaDevicePlugin: options.InstallNvidiaDevicePlugin,
 			UpdateAuthConfigMap:       options.UpdateAuthConfigMap,
 			DryRun:                    options.DryRun,
 			SkipOutdatedAddonsCheck:   options.SkipOutdatedAddonsCheck,
+			SkipVersionSkewCheck:      options.SkipVersionSkewCheck,
+			SkipUserDataValidation:    options.SkipUserDataValidation,
+			StrictDeprecations:        options.StrictDeprecations,
 			ConfigFileProvided:        cmd.ClusterConfigFile != "",
-		}, ngFilter)
+			WaitForInstanceProfile:    options.WaitForInstanceProfile,
+			AMIMaxAge:                 options.AMIMaxAge,
+		}, ngFilter); err != nil {
+			return err
+		}
+		if options.DryRun {
+			return nil
+		}
+		// the nodegroups of the config have been filtered down to the ones that were created
+		stackManager := ctl.NewStackManager(cmd.ClusterConfig)
+		return printSummary(ctl, cmd.ClusterConfig, options.SummaryFile, func(s *summary.Summary) error {
+			return s.AddNodeGroups(cmd.ClusterConfig, stackManager)
+		})
 	})
 }
 
@@ -99,12 +123,19 @@ func createNodeGroupCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc runFn) {
 		cmdutils.AddRegionFlag(fs, &cmd.ProviderConfig)
 		cmdutils.AddVersionFlag(fs, cfg.Metadata, `for nodegroups "auto" and "latest" can be used to automatically inherit version from the control plane or force latest`)
 		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddShowMergedConfigFlag(fs, &cmd.ShowMergedConfig)
 		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 		cmdutils.AddUpdateAuthConfigMap(fs, &options.UpdateAuthConfigMap, "Add nodegroup IAM role to aws-auth configmap")
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 		cmdutils.AddSubnetIDs(fs, &options.SubnetIDs, "Define an optional list of subnet IDs to create the nodegroup in")
 		fs.BoolVarP(&options.DryRun, "dry-run", "", false, "Dry-run mode that skips nodegroup creation and outputs a ClusterConfig")
 		fs.BoolVarP(&options.SkipOutdatedAddonsCheck, "skip-outdated-addons-check", "", false, "whether the creation of ARM nodegroups should proceed when the cluster addons are outdated")
+		fs.BoolVarP(&options.SkipVersionSkewCheck, "skip-version-skew-check", "", false, "whether the creation of nodegroups should proceed when their version is not supported by the control plane version")
+		fs.BoolVarP(&options.SkipUserDataValidation, "skip-userdata-validation", "", false, "whether the creation of nodegroups should proceed when the syntax of their bootstrap commands or the size of their user data is invalid")
+		fs.BoolVarP(&options.StrictDeprecations, "strict-deprecations", "", false, "whether to reject nodegroups using an AMI family that is deprecated for the EKS version, instead of warning about them")
+		fs.DurationVar(&options.AMIMaxAge, "ami-max-age", 0, "reject resolved AMIs that are deprecated or older than this (e.g. 2160h), instead of warning about deprecated AMIs; explicitly specified AMIs are not checked")
+		cmdutils.AddSummaryFileFlag(fs, &options.SummaryFile)
+		fs.BoolVarP(&options.WaitForInstanceProfile, "wait-for-instance-profile", "", false, "whether to wait, for up to 2 minutes, for the instance profiles set by iam.instanceProfileARN to be retrievable via IAM before creating the nodegroups")
 	})
 
 	cmd.FlagSetGroup.InFlagSet("New nodegroup", func(fs *pflag.FlagSet) {
@@ -0,0 +1,49 @@
+package create
+
+import (
+	"os"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/summary"
+)
+
+// printSummary prints the summary of the resources created in the cluster of cfg, which addResources completes with
+// the created nodegroups and iamserviceaccounts, and writes it as JSON to summaryFile if set. The resources have
+// already been created at this point, so failing to gather the summary is only a warning when no file was requested
+func printSummary(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, summaryFile string, addResources func(s *summary.Summary) error) error {
+	s, err := gatherSummary(ctl, cfg, addResources)
+	if err != nil {
+		if summaryFile == "" {
+			logger.Warning("unable to print the summary of the created resources: %v", err)
+			return nil
+		}
+		return errors.Wrap(err, "gathering summary of the created resources")
+	}
+
+	if err := s.Render(os.Stdout); err != nil {
+		return err
+	}
+	if summaryFile != "" {
+		if err := s.WriteFile(summaryFile); err != nil {
+			return err
+		}
+		logger.Success("saved summary as %q", summaryFile)
+	}
+	return nil
+}
+
+func gatherSummary(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, addResources func(s *summary.Summary) error) (*summary.Summary, error) {
+	cluster, err := ctl.DescribeControlPlane(cfg.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	s := summary.ForCluster(cfg, cluster)
+	if err := addResources(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
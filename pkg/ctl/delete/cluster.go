@@ -16,25 +16,21 @@ import (
 )
 
 func deleteClusterCmd(cmd *cmdutils.Cmd) {
-	deleteClusterWithRunFunc(cmd, func(cmd *cmdutils.Cmd, force bool, disableNodegroupEviction bool, parallel int) error {
-		return doDeleteCluster(cmd, force, disableNodegroupEviction, parallel)
+	deleteClusterWithRunFunc(cmd, func(cmd *cmdutils.Cmd, options cluster.DeleteOptions) error {
+		return doDeleteCluster(cmd, options)
 	})
 }
 
-func deleteClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, force bool, disableNodegroupEviction bool, parallel int) error) {
+func deleteClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options cluster.DeleteOptions) error) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
 	cmd.SetDescription("cluster", "Delete a cluster", "")
 
-	var (
-		force                    bool
-		disableNodegroupEviction bool
-		parallel                 int
-	)
+	var options cluster.DeleteOptions
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return runFunc(cmd, force, disableNodegroupEviction, parallel)
+		return runFunc(cmd, options)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -43,9 +39,12 @@ func deleteClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd,
 
 		cmd.Wait = false
 		cmdutils.AddWaitFlag(fs, &cmd.Wait, "deletion of all resources")
-		fs.BoolVar(&force, "force", false, "Force deletion to continue when errors occur")
-		fs.BoolVar(&disableNodegroupEviction, "disable-nodegroup-eviction", false, "Force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
-		fs.IntVar(&parallel, "parallel", 1, "Number of nodes to drain in parallel. Max 25")
+		fs.BoolVar(&options.Force, "force", false, "Force deletion to continue when errors occur")
+		fs.BoolVar(&options.DisableNodegroupEviction, "disable-nodegroup-eviction", false, "Force drain to use delete, even if eviction is supported. This will bypass checking PodDisruptionBudgets, use with caution.")
+		fs.IntVar(&options.Parallel, "parallel", 1, "Number of nodes to drain in parallel. Max 25")
+		fs.BoolVar(&options.ContinueAfterDeprecatedStacks, "continue-after-deprecated-stacks", false, "Continue deleting the remaining shared resources (SSH keys, kubeconfig, load balancers) after deleting deprecated stacks")
+		fs.BoolVar(&options.SkipOrphanedNetworkCleanup, "skip-orphaned-network-cleanup", false, "Skip deleting the detached network interfaces and orphaned security groups tagged with the cluster name when retrying the deletion of nodegroup stacks")
+		fs.BoolVar(&options.CheckNodeReachability, "check-node-reachability", false, "Warn about the nodes whose security groups do not allow the control plane to reach their kubelet before draining them")
 
 		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
@@ -54,7 +53,7 @@ func deleteClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd,
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, true)
 }
 
-func doDeleteCluster(cmd *cmdutils.Cmd, force bool, disableNodegroupEviction bool, parallel int) error {
+func doDeleteCluster(cmd *cmdutils.Cmd, options cluster.DeleteOptions) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -64,7 +63,7 @@ func doDeleteCluster(cmd *cmdutils.Cmd, force bool, disableNodegroupEviction boo
 	printer := printers.NewJSONPrinter()
 	ctl, err := cmd.NewProviderForExistingCluster()
 	if err != nil {
-		if !force {
+		if !options.Force {
 			return err
 		}
 		// initialise the controller without refreshing the cluster status.
@@ -86,7 +85,9 @@ func doDeleteCluster(cmd *cmdutils.Cmd, force bool, disableNodegroupEviction boo
 		return err
 	}
 
+	options.WaitInterval = time.Second * 20
+	options.Wait = cmd.Wait
 	// ProviderConfig.WaitTimeout is not respected by cluster.Delete, which means the operation will never time out.
 	// When this is fixed, a deadline-based Context can be used here.
-	return cluster.Delete(context.TODO(), time.Second*20, cmd.Wait, force, disableNodegroupEviction, parallel)
+	return cluster.Delete(context.TODO(), options)
 }
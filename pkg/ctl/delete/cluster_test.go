@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"github.com/weaveworks/eksctl/pkg/actions/cluster"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 )
 
@@ -14,14 +15,16 @@ const (
 
 var _ = Describe("delete cluster", func() {
 	DescribeTable("should be called to delete the cluster",
-		func(forceExpected bool, disableNodegroupEvictionExpected bool, args ...string) {
+		func(forceExpected bool, disableNodegroupEvictionExpected bool, continueAfterDeprecatedStacksExpected bool, skipOrphanedNetworkCleanupExpected bool, args ...string) {
 			cmd := newMockEmptyCmd(args...)
 			count := 0
 			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
-				deleteClusterWithRunFunc(cmd, func(cmd *cmdutils.Cmd, force bool, disableNodegroupEviction bool, parallel int) error {
+				deleteClusterWithRunFunc(cmd, func(cmd *cmdutils.Cmd, options cluster.DeleteOptions) error {
 					Expect(cmd.ClusterConfig.Metadata.Name).To(Equal(clusterName))
-					Expect(force).To(Equal(forceExpected))
-					Expect(disableNodegroupEviction).To(Equal(disableNodegroupEvictionExpected))
+					Expect(options.Force).To(Equal(forceExpected))
+					Expect(options.DisableNodegroupEviction).To(Equal(disableNodegroupEvictionExpected))
+					Expect(options.ContinueAfterDeprecatedStacks).To(Equal(continueAfterDeprecatedStacksExpected))
+					Expect(options.SkipOrphanedNetworkCleanup).To(Equal(skipOrphanedNetworkCleanupExpected))
 					count++
 					return nil
 				})
@@ -30,9 +33,11 @@ var _ = Describe("delete cluster", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(count).To(Equal(1))
 		},
-		Entry("with only valid cluster name", false, false, "cluster", "--name", clusterName),
-		Entry("with valid cluster name and force flag", true, false, "cluster", "--name", clusterName, "--force"),
-		Entry("with valid cluster name and disableNodeGroupEviction flag", false, true, "cluster", "--name", clusterName, "--disable-nodegroup-eviction"),
-		Entry("with valid cluster name, force & disableNodeGroupEviction flags", true, true, "cluster", "--name", clusterName, "--force", "--disable-nodegroup-eviction"),
+		Entry("with only valid cluster name", false, false, false, false, "cluster", "--name", clusterName),
+		Entry("with valid cluster name and force flag", true, false, false, false, "cluster", "--name", clusterName, "--force"),
+		Entry("with valid cluster name and disableNodeGroupEviction flag", false, true, false, false, "cluster", "--name", clusterName, "--disable-nodegroup-eviction"),
+		Entry("with valid cluster name, force & disableNodeGroupEviction flags", true, true, false, false, "cluster", "--name", clusterName, "--force", "--disable-nodegroup-eviction"),
+		Entry("with valid cluster name and continue-after-deprecated-stacks flag", false, false, true, false, "cluster", "--name", clusterName, "--continue-after-deprecated-stacks"),
+		Entry("with valid cluster name and skip-orphaned-network-cleanup flag", false, false, false, true, "cluster", "--name", clusterName, "--skip-orphaned-network-cleanup"),
 	)
 })
@@ -16,12 +16,12 @@ import (
 )
 
 func deleteIAMServiceAccountCmd(cmd *cmdutils.Cmd) {
-	deleteIAMServiceAccountCmdWithRunFunc(cmd, func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool) error {
-		return doDeleteIAMServiceAccount(cmd, serviceAccount, onlyMissing)
+	deleteIAMServiceAccountCmdWithRunFunc(cmd, func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool, options *irsa.DeleteOptions) error {
+		return doDeleteIAMServiceAccount(cmd, serviceAccount, onlyMissing, options)
 	})
 }
 
-func deleteIAMServiceAccountCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool) error) {
+func deleteIAMServiceAccountCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool, options *irsa.DeleteOptions) error) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
@@ -31,12 +31,13 @@ func deleteIAMServiceAccountCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *
 	cfg.IAM.ServiceAccounts = append(cfg.IAM.ServiceAccounts, serviceAccount)
 
 	var onlyMissing bool
+	options := &irsa.DeleteOptions{}
 
 	cmd.SetDescription("iamserviceaccount", "Delete an IAM service account", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return runFunc(cmd, serviceAccount, onlyMissing)
+		return runFunc(cmd, serviceAccount, onlyMissing, options)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -44,6 +45,8 @@ func deleteIAMServiceAccountCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *
 
 		fs.StringVar(&serviceAccount.Name, "name", "", "name of the iamserviceaccount to delete")
 		fs.StringVar(&serviceAccount.Namespace, "namespace", "default", "namespace where to delete the iamserviceaccount")
+		fs.BoolVar(&options.All, "all", false, "delete all iamserviceaccounts in the namespace set with --namespace")
+		fs.StringVar(&options.Selector, "selector", "", "delete the iamserviceaccounts whose serviceaccount matches this label selector (e.g. app=legacy), in all namespaces unless --namespace is set")
 
 		cmdutils.AddIAMServiceAccountFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 		fs.BoolVar(&onlyMissing, "only-missing", false, "Only delete iamserviceaccounts that are not defined in the given config file")
@@ -59,10 +62,10 @@ func deleteIAMServiceAccountCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, true)
 }
 
-func doDeleteIAMServiceAccount(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool) error {
+func doDeleteIAMServiceAccount(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool, options *irsa.DeleteOptions) error {
 	saFilter := filter.NewIAMServiceAccountFilter()
 
-	if err := cmdutils.NewDeleteIAMServiceAccountLoader(cmd, serviceAccount, saFilter).Load(); err != nil {
+	if err := cmdutils.NewDeleteIAMServiceAccountLoader(cmd, serviceAccount, saFilter, options).Load(); err != nil {
 		return err
 	}
 
@@ -100,6 +103,10 @@ func doDeleteIAMServiceAccount(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAM
 
 	stackManager := ctl.NewStackManager(cfg)
 
+	if options.IsBulk() {
+		return irsa.New(cfg.Metadata.Name, stackManager, oidc, clientSet).DeleteMatching(context.TODO(), *options, cmd.Plan, cmd.Wait)
+	}
+
 	if cmd.ClusterConfigFile != "" {
 		logger.Info("comparing %d iamserviceaccounts defined in the given config (%q) against remote state", len(cfg.IAM.ServiceAccounts), cmd.ClusterConfigFile)
 		if err := saFilter.SetDeleteFilter(stackManager, onlyMissing, cfg); err != nil {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/weaveworks/eksctl/pkg/actions/irsa"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
@@ -21,7 +22,7 @@ var _ = Describe("delete iamserviceaccount", func() {
 			cmd := newMockEmptyCmd(commandArgs...)
 			count := 0
 			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
-				deleteIAMServiceAccountCmdWithRunFunc(cmd, func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool) error {
+				deleteIAMServiceAccountCmdWithRunFunc(cmd, func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool, _ *irsa.DeleteOptions) error {
 					Expect(cmd.ClusterConfig.Metadata.Name).To(Equal("clusterName"))
 					Expect(cmd.ClusterConfig.IAM.ServiceAccounts[0].Name).To(Equal("serviceAccountName"))
 					Expect(onlyMissing).To(Equal(strings.Contains(strings.Join(commandArgs, " "), "only-missing")))
@@ -39,6 +40,27 @@ var _ = Describe("delete iamserviceaccount", func() {
 		Entry("with approve flag", "--cluster", "clusterName", "--name", "serviceAccountName", "--approve"),
 	)
 
+	DescribeTable("delete service accounts in bulk",
+		func(expected irsa.DeleteOptions, args ...string) {
+			commandArgs := append([]string{"iamserviceaccount"}, args...)
+			cmd := newMockEmptyCmd(commandArgs...)
+			count := 0
+			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
+				deleteIAMServiceAccountCmdWithRunFunc(cmd, func(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMServiceAccount, onlyMissing bool, options *irsa.DeleteOptions) error {
+					Expect(*options).To(Equal(expected))
+					Expect(options.IsBulk()).To(BeTrue())
+					count++
+					return nil
+				})
+			})
+			_, err := cmd.execute()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		},
+		Entry("with all flag", irsa.DeleteOptions{All: true}, "--cluster", "clusterName", "--namespace", "team-a", "--all"),
+		Entry("with selector flag", irsa.DeleteOptions{Selector: "app=legacy"}, "--cluster", "clusterName", "--selector", "app=legacy"),
+	)
+
 	DescribeTable("invalid flags or arguments",
 		func(c invalidParamsCase) {
 			commandArgs := append([]string{"iamserviceaccount"}, c.args...)
@@ -55,6 +77,18 @@ var _ = Describe("delete iamserviceaccount", func() {
 			args:  []string{"--cluster", "clusterName", "--name", "serviceAccountName", "serviceAccountName"},
 			error: fmt.Errorf("Error: --name=serviceAccountName and argument serviceAccountName cannot be used at the same time"),
 		}),
+		Entry("with all flag but without namespace", invalidParamsCase{
+			args:  []string{"--cluster", "clusterName", "--all"},
+			error: fmt.Errorf("Error: --namespace must be set when using --all"),
+		}),
+		Entry("with selector and name flags", invalidParamsCase{
+			args:  []string{"--cluster", "clusterName", "--selector", "app=legacy", "--name", "serviceAccountName"},
+			error: fmt.Errorf("Error: --name cannot be used with --all or --selector"),
+		}),
+		Entry("with an invalid selector", invalidParamsCase{
+			args:  []string{"--cluster", "clusterName", "--selector", "app in (legacy"},
+			error: fmt.Errorf(`Error: invalid --selector "app in (legacy"`),
+		}),
 		Entry("with invalid flags", invalidParamsCase{
 			args:  []string{"iamserviceaccount", "--invalid", "dummy"},
 			error: fmt.Errorf("Error: unknown flag: --invalid"),
@@ -22,7 +22,7 @@ var _ = Describe("delete", func() {
 			cmd := newMockEmptyCmd(args...)
 			count := 0
 			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
-				deleteNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *v1alpha5.NodeGroup, updateAuthConfigMap, deleteNodeGroupDrain, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool, parallel int) error {
+				deleteNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *v1alpha5.NodeGroup, updateAuthConfigMap, deleteNodeGroupDrain, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool, parallel int, purgeLaunchTemplates bool) error {
 					Expect(cmd.ClusterConfig.Metadata.Name).To(Equal("clusterName"))
 					Expect(ng.Name).To(Equal("ng"))
 					count++
@@ -37,6 +37,23 @@ var _ = Describe("delete", func() {
 		Entry("with deprecated flag --only", "nodegroup", "--cluster", "clusterName", "--name", "ng", "--only", "ng"),
 	)
 
+	It("passes --purge-launch-templates to the run function", func() {
+		cmd := newMockEmptyCmd("nodegroup", "--cluster", "clusterName", "--name", "ng", "--purge-launch-templates")
+		var purge, wait bool
+		cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
+			deleteNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, ng *v1alpha5.NodeGroup, updateAuthConfigMap, deleteNodeGroupDrain, onlyMissing bool, maxGracePeriod time.Duration, disableEviction bool, parallel int, purgeLaunchTemplates bool) error {
+				purge = purgeLaunchTemplates
+				wait = cmd.Wait
+				return nil
+			})
+		})
+		_, err := cmd.execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purge).To(BeTrue())
+		By("waiting for the nodegroups to be deleted")
+		Expect(wait).To(BeTrue())
+	})
+
 	DescribeTable("invalid flags or arguments",
 		func(c invalidParamsCase) {
 			cmd := newDefaultCmd(c.args...)
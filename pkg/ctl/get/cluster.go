@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	awseks "github.com/aws/aws-sdk-go/service/eks"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/weaveworks/eksctl/pkg/actions/cluster"
@@ -134,47 +133,52 @@ func getAndPrintCluster(ctx context.Context, cfg *api.ClusterConfig, ctl *eks.Cl
 		addGetClusterSummaryTableColumns(printer.(*printers.TablePrinter))
 	}
 
-	cluster, err := ctl.GetCluster(ctx, cfg.Metadata.Name)
-
+	summary, err := cluster.GetClusterSummary(ctx, ctl, cfg)
 	if err != nil {
 		return err
 	}
 
-	return printer.PrintObjWithKind("clusters", []*awseks.Cluster{cluster}, os.Stdout)
+	return printer.PrintObjWithKind("clusters", []*cluster.Summary{summary}, os.Stdout)
 }
 
 func addGetClusterSummaryTableColumns(printer *printers.TablePrinter) {
-	printer.AddColumn("NAME", func(c *awseks.Cluster) string {
+	printer.AddColumn("NAME", func(c *cluster.Summary) string {
 		if c.Name == nil {
 			return "-"
 		}
 		return *c.Name
 	})
-	printer.AddColumn("VERSION", func(c *awseks.Cluster) string {
+	printer.AddColumn("VERSION", func(c *cluster.Summary) string {
 		if c.Version == nil {
 			return "-"
 		}
 		return *c.Version
 	})
-	printer.AddColumn("STATUS", func(c *awseks.Cluster) string {
+	printer.AddColumn("STATUS", func(c *cluster.Summary) string {
 		if c.Status == nil {
 			return "-"
 		}
 		return *c.Status
 	})
-	printer.AddColumn("CREATED", func(c *awseks.Cluster) string {
+	printer.AddColumn("CREATED", func(c *cluster.Summary) string {
 		if c.CreatedAt == nil {
 			return "-"
 		}
 		return c.CreatedAt.Format(time.RFC3339)
 	})
-	printer.AddColumn("VPC", func(c *awseks.Cluster) string {
+	printer.AddColumn("LAST UPDATED", func(c *cluster.Summary) string {
+		return formatOptionalTime(c.LastUpdatedTime)
+	})
+	printer.AddColumn("EKSCTL VERSION", func(c *cluster.Summary) string {
+		return valueOrDash(c.EksctlVersion)
+	})
+	printer.AddColumn("VPC", func(c *cluster.Summary) string {
 		if c.ResourcesVpcConfig == nil {
 			return "-"
 		}
 		return *c.ResourcesVpcConfig.VpcId
 	})
-	printer.AddColumn("SUBNETS", func(c *awseks.Cluster) string {
+	printer.AddColumn("SUBNETS", func(c *cluster.Summary) string {
 		if c.ResourcesVpcConfig == nil || c.ResourcesVpcConfig.SubnetIds == nil {
 			return "-"
 		}
@@ -186,7 +190,7 @@ func addGetClusterSummaryTableColumns(printer *printers.TablePrinter) {
 		}
 		return strings.Join(subnets.List(), ",")
 	})
-	printer.AddColumn("SECURITYGROUPS", func(c *awseks.Cluster) string {
+	printer.AddColumn("SECURITYGROUPS", func(c *cluster.Summary) string {
 		if c.ResourcesVpcConfig == nil || c.ResourcesVpcConfig.SecurityGroupIds == nil {
 			return "-"
 		}
@@ -199,7 +203,7 @@ func addGetClusterSummaryTableColumns(printer *printers.TablePrinter) {
 		return strings.Join(groups.List(), ",")
 	})
 
-	printer.AddColumn("PROVIDER", func(c *awseks.Cluster) string {
+	printer.AddColumn("PROVIDER", func(c *cluster.Summary) string {
 		if c.ConnectorConfig != nil {
 			return *c.ConnectorConfig.Provider
 		}
@@ -1,10 +1,18 @@
 package get
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/actions/cluster"
+	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
 var _ = Describe("get", func() {
@@ -23,6 +31,60 @@ var _ = Describe("get", func() {
 			_, err = cmd.execute()
 			Expect(err).To(MatchError(ContainSubstring("Error: cannot use --name when --config-file/-f is set")))
 		})
+
+		Context("printing a cluster summary", func() {
+			var summaries []*cluster.Summary
+
+			BeforeEach(func() {
+				summaries = []*cluster.Summary{
+					{
+						Cluster: &awseks.Cluster{
+							Name:      aws.String("my-cluster"),
+							Version:   aws.String("1.22"),
+							Status:    aws.String(awseks.ClusterStatusActive),
+							CreatedAt: aws.Time(time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)),
+							ResourcesVpcConfig: &awseks.VpcConfigResponse{
+								VpcId:            aws.String("vpc-1234"),
+								SubnetIds:        aws.StringSlice([]string{"subnet-2", "subnet-1"}),
+								SecurityGroupIds: aws.StringSlice([]string{"sg-1"}),
+							},
+						},
+						CreationTime:    aws.Time(time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)),
+						LastUpdatedTime: aws.Time(time.Date(2022, 3, 15, 12, 30, 0, 0, time.UTC)),
+						EksctlVersion:   "0.90.0",
+					},
+				}
+			})
+
+			It("prints the last update time and eksctl version in the table", func() {
+				printer, err := printers.NewPrinter(printers.TableType)
+				Expect(err).NotTo(HaveOccurred())
+				addGetClusterSummaryTableColumns(printer.(*printers.TablePrinter))
+
+				var out bytes.Buffer
+				Expect(printer.PrintObjWithKind("clusters", summaries, &out)).To(Succeed())
+
+				golden, err := os.ReadFile("testdata/get_cluster.golden")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(out.String()).To(Equal(string(golden)))
+			})
+
+			It("includes the stack timestamps and eksctl version along with the cluster description in JSON", func() {
+				printer, err := printers.NewPrinter(printers.JSONType)
+				Expect(err).NotTo(HaveOccurred())
+
+				var out bytes.Buffer
+				Expect(printer.PrintObjWithKind("clusters", summaries, &out)).To(Succeed())
+
+				var clusters []map[string]interface{}
+				Expect(json.Unmarshal(out.Bytes(), &clusters)).To(Succeed())
+				Expect(clusters).To(HaveLen(1))
+				Expect(clusters[0]).To(HaveKeyWithValue("Name", "my-cluster"))
+				Expect(clusters[0]).To(HaveKeyWithValue("CreationTime", "2022-03-01T10:00:00Z"))
+				Expect(clusters[0]).To(HaveKeyWithValue("LastUpdatedTime", "2022-03-15T12:30:00Z"))
+				Expect(clusters[0]).To(HaveKeyWithValue("EksctlVersion", "0.90.0"))
+			})
+		})
 	})
 })
 
@@ -1,6 +1,8 @@
 package get
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
@@ -27,3 +29,17 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 
 	return verbCmd
 }
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -118,6 +118,9 @@ func addSummaryTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("CREATED", func(s *nodegroup.Summary) string {
 		return s.CreationTime.Format(time.RFC3339)
 	})
+	printer.AddColumn("LAST UPDATED", func(s *nodegroup.Summary) string {
+		return formatOptionalTime(s.LastUpdatedTime)
+	})
 	printer.AddColumn("MIN SIZE", func(s *nodegroup.Summary) string {
 		return strconv.Itoa(s.MinSize)
 	})
@@ -139,4 +142,10 @@ func addSummaryTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("TYPE", func(s *nodegroup.Summary) api.NodeGroupType {
 		return s.NodeGroupType
 	})
+	printer.AddColumn("SSH USER", func(s *nodegroup.Summary) string {
+		return valueOrDash(s.SSHUser)
+	})
+	printer.AddColumn("EKSCTL VERSION", func(s *nodegroup.Summary) string {
+		return valueOrDash(s.EksctlVersion)
+	})
 }
@@ -1,10 +1,16 @@
 package get
 
 import (
+	"bytes"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/actions/nodegroup"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
 var _ = Describe("get", func() {
@@ -46,6 +52,53 @@ var _ = Describe("get", func() {
 			_, err := cmd.execute()
 			Expect(err).To(MatchError(ContainSubstring("Error: unknown flag: --invalid")))
 		})
+
+		It("prints the last update time, eksctl version and SSH user in the table", func() {
+			creationTime := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+			lastUpdatedTime := time.Date(2022, 3, 15, 12, 30, 0, 0, time.UTC)
+			summaries := []*nodegroup.Summary{
+				{
+					Cluster:              "my-cluster",
+					Name:                 "ng-1",
+					Status:               "UPDATE_COMPLETE",
+					MinSize:              1,
+					MaxSize:              4,
+					DesiredCapacity:      2,
+					InstanceType:         "m5.large",
+					ImageID:              "ami-123",
+					CreationTime:         creationTime,
+					LastUpdatedTime:      &lastUpdatedTime,
+					EksctlVersion:        "0.90.0",
+					AutoScalingGroupName: "asg-1",
+					NodeGroupType:        api.NodeGroupTypeUnmanaged,
+					SSHUser:              "ec2-user",
+				},
+				{
+					Cluster:              "my-cluster",
+					Name:                 "mng-1",
+					Status:               "ACTIVE",
+					MinSize:              0,
+					MaxSize:              2,
+					DesiredCapacity:      1,
+					InstanceType:         "t3.medium",
+					ImageID:              "AL2_x86_64",
+					CreationTime:         creationTime,
+					AutoScalingGroupName: "asg-2",
+					NodeGroupType:        api.NodeGroupTypeManaged,
+				},
+			}
+
+			printer, err := printers.NewPrinter(printers.TableType)
+			Expect(err).NotTo(HaveOccurred())
+			addSummaryTableColumns(printer.(*printers.TablePrinter))
+
+			var out bytes.Buffer
+			Expect(printer.PrintObjWithKind("nodegroups", summaries, &out)).To(Succeed())
+
+			golden, err := os.ReadFile("testdata/get_nodegroup.golden")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(Equal(string(golden)))
+		})
 	})
 })
 
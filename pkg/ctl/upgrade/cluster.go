@@ -22,12 +22,14 @@ func upgradeCluster(cmd *cmdutils.Cmd) {
 	upgradeClusterWithRunFunc(cmd, DoUpgradeCluster)
 }
 
-func upgradeClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd) error) {
+func upgradeClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, planHash string) error) {
 	cfg := api.NewClusterConfig()
 	// Reset version
 	cfg.Metadata.Version = ""
 	cmd.ClusterConfig = cfg
 
+	var planHash string
+
 	cmd.SetDescription("cluster", "Upgrade control plane to the next version",
 		"Upgrade control plane to the next Kubernetes version if available. Will also perform any updates needed in the cluster stack if resources are missing.")
 
@@ -42,6 +44,7 @@ func upgradeClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd
 		// cmdutils.AddVersionFlag(fs, cfg.Metadata, `"next" and "latest" can be used to automatically increment version by one, or force latest`)
 
 		cmdutils.AddApproveFlag(fs, cmd)
+		fs.StringVar(&planHash, "plan-hash", "", "only apply the upgrade if its plan matches the hash printed by a previous run without --approve")
 
 		cmdutils.AddTimeoutFlagWithValue(fs, &cmd.ProviderConfig.WaitTimeout, upgradeClusterTimeout)
 	})
@@ -52,13 +55,13 @@ func upgradeClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd
 		if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 			return err
 		}
-		return runFunc(cmd)
+		return runFunc(cmd, planHash)
 	}
 }
 
 // DoUpgradeCluster made public so that it can be shared with update/cluster.go until this is deprecated
 // TODO Once `eksctl update cluster` is officially deprecated this can be made package private again
-func DoUpgradeCluster(cmd *cmdutils.Cmd) error {
+func DoUpgradeCluster(cmd *cmdutils.Cmd, planHash string) error {
 	ctl, err := cmd.NewProviderForExistingCluster()
 	if err != nil {
 		return err
@@ -78,5 +81,5 @@ func DoUpgradeCluster(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
-	return c.Upgrade(context.TODO(), cmd.Plan)
+	return c.Upgrade(context.TODO(), cmd.Plan, planHash)
 }
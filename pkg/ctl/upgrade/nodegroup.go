@@ -33,6 +33,9 @@ func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 		fs.BoolVar(&options.ForceUpgrade, "force-upgrade", false, "Force the update if the existing node group's pods are unable to be drained due to a pod disruption budget issue")
 		fs.StringVar(&options.ReleaseVersion, "release-version", "", "AMI version of the EKS optimized AMI to use")
 		fs.BoolVar(&options.Wait, "wait", true, "nodegroup upgrade to complete")
+		fs.BoolVar(&options.Roll, "roll", false, "Replace the existing instances of an unmanaged nodegroup after upgrading its AMI")
+		fs.BoolVar(&options.DryRun, "dry-run", false, "Print the AMI changes for an unmanaged nodegroup without applying them")
+		fs.BoolVar(&options.PreserveDesiredCapacity, "preserve-desired-capacity", true, "Keep the current desired capacity of an autoscaled nodegroup when updating its stack")
 	})
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
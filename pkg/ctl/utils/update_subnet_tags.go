@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+func updateSubnetTagsCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var subnetTags map[string]string
+
+	cmd.SetDescription("update-subnet-tags", "Update the tags of the subnets created by eksctl",
+		"Creates the tags of vpc.subnetTags, vpc.publicSubnetTags and vpc.privateSubnetTags that are missing or have a different value on the subnets of the cluster stack")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if err := cmdutils.NewUtilsUpdateSubnetTagsLoader(cmd, subnetTags).Load(); err != nil {
+			return err
+		}
+		return doUpdateSubnetTags(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, &cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddStringToStringVarPFlag(fs, &subnetTags, "subnet-tags", "", map[string]string{}, "Tags to apply to all subnets created by eksctl")
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, &cmd.ProviderConfig, false)
+}
+
+func doUpdateSubnetTags(cmd *cmdutils.Cmd) error {
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewProviderForExistingCluster()
+	if err != nil {
+		return err
+	}
+	logger.Info("using region %s", meta.Region)
+
+	ctx := context.TODO()
+	stackManager := ctl.NewStackManager(cfg)
+	if err := ctl.LoadClusterVPC(ctx, cfg, stackManager); err != nil {
+		return errors.Wrapf(err, "getting VPC configuration for cluster %q", meta.Name)
+	}
+
+	updates, err := vpc.PlanSubnetTagsUpdates(ctx, ctl.Provider.EC2(), stackManager.MakeClusterStackName(), cfg)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		logger.Success("tags of the subnets of cluster %q in %q are already up to date", meta.Name, meta.Region)
+		return nil
+	}
+
+	for _, update := range updates {
+		cmdutils.LogIntendedAction(cmd.Plan, "create tags %v on subnet %q", update.Tags, update.SubnetID)
+	}
+
+	if !cmd.Plan {
+		if err := vpc.UpdateSubnetTags(ctx, ctl.Provider.EC2(), updates); err != nil {
+			return err
+		}
+		cmdutils.LogCompletedAction(false, "updated the tags of %d subnet(s) of cluster %q in %q", len(updates), meta.Name, meta.Region)
+	}
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+	return nil
+}
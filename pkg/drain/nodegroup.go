@@ -15,6 +15,7 @@ import (
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/metrics"
 
 	cmap "github.com/orcaman/concurrent-map"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -161,6 +162,7 @@ func (n *NodeGroupDrainer) Drain() error {
 					logger.Debug("%d pods to be evicted from %s", pending, node)
 					if pending == 0 {
 						drainedNodes.Set(node, nil)
+						metrics.NodeDrained()
 					}
 
 					if n.nodeDrainWaitPeriod > 0 {
@@ -41,6 +41,8 @@ import (
 	ekscreds "github.com/weaveworks/eksctl/pkg/credentials"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
 	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/metrics"
+	"github.com/weaveworks/eksctl/pkg/utils/strings"
 	"github.com/weaveworks/eksctl/pkg/version"
 )
 
@@ -230,6 +232,10 @@ func ParseConfig(data []byte) (*api.ClusterConfig, error) {
 	if !ok {
 		return nil, fmt.Errorf("expected to decode object of type %T; got %T", &api.ClusterConfig{}, cfg)
 	}
+
+	if err := api.MergeNodeGroupDefaults(cfg); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
@@ -299,10 +305,10 @@ func ResolveAMI(ctx context.Context, provider api.ClusterProvider, version strin
 	case api.NodeImageResolverAuto:
 		resolver = ami.NewAutoResolver(provider.EC2())
 	case api.NodeImageResolverAutoSSM:
-		resolver = ami.NewSSMResolver(provider.SSM())
+		resolver = ami.NewSSMResolver(provider.SSM(), provider.EC2())
 	case "":
 		resolver = ami.NewMultiResolver(
-			ami.NewSSMResolver(provider.SSM()),
+			ami.NewSSMResolver(provider.SSM(), provider.EC2()),
 			ami.NewAutoResolver(provider.EC2()),
 		)
 	default:
@@ -310,7 +316,21 @@ func ResolveAMI(ctx context.Context, provider api.ClusterProvider, version strin
 	}
 
 	instanceType := api.SelectInstanceType(np)
-	id, err := resolver.Resolve(ctx, provider.Region(), version, instanceType, ng.AMIFamily)
+	architecture, err := nodePoolArchitecture(ctx, provider.EC2(), np)
+	if err != nil {
+		return errors.Wrap(err, "unable to determine AMI to use")
+	}
+	var id string
+	if unmanaged, ok := np.(*api.NodeGroup); ok && unmanaged.AMIReleaseVersion != "" {
+		// releases are only published in SSM; the instance types of the nodegroup share the architecture of
+		// instanceType, which was checked above
+		ssmResolver := ami.NewSSMResolver(provider.SSM(), provider.EC2()).(*ami.SSMResolver)
+		id, err = ssmResolver.ResolveWithReleaseVersion(ctx, provider.Region(), version, instanceType, ng.AMIFamily, unmanaged.AMIReleaseVersion)
+	} else if architectureResolver, ok := resolver.(ami.ArchitectureResolver); ok && architecture != "" {
+		id, err = architectureResolver.ResolveForArchitecture(ctx, provider.Region(), version, instanceType, ng.AMIFamily, architecture)
+	} else {
+		id, err = resolver.Resolve(ctx, provider.Region(), version, instanceType, ng.AMIFamily)
+	}
 	if err != nil {
 		return errors.Wrap(err, "unable to determine AMI to use")
 	}
@@ -321,35 +341,116 @@ func ResolveAMI(ctx context.Context, provider api.ClusterProvider, version strin
 	return nil
 }
 
-// SetAvailabilityZones sets the given (or chooses) the availability zones
+// nodePoolArchitecture returns the CPU architecture shared by the instance types of a nodegroup mixing several
+// instance types, or an error naming the instance types of each architecture if they do not share one. It returns
+// an empty string for nodegroups with a single instance type
+func nodePoolArchitecture(ctx context.Context, ec2API awsapi.EC2, np api.NodePool) (string, error) {
+	ng, ok := np.(interface{ InstanceTypeList() []string })
+	if !ok {
+		return "", nil
+	}
+	instanceTypes := ng.InstanceTypeList()
+	if len(instanceTypes) < 2 {
+		return "", nil
+	}
+	return ami.Architecture(ctx, ec2API, instanceTypes)
+}
+
+// SetAvailabilityZones sets the given (or chooses) the availability zones. Local Zones and Wavelength Zones, whether
+// listed in localZones or among the availability zones, are checked and set as the local zones of spec
 func SetAvailabilityZones(ctx context.Context, spec *api.ClusterConfig, given []string, ec2API awsapi.EC2, region string) error {
-	if count := len(given); count != 0 {
-		if count < api.MinRequiredAvailabilityZones {
-			return api.ErrTooFewAvailabilityZones(given)
-		}
-		spec.AvailabilityZones = given
-		return nil
+	zones := spec.AvailabilityZones
+	if len(given) != 0 {
+		zones = given
 	}
+	availabilityZones, localZones := splitLocalZones(zones, spec.LocalZones)
 
-	if count := len(spec.AvailabilityZones); count != 0 {
+	if count := len(availabilityZones); count != 0 {
 		if count < api.MinRequiredAvailabilityZones {
-			return api.ErrTooFewAvailabilityZones(spec.AvailabilityZones)
+			return api.ErrTooFewAvailabilityZones(availabilityZones)
+		}
+		if len(localZones) > 0 {
+			if err := az.CheckLocalZones(ctx, ec2API, region, localZones); err != nil {
+				return errors.Wrap(err, "getting local zones")
+			}
 		}
+		spec.AvailabilityZones = availabilityZones
+		spec.LocalZones = localZones
 		return nil
 	}
 
 	logger.Debug("determining availability zones")
-	zones, err := az.GetAvailabilityZones(ctx, ec2API, region)
+	opts := []az.Option{az.WithLocalZones(localZones)}
+	if instanceTypes := nodeGroupInstanceTypes(spec); len(instanceTypes) > 0 {
+		opts = append(opts, az.WithInstanceTypes(instanceTypes))
+	}
+	if spec.AvailabilityZoneCount != nil {
+		opts = append(opts, az.WithCount(*spec.AvailabilityZoneCount))
+	}
+	if len(spec.AvailabilityZoneIDs) > 0 {
+		opts = append(opts, az.WithZoneIDs(spec.AvailabilityZoneIDs))
+	}
+	zones, err := az.GetAvailabilityZones(ctx, ec2API, region, spec.DeniedAvailabilityZoneIDs, opts...)
 	if err != nil {
 		return errors.Wrap(err, "getting availability zones")
 	}
 
-	logger.Info("setting availability zones to %v", zones)
-	spec.AvailabilityZones = zones
+	availabilityZones = zones[:len(zones)-len(localZones)]
+	if count := spec.AvailabilityZoneCount; count != nil && len(availabilityZones) < *count {
+		return fmt.Errorf("%d availability zones were requested, but only %d zones of region %s can be chosen %v", *count, len(availabilityZones), region, availabilityZones)
+	}
+	spec.AvailabilityZones = availabilityZones
+	spec.LocalZones = localZones
+	if len(spec.AvailabilityZoneIDs) > 0 {
+		// the zone IDs are resolved to zone names, which are used from now on, so that the config remains valid
+		logger.Info("setting availability zones to %v for zone IDs %v", spec.AvailabilityZones, spec.AvailabilityZoneIDs)
+		spec.AvailabilityZoneIDs = nil
+	} else {
+		logger.Info("setting availability zones to %v", spec.AvailabilityZones)
+	}
+	if len(localZones) > 0 {
+		logger.Info("setting local zones to %v", localZones)
+	}
 
 	return nil
 }
 
+// nodeGroupInstanceTypes returns the instance types of all nodegroups of spec, without duplicates. The instance types
+// of nodegroups using instanceRequirements are not known, so they are left out
+func nodeGroupInstanceTypes(spec *api.ClusterConfig) []string {
+	var instanceTypes []string
+	addInstanceTypes := func(nodeGroupInstanceTypes []string) {
+		for _, instanceType := range nodeGroupInstanceTypes {
+			if instanceType != "" && instanceType != "mixed" && !strings.Contains(instanceTypes, instanceType) {
+				instanceTypes = append(instanceTypes, instanceType)
+			}
+		}
+	}
+	for _, ng := range spec.NodeGroups {
+		addInstanceTypes(ng.InstanceTypeList())
+	}
+	for _, ng := range spec.ManagedNodeGroups {
+		addInstanceTypes(ng.InstanceTypeList())
+	}
+	return instanceTypes
+}
+
+// splitLocalZones separates the Local Zones and Wavelength Zones listed among zones, either because they are in
+// localZones or because they are not named like availability zones, and returns them after localZones
+func splitLocalZones(zones, localZones []string) (availabilityZones, allLocalZones []string) {
+	allLocalZones = append(allLocalZones, localZones...)
+	for _, zone := range zones {
+		switch {
+		case strings.Contains(allLocalZones, zone):
+		case az.IsAvailabilityZoneName(zone):
+			availabilityZones = append(availabilityZones, zone)
+		default:
+			allLocalZones = append(allLocalZones, zone)
+		}
+	}
+	return availabilityZones, allLocalZones
+}
+
 func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session {
 	// we might want to use bits from kops, although right now it seems like too many things we
 	// don't want yet
@@ -389,6 +490,7 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 		Fn: request.MakeAddToUserAgentHandler(
 			"eksctl", version.String()),
 	})
+	s.Handlers.Complete.PushBackNamed(metrics.AWSRequestHandler)
 
 	if spec.Region == "" {
 		if api.IsSetAndNonEmptyString(s.Config.Region) {
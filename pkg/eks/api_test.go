@@ -43,6 +43,33 @@ var _ = Describe("eksctl API", func() {
 			Expect(cfg.NodeGroups).To(HaveLen(1))
 		})
 
+		It("should merge nodegroup defaults and resolve YAML anchors and aliases", func() {
+			cfg, err := LoadConfigFromFile("testdata/nodegroup-defaults.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.NodeGroupDefaults).To(BeNil())
+			Expect(cfg.ManagedNodeGroupDefaults).To(BeNil())
+
+			Expect(cfg.NodeGroups).To(HaveLen(2))
+			ng1, ng2 := cfg.NodeGroups[0], cfg.NodeGroups[1]
+			Expect(ng1.InstanceType).To(Equal("m5.large"))
+			Expect(*ng1.MinSize).To(Equal(1))
+			Expect(ng1.Labels).To(Equal(map[string]string{"team": "platform"}))
+			Expect(*ng1.SSH.Allow).To(BeTrue())
+			Expect(*ng1.SSH.PublicKeyName).To(Equal("ec2_dev_key"))
+			Expect(*ng1.IAM.WithAddonPolicies.ImageBuilder).To(BeTrue())
+
+			Expect(ng2.InstanceType).To(Equal("m5.xlarge"))
+			Expect(*ng2.MinSize).To(Equal(2))
+			Expect(ng2.Labels).To(Equal(map[string]string{"team": "platform", "role": "builders"}))
+			Expect(*ng2.IAM.WithAddonPolicies.ImageBuilder).To(BeTrue())
+			Expect(*ng2.IAM.WithAddonPolicies.AutoScaler).To(BeTrue())
+			Expect(ng2.SSH).NotTo(BeIdenticalTo(ng1.SSH))
+
+			Expect(cfg.ManagedNodeGroups).To(HaveLen(1))
+			Expect(cfg.ManagedNodeGroups[0].InstanceType).To(Equal("m5.large"))
+			Expect(cfg.ManagedNodeGroups[0].Labels).To(Equal(map[string]string{"team": "platform"}))
+		})
+
 		It("should error when version is a float, not a string", func() {
 			_, err := LoadConfigFromFile("testdata/bad-type-1.yaml")
 			Expect(err).To(HaveOccurred())
@@ -94,6 +121,18 @@ var _ = Describe("eksctl API", func() {
 			mockDescribeImages(provider, "ami-123", func(input *ec2.DescribeImagesInput) bool {
 				return len(input.ImageIds) == 1
 			})
+			provider.MockEC2().On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+				InstanceTypes: []ec2types.InstanceType{ec2types.InstanceTypeM5Large},
+			}).Return(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []ec2types.InstanceTypeInfo{
+					{
+						InstanceType: ec2types.InstanceTypeM5Large,
+						ProcessorInfo: &ec2types.ProcessorInfo{
+							SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+						},
+					},
+				},
+			}, nil)
 
 		})
 
@@ -115,6 +154,20 @@ var _ = Describe("eksctl API", func() {
 			testEnsureAMI(Equal("ami-ssm"))
 		})
 
+		It("should resolve the pinned release of the AMI", func() {
+			ng.AMIFamily = api.NodeImageFamilyBottlerocket
+			ng.AMIReleaseVersion = "1.19.2"
+			provider.MockSSM().On("GetParameter", mock.Anything, &ssm.GetParameterInput{
+				Name: aws.String("/aws/service/bottlerocket/aws-k8s-1.14/x86_64/1.19.2/image_id"),
+			}).Return(&ssm.GetParameterOutput{
+				Parameter: &ssmtypes.Parameter{
+					Value: aws.String("ami-bottlerocket-1.19.2"),
+				},
+			}, nil)
+
+			testEnsureAMI(Equal("ami-bottlerocket-1.19.2"))
+		})
+
 		It("should fall back to auto resolution for Ubuntu", func() {
 			ng.AMIFamily = api.NodeImageFamilyUbuntu1804
 			mockDescribeImages(provider, "ami-ubuntu", func(input *ec2.DescribeImagesInput) bool {
@@ -132,6 +185,34 @@ var _ = Describe("eksctl API", func() {
 
 			testEnsureAMI(Equal("ami-auto"))
 		})
+
+		It("should fail when the instance types of the nodegroup have different architectures", func() {
+			ng.InstanceType = "mixed"
+			ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
+				InstanceTypes: []string{"m5.large", "m6g.large"},
+			}
+			provider.MockEC2().On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+				InstanceTypes: []ec2types.InstanceType{ec2types.InstanceTypeM5Large, ec2types.InstanceTypeM6gLarge},
+			}).Return(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []ec2types.InstanceTypeInfo{
+					{
+						InstanceType: ec2types.InstanceTypeM5Large,
+						ProcessorInfo: &ec2types.ProcessorInfo{
+							SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+						},
+					},
+					{
+						InstanceType: ec2types.InstanceTypeM6gLarge,
+						ProcessorInfo: &ec2types.ProcessorInfo{
+							SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeArm64},
+						},
+					},
+				},
+			}, nil)
+
+			err := ResolveAMI(context.Background(), provider, "1.14", ng)
+			Expect(err).To(MatchError(ContainSubstring("arm64 (m6g.large), x86_64 (m5.large)")))
+		})
 	})
 
 })
@@ -255,6 +336,157 @@ var _ = Describe("Setting Availability Zones", func() {
 				err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("does not set the AZs denied in the config", func() {
+				region := "us-east-2"
+				provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []ec2types.AvailabilityZone{
+						{ZoneName: aws.String("us-east-2a"), ZoneId: aws.String("use2-az1")},
+						{ZoneName: aws.String("us-east-2b"), ZoneId: aws.String("use2-az2")},
+						{ZoneName: aws.String("us-east-2c"), ZoneId: aws.String("use2-az3")},
+					},
+				}, nil)
+				cfg.DeniedAvailabilityZoneIDs = []string{"use2-az2"}
+				err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.AvailabilityZones).To(ConsistOf("us-east-2a", "us-east-2c"))
+			})
+
+			It("sets the AZs with the zone IDs in the config", func() {
+				region := "us-east-2"
+				provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []ec2types.AvailabilityZone{
+						{ZoneName: aws.String("us-east-2a"), ZoneId: aws.String("use2-az1")},
+						{ZoneName: aws.String("us-east-2b"), ZoneId: aws.String("use2-az2")},
+						{ZoneName: aws.String("us-east-2c"), ZoneId: aws.String("use2-az3")},
+					},
+				}, nil)
+				cfg.AvailabilityZoneIDs = []string{"use2-az3", "use2-az2"}
+				err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.AvailabilityZones).To(Equal([]string{"us-east-2c", "us-east-2b"}))
+				Expect(cfg.AvailabilityZoneIDs).To(BeEmpty())
+			})
+
+			It("sets the number of AZs in the config", func() {
+				region := "us-east-2"
+				provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []ec2types.AvailabilityZone{
+						{ZoneName: aws.String("us-east-2a"), ZoneId: aws.String("use2-az1")},
+						{ZoneName: aws.String("us-east-2b"), ZoneId: aws.String("use2-az2")},
+						{ZoneName: aws.String("us-east-2c"), ZoneId: aws.String("use2-az3")},
+						{ZoneName: aws.String("us-east-2d"), ZoneId: aws.String("use2-az4")},
+					},
+				}, nil)
+				cfg.AvailabilityZoneCount = aws.Int(4)
+				err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.AvailabilityZones).To(ConsistOf("us-east-2a", "us-east-2b", "us-east-2c", "us-east-2d"))
+			})
+
+			It("errors when fewer AZs than the number in the config are available", func() {
+				region := "us-east-2"
+				provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []ec2types.AvailabilityZone{
+						{ZoneName: aws.String("us-east-2a"), ZoneId: aws.String("use2-az1")},
+						{ZoneName: aws.String("us-east-2b"), ZoneId: aws.String("use2-az2")},
+						{ZoneName: aws.String("us-east-2c"), ZoneId: aws.String("use2-az3")},
+					},
+				}, nil)
+				cfg.AvailabilityZoneCount = aws.Int(4)
+				err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+				Expect(err).To(MatchError(ContainSubstring("4 availability zones were requested, but only 3 zones of region us-east-2 can be chosen")))
+			})
+
+			It("only sets the AZs that offer the instance types of the nodegroups", func() {
+				region := "us-east-2"
+				provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.Anything).Return(&ec2.DescribeAvailabilityZonesOutput{
+					AvailabilityZones: []ec2types.AvailabilityZone{
+						{ZoneName: aws.String("us-east-2a"), ZoneId: aws.String("use2-az1")},
+						{ZoneName: aws.String("us-east-2b"), ZoneId: aws.String("use2-az2")},
+						{ZoneName: aws.String("us-east-2c"), ZoneId: aws.String("use2-az3")},
+					},
+				}, nil)
+				provider.MockEC2().On("DescribeInstanceTypeOfferings", mock.Anything, &ec2.DescribeInstanceTypeOfferingsInput{
+					LocationType: ec2types.LocationTypeAvailabilityZone,
+					Filters: []ec2types.Filter{
+						{
+							Name:   aws.String("instance-type"),
+							Values: []string{"p4d.24xlarge", "m5.large"},
+						},
+					},
+				}).Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+					InstanceTypeOfferings: []ec2types.InstanceTypeOffering{
+						{InstanceType: ec2types.InstanceTypeP4d24xlarge, Location: aws.String("us-east-2a")},
+						{InstanceType: ec2types.InstanceTypeP4d24xlarge, Location: aws.String("us-east-2b")},
+						{InstanceType: ec2types.InstanceTypeM5Large, Location: aws.String("us-east-2a")},
+						{InstanceType: ec2types.InstanceTypeM5Large, Location: aws.String("us-east-2b")},
+						{InstanceType: ec2types.InstanceTypeM5Large, Location: aws.String("us-east-2c")},
+					},
+				}, nil)
+				ng := cfg.NewNodeGroup()
+				ng.InstanceType = "p4d.24xlarge"
+				mng := api.NewManagedNodeGroup()
+				mng.InstanceTypes = []string{"m5.large", "p4d.24xlarge"}
+				cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{mng}
+				err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.AvailabilityZones).To(ConsistOf("us-east-2a", "us-east-2b"))
+			})
+		})
+	})
+
+	When("Local Zones are set", func() {
+		const region = "us-west-2"
+
+		BeforeEach(func() {
+			provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, &ec2.DescribeAvailabilityZonesInput{
+				Filters: []ec2types.Filter{{
+					Name:   aws.String("region-name"),
+					Values: []string{region},
+				}, {
+					Name:   aws.String("state"),
+					Values: []string{string(ec2types.AvailabilityZoneStateAvailable)},
+				}, {
+					Name:   aws.String("zone-type"),
+					Values: []string{"local-zone", "wavelength-zone"},
+				}},
+			}).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					{ZoneName: aws.String("us-west-2-lax-1a"), ZoneId: aws.String("usw2-lax1-az1")},
+					{ZoneName: aws.String("us-west-2-wl1-sea-wlz-1"), ZoneId: aws.String("usw2-wl1-sea-wlz1")},
+				},
+			}, nil)
+		})
+
+		It("moves the Local Zones listed among the AZs to the local zones", func() {
+			cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2-lax-1a", "us-west-2b"}
+			cfg.LocalZones = []string{"us-west-2-wl1-sea-wlz-1"}
+			err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.AvailabilityZones).To(Equal([]string{"us-west-2a", "us-west-2b"}))
+			Expect(cfg.LocalZones).To(Equal([]string{"us-west-2-wl1-sea-wlz-1", "us-west-2-lax-1a"}))
+		})
+
+		It("returns an error for a zone that is not an available Local Zone", func() {
+			err := eks.SetAvailabilityZones(context.Background(), cfg, []string{"us-west-2a", "us-west-2b", "us-west-2-den-1a"}, provider.EC2(), region)
+			Expect(err).To(MatchError(`getting local zones: zone "us-west-2-den-1a" is not an available Local Zone or Wavelength Zone of region us-west-2, available zones are [us-west-2-lax-1a us-west-2-wl1-sea-wlz-1]`))
+		})
+
+		It("selects the AZs along with the Local Zones", func() {
+			provider.MockEC2().On("DescribeAvailabilityZones", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeAvailabilityZonesInput) bool {
+				return input.Filters[2].Values[0] == "availability-zone"
+			})).Return(&ec2.DescribeAvailabilityZonesOutput{
+				AvailabilityZones: []ec2types.AvailabilityZone{
+					{ZoneName: aws.String("us-west-2a"), ZoneId: aws.String("usw2-az1")},
+					{ZoneName: aws.String("us-west-2b"), ZoneId: aws.String("usw2-az2")},
+				},
+			}, nil)
+			cfg.LocalZones = []string{"us-west-2-lax-1a"}
+			err := eks.SetAvailabilityZones(context.Background(), cfg, []string{}, provider.EC2(), region)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.AvailabilityZones).To(ConsistOf("us-west-2a", "us-west-2b"))
+			Expect(cfg.LocalZones).To(Equal([]string{"us-west-2-lax-1a"}))
 		})
 	})
 })
@@ -25,6 +25,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/credentials"
+	"github.com/weaveworks/eksctl/pkg/metrics"
 	"github.com/weaveworks/eksctl/pkg/version"
 )
 
@@ -57,6 +58,7 @@ func newV2Config(pc *api.ProviderConfig, region string, credentialsCacheFilePath
 		}),
 		config.WithAPIOptions([]func(stack *middleware.Stack) error{
 			middlewarev2.AddUserAgentKeyValue("eksctl", version.String()),
+			metrics.AddAWSMiddleware,
 		}),
 	)...)
 
@@ -9,7 +9,6 @@ import (
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -142,27 +141,28 @@ func (c *ClusterProvider) UpdateAuthConfigMap(nodeGroups []*api.NodeGroup, clien
 	return nil
 }
 
-// WaitForNodes waits till the nodes are ready
+// WaitForNodes waits till at least the minimum number of nodes of ng, selected by its label, are ready, or fails after
+// the wait timeout of the provider
 func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNodeGroup) error {
 	minSize := ng.Size()
 	if minSize == 0 {
 		return nil
 	}
 	timeout := time.After(c.Provider.WaitTimeout())
-	readyNodes := sets.NewString()
 	watcher, err := clientSet.CoreV1().Nodes().Watch(context.TODO(), ng.ListOptions())
 	if err != nil {
 		return errors.Wrap(err, "creating node watcher")
 	}
 	defer watcher.Stop()
 
-	counter, err := getNodes(clientSet, ng)
+	readyNodes, err := getNodes(clientSet, ng)
 	if err != nil {
 		return errors.Wrap(err, "listing nodes")
 	}
 
 	logger.Info("waiting for at least %d node(s) to become ready in %q", minSize, ng.NameString())
-	for {
+	// the nodes may already be ready, in which case no further event is guaranteed to be received
+	for readyNodes.Len() < minSize {
 		select {
 		case event, ok := <-watcher.ResultChan():
 			logger.Debug("event = %#v", event)
@@ -174,7 +174,6 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 				if node, ok := event.Object.(*corev1.Node); ok {
 					if isNodeReady(node) {
 						readyNodes.Insert(node.Name)
-						counter = readyNodes.Len()
 						logger.Debug("node %q is ready in %q", node.Name, ng.NameString())
 					} else {
 						logger.Debug("node %q seen in %q, but not ready yet", node.Name, ng.NameString())
@@ -185,10 +184,6 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 		case <-timeout:
 			return fmt.Errorf("timed out (after %s) waiting for at least %d nodes to join the cluster and become ready in %q", c.Provider.WaitTimeout(), minSize, ng.NameString())
 		}
-
-		if counter >= minSize {
-			break
-		}
 	}
 
 	if _, err = getNodes(clientSet, ng); err != nil {
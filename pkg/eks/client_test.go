@@ -1,12 +1,18 @@
 package eks_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5/fakes"
@@ -121,3 +127,80 @@ var _ = Describe("eks auth helpers", func() {
 		})
 	})
 })
+
+var _ = Describe("WaitForNodes", func() {
+	var (
+		ctl         *ClusterProvider
+		clientSet   *fake.Clientset
+		ng          *api.NodeGroup
+		waitTimeout time.Duration
+	)
+
+	newNode := func(name, nodeGroupName string, ready bool) *corev1.Node {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{api.NodeGroupNameLabel: nodeGroupName},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		waitTimeout = mockprovider.ProviderConfig.WaitTimeout
+		ctl = &ClusterProvider{
+			Provider: mockprovider.NewMockProvider(),
+			Status:   &ProviderStatus{},
+		}
+		clientSet = fake.NewSimpleClientset()
+		ng = api.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.MinSize = aws.Int(2)
+	})
+
+	AfterEach(func() {
+		mockprovider.ProviderConfig.WaitTimeout = waitTimeout
+	})
+
+	It("returns once the nodes are already ready", func() {
+		for _, node := range []*corev1.Node{newNode("node-1", "ng-1", true), newNode("node-2", "ng-1", true)} {
+			_, err := clientSet.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(ctl.WaitForNodes(clientSet, ng)).To(Succeed())
+	})
+
+	It("waits for the nodes to join the cluster and become ready", func() {
+		_, err := clientSet.CoreV1().Nodes().Create(context.TODO(), newNode("node-1", "ng-1", true), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(100 * time.Millisecond)
+			_, err := clientSet.CoreV1().Nodes().Create(context.TODO(), newNode("node-2", "ng-1", true), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}()
+		Expect(ctl.WaitForNodes(clientSet, ng)).To(Succeed())
+	})
+
+	It("times out when not enough nodes of the nodegroup are ready", func() {
+		mockprovider.ProviderConfig.WaitTimeout = 200 * time.Millisecond
+		for _, node := range []*corev1.Node{newNode("node-1", "ng-1", true), newNode("node-2", "ng-1", false), newNode("node-3", "ng-2", true)} {
+			_, err := clientSet.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		err := ctl.WaitForNodes(clientSet, ng)
+		Expect(err).To(MatchError(`timed out (after 200ms) waiting for at least 2 nodes to join the cluster and become ready in "ng-1"`))
+	})
+
+	It("does not wait for nodegroups without a minimum size", func() {
+		ng.MinSize = aws.Int(0)
+		Expect(ctl.WaitForNodes(clientSet, ng)).To(Succeed())
+		Expect(clientSet.Actions()).To(BeEmpty())
+	})
+})
@@ -4,6 +4,7 @@ package fakes
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
@@ -13,6 +14,18 @@ import (
 )
 
 type FakeNodeGroupInitialiser struct {
+	CheckMaxPodsStub        func(context.Context, *v1alpha5.ClusterConfig) error
+	checkMaxPodsMutex       sync.RWMutex
+	checkMaxPodsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1alpha5.ClusterConfig
+	}
+	checkMaxPodsReturns struct {
+		result1 error
+	}
+	checkMaxPodsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DoAllNodegroupStackTasksStub        func(*tasks.TaskTree, string, string) error
 	doAllNodegroupStackTasksMutex       sync.RWMutex
 	doAllNodegroupStackTasksArgsForCall []struct {
@@ -41,6 +54,18 @@ type FakeNodeGroupInitialiser struct {
 		result1 bool
 		result2 error
 	}
+	EnsureServiceLinkedRolesStub        func(context.Context, *v1alpha5.ClusterConfig) error
+	ensureServiceLinkedRolesMutex       sync.RWMutex
+	ensureServiceLinkedRolesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1alpha5.ClusterConfig
+	}
+	ensureServiceLinkedRolesReturns struct {
+		result1 error
+	}
+	ensureServiceLinkedRolesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ExpandInstanceSelectorOptionsStub        func([]v1alpha5.NodePool, []string) error
 	expandInstanceSelectorOptionsMutex       sync.RWMutex
 	expandInstanceSelectorOptionsArgsForCall []struct {
@@ -58,12 +83,13 @@ type FakeNodeGroupInitialiser struct {
 	newAWSSelectorSessionArgsForCall []struct {
 		arg1 v1alpha5.ClusterProvider
 	}
-	NormalizeStub        func(context.Context, []v1alpha5.NodePool, *v1alpha5.ClusterMeta) error
+	NormalizeStub        func(context.Context, []v1alpha5.NodePool, *v1alpha5.ClusterMeta, time.Duration) error
 	normalizeMutex       sync.RWMutex
 	normalizeArgsForCall []struct {
 		arg1 context.Context
 		arg2 []v1alpha5.NodePool
 		arg3 *v1alpha5.ClusterMeta
+		arg4 time.Duration
 	}
 	normalizeReturns struct {
 		result1 error
@@ -100,6 +126,68 @@ type FakeNodeGroupInitialiser struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeNodeGroupInitialiser) CheckMaxPods(arg1 context.Context, arg2 *v1alpha5.ClusterConfig) error {
+	fake.checkMaxPodsMutex.Lock()
+	ret, specificReturn := fake.checkMaxPodsReturnsOnCall[len(fake.checkMaxPodsArgsForCall)]
+	fake.checkMaxPodsArgsForCall = append(fake.checkMaxPodsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1alpha5.ClusterConfig
+	}{arg1, arg2})
+	stub := fake.CheckMaxPodsStub
+	fakeReturns := fake.checkMaxPodsReturns
+	fake.recordInvocation("CheckMaxPods", []interface{}{arg1, arg2})
+	fake.checkMaxPodsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeNodeGroupInitialiser) CheckMaxPodsCallCount() int {
+	fake.checkMaxPodsMutex.RLock()
+	defer fake.checkMaxPodsMutex.RUnlock()
+	return len(fake.checkMaxPodsArgsForCall)
+}
+
+func (fake *FakeNodeGroupInitialiser) CheckMaxPodsCalls(stub func(context.Context, *v1alpha5.ClusterConfig) error) {
+	fake.checkMaxPodsMutex.Lock()
+	defer fake.checkMaxPodsMutex.Unlock()
+	fake.CheckMaxPodsStub = stub
+}
+
+func (fake *FakeNodeGroupInitialiser) CheckMaxPodsArgsForCall(i int) (context.Context, *v1alpha5.ClusterConfig) {
+	fake.checkMaxPodsMutex.RLock()
+	defer fake.checkMaxPodsMutex.RUnlock()
+	argsForCall := fake.checkMaxPodsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNodeGroupInitialiser) CheckMaxPodsReturns(result1 error) {
+	fake.checkMaxPodsMutex.Lock()
+	defer fake.checkMaxPodsMutex.Unlock()
+	fake.CheckMaxPodsStub = nil
+	fake.checkMaxPodsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeNodeGroupInitialiser) CheckMaxPodsReturnsOnCall(i int, result1 error) {
+	fake.checkMaxPodsMutex.Lock()
+	defer fake.checkMaxPodsMutex.Unlock()
+	fake.CheckMaxPodsStub = nil
+	if fake.checkMaxPodsReturnsOnCall == nil {
+		fake.checkMaxPodsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.checkMaxPodsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeNodeGroupInitialiser) DoAllNodegroupStackTasks(arg1 *tasks.TaskTree, arg2 string, arg3 string) error {
 	fake.doAllNodegroupStackTasksMutex.Lock()
 	ret, specificReturn := fake.doAllNodegroupStackTasksReturnsOnCall[len(fake.doAllNodegroupStackTasksArgsForCall)]
@@ -229,6 +317,68 @@ func (fake *FakeNodeGroupInitialiser) DoesAWSNodeUseIRSAReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
+func (fake *FakeNodeGroupInitialiser) EnsureServiceLinkedRoles(arg1 context.Context, arg2 *v1alpha5.ClusterConfig) error {
+	fake.ensureServiceLinkedRolesMutex.Lock()
+	ret, specificReturn := fake.ensureServiceLinkedRolesReturnsOnCall[len(fake.ensureServiceLinkedRolesArgsForCall)]
+	fake.ensureServiceLinkedRolesArgsForCall = append(fake.ensureServiceLinkedRolesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1alpha5.ClusterConfig
+	}{arg1, arg2})
+	stub := fake.EnsureServiceLinkedRolesStub
+	fakeReturns := fake.ensureServiceLinkedRolesReturns
+	fake.recordInvocation("EnsureServiceLinkedRoles", []interface{}{arg1, arg2})
+	fake.ensureServiceLinkedRolesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeNodeGroupInitialiser) EnsureServiceLinkedRolesCallCount() int {
+	fake.ensureServiceLinkedRolesMutex.RLock()
+	defer fake.ensureServiceLinkedRolesMutex.RUnlock()
+	return len(fake.ensureServiceLinkedRolesArgsForCall)
+}
+
+func (fake *FakeNodeGroupInitialiser) EnsureServiceLinkedRolesCalls(stub func(context.Context, *v1alpha5.ClusterConfig) error) {
+	fake.ensureServiceLinkedRolesMutex.Lock()
+	defer fake.ensureServiceLinkedRolesMutex.Unlock()
+	fake.EnsureServiceLinkedRolesStub = stub
+}
+
+func (fake *FakeNodeGroupInitialiser) EnsureServiceLinkedRolesArgsForCall(i int) (context.Context, *v1alpha5.ClusterConfig) {
+	fake.ensureServiceLinkedRolesMutex.RLock()
+	defer fake.ensureServiceLinkedRolesMutex.RUnlock()
+	argsForCall := fake.ensureServiceLinkedRolesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNodeGroupInitialiser) EnsureServiceLinkedRolesReturns(result1 error) {
+	fake.ensureServiceLinkedRolesMutex.Lock()
+	defer fake.ensureServiceLinkedRolesMutex.Unlock()
+	fake.EnsureServiceLinkedRolesStub = nil
+	fake.ensureServiceLinkedRolesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeNodeGroupInitialiser) EnsureServiceLinkedRolesReturnsOnCall(i int, result1 error) {
+	fake.ensureServiceLinkedRolesMutex.Lock()
+	defer fake.ensureServiceLinkedRolesMutex.Unlock()
+	fake.EnsureServiceLinkedRolesStub = nil
+	if fake.ensureServiceLinkedRolesReturnsOnCall == nil {
+		fake.ensureServiceLinkedRolesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.ensureServiceLinkedRolesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeNodeGroupInitialiser) ExpandInstanceSelectorOptions(arg1 []v1alpha5.NodePool, arg2 []string) error {
 	var arg1Copy []v1alpha5.NodePool
 	if arg1 != nil {
@@ -333,7 +483,7 @@ func (fake *FakeNodeGroupInitialiser) NewAWSSelectorSessionArgsForCall(i int) v1
 	return argsForCall.arg1
 }
 
-func (fake *FakeNodeGroupInitialiser) Normalize(arg1 context.Context, arg2 []v1alpha5.NodePool, arg3 *v1alpha5.ClusterMeta) error {
+func (fake *FakeNodeGroupInitialiser) Normalize(arg1 context.Context, arg2 []v1alpha5.NodePool, arg3 *v1alpha5.ClusterMeta, arg4 time.Duration) error {
 	var arg2Copy []v1alpha5.NodePool
 	if arg2 != nil {
 		arg2Copy = make([]v1alpha5.NodePool, len(arg2))
@@ -345,13 +495,14 @@ func (fake *FakeNodeGroupInitialiser) Normalize(arg1 context.Context, arg2 []v1a
 		arg1 context.Context
 		arg2 []v1alpha5.NodePool
 		arg3 *v1alpha5.ClusterMeta
-	}{arg1, arg2Copy, arg3})
+		arg4 time.Duration
+	}{arg1, arg2Copy, arg3, arg4})
 	stub := fake.NormalizeStub
 	fakeReturns := fake.normalizeReturns
-	fake.recordInvocation("Normalize", []interface{}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("Normalize", []interface{}{arg1, arg2Copy, arg3, arg4})
 	fake.normalizeMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -365,17 +516,17 @@ func (fake *FakeNodeGroupInitialiser) NormalizeCallCount() int {
 	return len(fake.normalizeArgsForCall)
 }
 
-func (fake *FakeNodeGroupInitialiser) NormalizeCalls(stub func(context.Context, []v1alpha5.NodePool, *v1alpha5.ClusterMeta) error) {
+func (fake *FakeNodeGroupInitialiser) NormalizeCalls(stub func(context.Context, []v1alpha5.NodePool, *v1alpha5.ClusterMeta, time.Duration) error) {
 	fake.normalizeMutex.Lock()
 	defer fake.normalizeMutex.Unlock()
 	fake.NormalizeStub = stub
 }
 
-func (fake *FakeNodeGroupInitialiser) NormalizeArgsForCall(i int) (context.Context, []v1alpha5.NodePool, *v1alpha5.ClusterMeta) {
+func (fake *FakeNodeGroupInitialiser) NormalizeArgsForCall(i int) (context.Context, []v1alpha5.NodePool, *v1alpha5.ClusterMeta, time.Duration) {
 	fake.normalizeMutex.RLock()
 	defer fake.normalizeMutex.RUnlock()
 	argsForCall := fake.normalizeArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeNodeGroupInitialiser) NormalizeReturns(result1 error) {
@@ -529,10 +680,14 @@ func (fake *FakeNodeGroupInitialiser) ValidateLegacySubnetsForNodeGroupsReturnsO
 func (fake *FakeNodeGroupInitialiser) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkMaxPodsMutex.RLock()
+	defer fake.checkMaxPodsMutex.RUnlock()
 	fake.doAllNodegroupStackTasksMutex.RLock()
 	defer fake.doAllNodegroupStackTasksMutex.RUnlock()
 	fake.doesAWSNodeUseIRSAMutex.RLock()
 	defer fake.doesAWSNodeUseIRSAMutex.RUnlock()
+	fake.ensureServiceLinkedRolesMutex.RLock()
+	defer fake.ensureServiceLinkedRolesMutex.RUnlock()
 	fake.expandInstanceSelectorOptionsMutex.RLock()
 	defer fake.expandInstanceSelectorOptionsMutex.RUnlock()
 	fake.newAWSSelectorSessionMutex.RLock()
@@ -0,0 +1,61 @@
+package eks
+
+import (
+	"context"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
+)
+
+// lowMaxPods is the number of pods per node below which the VPC CNI is likely to prevent typical workloads, along
+// with the daemonsets running on every node, from being scheduled
+const lowMaxPods = 20
+
+// CheckMaxPods warns about the nodegroups in spec whose instance types only allow the VPC CNI to run a handful of
+// pods per node. The max pods are computed from the network interface limits of the instance types, using prefix
+// delegation in IPv6 clusters. Nodegroups that set maxPodsPerNode and Windows nodegroups are not checked
+func (m *NodeGroupService) CheckMaxPods(ctx context.Context, spec *api.ClusterConfig) error {
+	prefixDelegation := spec.IPv6Enabled()
+	for _, ng := range spec.NodeGroups {
+		if err := m.checkNodeGroupMaxPods(ctx, ng.NodeGroupBase, ng.InstanceTypeList(), prefixDelegation); err != nil {
+			return err
+		}
+	}
+	for _, ng := range spec.ManagedNodeGroups {
+		if err := m.checkNodeGroupMaxPods(ctx, ng.NodeGroupBase, ng.InstanceTypeList(), prefixDelegation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *NodeGroupService) checkNodeGroupMaxPods(ctx context.Context, ng *api.NodeGroupBase, instanceTypes []string, prefixDelegation bool) error {
+	if ng.MaxPodsPerNode != 0 || api.IsWindowsImage(ng.AMIFamily) {
+		return nil
+	}
+	var knownInstanceTypes []string
+	for _, instanceType := range instanceTypes {
+		// the instance types selected by instanceRequirements are not known
+		if instanceType != "" {
+			knownInstanceTypes = append(knownInstanceTypes, instanceType)
+		}
+	}
+	if len(knownInstanceTypes) == 0 {
+		return nil
+	}
+
+	info, err := instancetypes.InfoList(ctx, m.Provider.EC2(), knownInstanceTypes)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't retrieve instance type description for %v", knownInstanceTypes)
+	}
+	for _, it := range info {
+		if maxPods := it.MaxPods(prefixDelegation); maxPods > 0 && maxPods < lowMaxPods {
+			logger.Warning("nodes of nodegroup %q with instance type %s can only run %d pods, as the VPC CNI is limited to %d network interfaces with %d IPv4 addresses each; consider a larger instance type, or enabling prefix delegation in the VPC CNI and setting maxPodsPerNode",
+				ng.Name, it.InstanceType, maxPods, it.MaxENIs, it.MaxIPv4AddressesPerENI)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package eks_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kris-nova/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Max pods", func() {
+	var (
+		p             *mockprovider.MockProvider
+		clusterConfig *api.ClusterConfig
+		output        *bytes.Buffer
+		loggerWriter  io.Writer
+		loggerLevel   int
+	)
+
+	mockDescribeInstanceTypes := func(instanceType ec2types.InstanceType, maxENIs, addressesPerENI int32) {
+		p.MockEC2().On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: []ec2types.InstanceType{instanceType},
+		}).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []ec2types.InstanceTypeInfo{
+				{
+					InstanceType: instanceType,
+					NetworkInfo: &ec2types.NetworkInfo{
+						MaximumNetworkInterfaces:  aws.Int32(maxENIs),
+						Ipv4AddressesPerInterface: aws.Int32(addressesPerENI),
+					},
+					VCpuInfo: &ec2types.VCpuInfo{
+						DefaultVCpus: aws.Int32(2),
+					},
+				},
+			},
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		clusterConfig = api.NewClusterConfig()
+		output = &bytes.Buffer{}
+		loggerWriter = logger.Writer
+		logger.Writer = output
+		loggerLevel = logger.Level
+		logger.Level = 3
+	})
+
+	AfterEach(func() {
+		logger.Writer = loggerWriter
+		logger.Level = loggerLevel
+	})
+
+	It("warns about nodegroups whose instance type can only run a few pods", func() {
+		mockDescribeInstanceTypes(ec2types.InstanceTypeT3Small, 3, 4)
+		ng := clusterConfig.NewNodeGroup()
+		ng.Name = "small"
+		ng.InstanceType = "t3.small"
+
+		Expect(eks.NewNodeGroupService(p, nil).CheckMaxPods(context.Background(), clusterConfig)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring(`nodes of nodegroup "small" with instance type t3.small can only run 11 pods, as the VPC CNI is limited to 3 network interfaces with 4 IPv4 addresses each`))
+	})
+
+	It("does not warn about managed nodegroups whose instance type can run enough pods", func() {
+		mockDescribeInstanceTypes(ec2types.InstanceTypeM5Large, 3, 10)
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "large"
+		mng.InstanceType = "m5.large"
+		clusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{mng}
+
+		Expect(eks.NewNodeGroupService(p, nil).CheckMaxPods(context.Background(), clusterConfig)).To(Succeed())
+		Expect(output.String()).To(BeEmpty())
+	})
+
+	It("uses prefix delegation in IPv6 clusters", func() {
+		mockDescribeInstanceTypes(ec2types.InstanceTypeT3Small, 3, 4)
+		clusterConfig.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{IPFamily: api.IPV6Family}
+		ng := clusterConfig.NewNodeGroup()
+		ng.InstanceType = "t3.small"
+
+		Expect(eks.NewNodeGroupService(p, nil).CheckMaxPods(context.Background(), clusterConfig)).To(Succeed())
+		Expect(output.String()).To(BeEmpty())
+	})
+
+	It("does not check nodegroups that set maxPodsPerNode", func() {
+		ng := clusterConfig.NewNodeGroup()
+		ng.InstanceType = "t3.small"
+		ng.MaxPodsPerNode = 110
+
+		Expect(eks.NewNodeGroupService(p, nil).CheckMaxPods(context.Background(), clusterConfig)).To(Succeed())
+		Expect(p.MockEC2().AssertNotCalled(GinkgoT(), "DescribeInstanceTypes", mock.Anything, mock.Anything)).To(BeTrue())
+	})
+})
@@ -15,6 +15,7 @@ import (
 	addons "github.com/weaveworks/eksctl/pkg/addons/default"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
 	"github.com/weaveworks/eksctl/pkg/iam"
 	"github.com/weaveworks/eksctl/pkg/utils/tasks"
 
@@ -23,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -35,22 +37,23 @@ func isNodeReady(node *corev1.Node) bool {
 	return false
 }
 
-func getNodes(clientSet kubernetes.Interface, ng KubeNodeGroup) (int, error) {
+// getNodes logs the nodes of ng and returns the names of the ones that are ready
+func getNodes(clientSet kubernetes.Interface, ng KubeNodeGroup) (sets.String, error) {
 	nodes, err := clientSet.CoreV1().Nodes().List(context.TODO(), ng.ListOptions())
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	logger.Info("nodegroup %q has %d node(s)", ng.NameString(), len(nodes.Items))
-	counter := 0
+	readyNodes := sets.NewString()
 	for _, node := range nodes.Items {
 		ready := "not ready"
 		if isNodeReady(&node) {
 			ready = "ready"
-			counter++
+			readyNodes.Insert(node.Name)
 		}
 		logger.Info("node %q is %s", node.ObjectMeta.Name, ready)
 	}
-	return counter, nil
+	return readyNodes, nil
 }
 
 // SupportsWindowsWorkloads reports whether nodeGroups can support running Windows workloads
@@ -71,7 +74,7 @@ func hasWindowsNode(nodeGroups []KubeNodeGroup) bool {
 // hasAmazonLinux2Node reports whether there's at least one Windows node in nodeGroups
 func hasAmazonLinux2Node(nodeGroups []KubeNodeGroup) bool {
 	for _, ng := range nodeGroups {
-		if ng.GetAMIFamily() == api.NodeImageFamilyAmazonLinux2 {
+		if ng.GetAMIFamily() == api.NodeImageFamilyAmazonLinux2 || ng.GetAMIFamily() == api.NodeImageFamilyAmazonLinux2023 {
 			return true
 		}
 	}
@@ -103,8 +106,14 @@ type KubeNodeGroup interface {
 	GetAMIFamily() string
 }
 
-// GetNodeGroupIAM retrieves the IAM configuration of the given nodegroup
+// GetNodeGroupIAM retrieves the IAM configuration of the given nodegroup. An instance role ARN that is already set,
+// e.g. provided in the config file, is kept. Otherwise it is read from the InstanceRoleARN output of the nodegroup
+// stack, or from the role resource of the stack when it has no such output, as is the case for adopted stacks
 func (c *ClusterProvider) GetNodeGroupIAM(stackManager manager.StackManager, ng *api.NodeGroup) error {
+	if ng.IAM != nil && ng.IAM.InstanceRoleARN != "" {
+		return nil
+	}
+
 	stacks, err := stackManager.DescribeNodeGroupStacks()
 	if err != nil {
 		return err
@@ -112,6 +121,18 @@ func (c *ClusterProvider) GetNodeGroupIAM(stackManager manager.StackManager, ng
 
 	for _, s := range stacks {
 		if stackManager.GetNodeGroupName(s) == ng.Name {
+			if !outputs.Exists(*s, outputs.NodeGroupInstanceRoleARN) {
+				if ng.IAM == nil {
+					ng.IAM = &api.NodeGroupIAM{}
+				}
+				roleARN, err := stackManager.GetNodeGroupInstanceRoleARN(s)
+				if err != nil {
+					return errors.Wrapf(err, "couldn't get iam configuration for nodegroup %q", ng.Name)
+				}
+				ng.IAM.InstanceRoleARN = roleARN
+				return nil
+			}
+
 			err := iam.UseFromNodeGroup(s, ng)
 			// An empty InstanceRoleARN likely also points to an error
 			if err == nil && ng.IAM.InstanceRoleARN == "" {
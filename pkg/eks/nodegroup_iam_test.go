@@ -0,0 +1,69 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("GetNodeGroupIAM", func() {
+	const roleARN = "arn:aws:iam::123456789012:role/eksctl-test-nodegroup-ng-1-NodeInstanceRole-ABC"
+
+	var (
+		ctl          *ClusterProvider
+		stackManager *fakes.FakeStackManager
+		ng           *api.NodeGroup
+		stack        *cfn.Stack
+	)
+
+	BeforeEach(func() {
+		ctl = &ClusterProvider{
+			Provider: mockprovider.NewMockProvider(),
+			Status:   &ProviderStatus{},
+		}
+		ng = api.NewNodeGroup()
+		ng.Name = "ng-1"
+		stack = &cfn.Stack{
+			StackName:   aws.String("eksctl-test-nodegroup-ng-1"),
+			StackStatus: aws.String(cfn.StackStatusCreateComplete),
+		}
+
+		stackManager = &fakes.FakeStackManager{}
+		stackManager.DescribeNodeGroupStacksReturns([]*cfn.Stack{stack}, nil)
+		stackManager.GetNodeGroupNameReturns("ng-1")
+	})
+
+	It("reads the instance role ARN from the stack outputs", func() {
+		stack.Outputs = []*cfn.Output{
+			{
+				OutputKey:   aws.String(outputs.NodeGroupInstanceRoleARN),
+				OutputValue: aws.String(roleARN),
+			},
+		}
+		Expect(ctl.GetNodeGroupIAM(stackManager, ng)).To(Succeed())
+		Expect(ng.IAM.InstanceRoleARN).To(Equal(roleARN))
+		Expect(stackManager.GetNodeGroupInstanceRoleARNCallCount()).To(Equal(0))
+	})
+
+	It("falls back to the role resource of stacks without the InstanceRoleARN output", func() {
+		stackManager.GetNodeGroupInstanceRoleARNReturns(roleARN, nil)
+		Expect(ctl.GetNodeGroupIAM(stackManager, ng)).To(Succeed())
+		Expect(ng.IAM.InstanceRoleARN).To(Equal(roleARN))
+		Expect(stackManager.GetNodeGroupInstanceRoleARNCallCount()).To(Equal(1))
+		Expect(stackManager.GetNodeGroupInstanceRoleARNArgsForCall(0)).To(Equal(stack))
+	})
+
+	It("keeps an instance role ARN provided in the config", func() {
+		ng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/external"
+		Expect(ctl.GetNodeGroupIAM(stackManager, ng)).To(Succeed())
+		Expect(ng.IAM.InstanceRoleARN).To(Equal("arn:aws:iam::123456789012:role/external"))
+		Expect(stackManager.DescribeNodeGroupStacksCallCount()).To(Equal(0))
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/aws/amazon-ec2-instance-selector/v2/pkg/bytequantity"
 	"github.com/aws/amazon-ec2-instance-selector/v2/pkg/selector"
@@ -37,13 +38,15 @@ type InstanceSelector interface {
 //counterfeiter:generate -o fakes/fake_nodegroup_initialiser.go . NodeGroupInitialiser
 // NodeGroupInitialiser is an interface that provides helpers for nodegroup creation.
 type NodeGroupInitialiser interface {
-	Normalize(ctx context.Context, nodePools []api.NodePool, clusterMeta *api.ClusterMeta) error
+	Normalize(ctx context.Context, nodePools []api.NodePool, clusterMeta *api.ClusterMeta, amiMaxAge time.Duration) error
 	ExpandInstanceSelectorOptions(nodePools []api.NodePool, clusterAZs []string) error
 	NewAWSSelectorSession(provider api.ClusterProvider)
 	ValidateLegacySubnetsForNodeGroups(ctx context.Context, spec *api.ClusterConfig, provider api.ClusterProvider) error
+	CheckMaxPods(ctx context.Context, spec *api.ClusterConfig) error
 	DoesAWSNodeUseIRSA(ctx context.Context, provider api.ClusterProvider, clientSet kubernetes.Interface) (bool, error)
 	DoAllNodegroupStackTasks(taskTree *tasks.TaskTree, region, name string) error
 	ValidateExistingNodeGroupsForCompatibility(cfg *api.ClusterConfig, stackManager manager.StackManager) error
+	EnsureServiceLinkedRoles(ctx context.Context, spec *api.ClusterConfig) error
 }
 
 // A NodeGroupService provides helpers for nodegroup creation
@@ -67,21 +70,23 @@ func (m *NodeGroupService) NewAWSSelectorSession(provider api.ClusterProvider) {
 	m.instanceSelector = selector.New(provider.Session())
 }
 
-// Normalize normalizes nodegroups
-func (m *NodeGroupService) Normalize(ctx context.Context, nodePools []api.NodePool, clusterMeta *api.ClusterMeta) error {
+// Normalize normalizes nodegroups. The AMIs it resolves are verified with amiMaxAge, see ami.VerifyAMI, while
+// explicitly specified AMIs are used as is
+func (m *NodeGroupService) Normalize(ctx context.Context, nodePools []api.NodePool, clusterMeta *api.ClusterMeta, amiMaxAge time.Duration) error {
 	for _, np := range nodePools {
 		switch ng := np.(type) {
 		case *api.ManagedNodeGroup:
 			hasNativeAMIFamilySupport := ng.AMIFamily == api.NodeImageFamilyAmazonLinux2 || ng.AMIFamily == api.NodeImageFamilyBottlerocket
 			if !hasNativeAMIFamilySupport && !api.IsAMI(ng.AMI) {
-				if err := ResolveAMI(ctx, m.Provider, clusterMeta.Version, np); err != nil {
+				if err := m.resolveAndVerifyAMI(ctx, clusterMeta.Version, np, amiMaxAge); err != nil {
 					return err
 				}
 			}
 
 		case *api.NodeGroup:
-			if !api.IsAMI(ng.AMI) {
-				if err := ResolveAMI(ctx, m.Provider, clusterMeta.Version, ng); err != nil {
+			// the AMI of a nodegroup using an existing launch template is set by the launch template
+			if ng.LaunchTemplate == nil && !api.IsAMI(ng.AMI) {
+				if err := m.resolveAndVerifyAMI(ctx, clusterMeta.Version, ng, amiMaxAge); err != nil {
 					return err
 				}
 			}
@@ -96,17 +101,23 @@ func (m *NodeGroupService) Normalize(ctx context.Context, nodePools []api.NodePo
 				return err
 			}
 		}
-		// load or use SSH key - name includes cluster name and the
-		// fingerprint, so if unique keys are provided, each will get
-		// loaded and used as intended and there is no need to have
-		// nodegroup name in the key name
-		publicKeyName, err := ssh.LoadKey(ctx, ng.SSH, clusterMeta.Name, ng.Name, m.Provider.EC2())
-		if err != nil {
+		// reference an existing SSH key pair or set the key to be imported
+		// by the nodegroup stack - its name includes the cluster name, the
+		// nodegroup name and the fingerprint, so each nodegroup gets its own
+		// key pair, which is deleted along with the nodegroup
+		if err := ssh.LoadKey(ctx, ng.SSH, clusterMeta.Name, ng.Name, m.Provider.EC2()); err != nil {
 			return err
 		}
-		if publicKeyName != "" {
-			ng.SSH.PublicKeyName = &publicKeyName
-		}
+	}
+	return nil
+}
+
+func (m *NodeGroupService) resolveAndVerifyAMI(ctx context.Context, version string, np api.NodePool, amiMaxAge time.Duration) error {
+	if err := ResolveAMI(ctx, m.Provider, version, np); err != nil {
+		return err
+	}
+	if err := ami.VerifyAMI(ctx, m.Provider.EC2(), np.BaseNodeGroup().AMI, amiMaxAge); err != nil {
+		return fmt.Errorf("%w; to use it anyway, please run again with --ami-max-age=0", err)
 	}
 	return nil
 }
@@ -130,6 +141,9 @@ func (m *NodeGroupService) ExpandInstanceSelectorOptions(nodePools []api.NodePoo
 		azs := clusterAZs
 		if len(baseNG.AvailabilityZones) != 0 {
 			azs = baseNG.AvailabilityZones
+		} else if len(baseNG.AvailabilityZoneIDs) != 0 {
+			// the instance selector accepts zone IDs as well as zone names
+			azs = baseNG.AvailabilityZoneIDs
 		}
 		instanceTypes, err := m.expandInstanceSelector(baseNG.InstanceSelector, azs)
 		if err != nil {
@@ -245,6 +259,10 @@ func (m *NodeGroupService) ValidateExistingNodeGroupsForCompatibility(cfg *api.C
 	if len(infoByNodeGroup) == 0 {
 		return nil
 	}
+	if cfg.IsSharedNodeSecurityGroupDisabled() {
+		logger.Debug("the shared node security group is disabled, skipping the security group check of existing nodegroups")
+		return nil
+	}
 
 	logger.Info("checking security group configuration for all nodegroups")
 	incompatibleNodeGroups := []string{}
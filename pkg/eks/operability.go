@@ -0,0 +1,192 @@
+package eks
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+// OperabilityIssue identifies why the Kubernetes API of a cluster cannot be operated
+type OperabilityIssue string
+
+// Values for `OperabilityIssue`
+const (
+	// OperabilityIssueClusterNotActive means the status of the cluster does not allow Kubernetes API operations
+	OperabilityIssueClusterNotActive OperabilityIssue = "ClusterNotActive"
+	// OperabilityIssueEndpointUnreachable means no connection could be established to the Kubernetes API endpoint
+	OperabilityIssueEndpointUnreachable OperabilityIssue = "EndpointUnreachable"
+	// OperabilityIssueAuthenticationFailed means the Kubernetes API did not accept the credentials of the current session
+	OperabilityIssueAuthenticationFailed OperabilityIssue = "AuthenticationFailed"
+)
+
+// OperabilityReport is the result of an operability check
+type OperabilityReport struct {
+	ClusterName string
+	// Issue is empty when the cluster can be operated
+	Issue OperabilityIssue
+	// Reason describes what went wrong
+	Reason string
+	// Remediation describes how to resolve the issue
+	Remediation string
+	// Warnings describe missing RBAC permissions, which make some operations, e.g. draining nodegroups, fail
+	// without preventing the others
+	Warnings []string
+}
+
+// Operable returns true when no issue was found
+func (r *OperabilityReport) Operable() bool {
+	return r.Issue == ""
+}
+
+func (r *OperabilityReport) String() string {
+	if r.Operable() {
+		return fmt.Sprintf("the Kubernetes API of cluster %q is reachable and accepts the credentials of the current session", r.ClusterName)
+	}
+	return fmt.Sprintf("cannot perform Kubernetes API operations on cluster %q (%s): %s; %s", r.ClusterName, r.Issue, r.Reason, r.Remediation)
+}
+
+// requiredPermission is a Kubernetes API permission that eksctl needs to operate on nodegroups
+type requiredPermission struct {
+	verb, resource, subresource string
+}
+
+func (p requiredPermission) String() string {
+	resource := p.resource
+	if p.subresource != "" {
+		resource += "/" + p.subresource
+	}
+	return fmt.Sprintf("%s %s", p.verb, resource)
+}
+
+// requiredPermissions are the permissions needed to drain nodegroups
+var requiredPermissions = []requiredPermission{
+	{verb: "list", resource: "nodes"},
+	{verb: "patch", resource: "nodes"},
+	{verb: "list", resource: "pods"},
+	{verb: "create", resource: "pods", subresource: "eviction"},
+}
+
+// CheckOperability checks whether the Kubernetes API of the cluster can be operated with the identity of the
+// current session, connecting to it with newClientSet
+func (c *ClusterProvider) CheckOperability(ctx context.Context, spec *api.ClusterConfig, newClientSet func() (kubewrapper.Interface, error)) *OperabilityReport {
+	var cluster *awseks.Cluster
+	if c.Status.ClusterInfo != nil {
+		cluster = c.Status.ClusterInfo.Cluster
+	}
+	return CheckOperability(ctx, spec.Metadata.Name, cluster, c.Status.iamRoleARN, newClientSet)
+}
+
+// CheckOperability checks, in order, that the status of the cluster allows Kubernetes API operations, that its
+// endpoint can be reached and that it accepts the credentials of the identity callerARN. RBAC permissions needed
+// to drain nodegroups that are not granted are reported as warnings, since they do not prevent other operations
+func CheckOperability(ctx context.Context, clusterName string, cluster *awseks.Cluster, callerARN string, newClientSet func() (kubewrapper.Interface, error)) *OperabilityReport {
+	report := &OperabilityReport{ClusterName: clusterName}
+
+	if cluster == nil {
+		report.Issue = OperabilityIssueClusterNotActive
+		report.Reason = "cluster info not available"
+		report.Remediation = fmt.Sprintf("check that the cluster exists with `eksctl get cluster --name %s`", clusterName)
+		return report
+	}
+	switch status := aws.StringValue(cluster.Status); status {
+	case awseks.ClusterStatusCreating, awseks.ClusterStatusDeleting, awseks.ClusterStatusFailed:
+		report.Issue = OperabilityIssueClusterNotActive
+		report.Reason = fmt.Sprintf("cluster status is %q", status)
+		if status == awseks.ClusterStatusFailed {
+			report.Remediation = "check the health of the cluster in the EKS console"
+		} else {
+			report.Remediation = "wait for the cluster to become ACTIVE and retry"
+		}
+		return report
+	}
+
+	identity := callerARN
+	if identity == "" {
+		identity = "the current identity"
+	}
+
+	clientSet, err := newClientSet()
+	if err != nil {
+		report.Issue = OperabilityIssueAuthenticationFailed
+		report.Reason = fmt.Sprintf("creating Kubernetes client: %v", err)
+		report.Remediation = "check the AWS credentials of the current session"
+		return report
+	}
+
+	var missing []string
+	for _, permission := range requiredPermissions {
+		review, err := clientSet.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:        permission.verb,
+					Resource:    permission.resource,
+					Subresource: permission.subresource,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if apierrors.IsForbidden(err) {
+			// the request was authenticated, so the cluster can be operated
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s is not allowed to review its own permissions: %v", identity, err))
+			return report
+		}
+		if err != nil {
+			describeAPIError(report, err, clusterName, identity)
+			return report
+		}
+		if !review.Status.Allowed {
+			missing = append(missing, permission.String())
+		}
+	}
+
+	if len(missing) > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%s is not allowed to %s, which may be needed to drain nodegroups; map it to a Kubernetes group with these permissions, e.g. with `eksctl create iamidentitymapping --cluster %s --arn <arn> --group <group>`",
+			identity, strings.Join(missing, ", "), clusterName))
+	}
+	return report
+}
+
+// describeAPIError sets the issue, reason and remediation of report from an error returned by the Kubernetes API
+func describeAPIError(report *OperabilityReport, err error, clusterName, identity string) {
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	switch {
+	case apierrors.IsUnauthorized(err):
+		report.Issue = OperabilityIssueAuthenticationFailed
+		report.Reason = fmt.Sprintf("%s is not authorized to access the cluster", identity)
+		report.Remediation = fmt.Sprintf("ask an administrator of the cluster to map %s in the aws-auth ConfigMap, e.g. with `eksctl create iamidentitymapping --cluster %s --arn <arn>`, or to create an access entry for it", identity, clusterName)
+	case errors.As(err, &dnsErr):
+		report.Issue = OperabilityIssueEndpointUnreachable
+		report.Reason = fmt.Sprintf("the endpoint hostname could not be resolved: %v", dnsErr)
+		report.Remediation = "if the cluster only allows private access to its endpoint, run eksctl from a network with access to the cluster's VPC and DNS resolution, or enable public access with `eksctl utils update-cluster-endpoints`"
+	case errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		report.Issue = OperabilityIssueEndpointUnreachable
+		report.Reason = fmt.Sprintf("the TLS certificate of the endpoint could not be verified: %v", err)
+		report.Remediation = "check that no proxy intercepts the connection to the endpoint (HTTPS_PROXY) and that the certificate authority in the kubeconfig matches the cluster"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		report.Issue = OperabilityIssueEndpointUnreachable
+		report.Reason = fmt.Sprintf("connecting to the endpoint timed out: %v", err)
+		report.Remediation = "check that the endpoint allows access from the current network: run eksctl from a network with access to the cluster's VPC if it only allows private access, or add the current IP address with `eksctl utils set-public-access-cidrs`"
+	default:
+		report.Issue = OperabilityIssueEndpointUnreachable
+		report.Reason = fmt.Sprintf("calling the Kubernetes API: %v", err)
+		report.Remediation = "check that the endpoint is reachable from the current network"
+	}
+}
@@ -0,0 +1,166 @@
+package eks_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+var _ = Describe("CheckOperability", func() {
+	const (
+		clusterName = "my-cluster"
+		callerARN   = "arn:aws:iam::123456789012:role/deployer"
+	)
+
+	var (
+		cluster   *awseks.Cluster
+		clientSet *fake.Clientset
+	)
+
+	BeforeEach(func() {
+		cluster = &awseks.Cluster{
+			Name:   aws.String(clusterName),
+			Status: aws.String(awseks.ClusterStatusActive),
+		}
+		clientSet = fake.NewSimpleClientset()
+	})
+
+	// reviewAccess makes SelfSubjectAccessReviews return allowed for the permissions accepted by allow, or err
+	reviewAccess := func(allow func(attributes *authorizationv1.ResourceAttributes) bool, err error) {
+		clientSet.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if err != nil {
+				return true, nil, err
+			}
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = allow(review.Spec.ResourceAttributes)
+			return true, review, nil
+		})
+	}
+
+	checkOperability := func() *eks.OperabilityReport {
+		return eks.CheckOperability(context.Background(), clusterName, cluster, callerARN, func() (kubernetes.Interface, error) {
+			return clientSet, nil
+		})
+	}
+
+	It("reports an operable cluster", func() {
+		reviewAccess(func(*authorizationv1.ResourceAttributes) bool { return true }, nil)
+
+		report := checkOperability()
+		Expect(report.Operable()).To(BeTrue())
+		Expect(report.Issue).To(BeEmpty())
+		Expect(report.Warnings).To(BeEmpty())
+	})
+
+	It("reports a cluster whose info is not available", func() {
+		cluster = nil
+
+		report := checkOperability()
+		Expect(report.Operable()).To(BeFalse())
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueClusterNotActive))
+		Expect(report.Reason).To(Equal("cluster info not available"))
+	})
+
+	It("reports a cluster that is not active", func() {
+		cluster.Status = aws.String(awseks.ClusterStatusCreating)
+
+		report := checkOperability()
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueClusterNotActive))
+		Expect(report.Reason).To(ContainSubstring(`"CREATING"`))
+		Expect(report.Remediation).To(ContainSubstring("wait for the cluster to become ACTIVE"))
+		Expect(clientSet.Actions()).To(BeEmpty())
+	})
+
+	It("reports a failed cluster", func() {
+		cluster.Status = aws.String(awseks.ClusterStatusFailed)
+
+		report := checkOperability()
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueClusterNotActive))
+		Expect(report.Remediation).To(ContainSubstring("EKS console"))
+	})
+
+	It("reports an endpoint whose hostname cannot be resolved", func() {
+		reviewAccess(nil, &url.Error{Op: "Post", URL: "https://private.eks.amazonaws.com", Err: &net.DNSError{Err: "no such host", Name: "private.eks.amazonaws.com", IsNotFound: true}})
+
+		report := checkOperability()
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueEndpointUnreachable))
+		Expect(report.Reason).To(ContainSubstring("could not be resolved"))
+		Expect(report.Remediation).To(ContainSubstring("private access"))
+	})
+
+	It("reports an endpoint that times out", func() {
+		reviewAccess(nil, &url.Error{Op: "Post", URL: "https://private.eks.amazonaws.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}})
+
+		report := checkOperability()
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueEndpointUnreachable))
+		Expect(report.Reason).To(ContainSubstring("timed out"))
+		Expect(report.Remediation).To(ContainSubstring("set-public-access-cidrs"))
+	})
+
+	It("reports an endpoint whose certificate cannot be verified", func() {
+		reviewAccess(nil, &url.Error{Op: "Post", URL: "https://private.eks.amazonaws.com", Err: x509.UnknownAuthorityError{}})
+
+		report := checkOperability()
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueEndpointUnreachable))
+		Expect(report.Reason).To(ContainSubstring("TLS certificate"))
+		Expect(report.Remediation).To(ContainSubstring("HTTPS_PROXY"))
+	})
+
+	It("reports credentials that the cluster does not accept", func() {
+		reviewAccess(nil, apierrors.NewUnauthorized("Unauthorized"))
+
+		report := checkOperability()
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueAuthenticationFailed))
+		Expect(report.Reason).To(ContainSubstring(callerARN))
+		Expect(report.Remediation).To(ContainSubstring("aws-auth"))
+		Expect(report.Remediation).To(ContainSubstring("eksctl create iamidentitymapping --cluster my-cluster"))
+	})
+
+	It("reports a failure to create the client", func() {
+		report := eks.CheckOperability(context.Background(), clusterName, cluster, callerARN, func() (kubernetes.Interface, error) {
+			return nil, errors.New("unable to generate token")
+		})
+		Expect(report.Issue).To(Equal(eks.OperabilityIssueAuthenticationFailed))
+		Expect(report.Reason).To(ContainSubstring("unable to generate token"))
+	})
+
+	It("reports missing RBAC permissions", func() {
+		reviewAccess(func(attributes *authorizationv1.ResourceAttributes) bool {
+			return attributes.Resource == "nodes" && attributes.Verb == "list"
+		}, nil)
+
+		report := checkOperability()
+		Expect(report.Operable()).To(BeTrue())
+		Expect(report.Warnings).To(ConsistOf(HavePrefix(callerARN + " is not allowed to patch nodes, list pods, create pods/eviction")))
+	})
+
+	It("reports an identity that cannot review its own permissions", func() {
+		reviewAccess(nil, apierrors.NewForbidden(schema.GroupResource{Group: "authorization.k8s.io", Resource: "selfsubjectaccessreviews"}, "", errors.New("denied")))
+
+		report := checkOperability()
+		Expect(report.Operable()).To(BeTrue())
+		Expect(report.Warnings).To(ConsistOf(ContainSubstring("is not allowed to review its own permissions")))
+	})
+})
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
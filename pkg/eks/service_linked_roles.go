@@ -0,0 +1,131 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+type serviceLinkedRole struct {
+	name        string
+	serviceName string
+}
+
+var (
+	autoScalingServiceLinkedRole = serviceLinkedRole{
+		name:        "AWSServiceRoleForAutoScaling",
+		serviceName: "autoscaling.amazonaws.com",
+	}
+	spotServiceLinkedRole = serviceLinkedRole{
+		name:        "AWSServiceRoleForEC2Spot",
+		serviceName: "spot.amazonaws.com",
+	}
+)
+
+// EnsureServiceLinkedRoles checks that the service-linked roles required by the nodegroups in spec exist,
+// creating the missing ones. If they cannot be created, it returns an error listing the commands to create them.
+// Roles that the current credentials are not permitted to look up are assumed to exist
+func (m *NodeGroupService) EnsureServiceLinkedRoles(ctx context.Context, spec *api.ClusterConfig) error {
+	var missingRoles []serviceLinkedRole
+	for _, role := range requiredServiceLinkedRoles(spec) {
+		exists, err := m.serviceLinkedRoleExists(ctx, role)
+		if err != nil {
+			if isAccessDenied(err) {
+				logger.Warning("unable to check whether service-linked role %q exists, assuming it does: %v", role.name, err)
+				continue
+			}
+			return err
+		}
+		if exists {
+			logger.Debug("service-linked role %q exists", role.name)
+			continue
+		}
+
+		logger.Info("creating service-linked role %q", role.name)
+		if _, err := m.Provider.IAM().CreateServiceLinkedRole(ctx, &iam.CreateServiceLinkedRoleInput{
+			AWSServiceName: aws.String(role.serviceName),
+		}); err != nil {
+			if isAccessDenied(err) {
+				missingRoles = append(missingRoles, role)
+				continue
+			}
+			return errors.Wrapf(err, "creating service-linked role %q", role.name)
+		}
+	}
+
+	if len(missingRoles) == 0 {
+		return nil
+	}
+
+	var commands []string
+	for _, role := range missingRoles {
+		commands = append(commands, fmt.Sprintf("aws iam create-service-linked-role --aws-service-name %s", role.serviceName))
+	}
+	return fmt.Errorf("the service-linked role(s) required by the nodegroups do not exist and the current credentials are not permitted to create them; "+
+		"ask an administrator to run the following command(s) and retry:\n%s", strings.Join(commands, "\n"))
+}
+
+func (m *NodeGroupService) serviceLinkedRoleExists(ctx context.Context, role serviceLinkedRole) (bool, error) {
+	_, err := m.Provider.IAM().GetRole(ctx, &iam.GetRoleInput{
+		RoleName: aws.String(role.name),
+	})
+	if err != nil {
+		var notFoundErr *iamtypes.NoSuchEntityException
+		if errors.As(err, &notFoundErr) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting service-linked role %q", role.name)
+	}
+	return true, nil
+}
+
+func isAccessDenied(err error) bool {
+	var ae smithy.APIError
+	return errors.As(err, &ae) && ae.ErrorCode() == "AccessDenied"
+}
+
+// requiredServiceLinkedRoles returns the service-linked roles needed to launch the nodegroups in spec.
+// Unmanaged nodegroups are backed by an ASG created by eksctl, and spot capacity needs the EC2 Spot role
+func requiredServiceLinkedRoles(spec *api.ClusterConfig) []serviceLinkedRole {
+	var (
+		needsAutoScaling bool
+		needsSpot        bool
+	)
+	for _, ng := range spec.NodeGroups {
+		needsAutoScaling = true
+		if isSpotNodeGroup(ng) {
+			needsSpot = true
+		}
+	}
+	for _, ng := range spec.ManagedNodeGroups {
+		if ng.Spot {
+			needsSpot = true
+		}
+	}
+
+	var roles []serviceLinkedRole
+	if needsAutoScaling {
+		roles = append(roles, autoScalingServiceLinkedRole)
+	}
+	if needsSpot {
+		roles = append(roles, spotServiceLinkedRole)
+	}
+	return roles
+}
+
+func isSpotNodeGroup(ng *api.NodeGroup) bool {
+	if !api.HasMixedInstances(ng) {
+		return false
+	}
+	onDemandPercentage := ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity
+	return onDemandPercentage != nil && *onDemandPercentage < 100
+}
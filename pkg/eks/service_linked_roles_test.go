@@ -0,0 +1,149 @@
+package eks_test
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Service-linked roles", func() {
+	var (
+		p             *mockprovider.MockProvider
+		clusterConfig *api.ClusterConfig
+	)
+
+	mockGetRole := func(roleName string, exists bool) {
+		var (
+			output *iam.GetRoleOutput
+			err    error
+		)
+		if exists {
+			output = &iam.GetRoleOutput{Role: &iamtypes.Role{RoleName: aws.String(roleName)}}
+		} else {
+			err = &iamtypes.NoSuchEntityException{Message: aws.String("role not found")}
+		}
+		p.MockIAM().On("GetRole", mock.Anything, mock.MatchedBy(func(input *iam.GetRoleInput) bool {
+			return *input.RoleName == roleName
+		})).Return(output, err)
+	}
+
+	mockCreateServiceLinkedRole := func(serviceName string, err error) {
+		var output *iam.CreateServiceLinkedRoleOutput
+		if err == nil {
+			output = &iam.CreateServiceLinkedRoleOutput{}
+		}
+		p.MockIAM().On("CreateServiceLinkedRole", mock.Anything, mock.MatchedBy(func(input *iam.CreateServiceLinkedRoleInput) bool {
+			return *input.AWSServiceName == serviceName
+		})).Return(output, err)
+	}
+
+	accessDeniedErr := &smithy.GenericAPIError{
+		Code:    "AccessDenied",
+		Message: "not authorized to perform: iam:CreateServiceLinkedRole",
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		clusterConfig = api.NewClusterConfig()
+	})
+
+	ensureServiceLinkedRoles := func() error {
+		return eks.NewNodeGroupService(p, nil).EnsureServiceLinkedRoles(context.Background(), clusterConfig)
+	}
+
+	Context("a spot nodegroup", func() {
+		BeforeEach(func() {
+			ng := api.NewNodeGroup()
+			ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{
+				InstanceTypes:                       []string{"m5.large", "m5a.large"},
+				OnDemandPercentageAboveBaseCapacity: aws.Int(0),
+			}
+			clusterConfig.NodeGroups = []*api.NodeGroup{ng}
+		})
+
+		It("does not create the roles when they exist", func() {
+			mockGetRole("AWSServiceRoleForAutoScaling", true)
+			mockGetRole("AWSServiceRoleForEC2Spot", true)
+
+			Expect(ensureServiceLinkedRoles()).To(Succeed())
+			Expect(p.MockIAM().AssertNotCalled(GinkgoT(), "CreateServiceLinkedRole", mock.Anything, mock.Anything)).To(BeTrue())
+		})
+
+		It("creates the roles that do not exist", func() {
+			mockGetRole("AWSServiceRoleForAutoScaling", true)
+			mockGetRole("AWSServiceRoleForEC2Spot", false)
+			mockCreateServiceLinkedRole("spot.amazonaws.com", nil)
+
+			Expect(ensureServiceLinkedRoles()).To(Succeed())
+			Expect(p.MockIAM().AssertNumberOfCalls(GinkgoT(), "CreateServiceLinkedRole", 1)).To(BeTrue())
+		})
+
+		It("returns the commands to create the roles when creating them is forbidden", func() {
+			mockGetRole("AWSServiceRoleForAutoScaling", false)
+			mockGetRole("AWSServiceRoleForEC2Spot", false)
+			mockCreateServiceLinkedRole("autoscaling.amazonaws.com", accessDeniedErr)
+			mockCreateServiceLinkedRole("spot.amazonaws.com", accessDeniedErr)
+
+			err := ensureServiceLinkedRoles()
+			Expect(err).To(MatchError(ContainSubstring("aws iam create-service-linked-role --aws-service-name autoscaling.amazonaws.com\n" +
+				"aws iam create-service-linked-role --aws-service-name spot.amazonaws.com")))
+		})
+
+		It("proceeds when looking up the roles is forbidden", func() {
+			p.MockIAM().On("GetRole", mock.Anything, mock.Anything).Return(nil, &smithy.GenericAPIError{
+				Code:    "AccessDenied",
+				Message: "not authorized to perform: iam:GetRole",
+			})
+
+			Expect(ensureServiceLinkedRoles()).To(Succeed())
+			Expect(p.MockIAM().AssertNotCalled(GinkgoT(), "CreateServiceLinkedRole", mock.Anything, mock.Anything)).To(BeTrue())
+		})
+
+		It("returns any other error from looking up a role", func() {
+			p.MockIAM().On("GetRole", mock.Anything, mock.Anything).Return(nil, &smithy.GenericAPIError{Code: "ServiceFailure"})
+
+			Expect(ensureServiceLinkedRoles()).To(MatchError(ContainSubstring(`getting service-linked role "AWSServiceRoleForAutoScaling"`)))
+		})
+
+		It("returns any other error from creating a role", func() {
+			mockGetRole("AWSServiceRoleForAutoScaling", true)
+			mockGetRole("AWSServiceRoleForEC2Spot", false)
+			mockCreateServiceLinkedRole("spot.amazonaws.com", &smithy.GenericAPIError{Code: "ServiceFailure"})
+
+			Expect(ensureServiceLinkedRoles()).To(MatchError(ContainSubstring(`creating service-linked role "AWSServiceRoleForEC2Spot"`)))
+		})
+	})
+
+	It("only checks the Auto Scaling role for on-demand nodegroups", func() {
+		clusterConfig.NodeGroups = []*api.NodeGroup{api.NewNodeGroup()}
+		mockGetRole("AWSServiceRoleForAutoScaling", true)
+
+		Expect(ensureServiceLinkedRoles()).To(Succeed())
+		Expect(p.MockIAM().AssertNumberOfCalls(GinkgoT(), "GetRole", 1)).To(BeTrue())
+	})
+
+	It("only checks the EC2 Spot role for managed spot nodegroups", func() {
+		clusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{{NodeGroupBase: &api.NodeGroupBase{Name: "mng"}, Spot: true}}
+		mockGetRole("AWSServiceRoleForEC2Spot", false)
+		mockCreateServiceLinkedRole("spot.amazonaws.com", nil)
+
+		Expect(ensureServiceLinkedRoles()).To(Succeed())
+		Expect(p.MockIAM().AssertNumberOfCalls(GinkgoT(), "GetRole", 1)).To(BeTrue())
+		Expect(p.MockIAM().AssertNumberOfCalls(GinkgoT(), "CreateServiceLinkedRole", 1)).To(BeTrue())
+	})
+
+	It("does not check any roles when there are no nodegroups", func() {
+		Expect(ensureServiceLinkedRoles()).To(Succeed())
+		Expect(p.MockIAM().AssertNotCalled(GinkgoT(), "GetRole", mock.Anything, mock.Anything)).To(BeTrue())
+	})
+})
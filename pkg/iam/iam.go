@@ -4,27 +4,28 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	awsiam "github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/cfn/waiter"
 )
 
 // ImportInstanceRoleFromProfileARN fetches first role ARN from instance profile.
 func ImportInstanceRoleFromProfileARN(ctx context.Context, iamAPI awsapi.IAM, ng *api.NodeGroup, profileARN string) error {
-	partsOfProfileARN := strings.Split(profileARN, "/")
-
-	if len(partsOfProfileARN) != 2 {
-		return fmt.Errorf("unexpected format of instance profile ARN: %q", profileARN)
+	profileName, err := instanceProfileName(profileARN)
+	if err != nil {
+		return err
 	}
-	profileName := partsOfProfileARN[1]
 	input := &awsiam.GetInstanceProfileInput{
 		InstanceProfileName: &profileName,
 	}
@@ -45,6 +46,57 @@ func ImportInstanceRoleFromProfileARN(ctx context.Context, iamAPI awsapi.IAM, ng
 	return nil
 }
 
+// WaitForInstanceProfile polls IAM until the instance profile can be retrieved and has a role, as a newly created
+// instance profile may not have propagated yet when an Auto Scaling group launches instances with it. It gives up
+// after timeout
+func WaitForInstanceProfile(ctx context.Context, iamAPI awsapi.IAM, profileARN string, timeout time.Duration) error {
+	profileName, err := instanceProfileName(profileARN)
+	if err != nil {
+		return err
+	}
+
+	operation := func() (bool, error) {
+		output, err := iamAPI.GetInstanceProfile(ctx, &awsiam.GetInstanceProfileInput{
+			InstanceProfileName: &profileName,
+		})
+		if err != nil {
+			var notFoundErr *iamtypes.NoSuchEntityException
+			if errors.As(err, &notFoundErr) {
+				logger.Debug("instance profile %q is not retrievable yet", profileName)
+				return false, nil
+			}
+			return false, errors.Wrapf(err, "getting instance profile %q", profileName)
+		}
+		return len(output.InstanceProfile.Roles) > 0, nil
+	}
+
+	w := waiter.Waiter{
+		Operation: operation,
+		NextDelay: func(_ int) time.Duration {
+			return timeout / 20
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := w.Wait(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %v waiting for instance profile %q to be retrievable", timeout, profileName)
+		}
+		return err
+	}
+	return nil
+}
+
+func instanceProfileName(profileARN string) (string, error) {
+	partsOfProfileARN := strings.Split(profileARN, "/")
+
+	if len(partsOfProfileARN) != 2 {
+		return "", fmt.Errorf("unexpected format of instance profile ARN: %q", profileARN)
+	}
+	return partsOfProfileARN[1], nil
+}
+
 // UseFromNodeGroup retrieves the IAM configuration from an existing nodegroup
 // based on stack outputs
 func UseFromNodeGroup(stack *cfn.Stack, ng *api.NodeGroup) error {
@@ -53,10 +105,7 @@ func UseFromNodeGroup(stack *cfn.Stack, ng *api.NodeGroup) error {
 	}
 
 	requiredCollectors := map[string]outputs.Collector{
-		outputs.NodeGroupInstanceRoleARN: func(v string) error {
-			ng.IAM.InstanceRoleARN = v
-			return nil
-		},
+		outputs.NodeGroupInstanceRoleARN: outputs.NewInstanceRoleARNCollector(ng.IAM),
 	}
 	return outputs.Collect(*stack, requiredCollectors, nil)
 }
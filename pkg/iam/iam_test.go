@@ -0,0 +1,68 @@
+package iam_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	awsiam "github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("WaitForInstanceProfile", func() {
+	const profileARN = "arn:aws:iam::123456789012:instance-profile/my-profile"
+
+	var p *mockprovider.MockProvider
+
+	notFoundErr := &iamtypes.NoSuchEntityException{Message: aws.String("instance profile not found")}
+	withRole := &awsiam.GetInstanceProfileOutput{
+		InstanceProfile: &iamtypes.InstanceProfile{
+			Roles: []iamtypes.Role{{Arn: aws.String("arn:aws:iam::123456789012:role/my-role")}},
+		},
+	}
+
+	mockGetInstanceProfile := func(output *awsiam.GetInstanceProfileOutput, err error) *mock.Call {
+		return p.MockIAM().On("GetInstanceProfile", mock.Anything, mock.MatchedBy(func(input *awsiam.GetInstanceProfileInput) bool {
+			return *input.InstanceProfileName == "my-profile"
+		})).Return(output, err)
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+	})
+
+	It("waits until the instance profile is retrievable and has a role", func() {
+		mockGetInstanceProfile(nil, notFoundErr).Once()
+		mockGetInstanceProfile(&awsiam.GetInstanceProfileOutput{InstanceProfile: &iamtypes.InstanceProfile{}}, nil).Once()
+		mockGetInstanceProfile(withRole, nil).Once()
+
+		Expect(iam.WaitForInstanceProfile(context.Background(), p.MockIAM(), profileARN, time.Second)).To(Succeed())
+		Expect(p.MockIAM().Calls).To(HaveLen(3))
+	})
+
+	It("gives up after the timeout", func() {
+		mockGetInstanceProfile(nil, notFoundErr)
+
+		err := iam.WaitForInstanceProfile(context.Background(), p.MockIAM(), profileARN, 100*time.Millisecond)
+		Expect(err).To(MatchError(`timed out after 100ms waiting for instance profile "my-profile" to be retrievable`))
+	})
+
+	It("returns other errors", func() {
+		mockGetInstanceProfile(nil, errors.New("access denied"))
+
+		err := iam.WaitForInstanceProfile(context.Background(), p.MockIAM(), profileARN, time.Second)
+		Expect(err).To(MatchError(`getting instance profile "my-profile": access denied`))
+	})
+
+	It("rejects malformed instance profile ARNs", func() {
+		err := iam.WaitForInstanceProfile(context.Background(), p.MockIAM(), "my-profile", time.Second)
+		Expect(err).To(MatchError(`unexpected format of instance profile ARN: "my-profile"`))
+	})
+})
@@ -0,0 +1,268 @@
+package instancetypes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/weaveworks/eksctl/pkg/awsapi"
+	instanceutils "github.com/weaveworks/eksctl/pkg/utils/instance"
+)
+
+// CPU architectures of instance types, as named by EC2
+const (
+	ArchitectureX86_64 = "x86_64"
+	ArchitectureARM64  = "arm64"
+)
+
+// Capabilities are the capabilities of an instance type that nodegroups depend on
+type Capabilities struct {
+	InstanceType string
+	Architecture string
+	// NvidiaGPUs is the number of NVIDIA GPUs of the instance type
+	NvidiaGPUs int
+	// InferentiaChips is the number of AWS Inferentia chips of the instance type, which require AWS Neuron
+	InferentiaChips int
+	EFASupported    bool
+	MaxNetworkCards int
+	// MaxENIs is the maximum number of network interfaces, or 0 if it is not known
+	MaxENIs int
+	// MaxIPv4AddressesPerENI is the maximum number of IPv4 addresses of each network interface, or 0 if it is not known
+	MaxIPv4AddressesPerENI int
+	// VCPUs is the default number of vCPUs, or 0 if it is not known
+	VCPUs int
+	// NVMeInstanceStore is true if the instance type has instance store volumes exposed as NVMe devices
+	NVMeInstanceStore   bool
+	EBSOptimizedSupport ec2types.EbsOptimizedSupport
+}
+
+// IsARM returns true if the instance type has an ARM CPU
+func (c Capabilities) IsARM() bool {
+	return c.Architecture == ArchitectureARM64
+}
+
+// IsNvidia returns true if the instance type has NVIDIA GPUs
+func (c Capabilities) IsNvidia() bool {
+	return c.NvidiaGPUs > 0
+}
+
+// IsInferentia returns true if the instance type has AWS Inferentia chips
+func (c Capabilities) IsInferentia() bool {
+	return c.InferentiaChips > 0
+}
+
+// IsGPU returns true if the instance type needs an accelerated AMI
+func (c Capabilities) IsGPU() bool {
+	return c.IsNvidia() || c.IsInferentia()
+}
+
+// MaxPods returns the maximum number of pods the VPC CNI can assign IP addresses to on an instance of the instance
+// type, following the EKS max pods calculator, or 0 if the network limits of the instance type are not known. With
+// prefix delegation, each secondary IP address slot holds a /28 prefix of 16 addresses, and the result is capped at
+// the number of pods EKS recommends for the number of vCPUs
+func (c Capabilities) MaxPods(prefixDelegation bool) int {
+	if c.MaxENIs == 0 || c.MaxIPv4AddressesPerENI == 0 {
+		return 0
+	}
+	addressesPerENI := c.MaxIPv4AddressesPerENI - 1
+	if !prefixDelegation {
+		return c.MaxENIs*addressesPerENI + 2
+	}
+	maxPods := c.MaxENIs*addressesPerENI*16 + 2
+	recommended := 110
+	if c.VCPUs >= 30 {
+		recommended = 250
+	}
+	if maxPods > recommended {
+		return recommended
+	}
+	return maxPods
+}
+
+// Cache looks up the capabilities of instance types with the EC2 API, describing each instance type only once
+type Cache struct {
+	ec2API awsapi.EC2
+
+	mu           sync.Mutex
+	capabilities map[string]Capabilities
+}
+
+// NewCache creates a new Cache. When ec2API is nil, e.g. offline, capabilities are inferred from the instance type
+// family instead
+func NewCache(ec2API awsapi.EC2) *Cache {
+	return &Cache{
+		ec2API:       ec2API,
+		capabilities: map[string]Capabilities{},
+	}
+}
+
+// Info returns the capabilities of instanceType
+func (c *Cache) Info(ctx context.Context, instanceType string) (Capabilities, error) {
+	capabilities, err := c.InfoList(ctx, []string{instanceType})
+	if err != nil {
+		return Capabilities{}, err
+	}
+	return capabilities[0], nil
+}
+
+// InfoList returns the capabilities of each of instanceTypes, describing the instance types that are not cached yet
+// in a single call
+func (c *Cache) InfoList(ctx context.Context, instanceTypes []string) ([]Capabilities, error) {
+	if c.ec2API == nil {
+		var capabilities []Capabilities
+		for _, instanceType := range instanceTypes {
+			capabilities = append(capabilities, StaticInfo(instanceType))
+		}
+		return capabilities, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var missing []ec2types.InstanceType
+	for _, instanceType := range instanceTypes {
+		if _, ok := c.capabilities[instanceType]; ok || instanceType == "" || containsInstanceType(missing, instanceType) {
+			continue
+		}
+		missing = append(missing, ec2types.InstanceType(instanceType))
+	}
+	if len(missing) > 0 {
+		output, err := c.ec2API.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: missing,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range output.InstanceTypes {
+			c.capabilities[string(info.InstanceType)] = fromInstanceTypeInfo(info)
+		}
+	}
+
+	var capabilities []Capabilities
+	for _, instanceType := range instanceTypes {
+		if instanceType == "" {
+			// the instance types selected by instanceRequirements are only known to EC2 Auto Scaling
+			capabilities = append(capabilities, StaticInfo(instanceType))
+			continue
+		}
+		info, ok := c.capabilities[instanceType]
+		if !ok {
+			return nil, fmt.Errorf("instance type %s was not found", instanceType)
+		}
+		capabilities = append(capabilities, info)
+	}
+	return capabilities, nil
+}
+
+// caches holds a cache for each EC2 API, so that all the callers sharing a client share its cache
+var caches = struct {
+	sync.Mutex
+	byAPI map[awsapi.EC2]*Cache
+}{
+	byAPI: map[awsapi.EC2]*Cache{},
+}
+
+func cacheFor(ec2API awsapi.EC2) *Cache {
+	if ec2API == nil {
+		return NewCache(nil)
+	}
+	caches.Lock()
+	defer caches.Unlock()
+	cache, ok := caches.byAPI[ec2API]
+	if !ok {
+		cache = NewCache(ec2API)
+		caches.byAPI[ec2API] = cache
+	}
+	return cache
+}
+
+// Info returns the capabilities of instanceType, using a cache shared by all the callers of ec2API. When ec2API is
+// nil, e.g. offline, they are inferred from the instance type family instead
+func Info(ctx context.Context, ec2API awsapi.EC2, instanceType string) (Capabilities, error) {
+	return cacheFor(ec2API).Info(ctx, instanceType)
+}
+
+// InfoList returns the capabilities of each of instanceTypes, using a cache shared by all the callers of ec2API
+func InfoList(ctx context.Context, ec2API awsapi.EC2, instanceTypes []string) ([]Capabilities, error) {
+	return cacheFor(ec2API).InfoList(ctx, instanceTypes)
+}
+
+// StaticInfo infers the capabilities of an instance type from its family, for when EC2 cannot be queried. Only the
+// architecture and the kind of accelerators are known, so an instance type is assumed to have a single network card
+// without EFA support
+func StaticInfo(instanceType string) Capabilities {
+	capabilities := Capabilities{
+		InstanceType:    instanceType,
+		Architecture:    ArchitectureX86_64,
+		MaxNetworkCards: 1,
+	}
+	if instanceutils.IsARMInstanceType(instanceType) {
+		capabilities.Architecture = ArchitectureARM64
+	}
+	if instanceutils.IsNvidiaInstanceType(instanceType) {
+		capabilities.NvidiaGPUs = 1
+	}
+	if instanceutils.IsInferentiaInstanceType(instanceType) {
+		capabilities.InferentiaChips = 1
+	}
+	return capabilities
+}
+
+func fromInstanceTypeInfo(info ec2types.InstanceTypeInfo) Capabilities {
+	capabilities := Capabilities{
+		InstanceType: string(info.InstanceType),
+		Architecture: ArchitectureX86_64,
+	}
+	if info.ProcessorInfo != nil {
+		for _, arch := range info.ProcessorInfo.SupportedArchitectures {
+			if arch == ec2types.ArchitectureTypeArm64 {
+				capabilities.Architecture = ArchitectureARM64
+			}
+		}
+	}
+	if info.GpuInfo != nil {
+		for _, gpu := range info.GpuInfo.Gpus {
+			if strings.EqualFold(aws.ToString(gpu.Manufacturer), "NVIDIA") {
+				capabilities.NvidiaGPUs += int(aws.ToInt32(gpu.Count))
+			}
+		}
+	}
+	if info.InferenceAcceleratorInfo != nil {
+		for _, accelerator := range info.InferenceAcceleratorInfo.Accelerators {
+			if strings.EqualFold(aws.ToString(accelerator.Name), "Inferentia") {
+				capabilities.InferentiaChips += int(aws.ToInt32(accelerator.Count))
+			}
+		}
+	}
+	if info.NetworkInfo != nil {
+		capabilities.EFASupported = aws.ToBool(info.NetworkInfo.EfaSupported)
+		capabilities.MaxNetworkCards = int(aws.ToInt32(info.NetworkInfo.MaximumNetworkCards))
+		capabilities.MaxENIs = int(aws.ToInt32(info.NetworkInfo.MaximumNetworkInterfaces))
+		capabilities.MaxIPv4AddressesPerENI = int(aws.ToInt32(info.NetworkInfo.Ipv4AddressesPerInterface))
+	}
+	if info.VCpuInfo != nil {
+		capabilities.VCPUs = int(aws.ToInt32(info.VCpuInfo.DefaultVCpus))
+	}
+	if info.InstanceStorageInfo != nil {
+		nvme := info.InstanceStorageInfo.NvmeSupport
+		capabilities.NVMeInstanceStore = nvme == ec2types.EphemeralNvmeSupportSupported || nvme == ec2types.EphemeralNvmeSupportRequired
+	}
+	if info.EbsInfo != nil {
+		capabilities.EBSOptimizedSupport = info.EbsInfo.EbsOptimizedSupport
+	}
+	return capabilities
+}
+
+func containsInstanceType(instanceTypes []ec2types.InstanceType, instanceType string) bool {
+	for _, it := range instanceTypes {
+		if string(it) == instanceType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+package instancetypes_test
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestInstanceTypes(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
@@ -0,0 +1,193 @@
+package instancetypes_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/instancetypes"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Instance types", func() {
+	var p *mockprovider.MockProvider
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+	})
+
+	mockDescribeInstanceTypes := func(instanceTypes []ec2types.InstanceType, infos ...ec2types.InstanceTypeInfo) {
+		p.MockEC2().On("DescribeInstanceTypes", mock.Anything, &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: instanceTypes,
+		}).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: infos,
+		}, nil)
+	}
+
+	m5Large := ec2types.InstanceTypeInfo{
+		InstanceType: ec2types.InstanceTypeM5Large,
+		ProcessorInfo: &ec2types.ProcessorInfo{
+			SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+		},
+		NetworkInfo: &ec2types.NetworkInfo{
+			EfaSupported:             aws.Bool(false),
+			MaximumNetworkCards:      aws.Int32(1),
+			MaximumNetworkInterfaces: aws.Int32(3),
+		},
+		EbsInfo: &ec2types.EbsInfo{
+			EbsOptimizedSupport: ec2types.EbsOptimizedSupportDefault,
+		},
+	}
+	p4d := ec2types.InstanceTypeInfo{
+		InstanceType: ec2types.InstanceTypeP4d24xlarge,
+		ProcessorInfo: &ec2types.ProcessorInfo{
+			SupportedArchitectures: []ec2types.ArchitectureType{ec2types.ArchitectureTypeX8664},
+		},
+		GpuInfo: &ec2types.GpuInfo{
+			Gpus: []ec2types.GpuDeviceInfo{
+				{Count: aws.Int32(8), Manufacturer: aws.String("NVIDIA"), Name: aws.String("A100")},
+			},
+		},
+		NetworkInfo: &ec2types.NetworkInfo{
+			EfaSupported:             aws.Bool(true),
+			MaximumNetworkCards:      aws.Int32(4),
+			MaximumNetworkInterfaces: aws.Int32(60),
+		},
+		InstanceStorageInfo: &ec2types.InstanceStorageInfo{
+			NvmeSupport: ec2types.EphemeralNvmeSupportRequired,
+		},
+	}
+
+	Describe("Cache", func() {
+		It("describes each unique instance type once", func() {
+			mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeM5Large, ec2types.InstanceTypeP4d24xlarge}, m5Large, p4d)
+			cache := instancetypes.NewCache(p.MockEC2())
+
+			infos, err := cache.InfoList(context.Background(), []string{"m5.large", "p4d.24xlarge", "m5.large"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infos).To(HaveLen(3))
+			Expect(infos[0].InstanceType).To(Equal("m5.large"))
+			Expect(infos[1].InstanceType).To(Equal("p4d.24xlarge"))
+			Expect(infos[2]).To(Equal(infos[0]))
+
+			info, err := cache.Info(context.Background(), "p4d.24xlarge")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(infos[1]))
+
+			Expect(p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeInstanceTypes", 1)).To(BeTrue())
+		})
+
+		It("only describes the instance types that are not cached yet", func() {
+			mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeM5Large}, m5Large)
+			mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeP4d24xlarge}, p4d)
+			cache := instancetypes.NewCache(p.MockEC2())
+
+			_, err := cache.Info(context.Background(), "m5.large")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = cache.InfoList(context.Background(), []string{"m5.large", "p4d.24xlarge"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeInstanceTypes", 2)).To(BeTrue())
+		})
+
+		It("maps the instance type description to its capabilities", func() {
+			mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeP4d24xlarge}, p4d)
+			info, err := instancetypes.NewCache(p.MockEC2()).Info(context.Background(), "p4d.24xlarge")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(instancetypes.Capabilities{
+				InstanceType:      "p4d.24xlarge",
+				Architecture:      instancetypes.ArchitectureX86_64,
+				NvidiaGPUs:        8,
+				EFASupported:      true,
+				MaxNetworkCards:   4,
+				MaxENIs:           60,
+				NVMeInstanceStore: true,
+			}))
+			Expect(info.IsGPU()).To(BeTrue())
+			Expect(info.IsARM()).To(BeFalse())
+		})
+
+		It("does not count GPUs of other manufacturers as NVIDIA GPUs", func() {
+			mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeG4adXlarge}, ec2types.InstanceTypeInfo{
+				InstanceType: ec2types.InstanceTypeG4adXlarge,
+				GpuInfo: &ec2types.GpuInfo{
+					Gpus: []ec2types.GpuDeviceInfo{
+						{Count: aws.Int32(1), Manufacturer: aws.String("AMD"), Name: aws.String("Radeon Pro V520")},
+					},
+				},
+			})
+			info, err := instancetypes.NewCache(p.MockEC2()).Info(context.Background(), "g4ad.xlarge")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.IsNvidia()).To(BeFalse())
+		})
+
+		It("returns an error when an instance type is not found", func() {
+			mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeM5Large, "m5.huge"}, m5Large)
+			_, err := instancetypes.NewCache(p.MockEC2()).InfoList(context.Background(), []string{"m5.large", "m5.huge"})
+			Expect(err).To(MatchError("instance type m5.huge was not found"))
+		})
+
+		It("returns the error of the EC2 API", func() {
+			p.MockEC2().On("DescribeInstanceTypes", mock.Anything, mock.Anything).Return(nil, errors.New("throttled"))
+			_, err := instancetypes.NewCache(p.MockEC2()).Info(context.Background(), "m5.large")
+			Expect(err).To(MatchError("throttled"))
+		})
+	})
+
+	It("shares a cache between the callers of an EC2 API", func() {
+		mockDescribeInstanceTypes([]ec2types.InstanceType{ec2types.InstanceTypeM5Large}, m5Large)
+		for i := 0; i < 2; i++ {
+			info, err := instancetypes.Info(context.Background(), p.MockEC2(), "m5.large")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.EBSOptimizedSupport).To(Equal(ec2types.EbsOptimizedSupportDefault))
+		}
+		Expect(p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeInstanceTypes", 1)).To(BeTrue())
+	})
+
+	DescribeTable("computes the max pods of an instance type", func(capabilities instancetypes.Capabilities, prefixDelegation bool, expected int) {
+		Expect(capabilities.MaxPods(prefixDelegation)).To(Equal(expected))
+	},
+		Entry("t3.small", instancetypes.Capabilities{MaxENIs: 3, MaxIPv4AddressesPerENI: 4, VCPUs: 2}, false, 11),
+		Entry("m5.large", instancetypes.Capabilities{MaxENIs: 3, MaxIPv4AddressesPerENI: 10, VCPUs: 2}, false, 29),
+		Entry("t3.small with prefix delegation", instancetypes.Capabilities{MaxENIs: 3, MaxIPv4AddressesPerENI: 4, VCPUs: 2}, true, 110),
+		Entry("t3.nano with prefix delegation", instancetypes.Capabilities{MaxENIs: 2, MaxIPv4AddressesPerENI: 2, VCPUs: 2}, true, 34),
+		Entry("m5.8xlarge with prefix delegation", instancetypes.Capabilities{MaxENIs: 8, MaxIPv4AddressesPerENI: 30, VCPUs: 32}, true, 250),
+		Entry("unknown network limits", instancetypes.StaticInfo("m5.large"), false, 0),
+	)
+
+	DescribeTable("falls back to the static table without an EC2 API", func(instanceType string, expected instancetypes.Capabilities) {
+		info, err := instancetypes.Info(context.Background(), nil, instanceType)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(Equal(expected))
+	},
+		Entry("general purpose", "m5.large", instancetypes.Capabilities{
+			InstanceType:    "m5.large",
+			Architecture:    instancetypes.ArchitectureX86_64,
+			MaxNetworkCards: 1,
+		}),
+		Entry("ARM", "m6g.large", instancetypes.Capabilities{
+			InstanceType:    "m6g.large",
+			Architecture:    instancetypes.ArchitectureARM64,
+			MaxNetworkCards: 1,
+		}),
+		Entry("NVIDIA GPU", "p3.2xlarge", instancetypes.Capabilities{
+			InstanceType:    "p3.2xlarge",
+			Architecture:    instancetypes.ArchitectureX86_64,
+			NvidiaGPUs:      1,
+			MaxNetworkCards: 1,
+		}),
+		Entry("Inferentia", "inf1.xlarge", instancetypes.Capabilities{
+			InstanceType:    "inf1.xlarge",
+			Architecture:    instancetypes.ArchitectureX86_64,
+			InferentiaChips: 1,
+			MaxNetworkCards: 1,
+		}),
+	)
+})
@@ -1,6 +1,7 @@
 package managed
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/weaveworks/eksctl/pkg/awsapi"
@@ -104,7 +105,12 @@ func (m *Service) UpdateLabels(nodeGroupName string, labelsToAdd map[string]stri
 		return err
 	}
 
-	return m.stackCollection.UpdateNodeGroupStack(nodeGroupName, template, true)
+	return m.stackCollection.UpdateNodeGroupStack(context.TODO(), manager.UpdateNodeGroupStackOptions{
+		NodeGroupName:           nodeGroupName,
+		TemplateData:            manager.TemplateBody(template),
+		Wait:                    true,
+		PreserveDesiredCapacity: true,
+	})
 }
 
 // GetLabels fetches the labels for a nodegroup
@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/kris-nova/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "eksctl"
+
+var (
+	stacksInProgress = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "stacks_in_progress",
+		Help:      "Number of CloudFormation stacks being waited on.",
+	})
+	tasksInProgress = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tasks_in_progress",
+		Help:      "Number of tasks running.",
+	})
+	tasksCompleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_completed_total",
+		Help:      "Number of tasks completed, by result.",
+	}, []string{"result"})
+	nodesDrained = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nodes_drained_total",
+		Help:      "Number of nodes drained.",
+	})
+	awsAPICalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "aws_api_calls_total",
+		Help:      "Number of AWS API calls, by service and operation.",
+	}, []string{"service", "operation"})
+	awsAPIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "aws_api_errors_total",
+		Help:      "Number of AWS API calls that failed after retries, by service and operation.",
+	}, []string{"service", "operation"})
+	waitSeconds = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "wait_seconds",
+		Help:      "Seconds elapsed in the current wait, or 0 when nothing is being waited on.",
+	}, waits.elapsed)
+
+	registry = prometheus.NewRegistry()
+
+	// enabled is set while metrics are served, the collectors are not updated otherwise
+	enabled int32
+)
+
+func init() {
+	registry.MustRegister(stacksInProgress, tasksInProgress, tasksCompleted, nodesDrained, awsAPICalls, awsAPIErrors, waitSeconds)
+}
+
+// Enabled returns true if metrics are collected
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// TrackStack records that a CloudFormation stack is being waited on, and returns a function to call once the wait
+// is over
+func TrackStack() func() {
+	if !Enabled() {
+		return func() {}
+	}
+	stacksInProgress.Inc()
+	return stacksInProgress.Dec
+}
+
+// TrackTask records that a task has started, and returns a function to call with the result of the task once it
+// has completed
+func TrackTask() func(error) {
+	if !Enabled() {
+		return func(error) {}
+	}
+	tasksInProgress.Inc()
+	return func(err error) {
+		tasksInProgress.Dec()
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		tasksCompleted.WithLabelValues(result).Inc()
+	}
+}
+
+// TrackWait records that a wait has started, and returns a function to call once the wait is over
+func TrackWait() func() {
+	if !Enabled() {
+		return func() {}
+	}
+	return waits.start()
+}
+
+// NodeDrained records that a node has been drained
+func NodeDrained() {
+	if Enabled() {
+		nodesDrained.Inc()
+	}
+}
+
+// AWSAPICall records a call to an AWS API, along with its error if the call failed
+func AWSAPICall(service, operation string, err error) {
+	if !Enabled() {
+		return
+	}
+	awsAPICalls.WithLabelValues(service, operation).Inc()
+	if err != nil {
+		awsAPIErrors.WithLabelValues(service, operation).Inc()
+	}
+}
+
+// AWSRequestHandler records the calls made by AWS SDK v1 clients, it is meant to be added to the Complete handlers
+// of a session
+var AWSRequestHandler = request.NamedHandler{
+	Name: "eksctlMetrics",
+	Fn: func(r *request.Request) {
+		AWSAPICall(r.ClientInfo.ServiceID, r.Operation.Name, r.Error)
+	},
+}
+
+// AddAWSMiddleware adds a middleware recording the calls made by AWS SDK v2 clients to stack
+func AddAWSMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("eksctlMetrics", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		AWSAPICall(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), err)
+		return out, metadata, err
+	}), middleware.After)
+}
+
+// Handler returns an HTTP handler exposing the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Server serves the metrics over HTTP
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// Serve enables the collection of metrics and serves them on /metrics at addr, until the server is closed
+func Serve(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s to serve metrics: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	s := &Server{
+		listener: listener,
+		server:   &http.Server{Handler: mux},
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Warning("error serving metrics: %v", err)
+		}
+	}()
+
+	atomic.StoreInt32(&enabled, 1)
+	logger.Debug("serving metrics on %s/metrics", listener.Addr())
+	return s, nil
+}
+
+// Addr returns the address the metrics are served on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops serving the metrics and disables their collection
+func (s *Server) Close() error {
+	atomic.StoreInt32(&enabled, 0)
+	return s.server.Close()
+}
+
+// activeWaits holds the start time of the waits in progress
+type activeWaits struct {
+	mu     sync.Mutex
+	nextID int
+	starts map[int]time.Time
+}
+
+var waits = &activeWaits{
+	starts: map[int]time.Time{},
+}
+
+func (w *activeWaits) start() func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.starts[id] = time.Now()
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.starts, id)
+	}
+}
+
+// elapsed returns the seconds elapsed since the start of the earliest wait in progress
+func (w *activeWaits) elapsed() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var earliest time.Time
+	for _, start := range w.starts {
+		if earliest.IsZero() || start.Before(earliest) {
+			earliest = start
+		}
+	}
+	if earliest.IsZero() {
+		return 0
+	}
+	return time.Since(earliest).Seconds()
+}
@@ -0,0 +1,98 @@
+package metrics_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/metrics"
+	"github.com/weaveworks/eksctl/pkg/utils/tasks"
+)
+
+var _ = Describe("Metrics", func() {
+	var server *metrics.Server
+
+	scrape := func() string {
+		resp, err := http.Get(fmt.Sprintf("http://%s/metrics", server.Addr()))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	It("does not collect metrics unless they are served", func() {
+		Expect(metrics.Enabled()).To(BeFalse())
+		metrics.NodeDrained()
+
+		var err error
+		server, err = metrics.Serve("127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			Expect(server.Close()).To(Succeed())
+		}()
+		Expect(scrape()).To(ContainSubstring("eksctl_nodes_drained_total 0\n"))
+	})
+
+	It("exposes the progress of a task run", func() {
+		var err error
+		server, err = metrics.Serve("127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			Expect(server.Close()).To(Succeed())
+			Expect(metrics.Enabled()).To(BeFalse())
+		}()
+
+		var duringTask string
+		taskTree := &tasks.TaskTree{}
+		taskTree.Append(
+			&tasks.GenericTask{
+				Description: "fake stack creation",
+				Doer: func() error {
+					stackDone := metrics.TrackStack()
+					defer stackDone()
+					waitDone := metrics.TrackWait()
+					defer waitDone()
+					metrics.AWSAPICall("CloudFormation", "CreateStack", nil)
+					metrics.AWSAPICall("CloudFormation", "DescribeStacks", nil)
+					metrics.AWSAPICall("CloudFormation", "DescribeStacks", errors.New("throttled"))
+					metrics.NodeDrained()
+					duringTask = scrape()
+					return nil
+				},
+			},
+			&tasks.GenericTask{
+				Description: "fake failure",
+				Doer: func() error {
+					return errors.New("failed")
+				},
+			},
+		)
+		Expect(taskTree.DoAllSync()).To(ConsistOf(MatchError("failed")))
+
+		Expect(duringTask).To(ContainSubstring("eksctl_stacks_in_progress 1\n"))
+		Expect(duringTask).To(ContainSubstring("eksctl_tasks_in_progress 1\n"))
+		Expect(duringTask).To(ContainSubstring("eksctl_nodes_drained_total 1\n"))
+		Expect(duringTask).To(ContainSubstring(`eksctl_aws_api_calls_total{operation="DescribeStacks",service="CloudFormation"} 2`))
+		Expect(duringTask).To(ContainSubstring(`eksctl_aws_api_errors_total{operation="DescribeStacks",service="CloudFormation"} 1`))
+		Expect(duringTask).NotTo(ContainSubstring("eksctl_wait_seconds 0\n"))
+
+		afterTasks := scrape()
+		Expect(afterTasks).To(ContainSubstring("eksctl_stacks_in_progress 0\n"))
+		Expect(afterTasks).To(ContainSubstring("eksctl_tasks_in_progress 0\n"))
+		Expect(afterTasks).To(ContainSubstring(`eksctl_tasks_completed_total{result="success"} 1`))
+		Expect(afterTasks).To(ContainSubstring(`eksctl_tasks_completed_total{result="failure"} 1`))
+		Expect(afterTasks).To(ContainSubstring("eksctl_wait_seconds 0\n"))
+	})
+
+	It("errors when the address cannot be listened on", func() {
+		_, err := metrics.Serve("127.0.0.1:-1")
+		Expect(err).To(MatchError(ContainSubstring("listening on 127.0.0.1:-1 to serve metrics")))
+		Expect(metrics.Enabled()).To(BeFalse())
+	})
+})
@@ -0,0 +1,181 @@
+package nodebootstrap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	kubeletapi "k8s.io/kubelet/config/v1beta1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap/utils"
+)
+
+const (
+	nodeConfigAPIVersion = "node.eks.aws/v1alpha1"
+	nodeConfigKind       = "NodeConfig"
+	// nodeConfigMediaType is the MIME type of the user data parts read by nodeadm
+	nodeConfigMediaType = "application/node.eks.aws"
+	userDataBoundary    = "//"
+)
+
+// nodeConfig is the configuration read by nodeadm to bootstrap AL2023 nodes
+type nodeConfig struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Spec       nodeConfigSpec `json:"spec"`
+}
+
+type nodeConfigSpec struct {
+	Cluster nodeConfigCluster `json:"cluster"`
+	Kubelet nodeConfigKubelet `json:"kubelet,omitempty"`
+}
+
+type nodeConfigCluster struct {
+	Name                 string `json:"name"`
+	APIServerEndpoint    string `json:"apiServerEndpoint"`
+	CertificateAuthority []byte `json:"certificateAuthority"`
+	CIDR                 string `json:"cidr,omitempty"`
+}
+
+type nodeConfigKubelet struct {
+	Config map[string]interface{} `json:"config,omitempty"`
+	Flags  []string               `json:"flags,omitempty"`
+}
+
+type AmazonLinux2023 struct {
+	clusterConfig *api.ClusterConfig
+	ng            *api.NodeGroup
+}
+
+func NewAL2023Bootstrapper(clusterConfig *api.ClusterConfig, ng *api.NodeGroup) *AmazonLinux2023 {
+	return &AmazonLinux2023{
+		clusterConfig: clusterConfig,
+		ng:            ng,
+	}
+}
+
+// UserData returns a MIME multi-part document holding the preBootstrapCommands, preceded by the commands adding the
+// additional SSH public keys, and the NodeConfig read by nodeadm, which replaces bootstrap.sh on AL2023
+func (b *AmazonLinux2023) UserData() (string, error) {
+	config, err := b.makeNodeConfig()
+	if err != nil {
+		return "", err
+	}
+	nodeConfigYAML, err := yaml.Marshal(config)
+	if err != nil {
+		return "", errors.Wrap(err, "encoding NodeConfig")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("MIME-Version: 1.0\n")
+	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\n\n", userDataBoundary))
+
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(userDataBoundary); err != nil {
+		return "", err
+	}
+	var commands []string
+	if authorizedKeys := makeAuthorizedKeysCommands(b.ng.NodeGroupBase); authorizedKeys != "" {
+		commands = append(commands, authorizedKeys)
+	}
+	commands = append(commands, b.ng.PreBootstrapCommands...)
+	if len(commands) > 0 {
+		script := "#!/bin/bash\nset -o errexit\n" + strings.Join(commands, "\n") + "\n"
+		if err := writePart(writer, `text/x-shellscript; charset="us-ascii"`, script); err != nil {
+			return "", err
+		}
+	}
+	if err := writePart(writer, nodeConfigMediaType, "---\n"+string(nodeConfigYAML)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", errors.Wrap(err, "encoding user data")
+	}
+
+	logger.Debug("user-data = %s", buf.String())
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func (b *AmazonLinux2023) makeNodeConfig() (*nodeConfig, error) {
+	status := b.clusterConfig.Status
+	config := &nodeConfig{
+		APIVersion: nodeConfigAPIVersion,
+		Kind:       nodeConfigKind,
+		Spec: nodeConfigSpec{
+			Cluster: nodeConfigCluster{
+				Name:                 b.clusterConfig.Metadata.Name,
+				APIServerEndpoint:    status.Endpoint,
+				CertificateAuthority: status.CertificateAuthorityData,
+			},
+		},
+	}
+	if networkConfig := status.KubernetesNetworkConfig; networkConfig != nil {
+		config.Spec.Cluster.CIDR = networkConfig.ServiceIPv4CIDR
+		if networkConfig.ServiceIPv6CIDR != "" {
+			config.Spec.Cluster.CIDR = networkConfig.ServiceIPv6CIDR
+		}
+	}
+
+	kubeletConfig, err := b.makeKubeletConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Spec.Kubelet.Config = kubeletConfig
+
+	if len(b.ng.Labels) > 0 {
+		config.Spec.Kubelet.Flags = append(config.Spec.Kubelet.Flags, "--node-labels="+formatLabels(b.ng.Labels))
+	}
+	if taints := b.ng.NGTaints(); len(taints) > 0 {
+		config.Spec.Kubelet.Flags = append(config.Spec.Kubelet.Flags, "--register-with-taints="+utils.FormatTaints(taints))
+	}
+	return config, nil
+}
+
+// makeKubeletConfig returns the KubeletConfiguration fields set by the nodegroup, with kubeletExtraConfig taking
+// precedence as it does for AL2
+func (b *AmazonLinux2023) makeKubeletConfig() (map[string]interface{}, error) {
+	kubeletConfig := map[string]interface{}{}
+	if b.ng.MaxPodsPerNode > 0 {
+		kubeletConfig["maxPods"] = b.ng.MaxPodsPerNode
+	}
+	if b.ng.ClusterDNS != "" {
+		kubeletConfig["clusterDNS"] = []string{b.ng.ClusterDNS}
+	}
+	if b.ng.KubeletExtraConfig != nil {
+		data, err := json.Marshal(b.ng.KubeletExtraConfig)
+		if err != nil {
+			return nil, err
+		}
+		// validate that data can be decoded as legit KubeletConfiguration
+		if err := json.Unmarshal(data, &kubeletapi.KubeletConfiguration{}); err != nil {
+			return nil, err
+		}
+		for k, v := range *b.ng.KubeletExtraConfig {
+			kubeletConfig[k] = v
+		}
+	}
+	if len(kubeletConfig) == 0 {
+		return nil, nil
+	}
+	return kubeletConfig, nil
+}
+
+func writePart(writer *multipart.Writer, contentType, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": []string{contentType},
+	})
+	if err != nil {
+		return errors.Wrap(err, "encoding user data")
+	}
+	_, err = part.Write([]byte(content))
+	return err
+}
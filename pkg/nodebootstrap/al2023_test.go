@@ -0,0 +1,197 @@
+package nodebootstrap_test
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("AmazonLinux2023 User Data", func() {
+	var (
+		clusterConfig *api.ClusterConfig
+		ng            *api.NodeGroup
+	)
+
+	type userDataPart struct {
+		contentType string
+		content     string
+	}
+
+	decodeParts := func(userData string) []userDataPart {
+		data, err := base64.StdEncoding.DecodeString(userData)
+		Expect(err).NotTo(HaveOccurred())
+		msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+		Expect(err).NotTo(HaveOccurred())
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mediaType).To(Equal("multipart/mixed"))
+
+		var parts []userDataPart
+		reader := multipart.NewReader(msg.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return parts
+			}
+			Expect(err).NotTo(HaveOccurred())
+			content, err := io.ReadAll(part)
+			Expect(err).NotTo(HaveOccurred())
+			parts = append(parts, userDataPart{contentType: part.Header.Get("Content-Type"), content: string(content)})
+		}
+	}
+
+	BeforeEach(func() {
+		clusterConfig = api.NewClusterConfig()
+		clusterConfig.Metadata.Name = "al2023-test"
+		clusterConfig.Status = &api.ClusterStatus{
+			Endpoint:                 "https://test.eks.amazonaws.com",
+			CertificateAuthorityData: []byte("CA"),
+			KubernetesNetworkConfig: &api.KubernetesNetworkConfig{
+				ServiceIPv4CIDR: "10.100.0.0/16",
+			},
+		}
+		ng = &api.NodeGroup{
+			NodeGroupBase: &api.NodeGroupBase{
+				AMIFamily: api.NodeImageFamilyAmazonLinux2023,
+			},
+		}
+	})
+
+	It("renders a NodeConfig for nodeadm", func() {
+		userData, err := newBootstrapper(clusterConfig, ng).UserData()
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := decodeParts(userData)
+		Expect(parts).To(HaveLen(1))
+		Expect(parts[0].contentType).To(Equal("application/node.eks.aws"))
+		Expect(parts[0].content).To(MatchYAML(`
+apiVersion: node.eks.aws/v1alpha1
+kind: NodeConfig
+spec:
+  cluster:
+    name: al2023-test
+    apiServerEndpoint: https://test.eks.amazonaws.com
+    certificateAuthority: Q0E=
+    cidr: 10.100.0.0/16
+  kubelet:
+    config:
+      clusterDNS:
+      - 10.100.0.10
+`))
+	})
+
+	It("passes the settings of the nodegroup to the kubelet", func() {
+		ng.MaxPodsPerNode = 17
+		ng.Labels = map[string]string{"role": "worker"}
+		ng.Taints = []api.NodeGroupTaint{
+			{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"},
+		}
+		ng.KubeletExtraConfig = &api.InlineDocument{
+			"maxPods":         20,
+			"kubeReserved":    map[string]interface{}{"cpu": "300m"},
+			"evictionHard":    map[string]interface{}{"memory.available": "200Mi"},
+			"featureGates":    map[string]interface{}{"RotateKubeletServerCertificate": true},
+			"registryPullQPS": 10,
+		}
+
+		userData, err := newBootstrapper(clusterConfig, ng).UserData()
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := decodeParts(userData)
+		Expect(parts).To(HaveLen(1))
+		Expect(parts[0].content).To(MatchYAML(`
+apiVersion: node.eks.aws/v1alpha1
+kind: NodeConfig
+spec:
+  cluster:
+    name: al2023-test
+    apiServerEndpoint: https://test.eks.amazonaws.com
+    certificateAuthority: Q0E=
+    cidr: 10.100.0.0/16
+  kubelet:
+    config:
+      clusterDNS:
+      - 10.100.0.10
+      maxPods: 20
+      kubeReserved:
+        cpu: 300m
+      evictionHard:
+        memory.available: 200Mi
+      featureGates:
+        RotateKubeletServerCertificate: true
+      registryPullQPS: 10
+    flags:
+    - --node-labels=role=worker
+    - --register-with-taints=dedicated=gpu:NoSchedule
+`))
+	})
+
+	It("runs the preBootstrapCommands before nodeadm", func() {
+		ng.PreBootstrapCommands = []string{"echo hello", "echo world"}
+
+		userData, err := newBootstrapper(clusterConfig, ng).UserData()
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := decodeParts(userData)
+		Expect(parts).To(HaveLen(2))
+		Expect(parts[0].contentType).To(Equal(`text/x-shellscript; charset="us-ascii"`))
+		Expect(parts[0].content).To(Equal("#!/bin/bash\nset -o errexit\necho hello\necho world\n"))
+		Expect(parts[1].contentType).To(Equal("application/node.eks.aws"))
+	})
+
+	It("adds the public keys of the key pairs after the first one to the authorized_keys of ec2-user", func() {
+		ng.SSH = &api.NodeGroupSSH{
+			Allow:                api.Enabled(),
+			PublicKeyNames:       []string{"primary", "escrow"},
+			AdditionalPublicKeys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEscrowKey escrow"},
+		}
+		ng.PreBootstrapCommands = []string{"echo hello"}
+
+		userData, err := newBootstrapper(clusterConfig, ng).UserData()
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := decodeParts(userData)
+		Expect(parts).To(HaveLen(2))
+		Expect(parts[0].content).To(Equal(`#!/bin/bash
+set -o errexit
+install -d -m 700 -o ec2-user -g ec2-user /home/ec2-user/.ssh
+cat >> /home/ec2-user/.ssh/authorized_keys <<'EOF'
+ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEscrowKey escrow
+EOF
+chown ec2-user:ec2-user /home/ec2-user/.ssh/authorized_keys
+chmod 600 /home/ec2-user/.ssh/authorized_keys
+echo hello
+`))
+	})
+
+	It("uses the service IPv6 CIDR of IPv6 clusters", func() {
+		clusterConfig.Status.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{
+			ServiceIPv6CIDR: "fd00:ec2::/108",
+		}
+		ng.ClusterDNS = "fd00:ec2::a"
+
+		userData, err := newBootstrapper(clusterConfig, ng).UserData()
+		Expect(err).NotTo(HaveOccurred())
+
+		parts := decodeParts(userData)
+		Expect(parts[0].content).To(ContainSubstring("cidr: fd00:ec2::/108"))
+	})
+
+	It("rejects a kubeletExtraConfig that is not a KubeletConfiguration", func() {
+		ng.KubeletExtraConfig = &api.InlineDocument{
+			"maxPods": aws.String("many"),
+		}
+
+		_, err := newBootstrapper(clusterConfig, ng).UserData()
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -292,6 +292,27 @@ CONTAINER_RUNTIME=`, "\n")))
 		})
 	})
 
+	When("the cluster is an IPv6 cluster", func() {
+		BeforeEach(func() {
+			clusterConfig.KubernetesNetworkConfig.IPFamily = api.IPV6Family
+			clusterConfig.Status.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{
+				ServiceIPv6CIDR: "fd30:1c53:5f8a::/108",
+			}
+			bootstrapper = newBootstrapper(clusterConfig, ng)
+		})
+
+		It("configures the IP family and the service IPv6 CIDR in the env file", func() {
+			userData, err := bootstrapper.UserData()
+			Expect(err).NotTo(HaveOccurred())
+
+			cloudCfg := decode(userData)
+			Expect(cloudCfg.WriteFiles[1].Path).To(Equal("/etc/eksctl/kubelet.env"))
+			Expect(cloudCfg.WriteFiles[1].Content).To(ContainSubstring("IP_FAMILY=ipv6"))
+			Expect(cloudCfg.WriteFiles[1].Content).To(ContainSubstring("SERVICE_IPV6_CIDR=fd30:1c53:5f8a::/108"))
+			Expect(cloudCfg.WriteFiles[1].Content).To(ContainSubstring("CLUSTER_DNS=fd30:1c53:5f8a::a"))
+		})
+	})
+
 	When("PreBootstrapCommands are set", func() {
 		BeforeEach(func() {
 			ng.PreBootstrapCommands = []string{"echo 'rubarb'"}
@@ -307,6 +328,32 @@ CONTAINER_RUNTIME=`, "\n")))
 		})
 	})
 
+	When("several SSH key pairs are set", func() {
+		BeforeEach(func() {
+			ng.SSH = &api.NodeGroupSSH{
+				Allow:                api.Enabled(),
+				PublicKeyNames:       []string{"primary", "escrow"},
+				AdditionalPublicKeys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEscrowKey escrow"},
+			}
+			ng.PreBootstrapCommands = []string{"echo 'rubarb'"}
+			bootstrapper = newBootstrapper(clusterConfig, ng)
+		})
+
+		It("adds the public keys of the key pairs after the first one to the authorized_keys of ec2-user", func() {
+			userData, err := bootstrapper.UserData()
+			Expect(err).NotTo(HaveOccurred())
+
+			cloudCfg := decode(userData)
+			Expect(cloudCfg.Commands[0]).To(Equal([]interface{}{"/bin/bash", "-c", `install -d -m 700 -o ec2-user -g ec2-user /home/ec2-user/.ssh
+cat >> /home/ec2-user/.ssh/authorized_keys <<'EOF'
+ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEscrowKey escrow
+EOF
+chown ec2-user:ec2-user /home/ec2-user/.ssh/authorized_keys
+chmod 600 /home/ec2-user/.ssh/authorized_keys`}))
+			Expect(cloudCfg.Commands[1]).To(ContainElement("echo 'rubarb'"))
+		})
+	})
+
 	When("OverrideBootstrapCommand is set", func() {
 		var (
 			err      error
@@ -6,13 +6,20 @@ set -o nounset
 
 source /var/lib/cloud/scripts/eksctl/bootstrap.helper.sh
 
+IP_FAMILY_ARGS=()
+if [[ "${IP_FAMILY}" == "ipv6" ]]; then
+  IP_FAMILY_ARGS=(--ip-family ipv6 --service-ipv6-cidr "${SERVICE_IPV6_CIDR}")
+fi
+
 echo "eksctl: running /etc/eks/bootstrap"
+# expanding an empty array fails with nounset in the bash of AL2, hence the ${var[@]+...} form
 /etc/eks/bootstrap.sh "${CLUSTER_NAME}" \
   --apiserver-endpoint "${API_SERVER_URL}" \
   --b64-cluster-ca "${B64_CLUSTER_CA}" \
   --dns-cluster-ip "${CLUSTER_DNS}" \
   --kubelet-extra-args "${KUBELET_EXTRA_ARGS}" \
-  --container-runtime "${CONTAINER_RUNTIME}"
+  --container-runtime "${CONTAINER_RUNTIME}" \
+  ${IP_FAMILY_ARGS[@]+"${IP_FAMILY_ARGS[@]}"}
 
 echo "eksctl: merging user options into kubelet-config.json"
 trap 'rm -f ${TMP_KUBE_CONF}' EXIT
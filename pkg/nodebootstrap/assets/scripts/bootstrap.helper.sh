@@ -32,3 +32,5 @@ KUBELET_CONFIG='/etc/kubernetes/kubelet/kubelet-config.json'
 KUBELET_EXTRA_CONFIG='/etc/eksctl/kubelet-extra.json'
 TMP_KUBE_CONF='/tmp/kubelet-conf.json'
 CONTAINER_RUNTIME="${CONTAINER_RUNTIME:-dockerd}" # default for al2 just in case, not used in ubuntu
+IP_FAMILY="${IP_FAMILY:-ipv4}"
+SERVICE_IPV6_CIDR="${SERVICE_IPV6_CIDR:-}"
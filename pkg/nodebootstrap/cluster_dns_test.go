@@ -43,6 +43,15 @@ var _ = DescribeTable("Cluster DNS", func(c clusterDNSEntry) {
 		expectedClusterDNS: "172.16.0.10",
 	}),
 
+	Entry("ServiceIPv6CIDR", clusterDNSEntry{
+		clusterStatus: &api.ClusterStatus{
+			KubernetesNetworkConfig: &api.KubernetesNetworkConfig{
+				ServiceIPv6CIDR: "fd30:1c53:5f8a::/108",
+			},
+		},
+		expectedClusterDNS: "fd30:1c53:5f8a::a",
+	}),
+
 	Entry("empty ServiceIPv4CIDR", clusterDNSEntry{
 		clusterStatus:      &api.ClusterStatus{},
 		expectedClusterDNS: "",
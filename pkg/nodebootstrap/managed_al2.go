@@ -42,6 +42,9 @@ func (m *ManagedAL2) UserData() (string, error) {
 		cloudboot []string
 	)
 
+	if authorizedKeys := makeAuthorizedKeysCommands(ng.NodeGroupBase); authorizedKeys != "" {
+		scripts = append(scripts, "#!/bin/bash\n"+authorizedKeys)
+	}
 	if len(ng.PreBootstrapCommands) > 0 {
 		scripts = append(scripts, ng.PreBootstrapCommands...)
 	}
@@ -73,6 +76,9 @@ func makeCustomAMIUserData(ng *api.NodeGroupBase, mimeBoundary string) (string,
 		scripts []string
 	)
 
+	if authorizedKeys := makeAuthorizedKeysCommands(ng); authorizedKeys != "" {
+		scripts = append(scripts, "#!/bin/bash\n"+authorizedKeys)
+	}
 	if len(ng.PreBootstrapCommands) > 0 {
 		scripts = append(scripts, ng.PreBootstrapCommands...)
 	}
@@ -166,6 +166,31 @@ NODE_TAINTS=`, "\n")))
 		})
 	})
 
+	When("several SSH key pairs are set", func() {
+		BeforeEach(func() {
+			ng.SSH = &api.NodeGroupSSH{
+				Allow:                api.Enabled(),
+				PublicKeyNames:       []string{"primary", "escrow", "audit"},
+				AdditionalPublicKeys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEscrowKey escrow", "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAuditKey audit"},
+			}
+			bootstrapper = newBootstrapper(clusterConfig, ng)
+		})
+
+		It("adds the public keys of the key pairs after the first one to the authorized_keys of ubuntu", func() {
+			userData, err := bootstrapper.UserData()
+			Expect(err).NotTo(HaveOccurred())
+
+			cloudCfg := decode(userData)
+			Expect(cloudCfg.Commands[0]).To(ContainElement(`install -d -m 700 -o ubuntu -g ubuntu /home/ubuntu/.ssh
+cat >> /home/ubuntu/.ssh/authorized_keys <<'EOF'
+ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEscrowKey escrow
+ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAuditKey audit
+EOF
+chown ubuntu:ubuntu /home/ubuntu/.ssh/authorized_keys
+chmod 600 /home/ubuntu/.ssh/authorized_keys`))
+		})
+	})
+
 	When("OverrideBootstrapCommand is set", func() {
 		var (
 			err      error
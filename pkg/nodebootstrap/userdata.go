@@ -53,6 +53,8 @@ func NewBootstrapper(clusterConfig *api.ClusterConfig, ng *api.NodeGroup) (Boots
 		return NewBottlerocketBootstrapper(clusterConfig, ng), nil
 	case api.NodeImageFamilyAmazonLinux2:
 		return NewAL2Bootstrapper(clusterConfig, ng), nil
+	case api.NodeImageFamilyAmazonLinux2023:
+		return NewAL2023Bootstrapper(clusterConfig, ng), nil
 	default:
 		return nil, errors.Errorf("unrecognized AMI family %q for creating bootstrapper", ng.AMIFamily)
 
@@ -79,6 +81,16 @@ func GetClusterDNS(clusterConfig *api.ClusterConfig) (string, error) {
 		return "", nil
 	}
 
+	if networkConfig.ServiceIPv6CIDR != "" {
+		ip, _, err := net.ParseCIDR(networkConfig.ServiceIPv6CIDR)
+		if err != nil {
+			return "", errors.Wrapf(err, "unexpected error parsing the service IPv6 CIDR: %q", networkConfig.ServiceIPv6CIDR)
+		}
+		ip = ip.To16()
+		ip[net.IPv6len-1] = 10
+		return ip.String(), nil
+	}
+
 	ip, _, err := net.ParseCIDR(networkConfig.ServiceIPv4CIDR)
 	if err != nil {
 		return "", errors.Wrapf(err, "unexpected error parsing kubernetesNetworkConfig.serviceIPv4CIDR: %q", networkConfig.ServiceIPv4CIDR)
@@ -92,6 +104,9 @@ func linuxConfig(clusterConfig *api.ClusterConfig, bootScriptName, bootScriptCon
 	config := cloudconfig.New()
 	ng := np.BaseNodeGroup()
 
+	if authorizedKeys := makeAuthorizedKeysCommands(ng); authorizedKeys != "" {
+		config.AddShellCommand(authorizedKeys)
+	}
 	for _, command := range ng.PreBootstrapCommands {
 		config.AddShellCommand(command)
 	}
@@ -131,6 +146,22 @@ func linuxConfig(clusterConfig *api.ClusterConfig, bootScriptName, bootScriptCon
 	return body, nil
 }
 
+// makeAuthorizedKeysCommands returns the commands adding the additional SSH public keys of the nodegroup to the
+// authorized_keys of the default user of its AMI family, or an empty string if it has none
+func makeAuthorizedKeysCommands(ng *api.NodeGroupBase) string {
+	if ng.SSH == nil || !api.IsEnabled(ng.SSH.Allow) || len(ng.SSH.AdditionalPublicKeys) == 0 {
+		return ""
+	}
+	user := api.DefaultSSHUser(ng.AMIFamily)
+	sshDir := fmt.Sprintf("/home/%s/.ssh", user)
+	return fmt.Sprintf(`install -d -m 700 -o %[1]s -g %[1]s %[2]s
+cat >> %[2]s/authorized_keys <<'EOF'
+%[3]s
+EOF
+chown %[1]s:%[1]s %[2]s/authorized_keys
+chmod 600 %[2]s/authorized_keys`, user, sshDir, strings.Join(ng.SSH.AdditionalPublicKeys, "\n"))
+}
+
 func makeKubeletExtraConf(kubeletExtraConf *api.InlineDocument) (cloudconfig.File, error) {
 	if kubeletExtraConf == nil {
 		kubeletExtraConf = &api.InlineDocument{}
@@ -173,6 +204,14 @@ func makeBootstrapEnv(clusterConfig *api.ClusterConfig, np api.NodePool) cloudco
 		variables["CONTAINER_RUNTIME"] = unmanaged.GetContainerRuntime()
 	}
 
+	if _, ok := np.(*api.NodeGroup); ok && ng.AMIFamily == api.NodeImageFamilyAmazonLinux2 && clusterConfig.IPv6Enabled() {
+		// bootstrap.sh expects lower-case IP families
+		variables["IP_FAMILY"] = strings.ToLower(api.IPV6Family)
+		if networkConfig := clusterConfig.Status.KubernetesNetworkConfig; networkConfig != nil {
+			variables["SERVICE_IPV6_CIDR"] = networkConfig.ServiceIPv6CIDR
+		}
+	}
+
 	return cloudconfig.File{
 		Path:    configDir + envFile,
 		Content: makeKeyValues(variables, "\n"),
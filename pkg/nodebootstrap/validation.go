@@ -0,0 +1,217 @@
+package nodebootstrap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"mvdan.cc/sh/v3/syntax"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// MaxUserDataSize is the maximum size of the user data of an EC2 instance, before it is base64-encoded
+const MaxUserDataSize = 16 * 1024
+
+// The endpoint and certificate authority used in place of those of a cluster that is not created yet, of the size of
+// the actual ones
+const (
+	placeholderEndpoint                 = "https://0123456789ABCDEF0123456789ABCDEF.gr7.us-west-2.eks.amazonaws.com"
+	placeholderCertificateAuthoritySize = 1100
+)
+
+// ValidateUserData checks the syntax of the bootstrap commands of the nodegroup, and that the user data generated for
+// it does not exceed MaxUserDataSize. If the cluster is not created yet, the user data is generated with placeholders
+// for its endpoint and certificate authority
+func ValidateUserData(clusterConfig *api.ClusterConfig, np api.NodePool) error {
+	ng := np.BaseNodeGroup()
+	if err := ValidateBootstrapCommands(ng); err != nil {
+		return err
+	}
+
+	if clusterConfig.Status == nil {
+		clusterConfig = clusterConfig.DeepCopy()
+		clusterConfig.Status = &api.ClusterStatus{
+			Endpoint:                 placeholderEndpoint,
+			CertificateAuthorityData: make([]byte, placeholderCertificateAuthoritySize),
+		}
+	}
+
+	var bootstrapper Bootstrapper
+	switch ng := np.(type) {
+	case *api.NodeGroup:
+		// the user data of a nodegroup using an existing launch template is set by the launch template
+		if ng.LaunchTemplate != nil {
+			return nil
+		}
+		b, err := NewBootstrapper(clusterConfig, ng)
+		if err != nil {
+			return err
+		}
+		bootstrapper = b
+	case *api.ManagedNodeGroup:
+		bootstrapper = NewManagedBootstrapper(clusterConfig, ng)
+	}
+	if bootstrapper == nil {
+		return nil
+	}
+
+	userData, err := bootstrapper.UserData()
+	if err != nil {
+		return errors.Wrapf(err, "generating user data of nodegroup %q", ng.Name)
+	}
+	data, err := base64.StdEncoding.DecodeString(userData)
+	if err != nil {
+		return errors.Wrapf(err, "decoding user data of nodegroup %q", ng.Name)
+	}
+	if len(data) > MaxUserDataSize {
+		return fmt.Errorf("the user data of nodegroup %q is %d bytes, exceeding the limit of %d bytes of EC2; reduce the size of preBootstrapCommands or overrideBootstrapCommand", ng.Name, len(data), MaxUserDataSize)
+	}
+	return nil
+}
+
+// ValidateBootstrapCommands checks the syntax of preBootstrapCommands and overrideBootstrapCommand. Commands of
+// Linux nodegroups are parsed as bash scripts, while the blocks of PowerShell commands of Windows nodegroups are
+// checked to be balanced
+func ValidateBootstrapCommands(ng *api.NodeGroupBase) error {
+	if ng.AMIFamily == api.NodeImageFamilyBottlerocket {
+		return nil
+	}
+
+	if api.IsWindowsImage(ng.AMIFamily) {
+		// PowerShell commands are run as a single script
+		if err := checkPowerShellBlocks(strings.Join(ng.PreBootstrapCommands, "\n")); err != nil {
+			return errors.Wrapf(err, "invalid preBootstrapCommands of nodegroup %q", ng.Name)
+		}
+		if ng.OverrideBootstrapCommand != nil {
+			if err := checkPowerShellBlocks(*ng.OverrideBootstrapCommand); err != nil {
+				return errors.Wrapf(err, "invalid overrideBootstrapCommand of nodegroup %q", ng.Name)
+			}
+		}
+		return nil
+	}
+
+	if ng.AMIFamily == api.NodeImageFamilyAmazonLinux2023 {
+		// the commands are joined into a single script, see AmazonLinux2023.UserData
+		if err := checkShellSyntax(strings.Join(ng.PreBootstrapCommands, "\n")); err != nil {
+			return errors.Wrapf(err, "invalid preBootstrapCommands of nodegroup %q", ng.Name)
+		}
+	} else {
+		// each command is run as a separate script
+		for i, command := range ng.PreBootstrapCommands {
+			if err := checkShellSyntax(command); err != nil {
+				return errors.Wrapf(err, "invalid preBootstrapCommands[%d] of nodegroup %q", i, ng.Name)
+			}
+		}
+	}
+	if ng.OverrideBootstrapCommand != nil {
+		if err := checkShellSyntax(*ng.OverrideBootstrapCommand); err != nil {
+			return errors.Wrapf(err, "invalid overrideBootstrapCommand of nodegroup %q", ng.Name)
+		}
+	}
+	return nil
+}
+
+// checkShellSyntax parses script as bash would with `bash -n`, without executing it, and returns the first syntax
+// error found, along with the line and column it was found at
+func checkShellSyntax(script string) error {
+	_, err := syntax.NewParser(syntax.Variant(syntax.LangBash)).Parse(strings.NewReader(script), "")
+	var parseErr syntax.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("line %d, column %d: %s", parseErr.Pos.Line(), parseErr.Pos.Col(), parseErr.Text)
+	}
+	return err
+}
+
+// checkPowerShellBlocks checks that the braces, parentheses and brackets of a PowerShell script are balanced,
+// ignoring those in strings and comments
+func checkPowerShellBlocks(script string) error {
+	type block struct {
+		opening byte
+		line    int
+	}
+	var (
+		blocks   []block
+		line     = 1
+		openings = map[byte]byte{'{': '}', '(': ')', '[': ']'}
+		closings = map[byte]byte{'}': '{', ')': '(', ']': '['}
+	)
+
+	// skipTo skips past the end marker, returning false if the script ends before it
+	skipTo := func(i int, end string) (int, bool) {
+		j := strings.Index(script[i:], end)
+		if j < 0 {
+			return len(script), false
+		}
+		line += strings.Count(script[i:i+j+len(end)], "\n")
+		return i + j + len(end), true
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == '`':
+			if i+1 < len(script) && script[i+1] == '\n' {
+				line++
+			}
+			i += 2
+		case strings.HasPrefix(script[i:], "<#"):
+			start := line
+			var ok bool
+			if i, ok = skipTo(i+2, "#>"); !ok {
+				return fmt.Errorf("line %d: block comment is not closed", start)
+			}
+		case c == '#':
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(script[i:], "@'\n"), strings.HasPrefix(script[i:], "@\"\n"):
+			start := line
+			var ok bool
+			if i, ok = skipTo(i+2, "\n"+string(script[i+1])+"@"); !ok {
+				return fmt.Errorf("line %d: here-string is not closed", start)
+			}
+		case c == '\'' || c == '"':
+			start := line
+			i++
+			for ; i < len(script) && script[i] != c; i++ {
+				switch script[i] {
+				case '\n':
+					line++
+				case '`':
+					if c == '"' {
+						i++
+					}
+				}
+			}
+			if i >= len(script) {
+				return fmt.Errorf("line %d: missing the terminator: %c", start, c)
+			}
+			i++
+		case openings[c] != 0:
+			blocks = append(blocks, block{opening: c, line: line})
+			i++
+		case closings[c] != 0:
+			if len(blocks) == 0 {
+				return fmt.Errorf("line %d: unexpected token `%c'", line, c)
+			}
+			if last := blocks[len(blocks)-1]; last.opening != closings[c] {
+				return fmt.Errorf("line %d: unexpected token `%c', `%c' at line %d is not closed", line, c, last.opening, last.line)
+			}
+			blocks = blocks[:len(blocks)-1]
+			i++
+		default:
+			i++
+		}
+	}
+
+	if len(blocks) > 0 {
+		last := blocks[len(blocks)-1]
+		return fmt.Errorf("line %d: missing closing `%c' for `%c'", last.line, openings[last.opening], last.opening)
+	}
+	return nil
+}
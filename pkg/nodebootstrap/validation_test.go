@@ -0,0 +1,196 @@
+package nodebootstrap_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
+)
+
+var _ = Describe("User data validation", func() {
+	type commandsEntry struct {
+		amiFamily                string
+		preBootstrapCommands     []string
+		overrideBootstrapCommand *string
+
+		expectedErr string
+	}
+
+	DescribeTable("ValidateBootstrapCommands", func(e commandsEntry) {
+		ng := &api.NodeGroupBase{
+			Name:                     "ng",
+			AMIFamily:                e.amiFamily,
+			PreBootstrapCommands:     e.preBootstrapCommands,
+			OverrideBootstrapCommand: e.overrideBootstrapCommand,
+		}
+		err := nodebootstrap.ValidateBootstrapCommands(ng)
+		if e.expectedErr == "" {
+			Expect(err).NotTo(HaveOccurred())
+		} else {
+			Expect(err).To(MatchError(e.expectedErr))
+		}
+	},
+		Entry("valid bash commands", commandsEntry{
+			amiFamily: api.NodeImageFamilyAmazonLinux2,
+			preBootstrapCommands: []string{
+				"yum install -y jq && echo installed || exit 1",
+				`for f in /etc/eks/*.sh; do
+  if [ -x "$f" ]; then
+    echo "$(basename "$f")"
+  fi
+done`,
+				`cat <<EOF > /etc/profile.d/proxy.sh
+export HTTPS_PROXY=http://proxy:3128
+EOF`,
+				`case "$(uname -m)" in
+  x86_64) ARCH=amd64 ;;
+  aarch64) ARCH=arm64 ;;
+esac`,
+			},
+			overrideBootstrapCommand: aws.String(`#!/bin/bash
+set -ex
+/etc/eks/bootstrap.sh my-cluster --kubelet-extra-args "--node-labels=${NODE_LABELS}"`),
+		}),
+		Entry("an unclosed if in preBootstrapCommands", commandsEntry{
+			amiFamily: api.NodeImageFamilyUbuntu2004,
+			preBootstrapCommands: []string{
+				"echo ok",
+				"if [ -f /etc/ready ]; then\n  echo ready\n",
+			},
+			expectedErr: "invalid preBootstrapCommands[1] of nodegroup \"ng\": line 1, column 1: if statement must end with \"fi\"",
+		}),
+		Entry("an unexpected token in preBootstrapCommands", commandsEntry{
+			amiFamily:            api.NodeImageFamilyAmazonLinux2,
+			preBootstrapCommands: []string{"while true; do\n  sleep 1\ndone\nfi"},
+			expectedErr:          "invalid preBootstrapCommands[0] of nodegroup \"ng\": line 4, column 1: \"fi\" can only be used to end an if",
+		}),
+		Entry("an unterminated quote in overrideBootstrapCommand", commandsEntry{
+			amiFamily:                api.NodeImageFamilyAmazonLinux2,
+			overrideBootstrapCommand: aws.String("#!/bin/bash\n/etc/eks/bootstrap.sh my-cluster --kubelet-extra-args '--node-labels=a=b\n"),
+			expectedErr:              "invalid overrideBootstrapCommand of nodegroup \"ng\": line 2, column 55: reached EOF without closing quote '",
+		}),
+		Entry("an unterminated here-document", commandsEntry{
+			amiFamily:            api.NodeImageFamilyAmazonLinux2,
+			preBootstrapCommands: []string{"cat <<EOF > /tmp/config\nkey=value\nEFO"},
+			expectedErr:          "invalid preBootstrapCommands[0] of nodegroup \"ng\": line 1, column 5: unclosed here-document 'EOF'",
+		}),
+		Entry("valid PowerShell commands", commandsEntry{
+			amiFamily: api.NodeImageFamilyWindowsServer2019CoreContainer,
+			preBootstrapCommands: []string{
+				`if (-not (Test-Path "C:\temp")) {`,
+				`  New-Item -ItemType Directory -Path "C:\temp" # creates {`,
+				`}`,
+			},
+		}),
+		Entry("AmazonLinux2023 commands that are only valid as a single script", commandsEntry{
+			amiFamily: api.NodeImageFamilyAmazonLinux2023,
+			preBootstrapCommands: []string{
+				"if [ -f /etc/ready ]; then",
+				"  echo ready",
+				"fi",
+			},
+		}),
+		Entry("AmazonLinux2023 commands that are invalid as a single script", commandsEntry{
+			amiFamily: api.NodeImageFamilyAmazonLinux2023,
+			preBootstrapCommands: []string{
+				"echo ok",
+				"while true; do",
+			},
+			expectedErr: "invalid preBootstrapCommands of nodegroup \"ng\": line 2, column 13: \"do\" must be followed by a statement list",
+		}),
+		Entry("unbalanced PowerShell blocks", commandsEntry{
+			amiFamily: api.NodeImageFamilyWindowsServer2019FullContainer,
+			preBootstrapCommands: []string{
+				`foreach ($f in Get-ChildItem C:\temp) {`,
+				`  Remove-Item $f.FullName`,
+			},
+			expectedErr: "invalid preBootstrapCommands of nodegroup \"ng\": line 1: missing closing `}' for `{'",
+		}),
+	)
+
+	Describe("ValidateUserData", func() {
+		var clusterConfig *api.ClusterConfig
+
+		BeforeEach(func() {
+			clusterConfig = api.NewClusterConfig()
+			clusterConfig.Metadata.Name = "my-cluster"
+			clusterConfig.Status = &api.ClusterStatus{
+				Endpoint:                 "https://test.com",
+				CertificateAuthorityData: []byte("test"),
+			}
+		})
+
+		// randomScript returns a script that does not compress, as the user data of unmanaged nodegroups is gzipped
+		randomScript := func(size int) string {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			Expect(err).NotTo(HaveOccurred())
+			return "echo " + base64.StdEncoding.EncodeToString(data)
+		}
+
+		It("accepts user data within the EC2 limit", func() {
+			ng := api.NewNodeGroup()
+			ng.Name = "ng"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			ng.PreBootstrapCommands = []string{randomScript(1024)}
+			Expect(nodebootstrap.ValidateUserData(clusterConfig, ng)).To(Succeed())
+		})
+
+		It("rejects user data of unmanaged nodegroups exceeding the EC2 limit", func() {
+			ng := api.NewNodeGroup()
+			ng.Name = "ng"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			ng.PreBootstrapCommands = []string{randomScript(2 * nodebootstrap.MaxUserDataSize)}
+			Expect(nodebootstrap.ValidateUserData(clusterConfig, ng)).To(MatchError(ContainSubstring(`the user data of nodegroup "ng" is`)))
+		})
+
+		It("rejects user data of managed nodegroups exceeding the EC2 limit after MIME assembly", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.Name = "mng"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			ng.PreBootstrapCommands = []string{
+				"echo " + strings.Repeat("a", nodebootstrap.MaxUserDataSize/2),
+				"echo " + strings.Repeat("b", nodebootstrap.MaxUserDataSize/2),
+			}
+			Expect(nodebootstrap.ValidateUserData(clusterConfig, ng)).To(MatchError(ContainSubstring(`the user data of nodegroup "mng" is`)))
+		})
+
+		It("validates the bootstrap commands", func() {
+			ng := api.NewManagedNodeGroup()
+			ng.Name = "mng"
+			ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+			ng.PreBootstrapCommands = []string{"echo $(date"}
+			Expect(nodebootstrap.ValidateUserData(clusterConfig, ng)).To(MatchError(ContainSubstring(`invalid preBootstrapCommands[0] of nodegroup "mng"`)))
+		})
+
+		When("the cluster is not created yet", func() {
+			BeforeEach(func() {
+				clusterConfig.Status = nil
+			})
+
+			It("accepts user data within the EC2 limit", func() {
+				ng := api.NewNodeGroup()
+				ng.Name = "ng"
+				ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+				ng.PreBootstrapCommands = []string{randomScript(1024)}
+				Expect(nodebootstrap.ValidateUserData(clusterConfig, ng)).To(Succeed())
+				Expect(clusterConfig.Status).To(BeNil())
+			})
+
+			It("rejects user data exceeding the EC2 limit", func() {
+				ng := api.NewNodeGroup()
+				ng.Name = "ng"
+				ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+				ng.PreBootstrapCommands = []string{randomScript(2 * nodebootstrap.MaxUserDataSize)}
+				Expect(nodebootstrap.ValidateUserData(clusterConfig, ng)).To(MatchError(ContainSubstring(`the user data of nodegroup "ng" is`)))
+			})
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 
 	"github.com/aws/smithy-go"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
@@ -21,71 +22,82 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils/file"
 )
 
-// LoadKeyFromFile loads and imports a public SSH key from a file provided a path to that file.
-// returns the name of the key
-func LoadKeyFromFile(ctx context.Context, filePath, clusterName, ngName string, ec2API awsapi.EC2) (string, error) {
-	if !file.Exists(filePath) {
-		return "", fmt.Errorf("SSH public key file %q not found", filePath)
-	}
+// KeyPair is an EC2 key pair importing an SSH public key for a nodegroup
+type KeyPair struct {
+	// Name is in the form "eksctl-<clusterName>-nodegroup-<nodeGroupName>-<fingerprint>"
+	Name        string
+	Fingerprint string
+	PublicKey   string
+}
 
-	expandedPath := file.ExpandPath(filePath)
-	fileContent, err := readFileContents(expandedPath)
+// NewKeyPair returns the EC2 key pair that imports the SSH public key for the nodegroup
+func NewKeyPair(key, clusterName, ngName string) (*KeyPair, error) {
+	fingerprint, err := fingerprint(key)
 	if err != nil {
-		return "", errors.Wrap(err, fmt.Sprintf("reading SSH public key file %q", filePath))
+		return nil, err
 	}
+	return &KeyPair{
+		Name:        getKeyName(clusterName, ngName, fingerprint),
+		Fingerprint: fingerprint,
+		PublicKey:   key,
+	}, nil
+}
 
-	fingerprint, err := fingerprint(filePath, fileContent)
+// Exists returns whether the key pair was already imported in EC2, or an error if a key pair with the same name
+// but a different fingerprint exists
+func (k *KeyPair) Exists(ctx context.Context, ec2API awsapi.EC2) (bool, error) {
+	existing, err := findKeyInEC2(ctx, ec2API, k.Name, false)
 	if err != nil {
-		return "", err
+		return false, err
+	}
+	if existing == nil {
+		return false, nil
+	}
+	if *existing.KeyFingerprint != k.Fingerprint {
+		return false, fmt.Errorf("SSH public key %s already exists, but fingerprints don't match (exected: %q, got: %q)", k.Name, k.Fingerprint, *existing.KeyFingerprint)
 	}
+	return true, nil
+}
 
-	key := string(fileContent)
-	keyName := getKeyName(clusterName, ngName, fingerprint)
-	logger.Info("using SSH public key %q as %q ", expandedPath, keyName)
+// ReadKeyFile reads a public SSH key from a file provided a path to that file
+func ReadKeyFile(filePath string) (string, error) {
+	if !file.Exists(filePath) {
+		return "", fmt.Errorf("SSH public key file %q not found", filePath)
+	}
 
-	// Import SSH key in EC2
-	if err := importKey(ctx, keyName, fingerprint, &key, ec2API); err != nil {
+	fileContent, err := readFileContents(file.ExpandPath(filePath))
+	if err != nil {
 		return "", err
 	}
-	return keyName, nil
+	if _, err := fingerprint(string(fileContent)); err != nil {
+		return "", errors.Wrapf(err, "reading SSH public key file %q", filePath)
+	}
+	return string(fileContent), nil
 }
 
-func fingerprint(filePath string, key []byte) (string, error) {
-	pk, _, _, _, err := ssh.ParseAuthorizedKey(key)
+func fingerprint(key string) (string, error) {
+	pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
 	if err != nil {
-		return "", fmt.Errorf("parsing key %q: %w", filePath, err)
+		return "", fmt.Errorf("parsing key: %w", err)
 	}
 
 	if pk.Type() == "ssh-ed25519" {
 		return strings.TrimPrefix(ssh.FingerprintSHA256(pk), "SHA256:"), nil
 	}
 
-	fingerprint, err := pki.ComputeAWSKeyFingerprint(string(key))
+	fingerprint, err := pki.ComputeAWSKeyFingerprint(key)
 	if err != nil {
-		return "", errors.Wrap(err, fmt.Sprintf("computing fingerprint for key %q", filePath))
+		return "", errors.Wrap(err, "computing fingerprint for key")
 	}
 
 	return fingerprint, nil
 }
 
-// LoadKeyByContent loads and imports an SSH public key into EC2 if it doesn't exist
-func LoadKeyByContent(ctx context.Context, key *string, clusterName, ngName string, ec2API awsapi.EC2) (string, error) {
-	fingerprint, err := pki.ComputeAWSKeyFingerprint(*key)
-	if err != nil {
-		return "", errors.Wrap(err, fmt.Sprintf("computing fingerprint for key %q", *key))
-	}
-	keyName := getKeyName(clusterName, ngName, fingerprint)
-
-	logger.Info("using SSH public key %q ", *key)
+// cloudFormationStackNameTag is set by CloudFormation on the key pairs it creates
+const cloudFormationStackNameTag = "aws:cloudformation:stack-name"
 
-	// Import SSH key in EC2
-	if err := importKey(ctx, keyName, fingerprint, key, ec2API); err != nil {
-		return "", err
-	}
-	return keyName, nil
-}
-
-// DeleteKeys will delete the public SSH key, if it exists
+// DeleteKeys will delete the public SSH key, if it exists, unless it was created by the stack of a nodegroup, which
+// deletes it along with the stack
 func DeleteKeys(ctx context.Context, ec2API awsapi.EC2, clusterName string) {
 	existing, err := ec2API.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{})
 	if err != nil {
@@ -98,6 +110,10 @@ func DeleteKeys(ctx context.Context, ec2API awsapi.EC2, clusterName string) {
 		if !strings.HasPrefix(*e.KeyName, prefix) {
 			continue
 		}
+		if isCreatedByCloudFormation(e) {
+			logger.Debug("existing key %q is owned by a CloudFormation stack", *e.KeyName)
+			continue
+		}
 		nameParts := strings.Split(*e.KeyName, "-")
 		logger.Debug("existing key %q matches prefix", *e.KeyName)
 		if nameParts[len(nameParts)-1] == *e.KeyFingerprint {
@@ -116,9 +132,18 @@ func DeleteKeys(ctx context.Context, ec2API awsapi.EC2, clusterName string) {
 	}
 }
 
+func isCreatedByCloudFormation(keyPair ec2types.KeyPairInfo) bool {
+	for _, tag := range keyPair.Tags {
+		if aws.ToString(tag.Key) == cloudFormationStackNameTag {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckKeyExistsInEC2 returns whether a public ssh key already exists in EC2 or error if it couldn't be checked
 func CheckKeyExistsInEC2(ctx context.Context, ec2API awsapi.EC2, sshKeyName string) error {
-	existing, err := findKeyInEC2(ctx, ec2API, sshKeyName)
+	existing, err := findKeyInEC2(ctx, ec2API, sshKeyName, false)
 	if err != nil {
 		return errors.Wrap(err, "checking existing key pair")
 	}
@@ -129,29 +154,19 @@ func CheckKeyExistsInEC2(ctx context.Context, ec2API awsapi.EC2, sshKeyName stri
 	return nil
 }
 
-func importKey(ctx context.Context, keyName, fingerprint string, keyContent *string, ec2API awsapi.EC2) error {
-	if existing, err := findKeyInEC2(ctx, ec2API, keyName); err != nil {
-		return err
-	} else if existing != nil {
-		if *existing.KeyFingerprint != fingerprint {
-			return fmt.Errorf("SSH public key %s already exists, but fingerprints don't match (exected: %q, got: %q)", keyName, fingerprint, *existing.KeyFingerprint)
-		}
-
-		logger.Debug("SSH public key %s already exists", keyName)
-		return nil
+// GetPublicKey returns the public key of an existing EC2 key pair
+func GetPublicKey(ctx context.Context, ec2API awsapi.EC2, sshKeyName string) (string, error) {
+	existing, err := findKeyInEC2(ctx, ec2API, sshKeyName, true)
+	if err != nil {
+		return "", errors.Wrap(err, "getting public key of key pair")
 	}
-
-	// Import it
-	input := &ec2.ImportKeyPairInput{
-		KeyName:           &keyName,
-		PublicKeyMaterial: []byte(*keyContent),
+	if existing == nil {
+		return "", fmt.Errorf("cannot find EC2 key pair %q", sshKeyName)
 	}
-	logger.Debug("importing SSH public key %q", keyName)
-
-	if _, err := ec2API.ImportKeyPair(ctx, input); err != nil {
-		return errors.Wrap(err, "importing SSH public key")
+	if existing.PublicKey == nil {
+		return "", fmt.Errorf("EC2 key pair %q has no public key", sshKeyName)
 	}
-	return nil
+	return strings.TrimSpace(*existing.PublicKey), nil
 }
 
 // getKeyName generates the name of an SSH key based on the cluster name, nodegroup name and fingerprint
@@ -169,10 +184,13 @@ func getKeyName(clusterName, nodeGroupName, fingerprint string) string {
 	return strings.Join(keyNameParts, "-")
 }
 
-func findKeyInEC2(ctx context.Context, ec2API awsapi.EC2, name string) (*ec2types.KeyPairInfo, error) {
+func findKeyInEC2(ctx context.Context, ec2API awsapi.EC2, name string, includePublicKey bool) (*ec2types.KeyPairInfo, error) {
 	input := &ec2.DescribeKeyPairsInput{
 		KeyNames: []string{name},
 	}
+	if includePublicKey {
+		input.IncludePublicKey = aws.Bool(true)
+	}
 	output, err := ec2API.DescribeKeyPairs(ctx, input)
 
 	if err != nil {
@@ -2,8 +2,8 @@ package client
 
 import (
 	"context"
-	"errors"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
@@ -34,120 +34,83 @@ var _ = Describe("ssh public key", func() {
 		mockEC2 = &mocksv2.EC2{}
 	})
 
-	Describe("loading from a file", func() {
-
-		It("should import the key", func() {
-			mockDescribeKeyPairs(mockEC2, make(map[string]string))
-			mockImportKeyPair(mockEC2, keyName, rsaFingerprint, rsaKey)
-
-			keyName, err := LoadKeyFromFile(context.Background(), "assets/id_rsa_tests1.pub", clusterName, ngName, mockEC2)
+	Describe("reading from a file", func() {
+		It("should read the key", func() {
+			key, err := ReadKeyFile("assets/id_rsa_tests1.pub")
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(keyName).To(Equal("eksctl-sshtestcluster-nodegroup-ng1-f5:d9:01:88:1e:fb:40:fb:e1:ca:69:fe:2e:31:03:6c"))
-			mockEC2.AssertCalled(GinkgoT(),
-				"ImportKeyPair",
-				mock.Anything,
-				&ec2.ImportKeyPairInput{
-					KeyName:           &keyName,
-					PublicKeyMaterial: []byte(rsaKey),
-				})
+			Expect(key).To(Equal(rsaKey))
 		})
 
-		It("should not import key that already exists in EC2", func() {
-			mockDescribeKeyPairs(mockEC2, map[string]string{keyName: rsaFingerprint})
-			mockImportKeyPairError(mockEC2, errors.New("the key shouldn't be imported in this test"))
-
-			keyName, err := LoadKeyFromFile(context.Background(), "assets/id_rsa_tests1.pub", clusterName, ngName, mockEC2)
+		It("should return error if the file does not exist", func() {
+			_, err := ReadKeyFile("assets/file_not_existing.pub")
 
-			Expect(err).NotTo(HaveOccurred())
-			Expect(keyName).To(Equal("eksctl-sshtestcluster-nodegroup-ng1-f5:d9:01:88:1e:fb:40:fb:e1:ca:69:fe:2e:31:03:6c"))
-			mockEC2.AssertNotCalled(GinkgoT(), "ImportKeyPair", mock.Anything, mock.Anything)
+			Expect(err).To(HaveOccurred())
 		})
 
-		It("should return error if a key with same name exists in EC2 with different fingerprint", func() {
-			differentFingerprint := "ab:cd"
-			mockDescribeKeyPairs(mockEC2, map[string]string{keyName: differentFingerprint})
-			mockImportKeyPairError(mockEC2, errors.New("the key shouldn't be imported in this test"))
-
-			_, err := LoadKeyFromFile(context.Background(), "assets/id_rsa_tests1.pub", clusterName, ngName, mockEC2)
-
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("but fingerprints don't match"))
+		When("they key is invalid", func() {
+			It("errors", func() {
+				_, err := ReadKeyFile("assets/invalid.pub")
+				Expect(err).To(MatchError(ContainSubstring("reading SSH public key file \"assets/invalid.pub\": parsing key")))
+			})
 		})
+	})
 
-		It("should return error if the file does not exist", func() {
-			_, err := LoadKeyFromFile(context.Background(), "assets/file_not_existing.pub", clusterName, ngName, mockEC2)
+	Describe("key pair", func() {
+		It("should be named after the cluster, the nodegroup and the fingerprint of the key", func() {
+			keyPair, err := NewKeyPair(rsaKey, clusterName, ngName)
 
-			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*keyPair).To(Equal(KeyPair{
+				Name:        keyName,
+				Fingerprint: rsaFingerprint,
+				PublicKey:   rsaKey,
+			}))
 		})
 
 		When("they key is of type ed25519", func() {
-			It("loads the key", func() {
-				mockDescribeKeyPairs(mockEC2, make(map[string]string))
-				mockImportKeyPair(mockEC2, ed25519KeyName, ed25519Fingerprint, ed25519Key)
-
-				keyName, err := LoadKeyFromFile(context.Background(), "assets/id_ed25519_tests1.pub", clusterName, ngName, mockEC2)
+			It("uses its SHA256 fingerprint", func() {
+				keyPair, err := NewKeyPair(ed25519Key, clusterName, ngName)
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(keyName).To(Equal("eksctl-sshtestcluster-nodegroup-ng1-HvE7+gmH78VS53+iPuRDh/gKjVo26OzYU/qOnJWAgyk"))
-				mockEC2.AssertCalled(GinkgoT(),
-					"ImportKeyPair",
-					mock.Anything,
-					&ec2.ImportKeyPairInput{
-						KeyName:           &keyName,
-						PublicKeyMaterial: []byte(ed25519Key),
-					})
+				Expect(keyPair.Name).To(Equal(ed25519KeyName))
+				Expect(keyPair.Fingerprint).To(Equal(ed25519Fingerprint))
 			})
 		})
 
-		When("they key is invalid", func() {
-			It("errors", func() {
-				_, err := LoadKeyFromFile(context.Background(), "assets/invalid.pub", clusterName, ngName, mockEC2)
-				Expect(err).To(MatchError(ContainSubstring("parsing key \"assets/invalid.pub\"")))
-			})
-		})
-	})
-
-	Describe("loading by the key content", func() {
-		It("should import it", func() {
+		It("should not exist if it was not imported in EC2", func() {
 			mockDescribeKeyPairs(mockEC2, make(map[string]string))
-			mockImportKeyPair(mockEC2, keyName, rsaFingerprint, rsaKey)
+			keyPair, err := NewKeyPair(rsaKey, clusterName, ngName)
+			Expect(err).NotTo(HaveOccurred())
 
-			keyName, err := LoadKeyByContent(context.Background(), &rsaKey, clusterName, ngName, mockEC2)
+			exists, err := keyPair.Exists(context.Background(), mockEC2)
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(keyName).To(Equal("eksctl-sshtestcluster-nodegroup-ng1-f5:d9:01:88:1e:fb:40:fb:e1:ca:69:fe:2e:31:03:6c"))
-			mockEC2.AssertCalled(GinkgoT(),
-				"ImportKeyPair",
-				mock.Anything,
-				&ec2.ImportKeyPairInput{
-					KeyName:           &keyName,
-					PublicKeyMaterial: []byte(rsaKey),
-				})
+			Expect(exists).To(BeFalse())
 		})
 
-		It("should not import key that already exists in EC2", func() {
+		It("should exist if it was already imported in EC2", func() {
 			mockDescribeKeyPairs(mockEC2, map[string]string{keyName: rsaFingerprint})
-			mockImportKeyPairError(mockEC2, errors.New("the key shouldn't be imported in this test"))
+			keyPair, err := NewKeyPair(rsaKey, clusterName, ngName)
+			Expect(err).NotTo(HaveOccurred())
 
-			keyName, err := LoadKeyByContent(context.Background(), &rsaKey, clusterName, ngName, mockEC2)
+			exists, err := keyPair.Exists(context.Background(), mockEC2)
 
 			Expect(err).NotTo(HaveOccurred())
-			Expect(keyName).To(Equal("eksctl-sshtestcluster-nodegroup-ng1-f5:d9:01:88:1e:fb:40:fb:e1:ca:69:fe:2e:31:03:6c"))
-			mockEC2.AssertNotCalled(GinkgoT(), "ImportKeyPair", mock.Anything, mock.Anything)
+			Expect(exists).To(BeTrue())
 		})
 
 		It("should return error if a key with same name exists in EC2 with different fingerprint", func() {
 			differentFingerprint := "ab:cd"
 			mockDescribeKeyPairs(mockEC2, map[string]string{keyName: differentFingerprint})
-			mockImportKeyPairError(mockEC2, errors.New("the key shouldn't be imported in this test"))
+			keyPair, err := NewKeyPair(rsaKey, clusterName, ngName)
+			Expect(err).NotTo(HaveOccurred())
 
-			_, err := LoadKeyByContent(context.Background(), &rsaKey, clusterName, ngName, mockEC2)
+			_, err = keyPair.Exists(context.Background(), mockEC2)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("but fingerprints don't match"))
 		})
-
 	})
 
 	Describe("deletion", func() {
@@ -180,6 +143,33 @@ var _ = Describe("ssh public key", func() {
 					KeyName: &keyToDelete2,
 				})
 		})
+
+		It("should not delete keys created by the stack of a nodegroup", func() {
+			keyToDelete := "eksctl-sshtestcluster-nodegroup-ng1-ab"
+			stackKeyPair := toKeyPairInfo(map[string]string{"eksctl-sshtestcluster-nodegroup-ng2-cd": "cd"})[0]
+			stackKeyPair.Tags = []ec2types.Tag{
+				{
+					Key:   aws.String("aws:cloudformation:stack-name"),
+					Value: aws.String("eksctl-sshtestcluster-nodegroup-ng2"),
+				},
+			}
+			mockEC2.
+				On("DescribeKeyPairs", mock.Anything, mock.Anything).
+				Return(&ec2.DescribeKeyPairsOutput{
+					KeyPairs: append(toKeyPairInfo(map[string]string{keyToDelete: "ab"}), stackKeyPair),
+				}, nil)
+			mockDeleteKeyPair(mockEC2)
+
+			DeleteKeys(context.Background(), mockEC2, clusterName)
+
+			mockEC2.AssertNumberOfCalls(GinkgoT(), "DeleteKeyPair", 1)
+			mockEC2.AssertCalled(GinkgoT(),
+				"DeleteKeyPair",
+				mock.Anything,
+				&ec2.DeleteKeyPairInput{
+					KeyName: &keyToDelete,
+				})
+		})
 	})
 
 	Describe("checking in EC2", func() {
@@ -241,28 +231,6 @@ func mockDescribeKeyPairs(mockEC2 *mocksv2.EC2, keys map[string]string) {
 		}, nil)
 }
 
-func mockImportKeyPairError(mockEC2 *mocksv2.EC2, err error) {
-	if err != nil {
-		mockEC2.
-			On("ImportKeyPair", mock.Anything, mock.Anything).
-			Return(nil, err)
-	}
-}
-
-func mockImportKeyPair(mockEC2 *mocksv2.EC2, keyName, fingerprint, key string) {
-	mockEC2.
-		On("ImportKeyPair",
-			mock.Anything,
-			mock.MatchedBy(func(input *ec2.ImportKeyPairInput) bool {
-				return *input.KeyName == keyName &&
-					string(input.PublicKeyMaterial) == key
-			})).
-		Return(&ec2.ImportKeyPairOutput{
-			KeyName:        &keyName,
-			KeyFingerprint: &fingerprint,
-		}, nil)
-}
-
 func toKeyPairInfo(keys map[string]string) []ec2types.KeyPairInfo {
 	var keyPairs []ec2types.KeyPairInfo
 	for k, v := range keys {
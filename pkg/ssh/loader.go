@@ -2,6 +2,8 @@ package ssh
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/kris-nova/logger"
 
@@ -11,49 +13,93 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils/file"
 )
 
-// LoadKey loads the SSH public key specified in NodeGroupSSH and returns it. The key should be specified
-// in only one way: by name (for a key existing in EC2), by path (for a key in a local file)
-// or by its contents (in the config-file). It also assumes that if ssh is enabled (SSH.Allow
-// == true) then one key was specified
-func LoadKey(ctx context.Context, sshConfig *api.NodeGroupSSH, clusterName, nodeGroupName string, ec2API awsapi.EC2) (string, error) {
+// LoadKey resolves the SSH public key specified in NodeGroupSSH. The key should be specified in exactly one way:
+// by name (referencing a key pair existing in EC2), by path (for a key in a local file) or by its contents (in the
+// config-file). A key specified by path or contents is imported as a new EC2 key pair by the nodegroup stack, so
+// a key read from a file is set as PublicKey; if that key pair was already imported, it is referenced by name
+// instead. Several key pairs can be referenced by name, in which case the public keys of all but the first one
+// are set as AdditionalPublicKeys. It also assumes that if ssh is enabled (SSH.Allow == true) then one key was
+// specified
+func LoadKey(ctx context.Context, sshConfig *api.NodeGroupSSH, clusterName, nodeGroupName string, ec2API awsapi.EC2) error {
 	if sshConfig.Allow == nil || !*sshConfig.Allow {
-		return "", nil
+		return nil
 	}
 
 	switch {
 
-	// Load Key by content
-	case sshConfig.PublicKey != nil:
-		keyName, err := client.LoadKeyByContent(ctx, sshConfig.PublicKey, clusterName, nodeGroupName, ec2API)
-		if err != nil {
-			return "", err
+	// Use keys by name in EC2, the first one being set in the launch template
+	case len(sshConfig.PublicKeyNames) > 0:
+		if err := client.CheckKeyExistsInEC2(ctx, ec2API, sshConfig.PublicKeyNames[0]); err != nil {
+			return err
+		}
+		sshConfig.AdditionalPublicKeys = nil
+		for _, keyName := range sshConfig.PublicKeyNames[1:] {
+			publicKey, err := client.GetPublicKey(ctx, ec2API, keyName)
+			if err != nil {
+				return err
+			}
+			sshConfig.AdditionalPublicKeys = append(sshConfig.AdditionalPublicKeys, publicKey)
 		}
-		return keyName, nil
+		logger.Info("using EC2 key pairs %s", strings.Join(sshConfig.PublicKeyNames, ", "))
+		return nil
 
 	// Use key by name in EC2
-	case sshConfig.PublicKeyName != nil && *sshConfig.PublicKeyName != "":
+	case api.IsSetAndNonEmptyString(sshConfig.PublicKeyName):
 		if err := client.CheckKeyExistsInEC2(ctx, ec2API, *sshConfig.PublicKeyName); err != nil {
-			return "", err
+			return err
 		}
 		logger.Info("using EC2 key pair %q", *sshConfig.PublicKeyName)
-		return *sshConfig.PublicKeyName, nil
+		return nil
 
-	// Local ssh key file
-	case file.Exists(*sshConfig.PublicKeyPath):
-		keyName, err := client.LoadKeyFromFile(ctx, *sshConfig.PublicKeyPath, clusterName, nodeGroupName, ec2API)
+	// Import key by content
+	case api.IsSetAndNonEmptyString(sshConfig.PublicKey):
+		logger.Info("using SSH public key %q", *sshConfig.PublicKey)
+		return useKey(ctx, sshConfig, *sshConfig.PublicKey, clusterName, nodeGroupName, ec2API)
+
+	// Import local ssh key file
+	case api.IsSetAndNonEmptyString(sshConfig.PublicKeyPath) && file.Exists(*sshConfig.PublicKeyPath):
+		key, err := client.ReadKeyFile(*sshConfig.PublicKeyPath)
 		if err != nil {
-			return "", err
+			return err
 		}
-		return keyName, nil
+		logger.Info("using SSH public key %q", file.ExpandPath(*sshConfig.PublicKeyPath))
+		return useKey(ctx, sshConfig, key, clusterName, nodeGroupName, ec2API)
 
 	// A keyPath, when specified as a flag, can mean a local key (checked above) or a key name in EC2
-	default:
-		err := client.CheckKeyExistsInEC2(ctx, ec2API, *sshConfig.PublicKeyPath)
-		if err != nil {
-			return "", err
+	case api.IsSetAndNonEmptyString(sshConfig.PublicKeyPath):
+		if err := client.CheckKeyExistsInEC2(ctx, ec2API, *sshConfig.PublicKeyPath); err != nil {
+			return err
 		}
-		logger.Info("using EC2 key pair %q", sshConfig.PublicKeyName)
-		return *sshConfig.PublicKeyPath, nil
+		logger.Info("using EC2 key pair %q", *sshConfig.PublicKeyPath)
+		sshConfig.PublicKeyName = sshConfig.PublicKeyPath
+		sshConfig.PublicKeyPath = nil
+		return nil
+
+	default:
+		return errors.New("one of publicKeyName, publicKeyNames, publicKeyPath or publicKey must be specified when SSH access is allowed")
+	}
+}
+
+// useKey sets the key to be imported by the nodegroup stack, or references its key pair if it already exists in EC2
+func useKey(ctx context.Context, sshConfig *api.NodeGroupSSH, key, clusterName, nodeGroupName string, ec2API awsapi.EC2) error {
+	keyPair, err := client.NewKeyPair(key, clusterName, nodeGroupName)
+	if err != nil {
+		return err
+	}
+	sshConfig.PublicKeyPath = nil
+
+	exists, err := keyPair.Exists(ctx, ec2API)
+	if err != nil {
+		return err
+	}
+	if exists {
+		logger.Info("using existing EC2 key pair %q", keyPair.Name)
+		sshConfig.PublicKeyName = &keyPair.Name
+		sshConfig.PublicKey = nil
+		return nil
 	}
 
+	logger.Info("SSH public key will be imported as EC2 key pair %q", keyPair.Name)
+	sshConfig.PublicKey = &key
+	return nil
 }
@@ -0,0 +1,172 @@
+package ssh
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks/mocksv2"
+)
+
+var _ = Describe("LoadKey", func() {
+	const (
+		clusterName = "sshtestcluster"
+		ngName      = "ng1"
+		rsaKey      = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDcSoNjWaJaw+MYBz43lgm12ZGdP+zRs9o0sXAGbiQua6e3JSkAiH4p9YZHmWxCTjckbiEdXN5qcs5OC5KUxYBvnEgor7jEydcKe1ZJXqsm/8CrtnJMTNcO9QVFnXfjvpkNjgNYj+8w9PcFRr0JDgDhRb52JvPWoqywv/Om9s1hpUov0gxDIl6CLLHSk0lmXZEhtVMMJmo0Tu/NlHqdky2DxFgHyNjBcMNpiBd8bs3dA5xf36dY+qgcXBV23i1SCgbqn9xcw1Q0IrHuQ4/QB+PJ5haxUx0bnOTahxSZ+tlEz9EiLwlM8VtKo3ND/giBvGaXuIK2iGDL0kSCRjueM5/3 user@example\n"
+		rsaKeyName  = "eksctl-sshtestcluster-nodegroup-ng1-f5:d9:01:88:1e:fb:40:fb:e1:ca:69:fe:2e:31:03:6c"
+	)
+
+	var mockEC2 *mocksv2.EC2
+
+	BeforeEach(func() {
+		mockEC2 = &mocksv2.EC2{}
+	})
+
+	mockKeyPairs := func(keyPairs ...ec2types.KeyPairInfo) {
+		if len(keyPairs) == 0 {
+			mockEC2.On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(nil, &smithy.GenericAPIError{
+				Code:    "InvalidKeyPair.NotFound",
+				Message: "not found",
+			})
+			return
+		}
+		mockEC2.On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{
+			KeyPairs: keyPairs,
+		}, nil)
+	}
+
+	It("ignores the key when SSH is not allowed", func() {
+		sshConfig := &api.NodeGroupSSH{
+			Allow:     api.Disabled(),
+			PublicKey: aws.String(rsaKey),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(*sshConfig.PublicKey).To(Equal(rsaKey))
+		mockEC2.AssertNotCalled(GinkgoT(), "DescribeKeyPairs", mock.Anything, mock.Anything)
+	})
+
+	It("references an existing key pair by name", func() {
+		mockKeyPairs(ec2types.KeyPairInfo{KeyName: aws.String("my-key")})
+		sshConfig := &api.NodeGroupSSH{
+			Allow:         api.Enabled(),
+			PublicKeyName: aws.String("my-key"),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(*sshConfig.PublicKeyName).To(Equal("my-key"))
+		Expect(sshConfig.PublicKey).To(BeNil())
+	})
+
+	It("errors when the key pair referenced by name does not exist", func() {
+		mockKeyPairs()
+		sshConfig := &api.NodeGroupSSH{
+			Allow:         api.Enabled(),
+			PublicKeyName: aws.String("my-key"),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(MatchError(`cannot find EC2 key pair "my-key"`))
+	})
+
+	It("references the first of several key pairs by name and loads the public keys of the others", func() {
+		mockEC2.On("DescribeKeyPairs", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeKeyPairsInput) bool {
+			return input.KeyNames[0] == "primary"
+		})).Return(&ec2.DescribeKeyPairsOutput{
+			KeyPairs: []ec2types.KeyPairInfo{{KeyName: aws.String("primary")}},
+		}, nil)
+		mockEC2.On("DescribeKeyPairs", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeKeyPairsInput) bool {
+			return input.KeyNames[0] == "escrow" && aws.ToBool(input.IncludePublicKey)
+		})).Return(&ec2.DescribeKeyPairsOutput{
+			KeyPairs: []ec2types.KeyPairInfo{{KeyName: aws.String("escrow"), PublicKey: aws.String(rsaKey)}},
+		}, nil)
+
+		sshConfig := &api.NodeGroupSSH{
+			Allow:          api.Enabled(),
+			PublicKeyNames: []string{"primary", "escrow"},
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(sshConfig.PublicKeyNames).To(Equal([]string{"primary", "escrow"}))
+		Expect(sshConfig.AdditionalPublicKeys).To(Equal([]string{strings.TrimSpace(rsaKey)}))
+		Expect(sshConfig.PublicKey).To(BeNil())
+	})
+
+	It("errors when one of several key pairs referenced by name does not exist", func() {
+		mockEC2.On("DescribeKeyPairs", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeKeyPairsInput) bool {
+			return input.KeyNames[0] == "primary"
+		})).Return(&ec2.DescribeKeyPairsOutput{
+			KeyPairs: []ec2types.KeyPairInfo{{KeyName: aws.String("primary")}},
+		}, nil)
+		mockEC2.On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(nil, &smithy.GenericAPIError{
+			Code:    "InvalidKeyPair.NotFound",
+			Message: "not found",
+		})
+
+		sshConfig := &api.NodeGroupSSH{
+			Allow:          api.Enabled(),
+			PublicKeyNames: []string{"primary", "escrow"},
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(MatchError(`cannot find EC2 key pair "escrow"`))
+	})
+
+	It("sets a key specified by content to be imported", func() {
+		mockKeyPairs()
+		sshConfig := &api.NodeGroupSSH{
+			Allow:     api.Enabled(),
+			PublicKey: aws.String(rsaKey),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(*sshConfig.PublicKey).To(Equal(rsaKey))
+		Expect(sshConfig.PublicKeyName).To(BeNil())
+		mockEC2.AssertNotCalled(GinkgoT(), "ImportKeyPair", mock.Anything, mock.Anything)
+	})
+
+	It("sets a key read from a file to be imported", func() {
+		mockKeyPairs()
+		sshConfig := &api.NodeGroupSSH{
+			Allow:         api.Enabled(),
+			PublicKeyPath: aws.String("client/assets/id_rsa_tests1.pub"),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(*sshConfig.PublicKey).To(Equal(rsaKey))
+		Expect(sshConfig.PublicKeyPath).To(BeNil())
+		Expect(sshConfig.PublicKeyName).To(BeNil())
+	})
+
+	It("references a key that was already imported by name", func() {
+		mockKeyPairs(ec2types.KeyPairInfo{
+			KeyName:        aws.String(rsaKeyName),
+			KeyFingerprint: aws.String("f5:d9:01:88:1e:fb:40:fb:e1:ca:69:fe:2e:31:03:6c"),
+		})
+		sshConfig := &api.NodeGroupSSH{
+			Allow:         api.Enabled(),
+			PublicKeyPath: aws.String("client/assets/id_rsa_tests1.pub"),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(*sshConfig.PublicKeyName).To(Equal(rsaKeyName))
+		Expect(sshConfig.PublicKey).To(BeNil())
+		Expect(sshConfig.PublicKeyPath).To(BeNil())
+	})
+
+	It("references a key pair by name when the path is not a local file", func() {
+		mockKeyPairs(ec2types.KeyPairInfo{KeyName: aws.String("my-key")})
+		sshConfig := &api.NodeGroupSSH{
+			Allow:         api.Enabled(),
+			PublicKeyPath: aws.String("my-key"),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(Succeed())
+		Expect(*sshConfig.PublicKeyName).To(Equal("my-key"))
+		Expect(sshConfig.PublicKeyPath).To(BeNil())
+	})
+
+	It("errors when no key is specified", func() {
+		sshConfig := &api.NodeGroupSSH{
+			Allow: api.Enabled(),
+		}
+		Expect(LoadKey(context.Background(), sshConfig, clusterName, ngName, mockEC2)).To(MatchError("one of publicKeyName, publicKeyNames, publicKeyPath or publicKey must be specified when SSH access is allowed"))
+	})
+})
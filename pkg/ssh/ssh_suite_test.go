@@ -0,0 +1,11 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestSSH(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
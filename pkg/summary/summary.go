@@ -0,0 +1,256 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// Summary describes the resources created by `eksctl create`, it is written as JSON by --summary-file, so its fields
+// must not be renamed or removed
+type Summary struct {
+	Cluster         Cluster          `json:"cluster"`
+	NodeGroups      []NodeGroup      `json:"nodeGroups"`
+	ServiceAccounts []ServiceAccount `json:"serviceAccounts"`
+	// KubeconfigPath is empty if no kubeconfig was written
+	KubeconfigPath string `json:"kubeconfigPath"`
+}
+
+// Cluster describes the control plane of the cluster
+type Cluster struct {
+	Name       string `json:"name"`
+	Region     string `json:"region"`
+	ARN        string `json:"arn"`
+	Endpoint   string `json:"endpoint"`
+	OIDCIssuer string `json:"oidcIssuer"`
+	VPC        VPC    `json:"vpc"`
+}
+
+// VPC describes the network of the cluster, the subnets are only known when eksctl has loaded the VPC of the cluster
+type VPC struct {
+	ID                        string   `json:"id"`
+	PublicSubnetIDs           []string `json:"publicSubnetIDs"`
+	PrivateSubnetIDs          []string `json:"privateSubnetIDs"`
+	ClusterSecurityGroupID    string   `json:"clusterSecurityGroupID"`
+	SharedNodeSecurityGroupID string   `json:"sharedNodeSecurityGroupID"`
+}
+
+// NodeGroup describes a nodegroup and the resources of its stack
+type NodeGroup struct {
+	Name                 string            `json:"name"`
+	Type                 api.NodeGroupType `json:"type"`
+	InstanceRoleARN      string            `json:"instanceRoleARN"`
+	AutoScalingGroupName string            `json:"autoScalingGroupName"`
+}
+
+// ServiceAccount describes an iamserviceaccount and the role of its stack
+type ServiceAccount struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	RoleARN   string `json:"roleARN"`
+}
+
+// StackDescriber describes the stacks of the nodegroups and iamserviceaccounts of a summary, it is implemented by
+// manager.StackManager
+type StackDescriber interface {
+	DescribeNodeGroupStack(nodeGroupName string) (*manager.Stack, error)
+	GetAutoScalingGroupName(s *manager.Stack) (string, error)
+	GetNodeGroupInstanceRoleARN(s *manager.Stack) (string, error)
+	GetIAMServiceAccounts() ([]*api.ClusterIAMServiceAccount, error)
+}
+
+// ForCluster returns the summary of the cluster of spec, described by cluster
+func ForCluster(spec *api.ClusterConfig, cluster *awseks.Cluster) *Summary {
+	s := &Summary{
+		Cluster: Cluster{
+			Name:     spec.Metadata.Name,
+			Region:   spec.Metadata.Region,
+			ARN:      aws.StringValue(cluster.Arn),
+			Endpoint: aws.StringValue(cluster.Endpoint),
+			VPC: VPC{
+				PublicSubnetIDs:  []string{},
+				PrivateSubnetIDs: []string{},
+			},
+		},
+		NodeGroups:      []NodeGroup{},
+		ServiceAccounts: []ServiceAccount{},
+	}
+	if cluster.Identity != nil && cluster.Identity.Oidc != nil {
+		s.Cluster.OIDCIssuer = aws.StringValue(cluster.Identity.Oidc.Issuer)
+	}
+	if vpcConfig := cluster.ResourcesVpcConfig; vpcConfig != nil {
+		s.Cluster.VPC.ID = aws.StringValue(vpcConfig.VpcId)
+		s.Cluster.VPC.ClusterSecurityGroupID = aws.StringValue(vpcConfig.ClusterSecurityGroupId)
+	}
+	if spec.VPC != nil {
+		if spec.VPC.Subnets != nil {
+			s.Cluster.VPC.PublicSubnetIDs = sortedIDs(spec.VPC.Subnets.Public)
+			s.Cluster.VPC.PrivateSubnetIDs = sortedIDs(spec.VPC.Subnets.Private)
+		}
+		if spec.VPC.SharedNodeSecurityGroup != api.SharedNodeSecurityGroupDisabled {
+			s.Cluster.VPC.SharedNodeSecurityGroupID = spec.VPC.SharedNodeSecurityGroup
+		}
+	}
+	return s
+}
+
+func sortedIDs(subnets api.AZSubnetMapping) []string {
+	ids := subnets.WithIDs()
+	if ids == nil {
+		return []string{}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// AddNodeGroups adds the nodegroups of spec to the summary, along with the instance roles and autoscaling groups of
+// their stacks
+func (s *Summary) AddNodeGroups(spec *api.ClusterConfig, stacks StackDescriber) error {
+	addNodeGroup := func(ng *api.NodeGroupBase, nodeGroupType api.NodeGroupType) error {
+		stack, err := stacks.DescribeNodeGroupStack(ng.Name)
+		if err != nil {
+			return errors.Wrapf(err, "describing stack of nodegroup %q", ng.Name)
+		}
+		asgName, err := stacks.GetAutoScalingGroupName(stack)
+		if err != nil {
+			return errors.Wrapf(err, "getting autoscaling group of nodegroup %q", ng.Name)
+		}
+		var roleARN string
+		if ng.IAM != nil {
+			roleARN = ng.IAM.InstanceRoleARN
+		}
+		if roleARN == "" {
+			if roleARN, err = stacks.GetNodeGroupInstanceRoleARN(stack); err != nil {
+				return errors.Wrapf(err, "getting instance role of nodegroup %q", ng.Name)
+			}
+		}
+		s.NodeGroups = append(s.NodeGroups, NodeGroup{
+			Name:                 ng.Name,
+			Type:                 nodeGroupType,
+			InstanceRoleARN:      roleARN,
+			AutoScalingGroupName: asgName,
+		})
+		return nil
+	}
+
+	for _, ng := range spec.NodeGroups {
+		if err := addNodeGroup(ng.NodeGroupBase, api.NodeGroupTypeUnmanaged); err != nil {
+			return err
+		}
+	}
+	for _, ng := range spec.ManagedNodeGroups {
+		if err := addNodeGroup(ng.NodeGroupBase, api.NodeGroupTypeManaged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddServiceAccounts adds serviceAccounts to the summary, along with the roles of their stacks
+func (s *Summary) AddServiceAccounts(serviceAccounts []*api.ClusterIAMServiceAccount, stacks StackDescriber) error {
+	if len(serviceAccounts) == 0 {
+		return nil
+	}
+	existing, err := stacks.GetIAMServiceAccounts()
+	if err != nil {
+		return errors.Wrap(err, "getting iamserviceaccounts")
+	}
+	roleARNs := make(map[string]string, len(existing))
+	for _, sa := range existing {
+		if sa.Status != nil && sa.Status.RoleARN != nil {
+			roleARNs[sa.NameString()] = *sa.Status.RoleARN
+		}
+	}
+	for _, sa := range serviceAccounts {
+		roleARN, ok := roleARNs[sa.NameString()]
+		if !ok {
+			// no stack is created for service accounts bound to an existing role
+			roleARN = sa.AttachRoleARN
+		}
+		s.ServiceAccounts = append(s.ServiceAccounts, ServiceAccount{
+			Namespace: sa.Namespace,
+			Name:      sa.Name,
+			RoleARN:   roleARN,
+		})
+	}
+	return nil
+}
+
+// WriteJSON writes the summary as indented JSON
+func (s *Summary) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteFile writes the summary as JSON to path
+func (s *Summary) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating summary file %q", path)
+	}
+	if err := s.WriteJSON(f); err != nil {
+		_ = f.Close()
+		return errors.Wrapf(err, "writing summary file %q", path)
+	}
+	return f.Close()
+}
+
+// Render writes a concise human-readable version of the summary, followed by the commands to run next
+func (s *Summary) Render(w io.Writer) error {
+	var b strings.Builder
+	c := s.Cluster
+	fmt.Fprintf(&b, "cluster %q in %q\n", c.Name, c.Region)
+	writeField := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %s: %s\n", name, value)
+		}
+	}
+	writeField("ARN", c.ARN)
+	writeField("endpoint", c.Endpoint)
+	writeField("OIDC issuer", c.OIDCIssuer)
+	writeField("VPC", c.VPC.ID)
+	writeField("public subnets", strings.Join(c.VPC.PublicSubnetIDs, ", "))
+	writeField("private subnets", strings.Join(c.VPC.PrivateSubnetIDs, ", "))
+	writeField("cluster security group", c.VPC.ClusterSecurityGroupID)
+	writeField("shared node security group", c.VPC.SharedNodeSecurityGroupID)
+
+	for _, ng := range s.NodeGroups {
+		fmt.Fprintf(&b, "nodegroup %q (%s)\n", ng.Name, ng.Type)
+		writeField("instance role", ng.InstanceRoleARN)
+		writeField("autoscaling group", ng.AutoScalingGroupName)
+	}
+	for _, sa := range s.ServiceAccounts {
+		fmt.Fprintf(&b, "iamserviceaccount \"%s/%s\"\n", sa.Namespace, sa.Name)
+		writeField("role", sa.RoleARN)
+	}
+
+	b.WriteString("next steps:\n")
+	if s.KubeconfigPath != "" {
+		fmt.Fprintf(&b, "  kubectl --kubeconfig=%s get nodes\n", s.KubeconfigPath)
+	} else {
+		fmt.Fprintf(&b, "  eksctl utils write-kubeconfig --cluster=%s --region=%s\n", c.Name, c.Region)
+	}
+	if len(s.NodeGroups) > 0 {
+		fmt.Fprintf(&b, "  eksctl get nodegroup --cluster=%s --region=%s\n", c.Name, c.Region)
+	}
+	if len(s.ServiceAccounts) > 0 {
+		fmt.Fprintf(&b, "  eksctl get iamserviceaccount --cluster=%s --region=%s\n", c.Name, c.Region)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,11 @@
+package summary_test
+
+import (
+	"testing"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestSummary(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
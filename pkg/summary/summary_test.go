@@ -0,0 +1,181 @@
+package summary_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager/fakes"
+	"github.com/weaveworks/eksctl/pkg/summary"
+)
+
+var _ = Describe("Summary", func() {
+	var (
+		cfg          *api.ClusterConfig
+		cluster      *awseks.Cluster
+		stackManager *fakes.FakeStackManager
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "my-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		cfg.VPC.Subnets = &api.ClusterSubnets{
+			Public: api.AZSubnetMappingFromMap(map[string]api.AZSubnetSpec{
+				"us-west-2b": {ID: "subnet-public-b"},
+				"us-west-2a": {ID: "subnet-public-a"},
+			}),
+			Private: api.AZSubnetMappingFromMap(map[string]api.AZSubnetSpec{
+				"us-west-2a": {ID: "subnet-private-a"},
+			}),
+		}
+		cfg.VPC.SharedNodeSecurityGroup = "sg-shared"
+
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.IAM.InstanceRoleARN = "arn:aws:iam::123456789012:role/ng-1-role"
+		mng := api.NewManagedNodeGroup()
+		mng.Name = "mng-1"
+		cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{mng}
+
+		cluster = &awseks.Cluster{
+			Arn:      aws.String("arn:aws:eks:us-west-2:123456789012:cluster/my-cluster"),
+			Endpoint: aws.String("https://ABCDEF.gr7.us-west-2.eks.amazonaws.com"),
+			Identity: &awseks.Identity{
+				Oidc: &awseks.OIDC{
+					Issuer: aws.String("https://oidc.eks.us-west-2.amazonaws.com/id/ABCDEF"),
+				},
+			},
+			ResourcesVpcConfig: &awseks.VpcConfigResponse{
+				VpcId:                  aws.String("vpc-1"),
+				ClusterSecurityGroupId: aws.String("sg-cluster"),
+			},
+		}
+
+		stackManager = &fakes.FakeStackManager{}
+		stackManager.DescribeNodeGroupStackStub = func(name string) (*cloudformation.Stack, error) {
+			return &cloudformation.Stack{StackName: aws.String("eksctl-my-cluster-nodegroup-" + name)}, nil
+		}
+		stackManager.GetAutoScalingGroupNameStub = func(s *cloudformation.Stack) (string, error) {
+			return *s.StackName + "-asg", nil
+		}
+		stackManager.GetNodeGroupInstanceRoleARNReturns("arn:aws:iam::123456789012:role/mng-1-role", nil)
+		stackManager.GetIAMServiceAccountsReturns([]*api.ClusterIAMServiceAccount{
+			{
+				ClusterIAMMeta: api.ClusterIAMMeta{Name: "s3-reader", Namespace: "default"},
+				Status:         &api.ClusterIAMServiceAccountStatus{RoleARN: aws.String("arn:aws:iam::123456789012:role/s3-reader")},
+			},
+		}, nil)
+	})
+
+	newSummary := func() *summary.Summary {
+		s := summary.ForCluster(cfg, cluster)
+		s.KubeconfigPath = "/home/user/.kube/config"
+		Expect(s.AddNodeGroups(cfg, stackManager)).To(Succeed())
+		Expect(s.AddServiceAccounts([]*api.ClusterIAMServiceAccount{
+			{ClusterIAMMeta: api.ClusterIAMMeta{Name: "s3-reader", Namespace: "default"}},
+			{ClusterIAMMeta: api.ClusterIAMMeta{Name: "existing-role", Namespace: "kube-system"}, AttachRoleARN: "arn:aws:iam::123456789012:role/existing"},
+		}, stackManager)).To(Succeed())
+		return s
+	}
+
+	It("writes the JSON summary", func() {
+		var out bytes.Buffer
+		Expect(newSummary().WriteJSON(&out)).To(Succeed())
+
+		golden, err := os.ReadFile("testdata/summary.golden")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(string(golden)))
+	})
+
+	It("writes the JSON summary to a file", func() {
+		tmp, err := os.MkdirTemp("", "summary")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmp)
+
+		path := filepath.Join(tmp, "out.json")
+		Expect(newSummary().WriteFile(path)).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		golden, err := os.ReadFile("testdata/summary.golden")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(string(golden)))
+	})
+
+	It("renders the summary and the next steps", func() {
+		var out bytes.Buffer
+		Expect(newSummary().Render(&out)).To(Succeed())
+		Expect(out.String()).To(Equal(`cluster "my-cluster" in "us-west-2"
+  ARN: arn:aws:eks:us-west-2:123456789012:cluster/my-cluster
+  endpoint: https://ABCDEF.gr7.us-west-2.eks.amazonaws.com
+  OIDC issuer: https://oidc.eks.us-west-2.amazonaws.com/id/ABCDEF
+  VPC: vpc-1
+  public subnets: subnet-public-a, subnet-public-b
+  private subnets: subnet-private-a
+  cluster security group: sg-cluster
+  shared node security group: sg-shared
+nodegroup "ng-1" (unmanaged)
+  instance role: arn:aws:iam::123456789012:role/ng-1-role
+  autoscaling group: eksctl-my-cluster-nodegroup-ng-1-asg
+nodegroup "mng-1" (managed)
+  instance role: arn:aws:iam::123456789012:role/mng-1-role
+  autoscaling group: eksctl-my-cluster-nodegroup-mng-1-asg
+iamserviceaccount "default/s3-reader"
+  role: arn:aws:iam::123456789012:role/s3-reader
+iamserviceaccount "kube-system/existing-role"
+  role: arn:aws:iam::123456789012:role/existing
+next steps:
+  kubectl --kubeconfig=/home/user/.kube/config get nodes
+  eksctl get nodegroup --cluster=my-cluster --region=us-west-2
+  eksctl get iamserviceaccount --cluster=my-cluster --region=us-west-2
+`))
+	})
+
+	It("keeps the fields of the JSON summary when the cluster has no resources", func() {
+		cfg.VPC = nil
+		s := summary.ForCluster(cfg, &awseks.Cluster{})
+		var out bytes.Buffer
+		Expect(s.WriteJSON(&out)).To(Succeed())
+		Expect(out.String()).To(Equal(`{
+  "cluster": {
+    "name": "my-cluster",
+    "region": "us-west-2",
+    "arn": "",
+    "endpoint": "",
+    "oidcIssuer": "",
+    "vpc": {
+      "id": "",
+      "publicSubnetIDs": [],
+      "privateSubnetIDs": [],
+      "clusterSecurityGroupID": "",
+      "sharedNodeSecurityGroupID": ""
+    }
+  },
+  "nodeGroups": [],
+  "serviceAccounts": [],
+  "kubeconfigPath": ""
+}
+`))
+	})
+
+	It("only gets the instance roles of nodegroups whose role is not known", func() {
+		newSummary()
+		Expect(stackManager.GetNodeGroupInstanceRoleARNCallCount()).To(Equal(1))
+	})
+
+	It("fails when the stack of a nodegroup cannot be described", func() {
+		stackManager.DescribeNodeGroupStackStub = nil
+		stackManager.DescribeNodeGroupStackReturns(nil, errors.New("not found"))
+		err := summary.ForCluster(cfg, cluster).AddNodeGroups(cfg, stackManager)
+		Expect(err).To(MatchError(`describing stack of nodegroup "ng-1": not found`))
+	})
+})
@@ -2,6 +2,7 @@ package instance
 
 import (
 	"strings"
+	"unicode"
 )
 
 // IsARMInstanceType returns true if the instance type is ARM architecture
@@ -38,3 +39,8 @@ func IsNvidiaInstanceType(instanceType string) bool {
 func IsInferentiaInstanceType(instanceType string) bool {
 	return strings.HasPrefix(instanceType, "inf1")
 }
+
+// IsBurstableInstanceType returns true if the instance type is a burstable performance (T family) instance type
+func IsBurstableInstanceType(instanceType string) bool {
+	return len(instanceType) > 1 && instanceType[0] == 't' && unicode.IsDigit(rune(instanceType[1]))
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 
 	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/metrics"
 )
 
 // Task is a common interface for the stack manager tasks
@@ -47,6 +49,8 @@ type TaskTree struct {
 	Parallel  bool
 	PlanMode  bool
 	IsSubTask bool
+	// MaxParallel limits the number of tasks that run at the same time when Parallel is set, 0 means no limit
+	MaxParallel int
 }
 
 // Append new tasks to the set
@@ -144,7 +148,7 @@ func (t *TaskTree) Do(allErrs chan error) error {
 	errs := make(chan error)
 
 	if t.Parallel {
-		go doParallelTasks(errs, t.Tasks)
+		go doParallelTasks(errs, t.Tasks, t.MaxParallel)
 	} else {
 		go doSequentialTasks(errs, t.Tasks)
 	}
@@ -170,7 +174,7 @@ func (t *TaskTree) DoAllSync() []error {
 	errs := make(chan error)
 
 	if t.Parallel {
-		go doParallelTasks(errs, t.Tasks)
+		go doParallelTasks(errs, t.Tasks, t.MaxParallel)
 	} else {
 		go doSequentialTasks(errs, t.Tasks)
 	}
@@ -185,25 +189,41 @@ func (t *TaskTree) DoAllSync() []error {
 func doSingleTask(allErrs chan error, task Task) bool {
 	desc := task.Describe()
 	logger.Debug("started task: %s", desc)
+	taskDone := func(error) {}
+	if _, isTree := task.(*TaskTree); !isTree {
+		// only the tasks doing the actual work are tracked, not the trees grouping them
+		taskDone = metrics.TrackTask()
+	}
 	errs := make(chan error)
 	if err := task.Do(errs); err != nil {
+		taskDone(err)
 		allErrs <- err
 		return false
 	}
 	if err := <-errs; err != nil {
+		taskDone(err)
 		allErrs <- err
 		return false
 	}
+	taskDone(nil)
 	logger.Debug("completed task: %s", desc)
 	return true
 }
 
-func doParallelTasks(allErrs chan error, tasks []Task) {
+func doParallelTasks(allErrs chan error, tasks []Task, maxParallel int) {
 	wg := &sync.WaitGroup{}
 	wg.Add(len(tasks))
+	var slots chan struct{}
+	if maxParallel > 0 {
+		slots = make(chan struct{}, maxParallel)
+	}
 	for t := range tasks {
 		go func(t int) {
 			defer wg.Done()
+			if slots != nil {
+				slots <- struct{}{}
+				defer func() { <-slots }()
+			}
 			if ok := doSingleTask(allErrs, tasks[t]); !ok {
 				logger.Debug("failed task: %s (will continue until other parallel tasks are completed)", tasks[t].Describe())
 			}
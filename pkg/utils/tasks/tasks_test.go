@@ -621,5 +621,34 @@ var _ = Describe("TaskTree", func() {
 				Expect(errs[0].Error()).To(Equal("t1.3 always fails"))
 			}
 		})
+
+		It("should not run more parallel tasks than the limit", func() {
+			var running, maxRunning int32
+			tasks := &TaskTree{Parallel: true, MaxParallel: 2}
+			for i := 0; i < 5; i++ {
+				tasks.Append(&TaskWithoutParams{
+					Info: fmt.Sprintf("t%d", i),
+					Call: func(errs chan error) error {
+						n := atomic.AddInt32(&running, 1)
+						for {
+							max := atomic.LoadInt32(&maxRunning)
+							if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+								break
+							}
+						}
+						go func() {
+							time.Sleep(50 * time.Millisecond)
+							atomic.AddInt32(&running, -1)
+							errs <- nil
+							close(errs)
+						}()
+						return nil
+					},
+				})
+			}
+
+			Expect(tasks.DoAllSync()).To(BeEmpty())
+			Expect(atomic.LoadInt32(&maxRunning)).To(Equal(int32(2)))
+		})
 	})
 })
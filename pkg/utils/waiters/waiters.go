@@ -10,6 +10,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/metrics"
 )
 
 // Wait for something with a name to reach status that is expressed by acceptors using newRequest
@@ -21,6 +23,7 @@ func Wait(name, msg string, acceptors []request.WaiterAcceptor, newRequest func(
 
 	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
 	defer cancel()
+	defer metrics.TrackWait()()
 	startTime := time.Now()
 	w := makeWaiter(ctx, name, msg, acceptors, newRequest)
 	logger.Debug("start %s", msg)
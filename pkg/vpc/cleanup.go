@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -14,8 +16,12 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/awsapi"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 )
 
+// fargateENIDescriptionFilter matches the description of the network interfaces that Fargate creates for pods
+const fargateENIDescriptionFilter = "fargate-*"
+
 func fmtSecurityGroupNameRegexForCluster(name string) string {
 	const ourSecurityGroupNameRegexFmt = "^eksctl-%s-(cluster|nodegroup)-.+$"
 	return fmt.Sprintf(ourSecurityGroupNameRegexFmt, name)
@@ -83,3 +89,148 @@ func CleanupNetworkInterfaces(ctx context.Context, ec2API awsapi.EC2, spec *api.
 	}
 	return nil
 }
+
+// CleanupOrphanedNetworkResources deletes the detached network interfaces in vpcID that are tagged with the cluster
+// name, followed by the security groups tagged with the cluster name that are neither part of a CloudFormation stack
+// nor created by EKS, e.g. those created by controllers running in the cluster, as they block the deletion of the VPC.
+// Resources that cannot be deleted are logged and skipped
+func CleanupOrphanedNetworkResources(ctx context.Context, ec2API awsapi.EC2, clusterName, vpcID string) error {
+	clusterTagFilters := []ec2types.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []string{vpcID},
+		},
+		{
+			Name:   aws.String("tag-key"),
+			Values: []string{"kubernetes.io/cluster/" + clusterName},
+		},
+	}
+
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(ec2API, &ec2.DescribeNetworkInterfacesInput{
+		Filters: append(clusterTagFilters, ec2types.Filter{
+			Name:   aws.String("status"),
+			Values: []string{string(ec2types.NetworkInterfaceStatusAvailable)},
+		}),
+	})
+	var eniIDs []string
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to list detached network interfaces of cluster %q in %q: %w", clusterName, vpcID, err)
+		}
+		for _, eni := range output.NetworkInterfaces {
+			eniIDs = append(eniIDs, aws.StringValue(eni.NetworkInterfaceId))
+		}
+	}
+	for _, eniID := range eniIDs {
+		if _, err := ec2API.DeleteNetworkInterface(ctx, &ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String(eniID),
+		}); err != nil {
+			logger.Warning("unable to delete network interface %q: %v", eniID, err)
+			continue
+		}
+		logger.Info("deleted network interface %q", eniID)
+	}
+
+	sgPaginator := ec2.NewDescribeSecurityGroupsPaginator(ec2API, &ec2.DescribeSecurityGroupsInput{
+		Filters: clusterTagFilters,
+	})
+	var securityGroups []ec2types.SecurityGroup
+	for sgPaginator.HasMorePages() {
+		output, err := sgPaginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to list security groups of cluster %q in %q: %w", clusterName, vpcID, err)
+		}
+		securityGroups = append(securityGroups, output.SecurityGroups...)
+	}
+	for _, sg := range securityGroups {
+		groupID := aws.StringValue(sg.GroupId)
+		if isStackResource(sg.Tags) {
+			logger.Debug("security group %q (%s) belongs to a CloudFormation stack, not deleting it", aws.StringValue(sg.GroupName), groupID)
+			continue
+		}
+		if isEKSResource(sg.Tags) {
+			logger.Debug("security group %q (%s) was created by EKS, not deleting it", aws.StringValue(sg.GroupName), groupID)
+			continue
+		}
+		if aws.StringValue(sg.GroupName) == "default" {
+			continue
+		}
+		if _, err := ec2API.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{
+			GroupId: aws.String(groupID),
+		}); err != nil {
+			logger.Warning("unable to delete security group %q (%s): %v", aws.StringValue(sg.GroupName), groupID, err)
+			continue
+		}
+		logger.Info("deleted security group %q (%s)", aws.StringValue(sg.GroupName), groupID)
+	}
+	return nil
+}
+
+func isStackResource(tags []ec2types.Tag) bool {
+	return hasTag(tags, stackNameTagKey)
+}
+
+// isEKSResource returns true for resources created by EKS, e.g. the cluster security group, which EKS deletes along
+// with the cluster
+func isEKSResource(tags []ec2types.Tag) bool {
+	return hasTag(tags, eksClusterNameTagKey)
+}
+
+func hasTag(tags []ec2types.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func findFargateENIs(ctx context.Context, ec2API awsapi.EC2, vpcID string) ([]string, error) {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []string{vpcID},
+			},
+			{
+				Name:   aws.String("description"),
+				Values: []string{fargateENIDescriptionFilter},
+			},
+		},
+	}
+
+	var eniIDs []string
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(ec2API, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list Fargate network interfaces in %q: %w", vpcID, err)
+		}
+		for _, eni := range output.NetworkInterfaces {
+			eniIDs = append(eniIDs, *eni.NetworkInterfaceId)
+		}
+	}
+	return eniIDs, nil
+}
+
+// WaitForFargateNetworkInterfacesDeletion waits for the network interfaces of Fargate pods in the VPC to be deleted.
+// Fargate releases them asynchronously after the pods are deleted, and they block the deletion of the VPC
+func WaitForFargateNetworkInterfacesDeletion(ctx context.Context, ec2API awsapi.EC2, vpcID string, retryPolicy retry.Policy) error {
+	retryPolicy = retryPolicy.Clone()
+	for {
+		eniIDs, err := findFargateENIs(ctx, ec2API, vpcID)
+		if err != nil {
+			return err
+		}
+		if len(eniIDs) == 0 {
+			logger.Debug("no Fargate network interfaces left in %q", vpcID)
+			return nil
+		}
+		if retryPolicy.Done() {
+			return fmt.Errorf("timed out waiting for Fargate network interfaces in %q to be deleted: %s", vpcID, strings.Join(eniIDs, ", "))
+		}
+		logger.Info("waiting for %d Fargate network interface(s) in %q to be deleted", len(eniIDs), vpcID)
+		time.Sleep(retryPolicy.Duration())
+	}
+}
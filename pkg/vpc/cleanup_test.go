@@ -0,0 +1,171 @@
+package vpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kris-nova/logger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/stretchr/testify/mock"
+
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+var _ = Describe("WaitForFargateNetworkInterfacesDeletion", func() {
+	const vpcID = "vpc-fargate"
+
+	var (
+		p           *mockprovider.MockProvider
+		retryPolicy retry.Policy
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		retryPolicy = &retry.ConstantBackoff{
+			MaxRetries: 2,
+			Time:       1,
+			TimeUnit:   time.Millisecond,
+		}
+	})
+
+	mockFargateENIs := func(eniIDs ...string) {
+		var enis []ec2types.NetworkInterface
+		for _, id := range eniIDs {
+			enis = append(enis, ec2types.NetworkInterface{NetworkInterfaceId: aws.String(id)})
+		}
+		p.MockEC2().On("DescribeNetworkInterfaces", Anything, MatchedBy(func(input *ec2.DescribeNetworkInterfacesInput) bool {
+			return len(input.Filters) == 2 && input.Filters[0].Values[0] == vpcID &&
+				*input.Filters[1].Name == "description" && input.Filters[1].Values[0] == "fargate-*"
+		})).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: enis}, nil).Once()
+	}
+
+	It("returns once the Fargate network interfaces are deleted", func() {
+		mockFargateENIs("eni-1", "eni-2")
+		mockFargateENIs("eni-2")
+		mockFargateENIs()
+
+		Expect(WaitForFargateNetworkInterfacesDeletion(context.Background(), p.EC2(), vpcID, retryPolicy)).To(Succeed())
+		p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DescribeNetworkInterfaces", 3)
+	})
+
+	It("times out if the Fargate network interfaces are not deleted", func() {
+		mockFargateENIs("eni-1")
+		mockFargateENIs("eni-1")
+		mockFargateENIs("eni-1")
+
+		err := WaitForFargateNetworkInterfacesDeletion(context.Background(), p.EC2(), vpcID, retryPolicy)
+		Expect(err).To(MatchError(`timed out waiting for Fargate network interfaces in "vpc-fargate" to be deleted: eni-1`))
+	})
+
+	It("returns an error if the network interfaces cannot be listed", func() {
+		p.MockEC2().On("DescribeNetworkInterfaces", Anything, Anything).Return(nil, errors.New("access denied"))
+
+		err := WaitForFargateNetworkInterfacesDeletion(context.Background(), p.EC2(), vpcID, retryPolicy)
+		Expect(err).To(MatchError(ContainSubstring("access denied")))
+	})
+})
+
+var _ = Describe("CleanupOrphanedNetworkResources", func() {
+	const (
+		clusterName = "my-cluster"
+		vpcID       = "vpc-orphaned"
+	)
+
+	var (
+		p            *mockprovider.MockProvider
+		output       *bytes.Buffer
+		loggerWriter io.Writer
+	)
+
+	hasClusterTagFilter := func(filters []ec2types.Filter) bool {
+		return len(filters) >= 2 && filters[0].Values[0] == vpcID &&
+			*filters[1].Name == "tag-key" && filters[1].Values[0] == "kubernetes.io/cluster/"+clusterName
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		output = &bytes.Buffer{}
+		loggerWriter = logger.Writer
+		logger.Writer = output
+
+		p.MockEC2().On("DescribeNetworkInterfaces", Anything, MatchedBy(func(input *ec2.DescribeNetworkInterfacesInput) bool {
+			return hasClusterTagFilter(input.Filters) && len(input.Filters) == 3 &&
+				*input.Filters[2].Name == "status" && input.Filters[2].Values[0] == "available"
+		})).Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []ec2types.NetworkInterface{
+				{NetworkInterfaceId: aws.String("eni-1")},
+				{NetworkInterfaceId: aws.String("eni-2")},
+			},
+		}, nil)
+		p.MockEC2().On("DescribeSecurityGroups", Anything, MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return hasClusterTagFilter(input.Filters)
+		})).Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []ec2types.SecurityGroup{
+				{
+					GroupId:   aws.String("sg-orphaned"),
+					GroupName: aws.String("k8s-elb-orphaned"),
+				},
+				{
+					GroupId:   aws.String("sg-stack"),
+					GroupName: aws.String("eksctl-my-cluster-cluster-ClusterSharedNodeSecurityGroup"),
+					Tags: []ec2types.Tag{
+						{
+							Key:   aws.String("aws:cloudformation:stack-name"),
+							Value: aws.String("eksctl-my-cluster-cluster"),
+						},
+					},
+				},
+				{
+					GroupId:   aws.String("sg-eks"),
+					GroupName: aws.String("eks-cluster-sg-my-cluster-123456789"),
+					Tags: []ec2types.Tag{
+						{
+							Key:   aws.String("aws:eks:cluster-name"),
+							Value: aws.String(clusterName),
+						},
+					},
+				},
+			},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		logger.Writer = loggerWriter
+	})
+
+	It("deletes the detached network interfaces and the security groups that are neither part of a stack nor created by EKS", func() {
+		p.MockEC2().On("DeleteNetworkInterface", Anything, Anything).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+		p.MockEC2().On("DeleteSecurityGroup", Anything, Anything).Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+
+		Expect(CleanupOrphanedNetworkResources(context.Background(), p.EC2(), clusterName, vpcID)).To(Succeed())
+
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteNetworkInterface", Anything, &ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-1")})
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteNetworkInterface", Anything, &ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-2")})
+		p.MockEC2().AssertNumberOfCalls(GinkgoT(), "DeleteSecurityGroup", 1)
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteSecurityGroup", Anything, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-orphaned")})
+		Expect(output.String()).To(ContainSubstring(`deleted network interface "eni-1"`))
+		Expect(output.String()).To(ContainSubstring(`deleted network interface "eni-2"`))
+		Expect(output.String()).To(ContainSubstring(`deleted security group "k8s-elb-orphaned" (sg-orphaned)`))
+	})
+
+	It("carries on when a resource cannot be deleted", func() {
+		p.MockEC2().On("DeleteNetworkInterface", Anything, &ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-1")}).Return(nil, errors.New("in use"))
+		p.MockEC2().On("DeleteNetworkInterface", Anything, Anything).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+		p.MockEC2().On("DeleteSecurityGroup", Anything, Anything).Return(nil, errors.New("dependency violation"))
+
+		Expect(CleanupOrphanedNetworkResources(context.Background(), p.EC2(), clusterName, vpcID)).To(Succeed())
+
+		Expect(output.String()).To(ContainSubstring(`unable to delete network interface "eni-1": in use`))
+		Expect(output.String()).To(ContainSubstring(`deleted network interface "eni-2"`))
+		Expect(output.String()).To(ContainSubstring(`unable to delete security group "k8s-elb-orphaned" (sg-orphaned): dependency violation`))
+	})
+})
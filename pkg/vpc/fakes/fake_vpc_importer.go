@@ -39,15 +39,17 @@ type FakeImporter struct {
 	securityGroupsReturnsOnCall map[int]struct {
 		result1 types.Slice
 	}
-	SharedNodeSecurityGroupStub        func() *types.Value
+	SharedNodeSecurityGroupStub        func() (*types.Value, error)
 	sharedNodeSecurityGroupMutex       sync.RWMutex
 	sharedNodeSecurityGroupArgsForCall []struct {
 	}
 	sharedNodeSecurityGroupReturns struct {
 		result1 *types.Value
+		result2 error
 	}
 	sharedNodeSecurityGroupReturnsOnCall map[int]struct {
 		result1 *types.Value
+		result2 error
 	}
 	SubnetsPrivateStub        func() *types.Value
 	subnetsPrivateMutex       sync.RWMutex
@@ -242,7 +244,7 @@ func (fake *FakeImporter) SecurityGroupsReturnsOnCall(i int, result1 types.Slice
 	}{result1}
 }
 
-func (fake *FakeImporter) SharedNodeSecurityGroup() *types.Value {
+func (fake *FakeImporter) SharedNodeSecurityGroup() (*types.Value, error) {
 	fake.sharedNodeSecurityGroupMutex.Lock()
 	ret, specificReturn := fake.sharedNodeSecurityGroupReturnsOnCall[len(fake.sharedNodeSecurityGroupArgsForCall)]
 	fake.sharedNodeSecurityGroupArgsForCall = append(fake.sharedNodeSecurityGroupArgsForCall, struct {
@@ -255,9 +257,9 @@ func (fake *FakeImporter) SharedNodeSecurityGroup() *types.Value {
 		return stub()
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeImporter) SharedNodeSecurityGroupCallCount() int {
@@ -266,33 +268,36 @@ func (fake *FakeImporter) SharedNodeSecurityGroupCallCount() int {
 	return len(fake.sharedNodeSecurityGroupArgsForCall)
 }
 
-func (fake *FakeImporter) SharedNodeSecurityGroupCalls(stub func() *types.Value) {
+func (fake *FakeImporter) SharedNodeSecurityGroupCalls(stub func() (*types.Value, error)) {
 	fake.sharedNodeSecurityGroupMutex.Lock()
 	defer fake.sharedNodeSecurityGroupMutex.Unlock()
 	fake.SharedNodeSecurityGroupStub = stub
 }
 
-func (fake *FakeImporter) SharedNodeSecurityGroupReturns(result1 *types.Value) {
+func (fake *FakeImporter) SharedNodeSecurityGroupReturns(result1 *types.Value, result2 error) {
 	fake.sharedNodeSecurityGroupMutex.Lock()
 	defer fake.sharedNodeSecurityGroupMutex.Unlock()
 	fake.SharedNodeSecurityGroupStub = nil
 	fake.sharedNodeSecurityGroupReturns = struct {
 		result1 *types.Value
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeImporter) SharedNodeSecurityGroupReturnsOnCall(i int, result1 *types.Value) {
+func (fake *FakeImporter) SharedNodeSecurityGroupReturnsOnCall(i int, result1 *types.Value, result2 error) {
 	fake.sharedNodeSecurityGroupMutex.Lock()
 	defer fake.sharedNodeSecurityGroupMutex.Unlock()
 	fake.SharedNodeSecurityGroupStub = nil
 	if fake.sharedNodeSecurityGroupReturnsOnCall == nil {
 		fake.sharedNodeSecurityGroupReturnsOnCall = make(map[int]struct {
 			result1 *types.Value
+			result2 error
 		})
 	}
 	fake.sharedNodeSecurityGroupReturnsOnCall[i] = struct {
 		result1 *types.Value
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeImporter) SubnetsPrivate() *types.Value {
@@ -15,21 +15,27 @@ type Importer interface {
 	VPC() *gfnt.Value
 	ClusterSecurityGroup() *gfnt.Value
 	ControlPlaneSecurityGroup() *gfnt.Value
-	SharedNodeSecurityGroup() *gfnt.Value
+	SharedNodeSecurityGroup() (*gfnt.Value, error)
 	SecurityGroups() gfnt.Slice
 	SubnetsPublic() *gfnt.Value
 	SubnetsPrivate() *gfnt.Value
 }
 
+// ErrSharedNodeSecurityGroupDisabled is returned when a nodegroup requires the shared node security group of a
+// cluster that was created without it
+var ErrSharedNodeSecurityGroupDisabled = fmt.Errorf("the shared node security group is not created for the cluster as vpc.sharedNodeSecurityGroup is %q; disable securityGroups.withShared for the nodegroup", api.SharedNodeSecurityGroupDisabled)
+
 // StackConfigImporter returns VPC info based on the Cluster Stack
 type StackConfigImporter struct {
 	clusterStackName string
+	vpc              *api.ClusterVPC
 }
 
 // NewStackConfigImporter creates a new StackConfigImporter instance
-func NewStackConfigImporter(clusterStackName string) *StackConfigImporter {
+func NewStackConfigImporter(clusterStackName string, vpc *api.ClusterVPC) *StackConfigImporter {
 	return &StackConfigImporter{
 		clusterStackName: clusterStackName,
+		vpc:              vpc,
 	}
 }
 
@@ -53,8 +59,11 @@ func (si *StackConfigImporter) ControlPlaneSecurityGroup() *gfnt.Value {
 
 // SharedNodeSecurityGroup returns a gfnt value based on the cluster stack name
 // and the shared node security group from the cluster stack output
-func (si *StackConfigImporter) SharedNodeSecurityGroup() *gfnt.Value {
-	return makeImportValue(si.clusterStackName, outputs.ClusterSharedNodeSecurityGroup)
+func (si *StackConfigImporter) SharedNodeSecurityGroup() (*gfnt.Value, error) {
+	if si.vpc != nil && si.vpc.SharedNodeSecurityGroup == api.SharedNodeSecurityGroupDisabled {
+		return nil, ErrSharedNodeSecurityGroupDisabled
+	}
+	return makeImportValue(si.clusterStackName, outputs.ClusterSharedNodeSecurityGroup), nil
 }
 
 // SecurityGroups returns a gfnt slice based on the cluster stack name
@@ -112,11 +121,15 @@ func (si *SpecConfigImporter) ControlPlaneSecurityGroup() *gfnt.Value {
 // SharedNodeSecurityGroup returns the gfnt value of the cluster config VPC
 // sharedNodeSecurityGroup if it is set. If not, it returns the default
 // cluster security group
-func (si *SpecConfigImporter) SharedNodeSecurityGroup() *gfnt.Value {
-	if si.vpc.SharedNodeSecurityGroup != "" {
-		return gfnt.NewString(si.vpc.SharedNodeSecurityGroup)
+func (si *SpecConfigImporter) SharedNodeSecurityGroup() (*gfnt.Value, error) {
+	switch si.vpc.SharedNodeSecurityGroup {
+	case api.SharedNodeSecurityGroupDisabled:
+		return nil, ErrSharedNodeSecurityGroupDisabled
+	case "":
+		return si.ClusterSecurityGroup(), nil
+	default:
+		return gfnt.NewString(si.vpc.SharedNodeSecurityGroup), nil
 	}
-	return si.ClusterSecurityGroup()
 }
 
 // SecurityGroups returns a gfnt slice of the ClusterSecurityGroup
@@ -86,6 +86,17 @@ var _ = Describe("SpecConfigImporter", func() {
 				Expect(noSharedSgImporter.SharedNodeSecurityGroup()).To(Equal(gfnt.NewString(clusterSecurityGroup)))
 			})
 		})
+
+		Context("when the shared node security group is disabled", func() {
+			disabledSharedSgImporter := NewSpecConfigImporter(clusterSecurityGroup, &api.ClusterVPC{
+				SharedNodeSecurityGroup: api.SharedNodeSecurityGroupDisabled,
+			})
+
+			It("returns an error", func() {
+				_, err := disabledSharedSgImporter.SharedNodeSecurityGroup()
+				Expect(err).To(MatchError(ErrSharedNodeSecurityGroupDisabled))
+			})
+		})
 	})
 
 	Describe("SecurityGroups", func() {
@@ -111,3 +122,20 @@ var _ = Describe("SpecConfigImporter", func() {
 		})
 	})
 })
+
+var _ = Describe("StackConfigImporter", func() {
+	Describe("SharedNodeSecurityGroup", func() {
+		It("imports the shared node security group from the cluster stack", func() {
+			importer := NewStackConfigImporter("eksctl-test-cluster", &api.ClusterVPC{})
+			Expect(importer.SharedNodeSecurityGroup()).To(Equal(gfnt.MakeFnImportValueString("eksctl-test-cluster::SharedNodeSecurityGroup")))
+		})
+
+		It("returns an error when the shared node security group is disabled", func() {
+			importer := NewStackConfigImporter("eksctl-test-cluster", &api.ClusterVPC{
+				SharedNodeSecurityGroup: api.SharedNodeSecurityGroupDisabled,
+			})
+			_, err := importer.SharedNodeSecurityGroup()
+			Expect(err).To(MatchError(ErrSharedNodeSecurityGroupDisabled))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package vpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/awsapi"
+)
+
+// stackNameTagKey is the tag that CloudFormation adds to the resources of a stack
+const stackNameTagKey = "aws:cloudformation:stack-name"
+
+// eksClusterNameTagKey is the tag that EKS adds to the resources it creates for a cluster
+const eksClusterNameTagKey = "aws:eks:cluster-name"
+
+// SubnetTagsUpdate holds the tags to create on a subnet, which it is missing or has a different value for
+type SubnetTagsUpdate struct {
+	SubnetID string
+	Tags     map[string]string
+}
+
+// PlanSubnetTagsUpdates returns the updates that bring the tags of the subnets of spec created by the cluster stack
+// stackName in line with the subnet tags of spec.VPC. Subnets that were not created by the stack, e.g. those of an
+// existing VPC, are left out, and tags that are not set in spec.VPC are never removed
+func PlanSubnetTagsUpdates(ctx context.Context, ec2API awsapi.EC2, stackName string, spec *api.ClusterConfig) ([]SubnetTagsUpdate, error) {
+	if spec.VPC == nil || spec.VPC.Subnets == nil {
+		return nil, nil
+	}
+	subnetIDs := append(spec.VPC.Subnets.Public.WithIDs(), spec.VPC.Subnets.Private.WithIDs()...)
+	if len(subnetIDs) == 0 {
+		return nil, nil
+	}
+
+	subnets, err := describeSubnets(ctx, ec2API, "", subnetIDs, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing subnets %v", subnetIDs)
+	}
+	return subnetTagsUpdates(subnets, stackName, spec.VPC), nil
+}
+
+func subnetTagsUpdates(subnets []ec2types.Subnet, stackName string, clusterVPC *api.ClusterVPC) []SubnetTagsUpdate {
+	publicSubnetIDs := clusterVPC.Subnets.Public.WithIDs()
+
+	var updates []SubnetTagsUpdate
+	for _, subnet := range subnets {
+		currentTags := make(map[string]string, len(subnet.Tags))
+		for _, tag := range subnet.Tags {
+			currentTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if currentTags[stackNameTagKey] != stackName {
+			logger.Debug("skipping subnet %q, which was not created by stack %q", aws.StringValue(subnet.SubnetId), stackName)
+			continue
+		}
+
+		topology := api.SubnetTopologyPrivate
+		for _, id := range publicSubnetIDs {
+			if id == aws.StringValue(subnet.SubnetId) {
+				topology = api.SubnetTopologyPublic
+			}
+		}
+
+		tags := map[string]string{}
+		for key, value := range clusterVPC.TagsOfSubnets(topology) {
+			if currentValue, ok := currentTags[key]; !ok || currentValue != value {
+				tags[key] = value
+			}
+		}
+		if len(tags) > 0 {
+			updates = append(updates, SubnetTagsUpdate{
+				SubnetID: aws.StringValue(subnet.SubnetId),
+				Tags:     tags,
+			})
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].SubnetID < updates[j].SubnetID
+	})
+	return updates
+}
+
+// UpdateSubnetTags creates the tags of updates on their subnets
+func UpdateSubnetTags(ctx context.Context, ec2API awsapi.EC2, updates []SubnetTagsUpdate) error {
+	for _, update := range updates {
+		keys := make([]string, 0, len(update.Tags))
+		for key := range update.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		tags := make([]ec2types.Tag, 0, len(keys))
+		for _, key := range keys {
+			tags = append(tags, ec2types.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(update.Tags[key]),
+			})
+		}
+
+		logger.Debug("creating tags %v on subnet %q", keys, update.SubnetID)
+		if _, err := ec2API.CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{update.SubnetID},
+			Tags:      tags,
+		}); err != nil {
+			return errors.Wrapf(err, "creating tags on subnet %q", update.SubnetID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package vpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Subnet tags", func() {
+	const stackName = "eksctl-cluster-1-cluster"
+
+	var (
+		p    *mockprovider.MockProvider
+		spec *api.ClusterConfig
+	)
+
+	tag := func(key, value string) ec2types.Tag {
+		return ec2types.Tag{Key: aws.String(key), Value: aws.String(value)}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		spec = api.NewClusterConfig()
+		spec.VPC.Subnets = &api.ClusterSubnets{
+			Public: api.AZSubnetMappingFromMap(map[string]api.AZSubnetSpec{
+				"us-west-2a": {ID: "subnet-public-a"},
+				"us-west-2b": {ID: "subnet-existing"},
+			}),
+			Private: api.AZSubnetMappingFromMap(map[string]api.AZSubnetSpec{
+				"us-west-2a": {ID: "subnet-private-a"},
+			}),
+		}
+		spec.VPC.SubnetTags = map[string]string{
+			"karpenter.sh/discovery": "cluster-1",
+			"team":                   "platform",
+		}
+		spec.VPC.PublicSubnetTags = map[string]string{
+			"team": "web",
+		}
+
+		p.MockEC2().On("DescribeSubnets", Anything, MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return len(input.SubnetIds) == 3
+		})).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []ec2types.Subnet{
+				{
+					SubnetId: aws.String("subnet-public-a"),
+					Tags:     []ec2types.Tag{tag(stackNameTagKey, stackName), tag("team", "web")},
+				},
+				{
+					SubnetId: aws.String("subnet-existing"),
+					Tags:     []ec2types.Tag{tag("team", "other")},
+				},
+				{
+					SubnetId: aws.String("subnet-private-a"),
+					Tags:     []ec2types.Tag{tag(stackNameTagKey, stackName), tag("karpenter.sh/discovery", "cluster-2")},
+				},
+			},
+		}, nil)
+	})
+
+	It("plans to create the missing and changed tags on the subnets created by the cluster stack", func() {
+		updates, err := PlanSubnetTagsUpdates(context.Background(), p.EC2(), stackName, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(Equal([]SubnetTagsUpdate{
+			{
+				SubnetID: "subnet-private-a",
+				Tags: map[string]string{
+					"karpenter.sh/discovery": "cluster-1",
+					"team":                   "platform",
+				},
+			},
+			{
+				SubnetID: "subnet-public-a",
+				Tags: map[string]string{
+					"karpenter.sh/discovery": "cluster-1",
+				},
+			},
+		}))
+	})
+
+	It("plans no updates when the subnets are up to date", func() {
+		spec.VPC.SubnetTags = nil
+		updates, err := PlanSubnetTagsUpdates(context.Background(), p.EC2(), stackName, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(BeEmpty())
+	})
+
+	It("creates the tags of the updates", func() {
+		p.MockEC2().On("CreateTags", Anything, Anything).Return(&ec2.CreateTagsOutput{}, nil)
+		Expect(UpdateSubnetTags(context.Background(), p.EC2(), []SubnetTagsUpdate{
+			{
+				SubnetID: "subnet-private-a",
+				Tags: map[string]string{
+					"team":                   "platform",
+					"karpenter.sh/discovery": "cluster-1",
+				},
+			},
+		})).To(Succeed())
+		p.MockEC2().AssertCalled(GinkgoT(), "CreateTags", Anything, &ec2.CreateTagsInput{
+			Resources: []string{"subnet-private-a"},
+			Tags:      []ec2types.Tag{tag("karpenter.sh/discovery", "cluster-1"), tag("team", "platform")},
+		})
+	})
+
+	It("returns an error when the tags cannot be created", func() {
+		p.MockEC2().On("CreateTags", Anything, Anything).Return(nil, errors.New("denied"))
+		err := UpdateSubnetTags(context.Background(), p.EC2(), []SubnetTagsUpdate{
+			{SubnetID: "subnet-public-a", Tags: map[string]string{"team": "web"}},
+		})
+		Expect(err).To(MatchError(`creating tags on subnet "subnet-public-a": denied`))
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -381,9 +382,9 @@ func ValidateLegacySubnetsForNodeGroups(ctx context.Context, spec *api.ClusterCo
 	subnetsToValidate := sets.NewString()
 
 	selectSubnets := func(ng *api.NodeGroupBase) error {
-		if len(ng.AvailabilityZones) > 0 || len(ng.Subnets) > 0 {
+		if len(ng.AvailabilityZones) > 0 || len(ng.AvailabilityZoneIDs) > 0 || len(ng.Subnets) > 0 {
 			// Check only the public subnets that this ng has
-			subnetIDs, err := SelectNodeGroupSubnets(ctx, ng.AvailabilityZones, ng.Subnets, spec.VPC.Subnets.Public, provider.EC2(), spec.VPC.ID)
+			subnetIDs, err := SelectNodeGroupSubnets(ctx, ng.AvailabilityZones, ng.AvailabilityZoneIDs, ng.Subnets, spec.VPC.Subnets.Public, provider.EC2(), spec.VPC.ID)
 			if err != nil {
 				return errors.Wrap(err, "couldn't find public subnets")
 			}
@@ -513,7 +514,7 @@ func UseEndpointAccessFromCluster(provider api.ClusterProvider, spec *api.Cluste
 // cleanupSubnets clean up subnet entries having invalid AZ
 func cleanupSubnets(spec *api.ClusterConfig) {
 	availabilityZones := make(map[string]struct{})
-	for _, az := range spec.AvailabilityZones {
+	for _, az := range spec.AllZones() {
 		availabilityZones[az] = struct{}{}
 	}
 
@@ -559,21 +560,30 @@ func getSubnetByID(ctx context.Context, ec2API awsapi.EC2, id string) (ec2types.
 	return output.Subnets[0], nil
 }
 
-func SelectNodeGroupSubnets(ctx context.Context, nodegroupAZs, nodegroupSubnets []string, subnets api.AZSubnetMapping, ec2API awsapi.EC2, vpcID string) ([]string, error) {
+// SelectNodeGroupSubnets returns the IDs of the subnets of a nodegroup, which are those of subnets in nodegroupAZs or
+// in the zones with nodegroupAZIDs, along with nodegroupSubnets
+func SelectNodeGroupSubnets(ctx context.Context, nodegroupAZs, nodegroupAZIDs, nodegroupSubnets []string, subnets api.AZSubnetMapping, ec2API awsapi.EC2, vpcID string) ([]string, error) {
 	// We have validated that either azs are provided or subnets are provided
-	numNodeGroupsAZs := len(nodegroupAZs)
+	numNodeGroupsAZs := len(nodegroupAZs) + len(nodegroupAZIDs)
 	numNodeGroupsSubnets := len(nodegroupSubnets)
 	if numNodeGroupsAZs == 0 && numNodeGroupsSubnets == 0 {
 		return nil, nil
 	}
 
 	makeErrorDesc := func() string {
-		return fmt.Sprintf("(allSubnets=%#v AZs=%#v subnets=%#v)", subnets, nodegroupAZs, nodegroupSubnets)
+		return fmt.Sprintf("(allSubnets=%#v AZs=%#v AZIDs=%#v subnets=%#v)", subnets, nodegroupAZs, nodegroupAZIDs, nodegroupSubnets)
 	}
 	if len(subnets) < numNodeGroupsAZs || len(subnets) < numNodeGroupsSubnets {
 		return nil, fmt.Errorf("mapping doesn't have enough subnets: %s", makeErrorDesc())
 	}
 	subnetIDs := []string{}
+	if len(nodegroupAZIDs) > 0 {
+		azIDSubnetIDs, err := selectSubnetsByZoneID(ctx, ec2API, nodegroupAZIDs, subnets)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, makeErrorDesc())
+		}
+		subnetIDs = append(subnetIDs, azIDSubnetIDs...)
+	}
 	// We validate previously that either AZs or subnets is set
 	for _, az := range nodegroupAZs {
 		azSubnetIDs := []string{}
@@ -613,3 +623,31 @@ func SelectNodeGroupSubnets(ctx context.Context, nodegroupAZs, nodegroupSubnets
 	}
 	return subnetIDs, nil
 }
+
+// selectSubnetsByZoneID returns the IDs of the subnets of the mapping that are in the zones with zoneIDs. The mapping
+// only has the names of the zones of the subnets, so their zone IDs are fetched from EC2
+func selectSubnetsByZoneID(ctx context.Context, ec2API awsapi.EC2, zoneIDs []string, subnets api.AZSubnetMapping) ([]string, error) {
+	subnetIDs := subnets.WithIDs()
+	sort.Strings(subnetIDs)
+	output, err := ec2API.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: subnetIDs,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing subnets")
+	}
+
+	var selected []string
+	for _, zoneID := range zoneIDs {
+		var zoneSubnetIDs []string
+		for _, s := range output.Subnets {
+			if aws.ToString(s.AvailabilityZoneId) == zoneID {
+				zoneSubnetIDs = append(zoneSubnetIDs, aws.ToString(s.SubnetId))
+			}
+		}
+		if len(zoneSubnetIDs) == 0 {
+			return nil, fmt.Errorf("mapping doesn't have subnet with zone ID %s", zoneID)
+		}
+		selected = append(selected, zoneSubnetIDs...)
+	}
+	return selected, nil
+}
@@ -221,6 +221,31 @@ var _ = Describe("VPC", func() {
 		}),
 	)
 
+	DescribeTable("Set subnets without overlapping CIDRs",
+		func(availabilityZones []string, expectedPrefix int) {
+			vpc := api.NewClusterVPC(false)
+			Expect(SetSubnets(vpc, availabilityZones)).To(Succeed())
+
+			var cidrs []*net.IPNet
+			for _, zone := range availabilityZones {
+				for _, subnets := range []api.AZSubnetMapping{vpc.Subnets.Public, vpc.Subnets.Private} {
+					subnet, ok := subnets[zone]
+					Expect(ok).To(BeTrue())
+					prefix, _ := subnet.CIDR.Mask.Size()
+					Expect(prefix).To(Equal(expectedPrefix))
+					for _, cidr := range cidrs {
+						Expect(cidr.Contains(subnet.CIDR.IP) || subnet.CIDR.Contains(cidr.IP)).To(BeFalse(), "%s overlaps %s", subnet.CIDR, cidr)
+					}
+					cidrs = append(cidrs, &subnet.CIDR.IPNet)
+				}
+			}
+		},
+		Entry("2 AZs", []string{"us-west-2a", "us-west-2b"}, 19),
+		Entry("4 AZs", []string{"us-west-2a", "us-west-2b", "us-west-2c", "us-west-2d"}, 19),
+		Entry("5 AZs", []string{"us-west-2a", "us-west-2b", "us-west-2c", "us-west-2d", "us-west-2e"}, 20),
+		Entry("8 AZs", []string{"1", "2", "3", "4", "5", "6", "7", "8"}, 20),
+	)
+
 	DescribeTable("Use from Cluster",
 		func(clusterCase useFromClusterCase) {
 			p := mockprovider.NewMockProvider()
@@ -932,7 +957,7 @@ var _ = Describe("VPC", func() {
 
 	DescribeTable("select subnets",
 		func(e selectSubnetsCase) {
-			ids, err := SelectNodeGroupSubnets(context.Background(), e.nodegroupAZs, e.nodegroupSubnets, e.subnets, nil, "")
+			ids, err := SelectNodeGroupSubnets(context.Background(), e.nodegroupAZs, nil, e.nodegroupSubnets, e.subnets, nil, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(ids).To(ConsistOf(e.expectIDs))
 		},
@@ -1008,7 +1033,7 @@ var _ = Describe("VPC", func() {
 						},
 					},
 				}, nil)
-				ids, err := SelectNodeGroupSubnets(context.Background(), []string{az}, []string{subnetID}, api.AZSubnetMappingFromMap(azMap), mockEC2, vpcID)
+				ids, err := SelectNodeGroupSubnets(context.Background(), []string{az}, nil, []string{subnetID}, api.AZSubnetMappingFromMap(azMap), mockEC2, vpcID)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ids).To(ConsistOf("id-1", "id-2", subnetID))
 			})
@@ -1019,7 +1044,7 @@ var _ = Describe("VPC", func() {
 				mockEC2.On("DescribeSubnets", Anything, &ec2.DescribeSubnetsInput{
 					SubnetIds: []string{subnetID},
 				}).Return(nil, errors.New("nope"))
-				_, err := SelectNodeGroupSubnets(context.Background(), []string{az}, []string{subnetID}, api.AZSubnetMappingFromMap(azMap), mockEC2, vpcID)
+				_, err := SelectNodeGroupSubnets(context.Background(), []string{az}, nil, []string{subnetID}, api.AZSubnetMappingFromMap(azMap), mockEC2, vpcID)
 				Expect(err).To(MatchError(ContainSubstring("nope")))
 			})
 		})
@@ -1036,9 +1061,54 @@ var _ = Describe("VPC", func() {
 						},
 					},
 				}, nil)
-				_, err := SelectNodeGroupSubnets(context.Background(), []string{az}, []string{subnetID}, api.AZSubnetMappingFromMap(azMap), mockEC2, vpcID)
+				_, err := SelectNodeGroupSubnets(context.Background(), []string{az}, nil, []string{subnetID}, api.AZSubnetMappingFromMap(azMap), mockEC2, vpcID)
 				Expect(err).To(MatchError(ContainSubstring("subnet with id \"user-defined-id\" is not in the attached vpc with id \"vpc-id\"")))
 			})
 		})
 	})
+
+	Context("the user provides zone IDs", func() {
+		var (
+			mockEC2 *mocksv2.EC2
+			azMap   map[string]api.AZSubnetSpec
+		)
+		BeforeEach(func() {
+			mockEC2 = &mocksv2.EC2{}
+			azMap = map[string]api.AZSubnetSpec{
+				"us-east-1a": {
+					ID: "id-1",
+					AZ: "us-east-1a",
+				},
+				"us-east-1b": {
+					ID: "id-2",
+					AZ: "us-east-1b",
+				},
+			}
+			mockEC2.On("DescribeSubnets", Anything, &ec2.DescribeSubnetsInput{
+				SubnetIds: []string{"id-1", "id-2"},
+			}).Return(&ec2.DescribeSubnetsOutput{
+				Subnets: []ec2types.Subnet{
+					{
+						SubnetId:           aws.String("id-1"),
+						AvailabilityZoneId: aws.String("use1-az4"),
+					},
+					{
+						SubnetId:           aws.String("id-2"),
+						AvailabilityZoneId: aws.String("use1-az6"),
+					},
+				},
+			}, nil)
+		})
+
+		It("selects the subnets in the zones with these IDs", func() {
+			ids, err := SelectNodeGroupSubnets(context.Background(), nil, []string{"use1-az6"}, nil, api.AZSubnetMappingFromMap(azMap), mockEC2, "vpc-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(ConsistOf("id-2"))
+		})
+
+		It("returns an error when no subnet is in a zone", func() {
+			_, err := SelectNodeGroupSubnets(context.Background(), nil, []string{"use1-az4", "use1-az1"}, nil, api.AZSubnetMappingFromMap(azMap), mockEC2, "vpc-id")
+			Expect(err).To(MatchError(ContainSubstring("mapping doesn't have subnet with zone ID use1-az1")))
+		})
+	})
 })